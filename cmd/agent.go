@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var agentLabel string
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Control agents in a running board",
+	Long: `Commands for spawning, stopping and inspecting agents.
+
+Tickets can be referenced by ID, ID prefix, branch name, or title.
+Spawn and stop require a running openkanban instance; status falls back
+to the saved ticket state when the board is not running.`,
+}
+
+var agentSpawnCmd = &cobra.Command{
+	Use:   "spawn [ticket...]",
+	Short: "Spawn agents for tickets",
+	Long: `Spawn agents for the given tickets in the running board.
+
+Backlog tickets are moved to In Progress first. Use --label to spawn
+agents for every ticket carrying a label, for example from cron:
+
+  openkanban agent spawn --label auto`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireTicketArgs(args); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		return app.AgentSpawn(args, agentLabel)
+	},
+}
+
+var agentStopCmd = &cobra.Command{
+	Use:   "stop [ticket...]",
+	Short: "Stop agents for tickets",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireTicketArgs(args); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		return app.AgentStop(args, agentLabel)
+	},
+}
+
+var agentStatusCmd = &cobra.Command{
+	Use:   "status [ticket...]",
+	Short: "Show agent status",
+	Long:  "Show agent status for the given tickets, or for all in-progress tickets.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.AgentStatus(cfg, args, agentLabel)
+	},
+}
+
+func requireTicketArgs(args []string) error {
+	if len(args) == 0 && agentLabel == "" {
+		return errors.New("specify at least one ticket or --label")
+	}
+	return nil
+}
+
+func init() {
+	agentCmd.PersistentFlags().StringVarP(&agentLabel, "label", "l", "", "select all tickets with this label")
+
+	agentCmd.AddCommand(agentSpawnCmd)
+	agentCmd.AddCommand(agentStopCmd)
+	agentCmd.AddCommand(agentStatusCmd)
+
+	rootCmd.AddCommand(agentCmd)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var (
+	changelogFrom     string
+	changelogTo       string
+	changelogTicket   string
+	changelogLabel    string
+	changelogTitle    string
+	changelogByTicket bool
+	changelogOutput   string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate release notes from completed tickets",
+	Long: `Write a Markdown changelog from the commits on completed ticket branches,
+grouped by conventional-commit type (feat, fix, perf, ...).
+
+Covers Done and archived tickets, optionally only those with a label (such
+as a milestone) or completed in a date range. Tickets whose branch is gone
+are listed by title.
+
+  openkanban changelog --label v1.2 --title "v1.2.0" -o notes.md
+  openkanban changelog --ticket fix-login`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := app.ChangelogOptions{
+			Project:  projectPath,
+			Ticket:   changelogTicket,
+			Label:    changelogLabel,
+			Title:    changelogTitle,
+			ByTicket: changelogByTicket,
+			Output:   changelogOutput,
+		}
+		if changelogFrom != "" {
+			t, err := time.ParseInLocation(dateLayout, changelogFrom, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --from date %q (want YYYY-MM-DD)", changelogFrom)
+			}
+			opts.From = t
+		}
+		if changelogTo != "" {
+			t, err := time.ParseInLocation(dateLayout, changelogTo, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --to date %q (want YYYY-MM-DD)", changelogTo)
+			}
+			opts.To = t.AddDate(0, 0, 1)
+		}
+		if !opts.From.IsZero() && !opts.To.IsZero() && !opts.From.Before(opts.To) {
+			return errors.New("--from must be before --to")
+		}
+
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.Changelog(cfg, opts)
+	},
+}
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "completed on or after this date (YYYY-MM-DD)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "completed on or before this date (YYYY-MM-DD)")
+	changelogCmd.Flags().StringVar(&changelogTicket, "ticket", "", "a single ticket (ID, branch, or title), in any status")
+	changelogCmd.Flags().StringVar(&changelogLabel, "label", "", "only tickets with this label, e.g. a milestone")
+	changelogCmd.Flags().StringVar(&changelogTitle, "title", "", "heading of the changelog section (default: the label, or Unreleased)")
+	changelogCmd.Flags().BoolVar(&changelogByTicket, "by-ticket", false, "group changes under each ticket")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "write to a file instead of stdout")
+
+	rootCmd.AddCommand(changelogCmd)
+}
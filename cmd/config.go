@@ -1,17 +1,195 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
 	"github.com/techdufus/openkanban/internal/config"
 )
 
+var editConfig bool
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configuration management",
 	Long:  "Commands for managing OpenKanban configuration files.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !editConfig {
+			return cmd.Help()
+		}
+		return editConfigFile()
+	},
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the value at a dotted key, for example:
+
+  openkanban config get ui.theme
+  openkanban config get agents.claude.command`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a configuration value",
+	Long: `Set the value at a dotted key and reload the running board.
+
+  openkanban config set agent.default claude
+  openkanban config set cleanup.delete_branch true
+  openkanban config set agents.claude.args "--verbose,--debug"
+
+Values are parsed according to the setting's type. Lists accept
+comma-separated items or a JSON array.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+
+		if result := cfg.Validate(); result.HasErrors() {
+			fmt.Fprint(os.Stderr, result.FormatErrors())
+			return errors.New("config not saved")
+		}
+
+		if err := cfg.Save(path); err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+
+		value, _ := cfg.Get(args[0])
+		fmt.Printf("%s = %s\n", config.ResolveKey(args[0]), value)
+		reloadRunningBoard()
+		return nil
+	},
+}
+
+var listConfigCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all configuration values",
+	Long: `List every setting as a dotted key. Values overridden by environment
+variables are marked with the variable name.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		entries, err := cfg.List()
+		if err != nil {
+			return err
+		}
+		fromEnv := make(map[string]string)
+		for _, o := range cfg.EnvOverrides() {
+			fromEnv[o.Key] = o.Var
+		}
+		for _, kv := range entries {
+			if name, ok := fromEnv[kv.Key]; ok {
+				fmt.Printf("%s = %s  (from %s)\n", kv.Key, kv.Value, name)
+				continue
+			}
+			fmt.Printf("%s = %s\n", kv.Key, kv.Value)
+		}
+		return nil
+	},
+}
+
+func resolveConfigPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	path, err := config.ConfigPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config path: %w", err)
+	}
+	return path, nil
+}
+
+// editConfigFile opens the config in $EDITOR, validates the result and
+// reloads the running board.
+func editConfigFile() error {
+	path, err := resolveConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.DefaultConfig().Save(path); err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	c := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	_, result, err := config.LoadWithValidation(path)
+	if result != nil && result.HasErrors() {
+		fmt.Fprintf(os.Stderr, "Config errors in %s:\n\n%s", path, result.FormatErrors())
+		return errors.New("invalid configuration; running board not reloaded")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if result != nil && result.HasWarnings() {
+		fmt.Fprintf(os.Stderr, "Config warnings:\n%s\n", result.FormatWarnings())
+	}
+
+	reloadRunningBoard()
+	return nil
+}
+
+func reloadRunningBoard() {
+	reloaded, err := app.ReloadRunning()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: running board did not reload: %v\n", err)
+		return
+	}
+	if reloaded {
+		fmt.Println("Reloaded running board.")
+	}
 }
 
 var validateCmd = &cobra.Command{
@@ -112,10 +290,42 @@ var showPathCmd = &cobra.Command{
 	},
 }
 
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file to the current version",
+	Long: `Rewrite an older configuration file in the current format. The original
+is kept next to it as config.json.v<N>.bak. Older files are upgraded in
+memory whenever they are loaded; this writes the result back.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+
+		note, err := config.MigrateFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+		if note == "" {
+			fmt.Printf("Configuration is up to date: %s\n", path)
+			return nil
+		}
+		fmt.Printf("%s: %s\n", path, note)
+		return nil
+	},
+}
+
 func init() {
 	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(migrateCmd)
 	configCmd.AddCommand(generateCmd)
 	configCmd.AddCommand(showPathCmd)
+	configCmd.AddCommand(getCmd)
+	configCmd.AddCommand(setCmd)
+	configCmd.AddCommand(listConfigCmd)
+
+	configCmd.Flags().BoolVarP(&editConfig, "edit", "e", false, "open the config file in $EDITOR")
 
 	generateCmd.Flags().BoolVarP(&forceGenerate, "force", "f", false, "overwrite existing config file")
 
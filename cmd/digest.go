@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var (
+	digestPeriod    string
+	digestSend      bool
+	digestOutput    string
+	digestSkipEmpty bool
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarise recent board activity",
+	Long: `Write a Markdown digest of the last day or week: tickets completed, agent
+runs that failed, and in-progress tickets without activity for
+digest.stall_days days.
+
+With --send the digest is mailed through digest.smtp, authenticating with
+the password in OPENKANBAN_SMTP_PASSWORD. Run it from cron for a daily mail:
+
+  0 8 * * * openkanban digest --send --skip-empty`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.Digest(cfg, projectPath, app.DigestOptions{
+			Period:    digestPeriod,
+			Send:      digestSend,
+			Output:    digestOutput,
+			SkipEmpty: digestSkipEmpty,
+		})
+	},
+}
+
+func init() {
+	digestCmd.Flags().StringVar(&digestPeriod, "period", "", "daily or weekly (default digest.period)")
+	digestCmd.Flags().BoolVar(&digestSend, "send", false, "mail the digest through digest.smtp")
+	digestCmd.Flags().StringVarP(&digestOutput, "output", "o", "", "write the Markdown to a file")
+	digestCmd.Flags().BoolVar(&digestSkipEmpty, "skip-empty", false, "do nothing when there was no activity")
+
+	rootCmd.AddCommand(digestCmd)
+}
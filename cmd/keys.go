@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var keysExportOutput string
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Work with keybindings",
+}
+
+var keysExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a printable cheat sheet of the keybindings",
+	Long: `Write the keybindings in effect, with the preset and any overrides from
+the config, as a Markdown page: a table per group for the board, one for
+the agent view, and one for macros bound to a key. Markdown converters
+such as pandoc turn it into a PDF.
+
+  openkanban keys export -o keys.md
+  openkanban keys export | pandoc -o keys.pdf`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return app.KeysExport(cfg, keysExportOutput)
+	},
+}
+
+func init() {
+	keysExportCmd.Flags().StringVarP(&keysExportOutput, "output", "o", "", "write to a file instead of stdout")
+
+	keysCmd.AddCommand(keysExportCmd)
+	rootCmd.AddCommand(keysCmd)
+}
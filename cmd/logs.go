@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+)
+
+var (
+	logsLines  int
+	logsFollow bool
+	logsPath   bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the application log",
+	Long: `Print the end of openkanban's application log, logs/app.log in the
+config directory. Errors shown on the board, failed background work and
+warnings are written there, at the level set by logging.level.
+
+  openkanban logs -n 100
+  openkanban logs --follow | grep level=ERROR`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if logsPath {
+			path, err := app.LogPath()
+			if err != nil {
+				return err
+			}
+			fmt.Println(path)
+			return nil
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		return app.ShowLogs(ctx, os.Stdout, app.LogsOptions{Lines: logsLines, Follow: logsFollow})
+	},
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "number of lines to show; 0 shows the whole log")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new lines until interrupted")
+	logsCmd.Flags().BoolVar(&logsPath, "path", false, "print the log file's path and exit")
+
+	rootCmd.AddCommand(logsCmd)
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 
@@ -14,6 +16,13 @@ import (
 var (
 	cfgFile     string
 	projectPath string
+	ticketRef   string
+	readOnly    bool
+	pprofAddr   string
+	debugLog    bool
+
+	// appLog is the application log, open for the running command
+	appLog io.Closer
 )
 
 var rootCmd = &cobra.Command{
@@ -24,8 +33,20 @@ multiple AI coding agents across different tasks and git worktrees.
 
 Each ticket spawns an embedded terminal pane with its own git worktree
 for safe parallel development.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		startLogging(cmd)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if appLog != nil {
+			appLog.Close()
+		}
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
+		if readOnly {
+			// Set through the environment so a config reload keeps it
+			os.Setenv("OPENKANBAN_READ_ONLY", "true")
+		}
 		cfg, result, err := config.LoadWithValidation(cfgFile)
 		if err != nil {
 			if result != nil && result.HasErrors() {
@@ -46,17 +67,38 @@ for safe parallel development.`,
 			fmt.Fprintf(os.Stderr, "Config warnings:\n%s\n", result.FormatWarnings())
 		}
 
-		return app.Run(cfg, projectPath, Version)
+		target := app.LaunchTarget{Project: projectPath, Ticket: ticketRef}
+		return app.Run(cfg, cfgFile, target, app.DebugOptions{Pprof: pprofAddr, Log: debugLog}, Version)
 	},
 }
 
+// startLogging opens the application log for every command. A config that
+// fails to load is reported by the command itself, so logging falls back to
+// the defaults, and a log that can't be opened never stops the command.
+func startLogging(cmd *cobra.Command) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	appLog, err = app.StartLogging(cfg, debugLog)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: application log disabled: %v\n", err)
+		return
+	}
+	slog.Debug("command started", "command", cmd.CommandPath(), "version", Version)
+}
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/openkanban/config.json)")
-	rootCmd.PersistentFlags().StringVarP(&projectPath, "project", "p", "", "project or repository path")
+	rootCmd.PersistentFlags().StringVarP(&projectPath, "project", "p", "", "project name, ID, or repository path")
+	rootCmd.Flags().StringVarP(&ticketRef, "ticket", "t", "", "open with this ticket selected (ID, branch, or title)")
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "browse the board without changing tickets, agents or branches")
+	rootCmd.Flags().StringVar(&pprofAddr, "pprof", "", "serve runtime profiles on this address, e.g. :6060")
+	rootCmd.Flags().BoolVar(&debugLog, "debug", false, "log update loop and pane render timings to debug.log in the config directory, and everything else to the application log")
 
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(listCmd)
@@ -100,7 +142,11 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all projects",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return app.ListProjects()
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return app.ListProjects(cfg)
 	},
 }
 
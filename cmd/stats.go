@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	statsFrom string
+	statsTo   string
+	statsJSON bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cycle time, throughput and agent statistics",
+	Long: `Summarise ticket and agent history per project.
+
+Reports tickets created and completed, throughput, cycle time (start to
+done), agent success rate, agent time, and estimated cost when agents set
+cost_per_hour in the config. A second table compares agent types: the
+attempts they took per ticket and how often a ticket needed a retry.
+Defaults to the last 30 days.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now()
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+		if statsTo != "" {
+			t, err := time.ParseInLocation(dateLayout, statsTo, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --to date %q (want YYYY-MM-DD)", statsTo)
+			}
+			to = t.AddDate(0, 0, 1)
+		}
+
+		from := to.AddDate(0, 0, -30)
+		if statsFrom != "" {
+			t, err := time.ParseInLocation(dateLayout, statsFrom, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --from date %q (want YYYY-MM-DD)", statsFrom)
+			}
+			from = t
+		}
+
+		if !from.Before(to) {
+			return errors.New("--from must be before --to")
+		}
+
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load config, costs unavailable: %v\n", err)
+			cfg = config.DefaultConfig()
+		}
+
+		return app.Stats(cfg, projectPath, from, to, statsJSON)
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsFrom, "from", "", "start date, inclusive (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsTo, "to", "", "end date, inclusive (YYYY-MM-DD)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "output JSON")
+
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync boards with the team branch",
+	Long: `Merge each project's board with the copy other team members pushed to the
+sync branch (openkanban-state by default), then push the result.
+
+Runs once, whether or not sync.enabled is set; with it set the board also
+syncs in the background while openkanban runs. Use --project to sync one
+project.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load config, using defaults: %v\n", err)
+			cfg = config.DefaultConfig()
+		}
+		return app.Sync(cfg, projectPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
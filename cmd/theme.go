@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var (
+	themeImportName    string
+	themeImportForce   bool
+	themePreviewWidth  int
+	themePreviewHeight int
+)
+
+var themeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "Manage color themes",
+}
+
+var themeImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Convert a terminal color scheme into a user theme",
+	Long: `Convert a base16 scheme (YAML), an iTerm2 .itermcolors file or an
+alacritty color config (TOML or YAML) and install it in the themes directory.
+
+  openkanban theme import tomorrow-night.yaml
+  openkanban theme import Dracula.itermcolors --name dracula-iterm
+
+The theme is installed under a name derived from the scheme; select it with
+openkanban config set ui.theme <name>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		theme, format, err := config.ImportTheme(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to import theme: %w", err)
+		}
+
+		key := themeImportName
+		if key == "" {
+			key = config.ThemeKey(theme.Name)
+		}
+		path, err := config.InstallTheme(key, theme, themeImportForce)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported %s theme %q as %s (%s)\n", format, theme.Name, key, path)
+		fmt.Printf("Use it with: openkanban config set ui.theme %s\n", key)
+		reloadRunningBoard()
+		return nil
+	},
+}
+
+var themePreviewCmd = &cobra.Command{
+	Use:   "preview <name>",
+	Short: "Render a sample board with a theme",
+	Long: `Print a sample board in the named built-in or user theme without
+changing the config.
+
+  openkanban theme preview nord
+  openkanban theme preview tomorrow-night --width 120`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return app.ThemePreview(cfg, args[0], themePreviewWidth, themePreviewHeight)
+	},
+}
+
+func init() {
+	themeImportCmd.Flags().StringVar(&themeImportName, "name", "", "install under this name instead of one derived from the scheme")
+	themeImportCmd.Flags().BoolVarP(&themeImportForce, "force", "f", false, "replace an existing theme with the same name")
+	themePreviewCmd.Flags().IntVar(&themePreviewWidth, "width", 100, "preview width in columns")
+	themePreviewCmd.Flags().IntVar(&themePreviewHeight, "height", 24, "preview height in rows")
+
+	themeCmd.AddCommand(themeImportCmd)
+	themeCmd.AddCommand(themePreviewCmd)
+	rootCmd.AddCommand(themeCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var vaultPath string
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Export tickets to an Obsidian vault",
+}
+
+var vaultExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write every ticket as an interlinked Markdown note",
+	Long: `Write a note per ticket, with its description, branch, pull request,
+agent runs and commits, and an index note per project linking its tickets
+with [[wikilinks]]. Notes go to vault.folder inside the vault.
+
+Re-exporting replaces the notes but keeps anything written below the
+"Notes below this line are kept" marker.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.VaultExport(cfg, vaultPath)
+	},
+}
+
+func init() {
+	vaultExportCmd.Flags().StringVar(&vaultPath, "vault", "", "vault directory (default vault.path)")
+
+	vaultCmd.AddCommand(vaultExportCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream board events as line-delimited JSON",
+	Long: `Print every event from the running board as one JSON object per line
+until interrupted or the board exits. Event types are ticket.created,
+ticket.moved, ticket.merged, agent.completed, agent.failed and
+agent.waiting, plus ticket.changed and agent.state for every change to a
+ticket or an agent's state.
+
+  openkanban watch | grep --line-buffered agent.failed | xargs -L1 notify-send`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return app.Watch()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/techdufus/openkanban/internal/app"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+var (
+	pruneDryRun       bool
+	pruneForce        bool
+	pruneDeleteBranch bool
+	openPrintPath     bool
+	openEditor        bool
+)
+
+var worktreeCmd = &cobra.Command{
+	Use:     "worktree",
+	Aliases: []string{"wt"},
+	Short:   "Manage ticket worktrees",
+}
+
+var worktreeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ticket worktrees with branch and disk usage",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.WorktreeList(cfg)
+	},
+}
+
+var worktreePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove worktrees of done, archived or deleted tickets",
+	Long: `Remove worktrees whose tickets are done or archived, and worktrees in a
+project's worktree directory that no ticket references any more.
+
+Worktrees with uncommitted changes are kept unless --force is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		return app.WorktreePrune(cfg, pruneDryRun, pruneForce, pruneDeleteBranch)
+	},
+}
+
+var worktreeOpenCmd = &cobra.Command{
+	Use:   "open <ticket>",
+	Short: "Open a shell in a ticket's worktree",
+	Long: `Start a shell in the ticket's worktree. With --print, only print the path:
+
+  cd "$(openkanban worktree open --print fix-login)"
+
+With --editor, open it in editor.command (or $VISUAL/$EDITOR) instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		if openEditor {
+			return app.WorktreeEdit(cfg, args[0])
+		}
+		return app.WorktreeOpen(cfg, args[0], openPrintPath)
+	},
+}
+
+func init() {
+	worktreePruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "show what would be removed")
+	worktreePruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "remove worktrees with uncommitted changes")
+	worktreePruneCmd.Flags().BoolVar(&pruneDeleteBranch, "delete-branch", false, "also delete the worktree's branch")
+	worktreeOpenCmd.Flags().BoolVar(&openPrintPath, "print", false, "print the worktree path instead of starting a shell")
+	worktreeOpenCmd.Flags().BoolVarP(&openEditor, "editor", "e", false, "open the worktree in your editor instead of a shell")
+
+	worktreeCmd.AddCommand(worktreeListCmd)
+	worktreeCmd.AddCommand(worktreePruneCmd)
+	worktreeCmd.AddCommand(worktreeOpenCmd)
+
+	rootCmd.AddCommand(worktreeCmd)
+}
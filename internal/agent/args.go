@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// placeholderPattern matches the placeholders of spawn_args and resume_args
+var placeholderPattern = regexp.MustCompile(`\{(prompt|worktree|model|port|branch|ticket)\}`)
+
+// ExpandArgs fills the placeholders in an agent's argument templates from
+// vars. An argument whose placeholders all come out empty is dropped, along
+// with the flag before it when it was the flag's value, so
+// ["--model", "{model}"] disappears when no model is set.
+func ExpandArgs(templates []string, vars map[string]string) []string {
+	args := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		filled := false
+		arg := placeholderPattern.ReplaceAllStringFunc(tmpl, func(p string) string {
+			v := vars[strings.Trim(p, "{}")]
+			filled = filled || v != ""
+			return v
+		})
+		if !placeholderPattern.MatchString(tmpl) || filled {
+			args = append(args, arg)
+			continue
+		}
+		if tmpl == placeholderPattern.FindString(tmpl) && len(args) > 0 && strings.HasPrefix(args[len(args)-1], "-") {
+			args = args[:len(args)-1]
+		}
+	}
+	return args
+}
+
+// UsesPort reports whether templates need a port for the agent's server.
+func UsesPort(templates []string) bool {
+	for _, tmpl := range templates {
+		if strings.Contains(tmpl, "{port}") {
+			return true
+		}
+	}
+	return false
+}
+
+// Environ returns the variables the agent runs with, as KEY=value: its env
+// and, when it has a model, ModelEnv set to it.
+func Environ(cfg config.AgentConfig) []string {
+	env := make([]string, 0, len(cfg.Env)+1)
+	for k, v := range cfg.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	if cfg.ModelEnv != "" && cfg.Model != "" {
+		env = append(env, cfg.ModelEnv+"="+cfg.Model)
+	}
+	return env
+}
@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+func TestExpandArgs(t *testing.T) {
+	templates := []string{"--cwd", "{worktree}", "--model", "{model}", "--title={ticket}: {branch}", "{prompt}"}
+
+	got := ExpandArgs(templates, map[string]string{
+		"worktree": "/wt",
+		"model":    "opus",
+		"ticket":   "OKB-1",
+		"prompt":   "Fix the login",
+	})
+	want := []string{"--cwd", "/wt", "--model", "opus", "--title=OKB-1: ", "Fix the login"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExpandArgs() = %q; want %q", got, want)
+	}
+
+	// Unset placeholders drop their argument and the flag it belongs to
+	got = ExpandArgs(templates, map[string]string{"worktree": "/wt"})
+	want = []string{"--cwd", "/wt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExpandArgs() without model or prompt = %q; want %q", got, want)
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	cfg := config.AgentConfig{
+		Env:      map[string]string{"B": "2", "A": "1"},
+		Model:    "opus",
+		ModelEnv: "AGENT_MODEL",
+	}
+	want := []string{"A=1", "B=2", "AGENT_MODEL=opus"}
+	if got := Environ(cfg); !slices.Equal(got, want) {
+		t.Errorf("Environ() = %q; want %q", got, want)
+	}
+
+	cfg.Model = ""
+	if got := Environ(cfg); len(got) != 2 {
+		t.Errorf("Environ() without a model = %q; want only env", got)
+	}
+}
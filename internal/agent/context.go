@@ -2,6 +2,9 @@ package agent
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -11,6 +14,7 @@ import (
 type ContextData struct {
 	Title        string
 	Description  string
+	Notes        string
 	BranchName   string
 	BaseBranch   string
 	TicketID     string
@@ -26,6 +30,7 @@ func BuildContextPrompt(promptTemplate string, ticket *board.Ticket) string {
 	data := ContextData{
 		Title:        ticket.Title,
 		Description:  ticket.Description,
+		Notes:        ticket.Notes,
 		BranchName:   ticket.BranchName,
 		BaseBranch:   ticket.BaseBranch,
 		TicketID:     string(ticket.ID),
@@ -33,17 +38,32 @@ func BuildContextPrompt(promptTemplate string, ticket *board.Ticket) string {
 		WorktreePath: ticket.WorktreePath,
 	}
 
-	tmpl, err := template.New("prompt").Parse(promptTemplate)
-	if err != nil {
-		return buildFallbackPrompt(ticket)
+	prompt := buildFallbackPrompt(ticket)
+	if tmpl, err := template.New("prompt").Parse(promptTemplate); err == nil {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err == nil {
+			prompt = buf.String()
+		}
 	}
+	return prompt
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return buildFallbackPrompt(ticket)
+// IteratePrompt extends a ticket's prompt for a fresh run on work that was
+// reviewed and sent back: what the worktree already changes and the
+// reviewer's feedback
+func IteratePrompt(prompt, feedback, changes string) string {
+	var parts []string
+	if prompt = strings.TrimSpace(prompt); prompt != "" {
+		parts = append(parts, prompt)
 	}
-
-	return buf.String()
+	intro := "A previous attempt at this task was reviewed and sent back. Its work is in this worktree"
+	if changes != "" {
+		parts = append(parts, intro+", changing:\n\n"+changes)
+	} else {
+		parts = append(parts, intro+".")
+	}
+	parts = append(parts, "Build on it and address the review feedback:\n\n"+feedback)
+	return strings.Join(parts, "\n\n")
 }
 
 func buildFallbackPrompt(ticket *board.Ticket) string {
@@ -57,6 +77,33 @@ func buildFallbackPrompt(ticket *board.Ticket) string {
 	return sb.String()
 }
 
+// WriteContextFile writes the prompt to name in the worktree, for agents
+// given their context as a file, and returns the file's path
+func WriteContextFile(worktree, name, prompt string) (string, error) {
+	path := filepath.Join(worktree, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(prompt+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write context file: %w", err)
+	}
+	return path, nil
+}
+
+// ContextFilePrompt asks an agent to read its context from name
+func ContextFilePrompt(name string) string {
+	return "Your task is described in " + filepath.ToSlash(name) + ". Read it and follow it."
+}
+
+// ContextFileArgs fills the context file's path into an agent's context_args
+func ContextFileArgs(args []string, path string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = strings.ReplaceAll(a, "{file}", path)
+	}
+	return out
+}
+
 func ShouldInjectContext(ticket *board.Ticket) bool {
 	return ticket.AgentSpawnedAt == nil
 }
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -114,6 +115,20 @@ func TestBuildContextPrompt_InvalidTemplate(t *testing.T) {
 	}
 }
 
+func TestIteratePrompt(t *testing.T) {
+	got := IteratePrompt("Task: Fix login\n", "Keep the old error message", "M login.go +3 -1")
+	for _, want := range []string{"Task: Fix login\n\nA previous attempt", "changing:\n\nM login.go +3 -1", "feedback:\n\nKeep the old error message"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("IteratePrompt() = %q; want it to contain %q", got, want)
+		}
+	}
+
+	// Agents without an init prompt still get the feedback
+	if got := IteratePrompt("", "Add tests", ""); !strings.HasPrefix(got, "A previous attempt") || !strings.HasSuffix(got, "Add tests") {
+		t.Errorf("IteratePrompt() without a prompt = %q", got)
+	}
+}
+
 func TestBuildFallbackPrompt(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -149,6 +164,22 @@ func TestBuildFallbackPrompt(t *testing.T) {
 	}
 }
 
+func TestWriteContextFile(t *testing.T) {
+	worktree := t.TempDir()
+	path, err := WriteContextFile(worktree, "docs/TASK.md", "Task: fix login")
+	if err != nil {
+		t.Fatalf("WriteContextFile() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "Task: fix login\n" {
+		t.Errorf("context file = %q", data)
+	}
+
+	args := ContextFileArgs([]string{"--message-file", "{file}"}, path)
+	if args[1] != path {
+		t.Errorf("ContextFileArgs() = %v; want the path filled in", args)
+	}
+}
+
 func TestShouldInjectContext(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -184,16 +215,17 @@ func TestContextData_AllFieldsMapped(t *testing.T) {
 		ID:           "test-id-123",
 		Title:        "Test Title",
 		Description:  "Test Description",
+		Notes:        "Test Notes",
 		BranchName:   "feature/test",
 		BaseBranch:   "main",
 		Status:       board.StatusInProgress,
 		WorktreePath: "/home/user/project-worktrees/test",
 	}
 
-	template := "{{.TicketID}}|{{.Title}}|{{.Description}}|{{.BranchName}}|{{.BaseBranch}}|{{.Status}}|{{.WorktreePath}}"
+	template := "{{.TicketID}}|{{.Title}}|{{.Description}}|{{.Notes}}|{{.BranchName}}|{{.BaseBranch}}|{{.Status}}|{{.WorktreePath}}"
 	result := BuildContextPrompt(template, ticket)
 
-	expected := "test-id-123|Test Title|Test Description|feature/test|main|in_progress|/home/user/project-worktrees/test"
+	expected := "test-id-123|Test Title|Test Description|Test Notes|feature/test|main|in_progress|/home/user/project-worktrees/test"
 	if result != expected {
 		t.Errorf("All fields mapping:\ngot:  %q\nwant: %q", result, expected)
 	}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// followUpHeading matches a line introducing the work an agent left undone,
+// e.g. "## Remaining TODOs" or "Next steps:"
+var followUpHeading = regexp.MustCompile(`(?i)^[#*_\s]*(remaining|todos?\b|follow[- ]?ups?|next steps|still to do|not (yet )?done|left to do|out of scope)`)
+
+// listMarker matches the bullet, number or checkbox starting a list item
+var listMarker = regexp.MustCompile(`^\s*(?:[-*+•]|\d+[.)])\s+(?:\[[ xX]?\]\s+)?`)
+
+// FollowUps returns the items of the last list an agent introduced as
+// remaining work, such as a "Remaining TODOs:" heading followed by bullets.
+// Lines indented under an item continue it. It returns nil when the output
+// has no such list.
+func FollowUps(output string) []string {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !followUpHeading.MatchString(lines[i]) {
+			continue
+		}
+		if items := listAfter(lines[i+1:]); len(items) > 0 {
+			return items
+		}
+	}
+	return nil
+}
+
+// listAfter collects the list starting at the top of lines, skipping blank
+// lines, up to the first line that neither starts nor continues an item
+func listAfter(lines []string) []string {
+	var items []string
+	for _, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case listMarker.MatchString(line):
+			if item := listItem(line); item != "" {
+				items = append(items, item)
+			}
+		case len(items) > 0 && (line[0] == ' ' || line[0] == '\t'):
+			items[len(items)-1] += " " + strings.TrimSpace(line)
+		default:
+			return items
+		}
+	}
+	return items
+}
+
+// ListItems returns each non-blank line of text as an item, with any list
+// marker removed, such as the lines of a region selected in a pane
+func ListItems(text string) []string {
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		if item := listItem(line); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func listItem(line string) string {
+	return strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFollowUps(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name: "bullets under a heading",
+			output: `Implemented the login form.
+
+## Remaining TODOs
+- Add rate limiting
+* [ ] Write e2e tests
+  for the signup flow
+
+All done for now.`,
+			want: []string{"Add rate limiting", "Write e2e tests for the signup flow"},
+		},
+		{
+			name: "numbered next steps, last list wins",
+			output: `Next steps:
+1. Old item
+
+Follow-ups:
+1. Cache the lookup
+2) Drop the legacy flag`,
+			want: []string{"Cache the lookup", "Drop the legacy flag"},
+		},
+		{
+			name:   "heading without a list",
+			output: "TODO: nothing here\nJust prose.",
+			want:   nil,
+		},
+		{
+			name:   "no heading",
+			output: "- a bullet\n- another",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FollowUps(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FollowUps() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListItems(t *testing.T) {
+	got := ListItems("  - Fix the flaky test\n\n3. Bump deps\nplain line  ")
+	want := []string{"Fix the flaky test", "Bump deps", "plain line"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListItems() = %q; want %q", got, want)
+	}
+}
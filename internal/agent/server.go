@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/secretenv"
 )
 
 type OpencodeServer struct {
@@ -49,6 +50,7 @@ func (s *OpencodeServer) Start() error {
 	}
 
 	s.cmd = exec.Command("opencode", "serve", "--port", fmt.Sprintf("%d", s.port))
+	s.cmd.Env = secretenv.Environ()
 	if err := s.cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start opencode server: %w", err)
 	}
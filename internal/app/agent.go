@@ -0,0 +1,136 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+)
+
+// AgentSpawn asks the running instance to spawn agents for the given tickets
+// and for every ticket carrying label.
+func AgentSpawn(tickets []string, label string) error {
+	resp, err := callControl(control.Request{Method: control.MethodSpawn, Tickets: tickets, Label: label})
+	if err != nil {
+		if errors.Is(err, control.ErrNotRunning) {
+			return fmt.Errorf("%w; start the board with: openkanban", err)
+		}
+		return err
+	}
+	return printAgentResults("spawning", resp.Tickets)
+}
+
+// AgentStop asks the running instance to stop the agents for the given tickets.
+func AgentStop(tickets []string, label string) error {
+	resp, err := callControl(control.Request{Method: control.MethodStop, Tickets: tickets, Label: label})
+	if err != nil {
+		return err
+	}
+	return printAgentResults("stopped", resp.Tickets)
+}
+
+// AgentStatus prints agent state for the given tickets, or for all active
+// tickets when none are given. When openkanban is not running it reports the
+// last persisted state from the ticket store.
+func AgentStatus(cfg *config.Config, tickets []string, label string) error {
+	resp, err := callControl(control.Request{Method: control.MethodStatus, Tickets: tickets, Label: label})
+	if err == nil {
+		printAgentStatus(resp.Tickets)
+		return nil
+	}
+	if !errors.Is(err, control.ErrNotRunning) {
+		return err
+	}
+
+	states, err := storedAgentStatus(cfg, tickets, label)
+	if err != nil {
+		return err
+	}
+	fmt.Println("openkanban is not running; showing saved state.")
+	fmt.Println()
+	printAgentStatus(states)
+	return nil
+}
+
+func storedAgentStatus(cfg *config.Config, refs []string, label string) ([]control.TicketState, error) {
+	store, err := loadStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickets []*board.Ticket
+	for _, ref := range refs {
+		t, err := store.Find(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		tickets = append(tickets, t)
+	}
+	if label != "" {
+		tickets = append(tickets, store.FindByLabel(label)...)
+	}
+	if len(refs) == 0 && label == "" {
+		tickets = store.GetByStatus(board.StatusInProgress)
+	}
+
+	states := make([]control.TicketState, 0, len(tickets))
+	for _, t := range tickets {
+		var projectName string
+		if p := store.GetProjectForTicket(t); p != nil {
+			projectName = p.Name
+		}
+		states = append(states, control.NewTicketState(t, projectName, false))
+	}
+	return states, nil
+}
+
+func printAgentResults(verb string, states []control.TicketState) error {
+	failed := 0
+	for _, s := range states {
+		if s.Error != "" {
+			failed++
+			fmt.Printf("  %s  %s: %s\n", shortID(s.ID), s.Title, s.Error)
+			continue
+		}
+		fmt.Printf("  %s  %s: %s\n", shortID(s.ID), s.Title, verb)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d ticket(s) failed", failed, len(states))
+	}
+	return nil
+}
+
+func printAgentStatus(states []control.TicketState) {
+	if len(states) == 0 {
+		fmt.Println("No active agents.")
+		return
+	}
+
+	for _, s := range states {
+		agentState := "stopped"
+		if s.Running {
+			agentState = s.AgentStatus
+			if agentState == "" || agentState == string(board.AgentNone) {
+				agentState = "running"
+			}
+		}
+		agentType := s.AgentType
+		if agentType == "" {
+			agentType = "-"
+		}
+		fmt.Printf("  %s  %-12s %-10s %-8s %s", shortID(s.ID), s.Status, agentType, agentState, s.Title)
+		if s.Project != "" {
+			fmt.Printf(" (%s)", s.Project)
+		}
+		fmt.Println()
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,17 +11,19 @@ import (
 	"github.com/techdufus/openkanban/internal/config"
 	"github.com/techdufus/openkanban/internal/git"
 	"github.com/techdufus/openkanban/internal/project"
+	"github.com/techdufus/openkanban/internal/telemetry"
 	"github.com/techdufus/openkanban/internal/ui"
 	"github.com/techdufus/openkanban/internal/update"
 )
 
-func Run(cfg *config.Config, filterPath, version string) error {
+func Run(cfg *config.Config, configPath string, target LaunchTarget, debug DebugOptions, version string) error {
 	registry, err := project.LoadRegistry()
 	if err != nil {
 		return fmt.Errorf("failed to load project registry: %w", err)
 	}
 
-	globalStore, err := project.LoadGlobalTicketStore(registry)
+	cipher := ticketCipher(cfg)
+	globalStore, err := project.LoadGlobalTicketStoreWith(registry, project.EncryptedJSONRepository(cipher))
 	if err != nil {
 		return fmt.Errorf("failed to load tickets: %w", err)
 	}
@@ -31,13 +32,25 @@ func Run(cfg *config.Config, filterPath, version string) error {
 		return fmt.Errorf("no projects registered. Create one with: openkanban new")
 	}
 
-	var filterProjectID string
-	if filterPath != "" {
-		absPath, _ := filepath.Abs(filterPath)
-		absPath = git.ResolveMainRepo(absPath)
-		if p, err := registry.FindByPath(absPath); err == nil {
-			filterProjectID = p.ID
+	cwd, _ := os.Getwd()
+	filterProjectID, focusTicketID, err := resolveLaunchTarget(registry, globalStore, target, cwd)
+	if err != nil {
+		return err
+	}
+	git.IsolateEnv()
+
+	if debug.Pprof != "" {
+		stop, err := startPprof(debug.Pprof)
+		if err != nil {
+			return err
 		}
+		defer stop()
+	}
+
+	if cfg.Telemetry.Enabled {
+		tracer := telemetry.New(cfg.Telemetry.Endpoint, cfg.Telemetry.ServiceName, cfg.Telemetry.Headers)
+		telemetry.SetDefault(tracer)
+		defer tracer.Shutdown()
 	}
 
 	agentMgr := agent.NewManager(cfg)
@@ -54,13 +67,41 @@ func Run(cfg *config.Config, filterPath, version string) error {
 
 	updateChecker := update.NewChecker(version)
 	model := ui.NewModel(cfg, globalStore, registry, agentMgr, opencodeServer, filterProjectID, updateChecker)
+	model.SetSyncCipher(cipher)
+	model.SetConfigPath(configPath)
+	if filterProjectID == "" && focusTicketID == "" {
+		model.ApplyDefaultFilter()
+	}
+	if focusTicketID != "" {
+		model.FocusTicket(focusTicketID)
+	}
 
 	defer model.Cleanup()
 
+	if debug.Log {
+		f, err := openDebugLog()
+		if err != nil {
+			return fmt.Errorf("failed to open debug log: %w", err)
+		}
+		defer func() {
+			f.Close()
+			fmt.Fprintf(os.Stderr, "Debug log: %s\n", f.Name())
+		}()
+		model.EnableDebugLog(f)
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	guard := &crashGuard{model: model}
+	program := tea.NewProgram(guard, tea.WithAltScreen(), tea.WithMouseAllMotion(), tea.WithReportFocus())
+
+	srv, hub := startControlServer(program, model.Events())
+	if srv != nil {
+		model.SetControlSocket(srv.Path())
+		defer srv.Close()
+	}
+	defer shareBoard(program, model, hub)()
 
 	go func() {
 		<-sigChan
@@ -68,14 +109,45 @@ func Run(cfg *config.Config, filterPath, version string) error {
 		program.Quit()
 	}()
 
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	defer signal.Stop(hupChan)
+
+	go func() {
+		for range hupChan {
+			program.Send(ui.ReloadConfigMsg{})
+		}
+	}()
+
 	_, err = program.Run()
+	if guard.crash != nil {
+		return recoverFromCrash(guard.crash, globalStore, version)
+	}
+	if saveErr := globalStore.SaveDirty(); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to save tickets: %v\n", saveErr)
+	}
 	return err
 }
 
+// recoverFromCrash saves every ticket as it stood and writes a crash report.
+// Agents running in tmux are left running to be adopted on the next start.
+func recoverFromCrash(c *crash, store *project.GlobalTicketStore, version string) error {
+	if err := store.SaveAll(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save tickets: %v\n", err)
+	}
+	path, err := writeCrashReport(c, version)
+	if err != nil {
+		return fmt.Errorf("openkanban crashed: %v (failed to write crash report: %w)", c.value, err)
+	}
+	return fmt.Errorf("openkanban crashed: %v\ncrash report: %s", c.value, path)
+}
+
 func CreateProject(cfg *config.Config, name, repoPath string) error {
-	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+	if !git.IsRepository(repoPath) {
 		return fmt.Errorf("not a git repository: %s", repoPath)
 	}
+	// A subdirectory or linked worktree registers the repository it belongs to
+	repoPath = git.ResolveMainRepo(repoPath)
 
 	registry, err := project.LoadRegistry()
 	if err != nil {
@@ -99,13 +171,15 @@ func CreateProject(cfg *config.Config, name, repoPath string) error {
 	return nil
 }
 
-func ListProjects() error {
+func ListProjects(cfg *config.Config) error {
 	registry, err := project.LoadRegistry()
 	if err != nil {
 		return err
 	}
 
 	projects := registry.List()
+	project.SortProjects(projects, project.Order(cfg.UI.ProjectOrder))
+	cipher := ticketCipher(cfg)
 	if len(projects) == 0 {
 		fmt.Println("No projects found. Create one with: openkanban new")
 		return nil
@@ -115,37 +189,36 @@ func ListProjects() error {
 	fmt.Println()
 
 	for _, p := range projects {
-		tickets, err := project.LoadTicketStore(p)
-		if err != nil {
-			continue
-		}
-
-		total := tickets.Count()
-		inProgress := tickets.CountByStatus("in_progress")
-
 		fmt.Printf("  %s (%s)\n", p.Name, p.ID[:8])
 		fmt.Printf("    Path: %s\n", p.RepoPath)
-		fmt.Printf("    Tickets: %d total, %d in progress\n", total, inProgress)
+		if tickets, err := project.LoadEncryptedTicketStore(p, cipher); err != nil {
+			fmt.Printf("    Tickets: unavailable (%v)\n", err)
+		} else {
+			fmt.Printf("    Tickets: %d total, %d in progress\n", tickets.Count(), tickets.CountByStatus("in_progress"))
+		}
 		fmt.Println()
 	}
 
 	return nil
 }
 
+// matchProject finds a project by name, ID, or 8-char ID prefix.
+func matchProject(registry *project.ProjectRegistry, nameOrID string) *project.Project {
+	for _, p := range registry.List() {
+		if p.Name == nameOrID || p.ID == nameOrID || (len(p.ID) >= 8 && p.ID[:8] == nameOrID) {
+			return p
+		}
+	}
+	return nil
+}
+
 func DeleteProject(nameOrID string) error {
 	registry, err := project.LoadRegistry()
 	if err != nil {
 		return err
 	}
 
-	var target *project.Project
-	for _, p := range registry.List() {
-		if p.Name == nameOrID || p.ID == nameOrID || (len(p.ID) >= 8 && p.ID[:8] == nameOrID) {
-			target = p
-			break
-		}
-	}
-
+	target := matchProject(registry, nameOrID)
 	if target == nil {
 		return fmt.Errorf("project not found: %s", nameOrID)
 	}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/changelog"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// ChangelogOptions selects the tickets a changelog covers
+type ChangelogOptions struct {
+	Project  string    // project name, ID or path; "" for all projects
+	Ticket   string    // a single ticket, in any status
+	Label    string    // only tickets with this label, e.g. a milestone
+	From, To time.Time // completion date range; zero means unbounded
+	Title    string
+	ByTicket bool
+	Output   string // file to write; "" for stdout
+}
+
+// Changelog writes Markdown release notes from the commits on completed
+// tickets' branches, grouped by conventional-commit type
+func Changelog(cfg *config.Config, opts ChangelogOptions) error {
+	git.IsolateEnv()
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+	store, err := loadTickets(cfg, registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	var tickets []*board.Ticket
+	if opts.Ticket != "" {
+		t, err := store.Find(opts.Ticket)
+		if err != nil {
+			return fmt.Errorf("%s: %w", opts.Ticket, err)
+		}
+		tickets = []*board.Ticket{t}
+	} else {
+		var projectID string
+		if opts.Project != "" {
+			p := findProject(registry, opts.Project)
+			if p == nil {
+				return fmt.Errorf("project not found: %s", opts.Project)
+			}
+			projectID = p.ID
+		}
+		tickets = completedTickets(store.All(), projectID, opts)
+	}
+
+	entries := make([]changelog.Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		entries = append(entries, changelog.Ticket{Title: t.Title, Changes: ticketChanges(store, t)})
+	}
+
+	title := opts.Title
+	if title == "" {
+		switch {
+		case opts.Ticket != "" && len(tickets) == 1:
+			title = tickets[0].Title
+		case opts.Label != "":
+			title = opts.Label
+		default:
+			title = "Unreleased"
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", opts.Output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := changelog.Write(w, title, entries, opts.ByTicket); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// completedTickets returns the Done and archived tickets matching opts, in
+// the order they were completed
+func completedTickets(all []*board.Ticket, projectID string, opts ChangelogOptions) []*board.Ticket {
+	var tickets []*board.Ticket
+	for _, t := range all {
+		if t.Status != board.StatusDone && t.Status != board.StatusArchived || t.CompletedAt == nil {
+			continue
+		}
+		if projectID != "" && t.ProjectID != projectID {
+			continue
+		}
+		if opts.Label != "" && !slices.Contains(t.Labels, opts.Label) {
+			continue
+		}
+		if !opts.From.IsZero() && t.CompletedAt.Before(opts.From) {
+			continue
+		}
+		if !opts.To.IsZero() && !t.CompletedAt.Before(opts.To) {
+			continue
+		}
+		tickets = append(tickets, t)
+	}
+	slices.SortStableFunc(tickets, func(a, b *board.Ticket) int {
+		return a.CompletedAt.Compare(*b.CompletedAt)
+	})
+	return tickets
+}
+
+// ticketChanges parses the commits on a ticket's branch, oldest first.
+// Once the branch is merged or deleted its commits can no longer be told
+// apart, so the ticket title stands in for them.
+func ticketChanges(store *project.GlobalTicketStore, t *board.Ticket) []changelog.Change {
+	fallback := []changelog.Change{changelog.Parse(t.Title, "")}
+	proj := store.GetProjectForTicket(t)
+	if proj == nil {
+		return fallback
+	}
+	commits := branchCommits(git.NewWorktreeManager(proj), proj.RepoPath, t.BranchName, t.BaseBranch)
+
+	var changes []changelog.Change
+	for _, c := range slices.Backward(commits) {
+		if strings.HasPrefix(c.Subject, "Merge ") {
+			continue
+		}
+		change := changelog.Parse(c.Subject, c.Body)
+		change.Hash = c.ShortHash()
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return fallback
+	}
+	return changes
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/ui"
+)
+
+// collabQueue is how many messages may wait for a slow connection before
+// further ones are dropped.
+const collabQueue = 256
+
+// collabName is what the other instances sharing the board call this one.
+func collabName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "openkanban"
+}
+
+// shareBoard works on the board together with the other instances on this
+// machine. The instance owning the control socket relays through hub; any
+// later one joins it. The returned function stops sharing.
+func shareBoard(program *tea.Program, model *ui.Model, hub *control.Hub) func() {
+	if hub != nil {
+		send, stop := queueMessages(hub.Send)
+		model.SetCollab(hub.Name(), false, send)
+		unsubscribe := shareEvents(model.Events(), send)
+		return func() {
+			unsubscribe()
+			stop()
+		}
+	}
+
+	path, err := config.SocketPath()
+	if err != nil {
+		return func() {}
+	}
+	peer, err := control.Join(path, config.ControlToken(), collabName())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: CLI control disabled: %v\n", err)
+		return func() {}
+	}
+	go func() {
+		err := peer.Receive(func(msg control.Message) {
+			program.Send(ui.CollabMsg(msg))
+		})
+		program.Send(ui.CollabClosedMsg{Err: err})
+	}()
+
+	send, stop := queueMessages(func(msg control.Message) { _ = peer.Send(msg) })
+	model.SetCollab(peer.Name(), true, send)
+	unsubscribe := shareEvents(model.Events(), send)
+	return func() {
+		unsubscribe()
+		stop()
+		peer.Close()
+	}
+}
+
+// shareEvents sends the board's own changes to the other instances. Their
+// changes are applied without being published, so nothing echoes back.
+func shareEvents(bus *events.Bus, send func(control.Message)) func() {
+	ch, unsubscribe := bus.SubscribeBuffered(collabQueue)
+	go func() {
+		for e := range ch {
+			if e.Ticket != nil {
+				send(control.Message{Event: &e})
+			}
+		}
+	}()
+	return unsubscribe
+}
+
+// queueMessages sends messages in order on their own goroutine, so the board
+// never waits on another instance. The returned stop ends the goroutine.
+func queueMessages(send func(control.Message)) (func(control.Message), func()) {
+	queue := make(chan control.Message, collabQueue)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case msg := <-queue:
+				send(msg)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	enqueue := func(msg control.Message) {
+		select {
+		case queue <- msg:
+		default:
+		}
+	}
+	stop := func() { close(done) }
+	return enqueue, stop
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/telemetry"
+	"github.com/techdufus/openkanban/internal/ui"
+)
+
+const controlReplyTimeout = 10 * time.Second
+
+// startControlServer exposes the running program and its event bus on the
+// control socket, with a hub for other instances to share the board through.
+// Failure is not fatal: the board works without CLI control. When another
+// instance already owns the socket it returns nil without a warning, and
+// this one joins it instead; see shareBoard.
+func startControlServer(program *tea.Program, bus *events.Bus) (*control.Server, *control.Hub) {
+	path, err := config.SocketPath()
+	if err != nil {
+		return nil, nil
+	}
+
+	srv, err := control.Listen(path, func(req control.Request) control.Response {
+		span := telemetry.Start("control."+req.Method,
+			telemetry.String("control.method", req.Method),
+			telemetry.Int("control.tickets", len(req.Tickets)))
+		reply := make(chan control.Response, 1)
+		program.Send(ui.ControlRequestMsg{Request: req, Reply: reply})
+		var resp control.Response
+		select {
+		case resp = <-reply:
+		case <-time.After(controlReplyTimeout):
+			resp = control.Response{Error: "timed out waiting for openkanban"}
+		}
+		if resp.Error != "" {
+			span.End(errors.New(resp.Error))
+		} else {
+			span.End(nil)
+		}
+		return resp
+	})
+	if errors.Is(err, control.ErrAlreadyRunning) {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: CLI control disabled: %v\n", err)
+		return nil, nil
+	}
+
+	hub := control.NewHub(collabName(), func(msg control.Message) {
+		program.Send(ui.CollabMsg(msg))
+	})
+	srv.SetEvents(bus)
+	srv.SetHub(hub)
+	srv.SetToken(config.ControlToken())
+	go srv.Serve()
+	return srv, hub
+}
+
+func callControl(req control.Request) (*control.Response, error) {
+	path, err := config.SocketPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine socket path: %w", err)
+	}
+
+	req.Token = config.ControlToken()
+	resp, err := control.Call(path, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// boardRunning reports whether an instance answers on the control socket
+func boardRunning() bool {
+	path, err := config.SocketPath()
+	if err != nil {
+		return false
+	}
+	_, err = control.Call(path, control.Request{Method: control.MethodStatus, Token: config.ControlToken()})
+	return !errors.Is(err, control.ErrNotRunning)
+}
+
+// ReloadRunning asks a running instance to re-read its config. It reports
+// whether an instance was notified.
+func ReloadRunning() (bool, error) {
+	if _, err := callControl(control.Request{Method: control.MethodReload}); err != nil {
+		if errors.Is(err, control.ErrNotRunning) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Watch prints events from the running instance as line-delimited JSON until
+// the instance exits.
+func Watch() error {
+	path, err := config.SocketPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine socket path: %w", err)
+	}
+
+	return control.Watch(path, config.ControlToken(), func(raw json.RawMessage) error {
+		_, err := fmt.Fprintf(os.Stdout, "%s\n", raw)
+		return err
+	})
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// crash is a recovered panic with the state of every goroutine when it
+// happened.
+type crash struct {
+	value      any
+	time       time.Time
+	goroutines []byte
+}
+
+// crashMsg brings a panic in a command back to the update loop.
+type crashMsg struct{ crash *crash }
+
+// crashGuard wraps the board so a panic in Update, View or a command ends
+// the program normally instead of taking the process down: bubbletea then
+// restores the terminal, and Run can save tickets and write a crash report.
+type crashGuard struct {
+	model tea.Model
+	crash *crash
+}
+
+func recovered(value any) *crash {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	return &crash{value: value, time: time.Now(), goroutines: buf}
+}
+
+func (g *crashGuard) Init() tea.Cmd {
+	return guardCmd(g.model.Init())
+}
+
+func (g *crashGuard) Update(msg tea.Msg) (m tea.Model, cmd tea.Cmd) {
+	if msg, ok := msg.(crashMsg); ok {
+		g.crashed(msg.crash)
+		return g, tea.Quit
+	}
+	if g.crash != nil {
+		return g, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.crashed(recovered(r))
+			m, cmd = g, tea.Quit
+		}
+	}()
+	next, cmd := g.model.Update(msg)
+	g.model = next
+	return g, guardCmd(cmd)
+}
+
+func (g *crashGuard) View() (view string) {
+	if g.crash != nil {
+		return ""
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.crashed(recovered(r))
+			view = ""
+		}
+	}()
+	return g.model.View()
+}
+
+// crashed keeps the first panic; later ones are usually its fallout
+func (g *crashGuard) crashed(c *crash) {
+	if g.crash == nil {
+		g.crash = c
+	}
+}
+
+var cmdType = reflect.TypeOf(tea.Cmd(nil))
+
+// guardCmd recovers panics in cmd, and in the commands of the batch or
+// sequence it returns, as crash messages.
+func guardCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = crashMsg{crash: recovered(r)}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i, c := range batch {
+				batch[i] = guardCmd(c)
+			}
+			return batch
+		}
+		// tea.Sequence's message is an unexported []tea.Cmd
+		if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == cmdType {
+			cmds := make([]tea.Cmd, v.Len())
+			for i := range cmds {
+				cmds[i] = guardCmd(v.Index(i).Interface().(tea.Cmd))
+			}
+			return tea.Sequence(cmds...)()
+		}
+		return msg
+	}
+}
+
+// writeCrashReport saves the panic and goroutine dump under crash/ in the
+// config directory and returns the report's path.
+func writeCrashReport(c *crash, version string) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "crash")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "crash-"+c.time.Format("20060102-150405")+".log")
+	report := fmt.Sprintf("openkanban %s crashed at %s\n%s %s/%s\n\npanic: %v\n\n%s",
+		version, c.time.Format(time.RFC3339), runtime.Version(), runtime.GOOS, runtime.GOARCH,
+		c.value, c.goroutines)
+	if err := os.WriteFile(path, []byte(report), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type panicModel struct{ cmd tea.Cmd }
+
+func (m panicModel) Init() tea.Cmd { return nil }
+
+func (m panicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg == "boom" {
+		panic("update failed")
+	}
+	return m, m.cmd
+}
+
+func (m panicModel) View() string { return "board" }
+
+func TestCrashGuard_Update(t *testing.T) {
+	g := &crashGuard{model: panicModel{}}
+	m, cmd := g.Update("boom")
+	if m != g {
+		t.Errorf("Update() model = %#v; want the guard, which Bubble Tea renders next", m)
+	}
+	if g.crash == nil || g.crash.value != "update failed" {
+		t.Fatalf("crash = %+v; want the update panic", g.crash)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("a panic in Update should quit")
+	}
+	if g.View() != "" {
+		t.Error("View should render nothing after a crash")
+	}
+}
+
+func TestCrashGuard_Commands(t *testing.T) {
+	panics := func() tea.Msg { panic("command failed") }
+	for name, cmd := range map[string]tea.Cmd{
+		"plain":    panics,
+		"batch":    tea.Batch(panics, func() tea.Msg { return nil }),
+		"sequence": tea.Sequence(panics, func() tea.Msg { return nil }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := &crashGuard{model: panicModel{cmd: cmd}}
+			_, guarded := g.Update("tick")
+			msg := runFirst(guarded)
+			crashed, ok := msg.(crashMsg)
+			if !ok {
+				t.Fatalf("message = %#v; want crashMsg", msg)
+			}
+			if _, cmd := g.Update(crashed); cmd == nil || g.crash == nil {
+				t.Error("a crash message should record the crash and quit")
+			}
+		})
+	}
+}
+
+// runFirst runs cmd, following batches and sequences into their first command
+func runFirst(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		return runFirst(batch[0])
+	}
+	if cmds, ok := anyCmds(msg); ok {
+		return runFirst(cmds[0])
+	}
+	return msg
+}
+
+// anyCmds unpacks tea.Sequence's unexported message
+func anyCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != cmdType {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+func TestWriteCrashReport(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	g := &crashGuard{model: panicModel{}}
+	g.Update("boom")
+
+	path, err := writeCrashReport(g.crash, "v1.2.3")
+	if err != nil {
+		t.Fatalf("writeCrashReport() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"openkanban v1.2.3 crashed", "panic: update failed", "goroutine "} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report missing %q:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// DebugOptions turn on the diagnostics behind --pprof and --debug.
+type DebugOptions struct {
+	Pprof string // address to serve net/http/pprof on, e.g. ":6060"
+	Log   bool   // log update loop and pane render timings
+}
+
+// startPprof serves the runtime profiles on addr until the returned function
+// is called.
+func startPprof(addr string) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start pprof server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "pprof server stopped: %v\n", err)
+		}
+	}()
+	return func() { _ = srv.Close() }, nil
+}
+
+// openDebugLog truncates debug.log in the config directory for this session.
+func openDebugLog() (*os.File, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, "debug.log"))
+}
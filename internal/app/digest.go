@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/digest"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// DigestOptions controls how a digest is delivered
+type DigestOptions struct {
+	Period    string // "daily" or "weekly"; empty uses digest.period
+	Send      bool   // mail it through digest.smtp
+	Output    string // file to write the Markdown to; "" for stdout unless sending
+	SkipEmpty bool   // neither write nor send when nothing happened
+}
+
+// Digest summarises the last day or week of board activity. When
+// filterPath is set only the project containing that path is included.
+func Digest(cfg *config.Config, filterPath string, opts DigestOptions) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := loadTickets(cfg, registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	var filterProjectID string
+	if filterPath != "" {
+		absPath, _ := filepath.Abs(filterPath)
+		p, err := registry.FindByPath(git.ResolveMainRepo(absPath))
+		if err != nil {
+			return fmt.Errorf("no project registered for %s", absPath)
+		}
+		filterProjectID = p.ID
+	}
+
+	period := opts.Period
+	if period == "" {
+		period = cfg.Digest.Period
+	}
+	var span time.Duration
+	switch period {
+	case "daily":
+		span = 24 * time.Hour
+	case "weekly":
+		span = 7 * 24 * time.Hour
+	default:
+		return fmt.Errorf("invalid period %q (want daily or weekly)", period)
+	}
+
+	names := make(map[string]string)
+	for _, p := range globalStore.Projects() {
+		names[p.ID] = p.Name
+	}
+
+	var tickets []*board.Ticket
+	for _, t := range globalStore.All() {
+		if filterProjectID == "" || t.ProjectID == filterProjectID {
+			tickets = append(tickets, t)
+		}
+	}
+
+	now := time.Now()
+	d := digest.Build(tickets, digest.Options{
+		From:         now.Add(-span),
+		To:           now,
+		StallAfter:   time.Duration(cfg.Digest.StallDays) * 24 * time.Hour,
+		ProjectNames: names,
+	})
+	if opts.SkipEmpty && d.Empty() {
+		return nil
+	}
+
+	var body strings.Builder
+	if err := d.WriteMarkdown(&body); err != nil {
+		return err
+	}
+
+	if opts.Output != "" {
+		if err := os.WriteFile(opts.Output, []byte(body.String()), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", opts.Output, err)
+		}
+	}
+	if opts.Send {
+		smtp := cfg.Digest.SMTP
+		return digest.Send(digest.SMTP{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			From:     smtp.From,
+			To:       smtp.To,
+		}, d.Subject(), body.String())
+	}
+	if opts.Output == "" {
+		fmt.Print(body.String())
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// KeysExport writes a Markdown cheat sheet of the configured keybindings,
+// and of the macros that have a key, to output or to stdout when it is ""
+func KeysExport(cfg *config.Config, output string) error {
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	title := "openkanban keybindings"
+	if preset := cfg.Keybindings.Preset; preset != "" && preset != "default" {
+		title += " (" + preset + ")"
+	}
+	if err := cfg.Keymap().WriteCheatSheet(w, title); err != nil {
+		return fmt.Errorf("failed to write cheat sheet: %w", err)
+	}
+
+	var rows []string
+	for name, macro := range cfg.Keybindings.Macros {
+		if macro.Key == "" {
+			continue
+		}
+		key := strings.ReplaceAll(keymap.DisplayKey(keymap.NormalizeKey(macro.Key)), "|", `\|`)
+		rows = append(rows, "| `"+key+"` | "+name+" |\n")
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	slices.Sort(rows)
+	if _, err := io.WriteString(w, "\n## Macros\n\n| Key | Macro |\n|-----|-------|\n"+strings.Join(rows, "")); err != nil {
+		return fmt.Errorf("failed to write cheat sheet: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// markerFileName is an optional file in a repository (or any parent
+// directory) naming the project and ticket to open, e.g.
+// {"project": "my-app", "ticket": "fix-login"}.
+const markerFileName = ".openkanban"
+
+// LaunchTarget selects what the board shows on startup.
+type LaunchTarget struct {
+	Project string // project name, ID, ID prefix, or repository path
+	Ticket  string // ticket ID, ID prefix, branch, or title
+}
+
+type markerFile struct {
+	Project string `json:"project"`
+	Ticket  string `json:"ticket"`
+}
+
+// findProject matches a project by name, ID, 8-char ID prefix, or path.
+func findProject(registry *project.ProjectRegistry, ref string) *project.Project {
+	if p := matchProject(registry, ref); p != nil {
+		return p
+	}
+
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		absPath, _ := filepath.Abs(ref)
+		if p, err := registry.FindByPath(git.ResolveMainRepo(absPath)); err == nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolveLaunchTarget returns the project to filter on and the ticket to
+// select. Explicit flags win; otherwise a .openkanban marker file or the
+// repository containing cwd is used.
+func resolveLaunchTarget(registry *project.ProjectRegistry, store *project.GlobalTicketStore, target LaunchTarget, cwd string) (string, board.TicketID, error) {
+	explicit := target.Project != "" || target.Ticket != ""
+	if !explicit && cwd != "" {
+		if marker, ok := readMarkerFile(cwd); ok {
+			target = LaunchTarget{Project: marker.Project, Ticket: marker.Ticket}
+		} else {
+			projectID, ticketID := detectFromPath(registry, store, cwd)
+			return projectID, ticketID, nil
+		}
+	}
+
+	var projectID string
+	if target.Project != "" {
+		p := findProject(registry, target.Project)
+		if p == nil {
+			return "", "", fmt.Errorf("project not found: %s", target.Project)
+		}
+		projectID = p.ID
+	}
+
+	var ticketID board.TicketID
+	if target.Ticket != "" {
+		t, err := store.Find(target.Ticket)
+		if err != nil {
+			return "", "", fmt.Errorf("ticket %s: %w", target.Ticket, err)
+		}
+		ticketID = t.ID
+		if projectID == "" {
+			projectID = t.ProjectID
+		}
+	}
+
+	return projectID, ticketID, nil
+}
+
+// readMarkerFile looks for a .openkanban file in dir or any parent.
+func readMarkerFile(dir string) (markerFile, bool) {
+	dir = filepath.Clean(dir)
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, markerFileName))
+		if err == nil {
+			var marker markerFile
+			if err := json.Unmarshal(data, &marker); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: ignoring invalid %s: %v\n", filepath.Join(dir, markerFileName), err)
+				return markerFile{}, false
+			}
+			return marker, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return markerFile{}, false
+		}
+		dir = parent
+	}
+}
+
+// detectFromPath matches cwd against registered repositories and ticket
+// worktrees. Unknown locations return empty results.
+func detectFromPath(registry *project.ProjectRegistry, store *project.GlobalTicketStore, cwd string) (string, board.TicketID) {
+	cwd = filepath.Clean(cwd)
+	for _, t := range store.All() {
+		if t.WorktreePath == "" {
+			continue
+		}
+		wt := filepath.Clean(t.WorktreePath)
+		if cwd == wt || strings.HasPrefix(cwd, wt+string(filepath.Separator)) {
+			return t.ProjectID, t.ID
+		}
+	}
+
+	root := git.FindRepoRoot(cwd)
+	if root == "" {
+		return "", ""
+	}
+	if p, err := registry.FindByPath(git.ResolveMainRepo(root)); err == nil {
+		return p.ID, ""
+	}
+	return "", ""
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/logging"
+)
+
+// logFollowInterval is how often `openkanban logs --follow` checks for
+// new lines
+const logFollowInterval = 500 * time.Millisecond
+
+// LogPath is the application log in the config directory
+func LogPath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return logging.Path(dir), nil
+}
+
+// StartLogging sends the default slog logger to the application log at the
+// configured level, or at debug level when debug is set. Close the returned
+// file on exit.
+func StartLogging(cfg *config.Config, debug bool) (io.Closer, error) {
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		level = slog.LevelDebug
+	}
+	path, err := LogPath()
+	if err != nil {
+		return nil, err
+	}
+	return logging.Setup(path, logging.Options{
+		Level:    level,
+		MaxSize:  int64(cfg.Logging.MaxSizeMB) << 20,
+		MaxFiles: cfg.Logging.MaxFiles,
+	})
+}
+
+// LogsOptions select what `openkanban logs` prints
+type LogsOptions struct {
+	Lines  int  // last lines to print; 0 prints the whole log
+	Follow bool // keep printing lines as they are written
+}
+
+// ShowLogs prints the end of the application log, then with Follow keeps
+// printing new lines until ctx is done.
+func ShowLogs(ctx context.Context, w io.Writer, opts LogsOptions) error {
+	path, err := LogPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine log path: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && !opts.Follow {
+		return fmt.Errorf("no log yet at %s", path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var offset int64
+	if f != nil {
+		lines, err := logging.Tail(f, opts.Lines)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		offset, _ = f.Seek(0, io.SeekCurrent)
+		f.Close()
+	}
+	if !opts.Follow {
+		return nil
+	}
+	return followLog(ctx, w, path, offset)
+}
+
+// followLog copies what is appended to path after offset, starting over
+// when the log is rotated.
+func followLog(ctx context.Context, w io.Writer, path string, offset int64) error {
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err == nil {
+			n, err := io.Copy(w, f)
+			offset += n
+			if err != nil {
+				f.Close()
+				return err
+			}
+		}
+		f.Close()
+	}
+}
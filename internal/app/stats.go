@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+	"github.com/techdufus/openkanban/internal/stats"
+)
+
+// Stats prints board statistics for tickets in [from, to). When filterPath is
+// set only the project containing that path is included.
+func Stats(cfg *config.Config, filterPath string, from, to time.Time, asJSON bool) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	globalStore, err := loadTickets(cfg, registry)
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	var filterProjectID string
+	if filterPath != "" {
+		absPath, _ := filepath.Abs(filterPath)
+		p, err := registry.FindByPath(git.ResolveMainRepo(absPath))
+		if err != nil {
+			return fmt.Errorf("no project registered for %s", absPath)
+		}
+		filterProjectID = p.ID
+	}
+
+	names := make(map[string]string)
+	for _, p := range globalStore.Projects() {
+		names[p.ID] = p.Name
+	}
+
+	costs := make(map[string]float64)
+	for name, agentCfg := range cfg.Agents {
+		costs[name] = agentCfg.CostPerHour
+	}
+
+	var tickets []*board.Ticket
+	for _, t := range globalStore.All() {
+		if filterProjectID == "" || t.ProjectID == filterProjectID {
+			tickets = append(tickets, t)
+		}
+	}
+
+	report := stats.Compute(tickets, stats.Options{
+		From:         from,
+		To:           to,
+		ProjectNames: names,
+		CostPerHour:  costs,
+	})
+
+	if asJSON {
+		return report.WriteJSON(os.Stdout)
+	}
+	if len(report.Projects) == 0 {
+		fmt.Println("No activity in this range.")
+		return nil
+	}
+	return report.WriteTable(os.Stdout)
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/project"
+	"github.com/techdufus/openkanban/internal/teamsync"
+)
+
+// Sync syncs the boards of every project, or only projectRef, with their
+// team branch once, whether or not sync.enabled is set
+func Sync(cfg *config.Config, projectRef string) error {
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load project registry: %w", err)
+	}
+	cipher := ticketCipher(cfg)
+	store, err := project.LoadGlobalTicketStoreWith(registry, project.EncryptedJSONRepository(cipher))
+	if err != nil {
+		return fmt.Errorf("failed to load tickets: %w", err)
+	}
+
+	projects := store.Projects()
+	if projectRef != "" {
+		p := findProject(registry, projectRef)
+		if p == nil {
+			return fmt.Errorf("project not found: %s", projectRef)
+		}
+		projects = []*project.Project{p}
+	}
+	project.SortProjects(projects, project.Order(cfg.UI.ProjectOrder))
+
+	var failed int
+	for _, p := range projects {
+		var tickets []*board.Ticket
+		for _, t := range store.All() {
+			if t.ProjectID == p.ID {
+				tickets = append(tickets, t)
+			}
+		}
+		shared, err := teamsync.ShareAll(tickets)
+		if err == nil {
+			var result *teamsync.Result
+			if result, err = teamsync.Sync(teamsync.ProjectOptions(cfg.Sync, p, cipher), shared); err == nil {
+				var changed int
+				changed, err = applySync(store, p.ID, result)
+				fmt.Printf("%s: %s\n", p.Name, syncSummary(result, changed))
+			}
+		}
+		if err != nil {
+			fmt.Printf("%s: failed: %v\n", p.Name, err)
+			failed++
+		}
+	}
+
+	if err := store.SaveAll(); err != nil {
+		return fmt.Errorf("failed to save tickets: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d project(s) failed to sync", failed)
+	}
+	return nil
+}
+
+// applySync makes the project's tickets match a synced board and returns
+// the number of tickets added, changed or deleted
+func applySync(store *project.GlobalTicketStore, projectID string, result *teamsync.Result) (int, error) {
+	changed := 0
+	for id, shared := range result.Tickets {
+		ticket, _ := store.Get(id)
+		if ticket != nil && (ticket.ProjectID != projectID || teamsync.Equal(ticket, shared)) {
+			continue
+		}
+		if ticket == nil {
+			ticket = &board.Ticket{}
+			if err := teamsync.Apply(ticket, shared); err != nil {
+				return changed, err
+			}
+			ticket.ProjectID = projectID
+			if err := store.Add(ticket); err != nil {
+				return changed, err
+			}
+		} else if err := teamsync.Apply(ticket, shared); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	for _, ticket := range store.All() {
+		if _, ok := result.Tickets[ticket.ID]; !ok && ticket.ProjectID == projectID {
+			store.RemoveBlockerReferences(ticket.ID)
+			store.Delete(ticket.ID)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func syncSummary(result *teamsync.Result, changed int) string {
+	switch {
+	case result.Pushed && changed > 0:
+		return fmt.Sprintf("pushed local changes, pulled %d change(s)", changed)
+	case result.Pushed:
+		return "pushed local changes"
+	case changed > 0:
+		return fmt.Sprintf("pulled %d change(s)", changed)
+	default:
+		return "up to date"
+	}
+}
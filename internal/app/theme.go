@@ -0,0 +1,19 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/ui"
+)
+
+// ThemePreview prints a sample board rendered with the named theme
+func ThemePreview(cfg *config.Config, name string, width, height int) error {
+	if !config.IsValidTheme(name) {
+		return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(config.ThemeNames(), ", "))
+	}
+
+	fmt.Println(ui.RenderPreview(cfg, config.GetTheme(name, nil), width, height))
+	return nil
+}
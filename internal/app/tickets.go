@@ -0,0 +1,77 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/crypt"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// PassphraseEnv holds the ticket passphrase for storage.encryption
+// "passphrase", so scripts and cron jobs need no prompt
+const PassphraseEnv = "OPENKANBAN_PASSPHRASE"
+
+// keychainAccount names the ticket key in the OS keychain
+const keychainAccount = "tickets"
+
+// ticketCipher is the cipher for storage.encryption. Whatever the setting,
+// it can still open files sealed the other ways, so switching settings
+// rewrites the files on the next start instead of locking them away.
+func ticketCipher(cfg *config.Config) *crypt.Cipher {
+	seal := crypt.KindNone
+	switch cfg.Storage.Encryption {
+	case "passphrase":
+		seal = crypt.KindPassphrase
+	case "keychain":
+		seal = crypt.KindKeychain
+	}
+	var canCreate func() error
+	if seal == crypt.KindKeychain {
+		canCreate = canCreateKeychainKey
+	}
+	return crypt.New(seal, askPassphrase, crypt.KeychainSecret(keychainAccount, canCreate))
+}
+
+// canCreateKeychainKey refuses a new ticket key while tickets are sealed
+// with the old one, which a new key would leave unreadable for good
+func canCreateKeychainKey() error {
+	files, err := project.SealedTicketFiles(crypt.KindKeychain)
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return fmt.Errorf("the ticket key is missing from the keychain, but %d ticket files are sealed with it: restore the key rather than have a new one created", len(files))
+	}
+	return nil
+}
+
+// askPassphrase reads the ticket passphrase from OPENKANBAN_PASSPHRASE, or
+// from the terminal without echo.
+func askPassphrase() ([]byte, error) {
+	if pass := os.Getenv(PassphraseEnv); pass != "" {
+		return []byte(pass), nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return nil, fmt.Errorf("tickets are encrypted: set %s or run from a terminal", PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Ticket passphrase: ")
+	pass, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	if len(pass) == 0 {
+		return nil, errors.New("no passphrase given")
+	}
+	return pass, nil
+}
+
+// loadTickets loads every project's tickets with the configured storage
+func loadTickets(cfg *config.Config, registry *project.ProjectRegistry) (*project.GlobalTicketStore, error) {
+	return project.LoadGlobalTicketStoreWith(registry, project.EncryptedJSONRepository(ticketCipher(cfg)))
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/vault"
+)
+
+// VaultExport writes every ticket as a note into the vault at path, or
+// vault.path when path is empty
+func VaultExport(cfg *config.Config, path string) error {
+	if path == "" {
+		path = cfg.Vault.Path
+	}
+	if path == "" {
+		return errors.New("no vault set; pass --vault or set vault.path")
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return fmt.Errorf("vault %s is not a directory", path)
+	}
+
+	store, err := loadStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	var tickets []vault.Ticket
+	for _, t := range store.All() {
+		entry := vault.Ticket{Ticket: t, Project: t.ProjectID}
+		if proj := store.GetProjectForTicket(t); proj != nil {
+			entry.Project = proj.Name
+			entry.Commits = branchCommits(git.NewWorktreeManager(proj), proj.RepoPath, t.BranchName, t.BaseBranch)
+		}
+		tickets = append(tickets, entry)
+	}
+
+	dir := filepath.Join(path, cfg.Vault.Folder)
+	n, err := vault.Write(dir, tickets)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d notes to %s\n", n, dir)
+	return nil
+}
+
+// branchCommits returns the commits on branch since base, or nothing once
+// the branch is gone
+func branchCommits(mgr *git.WorktreeManager, repo, branch, base string) []git.Commit {
+	if branch == "" || !mgr.BranchExists(branch) {
+		return nil
+	}
+	if base == "" {
+		base, _ = mgr.GetDefaultBranch()
+	}
+	if base == "" || base == branch {
+		return nil
+	}
+	commits, _ := git.Log(repo, base, branch)
+	return commits
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/editor"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// ticketWorktree is a git worktree belonging to a registered project,
+// together with the ticket that owns it, if any.
+type ticketWorktree struct {
+	git.TicketWorktree
+	project *project.Project
+	mgr     *git.WorktreeManager
+}
+
+func loadStore(cfg *config.Config) (*project.GlobalTicketStore, error) {
+	git.IsolateEnv()
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project registry: %w", err)
+	}
+
+	store, err := loadTickets(cfg, registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tickets: %w", err)
+	}
+	return store, nil
+}
+
+func collectWorktrees(store *project.GlobalTicketStore) []ticketWorktree {
+	tickets := store.All()
+
+	var result []ticketWorktree
+	for _, p := range store.Projects() {
+		mgr := git.NewWorktreeManager(p)
+		worktrees, err := mgr.TicketWorktrees(tickets)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", p.Name, err)
+			continue
+		}
+		for _, wt := range worktrees {
+			result = append(result, ticketWorktree{TicketWorktree: wt, project: p, mgr: mgr})
+		}
+	}
+	return result
+}
+
+// WorktreeList prints every ticket worktree with its branch, owner and size.
+func WorktreeList(cfg *config.Config) error {
+	store, err := loadStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	worktrees := collectWorktrees(store)
+	if len(worktrees) == 0 {
+		fmt.Println("No worktrees found.")
+		return nil
+	}
+
+	var total int64
+	var current *project.Project
+	for _, wt := range worktrees {
+		if wt.project != current {
+			if current != nil {
+				fmt.Println()
+			}
+			current = wt.project
+			fmt.Printf("%s (%s)\n", current.Name, current.RepoPath)
+		}
+
+		size := "missing"
+		if n, err := git.DiskUsage(wt.Path); err == nil {
+			size = git.FormatBytes(n)
+			total += n
+		}
+
+		owner := "(no ticket)"
+		if wt.Ticket != nil {
+			owner = fmt.Sprintf("%s  %s [%s]", shortID(string(wt.Ticket.ID)), wt.Ticket.Title, wt.Ticket.Status)
+		}
+		marker := " "
+		if wt.Prunable() {
+			marker = "*"
+		}
+
+		fmt.Printf(" %s %-10s %-30s %s\n", marker, size, wt.Branch, owner)
+		fmt.Printf("     %s\n", wt.Path)
+	}
+
+	fmt.Printf("\nTotal: %s. Worktrees marked * can be removed with: openkanban worktree prune\n", git.FormatBytes(total))
+	return nil
+}
+
+// WorktreePrune removes worktrees whose tickets are done, archived or deleted.
+// Worktrees with uncommitted changes are kept unless force is set. It refuses
+// while a board is running, since the board would save its own copy of the
+// tickets over the pruned ones.
+func WorktreePrune(cfg *config.Config, dryRun, force, deleteBranch bool) error {
+	if !dryRun && boardRunning() {
+		return errors.New("openkanban is running; quit it before pruning worktrees, or preview with --dry-run")
+	}
+	store, err := loadStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	removed, skipped := 0, 0
+	touched := make(map[string]*project.Project)
+	for _, wt := range collectWorktrees(store) {
+		if !wt.Prunable() {
+			continue
+		}
+
+		if !force {
+			if dirty, err := wt.mgr.HasUncommittedChanges(wt.Path); err == nil && dirty {
+				fmt.Printf("  skip   %s (uncommitted changes, use --force)\n", wt.Path)
+				skipped++
+				continue
+			}
+		}
+
+		if dryRun {
+			fmt.Printf("  would remove %s\n", wt.Path)
+			removed++
+			continue
+		}
+
+		if err := wt.mgr.RemoveWorktree(wt.Path); err != nil {
+			fmt.Printf("  failed %s: %v\n", wt.Path, err)
+			skipped++
+			continue
+		}
+		if deleteBranch && wt.Branch != "" {
+			if err := wt.mgr.DeleteBranch(wt.Branch); err != nil {
+				fmt.Printf("  kept branch %s: %v\n", wt.Branch, err)
+			}
+		}
+		if wt.Ticket != nil {
+			wt.Ticket.WorktreePath = ""
+			if err := store.Save(wt.Ticket); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to update ticket %s: %v\n", shortID(string(wt.Ticket.ID)), err)
+			}
+		}
+		touched[wt.project.ID] = wt.project
+		fmt.Printf("  removed %s\n", wt.Path)
+		removed++
+	}
+
+	for _, p := range touched {
+		if err := git.NewWorktreeManager(p).PruneMetadata(); err != nil {
+			slog.Warn("failed to prune worktree metadata", "project", p.Name, "err", err)
+		}
+	}
+
+	switch {
+	case removed == 0 && skipped == 0:
+		fmt.Println("Nothing to prune.")
+	case dryRun:
+		fmt.Printf("%d worktree(s) would be removed, %d skipped.\n", removed, skipped)
+	default:
+		fmt.Printf("%d worktree(s) removed, %d skipped.\n", removed, skipped)
+	}
+	return nil
+}
+
+// WorktreeOpen starts a shell in the ticket's worktree, or prints its path.
+func WorktreeOpen(cfg *config.Config, ref string, printOnly bool) error {
+	ticket, err := findWorktree(cfg, ref)
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fmt.Println(ticket.WorktreePath)
+		return nil
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	fmt.Printf("Entering %s (exit to return)\n", ticket.WorktreePath)
+	cmd := exec.Command(shell)
+	cmd.Dir = ticket.WorktreePath
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "OPENKANBAN_TICKET_ID="+string(ticket.ID))
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to start shell: %w", err)
+	}
+	return nil
+}
+
+// WorktreeEdit opens a ticket's worktree in the configured editor, waiting
+// for terminal editors to exit
+func WorktreeEdit(cfg *config.Config, ref string) error {
+	ticket, err := findWorktree(cfg, ref)
+	if err != nil {
+		return err
+	}
+	ed, err := editor.Resolve(cfg.Editor.Command)
+	if err != nil {
+		return err
+	}
+
+	target := ticket.WorktreePath
+	if cfg.Editor.Workspace && ed.SupportsWorkspace() {
+		if target, err = editor.WriteWorkspace(ticket.WorktreePath, ticket.Title); err != nil {
+			return err
+		}
+	}
+
+	cmd := ed.Command(target)
+	cmd.Dir = ticket.WorktreePath
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", ed.Args[0], err)
+	}
+	return nil
+}
+
+// findWorktree finds the ticket matching ref and checks its worktree exists
+func findWorktree(cfg *config.Config, ref string) (*board.Ticket, error) {
+	store, err := loadStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := store.Find(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	if ticket.WorktreePath == "" {
+		return nil, fmt.Errorf("ticket %q has no worktree yet; move it to In Progress first", ticket.Title)
+	}
+	if _, err := os.Stat(ticket.WorktreePath); err != nil {
+		return nil, fmt.Errorf("worktree for %q is missing: %s", ticket.Title, ticket.WorktreePath)
+	}
+	return ticket, nil
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+)
+
+func TestWorktreePrune_RefusesWhileRunning(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("OPENKANBAN_CONFIG_DIR", dir)
+	t.Setenv("OPENKANBAN_SOCKET", filepath.Join(dir, "openkanban.sock"))
+
+	path, _ := config.SocketPath()
+	srv, err := control.Listen(path, func(control.Request) control.Response {
+		return control.Response{OK: true}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve()
+	defer srv.Close()
+
+	err = WorktreePrune(config.DefaultConfig(), false, false, false)
+	if err == nil || !strings.Contains(err.Error(), "running") {
+		t.Errorf("prune with a board running: error = %v; want a refusal", err)
+	}
+}
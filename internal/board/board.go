@@ -1,22 +1,50 @@
 package board
 
 import (
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
 )
 
 var nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9-]+`)
 
+// transliterations spell out letters that don't decompose into a base
+// letter and accents
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'þ': "th", 'ł': "l", 'ı': "i", 'ħ': "h", 'ŧ': "t",
+}
+
+// transliterate spells accented Latin letters without their accents, so
+// "café" becomes "cafe"
+func transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if t, ok := transliterations[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func Slugify(s string, maxLen int) string {
 	if maxLen <= 0 {
 		maxLen = 40
 	}
 
-	slug := strings.ToLower(s)
+	slug := transliterate(strings.ToLower(s))
 
 	slug = strings.Map(func(r rune) rune {
 		if unicode.IsLetter(r) || unicode.IsDigit(r) {
@@ -40,17 +68,37 @@ func Slugify(s string, maxLen int) string {
 	return slug
 }
 
+// Unique returns name, or name suffixed -2, -3 and so on when taken reports
+// it is already in use
+func Unique(name string, taken func(string) bool) string {
+	candidate := name
+	for n := 2; taken(candidate); n++ {
+		candidate = name + "-" + strconv.Itoa(n)
+	}
+	return candidate
+}
+
 type TicketID string
 
 func NewTicketID() TicketID {
 	return TicketID(uuid.New().String())
 }
 
+// FormatKey returns the short key of ticket number in a project with the
+// key prefix, e.g. "OKB-142", or "" for a ticket not yet numbered
+func FormatKey(prefix string, number int) string {
+	if number <= 0 || prefix == "" {
+		return ""
+	}
+	return prefix + "-" + strconv.Itoa(number)
+}
+
 type TicketStatus string
 
 const (
 	StatusBacklog    TicketStatus = "backlog"
 	StatusInProgress TicketStatus = "in_progress"
+	StatusReview     TicketStatus = "review"
 	StatusDone       TicketStatus = "done"
 	StatusArchived   TicketStatus = "archived"
 )
@@ -66,23 +114,65 @@ const (
 	AgentError     AgentStatus = "error"
 )
 
+type AgentOutcome string
+
+const (
+	OutcomeRunning   AgentOutcome = "running"
+	OutcomeCompleted AgentOutcome = "completed"
+	OutcomeFailed    AgentOutcome = "failed"
+	OutcomeStopped   AgentOutcome = "stopped"
+)
+
+// AgentRun records a single agent session on a ticket.
+type AgentRun struct {
+	Agent     string       `json:"agent"`
+	StartedAt time.Time    `json:"started_at"`
+	EndedAt   *time.Time   `json:"ended_at,omitempty"`
+	Outcome   AgentOutcome `json:"outcome"`
+}
+
+// Duration returns how long the run lasted, or has lasted so far.
+func (r AgentRun) Duration() time.Duration {
+	if r.EndedAt == nil {
+		return time.Since(r.StartedAt)
+	}
+	return r.EndedAt.Sub(r.StartedAt)
+}
+
+// TestRun records the last run of the project's test command on a ticket.
+type TestRun struct {
+	Passed bool      `json:"passed"`
+	Pass   int       `json:"pass,omitempty"` // passing tests, when the output tells
+	Fail   int       `json:"fail,omitempty"` // failing tests, likewise
+	RanAt  time.Time `json:"ran_at"`
+}
+
 type Ticket struct {
 	ID          TicketID     `json:"id"`
+	Number      int          `json:"number,omitempty"` // per-project sequence behind the ticket's key, e.g. 142 in OKB-142
 	ProjectID   string       `json:"project_id"`
 	Title       string       `json:"title"`
 	Description string       `json:"description,omitempty"`
+	Notes       string       `json:"notes,omitempty"`    // working notes, editable while the agent runs
+	Feedback    string       `json:"feedback,omitempty"` // review feedback the next agent run is given
 	Status      TicketStatus `json:"status"`
 
 	UseWorktree  bool   `json:"use_worktree"`
 	WorktreePath string `json:"worktree_path,omitempty"`
 	BranchName   string `json:"branch_name,omitempty"`
 	BaseBranch   string `json:"base_branch,omitempty"`
+	BaseBehind   int    `json:"base_behind,omitempty"` // commits BaseBranch was behind its upstream when last checked
+	PRURL        string `json:"pr_url,omitempty"`      // pull request opened from the branch
+	Stash        string `json:"stash,omitempty"`       // stash commit holding changes set aside while the current agent runs
 
 	AgentType      string      `json:"agent_type,omitempty"`
+	Model          string      `json:"model,omitempty"` // overrides the agent's model
 	AgentStatus    AgentStatus `json:"agent_status"`
 	AgentSpawnedAt *time.Time  `json:"agent_spawned_at,omitempty"`
 	AgentPort      int         `json:"agent_port,omitempty"`
 	AgentSessionID string      `json:"agent_session_id,omitempty"`
+	AgentRuns      []AgentRun  `json:"agent_runs,omitempty"`
+	Tests          *TestRun    `json:"tests,omitempty"` // last test run; cleared when an agent starts
 
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
@@ -91,10 +181,16 @@ type Ticket struct {
 
 	Labels   []string          `json:"labels,omitempty"`
 	Priority int               `json:"priority,omitempty"`
+	Color    string            `json:"color,omitempty"`    // Theme color tagging the card, e.g. "error"
+	Position int               `json:"position,omitempty"` // Place in its column when tickets are ordered by hand; 0 if never arranged
 	Meta     map[string]string `json:"meta,omitempty"`
 
 	// Dependencies - tickets that block this one (informational only, no enforcement)
 	BlockedBy []TicketID `json:"blocked_by,omitempty"`
+
+	// Source is the ticket this one was split off from, such as the ticket
+	// whose agent listed it as a follow-up
+	Source TicketID `json:"source,omitempty"`
 }
 
 func NewTicket(title, projectID string) *Ticket {
@@ -131,6 +227,39 @@ func (t *Ticket) SetStatus(status TicketStatus) {
 	}
 }
 
+// Clone returns a deep copy of the ticket.
+func (t *Ticket) Clone() *Ticket {
+	c := *t
+	c.Labels = slices.Clone(t.Labels)
+	c.BlockedBy = slices.Clone(t.BlockedBy)
+	c.AgentRuns = slices.Clone(t.AgentRuns)
+	c.Meta = maps.Clone(t.Meta)
+	return &c
+}
+
+// StartAgentRun records the start of an agent session.
+func (t *Ticket) StartAgentRun(agent string) {
+	t.AgentRuns = append(t.AgentRuns, AgentRun{
+		Agent:     agent,
+		StartedAt: time.Now(),
+		Outcome:   OutcomeRunning,
+	})
+}
+
+// EndAgentRun closes the most recent agent session, if one is still open.
+func (t *Ticket) EndAgentRun(outcome AgentOutcome) {
+	if len(t.AgentRuns) == 0 {
+		return
+	}
+	run := &t.AgentRuns[len(t.AgentRuns)-1]
+	if run.EndedAt != nil {
+		return
+	}
+	now := time.Now()
+	run.EndedAt = &now
+	run.Outcome = outcome
+}
+
 type Column struct {
 	ID     string       `json:"id"`
 	Name   string       `json:"name"`
@@ -147,6 +276,17 @@ func DefaultColumns() []Column {
 	}
 }
 
+// Columns returns the default columns, with a Review column before Done
+// when review is true
+func Columns(review bool) []Column {
+	columns := DefaultColumns()
+	if !review {
+		return columns
+	}
+	reviewColumn := Column{ID: "review", Name: "Review", Status: StatusReview, Color: "#cba6f7", Limit: 0}
+	return slices.Insert(columns, len(columns)-1, reviewColumn)
+}
+
 var (
 	ErrTicketNotFound = &BoardError{Message: "ticket not found"}
 )
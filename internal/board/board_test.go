@@ -1,6 +1,7 @@
 package board
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -73,10 +74,22 @@ func TestSlugify(t *testing.T) {
 			expected: "test",
 		},
 		{
-			name:     "unicode characters",
+			name:     "accents transliterated",
 			input:    "café résumé",
 			maxLen:   40,
-			expected: "caf-r-sum",
+			expected: "cafe-resume",
+		},
+		{
+			name:     "letters without accents transliterated",
+			input:    "Straße Ærø Łódź",
+			maxLen:   40,
+			expected: "strasse-aero-lodz",
+		},
+		{
+			name:     "non-latin scripts dropped",
+			input:    "fix 日本語 login",
+			maxLen:   40,
+			expected: "fix-login",
 		},
 		{
 			name:     "empty string",
@@ -102,6 +115,18 @@ func TestSlugify(t *testing.T) {
 	}
 }
 
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"fix-login": true, "fix-login-2": true}
+	isTaken := func(name string) bool { return taken[name] }
+
+	if got := Unique("fix-login", isTaken); got != "fix-login-3" {
+		t.Errorf("Unique(taken) = %q; want %q", got, "fix-login-3")
+	}
+	if got := Unique("add-search", isTaken); got != "add-search" {
+		t.Errorf("Unique(free) = %q; want %q", got, "add-search")
+	}
+}
+
 func TestNewTicketID(t *testing.T) {
 	id1 := NewTicketID()
 	id2 := NewTicketID()
@@ -256,6 +281,51 @@ func TestTicket_SetStatus(t *testing.T) {
 	})
 }
 
+func TestTicket_AgentRuns(t *testing.T) {
+	ticket := NewTicket("Test", "project-1")
+
+	ticket.EndAgentRun(OutcomeCompleted)
+	if len(ticket.AgentRuns) != 0 {
+		t.Fatalf("EndAgentRun without runs should be a no-op; got %d runs", len(ticket.AgentRuns))
+	}
+
+	ticket.StartAgentRun("claude")
+	if got := ticket.AgentRuns[0].Outcome; got != OutcomeRunning {
+		t.Errorf("new run outcome = %q; want %q", got, OutcomeRunning)
+	}
+
+	ticket.EndAgentRun(OutcomeFailed)
+	run := ticket.AgentRuns[0]
+	if run.EndedAt == nil || run.Outcome != OutcomeFailed {
+		t.Errorf("ended run = %+v; want outcome %q with EndedAt set", run, OutcomeFailed)
+	}
+
+	ticket.EndAgentRun(OutcomeStopped)
+	if got := ticket.AgentRuns[0].Outcome; got != OutcomeFailed {
+		t.Errorf("ending a closed run changed outcome to %q", got)
+	}
+}
+
+func TestTicket_Clone(t *testing.T) {
+	ticket := NewTicket("Original", "project-1")
+	ticket.Labels = []string{"a"}
+	ticket.Meta["k"] = "v"
+	ticket.StartAgentRun("claude")
+
+	c := ticket.Clone()
+	c.Title = "Copy"
+	c.Labels[0] = "b"
+	c.Meta["k"] = "changed"
+	c.EndAgentRun(OutcomeCompleted)
+
+	if ticket.Title != "Original" || ticket.Labels[0] != "a" || ticket.Meta["k"] != "v" {
+		t.Errorf("modifying clone changed original: %+v", ticket)
+	}
+	if ticket.AgentRuns[0].EndedAt != nil {
+		t.Error("ending clone's agent run changed original")
+	}
+}
+
 func TestDefaultColumns(t *testing.T) {
 	columns := DefaultColumns()
 
@@ -289,6 +359,21 @@ func TestDefaultColumns(t *testing.T) {
 	}
 }
 
+func TestColumns_Review(t *testing.T) {
+	if got := Columns(false); len(got) != 3 {
+		t.Errorf("Columns(false) returned %d columns; want 3", len(got))
+	}
+
+	var statuses []TicketStatus
+	for _, col := range Columns(true) {
+		statuses = append(statuses, col.Status)
+	}
+	want := []TicketStatus{StatusBacklog, StatusInProgress, StatusReview, StatusDone}
+	if !slices.Equal(statuses, want) {
+		t.Errorf("Columns(true) statuses = %v; want %v", statuses, want)
+	}
+}
+
 func TestBoardError(t *testing.T) {
 	err := &BoardError{Message: "test error"}
 
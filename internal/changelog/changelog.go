@@ -0,0 +1,167 @@
+// Package changelog groups the commits of completed tickets by
+// conventional-commit type into Markdown release notes.
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Change is one commit, parsed as a conventional commit
+type Change struct {
+	Type        string // lowercased, e.g. "feat"; "" when the subject is not conventional
+	Scope       string
+	Description string
+	Breaking    bool
+	Hash        string // short hash; "" when the ticket title stands in for its commits
+}
+
+// Ticket is a completed ticket and the changes on its branch
+type Ticket struct {
+	Title   string
+	Changes []Change
+}
+
+// groups are the sections of a changelog, in order. Types not listed go
+// under "Other".
+var groups = []struct {
+	title string
+	types []string
+}{
+	{"Features", []string{"feat", "feature"}},
+	{"Bug Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Refactoring", []string{"refactor"}},
+	{"Documentation", []string{"docs"}},
+	{"Tests", []string{"test", "tests"}},
+	{"Build and CI", []string{"build", "ci"}},
+	{"Chores", []string{"chore", "style", "revert"}},
+}
+
+const (
+	breakingTitle = "Breaking Changes"
+	otherTitle    = "Other"
+)
+
+var conventionalPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// Parse reads a commit as "type(scope)!: description". A "BREAKING CHANGE:"
+// footer in the body also marks it breaking. Other subjects are kept whole
+// with an empty type.
+func Parse(subject, body string) Change {
+	subject = strings.TrimSpace(subject)
+	m := conventionalPattern.FindStringSubmatch(subject)
+	if m == nil {
+		return Change{Description: subject}
+	}
+	return Change{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Description: m[4],
+		Breaking: m[3] != "" ||
+			strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:"),
+	}
+}
+
+// section returns the changelog section a change is listed under
+func (c Change) section() string {
+	if c.Breaking {
+		return breakingTitle
+	}
+	for _, g := range groups {
+		for _, t := range g.types {
+			if c.Type == t {
+				return g.title
+			}
+		}
+	}
+	return otherTitle
+}
+
+// sectionOrder lists every section title in the order they are written
+func sectionOrder() []string {
+	order := []string{breakingTitle}
+	for _, g := range groups {
+		order = append(order, g.title)
+	}
+	return append(order, otherTitle)
+}
+
+type entry struct {
+	change Change
+	ticket string
+}
+
+// Write renders the changelog as Markdown under a "## title" heading. With
+// byTicket, each ticket gets its own heading with sections below it;
+// otherwise all changes are grouped together and credited to their ticket.
+func Write(w io.Writer, title string, tickets []Ticket, byTicket bool) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", title)
+
+	if byTicket {
+		for _, t := range tickets {
+			if len(t.Changes) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n### %s\n", t.Title)
+			writeSections(&b, "####", entries([]Ticket{t}), false)
+		}
+	} else {
+		writeSections(&b, "###", entries(tickets), true)
+	}
+
+	if !strings.Contains(b.String(), "\n- ") {
+		b.WriteString("\nNo changes.\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func entries(tickets []Ticket) []entry {
+	var all []entry
+	for _, t := range tickets {
+		for _, c := range t.Changes {
+			all = append(all, entry{c, t.Title})
+		}
+	}
+	return all
+}
+
+func writeSections(b *strings.Builder, heading string, all []entry, credit bool) {
+	bySection := make(map[string][]entry)
+	for _, e := range all {
+		s := e.change.section()
+		bySection[s] = append(bySection[s], e)
+	}
+	for _, s := range sectionOrder() {
+		if len(bySection[s]) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\n%s %s\n\n", heading, s)
+		for _, e := range bySection[s] {
+			b.WriteString("- " + formatEntry(e, credit) + "\n")
+		}
+	}
+}
+
+func formatEntry(e entry, credit bool) string {
+	line := e.change.Description
+	if e.change.Scope != "" {
+		line = "**" + e.change.Scope + ":** " + line
+	}
+	var refs []string
+	if e.change.Hash != "" {
+		refs = append(refs, e.change.Hash)
+	}
+	// A change without a hash is the ticket itself
+	if credit && e.change.Hash != "" {
+		refs = append(refs, e.ticket)
+	}
+	if len(refs) > 0 {
+		line += " (" + strings.Join(refs, ", ") + ")"
+	}
+	return line
+}
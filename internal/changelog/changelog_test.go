@@ -0,0 +1,100 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		want          Change
+	}{
+		{"feat(ui): add editor action", "", Change{Type: "feat", Scope: "ui", Description: "add editor action"}},
+		{"Fix: handle empty config", "", Change{Type: "fix", Description: "handle empty config"}},
+		{"refactor!: drop v1 tickets", "", Change{Type: "refactor", Description: "drop v1 tickets", Breaking: true}},
+		{"feat: new format", "BREAKING CHANGE: old files are not read", Change{Type: "feat", Description: "new format", Breaking: true}},
+		{"Update README", "", Change{Description: "Update README"}},
+		{"Merge branch 'main': conflicts", "", Change{Description: "Merge branch 'main': conflicts"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.subject, tt.body); got != tt.want {
+			t.Errorf("Parse(%q) = %+v; want %+v", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tickets := []Ticket{
+		{Title: "Fix login", Changes: []Change{
+			{Type: "fix", Scope: "auth", Description: "check token expiry", Hash: "a1b2c3d"},
+			{Type: "test", Description: "cover expired tokens", Hash: "b2c3d4e"},
+		}},
+		{Title: "New settings", Changes: []Change{
+			{Type: "feat", Description: "settings page", Hash: "c3d4e5f", Breaking: true},
+			{Description: "Tidy up", Hash: "d4e5f6a"},
+		}},
+		{Title: "docs: Write a guide", Changes: []Change{
+			{Type: "docs", Description: "Write a guide"},
+		}},
+		{Title: "Empty"},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, "Unreleased", tickets, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `## Unreleased
+
+### Breaking Changes
+
+- settings page (c3d4e5f, New settings)
+
+### Bug Fixes
+
+- **auth:** check token expiry (a1b2c3d, Fix login)
+
+### Documentation
+
+- Write a guide
+
+### Tests
+
+- cover expired tokens (b2c3d4e, Fix login)
+
+### Other
+
+- Tidy up (d4e5f6a, New settings)
+`
+	if b.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Write(&b, "v1.2", tickets[:1], true); err != nil {
+		t.Fatal(err)
+	}
+	want = `## v1.2
+
+### Fix login
+
+#### Bug Fixes
+
+- **auth:** check token expiry (a1b2c3d)
+
+#### Tests
+
+- cover expired tokens (b2c3d4e)
+`
+	if b.String() != want {
+		t.Errorf("Write() by ticket =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Write(&b, "Unreleased", tickets[3:], false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(b.String(), "\nNo changes.\n") {
+		t.Errorf("Write() without changes = %q", b.String())
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/techdufus/openkanban/internal/events"
 )
 
 const defaultGlobalPrompt = `You have been spawned by OpenKanban to work on a ticket.
@@ -158,13 +160,65 @@ func DetectAvailableAgent(agents map[string]AgentConfig) string {
 
 // Config holds the global application configuration
 type Config struct {
+	// Version is the schema version of the config file; see CurrentVersion
+	Version int `json:"version"`
+
 	Defaults BoardSettings          `json:"defaults"`
 	Agents   map[string]AgentConfig `json:"agents"`
 	UI       UIConfig               `json:"ui"`
 	Cleanup  CleanupSettings        `json:"cleanup"`
 	Behavior BehaviorSettings       `json:"behavior"`
 	Opencode OpencodeSettings       `json:"opencode"`
-	Keys     map[string]string      `json:"keys,omitempty"`
+
+	// PullRequest controls the Create PR action
+	PullRequest PullRequestSettings `json:"pull_request"`
+
+	// Jira imports issues as tickets and transitions them as tickets move
+	Jira JiraSettings `json:"jira"`
+
+	// Linear imports team issues as tickets, moves them as tickets move and
+	// links agent branches and pull requests back to them
+	Linear LinearSettings `json:"linear"`
+
+	// Keybindings selects a key preset and per-action overrides
+	Keybindings KeybindingsConfig `json:"keybindings"`
+
+	// Hooks maps event names (e.g. "ticket.moved") to shell commands that
+	// receive the event as JSON on stdin
+	Hooks map[string][]string `json:"hooks,omitempty"`
+
+	// CommitRefs moves and annotates tickets referenced in commit messages
+	CommitRefs CommitRefSettings `json:"commit_refs"`
+
+	// Editor opens ticket worktrees in an editor or IDE
+	Editor EditorSettings `json:"editor"`
+
+	// Tmux runs agents in tmux windows instead of the embedded terminal
+	Tmux TmuxSettings `json:"tmux"`
+
+	// Notifications announces board events on the desktop and in chat
+	Notifications NotificationSettings `json:"notifications"`
+
+	// Vault is where `openkanban vault export` writes ticket notes
+	Vault VaultSettings `json:"vault"`
+
+	// Digest summarises board activity for `openkanban digest`
+	Digest DigestSettings `json:"digest"`
+
+	// Telemetry exports trace spans to an OpenTelemetry collector
+	Telemetry TelemetrySettings `json:"telemetry"`
+
+	// Logging controls the application log under logs/ in the config directory
+	Logging LoggingSettings `json:"logging"`
+
+	// Storage controls how ticket files are kept on disk
+	Storage StorageSettings `json:"storage"`
+
+	// Sync shares each project's board with a team through a git branch
+	Sync SyncSettings `json:"sync"`
+
+	// envOverrides records keys set from OPENKANBAN_* environment variables
+	envOverrides map[string]EnvOverride
 }
 
 // OpencodeSettings controls OpenCode server integration
@@ -186,8 +240,22 @@ type BoardSettings struct {
 	BranchTemplate   string `json:"branch_template"` // e.g., "{prefix}{slug}"
 	SlugMaxLength    int    `json:"slug_max_length"` // default: 40
 	InitPrompt       string `json:"init_prompt"`
+	ReviewPrompt     bool   `json:"review_prompt"` // Open the rendered init prompt in $EDITOR before it is sent
+
+	// SpawnOn lists the columns ("in_progress", "review") that spawn the
+	// ticket's agent when a ticket is moved into them, with auto_spawn_agent on
+	SpawnOn []string `json:"spawn_on"`
 }
 
+// Ways an agent receives its init prompt
+const (
+	ContextInputArgs = "args"
+	ContextInputType = "type"
+)
+
+// DefaultReadyTimeout is how long a typed init prompt waits for the agent
+const DefaultReadyTimeout = 30
+
 // AgentConfig defines how to spawn and monitor an AI agent
 type AgentConfig struct {
 	Command    string            `json:"command"`
@@ -195,6 +263,53 @@ type AgentConfig struct {
 	Env        map[string]string `json:"env"`
 	StatusFile string            `json:"status_file"`
 	InitPrompt string            `json:"init_prompt"`
+
+	// ContextFile, when set, has the init prompt written to this file in the
+	// worktree rather than passed on the command line
+	ContextFile string `json:"context_file,omitempty"`
+	// ContextArgs hand ContextFile to the agent, with {file} replaced by its
+	// path; without them the agent's prompt asks it to read the file
+	ContextArgs []string `json:"context_args,omitempty"`
+
+	// ContextInput "type" types the init prompt into the agent once it is
+	// ready, for agents that can't take one on the command line; "args",
+	// the default, passes it when the agent starts
+	ContextInput string `json:"context_input,omitempty"`
+	ReadyPattern string `json:"ready_pattern,omitempty"` // Regexp the agent's screen matches once it takes input; empty waits for its output to settle
+	ReadyTimeout int    `json:"ready_timeout,omitempty"` // Seconds to wait for the agent to be ready (default 30)
+
+	// SpawnArgs and ResumeArgs replace the built-in arguments for a new and
+	// a resumed session, after Args. They may use {prompt}, {worktree},
+	// {model}, {port}, {branch} and {ticket}.
+	SpawnArgs  []string `json:"spawn_args,omitempty"`
+	ResumeArgs []string `json:"resume_args,omitempty"`
+
+	// Model is the model the agent runs unless a ticket picks one of Models.
+	// It is passed through ModelArgs, with {model} replaced, and the
+	// ModelEnv variable, and fills {model} in SpawnArgs and ResumeArgs.
+	Model     string        `json:"model,omitempty"`
+	Models    []ModelOption `json:"models,omitempty"`
+	ModelArgs []string      `json:"model_args,omitempty"` // e.g. ["--model", "{model}"]; unused with SpawnArgs
+	ModelEnv  string        `json:"model_env,omitempty"`  // Environment variable set to the model
+
+	// CostPerHour is used by `openkanban stats` to estimate agent cost
+	CostPerHour float64 `json:"cost_per_hour,omitempty"`
+}
+
+// ModelOption is a model offered for an agent in the ticket form
+type ModelOption struct {
+	Name string `json:"name"`
+	Tier string `json:"tier,omitempty"` // Cost hint shown beside it, e.g. "$$$"
+}
+
+// ModelTier returns the cost hint of one of the agent's models
+func (a AgentConfig) ModelTier(name string) string {
+	for _, m := range a.Models {
+		if m.Name == name {
+			return m.Tier
+		}
+	}
+	return ""
 }
 
 // UIConfig holds UI-related preferences
@@ -202,23 +317,209 @@ type UIConfig struct {
 	Theme           string       `json:"theme"`
 	CustomColors    *ThemeColors `json:"custom_colors,omitempty"`
 	ShowAgentStatus bool         `json:"show_agent_status"`
+	ShowGitStatus   bool         `json:"show_git_status"`
 	RefreshInterval int          `json:"refresh_interval"`
 	ColumnWidth     int          `json:"column_width"`
 	TicketHeight    int          `json:"ticket_height"`
 	SidebarVisible  bool         `json:"sidebar_visible"`
 	ScrollbackLines int          `json:"scrollback_lines"`
+	Split           bool         `json:"split"`            // Keep the board visible beside the agent pane
+	SplitDirection  string       `json:"split_direction"`  // "right" | "bottom": where the agent pane goes in split view
+	SplitRatio      int          `json:"split_ratio"`      // Percentage of the screen given to the agent pane
+	Tabs            bool         `json:"tabs"`             // Show a header tab per project
+	GroupByProject  bool         `json:"group_by_project"` // Order each column by project when showing several
+	TicketOrder     string       `json:"ticket_order"`     // "created" | "updated" | "name" | "manual": order of tickets in a column
+	ProjectOrder    string       `json:"project_order"`    // "name" | "created": order of projects in the sidebar, tabs and lists
+	CardDensity     string       `json:"card_density"`     // "compact" | "normal" | "detailed": how much each ticket card shows
+	OutputPreview   bool         `json:"output_preview"`   // Show the selected card's agent output beside the board
+	FileFeed        bool         `json:"file_feed"`        // Watch the files agents change and list them beside the board
+
+	SymbolicIndicators bool `json:"symbolic_indicators"` // Mark working agents with a static glyph instead of the spinner
+
+	// ColumnColors colors a column's header and selected card, keyed by
+	// status (backlog, in_progress, done, archived). Values are theme color
+	// names such as "info" or hex colors.
+	ColumnColors map[string]string `json:"column_colors,omitempty"`
+
+	// Highlights style what their patterns match in agent panes, over the
+	// agent's own colors, e.g. errors in red
+	Highlights []HighlightRule `json:"highlights,omitempty"`
+}
+
+// HighlightRule styles the text a regular expression matches in agent output
+type HighlightRule struct {
+	Pattern   string `json:"pattern"`
+	Color     string `json:"color,omitempty"` // theme color name such as "error", or a hex color
+	Bold      bool   `json:"bold,omitempty"`
+	Underline bool   `json:"underline,omitempty"`
 }
 
 // CleanupSettings controls cleanup behavior when deleting tickets
 type CleanupSettings struct {
-	DeleteWorktree       bool `json:"delete_worktree"`        // Remove git worktree on ticket delete
-	DeleteBranch         bool `json:"delete_branch"`          // Delete git branch after worktree removal
-	ForceWorktreeRemoval bool `json:"force_worktree_removal"` // Force removal even with uncommitted changes
+	DeleteWorktree       bool   `json:"delete_worktree"`        // Remove git worktree on ticket delete
+	DeleteBranch         bool   `json:"delete_branch"`          // Delete git branch after worktree removal
+	ForceWorktreeRemoval bool   `json:"force_worktree_removal"` // Force removal even with uncommitted changes
+	AfterMerge           string `json:"after_merge"`            // "ask" | "always" | "never": remove worktree and branch once merged
+	OnArchive            string `json:"on_archive"`             // "ask" | "always" | "never": remove worktree (and branch, with delete_branch) on archive
+	DiskLimitGB          int    `json:"disk_limit_gb"`          // Warn when worktrees use more than this many GiB; 0 disables
+}
+
+// PullRequestSettings controls how pull requests are opened from tickets
+type PullRequestSettings struct {
+	Remote         string `json:"remote"`           // Remote the ticket branch is pushed to; empty means origin
+	Provider       string `json:"provider"`         // "auto" | "github" | "gitlab" | "gitea"; auto detects from the remote URL
+	Draft          bool   `json:"draft"`            // Open pull requests as drafts
+	IncludeCommits bool   `json:"include_commits"`  // List the branch's commit messages in the body
+	PushOnComplete bool   `json:"push_on_complete"` // Push the ticket branch when its agent completes
+	StatusInterval int    `json:"status_interval"`  // Seconds between checks of linked pull requests' reviews and CI; 0 disables
+}
+
+// JiraSettings connects the board to a Jira instance. Each project picks the
+// issues it imports with jira_jql in its settings; the API token comes from
+// JIRA_API_TOKEN.
+type JiraSettings struct {
+	URL          string            `json:"url"`                   // e.g. https://acme.atlassian.net; empty disables Jira
+	Email        string            `json:"email"`                 // Jira Cloud account; empty sends the token as a Data Center bearer token
+	SyncInterval int               `json:"sync_interval"`         // Minutes between imports; 0 disables them
+	Transitions  map[string]string `json:"transitions,omitempty"` // Column ("backlog", "in_progress", "done", "archived") to the Jira status to move issues to
+}
+
+// LinearSettings controls syncing with Linear. Each project connects to a team
+// with linear_team_id in its settings, authenticating with its
+// linear_api_key or LINEAR_API_KEY.
+type LinearSettings struct {
+	SyncInterval int               `json:"sync_interval"`    // Minutes between imports; 0 disables them
+	States       map[string]string `json:"states,omitempty"` // Column ("backlog", "in_progress", "done", "archived") to the workflow state to move issues to
+	LinkBranch   bool              `json:"link_branch"`      // Comment the agent's branch on the issue when the agent starts
+	LinkPR       bool              `json:"link_pr"`          // Attach pull requests to the issue when they are opened
+}
+
+// CommitRefSettings controls how commit messages drive the board. A commit
+// mentioning "okb:<ticket>" is noted on the ticket, and "okb:<ticket> done"
+// or "Fixes okb:<ticket>" moves it to Done.
+type CommitRefSettings struct {
+	Enabled  bool   `json:"enabled"`
+	Prefix   string `json:"prefix"`    // Marks ticket references, e.g. "okb" for okb:fix-login
+	ScanBase bool   `json:"scan_base"` // Also read recent commits on each project's default branch
+}
+
+// EditorSettings chooses the editor worktrees are opened in
+type EditorSettings struct {
+	Command   string `json:"command"`   // e.g. "code", "zed" or "nvim"; empty uses $VISUAL or $EDITOR
+	Workspace bool   `json:"workspace"` // Open VS Code style editors on a workspace file generated per worktree
+}
+
+// TmuxSettings runs each agent in a window of its project's tmux session.
+// The windows outlive openkanban and are picked up again on the next start.
+type TmuxSettings struct {
+	Enabled       bool   `json:"enabled"`
+	SessionPrefix string `json:"session_prefix"` // Prepended to the project name to name its session
+}
+
+// VaultSettings places exported notes in an Obsidian (or other Markdown)
+// vault
+type VaultSettings struct {
+	Path   string `json:"path"`   // Vault directory; ~ is expanded
+	Folder string `json:"folder"` // Folder inside the vault for openkanban's notes
+}
+
+// DigestSettings configures the activity digest. Mail is sent over SMTP with
+// the password in OPENKANBAN_SMTP_PASSWORD.
+type DigestSettings struct {
+	Period    string       `json:"period"`     // "daily" or "weekly"
+	StallDays int          `json:"stall_days"` // Days without activity before an in-progress ticket counts as stalled; 0 disables
+	SMTP      SMTPSettings `json:"smtp"`
+}
+
+// SMTPSettings is the mail server digests are sent through
+type SMTPSettings struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"` // 465 uses implicit TLS; 0 means 587 with STARTTLS
+	Username string   `json:"username"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// TelemetrySettings exports spans for agent sessions, git operations and
+// control requests over OTLP/HTTP. Changes apply on restart.
+type TelemetrySettings struct {
+	Enabled     bool              `json:"enabled"`
+	Endpoint    string            `json:"endpoint"`          // Collector base URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318
+	ServiceName string            `json:"service_name"`      // service.name of the exported spans
+	Headers     map[string]string `json:"headers,omitempty"` // Sent with every export, e.g. an API key
+}
+
+// StorageSettings controls the ticket files in the config directory.
+// Changes apply on restart.
+type StorageSettings struct {
+	// Encryption is "none", "passphrase" (read from OPENKANBAN_PASSPHRASE
+	// or asked for on start) or "keychain" (a random key kept in the OS
+	// keychain)
+	Encryption string `json:"encryption"`
+}
+
+// SyncSettings shares boards through a branch pushed to a git remote.
+// Changes apply on restart.
+type SyncSettings struct {
+	Enabled  bool   `json:"enabled"`
+	Remote   string `json:"remote"`   // Remote to pull from and push to
+	Branch   string `json:"branch"`   // Branch holding the board
+	Repo     string `json:"repo"`     // Repository keeping every project's board; empty uses each project's own
+	Interval int    `json:"interval"` // Seconds between syncs while the board runs; 0 syncs only on start
+}
+
+// LoggingSettings controls the application log. Changes apply on restart.
+type LoggingSettings struct {
+	Level     string `json:"level"`       // "debug" | "info" | "warn" | "error"
+	MaxSizeMB int    `json:"max_size_mb"` // Rotate app.log once it passes this size; 0 never rotates
+	MaxFiles  int    `json:"max_files"`   // Rotated logs to keep
+}
+
+// NotificationSettings controls where board events are announced
+type NotificationSettings struct {
+	// Webhooks post events to Slack or Discord channels; list one per
+	// channel and pick its projects to route projects to their channels
+	Webhooks []events.Webhook `json:"webhooks,omitempty"`
+
+	Desktop    DesktopNotifySettings `json:"desktop"`
+	QuietHours QuietHours            `json:"quiet_hours"`
+
+	// QuietSchedules are further quiet windows, e.g. weekends all day
+	QuietSchedules []QuietHours `json:"quiet_schedules,omitempty"`
+
+	// Muted holds webhook and desktop notifications back until unmuted;
+	// they are still kept in the notification history
+	Muted bool `json:"muted"`
+}
+
+// DesktopNotifySettings chooses which events raise a native desktop
+// notification
+type DesktopNotifySettings struct {
+	Enabled        bool `json:"enabled"`
+	AgentWaiting   bool `json:"agent_waiting"`   // An agent is waiting for input
+	AgentCompleted bool `json:"agent_completed"` // An agent finished
+	AgentFailed    bool `json:"agent_failed"`    // An agent failed
+	Errors         bool `json:"errors"`          // Any other failure shown in the status bar
 }
 
 // BehaviorSettings controls application behavior preferences
 type BehaviorSettings struct {
-	ConfirmQuitWithAgents bool `json:"confirm_quit_with_agents"` // Prompt before quitting with running agents
+	ConfirmQuitWithAgents bool   `json:"confirm_quit_with_agents"` // Prompt before quitting with running agents
+	SyncStrategy          string `json:"sync_strategy"`            // "rebase" | "merge": how syncing brings a ticket branch up to date with base
+	ReadOnly              bool   `json:"read_only"`                // Browse only: no ticket, agent or git changes
+	AutosaveInterval      int    `json:"autosave_interval"`        // Seconds ticket changes wait to be written; 0 writes each change at once
+	Review                bool   `json:"review"`                   // Tickets whose agent finishes move to a Review column to be approved or rejected
+	OnApprove             string `json:"on_approve"`               // "merge" | "pr" | "none": what approving a reviewed ticket starts
+
+	Confirm ConfirmSettings `json:"confirm"`
+}
+
+// ConfirmSettings chooses which destructive actions ask first
+type ConfirmSettings struct {
+	DeleteTicket  bool `json:"delete_ticket"`  // Ask before deleting a ticket
+	StopAgent     bool `json:"stop_agent"`     // Ask before stopping an agent
+	PruneWorktree bool `json:"prune_worktree"` // Ask before removing a worktree from the worktrees screen
+	TypeToDelete  bool `json:"type_to_delete"` // Deleting a ticket that also removes its worktree asks for the ticket's title
 }
 
 func defaultAgents() map[string]AgentConfig {
@@ -229,6 +530,12 @@ func defaultAgents() map[string]AgentConfig {
 			Env:        map[string]string{},
 			StatusFile: ".claude/status.json",
 			InitPrompt: defaultClaudePrompt,
+			Models: []ModelOption{
+				{Name: "haiku", Tier: "$"},
+				{Name: "sonnet", Tier: "$$"},
+				{Name: "opus", Tier: "$$$"},
+			},
+			ModelArgs: []string{"--model", "{model}"},
 		},
 		"opencode": {
 			Command:    "opencode",
@@ -236,13 +543,21 @@ func defaultAgents() map[string]AgentConfig {
 			Env:        map[string]string{},
 			StatusFile: ".opencode/status.json",
 			InitPrompt: defaultOpencodePrompt,
+			ModelArgs:  []string{"--model", "{model}"},
 		},
 		"aider": {
-			Command:    "aider",
-			Args:       []string{"--yes"},
-			Env:        map[string]string{},
-			StatusFile: "",
-			InitPrompt: defaultAiderPrompt,
+			Command:      "aider",
+			Args:         []string{"--yes"},
+			Env:          map[string]string{},
+			StatusFile:   "",
+			InitPrompt:   defaultAiderPrompt,
+			ContextInput: ContextInputType,
+			Models: []ModelOption{
+				{Name: "gpt-4o", Tier: "$$"},
+				{Name: "sonnet", Tier: "$$"},
+				{Name: "o3", Tier: "$$$"},
+			},
+			ModelArgs: []string{"--model", "{model}"},
 		},
 		"gemini": {
 			Command:    "gemini",
@@ -250,6 +565,11 @@ func defaultAgents() map[string]AgentConfig {
 			Env:        map[string]string{},
 			StatusFile: "",
 			InitPrompt: defaultGeminiPrompt,
+			Models: []ModelOption{
+				{Name: "gemini-2.5-flash", Tier: "$"},
+				{Name: "gemini-2.5-pro", Tier: "$$"},
+			},
+			ModelArgs: []string{"--model", "{model}"},
 		},
 		"codex": {
 			Command:    "codex",
@@ -257,6 +577,12 @@ func defaultAgents() map[string]AgentConfig {
 			Env:        map[string]string{},
 			StatusFile: "",
 			InitPrompt: defaultCodexPrompt,
+			Models: []ModelOption{
+				{Name: "o4-mini", Tier: "$"},
+				{Name: "gpt-4o", Tier: "$$"},
+				{Name: "o3", Tier: "$$$"},
+			},
+			ModelArgs: []string{"--model", "{model}"},
 		},
 	}
 }
@@ -265,6 +591,7 @@ func defaultAgents() map[string]AgentConfig {
 func DefaultConfig() *Config {
 	agents := defaultAgents()
 	return &Config{
+		Version: CurrentVersion,
 		Defaults: BoardSettings{
 			DefaultAgent:     DetectAvailableAgent(agents),
 			WorktreeBase:     "",
@@ -275,24 +602,42 @@ func DefaultConfig() *Config {
 			BranchTemplate:   "{prefix}{slug}",
 			SlugMaxLength:    40,
 			InitPrompt:       defaultGlobalPrompt,
+			SpawnOn:          []string{},
 		},
 		Agents: agents,
 		UI: UIConfig{
 			Theme:           "catppuccin-mocha",
 			ShowAgentStatus: true,
+			ShowGitStatus:   true,
 			RefreshInterval: 5,
 			ColumnWidth:     40,
 			TicketHeight:    4,
 			SidebarVisible:  true,
 			ScrollbackLines: 10000,
+			SplitDirection:  "right",
+			SplitRatio:      50,
+			Tabs:            true,
+			TicketOrder:     "created",
+			ProjectOrder:    "name",
+			CardDensity:     "normal",
+			OutputPreview:   true,
 		},
 		Cleanup: CleanupSettings{
 			DeleteWorktree:       true,
 			DeleteBranch:         false,
 			ForceWorktreeRemoval: false,
+			AfterMerge:           "ask",
+			OnArchive:            "ask",
 		},
 		Behavior: BehaviorSettings{
 			ConfirmQuitWithAgents: true,
+			SyncStrategy:          "rebase",
+			AutosaveInterval:      2,
+			OnApprove:             "merge",
+			Confirm: ConfirmSettings{
+				DeleteTicket:  true,
+				PruneWorktree: true,
+			},
 		},
 		Opencode: OpencodeSettings{
 			ServerEnabled:  true,
@@ -300,6 +645,71 @@ func DefaultConfig() *Config {
 			PollInterval:   1,
 			StartupTimeout: 10,
 		},
+		PullRequest: PullRequestSettings{
+			Remote:         "origin",
+			Provider:       "auto",
+			IncludeCommits: true,
+			StatusInterval: 60,
+		},
+		Jira: JiraSettings{
+			SyncInterval: 5,
+			Transitions: map[string]string{
+				"in_progress": "In Progress",
+				"done":        "Done",
+			},
+		},
+		Linear: LinearSettings{
+			SyncInterval: 5,
+			States: map[string]string{
+				"in_progress": "In Progress",
+				"done":        "Done",
+			},
+			LinkBranch: true,
+			LinkPR:     true,
+		},
+		CommitRefs: CommitRefSettings{
+			Enabled: true,
+			Prefix:  "okb",
+		},
+		Tmux: TmuxSettings{
+			SessionPrefix: "openkanban-",
+		},
+		Vault: VaultSettings{
+			Folder: "openkanban",
+		},
+		Digest: DigestSettings{
+			Period:    "daily",
+			StallDays: 3,
+			SMTP: SMTPSettings{
+				To: []string{},
+			},
+		},
+		Telemetry: TelemetrySettings{
+			ServiceName: "openkanban",
+		},
+		Storage: StorageSettings{
+			Encryption: "none",
+		},
+		Sync: SyncSettings{
+			Remote:   "origin",
+			Branch:   "openkanban-state",
+			Interval: 60,
+		},
+		Logging: LoggingSettings{
+			Level:     "info",
+			MaxSizeMB: 10,
+			MaxFiles:  3,
+		},
+		Notifications: NotificationSettings{
+			Desktop: DesktopNotifySettings{
+				AgentWaiting:   true,
+				AgentCompleted: true,
+				AgentFailed:    true,
+			},
+		},
+		Keybindings: KeybindingsConfig{
+			Preset: "default",
+		},
 	}
 }
 
@@ -333,8 +743,45 @@ func ConfigPath() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
-// Load reads configuration from file or returns defaults
+// SocketPath returns the control socket path used by the running TUI.
+// OPENKANBAN_SOCKET overrides the default location.
+func SocketPath() (string, error) {
+	if path := os.Getenv("OPENKANBAN_SOCKET"); path != "" {
+		return path, nil
+	}
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "openkanban.sock"), nil
+}
+
+// ControlTokenEnv holds the shared secret required on the control socket
+const ControlTokenEnv = "OPENKANBAN_CONTROL_TOKEN"
+
+// ControlToken returns the shared secret required on the control socket,
+// from ControlTokenEnv. Empty means none is required.
+func ControlToken() string {
+	return os.Getenv(ControlTokenEnv)
+}
+
+// Load reads configuration from file or returns defaults, then applies
+// OPENKANBAN_* environment overrides
 func Load(path string) (*Config, error) {
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if errs := cfg.applyEnv(os.LookupEnv); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return cfg, nil
+}
+
+// LoadFile reads configuration from file or returns defaults, ignoring the
+// environment. Use it when the result is saved back to the file.
+func LoadFile(path string) (*Config, error) {
 	if path == "" {
 		var err error
 		path, err = ConfigPath()
@@ -343,6 +790,8 @@ func Load(path string) (*Config, error) {
 		}
 	}
 
+	loadUserThemes()
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -351,6 +800,11 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	data, _, _, err = migrateData(data)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := DefaultConfig()
 	if err := json.Unmarshal(data, cfg); err != nil {
 		return nil, err
@@ -372,6 +826,12 @@ func (c *Config) mergeAgentDefaults() {
 			if userCfg.Env == nil {
 				userCfg.Env = defaultCfg.Env
 			}
+			if userCfg.Models == nil {
+				userCfg.Models = defaultCfg.Models
+			}
+			if userCfg.ModelArgs == nil {
+				userCfg.ModelArgs = defaultCfg.ModelArgs
+			}
 			c.Agents[name] = userCfg
 		}
 	}
@@ -424,15 +884,39 @@ func LoadWithValidation(path string) (*Config, *ValidationResult, error) {
 		}
 	}
 
+	themeErrs := loadUserThemes()
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			cfg := DefaultConfig()
-			return cfg, cfg.Validate(), nil
+			envErrs := cfg.applyEnv(os.LookupEnv)
+			result := cfg.Validate()
+			addEnvErrors(result, envErrs)
+			addThemeWarnings(result, themeErrs)
+			return cfg, result, nil
 		}
 		return nil, nil, err
 	}
 
+	data, version, applied, err := migrateData(data)
+	if err != nil {
+		result := &ValidationResult{}
+		result.AddError("version", "", err.Error(), nil)
+		return nil, result, err
+	}
+
+	// Check types against the schema first so every mismatch is reported
+	// with its key path, not just the first one json.Unmarshal hits.
+	schema := &ValidationResult{}
+	var raw map[string]any
+	if json.Unmarshal(data, &raw) == nil {
+		checkSchema(raw, schema)
+		if schema.HasErrors() {
+			return nil, schema, errors.New("config does not match schema")
+		}
+	}
+
 	cfg := DefaultConfig()
 	if err := json.Unmarshal(data, cfg); err != nil {
 		result := &ValidationResult{}
@@ -445,7 +929,15 @@ func LoadWithValidation(path string) (*Config, *ValidationResult, error) {
 	}
 
 	cfg.mergeAgentDefaults()
+	envErrs := cfg.applyEnv(os.LookupEnv)
 	result := cfg.Validate()
+	addEnvErrors(result, envErrs)
+	result.Warnings = append(schema.Warnings, result.Warnings...)
+	addThemeWarnings(result, themeErrs)
+	if version != CurrentVersion {
+		summary := fmt.Sprintf("file is version %d, upgraded to %d on load; run `openkanban config migrate` to update it", version, CurrentVersion)
+		result.AddWarning("version", "", migrationNote(summary, applied), nil)
+	}
 
 	return cfg, result, nil
 }
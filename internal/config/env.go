@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnvPrefix starts every environment variable that overrides a config key.
+const EnvPrefix = "OPENKANBAN_"
+
+// envAliases are short environment variable names for common keys, checked
+// in addition to the generated names.
+var envAliases = map[string]string{
+	"OPENKANBAN_THEME":         "ui.theme",
+	"OPENKANBAN_DEFAULT_AGENT": "defaults.default_agent",
+	"OPENKANBAN_READ_ONLY":     "behavior.read_only",
+	"OPENKANBAN_LOG_LEVEL":     "logging.level",
+}
+
+// EnvVar returns the environment variable that overrides key, e.g.
+// "ui.column_width" -> "OPENKANBAN_UI_COLUMN_WIDTH".
+func EnvVar(key string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(ResolveKey(key))
+	return EnvPrefix + strings.ToUpper(name)
+}
+
+// EnvOverride is a config value taken from the environment.
+type EnvOverride struct {
+	Key   string
+	Var   string
+	Value string
+}
+
+// applyEnv overrides config keys from environment variables. Every setting
+// can be set through EnvVar(key); aliases take precedence over the
+// generated names. Values are parsed like `openkanban config set`.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) []error {
+	settings, err := c.settingKeys()
+	if err != nil {
+		return []error{err}
+	}
+
+	values := make(map[string]EnvOverride)
+	for _, key := range settings {
+		if key == "version" {
+			continue
+		}
+		name := EnvVar(key)
+		if value, ok := lookup(name); ok {
+			values[key] = EnvOverride{Key: key, Var: name, Value: value}
+		}
+	}
+	for name, key := range envAliases {
+		if value, ok := lookup(name); ok {
+			values[key] = EnvOverride{Key: key, Var: name, Value: value}
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	applied := make(map[string]EnvOverride)
+	for _, key := range keys {
+		o := values[key]
+		if err := c.Set(key, o.Value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", o.Var, err))
+			continue
+		}
+		applied[key] = o
+	}
+
+	if len(applied) > 0 {
+		c.envOverrides = applied
+	}
+	return errs
+}
+
+// settingKeys lists the key of every setting. The fields of Config give
+// them, whether or not they are set; List misses fields left out by
+// omitempty. Entries of maps such as agents come from the current values,
+// and an empty map is a setting of its own.
+func (c *Config) settingKeys() ([]string, error) {
+	m, err := c.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	var walk func(prefix string, t reflect.Type, v any)
+	walk = func(prefix string, t reflect.Type, v any) {
+		obj, _ := v.(map[string]any)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			for name, field := range jsonFields(t) {
+				walk(joinPath(prefix, name), field, obj[name])
+			}
+			return
+		case reflect.Map:
+			if len(obj) > 0 {
+				for name, child := range obj {
+					walk(joinPath(prefix, name), t.Elem(), child)
+				}
+				return
+			}
+		}
+		keys = append(keys, prefix)
+	}
+	walk("", reflect.TypeOf(Config{}), m)
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// IsEnvOverride reports whether key was set from the environment. Values set
+// this way take precedence over project settings.
+func (c *Config) IsEnvOverride(key string) bool {
+	_, ok := c.envOverrides[ResolveKey(key)]
+	return ok
+}
+
+// EnvOverrides lists the keys set from the environment, sorted by key.
+func (c *Config) EnvOverrides() []EnvOverride {
+	result := make([]EnvOverride, 0, len(c.envOverrides))
+	for _, o := range c.envOverrides {
+		result = append(result, o)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+func addEnvErrors(r *ValidationResult, errs []error) {
+	for _, err := range errs {
+		r.AddError("env", "", err.Error(), nil)
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvVar(t *testing.T) {
+	tests := map[string]string{
+		"ui.column_width":       "OPENKANBAN_UI_COLUMN_WIDTH",
+		"agents.claude.command": "OPENKANBAN_AGENTS_CLAUDE_COMMAND",
+		"theme":                 "OPENKANBAN_UI_THEME",
+	}
+	for key, want := range tests {
+		if got := EnvVar(key); got != want {
+			t.Errorf("EnvVar(%q) = %q; want %q", key, got, want)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"OPENKANBAN_THEME":                 "nord",
+		"OPENKANBAN_UI_THEME":              "dracula",
+		"OPENKANBAN_UI_COLUMN_WIDTH":       "60",
+		"OPENKANBAN_CLEANUP_DELETE_BRANCH": "true",
+		"OPENKANBAN_DEFAULT_AGENT":         "claude",
+		"OPENKANBAN_READ_ONLY":             "true",
+		"OPENKANBAN_VERSION":               "7",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cfg := DefaultConfig()
+	if errs := cfg.applyEnv(lookup); len(errs) > 0 {
+		t.Fatalf("applyEnv() errors = %v", errs)
+	}
+
+	if cfg.UI.Theme != "nord" {
+		t.Errorf("UI.Theme = %q; alias should win over generated name", cfg.UI.Theme)
+	}
+	if cfg.UI.ColumnWidth != 60 || !cfg.Cleanup.DeleteBranch || cfg.Defaults.DefaultAgent != "claude" || !cfg.Behavior.ReadOnly {
+		t.Errorf("overrides not applied: %+v %+v %+v %+v", cfg.UI, cfg.Cleanup, cfg.Defaults, cfg.Behavior)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d; version must not be overridable", cfg.Version)
+	}
+	if !cfg.IsEnvOverride("ui.column_width") || !cfg.IsEnvOverride("theme") || cfg.IsEnvOverride("ui.ticket_height") {
+		t.Error("IsEnvOverride() does not reflect applied overrides")
+	}
+	if got := cfg.EnvOverrides(); len(got) != 5 || got[0].Key != "behavior.read_only" {
+		t.Errorf("EnvOverrides() = %+v; want 5 sorted entries", got)
+	}
+}
+
+func TestApplyEnv_InvalidValue(t *testing.T) {
+	cfg := DefaultConfig()
+	errs := cfg.applyEnv(func(name string) (string, bool) {
+		if name == "OPENKANBAN_UI_COLUMN_WIDTH" {
+			return "wide", true
+		}
+		return "", false
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "OPENKANBAN_UI_COLUMN_WIDTH") {
+		t.Errorf("applyEnv() errors = %v; want one naming the variable", errs)
+	}
+	if cfg.UI.ColumnWidth != 40 {
+		t.Errorf("invalid override changed ColumnWidth to %d", cfg.UI.ColumnWidth)
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "ui": {"theme": "nord", "column_width": 50}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OPENKANBAN_THEME", "dracula")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.UI.Theme != "dracula" || cfg.UI.ColumnWidth != 50 {
+		t.Errorf("Load() theme = %q, column_width = %d; want env theme and file width", cfg.UI.Theme, cfg.UI.ColumnWidth)
+	}
+
+	fileOnly, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if fileOnly.UI.Theme != "nord" {
+		t.Errorf("LoadFile() theme = %q; want value from file", fileOnly.UI.Theme)
+	}
+
+	t.Setenv("OPENKANBAN_UI_COLUMN_WIDTH", "narrow")
+	_, result, _ := LoadWithValidation(path)
+	found := false
+	for _, e := range result.Errors {
+		if e.Section == "env" && strings.Contains(e.Message, "OPENKANBAN_UI_COLUMN_WIDTH") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("LoadWithValidation() errors = %+v; want env error", result.Errors)
+	}
+}
+
+func TestSocketPath_EnvOverride(t *testing.T) {
+	t.Setenv("OPENKANBAN_SOCKET", "/tmp/custom.sock")
+	if path, err := SocketPath(); err != nil || path != "/tmp/custom.sock" {
+		t.Errorf("SocketPath() = %q, %v; want override", path, err)
+	}
+}
+
+func TestApplyEnv_OmittedFields(t *testing.T) {
+	env := map[string]string{
+		"OPENKANBAN_AGENTS_CLAUDE_READY_TIMEOUT": "45",
+		"OPENKANBAN_AGENTS_CLAUDE_READY_PATTERN": "> $",
+		"OPENKANBAN_UI_CUSTOM_COLORS_PRIMARY":    "#ff0000",
+	}
+	cfg := DefaultConfig()
+	claude := cfg.Agents["claude"]
+	claude.ReadyTimeout, claude.ReadyPattern = 0, ""
+	cfg.Agents["claude"] = claude
+	cfg.UI.CustomColors = nil
+
+	if errs := cfg.applyEnv(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}); len(errs) > 0 {
+		t.Fatalf("applyEnv() errors = %v", errs)
+	}
+	if got := cfg.Agents["claude"]; got.ReadyTimeout != 45 || got.ReadyPattern != "> $" {
+		t.Errorf("claude ready_timeout, ready_pattern = %d, %q; want unset settings overridden", got.ReadyTimeout, got.ReadyPattern)
+	}
+	if cfg.UI.CustomColors == nil || cfg.UI.CustomColors.Primary != "#ff0000" {
+		t.Errorf("UI.CustomColors = %+v; want primary overridden", cfg.UI.CustomColors)
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// KeybindingsConfig maps board actions to keys
+type KeybindingsConfig struct {
+	Preset   string             `json:"preset"`             // "default" | "vim" | "emacs"
+	Bindings map[string]KeyList `json:"bindings,omitempty"` // action -> keys, e.g. "new_ticket": "a"
+	Macros   map[string]Macro   `json:"macros,omitempty"`   // name -> recorded keys
+}
+
+// Macro is a recorded sequence of board keys, replayed from the macro menu
+// or with its own key
+type Macro struct {
+	Key  string   `json:"key,omitempty"` // Board key that replays the macro
+	Keys []string `json:"keys"`          // Keys in the order pressed, written like bindings
+}
+
+// KeyList is one or more keys. In JSON it may be a single string or a list.
+type KeyList []string
+
+// UnmarshalJSON accepts either "key" or ["key", ...]
+func (k *KeyList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*k = KeyList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*k = list
+	return nil
+}
+
+func (c *KeybindingsConfig) overrides() map[string][]string {
+	if len(c.Bindings) == 0 {
+		return nil
+	}
+	overrides := make(map[string][]string, len(c.Bindings))
+	for action, keys := range c.Bindings {
+		overrides[action] = keys
+	}
+	return overrides
+}
+
+// Keymap builds the board's key bindings. An invalid configuration, which
+// validation reports, falls back to the default preset.
+func (c *Config) Keymap() *keymap.Keymap {
+	k, _, err := keymap.New(c.Keybindings.Preset, c.Keybindings.overrides())
+	if err != nil {
+		return keymap.Default()
+	}
+	return k
+}
+
+// validateKeybindings validates the keybindings section
+func (c *Config) validateKeybindings(r *ValidationResult) {
+	km, conflicts, err := keymap.New(c.Keybindings.Preset, c.Keybindings.overrides())
+	if err != nil {
+		r.AddError("keybindings", "", err.Error(), nil)
+		return
+	}
+	for _, conflict := range conflicts {
+		r.AddError("keybindings", "bindings", conflict.String(), nil)
+	}
+
+	macroKeys := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(c.Keybindings.Macros)) {
+		macro := c.Keybindings.Macros[name]
+		field := "macros." + name
+		if len(macro.Keys) == 0 {
+			r.AddError("keybindings", field+".keys", "must list at least one key", nil)
+		}
+		if macro.Key == "" {
+			continue
+		}
+		key := keymap.NormalizeKey(macro.Key)
+		if action, bound := km.Action(keymap.ContextBoard, key); bound {
+			r.AddError("keybindings", field+".key",
+				fmt.Sprintf("%s is already bound to %s", macro.Key, action), macro.Key)
+		} else if key == "esc" || key == "ctrl+c" {
+			r.AddError("keybindings", field+".key", macro.Key+" is reserved", macro.Key)
+		} else if other, taken := macroKeys[key]; taken {
+			r.AddError("keybindings", field+".key",
+				fmt.Sprintf("%s also replays macro %s", macro.Key, other), macro.Key)
+		} else {
+			macroKeys[key] = name
+		}
+	}
+}
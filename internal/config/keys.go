@@ -0,0 +1,334 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keyAliases maps short, memorable keys to their full dotted paths.
+var keyAliases = map[string]string{
+	"agent.default": "defaults.default_agent",
+	"theme":         "ui.theme",
+}
+
+// KeyValue is a flattened config entry as shown by `openkanban config list`.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// ResolveKey expands aliases and returns the canonical dotted key.
+func ResolveKey(key string) string {
+	if full, ok := keyAliases[key]; ok {
+		return full
+	}
+	return key
+}
+
+// toMap converts the config into its JSON object form.
+func (c *Config) toMap() (map[string]any, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Get returns the value at a dotted key such as "ui.theme" or
+// "agents.claude.command", formatted for display.
+func (c *Config) Get(key string) (string, error) {
+	m, err := c.toMap()
+	if err != nil {
+		return "", err
+	}
+
+	key = ResolveKey(key)
+	var cur any = m
+	for _, part := range splitKey(key) {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("unknown config key: %s", key)
+		}
+		cur, ok = obj[part]
+		if !ok {
+			return "", fmt.Errorf("unknown config key: %s", key)
+		}
+	}
+	return formatValue(cur), nil
+}
+
+// Set parses value according to the type of the existing setting at key and
+// stores it. Keys inside maps (agents, hooks, custom colors) may be created.
+func (c *Config) Set(key, value string) error {
+	m, err := c.toMap()
+	if err != nil {
+		return err
+	}
+
+	key = ResolveKey(key)
+	parts := splitKey(key)
+	parent := m
+	for i, part := range parts[:len(parts)-1] {
+		next, ok := parent[part]
+		if !ok || next == nil {
+			if _, known := fieldType(parts[:i+1]); !known && !c.isMapPath(parts[:i+1]) {
+				return fmt.Errorf("unknown config key: %s", key)
+			}
+			next = map[string]any{}
+			parent[part] = next
+		}
+		obj, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s is not a section", strings.Join(parts[:i+1], "."))
+		}
+		parent = obj
+	}
+
+	last := parts[len(parts)-1]
+	existing, exists := parent[last]
+	// Settings left out by omitempty are parsed by their field's type
+	t, known := fieldType(parts)
+	if !exists && known {
+		existing = zeroValue(t)
+	}
+	if !exists && !known && !c.isMapPath(parts[:len(parts)-1]) {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
+	parsed, err := parseValue(existing, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	if s, ok := parsed.(string); ok && len(parts) == 2 && parts[0] == "hooks" {
+		parsed = []any{s}
+	}
+	parent[last] = parsed
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	updated := &Config{}
+	if err := json.Unmarshal(data, updated); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	// A zero value set on an omitempty field doesn't show up in Get, and a
+	// key unknown to a map's entries is dropped by the decode
+	if !known {
+		if _, err := updated.Get(key); err != nil {
+			return err
+		}
+	}
+	updated.envOverrides = c.envOverrides
+	*c = *updated
+	return nil
+}
+
+// fieldType returns the Go type of the setting at path, following the
+// fields of Config and the element types of its maps
+func fieldType(path []string) (reflect.Type, bool) {
+	t := reflect.TypeOf(Config{})
+	for _, part := range path {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := jsonFields(t)[part]
+			if !ok {
+				return nil, false
+			}
+			t = field
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil, false
+		}
+	}
+	return t, true
+}
+
+// zeroValue is the JSON form of an unset setting of type t, for parseValue
+func zeroValue(t reflect.Type) any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return ""
+	case reflect.Bool:
+		return false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return float64(0)
+	case reflect.Slice, reflect.Array:
+		return []any{}
+	case reflect.Map, reflect.Struct:
+		return map[string]any{}
+	}
+	return nil
+}
+
+// Unset removes an entry from a free-form map such as keybindings.bindings or
+// ui.custom_colors, so the preset or theme value applies again. Removing an
+// entry that is not set is not an error.
+func (c *Config) Unset(key string) error {
+	key = ResolveKey(key)
+	parts := splitKey(key)
+	if len(parts) < 2 || !c.isMapPath(parts[:len(parts)-1]) {
+		return fmt.Errorf("%s cannot be unset", key)
+	}
+
+	m, err := c.toMap()
+	if err != nil {
+		return err
+	}
+	parent := m
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := parent[part].(map[string]any)
+		if !ok {
+			return nil
+		}
+		parent = next
+	}
+	delete(parent, parts[len(parts)-1])
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	updated := &Config{}
+	if err := json.Unmarshal(data, updated); err != nil {
+		return err
+	}
+	updated.envOverrides = c.envOverrides
+	*c = *updated
+	return nil
+}
+
+// splitKey splits a dotted key into path segments. Hook names contain dots
+// themselves, so everything after "hooks." is kept as a single segment.
+func splitKey(key string) []string {
+	section, rest, found := strings.Cut(key, ".")
+	if found && section == "hooks" {
+		return []string{section, rest}
+	}
+	return strings.Split(key, ".")
+}
+
+// isMapPath reports whether path names a free-form map in the config, where
+// new keys may be added.
+func (c *Config) isMapPath(path []string) bool {
+	switch strings.Join(path, ".") {
+	case "agents", "hooks", "keybindings.bindings", "ui.custom_colors":
+		return true
+	}
+	if len(path) == 2 && path[0] == "agents" {
+		return true
+	}
+	if len(path) == 3 && path[0] == "agents" && path[2] == "env" {
+		return true
+	}
+	return false
+}
+
+// List returns every leaf setting as a flattened, sorted key/value list.
+func (c *Config) List() ([]KeyValue, error) {
+	m, err := c.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []KeyValue
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		if obj, ok := v.(map[string]any); ok && len(obj) > 0 {
+			for k, child := range obj {
+				key := k
+				if prefix != "" {
+					key = prefix + "." + k
+				}
+				walk(key, child)
+			}
+			return
+		}
+		result = append(result, KeyValue{Key: prefix, Value: formatValue(v)})
+	}
+	walk("", m)
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+// parseValue converts a command-line string into the JSON type of existing.
+// When there is no existing value, JSON literals are accepted and anything
+// else is stored as a string.
+func parseValue(existing any, value string) (any, error) {
+	switch existing.(type) {
+	case string:
+		return value, nil
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", value)
+		}
+		return b, nil
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return f, nil
+	case []any:
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			var list []any
+			err := json.Unmarshal([]byte(value), &list)
+			return list, err
+		}
+		if value == "" {
+			return []any{}, nil
+		}
+		var list []any
+		for _, item := range strings.Split(value, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+		return list, nil
+	case map[string]any:
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(value), &obj); err != nil {
+			return nil, fmt.Errorf("expected a JSON object")
+		}
+		return obj, nil
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+		return parsed, nil
+	}
+	return value, nil
+}
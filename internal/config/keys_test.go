@@ -0,0 +1,181 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_Get(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.Theme = "nord"
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "ui.theme", want: "nord"},
+		{key: "theme", want: "nord"},
+		{key: "ui.column_width", want: "40"},
+		{key: "behavior.confirm_quit_with_agents", want: "true"},
+		{key: "agents.claude.command", want: "claude"},
+		{key: "ui.nope", wantErr: true},
+		{key: "ui.theme.deeper", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := cfg.Get(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Get(%q) = %q; want error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get(%q) error = %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("Get(%q) = %q; want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		check   func(*Config) bool
+		wantErr string
+	}{
+		{
+			name:  "string via alias",
+			key:   "agent.default",
+			value: "claude",
+			check: func(c *Config) bool { return c.Defaults.DefaultAgent == "claude" },
+		},
+		{
+			name:  "bool",
+			key:   "cleanup.delete_branch",
+			value: "true",
+			check: func(c *Config) bool { return c.Cleanup.DeleteBranch },
+		},
+		{
+			name:  "int",
+			key:   "ui.column_width",
+			value: "55",
+			check: func(c *Config) bool { return c.UI.ColumnWidth == 55 },
+		},
+		{
+			name:  "list from commas",
+			key:   "agents.claude.args",
+			value: "--verbose, --debug",
+			check: func(c *Config) bool {
+				return strings.Join(c.Agents["claude"].Args, " ") == "--verbose --debug"
+			},
+		},
+		{
+			name:  "new agent field",
+			key:   "agents.claude.cost_per_hour",
+			value: "2.5",
+			check: func(c *Config) bool { return c.Agents["claude"].CostPerHour == 2.5 },
+		},
+		{
+			name:  "new hook",
+			key:   "hooks.ticket.moved",
+			value: "echo moved",
+			check: func(c *Config) bool { return len(c.Hooks["ticket.moved"]) == 1 },
+		},
+		{
+			name:  "new keybinding",
+			key:   "keybindings.bindings.new_ticket",
+			value: "a",
+			check: func(c *Config) bool { return strings.Join(c.Keybindings.Bindings["new_ticket"], ",") == "a" },
+		},
+		{name: "bad bool", key: "cleanup.delete_branch", value: "maybe", wantErr: "invalid value"},
+		{name: "bad int", key: "ui.column_width", value: "wide", wantErr: "invalid value"},
+		{name: "unknown key", key: "ui.nope", value: "x", wantErr: "unknown config key"},
+		{name: "unknown agent field", key: "agents.claude.comand", value: "x", wantErr: "unknown config key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Set(%q, %q) error = %v; want %q", tt.key, tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set(%q, %q) error = %v", tt.key, tt.value, err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("Set(%q, %q) did not apply", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestConfig_Unset(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Set("keybindings.bindings.new_ticket", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("ui.custom_colors.primary", "#ff0000"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.Unset("keybindings.bindings.new_ticket"); err != nil {
+		t.Fatalf("Unset() error = %v", err)
+	}
+	if _, ok := cfg.Keybindings.Bindings["new_ticket"]; ok {
+		t.Error("Unset() did not remove the binding")
+	}
+	if err := cfg.Unset("ui.custom_colors.primary"); err != nil || cfg.UI.CustomColors.Primary != "" {
+		t.Errorf("Unset(custom color) = %v, primary %q", err, cfg.UI.CustomColors.Primary)
+	}
+	if err := cfg.Unset("keybindings.bindings.quit"); err != nil {
+		t.Errorf("Unset() of a missing entry error = %v", err)
+	}
+	if err := cfg.Unset("ui.theme"); err == nil {
+		t.Error("Unset() should refuse keys outside maps")
+	}
+}
+
+func TestConfig_SetKeepsEnvOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.applyEnv(func(name string) (string, bool) {
+		return "nord", name == "OPENKANBAN_THEME"
+	})
+	if err := cfg.Set("ui.column_width", "50"); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.IsEnvOverride("ui.theme") {
+		t.Error("Set() dropped environment overrides")
+	}
+}
+
+func TestConfig_List(t *testing.T) {
+	cfg := DefaultConfig()
+	entries, err := cfg.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	found := false
+	for i, kv := range entries {
+		if i > 0 && entries[i-1].Key > kv.Key {
+			t.Fatalf("List() not sorted: %q before %q", entries[i-1].Key, kv.Key)
+		}
+		if kv.Key == "ui.theme" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("List() missing ui.theme")
+	}
+}
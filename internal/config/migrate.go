@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// migration upgrades a decoded config file from one version to the next.
+type migration struct {
+	from        int
+	description string
+	// apply reports whether anything changed, with notes on anything it
+	// left alone
+	apply func(raw map[string]any) (bool, []string)
+}
+
+// migrations are applied in order to files older than CurrentVersion. Files
+// without a "version" key are version 1.
+var migrations = []migration{
+	{
+		from:        1,
+		description: `moved "keys" into "keybindings.bindings"`,
+		apply:       migrateKeysToKeybindings,
+	},
+}
+
+// migrateKeysToKeybindings moves the unused top-level "keys" map
+// (action -> key) into the keybindings section. Existing bindings win.
+// Entries for unknown actions stay under "keys", which is ignored, rather
+// than failing validation as bindings.
+func migrateKeysToKeybindings(raw map[string]any) (bool, []string) {
+	if _, exists := raw["keys"]; !exists {
+		return false, nil
+	}
+	keys, _ := raw["keys"].(map[string]any)
+	delete(raw, "keys")
+	if len(keys) == 0 {
+		return true, nil
+	}
+
+	kb, _ := raw["keybindings"].(map[string]any)
+	if kb == nil {
+		kb = map[string]any{}
+		raw["keybindings"] = kb
+	}
+	bindings, _ := kb["bindings"].(map[string]any)
+	if bindings == nil {
+		bindings = map[string]any{}
+		kb["bindings"] = bindings
+	}
+	kept := map[string]any{}
+	for action, key := range keys {
+		if _, known := keymap.Lookup(keymap.Action(action)); !known {
+			kept[action] = key
+			continue
+		}
+		if _, exists := bindings[action]; !exists {
+			bindings[action] = key
+		}
+	}
+	if len(kept) == 0 {
+		return true, nil
+	}
+	raw["keys"] = kept
+	notes := make([]string, 0, len(kept))
+	for action := range kept {
+		notes = append(notes, fmt.Sprintf("kept keys.%s: unknown action", action))
+	}
+	sort.Strings(notes)
+	return true, notes
+}
+
+// fileVersion returns the schema version recorded in a decoded config file.
+func fileVersion(raw map[string]any) (int, error) {
+	v, ok := raw["version"]
+	if !ok {
+		return 1, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n < 1 || n != float64(int(n)) {
+		return 0, fmt.Errorf("version must be a positive whole number, got %v", v)
+	}
+	return int(n), nil
+}
+
+// Migrate upgrades a decoded config file to CurrentVersion in place and
+// returns a description of each step that changed something.
+func Migrate(raw map[string]any) ([]string, error) {
+	version, err := fileVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this openkanban supports (%d); please upgrade", version, CurrentVersion)
+	}
+
+	var applied []string
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if changed, notes := m.apply(raw); changed {
+			applied = append(applied, m.description)
+			applied = append(applied, notes...)
+		}
+		version = m.from + 1
+	}
+	raw["version"] = CurrentVersion
+	return applied, nil
+}
+
+// BackupPath returns where the pre-migration copy of a config file is kept.
+func BackupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, version)
+}
+
+// migrateData upgrades the contents of a config file older than
+// CurrentVersion in memory. It returns the upgraded contents, the version
+// the file had and the steps applied; the version is CurrentVersion when
+// nothing needed upgrading.
+func migrateData(data []byte) ([]byte, int, []string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		// Syntax errors are reported by the regular decode
+		return data, CurrentVersion, nil, nil
+	}
+
+	version, err := fileVersion(raw)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if version == CurrentVersion {
+		return data, version, nil, nil
+	}
+
+	applied, err := Migrate(raw)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(raw); err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	return buf.Bytes(), version, applied, nil
+}
+
+// migrationNote describes a migration, one step per line
+func migrationNote(summary string, applied []string) string {
+	for _, step := range applied {
+		summary += "\n    - " + step
+	}
+	return summary
+}
+
+// MigrateFile upgrades the config file at path to CurrentVersion, copying
+// the original to BackupPath first. Loading a config only upgrades it in
+// memory; this is the one place the file is rewritten. It returns a note
+// describing the migration, or "" when the file was up to date.
+func MigrateFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	migrated, version, applied, err := migrateData(data)
+	if err != nil {
+		return "", err
+	}
+	if version == CurrentVersion {
+		return "", nil
+	}
+
+	backup := BackupPath(path, version)
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up config before migration: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		return "", fmt.Errorf("failed to write migrated config: %w", err)
+	}
+	return migrationNote(fmt.Sprintf("migrated from version %d to %d (backup: %s)", version, CurrentVersion, backup), applied), nil
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate_KeysToKeybindings(t *testing.T) {
+	raw := map[string]any{
+		"keys": map[string]any{"new_ticket": "a", "quit": "Q"},
+		"keybindings": map[string]any{
+			"bindings": map[string]any{"quit": "x"},
+		},
+	}
+
+	applied, err := Migrate(raw)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != 1 {
+		t.Errorf("Migrate() applied %v; want one step", applied)
+	}
+	if _, exists := raw["keys"]; exists {
+		t.Error("keys should be removed")
+	}
+	if raw["version"] != CurrentVersion {
+		t.Errorf("version = %v; want %d", raw["version"], CurrentVersion)
+	}
+
+	bindings := raw["keybindings"].(map[string]any)["bindings"].(map[string]any)
+	if bindings["new_ticket"] != "a" {
+		t.Errorf("new_ticket = %v; want migrated key", bindings["new_ticket"])
+	}
+	if bindings["quit"] != "x" {
+		t.Errorf("quit = %v; existing binding should win", bindings["quit"])
+	}
+}
+
+func TestMigrate_Versions(t *testing.T) {
+	if applied, err := Migrate(map[string]any{"version": float64(CurrentVersion)}); err != nil || len(applied) != 0 {
+		t.Errorf("Migrate(current) = %v, %v; want no-op", applied, err)
+	}
+	if _, err := Migrate(map[string]any{"version": float64(CurrentVersion + 1)}); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Migrate(future) error = %v; want newer-version error", err)
+	}
+	if _, err := Migrate(map[string]any{"version": "two"}); err == nil {
+		t.Error("Migrate() should reject a non-numeric version")
+	}
+}
+
+func TestMigrate_KeepsUnknownKeys(t *testing.T) {
+	raw := map[string]any{"keys": map[string]any{"new_ticket": "a", "fly": "f"}}
+
+	applied, err := Migrate(raw)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != 2 || applied[1] != "kept keys.fly: unknown action" {
+		t.Errorf("Migrate() applied %v; want the step and a note on fly", applied)
+	}
+	if keys, _ := raw["keys"].(map[string]any); len(keys) != 1 || keys["fly"] != "f" {
+		t.Errorf("keys = %v; want only the unknown action kept", raw["keys"])
+	}
+	bindings := raw["keybindings"].(map[string]any)["bindings"].(map[string]any)
+	if _, moved := bindings["fly"]; moved || bindings["new_ticket"] != "a" {
+		t.Errorf("bindings = %v; want only new_ticket", bindings)
+	}
+}
+
+func TestLoadWithValidation_MigratesInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := `{"keys": {"new_ticket": "a", "fly": "f"}, "ui": {"column_width": 50}}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, result, err := LoadWithValidation(path)
+	if err != nil {
+		t.Fatalf("LoadWithValidation() error = %v", err)
+	}
+	if cfg.Version != CurrentVersion || cfg.UI.ColumnWidth != 50 {
+		t.Errorf("loaded version %d, column_width %d", cfg.Version, cfg.UI.ColumnWidth)
+	}
+	if got := cfg.Keybindings.Bindings["new_ticket"]; len(got) != 1 || got[0] != "a" {
+		t.Errorf("new_ticket binding = %v; want [a]", got)
+	}
+
+	found := false
+	for _, w := range result.Warnings {
+		if w.Section == "version" && strings.Contains(w.Message, "config migrate") && strings.Contains(w.Message, "keys.fly") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected migration notice in warnings: %+v", result.Warnings)
+	}
+
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("loading rewrote the file:\n%s", data)
+	}
+	if _, err := os.Stat(BackupPath(path, 1)); !os.IsNotExist(err) {
+		t.Errorf("loading wrote a backup: %v", err)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	original := `{"keys": {"new_ticket": "a"}, "ui": {"column_width": 50}}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := MigrateFile(path)
+	if err != nil {
+		t.Fatalf("MigrateFile() error = %v", err)
+	}
+	if !strings.Contains(note, "migrated from version 1") {
+		t.Errorf("MigrateFile() note = %q", note)
+	}
+
+	backup, err := os.ReadFile(BackupPath(path, 1))
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("backup = %s; want original contents", backup)
+	}
+
+	var rewritten map[string]any
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &rewritten); err != nil {
+		t.Fatalf("rewritten config is not valid JSON: %v", err)
+	}
+	if rewritten["version"] != float64(CurrentVersion) {
+		t.Errorf("rewritten version = %v; want %d", rewritten["version"], CurrentVersion)
+	}
+
+	// A second run finds nothing to migrate, and so does a load
+	if note, err := MigrateFile(path); note != "" || err != nil {
+		t.Errorf("second MigrateFile() = %q, %v; want nothing to do", note, err)
+	}
+	if _, result, _ := LoadWithValidation(path); len(result.Warnings) != 0 {
+		t.Errorf("load after migrating warnings = %+v; want none", result.Warnings)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietHours is a daily window, in local time, during which desktop and
+// webhook notifications are held back. A window whose end is before its
+// start runs past midnight, and one whose end equals its start lasts all
+// day.
+type QuietHours struct {
+	Start string   `json:"start"`          // "HH:MM"; empty disables quiet hours
+	End   string   `json:"end"`            // "HH:MM"
+	Days  []string `json:"days,omitempty"` // days the window starts on, "mon" to "sun"; empty is every day
+}
+
+// weekdays maps the names used in QuietHours.Days to days
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("must be a time of day such as 22:00")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Active reports whether t falls inside the quiet hours
+func (q QuietHours) Active(t time.Time) bool {
+	if q.Start == "" || q.End == "" {
+		return false
+	}
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	switch {
+	case start == end:
+		return q.startsOn(t)
+	case start < end:
+		return now >= start && now < end && q.startsOn(t)
+	case now >= start:
+		return q.startsOn(t)
+	case now < end:
+		// The early hours of a window that started the day before
+		return q.startsOn(t.AddDate(0, 0, -1))
+	}
+	return false
+}
+
+// startsOn reports whether the window opens on t's day
+func (q QuietHours) startsOn(t time.Time) bool {
+	if len(q.Days) == 0 {
+		return true
+	}
+	for _, d := range q.Days {
+		if day, ok := weekdays[d]; ok && day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// Quiet reports whether webhook and desktop notifications are held back at
+// t: while muted, or inside quiet hours or any quiet schedule
+func (n NotificationSettings) Quiet(t time.Time) bool {
+	if n.Muted || n.QuietHours.Active(t) {
+		return true
+	}
+	for _, q := range n.QuietSchedules {
+		if q.Active(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Active(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, _ := time.Parse("15:04", clock)
+		return t
+	}
+	overnight := QuietHours{Start: "22:00", End: "07:00"}
+	daytime := QuietHours{Start: "12:00", End: "13:00"}
+
+	tests := []struct {
+		quiet QuietHours
+		clock string
+		want  bool
+	}{
+		{overnight, "23:30", true},
+		{overnight, "06:59", true},
+		{overnight, "07:00", false},
+		{overnight, "12:00", false},
+		{daytime, "12:30", true},
+		{daytime, "13:00", false},
+		{QuietHours{}, "12:30", false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Active(at(tt.clock)); got != tt.want {
+			t.Errorf("%+v.Active(%s) = %v; want %v", tt.quiet, tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHours_Days(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day int, clock string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", "2026-10-"+strconv.Itoa(day)+" "+clock)
+		return t
+	}
+	fridayNights := QuietHours{Start: "22:00", End: "08:00", Days: []string{"fri"}}
+	weekends := QuietHours{Start: "00:00", End: "00:00", Days: []string{"sat", "sun"}}
+
+	tests := []struct {
+		quiet QuietHours
+		at    time.Time
+		want  bool
+	}{
+		{fridayNights, at(16, "23:00"), true},
+		{fridayNights, at(17, "07:00"), true}, // Saturday morning, still Friday night's window
+		{fridayNights, at(17, "23:00"), false},
+		{fridayNights, at(16, "07:00"), false}, // Friday morning belongs to Thursday night
+		{weekends, at(17, "12:00"), true},
+		{weekends, at(18, "00:00"), true},
+		{weekends, at(19, "09:00"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Active(tt.at); got != tt.want {
+			t.Errorf("%+v.Active(%s) = %v; want %v", tt.quiet, tt.at.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestNotificationSettings_Quiet(t *testing.T) {
+	noon, _ := time.Parse("15:04", "12:30")
+	n := NotificationSettings{QuietSchedules: []QuietHours{{Start: "09:00", End: "10:00"}}}
+	if n.Quiet(noon) {
+		t.Error("quiet outside every window")
+	}
+	n.QuietSchedules = append(n.QuietSchedules, QuietHours{Start: "12:00", End: "13:00"})
+	if !n.Quiet(noon) {
+		t.Error("not quiet inside the second schedule")
+	}
+	if !(NotificationSettings{Muted: true}).Quiet(noon) {
+		t.Error("not quiet while muted")
+	}
+}
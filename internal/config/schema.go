@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CurrentVersion is the config schema version written by this build.
+// Older files are migrated on load; see migrations.
+const CurrentVersion = 2
+
+// checkSchema compares a decoded config file against the Config type and
+// reports type mismatches as errors and unrecognised keys as warnings, each
+// with its full dotted key path.
+func checkSchema(raw map[string]any, r *ValidationResult) {
+	checkValue("", raw, reflect.TypeOf(Config{}), r)
+}
+
+func checkValue(path string, value any, t reflect.Type, r *ValidationResult) {
+	if value == nil {
+		return
+	}
+
+	if t == reflect.TypeOf(KeyList{}) {
+		if _, ok := value.(string); ok {
+			return
+		}
+		checkValue(path, value, reflect.TypeOf([]string{}), r)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			addTypeError(r, path, "an object", value)
+			return
+		}
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, known := fields[key]
+			if !known {
+				section, name := splitPath(joinPath(path, key))
+				r.AddWarning(section, name, "unknown key; it will be ignored", nil)
+				continue
+			}
+			checkValue(joinPath(path, key), obj[key], field, r)
+		}
+
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			addTypeError(r, path, "an object", value)
+			return
+		}
+		for key, item := range obj {
+			checkValue(joinPath(path, key), item, t.Elem(), r)
+		}
+
+	case reflect.Slice:
+		list, ok := value.([]any)
+		if !ok {
+			addTypeError(r, path, "a list", value)
+			return
+		}
+		for i, item := range list {
+			checkValue(fmt.Sprintf("%s[%d]", path, i), item, t.Elem(), r)
+		}
+
+	case reflect.Pointer:
+		checkValue(path, value, t.Elem(), r)
+
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			addTypeError(r, path, "a string", value)
+		}
+
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			addTypeError(r, path, "true or false", value)
+		}
+
+	case reflect.Int, reflect.Int64:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			addTypeError(r, path, "a whole number", value)
+		}
+
+	case reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			addTypeError(r, path, "a number", value)
+		}
+	}
+}
+
+// jsonFields maps JSON keys to field types for a struct type.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func addTypeError(r *ValidationResult, path, expected string, got any) {
+	section, name := splitPath(path)
+	r.AddError(section, name, fmt.Sprintf("expected %s, got %s", expected, describeJSON(got)), got)
+}
+
+// describeJSON names the JSON type of a decoded value.
+func describeJSON(v any) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "an object"
+	}
+	return "null"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// splitPath splits a dotted key path into a section and field for
+// ValidationResult, e.g. "agents.claude.args" -> "agents.claude", "args".
+func splitPath(path string) (string, string) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return path, ""
+	}
+	return path[:i], path[i+1:]
+}
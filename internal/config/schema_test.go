@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func keyPath(e ValidationError) string {
+	if e.Field == "" {
+		return e.Section
+	}
+	return e.Section + "." + e.Field
+}
+
+func TestCheckSchema(t *testing.T) {
+	var raw map[string]any
+	err := json.Unmarshal([]byte(`{
+		"version": 2,
+		"ui": {"column_width": "wide", "sidebar_visible": "yes", "colum_height": 3},
+		"agents": {"claude": {"command": "claude", "args": "--verbose", "cost_per_hour": 1.5}},
+		"defaults": {"slug_max_length": 4.5},
+		"hooks": {"ticket.moved": [1]},
+		"keybindings": {"bindings": {"quit": "Q", "filter": ["/", 2]}},
+		"extra": true
+	}`), &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ValidationResult{}
+	checkSchema(raw, r)
+
+	wantErrors := map[string]string{
+		"ui.column_width":                "expected a whole number, got a string",
+		"ui.sidebar_visible":             "expected true or false, got a string",
+		"agents.claude.args":             "expected a list, got a string",
+		"defaults.slug_max_length":       "expected a whole number, got a number",
+		"hooks.ticket.moved[0]":          "expected a string, got a number",
+		"keybindings.bindings.filter[1]": "expected a string, got a number",
+	}
+	got := make(map[string]string)
+	for _, e := range r.Errors {
+		got[keyPath(e)] = e.Message
+	}
+	for path, msg := range wantErrors {
+		if got[path] != msg {
+			t.Errorf("error at %s = %q; want %q", path, got[path], msg)
+		}
+	}
+	if len(r.Errors) != len(wantErrors) {
+		t.Errorf("got %d errors; want %d: %+v", len(r.Errors), len(wantErrors), r.Errors)
+	}
+
+	warnings := make(map[string]bool)
+	for _, w := range r.Warnings {
+		warnings[keyPath(w)] = true
+	}
+	if !warnings["ui.colum_height"] || !warnings["extra"] || len(r.Warnings) != 2 {
+		t.Errorf("warnings = %+v; want unknown keys ui.colum_height and extra", r.Warnings)
+	}
+}
+
+func TestCheckSchema_DefaultConfigIsValid(t *testing.T) {
+	data, err := json.Marshal(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ValidationResult{}
+	checkSchema(raw, r)
+	if r.HasErrors() || r.HasWarnings() {
+		t.Errorf("default config does not match schema: %+v %+v", r.Errors, r.Warnings)
+	}
+}
+
+func TestLoadWithValidation_ReportsAllTypeErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"version": 2, "ui": {"column_width": "wide", "ticket_height": "tall"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, result, err := LoadWithValidation(path)
+	if err == nil {
+		t.Fatal("LoadWithValidation() should fail on type errors")
+	}
+	if result == nil || len(result.Errors) != 2 {
+		t.Fatalf("result = %+v; want two type errors", result)
+	}
+	if result.Errors[0].Section != "ui" || result.Errors[0].Field != "column_width" {
+		t.Errorf("first error = %+v; want ui.column_width", result.Errors[0])
+	}
+}
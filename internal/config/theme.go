@@ -388,11 +388,31 @@ var BuiltinThemes = map[string]Theme{
 			Info:      "#35a77c",
 		},
 	},
+	// High contrast: pure black and white with the Okabe-Ito palette, whose
+	// hues stay apart for the common kinds of color blindness
+	"high-contrast": {
+		Name: "High Contrast",
+		Colors: ThemeColors{
+			Base:      "#000000",
+			Surface:   "#1c1c1c",
+			Overlay:   "#3a3a3a",
+			Text:      "#ffffff",
+			Subtext:   "#e0e0e0",
+			Muted:     "#a8a8a8",
+			Primary:   "#56b4e9",
+			Secondary: "#cc79a7",
+			Success:   "#009e73",
+			Warning:   "#f0e442",
+			Error:     "#d55e00",
+			Info:      "#e69f00",
+		},
+	},
 }
 
-// ThemeNames returns a sorted list of all available theme names
+// ThemeNames returns all available theme names: built-in themes first,
+// followed by user themes sorted by name
 func ThemeNames() []string {
-	return []string{
+	return append([]string{
 		"catppuccin-mocha",
 		"catppuccin-macchiato",
 		"catppuccin-frappe",
@@ -413,12 +433,13 @@ func ThemeNames() []string {
 		"kanagawa",
 		"everforest-dark",
 		"everforest-light",
-	}
+		"high-contrast",
+	}, userThemeNames()...)
 }
 
 // GetTheme returns a theme by name, with optional custom color overrides
 func GetTheme(name string, customColors *ThemeColors) Theme {
-	theme, exists := BuiltinThemes[name]
+	theme, exists := lookupTheme(name)
 	if !exists {
 		// Fall back to catppuccin-mocha
 		theme = BuiltinThemes["catppuccin-mocha"]
@@ -468,6 +489,6 @@ func GetTheme(name string, customColors *ThemeColors) Theme {
 
 // IsValidTheme checks if a theme name is valid
 func IsValidTheme(name string) bool {
-	_, exists := BuiltinThemes[name]
+	_, exists := lookupTheme(name)
 	return exists
 }
@@ -0,0 +1,438 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Theme import formats
+const (
+	FormatBase16    = "base16"
+	FormatITerm     = "iterm"
+	FormatAlacritty = "alacritty"
+)
+
+// ImportTheme converts a base16 scheme (YAML), an iTerm2 .itermcolors file
+// or an alacritty color config (YAML or TOML) into a Theme. It also returns
+// the detected format.
+func ImportTheme(path string) (Theme, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, "", err
+	}
+
+	format := detectThemeFormat(path, data)
+	var theme Theme
+	switch format {
+	case FormatITerm:
+		theme, err = parseITermColors(data)
+	case FormatAlacritty:
+		theme, err = parseAlacrittyColors(data)
+	default:
+		theme, err = parseBase16(data)
+	}
+	if err != nil {
+		return Theme{}, format, fmt.Errorf("%s: %w", format, err)
+	}
+
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := theme.Colors.validate(); err != nil {
+		return Theme{}, format, fmt.Errorf("%s: %w", format, err)
+	}
+	return theme, format, nil
+}
+
+func detectThemeFormat(path string, data []byte) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".itermcolors" || bytes.Contains(data, []byte("<plist")):
+		return FormatITerm
+	case ext == ".toml" || bytes.Contains(data, []byte("[colors")):
+		return FormatAlacritty
+	case bytes.Contains(data, []byte("base00")):
+		return FormatBase16
+	case bytes.Contains(data, []byte("colors:")):
+		return FormatAlacritty
+	}
+	return FormatBase16
+}
+
+// ThemeKey turns a theme name into the file name it is installed under,
+// e.g. "Tomorrow Night" -> "tomorrow-night".
+func ThemeKey(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// InstallTheme writes theme to ThemesDir as <key>.json. Existing files are
+// only replaced when overwrite is set; built-in names are always refused.
+func InstallTheme(key string, theme Theme, overwrite bool) (string, error) {
+	if key == "" {
+		return "", errors.New("theme name is empty")
+	}
+	if _, exists := BuiltinThemes[key]; exists {
+		return "", fmt.Errorf("%q is a built-in theme; choose another name", key)
+	}
+
+	dir, err := ThemesDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create themes directory: %w", err)
+	}
+
+	path := filepath.Join(dir, key+".json")
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", fmt.Errorf("theme %q already exists (use --force to replace it)", key)
+	}
+
+	data, err := json.MarshalIndent(theme, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write theme: %w", err)
+	}
+	return path, nil
+}
+
+// parseBase16 reads a base16 scheme. Both the classic flat layout
+// (scheme: ..., base00: ...) and the newer one with a palette section are
+// accepted, since values are looked up by key regardless of nesting.
+func parseBase16(data []byte) (Theme, error) {
+	values := parseSimpleYAML(data)
+
+	color := func(key string) (string, error) {
+		for k, v := range values {
+			if strings.EqualFold(k[strings.LastIndex(k, ".")+1:], key) {
+				return normalizeHex(v)
+			}
+		}
+		return "", fmt.Errorf("%s is missing", key)
+	}
+
+	palette := make(map[string]string)
+	for _, key := range []string{"base00", "base01", "base02", "base03", "base04", "base05", "base08", "base0A", "base0B", "base0C", "base0D", "base0E"} {
+		c, err := color(key)
+		if err != nil {
+			return Theme{}, err
+		}
+		palette[key] = c
+	}
+
+	name := values["scheme"]
+	if name == "" {
+		name = values["name"]
+	}
+
+	return Theme{
+		Name: name,
+		Colors: ThemeColors{
+			Base:      palette["base00"],
+			Surface:   palette["base01"],
+			Overlay:   palette["base02"],
+			Text:      palette["base05"],
+			Subtext:   palette["base04"],
+			Muted:     palette["base03"],
+			Primary:   palette["base0D"],
+			Secondary: palette["base0E"],
+			Success:   palette["base0B"],
+			Warning:   palette["base0A"],
+			Error:     palette["base08"],
+			Info:      palette["base0C"],
+		},
+	}, nil
+}
+
+// terminalPalette is the subset of a terminal color scheme a theme is built
+// from. ANSI colors use their index (0 black, 1 red, ... 8 bright black).
+type terminalPalette struct {
+	background string
+	foreground string
+	selection  string
+	ansi       [16]string
+}
+
+func (p terminalPalette) theme() (Theme, error) {
+	if p.background == "" || p.foreground == "" {
+		return Theme{}, errors.New("background and foreground colors are required")
+	}
+	for _, i := range []int{1, 2, 3, 4, 5, 6} {
+		if p.ansi[i] == "" {
+			return Theme{}, fmt.Errorf("ANSI color %d is missing", i)
+		}
+	}
+
+	muted := p.ansi[8]
+	if muted == "" {
+		muted = mixHex(p.foreground, p.background, 0.5)
+	}
+	overlay := p.selection
+	if overlay == "" {
+		overlay = mixHex(p.background, p.foreground, 0.2)
+	}
+
+	return Theme{
+		Colors: ThemeColors{
+			Base:      p.background,
+			Surface:   mixHex(p.background, p.foreground, 0.1),
+			Overlay:   overlay,
+			Text:      p.foreground,
+			Subtext:   mixHex(p.foreground, p.background, 0.2),
+			Muted:     muted,
+			Primary:   p.ansi[4],
+			Secondary: p.ansi[5],
+			Success:   p.ansi[2],
+			Warning:   p.ansi[3],
+			Error:     p.ansi[1],
+			Info:      p.ansi[6],
+		},
+	}, nil
+}
+
+// parseITermColors reads an iTerm2 .itermcolors property list
+func parseITermColors(data []byte) (Theme, error) {
+	var plist struct {
+		Dict struct {
+			Items []plistItem `xml:",any"`
+		} `xml:"dict"`
+	}
+	if err := xml.Unmarshal(data, &plist); err != nil {
+		return Theme{}, fmt.Errorf("invalid property list: %w", err)
+	}
+
+	var p terminalPalette
+	items := plist.Dict.Items
+	for i := 0; i+1 < len(items); i += 2 {
+		if items[i].XMLName.Local != "key" || items[i+1].XMLName.Local != "dict" {
+			continue
+		}
+		var target *string
+		switch name := items[i].Text; name {
+		case "Background Color":
+			target = &p.background
+		case "Foreground Color":
+			target = &p.foreground
+		case "Selection Color":
+			target = &p.selection
+		default:
+			var n int
+			if _, err := fmt.Sscanf(name, "Ansi %d Color", &n); err == nil && n >= 0 && n < 16 {
+				target = &p.ansi[n]
+			}
+		}
+		if target == nil {
+			continue
+		}
+
+		hex, err := items[i+1].color()
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", items[i].Text, err)
+		}
+		*target = hex
+	}
+	return p.theme()
+}
+
+// plistItem is a key or value element inside a plist dict
+type plistItem struct {
+	XMLName xml.Name
+	Text    string      `xml:",chardata"`
+	Items   []plistItem `xml:",any"`
+}
+
+// color converts a dict of Red/Green/Blue Component reals (0-1) to hex
+func (d plistItem) color() (string, error) {
+	var rgb [3]float64
+	var found [3]bool
+	idx := map[string]int{"Red Component": 0, "Green Component": 1, "Blue Component": 2}
+	for i := 0; i+1 < len(d.Items); i += 2 {
+		n, ok := idx[d.Items[i].Text]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(d.Items[i+1].Text), 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s", d.Items[i].Text)
+		}
+		rgb[n], found[n] = v, true
+	}
+	if !found[0] || !found[1] || !found[2] {
+		return "", errors.New("missing color components")
+	}
+	return fmt.Sprintf("#%02x%02x%02x", component(rgb[0]), component(rgb[1]), component(rgb[2])), nil
+}
+
+func component(v float64) int {
+	n := int(v*255 + 0.5)
+	return min(max(n, 0), 255)
+}
+
+// parseAlacrittyColors reads the colors section of an alacritty config in
+// either its TOML or legacy YAML form.
+func parseAlacrittyColors(data []byte) (Theme, error) {
+	var values map[string]string
+	if bytes.Contains(data, []byte("[colors")) {
+		values = parseSimpleTOML(data)
+	} else {
+		values = parseSimpleYAML(data)
+	}
+
+	color := func(key string) string {
+		value, ok := values["colors."+key]
+		if !ok {
+			return ""
+		}
+		hex, err := normalizeHex(value)
+		if err != nil {
+			return ""
+		}
+		return hex
+	}
+
+	names := []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	p := terminalPalette{
+		background: color("primary.background"),
+		foreground: color("primary.foreground"),
+		selection:  color("selection.background"),
+	}
+	for i, name := range names {
+		p.ansi[i] = color("normal." + name)
+		p.ansi[i+8] = color("bright." + name)
+	}
+	return p.theme()
+}
+
+// parseSimpleYAML flattens the mapping entries of a YAML document into
+// dotted keys. It understands only what color schemes use: nested mappings
+// of scalar values, quoting and comments.
+func parseSimpleYAML(data []byte) map[string]string {
+	values := make(map[string]string)
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		key = unquote(strings.TrimSpace(key))
+		full := key
+		if len(stack) > 0 {
+			full = stack[len(stack)-1].key + "." + key
+		}
+
+		value = unquote(stripComment(strings.TrimSpace(value)))
+		if value == "" {
+			stack = append(stack, level{indent: indent, key: full})
+			continue
+		}
+		values[full] = value
+	}
+	return values
+}
+
+// parseSimpleTOML flattens tables and key/value pairs of a TOML document
+// into dotted keys, enough for alacritty color files.
+func parseSimpleTOML(data []byte) map[string]string {
+	values := make(map[string]string)
+	table := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			table = strings.Trim(trimmed, "[] ")
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		full := unquote(strings.TrimSpace(key))
+		if table != "" {
+			full = table + "." + full
+		}
+		values[full] = unquote(stripComment(strings.TrimSpace(value)))
+	}
+	return values
+}
+
+func stripComment(value string) string {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		quote := value[:1]
+		if end := strings.Index(value[1:], quote); end >= 0 {
+			return value[:end+2]
+		}
+		return value
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		return strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// normalizeHex accepts "1d1f21", "#1d1f21" or "0x1d1f21" and returns
+// "#1d1f21".
+func normalizeHex(value string) (string, error) {
+	v := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(value), "0x"), "#")
+	hex := "#" + v
+	if !hexColorPattern.MatchString(hex) {
+		return "", fmt.Errorf("%q is not a hex color", value)
+	}
+	if len(v) == 3 {
+		hex = "#" + string([]byte{v[0], v[0], v[1], v[1], v[2], v[2]})
+	}
+	return hex, nil
+}
+
+// mixHex blends a toward b by weight (0 keeps a, 1 gives b)
+func mixHex(a, b string, weight float64) string {
+	var ar, ag, ab, br, bg, bb int
+	fmt.Sscanf(a, "#%02x%02x%02x", &ar, &ag, &ab)
+	fmt.Sscanf(b, "#%02x%02x%02x", &br, &bg, &bb)
+	mix := func(x, y int) int {
+		return int(float64(x) + (float64(y)-float64(x))*weight + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", mix(ar, br), mix(ag, bg), mix(ab, bb))
+}
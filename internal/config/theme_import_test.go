@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const base16Scheme = `scheme: "Tomorrow Night"
+author: "Chris Kempson"
+base00: "1d1f21"
+base01: "282a2e"
+base02: "373b41"
+base03: "969896" # comments
+base04: "b4b7b4"
+base05: "c5c8c6"
+base06: "e0e0e0"
+base07: "ffffff"
+base08: "cc6666"
+base09: "de935f"
+base0A: "f0c674"
+base0B: "b5bd68"
+base0C: "8abeb7"
+base0D: "81a2be"
+base0E: "b294bb"
+base0F: "a3685a"
+`
+
+const alacrittyTOML = `[colors.primary]
+background = "#1d1f21"
+foreground = "#c5c8c6"
+
+[colors.normal]
+black = "#1d1f21"
+red = "#cc6666"
+green = "#b5bd68"
+yellow = "#f0c674"
+blue = "#81a2be"
+magenta = "#b294bb"
+cyan = "#8abeb7"
+white = "#c5c8c6"
+
+[colors.bright]
+black = "#969896"
+`
+
+const alacrittyYAML = `colors:
+  primary:
+    background: '0x1d1f21'
+    foreground: '0xc5c8c6'
+  normal:
+    red:     '0xcc6666'
+    green:   '0xb5bd68'
+    yellow:  '0xf0c674'
+    blue:    '0x81a2be'
+    magenta: '0xb294bb'
+    cyan:    '0x8abeb7'
+`
+
+func writeImport(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func iTermColors() string {
+	entry := func(name string, r, g, b float64) string {
+		return fmt.Sprintf(`<key>%s</key><dict>
+<key>Alpha Component</key><real>1</real>
+<key>Blue Component</key><real>%g</real>
+<key>Color Space</key><string>sRGB</string>
+<key>Green Component</key><real>%g</real>
+<key>Red Component</key><real>%g</real>
+</dict>`, name, b, g, r)
+	}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><plist version="1.0"><dict>`)
+	b.WriteString(entry("Background Color", 0, 0, 0))
+	b.WriteString(entry("Foreground Color", 1, 1, 1))
+	for i := 1; i <= 6; i++ {
+		b.WriteString(entry(fmt.Sprintf("Ansi %d Color", i), 1, 0, 0.5))
+	}
+	b.WriteString(`<key>Cursor Text Color</key><dict></dict>`)
+	b.WriteString(`</dict></plist>`)
+	return b.String()
+}
+
+func TestImportTheme_Base16(t *testing.T) {
+	theme, format, err := ImportTheme(writeImport(t, "tomorrow.yaml", base16Scheme))
+	if err != nil {
+		t.Fatalf("ImportTheme() error = %v", err)
+	}
+	if format != FormatBase16 || theme.Name != "Tomorrow Night" {
+		t.Errorf("format = %q, name = %q", format, theme.Name)
+	}
+	if theme.Colors.Base != "#1d1f21" || theme.Colors.Muted != "#969896" || theme.Colors.Warning != "#f0c674" {
+		t.Errorf("unexpected colors: %+v", theme.Colors)
+	}
+}
+
+func TestImportTheme_Base16Palette(t *testing.T) {
+	scheme := "system: base16\nname: Nested\npalette:\n"
+	for _, line := range strings.Split(base16Scheme, "\n") {
+		if strings.HasPrefix(line, "base") {
+			scheme += "  " + strings.Replace(line, `: "`, `: "#`, 1) + "\n"
+		}
+	}
+
+	theme, _, err := ImportTheme(writeImport(t, "nested.yaml", scheme))
+	if err != nil {
+		t.Fatalf("ImportTheme() error = %v", err)
+	}
+	if theme.Name != "Nested" || theme.Colors.Primary != "#81a2be" {
+		t.Errorf("theme = %+v", theme)
+	}
+}
+
+func TestImportTheme_Alacritty(t *testing.T) {
+	for name, content := range map[string]string{"colors.toml": alacrittyTOML, "colors.yml": alacrittyYAML} {
+		theme, format, err := ImportTheme(writeImport(t, name, content))
+		if err != nil {
+			t.Fatalf("%s: ImportTheme() error = %v", name, err)
+		}
+		if format != FormatAlacritty {
+			t.Errorf("%s: format = %q", name, format)
+		}
+		if theme.Name != "colors" || theme.Colors.Error != "#cc6666" || theme.Colors.Text != "#c5c8c6" {
+			t.Errorf("%s: theme = %+v", name, theme)
+		}
+		if theme.Colors.Surface == theme.Colors.Base {
+			t.Errorf("%s: surface should be derived from background and foreground", name)
+		}
+	}
+}
+
+func TestImportTheme_ITerm(t *testing.T) {
+	theme, format, err := ImportTheme(writeImport(t, "Mine.itermcolors", iTermColors()))
+	if err != nil {
+		t.Fatalf("ImportTheme() error = %v", err)
+	}
+	if format != FormatITerm || theme.Name != "Mine" {
+		t.Errorf("format = %q, name = %q", format, theme.Name)
+	}
+	if theme.Colors.Base != "#000000" || theme.Colors.Text != "#ffffff" || theme.Colors.Primary != "#ff0080" {
+		t.Errorf("unexpected colors: %+v", theme.Colors)
+	}
+	if theme.Colors.Muted != "#808080" {
+		t.Errorf("Muted = %q; want midpoint when bright black is missing", theme.Colors.Muted)
+	}
+}
+
+func TestImportTheme_Incomplete(t *testing.T) {
+	_, _, err := ImportTheme(writeImport(t, "short.yaml", "scheme: Short\nbase00: \"000000\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "base01 is missing") {
+		t.Errorf("ImportTheme() error = %v; want missing base01", err)
+	}
+}
+
+func TestInstallTheme(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	theme, _, err := ImportTheme(writeImport(t, "tomorrow.yaml", base16Scheme))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := ThemeKey(theme.Name)
+	if key != "tomorrow-night" {
+		t.Errorf("ThemeKey() = %q; want tomorrow-night", key)
+	}
+	if _, err := InstallTheme(key, theme, false); err != nil {
+		t.Fatalf("InstallTheme() error = %v", err)
+	}
+	if _, err := InstallTheme(key, theme, false); err == nil {
+		t.Error("InstallTheme() should refuse to replace an existing theme")
+	}
+	if _, err := InstallTheme(key, theme, true); err != nil {
+		t.Errorf("InstallTheme(overwrite) error = %v", err)
+	}
+	if _, err := InstallTheme("nord", theme, true); err == nil {
+		t.Error("InstallTheme() should refuse built-in names")
+	}
+
+	dir, _ := ThemesDir()
+	themes, errs := LoadUserThemes(dir)
+	if len(errs) != 0 || themes[key].Colors.Info != "#8abeb7" {
+		t.Errorf("installed theme did not load: %v %v", themes, errs)
+	}
+}
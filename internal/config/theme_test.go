@@ -8,8 +8,8 @@ import (
 func TestThemeNames(t *testing.T) {
 	names := ThemeNames()
 
-	if len(names) != 20 {
-		t.Errorf("ThemeNames() returned %d themes; want 20", len(names))
+	if len(names) != 21 {
+		t.Errorf("ThemeNames() returned %d themes; want 21", len(names))
 	}
 
 	for _, name := range names {
@@ -235,8 +235,8 @@ func TestBuiltinThemes_AllHaveRequiredColors(t *testing.T) {
 }
 
 func TestBuiltinThemes_Count(t *testing.T) {
-	if len(BuiltinThemes) != 20 {
-		t.Errorf("BuiltinThemes has %d themes; want 20", len(BuiltinThemes))
+	if len(BuiltinThemes) != 21 {
+		t.Errorf("BuiltinThemes has %d themes; want 21", len(BuiltinThemes))
 	}
 }
 
@@ -262,6 +262,7 @@ func TestBuiltinThemes_ExpectedThemesExist(t *testing.T) {
 		"kanagawa",
 		"everforest-dark",
 		"everforest-light",
+		"high-contrast",
 	}
 
 	for _, name := range expectedThemes {
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+var (
+	userThemesMu sync.RWMutex
+	userThemes   = map[string]Theme{}
+)
+
+// ThemeFileError describes a theme file in the themes directory that could
+// not be loaded.
+type ThemeFileError struct {
+	File    string
+	Message string
+}
+
+func (e *ThemeFileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// ThemesDir returns the directory user themes are loaded from
+func ThemesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// LoadUserThemes reads every *.json file in dir as a Theme, keyed by file
+// name without extension. Invalid files are skipped and reported as errors.
+// A missing directory yields no themes and no errors.
+func LoadUserThemes(dir string) (map[string]Theme, []error) {
+	themes := make(map[string]Theme)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return themes, []error{err}
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		file := filepath.Base(path)
+		key := strings.TrimSuffix(file, filepath.Ext(file))
+
+		if _, exists := BuiltinThemes[key]; exists {
+			errs = append(errs, &ThemeFileError{File: file, Message: fmt.Sprintf("%q is a built-in theme; rename the file", key)})
+			continue
+		}
+
+		theme, err := readThemeFile(path)
+		if err != nil {
+			errs = append(errs, &ThemeFileError{File: file, Message: err.Error()})
+			continue
+		}
+		if theme.Name == "" {
+			theme.Name = key
+		}
+		themes[key] = theme
+	}
+	return themes, errs
+}
+
+func readThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		if msg := formatJSONError(err); msg != "" {
+			return Theme{}, errors.New(msg)
+		}
+		return Theme{}, err
+	}
+
+	if err := theme.Colors.validate(); err != nil {
+		return Theme{}, err
+	}
+	return theme, nil
+}
+
+type colorField struct {
+	name  string
+	value string
+}
+
+// fields lists the colors with their JSON names, in declaration order
+func (c ThemeColors) fields() []colorField {
+	return []colorField{
+		{"base", c.Base},
+		{"surface", c.Surface},
+		{"overlay", c.Overlay},
+		{"text", c.Text},
+		{"subtext", c.Subtext},
+		{"muted", c.Muted},
+		{"primary", c.Primary},
+		{"secondary", c.Secondary},
+		{"success", c.Success},
+		{"warning", c.Warning},
+		{"error", c.Error},
+		{"info", c.Info},
+	}
+}
+
+// AccentColorNames are the theme colors a column or ticket can be tagged
+// with, in the order the tag cycles through them
+var AccentColorNames = []string{"primary", "secondary", "success", "warning", "error", "info"}
+
+// Lookup returns the color a theme color name stands for
+func (c ThemeColors) Lookup(name string) (string, bool) {
+	for _, f := range c.fields() {
+		if f.name == name {
+			return f.value, true
+		}
+	}
+	return "", false
+}
+
+// IsColorRef reports whether value names a theme color or is a hex color
+func IsColorRef(value string) bool {
+	if hexColorPattern.MatchString(value) {
+		return true
+	}
+	_, ok := ThemeColors{}.Lookup(value)
+	return ok
+}
+
+// ThemeColorNames lists the keys of ThemeColors in display order
+func ThemeColorNames() []string {
+	fields := ThemeColors{}.fields()
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// validate checks that every color is set to a #rgb or #rrggbb value.
+func (c ThemeColors) validate() error {
+	var problems []string
+	for _, f := range c.fields() {
+		switch {
+		case f.value == "":
+			problems = append(problems, fmt.Sprintf("colors.%s is missing", f.name))
+		case !hexColorPattern.MatchString(f.value):
+			problems = append(problems, fmt.Sprintf("colors.%s %q is not a hex color (use #rgb or #rrggbb)", f.name, f.value))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// SetUserThemes replaces the themes available in addition to BuiltinThemes
+func SetUserThemes(themes map[string]Theme) {
+	userThemesMu.Lock()
+	defer userThemesMu.Unlock()
+	userThemes = themes
+}
+
+// loadUserThemes installs the themes found in ThemesDir and returns any
+// problems with individual files.
+func loadUserThemes() []error {
+	dir, err := ThemesDir()
+	if err != nil {
+		return nil
+	}
+	themes, errs := LoadUserThemes(dir)
+	SetUserThemes(themes)
+	return errs
+}
+
+// addThemeWarnings reports theme files that were skipped. They are warnings
+// rather than errors so a broken theme never prevents the board from starting.
+func addThemeWarnings(r *ValidationResult, errs []error) {
+	for _, err := range errs {
+		var fileErr *ThemeFileError
+		if errors.As(err, &fileErr) {
+			r.AddWarning("themes", fileErr.File, fileErr.Message+"; theme skipped", nil)
+		} else {
+			r.AddWarning("themes", "", err.Error(), nil)
+		}
+	}
+}
+
+func userThemeNames() []string {
+	userThemesMu.RLock()
+	defer userThemesMu.RUnlock()
+
+	names := make([]string, 0, len(userThemes))
+	for name := range userThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTheme finds a built-in or user theme by name
+func lookupTheme(name string) (Theme, bool) {
+	if theme, exists := BuiltinThemes[name]; exists {
+		return theme, true
+	}
+
+	userThemesMu.RLock()
+	defer userThemesMu.RUnlock()
+	theme, exists := userThemes[name]
+	return theme, exists
+}
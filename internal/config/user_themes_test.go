@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validThemeJSON = `{
+  "name": "My Theme",
+  "colors": {
+    "base": "#101010", "surface": "#202020", "overlay": "#303030",
+    "text": "#f0f0f0", "subtext": "#d0d0d0", "muted": "#909090",
+    "primary": "#5599ff", "secondary": "#ff55aa", "success": "#55ff55",
+    "warning": "#ffaa00", "error": "#ff5555", "info": "#0af"
+  }
+}`
+
+func writeTheme(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadUserThemes(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, dir, "mine.json", validThemeJSON)
+	writeTheme(t, dir, "bad-hex.json", strings.Replace(validThemeJSON, `"#5599ff"`, `"blue"`, 1))
+	writeTheme(t, dir, "broken.json", `{"name": `)
+	writeTheme(t, dir, "nord.json", validThemeJSON)
+	writeTheme(t, dir, "notes.txt", "ignored")
+
+	themes, errs := LoadUserThemes(dir)
+
+	if len(themes) != 1 {
+		t.Fatalf("LoadUserThemes() loaded %d themes; want 1", len(themes))
+	}
+	if themes["mine"].Name != "My Theme" {
+		t.Errorf("themes[mine].Name = %q; want %q", themes["mine"].Name, "My Theme")
+	}
+
+	if len(errs) != 3 {
+		t.Fatalf("LoadUserThemes() returned %d errors; want 3: %v", len(errs), errs)
+	}
+	joined := ""
+	for _, err := range errs {
+		joined += err.Error() + "\n"
+	}
+	for _, want := range []string{
+		`bad-hex.json: colors.primary "blue" is not a hex color`,
+		"broken.json:",
+		`nord.json: "nord" is a built-in theme`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("errors missing %q; got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestLoadUserThemes_MissingColorAndDefaultName(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, dir, "nameless.json", strings.Replace(validThemeJSON, `"name": "My Theme",`, "", 1))
+	writeTheme(t, dir, "partial.json", `{"colors": {"base": "#000000"}}`)
+
+	themes, errs := LoadUserThemes(dir)
+
+	if themes["nameless"].Name != "nameless" {
+		t.Errorf("theme without name got Name %q; want file name", themes["nameless"].Name)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "colors.surface is missing") {
+		t.Errorf("LoadUserThemes() errors = %v; want missing colors for partial.json", errs)
+	}
+}
+
+func TestLoadUserThemes_MissingDir(t *testing.T) {
+	themes, errs := LoadUserThemes(filepath.Join(t.TempDir(), "nope"))
+	if len(themes) != 0 || len(errs) != 0 {
+		t.Errorf("LoadUserThemes() on missing dir = %v, %v; want nothing", themes, errs)
+	}
+}
+
+func TestUserThemesAreAvailable(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, dir, "mine.json", validThemeJSON)
+	themes, _ := LoadUserThemes(dir)
+
+	SetUserThemes(themes)
+	t.Cleanup(func() { SetUserThemes(map[string]Theme{}) })
+
+	names := ThemeNames()
+	if names[len(names)-1] != "mine" {
+		t.Errorf("ThemeNames() should end with user theme; got %v", names)
+	}
+	if !IsValidTheme("mine") {
+		t.Error("IsValidTheme(\"mine\") = false; want true")
+	}
+	if got := GetTheme("mine", nil).Colors.Info; got != "#0af" {
+		t.Errorf("GetTheme(\"mine\").Colors.Info = %q; want %q", got, "#0af")
+	}
+}
+
+func TestLoadWithValidation_ThemeWarnings(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("OPENKANBAN_CONFIG_DIR", dir)
+	t.Cleanup(func() { SetUserThemes(map[string]Theme{}) })
+
+	if err := os.MkdirAll(filepath.Join(dir, "themes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTheme(t, filepath.Join(dir, "themes"), "mine.json", validThemeJSON)
+	writeTheme(t, filepath.Join(dir, "themes"), "broken.json", "{")
+	writeTheme(t, dir, "config.json", `{"version": 2, "ui": {"theme": "mine"}}`)
+
+	cfg, result, err := LoadWithValidation("")
+	if err != nil {
+		t.Fatalf("LoadWithValidation() error = %v", err)
+	}
+	if cfg.GetTheme().Name != "My Theme" {
+		t.Errorf("GetTheme().Name = %q; want user theme", cfg.GetTheme().Name)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Section != "themes" || result.Warnings[0].Field != "broken.json" {
+		t.Errorf("warnings = %+v; want one for themes/broken.json", result.Warnings)
+	}
+}
@@ -2,9 +2,18 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net/mail"
+	"net/url"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"text/template"
+	"unicode"
+
+	"github.com/techdufus/openkanban/internal/events"
 )
 
 // ValidationError represents a single config validation issue
@@ -94,6 +103,23 @@ func (c *Config) Validate() *ValidationResult {
 	c.validateAgents(result)
 	c.validateUI(result)
 	c.validateOpencode(result)
+	c.validatePullRequest(result)
+	c.validateJira(result)
+	c.validateLinear(result)
+	c.validateCleanup(result)
+	c.validateBehavior(result)
+	c.validateHooks(result)
+	c.validateCommitRefs(result)
+	c.validateTmux(result)
+	c.validateVault(result)
+	c.validateDigest(result)
+	c.validateTelemetry(result)
+	c.validateLogging(result)
+	c.validateStorage(result)
+	c.validateSync(result)
+	c.validateNotifications(result)
+	c.validateQuietHours(result)
+	c.validateKeybindings(result)
 	return result
 }
 
@@ -123,12 +149,22 @@ func (c *Config) validateDefaults(r *ValidationResult) {
 		}
 	}
 
+	// SpawnOn must name columns an agent can work in
+	for _, column := range c.Defaults.SpawnOn {
+		if column != "in_progress" && column != "review" {
+			r.AddError("defaults", "spawn_on",
+				fmt.Sprintf("must list in_progress or review (got %q)", column),
+				column)
+		}
+	}
+
 	// BranchTemplate should contain placeholders (warning only)
 	if c.Defaults.BranchTemplate != "" {
 		if !strings.Contains(c.Defaults.BranchTemplate, "{slug}") &&
-			!strings.Contains(c.Defaults.BranchTemplate, "{prefix}") {
+			!strings.Contains(c.Defaults.BranchTemplate, "{prefix}") &&
+			!strings.Contains(c.Defaults.BranchTemplate, "{key}") {
 			r.AddWarning("defaults", "branch_template",
-				"should contain {slug} or {prefix} placeholder",
+				"should contain {slug}, {prefix} or {key} placeholder",
 				c.Defaults.BranchTemplate)
 		}
 	}
@@ -164,6 +200,60 @@ func (c *Config) validateAgents(r *ValidationResult) {
 					nil)
 			}
 		}
+
+		if agent.ContextFile != "" {
+			if !filepath.IsLocal(agent.ContextFile) {
+				r.AddError(section, "context_file", "must be a path inside the worktree", agent.ContextFile)
+			}
+		} else if len(agent.ContextArgs) > 0 {
+			r.AddWarning(section, "context_args", "has no effect without context_file", nil)
+		}
+		if len(agent.ContextArgs) > 0 && !slices.ContainsFunc(agent.ContextArgs, func(a string) bool { return strings.Contains(a, "{file}") }) {
+			r.AddWarning(section, "context_args", "no argument contains {file}; the agent won't be told where the file is", agent.ContextArgs)
+		}
+
+		switch agent.ContextInput {
+		case "", ContextInputArgs, ContextInputType:
+		default:
+			r.AddError(section, "context_input", `must be "args" or "type"`, agent.ContextInput)
+		}
+		if agent.ReadyPattern != "" {
+			if _, err := regexp.Compile(agent.ReadyPattern); err != nil {
+				r.AddError(section, "ready_pattern", fmt.Sprintf("invalid regexp: %v", err), agent.ReadyPattern)
+			}
+		}
+		if agent.ReadyTimeout < 0 {
+			r.AddError(section, "ready_timeout", "must not be negative", agent.ReadyTimeout)
+		}
+
+		for i, m := range agent.Models {
+			if m.Name == "" {
+				r.AddError(section, fmt.Sprintf("models[%d].name", i), "is required but missing", nil)
+			} else if slices.ContainsFunc(agent.Models[:i], func(o ModelOption) bool { return o.Name == m.Name }) {
+				r.AddWarning(section, "models", "lists a model twice", m.Name)
+			}
+		}
+		if len(agent.ModelArgs) > 0 && !slices.ContainsFunc(agent.ModelArgs, func(a string) bool { return strings.Contains(a, "{model}") }) {
+			r.AddWarning(section, "model_args", "no argument contains {model}; the model won't be passed", agent.ModelArgs)
+		}
+
+		if agent.CostPerHour < 0 {
+			r.AddError(section, "cost_per_hour", "must not be negative", agent.CostPerHour)
+		}
+	}
+}
+
+// validateHooks validates the hooks section
+func (c *Config) validateHooks(r *ValidationResult) {
+	for event, commands := range c.Hooks {
+		if !events.IsValid(event) {
+			r.AddWarning("hooks", event, "unknown event; hook will never run", nil)
+		}
+		for _, command := range commands {
+			if strings.TrimSpace(command) == "" {
+				r.AddError("hooks", event, "command must not be empty", nil)
+			}
+		}
 	}
 }
 
@@ -193,6 +283,85 @@ func (c *Config) validateUI(r *ValidationResult) {
 			"must be a positive number",
 			c.UI.RefreshInterval)
 	}
+
+	switch c.UI.TicketOrder {
+	case "", "created", "updated", "name", "manual":
+	default:
+		r.AddError("ui", "ticket_order",
+			"must be one of: created, updated, name, manual",
+			c.UI.TicketOrder)
+	}
+
+	switch c.UI.ProjectOrder {
+	case "", "name", "created":
+	default:
+		r.AddError("ui", "project_order",
+			"must be one of: name, created",
+			c.UI.ProjectOrder)
+	}
+
+	if c.UI.SplitDirection != "" && c.UI.SplitDirection != "right" && c.UI.SplitDirection != "bottom" {
+		r.AddError("ui", "split_direction",
+			fmt.Sprintf("must be one of: right, bottom (got %q)", c.UI.SplitDirection),
+			c.UI.SplitDirection)
+	}
+
+	if c.UI.SplitRatio < 20 || c.UI.SplitRatio > 80 {
+		r.AddError("ui", "split_ratio",
+			"must be between 20 and 80",
+			c.UI.SplitRatio)
+	}
+
+	switch c.UI.CardDensity {
+	case "", "compact", "normal", "detailed":
+	default:
+		r.AddError("ui", "card_density",
+			fmt.Sprintf("must be one of: compact, normal, detailed (got %q)", c.UI.CardDensity),
+			c.UI.CardDensity)
+	}
+
+	for _, status := range slices.Sorted(maps.Keys(c.UI.ColumnColors)) {
+		color := c.UI.ColumnColors[status]
+		switch status {
+		case "backlog", "in_progress", "review", "done", "archived":
+		default:
+			r.AddError("ui", "column_colors."+status,
+				"must be a column status: backlog, in_progress, review, done or archived",
+				color)
+			continue
+		}
+		if !IsColorRef(color) {
+			r.AddError("ui", "column_colors."+status,
+				fmt.Sprintf("must be a theme color (%s) or a hex color", strings.Join(ThemeColorNames(), ", ")),
+				color)
+		}
+	}
+
+	for i, rule := range c.UI.Highlights {
+		field := fmt.Sprintf("highlights[%d]", i)
+		if _, err := regexp.Compile(rule.Pattern); rule.Pattern == "" || err != nil {
+			r.AddError("ui", field+".pattern", "must be a regular expression", rule.Pattern)
+		}
+		if rule.Color != "" && !IsColorRef(rule.Color) {
+			r.AddError("ui", field+".color",
+				fmt.Sprintf("must be a theme color (%s) or a hex color", strings.Join(ThemeColorNames(), ", ")),
+				rule.Color)
+		}
+		if rule.Color == "" && !rule.Bold && !rule.Underline {
+			r.AddWarning("ui", field, "sets no color, bold or underline; it changes nothing", rule.Pattern)
+		}
+	}
+
+	// Custom colors are optional, but those that are set must be hex
+	if c.UI.CustomColors != nil {
+		for _, f := range c.UI.CustomColors.fields() {
+			if f.value != "" && !hexColorPattern.MatchString(f.value) {
+				r.AddError("ui", "custom_colors."+f.name,
+					"must be a hex color (#rgb or #rrggbb)",
+					f.value)
+			}
+		}
+	}
 }
 
 // validateOpencode validates the opencode server settings
@@ -210,6 +379,297 @@ func (c *Config) validateOpencode(r *ValidationResult) {
 	}
 }
 
+// validateCleanup validates the cleanup settings
+func (c *Config) validateCleanup(r *ValidationResult) {
+	switch c.Cleanup.AfterMerge {
+	case "", "ask", "always", "never":
+	default:
+		r.AddError("cleanup", "after_merge",
+			"must be one of: ask, always, never",
+			c.Cleanup.AfterMerge)
+	}
+	switch c.Cleanup.OnArchive {
+	case "", "ask", "always", "never":
+	default:
+		r.AddError("cleanup", "on_archive",
+			"must be one of: ask, always, never",
+			c.Cleanup.OnArchive)
+	}
+	if c.Cleanup.DiskLimitGB < 0 {
+		r.AddError("cleanup", "disk_limit_gb",
+			"must be zero (no limit) or a positive number",
+			c.Cleanup.DiskLimitGB)
+	}
+}
+
+// validateBehavior validates the behavior settings
+func (c *Config) validateBehavior(r *ValidationResult) {
+	switch c.Behavior.SyncStrategy {
+	case "", "rebase", "merge":
+	default:
+		r.AddError("behavior", "sync_strategy",
+			"must be one of: rebase, merge",
+			c.Behavior.SyncStrategy)
+	}
+
+	if c.Behavior.AutosaveInterval < 0 {
+		r.AddError("behavior", "autosave_interval",
+			"must be 0 or more",
+			c.Behavior.AutosaveInterval)
+	}
+
+	switch c.Behavior.OnApprove {
+	case "", "merge", "pr", "none":
+	default:
+		r.AddError("behavior", "on_approve",
+			"must be one of: merge, pr, none",
+			c.Behavior.OnApprove)
+	}
+}
+
+// validatePullRequest validates the pull request settings
+func (c *Config) validatePullRequest(r *ValidationResult) {
+	switch c.PullRequest.Provider {
+	case "", "auto", "github", "gitlab", "gitea":
+	default:
+		r.AddError("pull_request", "provider",
+			"must be one of: auto, github, gitlab, gitea",
+			c.PullRequest.Provider)
+	}
+	if c.PullRequest.StatusInterval < 0 {
+		r.AddError("pull_request", "status_interval",
+			"must be zero (disabled) or a positive number",
+			c.PullRequest.StatusInterval)
+	}
+}
+
+// validateCommitRefs validates the commit reference prefix
+func (c *Config) validateCommitRefs(r *ValidationResult) {
+	if !c.CommitRefs.Enabled {
+		return
+	}
+	alnum := c.CommitRefs.Prefix != ""
+	for _, ch := range c.CommitRefs.Prefix {
+		alnum = alnum && (unicode.IsLetter(ch) || unicode.IsDigit(ch))
+	}
+	if !alnum {
+		r.AddError("commit_refs", "prefix",
+			"must be letters and digits only, e.g. \"okb\"",
+			c.CommitRefs.Prefix)
+	}
+}
+
+// validateTmux validates the tmux settings
+func (c *Config) validateTmux(r *ValidationResult) {
+	if strings.ContainsAny(c.Tmux.SessionPrefix, ".:") {
+		r.AddError("tmux", "session_prefix",
+			"must not contain '.' or ':', which tmux reads as target separators",
+			c.Tmux.SessionPrefix)
+	}
+}
+
+// validateVault validates the notes folder
+func (c *Config) validateVault(r *ValidationResult) {
+	folder := filepath.Clean(c.Vault.Folder)
+	if filepath.IsAbs(c.Vault.Folder) || folder == ".." || strings.HasPrefix(folder, ".."+string(filepath.Separator)) {
+		r.AddError("vault", "folder", "must be a folder inside the vault", c.Vault.Folder)
+	}
+}
+
+// validateDigest validates the digest period and mail settings
+func (c *Config) validateDigest(r *ValidationResult) {
+	switch c.Digest.Period {
+	case "daily", "weekly":
+	default:
+		r.AddError("digest", "period", "must be daily or weekly", c.Digest.Period)
+	}
+	if c.Digest.StallDays < 0 {
+		r.AddError("digest", "stall_days",
+			"must be zero (disabled) or a positive number",
+			c.Digest.StallDays)
+	}
+	smtp := c.Digest.SMTP
+	if smtp.Port < 0 || smtp.Port > 65535 {
+		r.AddError("digest", "smtp.port", "must be a port number, or 0 for 587", smtp.Port)
+	}
+	if smtp.From != "" {
+		if _, err := mail.ParseAddress(smtp.From); err != nil {
+			r.AddError("digest", "smtp.from", "must be an email address", smtp.From)
+		}
+	}
+	for i, to := range smtp.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			r.AddError("digest", fmt.Sprintf("smtp.to[%d]", i), "must be an email address", to)
+		}
+	}
+}
+
+// validateTelemetry validates the OTLP collector endpoint
+func (c *Config) validateTelemetry(r *ValidationResult) {
+	if c.Telemetry.Endpoint == "" {
+		return
+	}
+	if u, err := url.Parse(c.Telemetry.Endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		r.AddError("telemetry", "endpoint",
+			"must be an http(s) URL such as http://localhost:4318",
+			c.Telemetry.Endpoint)
+	}
+}
+
+// validateLogging validates the log level and rotation
+func (c *Config) validateLogging(r *ValidationResult) {
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		r.AddError("logging", "level", "must be one of: debug, info, warn, error", c.Logging.Level)
+	}
+	if c.Logging.MaxSizeMB < 0 {
+		r.AddError("logging", "max_size_mb", "must not be negative", c.Logging.MaxSizeMB)
+	}
+	if c.Logging.MaxFiles < 0 {
+		r.AddError("logging", "max_files", "must not be negative", c.Logging.MaxFiles)
+	}
+}
+
+// validateStorage validates the ticket encryption
+func (c *Config) validateStorage(r *ValidationResult) {
+	switch c.Storage.Encryption {
+	case "none", "passphrase", "keychain":
+	default:
+		r.AddError("storage", "encryption", "must be one of: none, passphrase, keychain", c.Storage.Encryption)
+	}
+}
+
+// validateSync validates the team sync branch and remote
+func (c *Config) validateSync(r *ValidationResult) {
+	s := c.Sync
+	if s.Interval < 0 {
+		r.AddError("sync", "interval", "must not be negative", s.Interval)
+	}
+	if !s.Enabled {
+		return
+	}
+	if s.Remote == "" {
+		r.AddError("sync", "remote", "is required when sync is enabled", s.Remote)
+	}
+	if s.Branch == "" || strings.ContainsAny(s.Branch, " ~^:?*[\\") || strings.Contains(s.Branch, "..") ||
+		strings.HasPrefix(s.Branch, "-") || strings.HasPrefix(s.Branch, "/") || strings.HasSuffix(s.Branch, "/") {
+		r.AddError("sync", "branch", "must be a valid branch name", s.Branch)
+	}
+	if c.Storage.Encryption == "keychain" {
+		r.AddError("sync", "enabled",
+			"keychain keys stay on one machine; use storage.encryption \"passphrase\" to share an encrypted board",
+			s.Enabled)
+	}
+}
+
+// validateNotifications validates the chat webhooks
+func (c *Config) validateNotifications(r *ValidationResult) {
+	for i, w := range c.Notifications.Webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			r.AddError("notifications", field+".url", "must be an http(s) webhook URL", nil)
+		}
+		switch w.DetectService() {
+		case events.Slack, events.Discord:
+		case "":
+			r.AddError("notifications", field+".service",
+				"cannot tell the service from the URL; set slack or discord", nil)
+		default:
+			r.AddError("notifications", field+".service",
+				"must be one of: slack, discord", w.Service)
+		}
+		for _, e := range w.Events {
+			if !events.IsNotifyEvent(e) {
+				r.AddWarning("notifications", field+".events", "unknown event; it is never posted", e)
+			}
+		}
+		for e, tmpl := range w.Templates {
+			if err := validateTemplate(tmpl); err != nil {
+				r.AddError("notifications", field+".templates."+e,
+					fmt.Sprintf("invalid template: %v", err), tmpl)
+			}
+		}
+	}
+}
+
+// validateQuietHours validates notifications.quiet_hours and
+// notifications.quiet_schedules
+func (c *Config) validateQuietHours(r *ValidationResult) {
+	validateQuietWindow(r, "quiet_hours", c.Notifications.QuietHours)
+	for i, q := range c.Notifications.QuietSchedules {
+		field := fmt.Sprintf("quiet_schedules[%d]", i)
+		if q.Start == "" && q.End == "" {
+			r.AddError("notifications", field, "needs a start and an end", nil)
+			continue
+		}
+		validateQuietWindow(r, field, q)
+	}
+}
+
+func validateQuietWindow(r *ValidationResult, field string, q QuietHours) {
+	if (q.Start == "") != (q.End == "") {
+		r.AddWarning("notifications", field,
+			"set both start and end; quiet hours are off meanwhile", nil)
+	}
+	for _, f := range []struct{ name, value string }{{"start", q.Start}, {"end", q.End}} {
+		if f.value == "" {
+			continue
+		}
+		if _, err := parseClock(f.value); err != nil {
+			r.AddError("notifications", field+"."+f.name, err.Error(), f.value)
+		}
+	}
+	for _, day := range q.Days {
+		if _, ok := weekdays[day]; !ok {
+			r.AddError("notifications", field+".days", "must be days: mon, tue, wed, thu, fri, sat, sun", day)
+		}
+	}
+}
+
+// validateJira validates the Jira settings
+func (c *Config) validateJira(r *ValidationResult) {
+	if c.Jira.URL != "" {
+		if u, err := url.Parse(c.Jira.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			r.AddError("jira", "url",
+				"must be an http(s) URL such as https://acme.atlassian.net",
+				c.Jira.URL)
+		}
+	}
+	if c.Jira.SyncInterval < 0 {
+		r.AddError("jira", "sync_interval",
+			"must be zero (disabled) or a positive number",
+			c.Jira.SyncInterval)
+	}
+	for column := range c.Jira.Transitions {
+		switch column {
+		case "backlog", "in_progress", "review", "done", "archived":
+		default:
+			r.AddError("jira", "transitions."+column,
+				"must be a column: backlog, in_progress, review, done, archived",
+				column)
+		}
+	}
+}
+
+// validateLinear validates the Linear settings
+func (c *Config) validateLinear(r *ValidationResult) {
+	if c.Linear.SyncInterval < 0 {
+		r.AddError("linear", "sync_interval",
+			"must be zero (disabled) or a positive number",
+			c.Linear.SyncInterval)
+	}
+	for column := range c.Linear.States {
+		switch column {
+		case "backlog", "in_progress", "review", "done", "archived":
+		default:
+			r.AddError("linear", "states."+column,
+				"must be a column: backlog, in_progress, review, done, archived",
+				column)
+		}
+	}
+}
+
 // validateTemplate checks if a string is a valid Go template
 func validateTemplate(tmpl string) error {
 	_, err := template.New("check").Parse(tmpl)
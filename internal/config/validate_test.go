@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/techdufus/openkanban/internal/events"
 )
 
 func TestValidate_ValidDefaultConfig(t *testing.T) {
@@ -59,6 +63,23 @@ func TestValidate_NegativeSlugMaxLength(t *testing.T) {
 	}
 }
 
+func TestValidate_SpawnOn(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Defaults.SpawnOn = []string{"in_progress", "review", "backlog"}
+
+	result := cfg.Validate()
+
+	var errs []string
+	for _, e := range result.Errors {
+		if e.Section == "defaults" && e.Field == "spawn_on" {
+			errs = append(errs, e.Message)
+		}
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0], `"backlog"`) {
+		t.Errorf("spawn_on errors = %q; want one for backlog", errs)
+	}
+}
+
 func TestValidate_NonexistentDefaultAgent(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Defaults.DefaultAgent = "nonexistent-agent"
@@ -285,6 +306,115 @@ func TestValidate_ZeroUIRefreshInterval(t *testing.T) {
 	}
 }
 
+func TestValidate_CustomColors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.CustomColors = &ThemeColors{Primary: "#ff0000", Error: "red"}
+
+	result := cfg.Validate()
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected one error, got %+v", result.Errors)
+	}
+	if e := result.Errors[0]; e.Section != "ui" || e.Field != "custom_colors.error" {
+		t.Errorf("error = %+v; want ui.custom_colors.error", e)
+	}
+}
+
+func TestValidate_ColumnColors(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.ColumnColors = map[string]string{
+		"backlog":     "info",
+		"done":        "#8be9fd",
+		"in_progress": "orange",
+		"qa":          "warning",
+	}
+
+	result := cfg.Validate()
+
+	var fields []string
+	for _, e := range result.Errors {
+		fields = append(fields, e.Field)
+	}
+	if len(fields) != 2 || fields[0] != "column_colors.in_progress" || fields[1] != "column_colors.qa" {
+		t.Errorf("errors on %v; want column_colors.in_progress and column_colors.qa", fields)
+	}
+}
+
+func TestValidate_Highlights(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.Highlights = []HighlightRule{
+		{Pattern: `(?i)\berror\b`, Color: "error", Bold: true},
+		{Pattern: `[\w./-]+\.go:\d+`, Underline: true},
+		{Pattern: `warn(`, Color: "warning"},
+		{Pattern: `todo`, Color: "orange"},
+		{Pattern: `note`},
+	}
+
+	result := cfg.Validate()
+
+	var fields []string
+	for _, e := range result.Errors {
+		fields = append(fields, e.Field)
+	}
+	if len(fields) != 2 || fields[0] != "highlights[2].pattern" || fields[1] != "highlights[3].color" {
+		t.Errorf("errors on %v; want highlights[2].pattern and highlights[3].color", fields)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Field != "highlights[4]" {
+		t.Errorf("warnings = %v; want one on highlights[4]", result.Warnings)
+	}
+}
+
+func TestValidate_Split(t *testing.T) {
+	tests := []struct {
+		direction string
+		ratio     int
+		field     string
+	}{
+		{"right", 60, ""},
+		{"bottom", 20, ""},
+		{"left", 60, "split_direction"},
+		{"right", 90, "split_ratio"},
+		{"bottom", 0, "split_ratio"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.UI.SplitDirection = tt.direction
+		cfg.UI.SplitRatio = tt.ratio
+
+		field := ""
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "ui" {
+				field = e.Field
+			}
+		}
+		if field != tt.field {
+			t.Errorf("%s/%d: got error on %q; want %q", tt.direction, tt.ratio, field, tt.field)
+		}
+	}
+}
+
+func TestValidate_CardDensity(t *testing.T) {
+	for _, density := range []string{"", "compact", "normal", "detailed"} {
+		cfg := DefaultConfig()
+		cfg.UI.CardDensity = density
+		if errs := cfg.Validate().Errors; len(errs) > 0 {
+			t.Errorf("card_density %q: unexpected errors %v", density, errs)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.UI.CardDensity = "huge"
+	found := false
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "ui" && e.Field == "card_density" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected error for ui.card_density")
+	}
+}
+
 func TestValidate_InvalidServerPort(t *testing.T) {
 	tests := []struct {
 		name string
@@ -339,6 +469,99 @@ func TestValidate_NegativePollInterval(t *testing.T) {
 	}
 }
 
+func TestValidate_Hooks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Hooks = map[string][]string{
+		"ticket.moved":  {"notify-send moved"},
+		"ticket.banana": {"true"},
+		"agent.failed":  {"  "},
+	}
+
+	result := cfg.Validate()
+
+	foundWarning := false
+	for _, w := range result.Warnings {
+		if w.Section == "hooks" && w.Field == "ticket.banana" {
+			foundWarning = true
+		}
+	}
+	if !foundWarning {
+		t.Error("expected warning for unknown hook event")
+	}
+
+	foundError := false
+	for _, e := range result.Errors {
+		if e.Section == "hooks" && e.Field == "agent.failed" {
+			foundError = true
+		}
+		if e.Field == "ticket.moved" {
+			t.Errorf("unexpected error for valid hook: %s", e.Message)
+		}
+	}
+	if !foundError {
+		t.Error("expected error for empty hook command")
+	}
+}
+
+func TestValidate_Keybindings(t *testing.T) {
+	tests := []struct {
+		name        string
+		keybindings KeybindingsConfig
+		wantError   string
+	}{
+		{"valid preset", KeybindingsConfig{Preset: "emacs"}, ""},
+		{"valid override", KeybindingsConfig{Bindings: map[string]KeyList{"new_ticket": {"b"}}}, ""},
+		{"unknown preset", KeybindingsConfig{Preset: "helix"}, "unknown keybinding preset"},
+		{"unknown action", KeybindingsConfig{Bindings: map[string]KeyList{"launch": {"x"}}}, "unknown keybinding action"},
+		{"conflict", KeybindingsConfig{Bindings: map[string]KeyList{"new_ticket": {"j"}}}, "move_down and new_ticket"},
+		{"valid macro", KeybindingsConfig{Macros: map[string]Macro{"triage": {Key: "ctrl+t", Keys: []string{"n", "enter"}}}}, ""},
+		{"empty macro", KeybindingsConfig{Macros: map[string]Macro{"triage": {}}}, "at least one key"},
+		{"macro key bound", KeybindingsConfig{Macros: map[string]Macro{"triage": {Key: "n", Keys: []string{"j"}}}}, "already bound to new_ticket"},
+		{"macro key reserved", KeybindingsConfig{Macros: map[string]Macro{"triage": {Key: "esc", Keys: []string{"j"}}}}, "reserved"},
+		{"macro keys clash", KeybindingsConfig{Macros: map[string]Macro{
+			"a": {Key: "ctrl+t", Keys: []string{"j"}},
+			"b": {Key: "ctrl+t", Keys: []string{"k"}},
+		}}, "also replays macro a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Keybindings = tt.keybindings
+
+			var messages []string
+			for _, e := range cfg.Validate().Errors {
+				if e.Section == "keybindings" {
+					messages = append(messages, e.Message)
+				}
+			}
+
+			if tt.wantError == "" {
+				if len(messages) > 0 {
+					t.Errorf("unexpected keybinding errors: %v", messages)
+				}
+				return
+			}
+			if len(messages) != 1 || !strings.Contains(messages[0], tt.wantError) {
+				t.Errorf("keybinding errors = %v; want one containing %q", messages, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestKeyList_UnmarshalJSON(t *testing.T) {
+	var kb KeybindingsConfig
+	if err := json.Unmarshal([]byte(`{"bindings": {"quit": "Q", "filter": ["/", "ctrl+f"]}}`), &kb); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(kb.Bindings["quit"]) != 1 || kb.Bindings["quit"][0] != "Q" {
+		t.Errorf("quit = %v; want [Q]", kb.Bindings["quit"])
+	}
+	if len(kb.Bindings["filter"]) != 2 {
+		t.Errorf("filter = %v; want two keys", kb.Bindings["filter"])
+	}
+}
+
 func TestValidationResult_FormatErrors(t *testing.T) {
 	r := &ValidationResult{}
 	r.AddError("defaults", "branch_naming", "must be valid", "invalid")
@@ -471,3 +694,434 @@ func TestValidate_ValidTemplatePrompt(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_PullRequestProvider(t *testing.T) {
+	for provider, valid := range map[string]bool{"": true, "auto": true, "github": true, "gitlab": true, "gitea": true, "bitbucket": false} {
+		cfg := DefaultConfig()
+		cfg.PullRequest.Provider = provider
+
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "pull_request" && e.Field == "provider" {
+				found = true
+			}
+		}
+		if found == valid {
+			t.Errorf("provider %q: got error = %v; want %v", provider, found, !valid)
+		}
+	}
+}
+
+func TestValidate_SyncStrategy(t *testing.T) {
+	for strategy, valid := range map[string]bool{"": true, "rebase": true, "merge": true, "squash": false} {
+		cfg := DefaultConfig()
+		cfg.Behavior.SyncStrategy = strategy
+
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "behavior" && e.Field == "sync_strategy" {
+				found = true
+			}
+		}
+		if found == valid {
+			t.Errorf("strategy %q: got error = %v; want %v", strategy, found, !valid)
+		}
+	}
+}
+
+func TestValidate_OnApprove(t *testing.T) {
+	for action, valid := range map[string]bool{"": true, "merge": true, "pr": true, "none": true, "push": false} {
+		cfg := DefaultConfig()
+		cfg.Behavior.OnApprove = action
+
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "behavior" && e.Field == "on_approve" {
+				found = true
+			}
+		}
+		if found == valid {
+			t.Errorf("on_approve %q: got error = %v; want %v", action, found, !valid)
+		}
+	}
+}
+
+func TestValidate_ContextFile(t *testing.T) {
+	for file, valid := range map[string]bool{"": true, ".openkanban-task.md": true, "docs/TASK.md": true, "../TASK.md": false, "/tmp/TASK.md": false} {
+		cfg := DefaultConfig()
+		agent := cfg.Agents["aider"]
+		agent.ContextFile = file
+		cfg.Agents["aider"] = agent
+
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "agents.aider" && e.Field == "context_file" {
+				found = true
+			}
+		}
+		if found == valid {
+			t.Errorf("context_file %q: got error = %v; want %v", file, found, !valid)
+		}
+	}
+}
+
+func TestValidate_ContextInput(t *testing.T) {
+	cfg := DefaultConfig()
+	agent := cfg.Agents["aider"]
+	agent.ContextInput = "stdin"
+	agent.ReadyPattern = "(unclosed"
+	agent.ReadyTimeout = -1
+	cfg.Agents["aider"] = agent
+
+	fields := make(map[string]bool)
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "agents.aider" {
+			fields[e.Field] = true
+		}
+	}
+	for _, field := range []string{"context_input", "ready_pattern", "ready_timeout"} {
+		if !fields[field] {
+			t.Errorf("expected an error for agents.aider.%s", field)
+		}
+	}
+}
+
+func TestValidate_Models(t *testing.T) {
+	cfg := DefaultConfig()
+	agent := cfg.Agents["claude"]
+	agent.Models = append(agent.Models, ModelOption{Tier: "$"}, ModelOption{Name: "opus"})
+	agent.ModelArgs = []string{"--model"}
+	cfg.Agents["claude"] = agent
+
+	result := cfg.Validate()
+	if !slices.ContainsFunc(result.Errors, func(e ValidationError) bool { return e.Field == "models[3].name" }) {
+		t.Error("expected an error for a model without a name")
+	}
+	warned := make(map[string]bool)
+	for _, w := range result.Warnings {
+		if w.Section == "agents.claude" {
+			warned[w.Field] = true
+		}
+	}
+	for _, field := range []string{"models", "model_args"} {
+		if !warned[field] {
+			t.Errorf("expected a warning for agents.claude.%s", field)
+		}
+	}
+}
+
+func TestValidate_Orders(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.TicketOrder = "priority"
+	cfg.UI.ProjectOrder = "manual"
+
+	fields := make(map[string]bool)
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "ui" {
+			fields[e.Field] = true
+		}
+	}
+	for _, field := range []string{"ticket_order", "project_order"} {
+		if !fields[field] {
+			t.Errorf("expected error for ui.%s", field)
+		}
+	}
+
+	cfg = DefaultConfig()
+	cfg.UI.TicketOrder = "manual"
+	if result := cfg.Validate(); result.HasErrors() {
+		t.Errorf("manual ticket order should be valid: %s", result.FormatErrors())
+	}
+}
+
+func TestValidate_AutosaveInterval(t *testing.T) {
+	for interval, valid := range map[int]bool{0: true, 2: true, -1: false} {
+		cfg := DefaultConfig()
+		cfg.Behavior.AutosaveInterval = interval
+
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "behavior" && e.Field == "autosave_interval" {
+				found = true
+			}
+		}
+		if found == valid {
+			t.Errorf("interval %d: got error = %v; want %v", interval, found, !valid)
+		}
+	}
+}
+
+func TestValidate_Jira(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Jira.URL = "acme.atlassian.net"
+	cfg.Jira.SyncInterval = -1
+	cfg.Jira.Transitions["qa"] = "In QA"
+
+	fields := make(map[string]bool)
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "jira" {
+			fields[e.Field] = true
+		}
+	}
+	for _, field := range []string{"url", "sync_interval", "transitions.qa"} {
+		if !fields[field] {
+			t.Errorf("expected an error for jira.%s, got %v", field, fields)
+		}
+	}
+
+	cfg = DefaultConfig()
+	cfg.Jira.URL = "https://acme.atlassian.net"
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "jira" {
+			t.Errorf("unexpected error for a valid Jira config: %v", e)
+		}
+	}
+}
+
+func TestValidate_Linear(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Linear.SyncInterval = -1
+	cfg.Linear.States["qa"] = "In QA"
+
+	fields := make(map[string]bool)
+	for _, e := range cfg.Validate().Errors {
+		if e.Section == "linear" {
+			fields[e.Field] = true
+		}
+	}
+	for _, field := range []string{"sync_interval", "states.qa"} {
+		if !fields[field] {
+			t.Errorf("expected an error for linear.%s, got %v", field, fields)
+		}
+	}
+}
+
+func TestValidate_Notifications(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Notifications.Webhooks = []events.Webhook{
+		{URL: "https://hooks.slack.com/services/T/B/x", Events: []string{"agent.failed", "ticket.done"}},
+		{URL: "https://chat.example.com/hook", Templates: map[string]string{"agent.failed": "{{.Nope"}},
+		{URL: "hooks.slack.com/x", Service: "teams", Events: []string{"agent.exploded"}},
+	}
+	result := cfg.Validate()
+
+	errs := make(map[string]bool)
+	for _, e := range result.Errors {
+		if e.Section == "notifications" {
+			errs[e.Field] = true
+		}
+	}
+	for _, field := range []string{"webhooks[1].service", "webhooks[1].templates.agent.failed", "webhooks[2].url", "webhooks[2].service"} {
+		if !errs[field] {
+			t.Errorf("expected an error for %s, got %v", field, errs)
+		}
+	}
+	if len(errs) != 4 {
+		t.Errorf("got errors %v; want only the four above", errs)
+	}
+
+	warned := false
+	for _, w := range result.Warnings {
+		warned = warned || w.Field == "webhooks[2].events"
+	}
+	if !warned {
+		t.Error("expected a warning for the unknown event")
+	}
+}
+
+func TestValidate_QuietHours(t *testing.T) {
+	tests := []struct {
+		quiet QuietHours
+		valid bool
+	}{
+		{QuietHours{}, true},
+		{QuietHours{Start: "22:00", End: "07:30"}, true},
+		{QuietHours{Start: "22:00"}, true}, // a warning, so the end can be set next
+		{QuietHours{Start: "10pm", End: "07:00"}, false},
+		{QuietHours{Start: "00:00", End: "00:00", Days: []string{"sat", "sun"}}, true},
+		{QuietHours{Start: "22:00", End: "07:00", Days: []string{"saturday"}}, false},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Notifications.QuietHours = tt.quiet
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || strings.HasPrefix(e.Field, "quiet_hours")
+		}
+		if found == tt.valid {
+			t.Errorf("quiet hours %+v: got error = %v; want %v", tt.quiet, found, !tt.valid)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Notifications.QuietSchedules = []QuietHours{{Start: "12:00", End: "13:00"}, {}}
+	errs := cfg.Validate().Errors
+	if len(errs) != 1 || errs[0].Field != "quiet_schedules[1]" {
+		t.Errorf("errors = %v; want one on the empty quiet_schedules[1]", errs)
+	}
+}
+
+func TestValidate_TmuxSessionPrefix(t *testing.T) {
+	for prefix, valid := range map[string]bool{"openkanban-": true, "": true, "ok.": false, "ok:": false} {
+		cfg := DefaultConfig()
+		cfg.Tmux.SessionPrefix = prefix
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || (e.Section == "tmux" && e.Field == "session_prefix")
+		}
+		if found == valid {
+			t.Errorf("prefix %q: got error = %v; want %v", prefix, found, !valid)
+		}
+	}
+}
+
+func TestValidate_CommitRefsPrefix(t *testing.T) {
+	for prefix, valid := range map[string]bool{"okb": true, "OK2": true, "": false, "ok-b": false, "o k": false} {
+		cfg := DefaultConfig()
+		cfg.CommitRefs.Prefix = prefix
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || (e.Section == "commit_refs" && e.Field == "prefix")
+		}
+		if found == valid {
+			t.Errorf("prefix %q: got error = %v; want %v", prefix, found, !valid)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CommitRefs.Enabled = false
+	cfg.CommitRefs.Prefix = ""
+	if errs := cfg.Validate().Errors; len(errs) != 0 {
+		t.Errorf("disabled commit refs: errors = %v", errs)
+	}
+}
+
+func TestValidate_TelemetryEndpoint(t *testing.T) {
+	for endpoint, valid := range map[string]bool{"": true, "http://localhost:4318": true, "https://otel.example.com": true, "localhost:4318": false, "grpc://collector:4317": false} {
+		cfg := DefaultConfig()
+		cfg.Telemetry.Endpoint = endpoint
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || (e.Section == "telemetry" && e.Field == "endpoint")
+		}
+		if found == valid {
+			t.Errorf("endpoint %q: got error = %v; want %v", endpoint, found, !valid)
+		}
+	}
+}
+
+func TestValidate_Digest(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(*Config)
+		field string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"weekly", func(c *Config) { c.Digest.Period = "weekly" }, ""},
+		{"bad period", func(c *Config) { c.Digest.Period = "monthly" }, "period"},
+		{"negative stall days", func(c *Config) { c.Digest.StallDays = -1 }, "stall_days"},
+		{"bad port", func(c *Config) { c.Digest.SMTP.Port = 70000 }, "smtp.port"},
+		{"bad from", func(c *Config) { c.Digest.SMTP.From = "not an address" }, "smtp.from"},
+		{"named recipient", func(c *Config) { c.Digest.SMTP.To = []string{"Me <me@example.com>"} }, ""},
+		{"bad recipient", func(c *Config) { c.Digest.SMTP.To = []string{"me@example.com", "nope"} }, "smtp.to[1]"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.edit(cfg)
+		var fields []string
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "digest" {
+				fields = append(fields, e.Field)
+			}
+		}
+		if tt.field == "" && len(fields) > 0 || tt.field != "" && (len(fields) != 1 || fields[0] != tt.field) {
+			t.Errorf("%s: digest errors = %v; want %q", tt.name, fields, tt.field)
+		}
+	}
+}
+
+func TestValidate_Logging(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(*Config)
+		field string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"debug", func(c *Config) { c.Logging.Level = "debug" }, ""},
+		{"bad level", func(c *Config) { c.Logging.Level = "verbose" }, "level"},
+		{"no rotation", func(c *Config) { c.Logging.MaxSizeMB = 0 }, ""},
+		{"negative size", func(c *Config) { c.Logging.MaxSizeMB = -1 }, "max_size_mb"},
+		{"negative files", func(c *Config) { c.Logging.MaxFiles = -1 }, "max_files"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.edit(cfg)
+		var fields []string
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "logging" {
+				fields = append(fields, e.Field)
+			}
+		}
+		if tt.field == "" && len(fields) > 0 || tt.field != "" && (len(fields) != 1 || fields[0] != tt.field) {
+			t.Errorf("%s: logging errors = %v; want %q", tt.name, fields, tt.field)
+		}
+	}
+}
+
+func TestValidate_StorageEncryption(t *testing.T) {
+	for mode, valid := range map[string]bool{"none": true, "passphrase": true, "keychain": true, "": false, "age": false} {
+		cfg := DefaultConfig()
+		cfg.Storage.Encryption = mode
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || (e.Section == "storage" && e.Field == "encryption")
+		}
+		if found == valid {
+			t.Errorf("encryption %q: got error = %v; want %v", mode, found, !valid)
+		}
+	}
+}
+
+func TestValidate_Sync(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(*Config)
+		field string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"enabled", func(c *Config) { c.Sync.Enabled = true }, ""},
+		{"negative interval", func(c *Config) { c.Sync.Interval = -1 }, "interval"},
+		{"no remote", func(c *Config) { c.Sync.Enabled, c.Sync.Remote = true, "" }, "remote"},
+		{"bad branch", func(c *Config) { c.Sync.Enabled, c.Sync.Branch = true, "state..board" }, "branch"},
+		{"nested branch", func(c *Config) { c.Sync.Enabled, c.Sync.Branch = true, "team/board" }, ""},
+		{"keychain", func(c *Config) { c.Sync.Enabled, c.Storage.Encryption = true, "keychain" }, "enabled"},
+		{"passphrase", func(c *Config) { c.Sync.Enabled, c.Storage.Encryption = true, "passphrase" }, ""},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		tt.edit(cfg)
+		var fields []string
+		for _, e := range cfg.Validate().Errors {
+			if e.Section == "sync" {
+				fields = append(fields, e.Field)
+			}
+		}
+		if tt.field == "" && len(fields) > 0 || tt.field != "" && (len(fields) != 1 || fields[0] != tt.field) {
+			t.Errorf("%s: sync errors = %v; want %q", tt.name, fields, tt.field)
+		}
+	}
+}
+
+func TestValidate_VaultFolder(t *testing.T) {
+	for folder, valid := range map[string]bool{"openkanban": true, "": true, "work/boards": true, "/abs": false, "..": false, "../outside": false} {
+		cfg := DefaultConfig()
+		cfg.Vault.Folder = folder
+		found := false
+		for _, e := range cfg.Validate().Errors {
+			found = found || (e.Section == "vault" && e.Field == "folder")
+		}
+		if found == valid {
+			t.Errorf("folder %q: got error = %v; want %v", folder, found, !valid)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// writeTimeout bounds how long a stuck instance can hold up a message to it.
+const writeTimeout = 2 * time.Second
+
+// Presence is where an instance sharing the board is.
+type Presence struct {
+	Client  string `json:"client"`
+	Ticket  string `json:"ticket,omitempty"`  // selected ticket
+	Editing bool   `json:"editing,omitempty"` // Ticket is open in the edit form
+	Left    bool   `json:"left,omitempty"`    // the instance has gone
+}
+
+// Message is exchanged between instances sharing a board. Event carries a
+// change made on the sending instance, with the ticket as it now stands.
+type Message struct {
+	From     string        `json:"from"`
+	Event    *events.Event `json:"event,omitempty"`
+	Presence *Presence     `json:"presence,omitempty"`
+}
+
+// Hub relays Messages between the running instance and the instances that
+// joined it, so every one of them sees the others' changes and presence.
+type Hub struct {
+	name    string
+	receive func(Message)
+
+	mu       sync.Mutex
+	peers    map[string]*conn
+	presence map[string]Presence
+}
+
+// NewHub creates a hub for the running instance, known as name. receive is
+// called with each message from a joined instance.
+func NewHub(name string, receive func(Message)) *Hub {
+	return &Hub{
+		name:     name,
+		receive:  receive,
+		peers:    make(map[string]*conn),
+		presence: make(map[string]Presence),
+	}
+}
+
+// Name returns the running instance's name.
+func (h *Hub) Name() string {
+	return h.name
+}
+
+// Send delivers msg from the running instance to every joined instance.
+func (h *Hub) Send(msg Message) {
+	msg.From = h.name
+	if msg.Presence != nil {
+		msg.Presence.Client = h.name
+	}
+	h.relay(msg, "")
+}
+
+// relay records msg's presence and sends msg to every instance but except.
+func (h *Hub) relay(msg Message, except string) {
+	h.mu.Lock()
+	if p := msg.Presence; p != nil {
+		if p.Left {
+			delete(h.presence, p.Client)
+		} else {
+			h.presence[p.Client] = *p
+		}
+	}
+	peers := make([]*conn, 0, len(h.peers))
+	for name, c := range h.peers {
+		if name != except {
+			peers = append(peers, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range peers {
+		c.send(msg)
+	}
+}
+
+// serve runs a joined instance's connection until it leaves or done closes.
+func (h *Hub) serve(nc net.Conn, dec *json.Decoder, want string, done <-chan struct{}) {
+	c := &conn{conn: nc, enc: json.NewEncoder(nc)}
+
+	// Hold the connection until the reply and the known presence are
+	// written, so nothing relayed to it meanwhile comes first
+	c.mu.Lock()
+	h.mu.Lock()
+	name := h.uniqueName(want)
+	h.peers[name] = c
+	greeting := []any{Response{OK: true, Client: name}}
+	for _, p := range h.presence {
+		greeting = append(greeting, Message{From: p.Client, Presence: &p})
+	}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.peers, name)
+		h.mu.Unlock()
+		left := Message{From: name, Presence: &Presence{Client: name, Left: true}}
+		h.relay(left, name)
+		h.receive(left)
+	}()
+
+	nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	for _, v := range greeting {
+		if err := c.enc.Encode(v); err != nil {
+			c.mu.Unlock()
+			return
+		}
+	}
+	c.mu.Unlock()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-done:
+			nc.Close()
+		case <-stop:
+		}
+	}()
+
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+		msg.From = name
+		if msg.Presence != nil {
+			msg.Presence.Client = name
+		}
+		h.relay(msg, name)
+		h.receive(msg)
+	}
+}
+
+// uniqueName returns want, numbered when another instance already has it.
+// The caller holds h.mu.
+func (h *Hub) uniqueName(want string) string {
+	if want == "" {
+		want = "client"
+	}
+	taken := func(name string) bool {
+		_, ok := h.peers[name]
+		return ok || name == h.name
+	}
+	name := want
+	for n := 2; taken(name); n++ {
+		name = want + " " + strconv.Itoa(n)
+	}
+	return name
+}
+
+// conn serialises writes to one instance's connection.
+type conn struct {
+	mu   sync.Mutex
+	conn net.Conn
+	enc  *json.Encoder
+}
+
+func (c *conn) encode(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.enc.Encode(v)
+}
+
+// send writes msg, dropping the connection when the instance can't keep up.
+func (c *conn) send(msg Message) error {
+	if err := c.encode(msg); err != nil {
+		c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// Peer is an instance's connection to the running instance it joined.
+type Peer struct {
+	name string
+	c    *conn
+	dec  *json.Decoder
+}
+
+// Join connects to the instance listening on path as name, with token for a
+// server that requires one. The running instance may number the name to
+// tell it apart; see Peer.Name.
+func Join(path, token, name string) (*Peer, error) {
+	nc, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	c := &conn{conn: nc, enc: json.NewEncoder(nc)}
+	if err := c.encode(Request{Method: MethodJoin, Client: name, Token: token}); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	dec := json.NewDecoder(nc)
+	var resp Response
+	if err := dec.Decode(&resp); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if !resp.OK {
+		nc.Close()
+		return nil, errors.New(resp.Error)
+	}
+	return &Peer{name: resp.Client, c: c, dec: dec}, nil
+}
+
+// Name returns the name the running instance knows this one by.
+func (p *Peer) Name() string {
+	return p.name
+}
+
+// Send delivers msg to the running instance, which relays it to the others.
+func (p *Peer) Send(msg Message) error {
+	msg.From = p.name
+	if msg.Presence != nil {
+		msg.Presence.Client = p.name
+	}
+	return p.c.send(msg)
+}
+
+// Receive calls fn with each message from the running instance and the
+// instances it relays for, until the connection closes.
+func (p *Peer) Receive(fn func(Message)) error {
+	for {
+		var msg Message
+		if err := p.dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		fn(msg)
+	}
+}
+
+// Close leaves the shared board.
+func (p *Peer) Close() error {
+	return p.c.conn.Close()
+}
@@ -0,0 +1,74 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+func TestHubRelaysBetweenInstances(t *testing.T) {
+	path := socketPath(t)
+
+	fromClients := make(chan Message, 16)
+	hub := NewHub("me", func(msg Message) { fromClients <- msg })
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv.SetHub(hub)
+	go srv.Serve()
+	defer srv.Close()
+
+	hub.Send(Message{Presence: &Presence{Ticket: "t1"}})
+
+	join := func() (*Peer, chan Message) {
+		t.Helper()
+		peer, err := Join(path, "", "me")
+		if err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+		t.Cleanup(func() { peer.Close() })
+		received := make(chan Message, 16)
+		go peer.Receive(func(msg Message) { received <- msg })
+		return peer, received
+	}
+	next := func(ch chan Message) Message {
+		t.Helper()
+		select {
+		case msg := <-ch:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("no message received")
+			return Message{}
+		}
+	}
+
+	first, firstReceived := join()
+	if first.Name() != "me 2" {
+		t.Errorf("first client named %q; want %q", first.Name(), "me 2")
+	}
+	if msg := next(firstReceived); msg.Presence == nil || msg.Presence.Client != "me" || msg.Presence.Ticket != "t1" {
+		t.Errorf("joining client got %+v; want the running instance's presence", msg)
+	}
+
+	second, secondReceived := join()
+	next(secondReceived) // the running instance's presence
+
+	ticket := board.NewTicket("Shared", "p")
+	e := events.New(events.TicketChanged, ticket, "")
+	if err := second.Send(Message{Event: &e}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	for _, msg := range []Message{next(firstReceived), next(fromClients)} {
+		if msg.From != second.Name() || msg.Event == nil || msg.Event.Ticket.ID != ticket.ID {
+			t.Errorf("relayed %+v; want the event from %s", msg, second.Name())
+		}
+	}
+
+	second.Close()
+	if msg := next(firstReceived); msg.Presence == nil || !msg.Presence.Left || msg.Presence.Client != second.Name() {
+		t.Errorf("after leaving got %+v; want %s gone", msg, second.Name())
+	}
+}
@@ -0,0 +1,351 @@
+// Package control implements the local socket used by CLI commands to talk
+// to a running openkanban instance.
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// Request methods understood by the running instance.
+const (
+	MethodSpawn  = "spawn"
+	MethodStop   = "stop"
+	MethodStatus = "status"
+	MethodReload = "reload"
+
+	// MethodWatch keeps the connection open and streams board events as
+	// line-delimited JSON after the initial Response.
+	MethodWatch = "watch"
+
+	// MethodJoin connects another openkanban instance, which then exchanges
+	// Messages with the running one until it leaves; see Hub.
+	MethodJoin = "join"
+)
+
+var (
+	// ErrNotRunning is returned by Call when no instance is listening.
+	ErrNotRunning = errors.New("openkanban is not running")
+
+	// ErrAlreadyRunning is returned by Listen when another instance owns the socket.
+	ErrAlreadyRunning = errors.New("another openkanban instance is already running")
+
+	// errPeerUnknown means the platform can't tell who is connecting.
+	errPeerUnknown = errors.New("peer credentials are not available on this platform")
+)
+
+const dialTimeout = 2 * time.Second
+
+// Request is a single command sent over the control socket.
+type Request struct {
+	Method  string   `json:"method"`
+	Tickets []string `json:"tickets,omitempty"`
+	Label   string   `json:"label,omitempty"`
+	Client  string   `json:"client,omitempty"` // name asked for by MethodJoin
+	Token   string   `json:"token,omitempty"`  // must match the server's, when it has one
+}
+
+// Response is the reply to a Request.
+type Response struct {
+	OK      bool          `json:"ok"`
+	Error   string        `json:"error,omitempty"`
+	Tickets []TicketState `json:"tickets,omitempty"`
+	Client  string        `json:"client,omitempty"` // name given by MethodJoin
+}
+
+// TicketState describes a ticket's agent as seen by the running instance.
+type TicketState struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Project     string `json:"project,omitempty"`
+	Status      string `json:"status"`
+	AgentType   string `json:"agent_type,omitempty"`
+	AgentStatus string `json:"agent_status,omitempty"`
+	Running     bool   `json:"running"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NewTicketState builds the TicketState for t.
+func NewTicketState(t *board.Ticket, projectName string, running bool) TicketState {
+	return TicketState{
+		ID:          string(t.ID),
+		Title:       t.Title,
+		Project:     projectName,
+		Status:      string(t.Status),
+		AgentType:   t.AgentType,
+		AgentStatus: string(t.AgentStatus),
+		Running:     running,
+	}
+}
+
+// Handler answers a control request.
+type Handler func(Request) Response
+
+// Server accepts control connections on a Unix socket.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+	events   *events.Bus
+	hub      *Hub
+	token    string
+	done     chan struct{}
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// Listen creates the control socket at path, readable and writable only by
+// the current user, whose processes alone may connect. A stale socket left
+// behind by a crashed instance is removed; a live one yields
+// ErrAlreadyRunning.
+func Listen(path string, handler Handler) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	ln, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// listenPrivate can't on Windows, which has no umask
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to restrict %s: %w", path, err)
+	}
+
+	return &Server{path: path, listener: ln, handler: handler, done: make(chan struct{})}, nil
+}
+
+// SetEvents enables MethodWatch, streaming events published on bus.
+// It must be called before Serve.
+func (s *Server) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
+// SetHub enables MethodJoin, relaying messages through hub.
+// It must be called before Serve.
+func (s *Server) SetHub(hub *Hub) {
+	s.hub = hub
+}
+
+// SetToken makes every request carry token. It must be called before Serve.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// Serve accepts connections until Close is called.
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn)
+		}()
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	if err := checkPeer(conn); err != nil {
+		slog.Warn("refused control connection", "err", err)
+		_ = json.NewEncoder(conn).Encode(Response{Error: "permission denied"})
+		return
+	}
+
+	dec := json.NewDecoder(conn)
+	var req Request
+	if err := dec.Decode(&req); err != nil {
+		slog.Debug("invalid control request", "err", err)
+		_ = json.NewEncoder(conn).Encode(Response{Error: "invalid request: " + err.Error()})
+		return
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.token)) != 1 {
+		slog.Warn("refused control request with a wrong token", "method", req.Method)
+		_ = json.NewEncoder(conn).Encode(Response{Error: "invalid control token"})
+		return
+	}
+
+	if req.Method == MethodWatch {
+		s.streamEvents(conn)
+		return
+	}
+	if req.Method == MethodJoin {
+		if s.hub == nil {
+			_ = json.NewEncoder(conn).Encode(Response{Error: "joining is not available"})
+			return
+		}
+		s.hub.serve(conn, dec, req.Client, s.done)
+		return
+	}
+
+	_ = json.NewEncoder(conn).Encode(s.handler(req))
+}
+
+// checkPeer refuses connections from other users' processes. Where the
+// platform can't say who connected, the socket's file mode has to do.
+func checkPeer(conn net.Conn) error {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var uid int
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		uid, credErr = peerUID(fd)
+	}); err != nil {
+		return err
+	}
+	if errors.Is(credErr, errPeerUnknown) {
+		return nil
+	}
+	if credErr != nil {
+		return fmt.Errorf("failed to read peer credentials: %w", credErr)
+	}
+	if uid != os.Getuid() {
+		return fmt.Errorf("connection from uid %d", uid)
+	}
+	return nil
+}
+
+// streamEvents writes every published event to conn until the client
+// disconnects or the server is closed.
+func (s *Server) streamEvents(conn net.Conn) {
+	enc := json.NewEncoder(conn)
+	if s.events == nil {
+		_ = enc.Encode(Response{Error: "event streaming is not available"})
+		return
+	}
+
+	ch, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	if err := enc.Encode(Response{OK: true}); err != nil {
+		return
+	}
+
+	// The client never sends anything after its request, so a finished read
+	// means it has gone away.
+	gone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		close(gone)
+	}()
+
+	for {
+		select {
+		case e := <-ch:
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Close stops accepting connections and removes the socket file.
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.listener.Close()
+		close(s.done)
+		s.wg.Wait()
+		os.Remove(s.path)
+	})
+	return err
+}
+
+// Path returns the socket path the server listens on.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Call sends req to the instance listening on path and waits for its reply.
+func Call(path string, req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Watch subscribes to events from the instance listening on path and calls fn
+// with each event's JSON until the instance exits or fn returns an error.
+// token is sent for a server that requires one.
+func Watch(path, token string, fn func(json.RawMessage) error) error {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return ErrNotRunning
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(Request{Method: MethodWatch, Token: token}); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	dec := json.NewDecoder(conn)
+	var resp Response
+	if err := dec.Decode(&resp); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read event: %w", err)
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+}
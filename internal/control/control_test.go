@@ -0,0 +1,213 @@
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// socketPath returns a short path; Unix socket paths are limited to ~100 bytes.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "okctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "test.sock")
+}
+
+func TestCallRoundTrip(t *testing.T) {
+	path := socketPath(t)
+
+	srv, err := Listen(path, func(req Request) Response {
+		if req.Method != MethodStatus {
+			return Response{Error: "unexpected method " + req.Method}
+		}
+		return Response{OK: true, Tickets: []TicketState{{ID: req.Tickets[0], Running: true}}}
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go srv.Serve()
+	defer srv.Close()
+
+	resp, err := Call(path, Request{Method: MethodStatus, Tickets: []string{"abc"}})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if !resp.OK {
+		t.Fatalf("Call() response not OK: %s", resp.Error)
+	}
+	if len(resp.Tickets) != 1 || resp.Tickets[0].ID != "abc" || !resp.Tickets[0].Running {
+		t.Errorf("Call() tickets = %+v; want one running ticket abc", resp.Tickets)
+	}
+}
+
+func TestListenOwnerOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no Unix file modes")
+	}
+	dir := filepath.Join(filepath.Dir(socketPath(t)), "run")
+	path := filepath.Join(dir, "test.sock")
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer srv.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("socket mode = %o; want 600", mode)
+	}
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("socket directory = %v, %v; want mode 700", info, err)
+	}
+}
+
+func TestCallToken(t *testing.T) {
+	path := socketPath(t)
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv.SetToken("secret")
+	go srv.Serve()
+	defer srv.Close()
+
+	for _, token := range []string{"", "wrong"} {
+		resp, err := Call(path, Request{Method: MethodStatus, Token: token})
+		if err != nil {
+			t.Fatalf("Call() error = %v", err)
+		}
+		if resp.OK {
+			t.Errorf("Call() with token %q succeeded; want it refused", token)
+		}
+	}
+
+	resp, err := Call(path, Request{Method: MethodStatus, Token: "secret"})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if !resp.OK {
+		t.Errorf("Call() with the right token failed: %s", resp.Error)
+	}
+}
+
+func TestCallNotRunning(t *testing.T) {
+	_, err := Call(socketPath(t), Request{Method: MethodStatus})
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Call() error = %v; want ErrNotRunning", err)
+	}
+}
+
+func TestListenAlreadyRunning(t *testing.T) {
+	path := socketPath(t)
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go srv.Serve()
+	defer srv.Close()
+
+	if _, err := Listen(path, nil); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("second Listen() error = %v; want ErrAlreadyRunning", err)
+	}
+}
+
+func TestListenRemovesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() with stale socket error = %v", err)
+	}
+	srv.Close()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after Close()")
+	}
+}
+
+func TestWatchStreamsEvents(t *testing.T) {
+	path := socketPath(t)
+	bus := events.NewBus()
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv.SetEvents(bus)
+	go srv.Serve()
+
+	received := make(chan events.Event, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(path, "", func(raw json.RawMessage) error {
+			var e events.Event
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return err
+			}
+			select {
+			case received <- e:
+			default:
+			}
+			return nil
+		})
+	}()
+
+	// Publish until the subscription is in place; events before that are lost.
+	deadline := time.After(2 * time.Second)
+	for got := false; !got; {
+		bus.Publish(events.Event{Type: events.AgentFailed, Error: "boom"})
+		select {
+		case e := <-received:
+			if e.Type != events.AgentFailed || e.Error != "boom" {
+				t.Errorf("Watch() received %+v; want agent.failed event", e)
+			}
+			got = true
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("Watch() received no event")
+		}
+	}
+
+	srv.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch() error after server close = %v; want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch() did not return after server close")
+	}
+}
+
+func TestWatchWithoutEvents(t *testing.T) {
+	path := socketPath(t)
+
+	srv, err := Listen(path, func(Request) Response { return Response{OK: true} })
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go srv.Serve()
+	defer srv.Close()
+
+	if err := Watch(path, "", func(json.RawMessage) error { return nil }); err == nil {
+		t.Error("Watch() without an event bus should fail")
+	}
+}
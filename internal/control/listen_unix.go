@@ -0,0 +1,16 @@
+//go:build !windows
+
+package control
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivate binds the socket under a umask that leaves it to its owner,
+// so it never exists with looser permissions
+func listenPrivate(path string) (net.Listener, error) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,9 @@
+package control
+
+import "net"
+
+// listenPrivate binds the socket; Windows has no umask, and the directory
+// Listen creates is the user's own
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,15 @@
+//go:build darwin
+
+package control
+
+import "golang.org/x/sys/unix"
+
+// peerUID returns the user ID of the process at the other end of the
+// socket fd
+func peerUID(fd uintptr) (int, error) {
+	cred, err := unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	if err != nil {
+		return 0, err
+	}
+	return int(cred.Uid), nil
+}
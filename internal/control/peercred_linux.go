@@ -0,0 +1,15 @@
+//go:build linux
+
+package control
+
+import "golang.org/x/sys/unix"
+
+// peerUID returns the user ID of the process at the other end of the
+// socket fd
+func peerUID(fd uintptr) (int, error) {
+	cred, err := unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	if err != nil {
+		return 0, err
+	}
+	return int(cred.Uid), nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package control
+
+// peerUID is unavailable here; the socket's file mode alone keeps other
+// users out
+func peerUID(fd uintptr) (int, error) {
+	return 0, errPeerUnknown
+}
@@ -0,0 +1,264 @@
+// Package crypt encrypts openkanban's data files at rest with AES-256-GCM,
+// keyed by a passphrase (through PBKDF2) or by a random key kept in the
+// OS keychain.
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Kind is the secret a file is sealed with
+type Kind string
+
+const (
+	KindNone       Kind = ""           // not sealed
+	KindPassphrase Kind = "passphrase" // a passphrase stretched with PBKDF2
+	KindKeychain   Kind = "keychain"   // a random key from the OS keychain
+)
+
+// envelopeFormat marks a sealed file, which is a JSON object so it is
+// recognisable at a glance
+const envelopeFormat = "openkanban-encrypted"
+
+const (
+	keySize  = 32
+	saltSize = 16
+)
+
+// Iterations is the PBKDF2-SHA256 work factor for new passphrase files
+var Iterations = 600_000
+
+var (
+	// ErrWrongKey is returned when a file does not open with the secret
+	// given, or has been tampered with
+	ErrWrongKey = errors.New("wrong passphrase or key")
+
+	// ErrNoSecret is returned when a file needs a secret the cipher was
+	// not given
+	ErrNoSecret = errors.New("no passphrase or key to decrypt with")
+)
+
+// envelope is a sealed file
+type envelope struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Kind       Kind   `json:"kind"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// IsSealed reports whether data is a file sealed by this package
+func IsSealed(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+	var e struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(data, &e) == nil && e.Format == envelopeFormat
+}
+
+// SealedWith returns the kind of secret data is sealed with, or KindNone
+// for data this package did not seal
+func SealedWith(data []byte) Kind {
+	if !IsSealed(data) {
+		return KindNone
+	}
+	var e struct {
+		Kind Kind `json:"kind"`
+	}
+	if json.Unmarshal(data, &e) != nil {
+		return KindNone
+	}
+	return e.Kind
+}
+
+// Secret fetches a passphrase or key. It is called at most once per
+// Cipher, when a file first needs it, so prompts and keychain lookups only
+// happen for encrypted data.
+type Secret func() ([]byte, error)
+
+// Cipher seals and opens files. It seals with one kind of secret and can
+// open files sealed with either, so a board can switch between them.
+type Cipher struct {
+	seal       Kind
+	passphrase Secret
+	key        Secret
+
+	mu      sync.Mutex
+	secrets map[Kind][]byte
+	derived map[string][]byte // passphrase keys by salt
+	salt    []byte            // salt for files this cipher seals
+}
+
+// New returns a cipher sealing with kind, which may be KindNone to write
+// files in the clear while still opening sealed ones. Either secret may be
+// nil when it is not available.
+func New(seal Kind, passphrase, key Secret) *Cipher {
+	return &Cipher{
+		seal:       seal,
+		passphrase: passphrase,
+		key:        key,
+		secrets:    make(map[Kind][]byte),
+		derived:    make(map[string][]byte),
+	}
+}
+
+// Seals reports whether Seal encrypts
+func (c *Cipher) Seals() bool {
+	return c != nil && c.seal != KindNone
+}
+
+// Seal encrypts plain, or returns it unchanged when the cipher seals
+// nothing
+func (c *Cipher) Seal(plain []byte) ([]byte, error) {
+	if !c.Seals() {
+		return plain, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := envelope{Format: envelopeFormat, Version: 1, Kind: c.seal}
+	if c.seal == KindPassphrase {
+		if c.salt == nil {
+			c.salt = make([]byte, saltSize)
+			if _, err := rand.Read(c.salt); err != nil {
+				return nil, err
+			}
+		}
+		e.Iterations, e.Salt = Iterations, c.salt
+	}
+	key, err := c.keyFor(e)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	e.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, err
+	}
+	e.Data = aead.Seal(nil, e.Nonce, plain, []byte(e.Kind))
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Open decrypts data sealed by any cipher given the same secret. Data that
+// is not sealed is returned unchanged.
+func (c *Cipher) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, ErrNoSecret
+	}
+	var e envelope
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("invalid encrypted file: %w", err)
+	}
+	if e.Version != 1 {
+		return nil, fmt.Errorf("unsupported encrypted file version %d", e.Version)
+	}
+
+	c.mu.Lock()
+	key, err := c.keyFor(e)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongKey
+	}
+	plain, err := aead.Open(nil, e.Nonce, e.Data, []byte(e.Kind))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// keyFor returns the AES key for an envelope's kind and salt. It is
+// called with mu held.
+func (c *Cipher) keyFor(e envelope) ([]byte, error) {
+	switch e.Kind {
+	case KindKeychain:
+		key, err := c.secret(KindKeychain, c.key)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("keychain key is %d bytes; want %d", len(key), keySize)
+		}
+		return key, nil
+	case KindPassphrase:
+		if key, ok := c.derived[string(e.Salt)]; ok {
+			return key, nil
+		}
+		pass, err := c.secret(KindPassphrase, c.passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if e.Iterations < 1 || len(e.Salt) == 0 {
+			return nil, errors.New("invalid encrypted file: missing key derivation parameters")
+		}
+		key, err := pbkdf2.Key(sha256.New, string(pass), e.Salt, e.Iterations, keySize)
+		if err != nil {
+			return nil, err
+		}
+		c.derived[string(e.Salt)] = key
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported encryption %q", e.Kind)
+}
+
+// secret fetches and remembers one kind of secret. It is called with mu
+// held.
+func (c *Cipher) secret(kind Kind, fetch Secret) ([]byte, error) {
+	if s, ok := c.secrets[kind]; ok {
+		return s, nil
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf("%w: file is sealed with a %s", ErrNoSecret, kind)
+	}
+	s, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if len(s) == 0 {
+		return nil, fmt.Errorf("%w: empty %s", ErrNoSecret, kind)
+	}
+	c.secrets[kind] = s
+	return s, nil
+}
+
+// NewKey returns a random key for KindKeychain
+func NewKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,198 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func init() {
+	// Keep the tests fast; the work factor is stored in each file
+	Iterations = 1000
+}
+
+func passphrase(s string) Secret {
+	return func() ([]byte, error) { return []byte(s), nil }
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	key, _ := NewKey()
+	plain := []byte(`{"tickets": {"a": {"description": "client: Acme"}}}`)
+
+	for _, kind := range []Kind{KindPassphrase, KindKeychain} {
+		c := New(kind, passphrase("hunter2"), func() ([]byte, error) { return key, nil })
+		sealed, err := c.Seal(plain)
+		if err != nil {
+			t.Fatalf("%s: Seal: %v", kind, err)
+		}
+		if !IsSealed(sealed) || bytes.Contains(sealed, []byte("Acme")) {
+			t.Fatalf("%s: sealed file is not encrypted: %s", kind, sealed)
+		}
+
+		// A fresh cipher with the same secrets opens it
+		other := New(KindNone, passphrase("hunter2"), func() ([]byte, error) { return key, nil })
+		got, err := other.Open(sealed)
+		if err != nil || !bytes.Equal(got, plain) {
+			t.Errorf("%s: Open = %q, %v; want the original", kind, got, err)
+		}
+	}
+}
+
+func TestCipher_WrongPassphrase(t *testing.T) {
+	sealed, _ := New(KindPassphrase, passphrase("right"), nil).Seal([]byte("secret"))
+	if _, err := New(KindPassphrase, passphrase("wrong"), nil).Open(sealed); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Open with the wrong passphrase: error = %v; want ErrWrongKey", err)
+	}
+}
+
+func TestCipher_Tampered(t *testing.T) {
+	c := New(KindPassphrase, passphrase("pass"), nil)
+	sealed, _ := c.Seal([]byte("secret"))
+	// Claiming the file is keychain-sealed must not get past authentication
+	tampered := bytes.Replace(sealed, []byte(`"kind": "passphrase"`), []byte(`"kind": "keychain"`), 1)
+	key, _ := NewKey()
+	c2 := New(KindNone, passphrase("pass"), func() ([]byte, error) { return key, nil })
+	if _, err := c2.Open(tampered); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Open(tampered) error = %v; want ErrWrongKey", err)
+	}
+}
+
+func TestCipher_Plain(t *testing.T) {
+	plain := []byte(`{"tickets": {}}`)
+	var none *Cipher
+	if got, err := none.Open(plain); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("nil cipher Open(plain) = %q, %v", got, err)
+	}
+	if got, _ := New(KindNone, nil, nil).Seal(plain); !bytes.Equal(got, plain) {
+		t.Errorf("KindNone Seal changed the data: %q", got)
+	}
+
+	sealed, _ := New(KindPassphrase, passphrase("pass"), nil).Seal(plain)
+	if _, err := none.Open(sealed); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("nil cipher Open(sealed) error = %v; want ErrNoSecret", err)
+	}
+	if _, err := New(KindNone, nil, nil).Open(sealed); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("Open without a passphrase: error = %v; want ErrNoSecret", err)
+	}
+}
+
+func TestCipher_AsksOnce(t *testing.T) {
+	asked := 0
+	c := New(KindPassphrase, func() ([]byte, error) { asked++; return []byte("pass"), nil }, nil)
+	for i := 0; i < 3; i++ {
+		sealed, err := c.Seal([]byte("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Open(sealed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if asked != 1 {
+		t.Errorf("passphrase asked for %d times; want 1", asked)
+	}
+}
+
+// fakeSecurity stands in for macOS security, keeping one entry
+type fakeSecurity struct {
+	t      *testing.T
+	stored string
+	err    error // returned by every lookup when set
+}
+
+func (f *fakeSecurity) run(stdin, name string, args ...string) (string, error) {
+	for _, a := range args {
+		if f.stored != "" && strings.Contains(a, f.stored) {
+			f.t.Errorf("key passed on the command line: %s %s", name, strings.Join(args, " "))
+		}
+	}
+	switch {
+	case args[0] == "find-generic-password":
+		if f.err != nil {
+			return "", f.err
+		}
+		if f.stored == "" {
+			return "", &keychainError{tool: name, code: securityNotFound, msg: "The specified item could not be found in the keychain."}
+		}
+		return f.stored, nil
+	case args[0] == "-i" && strings.HasPrefix(stdin, "add-generic-password "):
+		if strings.Contains(stdin, " -U") {
+			f.t.Errorf("store would replace an existing entry: %q", stdin)
+		}
+		if f.stored == "" {
+			fields := strings.Fields(stdin)
+			f.stored = fields[len(fields)-1]
+		}
+		return "", nil
+	}
+	f.t.Fatalf("unexpected keychain call %s %s", name, strings.Join(args, " "))
+	return "", nil
+}
+
+func TestKeychainSecret(t *testing.T) {
+	defer func(f func(string, string, ...string) (string, error)) { runKeychain = f }(runKeychain)
+	fake := &fakeSecurity{t: t}
+	runKeychain = fake.run
+
+	if _, err := keychainLookup("darwin", "tickets"); !errors.Is(err, errKeyNotFound) {
+		t.Fatalf("lookup of a missing key: error = %v", err)
+	}
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keychainStore("darwin", "tickets", key); err != nil {
+		t.Fatal(err)
+	}
+	got, err := keychainLookup("darwin", "tickets")
+	if err != nil || !bytes.Equal(got, key) {
+		t.Errorf("lookup = %x, %v; want the stored key", got, err)
+	}
+	other, _ := NewKey()
+	if err := keychainStore("darwin", "tickets", other); err == nil {
+		t.Error("store replaced an existing key")
+	}
+	if _, err := keychainLookup("windows", "tickets"); !errors.Is(err, ErrNoKeychain) {
+		t.Errorf("windows lookup error = %v; want ErrNoKeychain", err)
+	}
+}
+
+func TestKeychainSecret_Create(t *testing.T) {
+	defer func(f func(string, string, ...string) (string, error)) { runKeychain = f }(runKeychain)
+	defer func(goos string) { keychainOS = goos }(keychainOS)
+	keychainOS = "darwin"
+
+	t.Run("locked keychain", func(t *testing.T) {
+		fake := &fakeSecurity{t: t, err: &keychainError{tool: "security", code: 36, msg: "User interaction is not allowed."}}
+		runKeychain = fake.run
+		if _, err := KeychainSecret("tickets", func() error { return nil })(); err == nil || errors.Is(err, errKeyNotFound) {
+			t.Errorf("error = %v; want the keychain failure", err)
+		}
+		if fake.stored != "" {
+			t.Error("a new key was created over a keychain that could not be read")
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		fake := &fakeSecurity{t: t}
+		runKeychain = fake.run
+		refuse := errors.New("files sealed with the missing key")
+		if _, err := KeychainSecret("tickets", func() error { return refuse })(); !errors.Is(err, refuse) {
+			t.Errorf("error = %v; want the refusal", err)
+		}
+		if fake.stored != "" {
+			t.Error("a new key was created after being refused")
+		}
+	})
+
+	t.Run("created", func(t *testing.T) {
+		fake := &fakeSecurity{t: t}
+		runKeychain = fake.run
+		key, err := KeychainSecret("tickets", func() error { return nil })()
+		if err != nil || len(key) != keySize || fake.stored != hex.EncodeToString(key) {
+			t.Errorf("KeychainSecret() = %x, %v; want a new stored key", key, err)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService names openkanban's entries in the keychain
+const keychainService = "openkanban"
+
+// securityNotFound is the exit status of macOS security for a missing item
+// (errSecItemNotFound)
+const securityNotFound = 44
+
+// keychainOS picks the keychain tool
+var keychainOS = runtime.GOOS
+
+// ErrNoKeychain is returned where no supported keychain tool is installed
+var ErrNoKeychain = errors.New("no keychain available: needs macOS security or secret-tool (libsecret)")
+
+// errKeyNotFound is returned by a keychain lookup that found nothing
+var errKeyNotFound = errors.New("no key in the keychain")
+
+// keychainError is a keychain tool failing
+type keychainError struct {
+	tool string
+	code int    // exit status, or -1 when the tool did not run to completion
+	msg  string // what it printed on stderr
+	err  error
+}
+
+func (e *keychainError) Error() string {
+	if e.msg != "" {
+		return e.tool + ": " + e.msg
+	}
+	return e.tool + ": " + e.err.Error()
+}
+
+func (e *keychainError) Unwrap() error { return e.err }
+
+// runKeychain runs a keychain tool with stdin, returning its trimmed output
+var runKeychain = func(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		code := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		}
+		return "", &keychainError{tool: name, code: code, msg: strings.TrimSpace(stderr.String()), err: err}
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// KeychainSecret is a Secret reading the key stored under account in the
+// OS keychain. With canCreate set, a missing key is generated and stored
+// once canCreate agrees; it is the place to refuse when data is already
+// sealed with a key that has gone missing.
+func KeychainSecret(account string, canCreate func() error) Secret {
+	return func() ([]byte, error) {
+		key, err := keychainLookup(keychainOS, account)
+		if errors.Is(err, errKeyNotFound) && canCreate != nil {
+			if err := canCreate(); err != nil {
+				return nil, err
+			}
+			if key, err = NewKey(); err != nil {
+				return nil, err
+			}
+			err = keychainStore(keychainOS, account, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+}
+
+func keychainLookup(goos, account string) ([]byte, error) {
+	var out string
+	var err error
+	var kerr *keychainError
+	switch goos {
+	case "darwin":
+		out, err = runKeychain("", "security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+		if errors.As(err, &kerr) && kerr.code == securityNotFound {
+			return nil, errKeyNotFound
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, lookErr := exec.LookPath("secret-tool"); lookErr != nil {
+			return nil, ErrNoKeychain
+		}
+		out, err = runKeychain("", "secret-tool", "lookup", "service", keychainService, "account", account)
+		// secret-tool exits 1 without a word for a missing entry, and
+		// explains anything else, such as a locked or absent keyring
+		if errors.As(err, &kerr) && kerr.code == 1 && kerr.msg == "" {
+			return nil, errKeyNotFound
+		}
+	default:
+		return nil, ErrNoKeychain
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key from the keychain: %w", err)
+	}
+	if out == "" {
+		return nil, errKeyNotFound
+	}
+	key, err := hex.DecodeString(out)
+	if err != nil {
+		return nil, fmt.Errorf("keychain entry %s/%s is not a key: %w", keychainService, account, err)
+	}
+	return key, nil
+}
+
+// keychainStore adds a new entry for key, never replacing one, and reads
+// it back to make sure it landed. The key goes to the tool on stdin, out of
+// sight of ps.
+func keychainStore(goos, account string, key []byte) error {
+	secret := hex.EncodeToString(key)
+	var err error
+	switch goos {
+	case "darwin":
+		// security only takes a password on its command line, so give it
+		// the command on stdin in interactive mode
+		_, err = runKeychain(fmt.Sprintf("add-generic-password -s %s -a %s -w %s\n", keychainService, account, secret), "security", "-i")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		_, err = runKeychain(secret, "secret-tool", "store", "--label", "openkanban "+account+" key", "service", keychainService, "account", account)
+	default:
+		return ErrNoKeychain
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store key in the keychain: %w", err)
+	}
+	// security -i reports a failed command on stderr but still exits 0
+	stored, err := keychainLookup(goos, account)
+	if err != nil {
+		return fmt.Errorf("failed to store key in the keychain: %w", err)
+	}
+	if !bytes.Equal(stored, key) {
+		return fmt.Errorf("failed to store key in the keychain: entry %s/%s already holds another key", keychainService, account)
+	}
+	return nil
+}
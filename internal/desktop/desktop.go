@@ -0,0 +1,60 @@
+// Package desktop shows native desktop notifications through the tools each
+// platform ships or commonly has installed.
+package desktop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned where no notification tool is available
+var ErrUnsupported = errors.New("no desktop notification tool found")
+
+// timeout bounds how long the notification tool may run
+const timeout = 10 * time.Second
+
+// Notify shows a desktop notification with title and body
+func Notify(title, body string) error {
+	name, args, err := command(runtime.GOOS, exec.LookPath, title, body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, name, args...).CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s failed: %s", name, msg)
+	}
+	return nil
+}
+
+// command picks the notification tool for goos: terminal-notifier or
+// osascript on macOS, notify-send elsewhere
+func command(goos string, lookPath func(string) (string, error), title, body string) (string, []string, error) {
+	if goos == "darwin" {
+		if _, err := lookPath("terminal-notifier"); err == nil {
+			return "terminal-notifier", []string{"-title", title, "-message", body, "-group", "openkanban"}, nil
+		}
+		script := fmt.Sprintf("display notification %s with title %s", appleString(body), appleString(title))
+		return "osascript", []string{"-e", script}, nil
+	}
+	if _, err := lookPath("notify-send"); err == nil {
+		return "notify-send", []string{"--app-name=openkanban", title, body}, nil
+	}
+	return "", nil, ErrUnsupported
+}
+
+// appleString quotes s as an AppleScript string literal, which uses the same
+// escapes as Go for quotes and backslashes
+func appleString(s string) string {
+	return strconv.Quote(strings.ReplaceAll(s, "\n", " "))
+}
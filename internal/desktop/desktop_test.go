@@ -0,0 +1,32 @@
+package desktop
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	has := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			if slices.Contains(tools, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	name, args, _ := command("darwin", has(), "Done", `Fix "login"`)
+	if name != "osascript" || args[1] != `display notification "Fix \"login\"" with title "Done"` {
+		t.Errorf("command() = %s %q; want osascript", name, args)
+	}
+	if name, _, _ := command("darwin", has("terminal-notifier"), "Done", "x"); name != "terminal-notifier" {
+		t.Errorf("command() = %s; want terminal-notifier when installed", name)
+	}
+	if name, args, _ := command("linux", has("notify-send"), "Done", "x"); name != "notify-send" || args[1] != "Done" {
+		t.Errorf("command() = %s %q; want notify-send", name, args)
+	}
+	if _, _, err := command("linux", has(), "Done", "x"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("command() without tools error = %v; want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package digest summarises board activity over a period: tickets completed,
+// agent runs that failed, and in-progress tickets that have stalled.
+package digest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Item is one line of a digest section
+type Item struct {
+	Project string
+	Title   string
+	At      time.Time
+	Detail  string
+}
+
+// Digest is the result of Build
+type Digest struct {
+	From      time.Time
+	To        time.Time
+	Completed []Item
+	Failed    []Item
+	Stalled   []Item
+}
+
+// Options controls what Build includes
+type Options struct {
+	From time.Time
+	To   time.Time
+
+	// StallAfter is how long an in-progress ticket may go without activity
+	// before it counts as stalled
+	StallAfter time.Duration
+
+	// ProjectNames maps project IDs to display names
+	ProjectNames map[string]string
+}
+
+// Build summarises tickets for [From, To). Stalled tickets are judged at To.
+func Build(tickets []*board.Ticket, opts Options) *Digest {
+	d := &Digest{From: opts.From, To: opts.To}
+	in := func(t time.Time) bool {
+		return !t.Before(opts.From) && t.Before(opts.To)
+	}
+
+	for _, t := range tickets {
+		project := opts.ProjectNames[t.ProjectID]
+		if project == "" {
+			project = t.ProjectID
+		}
+
+		if t.CompletedAt != nil && in(*t.CompletedAt) && (t.Status == board.StatusDone || t.Status == board.StatusArchived) {
+			d.Completed = append(d.Completed, Item{project, t.Title, *t.CompletedAt, cycleTime(t)})
+		}
+
+		for _, run := range t.AgentRuns {
+			if run.Outcome == board.OutcomeFailed && run.EndedAt != nil && in(*run.EndedAt) {
+				d.Failed = append(d.Failed, Item{project, t.Title, *run.EndedAt, run.Agent})
+			}
+		}
+
+		if t.Status == board.StatusInProgress && opts.StallAfter > 0 {
+			last := lastActivity(t)
+			if idle := opts.To.Sub(last); idle >= opts.StallAfter {
+				d.Stalled = append(d.Stalled, Item{project, t.Title, last, "idle " + formatDuration(idle)})
+			}
+		}
+	}
+
+	for _, items := range [][]Item{d.Completed, d.Failed, d.Stalled} {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].At.Before(items[j].At) })
+	}
+	return d
+}
+
+// lastActivity is the latest change to a ticket or its agent runs
+func lastActivity(t *board.Ticket) time.Time {
+	last := t.UpdatedAt
+	for _, run := range t.AgentRuns {
+		if run.EndedAt == nil {
+			// An agent is still running; it counts as activity until it ends
+			return time.Now()
+		}
+		if run.EndedAt.After(last) {
+			last = *run.EndedAt
+		}
+	}
+	return last
+}
+
+func cycleTime(t *board.Ticket) string {
+	if t.StartedAt == nil || t.CompletedAt == nil {
+		return ""
+	}
+	return "cycle time " + formatDuration(t.CompletedAt.Sub(*t.StartedAt))
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// Empty reports whether nothing happened in the period
+func (d *Digest) Empty() bool {
+	return len(d.Completed) == 0 && len(d.Failed) == 0 && len(d.Stalled) == 0
+}
+
+// Subject is a one-line summary, used as the email subject
+func (d *Digest) Subject() string {
+	return fmt.Sprintf("openkanban digest %s: %d done, %d failed, %d stalled",
+		d.period(), len(d.Completed), len(d.Failed), len(d.Stalled))
+}
+
+func (d *Digest) period() string {
+	const layout = "2006-01-02"
+	last := d.To.Add(-time.Nanosecond)
+	if d.From.Format(layout) == last.Format(layout) {
+		return d.From.Format(layout)
+	}
+	return d.From.Format(layout) + " to " + last.Format(layout)
+}
+
+// WriteMarkdown renders the digest as Markdown
+func (d *Digest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Board digest, %s\n", d.period())
+	writeSection(&b, "Completed", d.Completed, "No tickets completed.")
+	writeSection(&b, "Failed agents", d.Failed, "No agent failures.")
+	writeSection(&b, "Stalled", d.Stalled, "Nothing stalled.")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSection(b *strings.Builder, title string, items []Item, empty string) {
+	fmt.Fprintf(b, "\n## %s (%d)\n\n", title, len(items))
+	if len(items) == 0 {
+		b.WriteString(empty + "\n")
+		return
+	}
+	for _, it := range items {
+		line := fmt.Sprintf("- **%s** %s", it.Project, it.Title)
+		if it.Detail != "" {
+			line += " (" + it.Detail + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+}
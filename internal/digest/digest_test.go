@@ -0,0 +1,85 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	from, to := day, day.AddDate(0, 0, 1)
+
+	done := &board.Ticket{ProjectID: "p1", Title: "Fix login", Status: board.StatusDone,
+		StartedAt: ptr(day.Add(-3 * time.Hour)), CompletedAt: ptr(day.Add(2 * time.Hour))}
+	oldDone := &board.Ticket{ProjectID: "p1", Title: "Old", Status: board.StatusDone,
+		CompletedAt: ptr(day.Add(-time.Hour))}
+	failing := &board.Ticket{ProjectID: "p2", Title: "Flaky tests", Status: board.StatusInProgress,
+		UpdatedAt: day.Add(5 * time.Hour),
+		AgentRuns: []board.AgentRun{
+			{Agent: "claude", StartedAt: day.Add(4 * time.Hour), EndedAt: ptr(day.Add(5 * time.Hour)), Outcome: board.OutcomeFailed},
+			{Agent: "claude", StartedAt: day.Add(-48 * time.Hour), EndedAt: ptr(day.Add(-47 * time.Hour)), Outcome: board.OutcomeFailed},
+		}}
+	stalled := &board.Ticket{ProjectID: "p1", Title: "Refactor", Status: board.StatusInProgress,
+		UpdatedAt: day.Add(-4 * 24 * time.Hour)}
+	running := &board.Ticket{ProjectID: "p1", Title: "Long run", Status: board.StatusInProgress,
+		UpdatedAt: day.Add(-10 * 24 * time.Hour),
+		AgentRuns: []board.AgentRun{{Agent: "claude", StartedAt: day.Add(-10 * 24 * time.Hour), Outcome: board.OutcomeRunning}}}
+
+	d := Build([]*board.Ticket{done, oldDone, failing, stalled, running}, Options{
+		From: from, To: to, StallAfter: 3 * 24 * time.Hour,
+		ProjectNames: map[string]string{"p1": "app"},
+	})
+
+	if len(d.Completed) != 1 || d.Completed[0].Title != "Fix login" || d.Completed[0].Detail != "cycle time 5h" {
+		t.Errorf("Completed = %+v", d.Completed)
+	}
+	if len(d.Failed) != 1 || d.Failed[0].Project != "p2" || d.Failed[0].Detail != "claude" {
+		t.Errorf("Failed = %+v", d.Failed)
+	}
+	if len(d.Stalled) != 1 || d.Stalled[0].Title != "Refactor" || d.Stalled[0].Detail != "idle 5d" {
+		t.Errorf("Stalled = %+v", d.Stalled)
+	}
+	if got := d.Subject(); got != "openkanban digest 2026-03-10: 1 done, 1 failed, 1 stalled" {
+		t.Errorf("Subject() = %q", got)
+	}
+
+	var b strings.Builder
+	if err := d.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Board digest, 2026-03-10
+
+## Completed (1)
+
+- **app** Fix login (cycle time 5h)
+
+## Failed agents (1)
+
+- **p2** Flaky tests (claude)
+
+## Stalled (1)
+
+- **app** Refactor (idle 5d)
+`
+	if b.String() != want {
+		t.Errorf("WriteMarkdown() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestEmptyWeek(t *testing.T) {
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	d := Build(nil, Options{From: from, To: from.AddDate(0, 0, 7)})
+	if !d.Empty() {
+		t.Error("Empty() = false")
+	}
+	var b strings.Builder
+	_ = d.WriteMarkdown(&b)
+	if !strings.HasPrefix(b.String(), "# Board digest, 2026-03-02 to 2026-03-08\n") || !strings.Contains(b.String(), "Nothing stalled.") {
+		t.Errorf("WriteMarkdown() = %q", b.String())
+	}
+}
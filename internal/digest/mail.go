@@ -0,0 +1,127 @@
+package digest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PasswordEnv is the environment variable holding the SMTP password. It is
+// read from the environment so the secret stays out of the config file.
+const PasswordEnv = "OPENKANBAN_SMTP_PASSWORD"
+
+// SMTP is where digests are mailed
+type SMTP struct {
+	Host     string
+	Port     int // 465 uses implicit TLS; other ports upgrade with STARTTLS when offered
+	Username string
+	From     string
+	To       []string
+}
+
+// Send mails the digest as plain text, authenticating with PasswordEnv when
+// a username is set
+func Send(s SMTP, subject, body string) error {
+	if s.Host == "" || s.From == "" || len(s.To) == 0 {
+		return fmt.Errorf("set digest.smtp host, from and to to send digests")
+	}
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		password := os.Getenv(PasswordEnv)
+		if password == "" {
+			return fmt.Errorf("set %s to send digests as %s", PasswordEnv, s.Username)
+		}
+		auth = smtp.PlainAuth("", s.Username, password, s.Host)
+	}
+
+	// The envelope takes bare addresses; the headers keep display names
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", s.From, err)
+	}
+	rcpts := make([]string, 0, len(s.To))
+	for _, to := range s.To {
+		a, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		rcpts = append(rcpts, a.Address)
+	}
+
+	msg := message(s.From, s.To, subject, body, time.Now())
+	if port != 465 {
+		if err := smtp.SendMail(addr, auth, from.Address, rcpts, msg); err != nil {
+			return fmt.Errorf("failed to send digest: %w", err)
+		}
+		return nil
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: s.Host})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer c.Close()
+	if err := deliver(c, auth, from.Address, rcpts, msg); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	return nil
+}
+
+func deliver(c *smtp.Client, auth smtp.Auth, from string, to []string, msg []byte) error {
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message builds an RFC 5322 plain text message with CRLF line endings
+func message(from string, to []string, subject, body string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + subject + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
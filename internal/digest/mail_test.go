@@ -0,0 +1,87 @@
+package digest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	date := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	got := string(message("board@example.com", []string{"a@example.com", "b@example.com"}, "Digest", "# Hi\n\n- one\n", date))
+	want := "From: board@example.com\r\nTo: a@example.com, b@example.com\r\nSubject: Digest\r\n" +
+		"Date: Tue, 10 Mar 2026 08:00:00 +0000\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n" +
+		"# Hi\r\n\r\n- one\r\n"
+	if got != want {
+		t.Errorf("message() = %q\nwant %q", got, want)
+	}
+}
+
+func TestSend(t *testing.T) {
+	if err := Send(SMTP{Host: "localhost"}, "s", "b"); err == nil {
+		t.Error("Send() without from and to succeeded")
+	}
+	t.Setenv(PasswordEnv, "")
+	if err := Send(SMTP{Host: "localhost", Username: "me", From: "a@b", To: []string{"c@d"}}, "s", "b"); err == nil || !strings.Contains(err.Error(), PasswordEnv) {
+		t.Errorf("Send() without a password error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go serveSMTP(ln, received)
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	s := SMTP{Host: "127.0.0.1", Port: port, From: "board@example.com", To: []string{"Me <me@example.com>"}}
+	if err := Send(s, "Digest", "- one\n"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if data := <-received; !strings.Contains(data, "Subject: Digest\r\n") || !strings.HasSuffix(data, "- one\r\n") {
+		t.Errorf("server received %q", data)
+	}
+}
+
+// serveSMTP accepts one session from net/smtp and sends back its DATA
+func serveSMTP(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 test ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 test")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			received <- data.String()
+			reply("250 ok")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
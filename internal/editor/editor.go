@@ -0,0 +1,95 @@
+// Package editor opens ticket worktrees in the user's editor or IDE.
+package editor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoEditor is returned when neither editor.command nor $VISUAL/$EDITOR
+// names an editor
+var ErrNoEditor = errors.New("set editor.command or $EDITOR to open worktrees")
+
+// terminalEditors run in the terminal, so the board has to step aside for
+// them; anything else is treated as a GUI editor and left running
+var terminalEditors = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "nano": true, "micro": true,
+	"hx": true, "helix": true, "kak": true, "ne": true, "joe": true, "mg": true,
+}
+
+// workspaceEditors read VS Code style .code-workspace files
+var workspaceEditors = map[string]bool{
+	"code": true, "code-insiders": true, "codium": true, "cursor": true, "windsurf": true,
+}
+
+// Editor is a resolved editor command
+type Editor struct {
+	Args     []string // command and its arguments, before the path
+	Terminal bool     // runs in the terminal
+}
+
+// Resolve returns the editor for command, falling back to $VISUAL and
+// $EDITOR. Emacs counts as a terminal editor only with -nw or -t.
+func Resolve(command string) (Editor, error) {
+	for _, candidate := range []string{command, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		args := strings.Fields(candidate)
+		if len(args) == 0 {
+			continue
+		}
+		name := filepath.Base(args[0])
+		terminal := terminalEditors[name]
+		if name == "emacs" || name == "emacsclient" {
+			for _, a := range args[1:] {
+				terminal = terminal || a == "-nw" || a == "-t" || a == "--tty"
+			}
+		}
+		return Editor{Args: args, Terminal: terminal}, nil
+	}
+	return Editor{}, ErrNoEditor
+}
+
+// SupportsWorkspace reports whether the editor opens .code-workspace files
+func (e Editor) SupportsWorkspace() bool {
+	return workspaceEditors[filepath.Base(e.Args[0])]
+}
+
+// Command returns the command opening target, a directory or workspace file
+func (e Editor) Command(target string) *exec.Cmd {
+	args := append(append([]string{}, e.Args[1:]...), target)
+	return exec.Command(e.Args[0], args...)
+}
+
+// WorkspacePath is where the workspace file of a worktree is kept: beside
+// the worktree rather than in it, so it never shows up as a change
+func WorkspacePath(worktree string) string {
+	return filepath.Clean(worktree) + ".code-workspace"
+}
+
+// WriteWorkspace writes a VS Code workspace file for the worktree, titling
+// the window after the ticket so several worktrees can be told apart
+func WriteWorkspace(worktree, title string) (string, error) {
+	abs, err := filepath.Abs(worktree)
+	if err != nil {
+		return "", err
+	}
+	workspace := map[string]any{
+		"folders": []map[string]string{{"path": abs, "name": title}},
+		"settings": map[string]string{
+			"window.title": title + " — ${activeEditorShort}${separator}${rootName}",
+		},
+	}
+	data, err := json.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := WorkspacePath(abs)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write workspace file: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nvim")
+
+	tests := []struct {
+		command   string
+		name      string
+		terminal  bool
+		workspace bool
+	}{
+		{"code --new-window", "code", false, true},
+		{"/usr/local/bin/zed", "/usr/local/bin/zed", false, false},
+		{"emacs -nw", "emacs", true, false},
+		{"emacs", "emacs", false, false},
+		{"", "nvim", true, false},
+	}
+	for _, tt := range tests {
+		e, err := Resolve(tt.command)
+		if err != nil {
+			t.Fatalf("Resolve(%q) error = %v", tt.command, err)
+		}
+		if e.Args[0] != tt.name || e.Terminal != tt.terminal || e.SupportsWorkspace() != tt.workspace {
+			t.Errorf("Resolve(%q) = %+v; want %s, terminal %v, workspace %v", tt.command, e, tt.name, tt.terminal, tt.workspace)
+		}
+	}
+
+	t.Setenv("EDITOR", "")
+	if _, err := Resolve(""); !errors.Is(err, ErrNoEditor) {
+		t.Errorf("Resolve() without an editor error = %v; want ErrNoEditor", err)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	e, _ := Resolve("code --new-window")
+	cmd := e.Command("/tmp/wt")
+	if got := cmd.Args; len(got) != 3 || got[1] != "--new-window" || got[2] != "/tmp/wt" {
+		t.Errorf("Command() args = %q", got)
+	}
+}
+
+func TestWriteWorkspace(t *testing.T) {
+	worktree := filepath.Join(t.TempDir(), "fix-login")
+	if err := os.Mkdir(worktree, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := WriteWorkspace(worktree+"/", "Fix login")
+	if err != nil || path != worktree+".code-workspace" {
+		t.Fatalf("WriteWorkspace() = %q, %v", path, err)
+	}
+	var ws struct {
+		Folders []struct{ Path, Name string }
+	}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &ws); err != nil || ws.Folders[0].Path != worktree || ws.Folders[0].Name != "Fix login" {
+		t.Errorf("workspace = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 64
+
+// Bus fans out events to any number of subscribers. Publish never blocks:
+// a subscriber that stops reading misses events instead of stalling the board.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every published event and a function
+// that unsubscribes and closes the channel.
+func (b *Bus) Subscribe() (<-chan Event, func()) {
+	return b.SubscribeBuffered(subscriberBuffer)
+}
+
+// SubscribeBuffered is Subscribe with room for size events, for subscribers
+// that must not miss a burst of them.
+func (b *Bus) SubscribeBuffered(size int) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers e to every subscriber that has room for it.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
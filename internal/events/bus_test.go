@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	first, cancelFirst := bus.Subscribe()
+	second, cancelSecond := bus.Subscribe()
+	defer cancelSecond()
+
+	bus.Publish(Event{Type: TicketCreated})
+
+	for _, ch := range []<-chan Event{first, second} {
+		select {
+		case e := <-ch:
+			if e.Type != TicketCreated {
+				t.Errorf("received %s; want %s", e.Type, TicketCreated)
+			}
+		default:
+			t.Fatal("subscriber did not receive event")
+		}
+	}
+
+	cancelFirst()
+	cancelFirst()
+	if _, ok := <-first; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+
+	bus.Publish(Event{Type: TicketMoved})
+	if e := <-second; e.Type != TicketMoved {
+		t.Errorf("received %s; want %s", e.Type, TicketMoved)
+	}
+}
+
+func TestBus_SlowSubscriberDropsEvents(t *testing.T) {
+	bus := NewBus()
+	ch, cancel := bus.Subscribe()
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		bus.Publish(Event{Type: TicketMoved})
+	}
+
+	if len(ch) != subscriberBuffer {
+		t.Errorf("buffered %d events; want %d", len(ch), subscriberBuffer)
+	}
+}
+
+func TestBus_SubscribeBuffered(t *testing.T) {
+	bus := NewBus()
+	ch, cancel := bus.SubscribeBuffered(subscriberBuffer * 4)
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		bus.Publish(Event{Type: TicketChanged})
+	}
+
+	if len(ch) != subscriberBuffer*2 {
+		t.Errorf("buffered %d events; want %d", len(ch), subscriberBuffer*2)
+	}
+}
+
+func TestBus_NilPublish(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Event{Type: TicketCreated})
+}
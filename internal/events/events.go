@@ -0,0 +1,149 @@
+// Package events defines board events and runs user-configured hooks for them.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/secretenv"
+)
+
+// Type identifies a board event.
+type Type string
+
+const (
+	TicketCreated  Type = "ticket.created"
+	TicketMoved    Type = "ticket.moved"
+	TicketMerged   Type = "ticket.merged"
+	AgentCompleted Type = "agent.completed"
+	AgentFailed    Type = "agent.failed"
+	AgentWaiting   Type = "agent.waiting"
+)
+
+// Types lists every event that hooks can subscribe to.
+var Types = []Type{
+	TicketCreated,
+	TicketMoved,
+	TicketMerged,
+	AgentCompleted,
+	AgentFailed,
+	AgentWaiting,
+}
+
+// State events mirror the board for views kept in step with it, such as
+// openkanban watch and the board itself. They fire on every change, so hooks
+// and webhooks can't subscribe to them.
+const (
+	TicketChanged     Type = "ticket.changed"
+	TicketDeleted     Type = "ticket.deleted"
+	AgentStateChanged Type = "agent.state"
+)
+
+// IsValid reports whether t is a known event type.
+func IsValid(t string) bool {
+	for _, known := range Types {
+		if string(known) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the payload passed to hooks as JSON on stdin.
+type Event struct {
+	Type    Type          `json:"type"`
+	Time    time.Time     `json:"time"`
+	Project string        `json:"project,omitempty"`
+	Ticket  *board.Ticket `json:"ticket,omitempty"`
+
+	// From and To are set for ticket.moved
+	From board.TicketStatus `json:"from,omitempty"`
+	To   board.TicketStatus `json:"to,omitempty"`
+
+	// Error is set for agent.failed
+	Error string `json:"error,omitempty"`
+
+	// AgentFrom and AgentTo are set for agent.state
+	AgentFrom board.AgentStatus `json:"agent_from,omitempty"`
+	AgentTo   board.AgentStatus `json:"agent_to,omitempty"`
+}
+
+// New creates an event for ticket stamped with the current time.
+func New(t Type, ticket *board.Ticket, projectName string) Event {
+	return Event{Type: t, Time: time.Now(), Project: projectName, Ticket: ticket}
+}
+
+// HookTimeout bounds how long a single hook command may run.
+const HookTimeout = 30 * time.Second
+
+// HookRunner executes shell commands configured per event type.
+type HookRunner struct {
+	hooks map[string][]string
+}
+
+// NewHookRunner creates a runner for the given event-to-commands mapping.
+func NewHookRunner(hooks map[string][]string) *HookRunner {
+	return &HookRunner{hooks: hooks}
+}
+
+// Has reports whether any hook is configured for t.
+func (r *HookRunner) Has(t Type) bool {
+	return r != nil && len(r.hooks[string(t)]) > 0
+}
+
+// Run executes every hook registered for e.Type with the event JSON on stdin.
+// All hooks run even if one fails; failures are joined into the returned error.
+func (r *HookRunner) Run(e Event) error {
+	if !r.Has(e.Type) {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	var errs []error
+	for _, command := range r.hooks[string(e.Type)] {
+		if err := runHook(command, e, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runHook(command string, e Event, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), HookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(secretenv.Environ(), "OPENKANBAN_EVENT="+string(e.Type))
+	if e.Ticket != nil {
+		cmd.Env = append(cmd.Env, "OPENKANBAN_TICKET_ID="+string(e.Ticket.ID))
+		if info, err := os.Stat(e.Ticket.WorktreePath); err == nil && info.IsDir() {
+			cmd.Dir = e.Ticket.WorktreePath
+		}
+	}
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("hook %q timed out after %s", command, HookTimeout)
+	}
+	if err != nil {
+		msg := strings.TrimSpace(string(output))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("hook %q failed: %s", command, msg)
+	}
+	return nil
+}
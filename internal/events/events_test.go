@@ -0,0 +1,81 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestHookRunner_Run(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event.json")
+	runner := NewHookRunner(map[string][]string{
+		string(TicketMoved): {"cat > " + out},
+	})
+
+	ticket := board.NewTicket("Hook test", "project-1")
+	e := New(TicketMoved, ticket, "demo")
+	e.From = board.StatusBacklog
+	e.To = board.StatusInProgress
+
+	if err := runner.Run(e); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not write output: %v", err)
+	}
+
+	var got Event
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook stdin was not valid JSON: %v", err)
+	}
+	if got.Type != TicketMoved || got.To != board.StatusInProgress || got.Ticket.ID != ticket.ID {
+		t.Errorf("hook received %+v; want moved event for %s", got, ticket.ID)
+	}
+}
+
+func TestHookRunner_EnvAndErrors(t *testing.T) {
+	runner := NewHookRunner(map[string][]string{
+		string(AgentFailed): {
+			`test "$OPENKANBAN_EVENT" = agent.failed`,
+			"echo boom >&2; exit 3",
+		},
+	})
+
+	err := runner.Run(New(AgentFailed, board.NewTicket("x", "p"), ""))
+	if err == nil {
+		t.Fatal("Run() error = nil; want failure from second hook")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Run() error = %v; want hook output in message", err)
+	}
+	if strings.Contains(err.Error(), "OPENKANBAN_EVENT") {
+		t.Errorf("env check hook failed: %v", err)
+	}
+}
+
+func TestHookRunner_NoHooks(t *testing.T) {
+	var nilRunner *HookRunner
+	if nilRunner.Has(TicketCreated) {
+		t.Error("nil runner should have no hooks")
+	}
+
+	runner := NewHookRunner(nil)
+	if err := runner.Run(New(TicketCreated, nil, "")); err != nil {
+		t.Errorf("Run() with no hooks error = %v", err)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	if !IsValid("agent.completed") {
+		t.Error("IsValid(agent.completed) = false; want true")
+	}
+	if IsValid("agent.exploded") {
+		t.Error("IsValid(agent.exploded) = true; want false")
+	}
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// TicketDone names ticket.moved events into the done column for
+// notifications, which usually care about nothing else a ticket does.
+const TicketDone Type = "ticket.done"
+
+// DefaultNotifyEvents are the events a webhook posts when it lists none.
+var DefaultNotifyEvents = []Type{AgentCompleted, AgentFailed, AgentWaiting, TicketDone}
+
+// Webhook services
+const (
+	Slack   = "slack"
+	Discord = "discord"
+)
+
+// defaultTemplates are the messages for events without a template of their
+// own, written to read well on both Slack and Discord.
+var defaultTemplates = map[Type]string{
+	TicketCreated:  `🆕 {{.Ticket.Title}} created{{with .Project}} in {{.}}{{end}}`,
+	TicketMoved:    `➡️ {{.Ticket.Title}} moved from {{.From}} to {{.To}}{{with .Project}} ({{.}}){{end}}`,
+	TicketDone:     `🎉 {{.Ticket.Title}} is done{{with .Project}} ({{.}}){{end}}`,
+	TicketMerged:   `🔀 {{.Ticket.Title}} merged into {{.Ticket.BaseBranch}}{{with .Project}} ({{.}}){{end}}`,
+	AgentCompleted: `✅ Agent finished {{.Ticket.Title}}{{with .Project}} ({{.}}){{end}}`,
+	AgentFailed:    `❌ Agent failed on {{.Ticket.Title}}{{with .Project}} ({{.}}){{end}}: {{.Error}}`,
+	AgentWaiting:   `⏳ Agent is waiting for input on {{.Ticket.Title}}{{with .Project}} ({{.}}){{end}}`,
+}
+
+// Webhook posts messages for board events to a Slack or Discord channel.
+type Webhook struct {
+	URL       string            `json:"url"`
+	Service   string            `json:"service,omitempty"`   // "slack" | "discord"; detected from the URL when empty
+	Events    []string          `json:"events,omitempty"`    // default: agent.completed, agent.failed, agent.waiting, ticket.done
+	Projects  []string          `json:"projects,omitempty"`  // project names; empty posts for every project
+	Templates map[string]string `json:"templates,omitempty"` // event to a Go template over the event
+}
+
+// IsNotifyEvent reports whether t names an event webhooks can post.
+func IsNotifyEvent(t string) bool {
+	return IsValid(t) || t == string(TicketDone)
+}
+
+// DetectService returns the webhook service for w, from the URL unless set.
+func (w Webhook) DetectService() string {
+	if w.Service != "" {
+		return w.Service
+	}
+	switch {
+	case strings.Contains(w.URL, "hooks.slack.com"):
+		return Slack
+	case strings.Contains(w.URL, "discord.com/api/webhooks"), strings.Contains(w.URL, "discordapp.com/api/webhooks"):
+		return Discord
+	}
+	return ""
+}
+
+// notifyType is the event name e is posted under.
+func notifyType(e Event) Type {
+	if e.Type == TicketMoved && e.To == board.StatusDone {
+		return TicketDone
+	}
+	return e.Type
+}
+
+// Wants reports whether w posts e.
+func (w Webhook) Wants(e Event) bool {
+	if len(w.Projects) > 0 && !slices.Contains(w.Projects, e.Project) {
+		return false
+	}
+	t := notifyType(e)
+	if len(w.Events) == 0 {
+		return slices.Contains(DefaultNotifyEvents, t)
+	}
+	// ticket.moved subscribers also hear about moves into done
+	return slices.Contains(w.Events, string(t)) ||
+		(t == TicketDone && slices.Contains(w.Events, string(TicketMoved)))
+}
+
+// Summary renders the default notification message for e.
+func Summary(e Event) string {
+	text, _ := Webhook{}.Message(e)
+	return text
+}
+
+// Message renders the text w posts for e.
+func (w Webhook) Message(e Event) (string, error) {
+	t := notifyType(e)
+	text, ok := w.Templates[string(t)]
+	if !ok && t == TicketDone {
+		text, ok = w.Templates[string(TicketMoved)]
+	}
+	if !ok {
+		text = defaultTemplates[t]
+	}
+	if e.Ticket == nil {
+		e.Ticket = &board.Ticket{}
+	}
+
+	tmpl, err := template.New(string(t)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template for %s: %w", t, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, e); err != nil {
+		return "", fmt.Errorf("failed to render message for %s: %w", t, err)
+	}
+	return b.String(), nil
+}
+
+// payload is the JSON body each service expects.
+func (w Webhook) payload(text string) any {
+	if w.DetectService() == Discord {
+		return map[string]string{"content": text}
+	}
+	return map[string]string{"text": text}
+}
+
+// Notifier posts events to the configured webhooks.
+type Notifier struct {
+	webhooks []Webhook
+	http     *http.Client
+}
+
+// NewNotifier creates a notifier for webhooks.
+func NewNotifier(webhooks []Webhook) *Notifier {
+	return &Notifier{webhooks: webhooks, http: &http.Client{Timeout: HookTimeout}}
+}
+
+// Wants reports whether any webhook posts e.
+func (n *Notifier) Wants(e Event) bool {
+	if n == nil {
+		return false
+	}
+	for _, w := range n.webhooks {
+		if w.Wants(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send posts e to every webhook that wants it. All webhooks are tried even
+// if one fails; failures are joined into the returned error.
+func (n *Notifier) Send(e Event) error {
+	if n == nil {
+		return nil
+	}
+	var errs []error
+	for _, w := range n.webhooks {
+		if !w.Wants(e) {
+			continue
+		}
+		if err := n.post(w, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) post(w Webhook, e Event) error {
+	text, err := w.Message(e)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(w.payload(text))
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.http.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The webhook URL is a secret, so leave it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to %s: %w", w.DetectService(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook returned %s", w.DetectService(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestWebhook_Wants(t *testing.T) {
+	ticket := board.NewTicket("Add login", "p1")
+	done := New(TicketMoved, ticket, "api")
+	done.To = board.StatusDone
+	moved := New(TicketMoved, ticket, "api")
+	moved.To = board.StatusInProgress
+
+	tests := []struct {
+		name string
+		hook Webhook
+		e    Event
+		want bool
+	}{
+		{"default events include done", Webhook{}, done, true},
+		{"default events skip other moves", Webhook{}, moved, false},
+		{"default events include waiting", Webhook{}, New(AgentWaiting, ticket, "api"), true},
+		{"project filter", Webhook{Projects: []string{"web"}}, done, false},
+		{"project match", Webhook{Projects: []string{"api"}}, done, true},
+		{"moved covers done", Webhook{Events: []string{"ticket.moved"}}, done, true},
+		{"explicit events", Webhook{Events: []string{"agent.failed"}}, New(AgentCompleted, ticket, "api"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.hook.Wants(tt.e); got != tt.want {
+			t.Errorf("%s: Wants() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWebhook_Message(t *testing.T) {
+	ticket := board.NewTicket("Add login", "p1")
+	e := New(AgentFailed, ticket, "api")
+	e.Error = "exit status 1"
+
+	got, err := Webhook{}.Message(e)
+	if err != nil || got != "❌ Agent failed on Add login (api): exit status 1" {
+		t.Errorf("Message() = %q, %v", got, err)
+	}
+
+	w := Webhook{Templates: map[string]string{"agent.failed": "{{.Ticket.Title}} broke"}}
+	if got, _ := w.Message(e); got != "Add login broke" {
+		t.Errorf("Message() with template = %q", got)
+	}
+
+	w.Templates["agent.failed"] = "{{.Nope"
+	if _, err := w.Message(e); err == nil {
+		t.Error("Message() with a broken template succeeded")
+	}
+}
+
+func TestNotifier_Send(t *testing.T) {
+	got := make(map[string]map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got[r.URL.Path] = body
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	n := NewNotifier([]Webhook{
+		{URL: srv.URL + "/slack", Service: Slack},
+		{URL: srv.URL + "/discord", Service: Discord},
+		{URL: srv.URL + "/broken", Service: Slack},
+	})
+	e := New(AgentCompleted, board.NewTicket("Add login", "p1"), "")
+	if err := n.Send(e); err == nil || err.Error() != "slack webhook returned 404 Not Found" {
+		t.Errorf("Send() error = %v; want the broken webhook's failure", err)
+	}
+	if got["/slack"]["text"] != "✅ Agent finished Add login" || got["/discord"]["content"] != "✅ Agent finished Add login" {
+		t.Errorf("posted %v", got)
+	}
+}
+
+func TestWebhook_DetectService(t *testing.T) {
+	if s := (Webhook{URL: "https://hooks.slack.com/services/T/B/x"}).DetectService(); s != Slack {
+		t.Errorf("DetectService() = %q; want slack", s)
+	}
+	if s := (Webhook{URL: "https://discord.com/api/webhooks/1/x"}).DetectService(); s != Discord {
+		t.Errorf("DetectService() = %q; want discord", s)
+	}
+}
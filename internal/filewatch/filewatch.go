@@ -0,0 +1,159 @@
+// Package filewatch reports the files changing under a directory, such as
+// the ones an agent edits in its ticket's worktree.
+package filewatch
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// batchWindow is how long Next keeps collecting changes after the first,
+// so a burst of writes arrives as one batch
+const batchWindow = 200 * time.Millisecond
+
+// skipDirs are never watched: git's own bookkeeping and dependency trees
+// change constantly and aren't the agent's work
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// Op is what happened to a file
+type Op string
+
+const (
+	Created  Op = "created"
+	Modified Op = "modified"
+	Removed  Op = "removed"
+)
+
+// Change is a file created, modified or removed
+type Change struct {
+	Path string // relative to the watched directory
+	Op   Op
+	At   time.Time
+}
+
+// Watcher watches a directory tree. Directories created later are watched
+// as they appear.
+type Watcher struct {
+	root string
+	fs   *fsnotify.Watcher
+}
+
+// New starts watching every directory under root
+func New(root string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{root: root, fs: fsw}
+	if _, err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// addTree watches dir and the directories below it, returning the files
+// found in them
+func (w *Watcher) addTree(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A directory removed while walking is no reason to fail
+			if path != dir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+		if path != dir && skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		return w.fs.Add(path)
+	})
+	return files, err
+}
+
+// Next blocks until files change and returns the changes, collected over
+// a short window. It returns false once the watcher is closed.
+func (w *Watcher) Next() ([]Change, bool) {
+	var changes []Change
+	var window <-chan time.Time
+	for {
+		select {
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return changes, len(changes) > 0
+			}
+			if cs := w.changes(ev); len(cs) > 0 {
+				changes = append(changes, cs...)
+				if window == nil {
+					window = time.After(batchWindow)
+				}
+			}
+		case _, ok := <-w.fs.Errors:
+			// Overflows and unreadable directories only cost some events
+			if !ok {
+				return changes, len(changes) > 0
+			}
+		case <-window:
+			return changes, true
+		}
+	}
+}
+
+// changes turns an fsnotify event into Changes. A new directory is
+// watched, and the files already in it by then are reported as created.
+func (w *Watcher) changes(ev fsnotify.Event) []Change {
+	rel, err := filepath.Rel(w.root, ev.Name)
+	if err != nil || ignored(rel) {
+		return nil
+	}
+	now := time.Now()
+	switch {
+	case ev.Has(fsnotify.Create):
+		info, err := os.Stat(ev.Name)
+		if err != nil || !info.IsDir() {
+			return []Change{{Path: rel, Op: Created, At: now}}
+		}
+		files, _ := w.addTree(ev.Name)
+		var changes []Change
+		for _, f := range files {
+			if rel, err := filepath.Rel(w.root, f); err == nil {
+				changes = append(changes, Change{Path: rel, Op: Created, At: now})
+			}
+		}
+		return changes
+	case ev.Has(fsnotify.Write):
+		return []Change{{Path: rel, Op: Modified, At: now}}
+	case ev.Has(fsnotify.Remove), ev.Has(fsnotify.Rename):
+		return []Change{{Path: rel, Op: Removed, At: now}}
+	}
+	return nil
+}
+
+// ignored reports whether a path is, or lies in, a directory that isn't
+// watched
+func ignored(rel string) bool {
+	for p := rel; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		if skipDirs[filepath.Base(p)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops watching; a pending Next returns false
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
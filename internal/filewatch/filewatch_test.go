@@ -0,0 +1,102 @@
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// nextChanges waits for the next batch, failing the test after a timeout
+func nextChanges(t *testing.T, w *Watcher) []Change {
+	t.Helper()
+	done := make(chan []Change, 1)
+	go func() {
+		changes, _ := w.Next()
+		done <- changes
+	}()
+	select {
+	case changes := <-done:
+		return changes
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+		return nil
+	}
+}
+
+func hasChange(changes []Change, path string, op Op) bool {
+	for _, c := range changes {
+		if c.Path == path && c.Op == op {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(root)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if err := os.WriteFile(filepath.Join(root, ".git", "index"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changes := nextChanges(t, w)
+	if !hasChange(changes, "main.go", Created) {
+		t.Errorf("changes = %+v; want main.go created", changes)
+	}
+	for _, c := range changes {
+		if c.Path == filepath.Join(".git", "index") {
+			t.Errorf("changes = %+v; .git should be ignored", changes)
+		}
+	}
+
+	// A file written into a new directory before it is watched still counts
+	sub := filepath.Join(root, "pkg")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "util.go"), []byte("package pkg\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changes := nextChanges(t, w); !hasChange(changes, filepath.Join("pkg", "util.go"), Created) {
+		t.Errorf("changes = %+v; want pkg/util.go created", changes)
+	}
+
+	if err := os.Remove(filepath.Join(root, "main.go")); err != nil {
+		t.Fatal(err)
+	}
+	if changes := nextChanges(t, w); !hasChange(changes, "main.go", Removed) {
+		t.Errorf("changes = %+v; want main.go removed", changes)
+	}
+}
+
+func TestWatcher_Close(t *testing.T) {
+	w, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	done := make(chan bool, 1)
+	go func() {
+		_, ok := w.Next()
+		done <- ok
+	}()
+	w.Close()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("Next() = true after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Next() did not return after Close")
+	}
+}
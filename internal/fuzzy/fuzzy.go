@@ -0,0 +1,91 @@
+// Package fuzzy scores text against a typed pattern the way fuzzy finders
+// do: the pattern's characters must appear in order, and matches in runs or
+// at the start of words rank higher.
+package fuzzy
+
+import (
+	"unicode"
+)
+
+const (
+	scoreMatch       = 16
+	bonusWordStart   = 8
+	bonusConsecutive = 6
+	bonusFirstRune   = 4
+	penaltyGap       = 1
+)
+
+// Match reports whether the runes of pattern appear in text in order,
+// ignoring case. It returns a score that is higher for tighter matches and
+// the rune indexes of text that matched. An empty pattern matches anything
+// with a score of zero.
+func Match(pattern, text string) (score int, positions []int, ok bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(text)
+
+	// Find the first place the whole pattern fits, then walk back from its
+	// end for the shortest window, so "ab" in "a-xab" picks the final "ab"
+	pi, end := 0, -1
+	for i, r := range t {
+		if fold(r) == fold(p[pi]) {
+			pi++
+			if pi == len(p) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	positions = make([]int, len(p))
+	pi = len(p) - 1
+	for i := end; i >= 0 && pi >= 0; i-- {
+		if fold(t[i]) == fold(p[pi]) {
+			positions[pi] = i
+			pi--
+		}
+	}
+
+	for k, i := range positions {
+		score += scoreMatch
+		if wordStart(t, i) {
+			score += bonusWordStart
+		}
+		if k > 0 {
+			if gap := i - positions[k-1] - 1; gap == 0 {
+				score += bonusConsecutive
+			} else {
+				score -= gap * penaltyGap
+			}
+		}
+	}
+	if positions[0] == 0 {
+		score += bonusFirstRune
+	}
+	return score, positions, true
+}
+
+func fold(r rune) rune {
+	return unicode.ToLower(r)
+}
+
+// wordStart reports whether t[i] begins a word: the first rune, a letter or
+// digit after a separator, or an upper-case letter after a lower-case one
+func wordStart(t []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := t[i-1], t[i]
+	if !isWordRune(prev) {
+		return isWordRune(cur)
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,57 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, nil},
+		{"abc", "abc", true, []int{0, 1, 2}},
+		{"ABC", "abc", true, []int{0, 1, 2}},
+		{"fb", "fix login bug", true, []int{0, 10}},
+		{"ab", "a-xab", true, []int{3, 4}},
+		{"ba", "abc", false, nil},
+		{"abcd", "abc", false, nil},
+		{"ür", "Über", true, []int{0, 3}},
+	}
+	for _, tt := range tests {
+		_, positions, ok := Match(tt.pattern, tt.text)
+		if ok != tt.ok {
+			t.Errorf("Match(%q, %q) ok = %v; want %v", tt.pattern, tt.text, ok, tt.ok)
+			continue
+		}
+		if !slices.Equal(positions, tt.positions) {
+			t.Errorf("Match(%q, %q) positions = %v; want %v", tt.pattern, tt.text, positions, tt.positions)
+		}
+	}
+}
+
+func TestMatchRanking(t *testing.T) {
+	// Each pair lists the text that should outrank the other
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		{"log", "login page", "a long goal"},
+		{"lp", "login page", "lamp"},
+		{"api", "fix API timeout", "rapid fire"},
+		{"db", "DataBase migration", "add backup"},
+	}
+	for _, tt := range tests {
+		better, _, ok1 := Match(tt.pattern, tt.better)
+		worse, _, ok2 := Match(tt.pattern, tt.worse)
+		if !ok1 || !ok2 {
+			t.Fatalf("Match(%q) should match both %q and %q", tt.pattern, tt.better, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("Match(%q): %q scored %d, not above %q with %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
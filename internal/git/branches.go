@@ -0,0 +1,142 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Branch is a local or remote-tracking branch that can serve as a ticket's
+// base
+type Branch struct {
+	Name     string // e.g. "main", or "origin/feature" for a remote branch
+	Remote   bool
+	Upstream string // remote branch a local branch tracks, if any
+	Ahead    int    // commits on the branch that are not on its upstream
+	Behind   int    // commits on the upstream that are not on the branch
+}
+
+// Stale reports whether the branch is behind its upstream as of the last fetch
+func (b Branch) Stale() bool {
+	return b.Behind > 0
+}
+
+// FetchAll updates the remote-tracking branches of every remote
+func (m *WorktreeManager) FetchAll() error {
+	if output, err := run(m.repoPath, "fetch", "--all", "--prune", "--quiet"); err != nil {
+		return fmt.Errorf("failed to fetch: %s", output)
+	}
+	return nil
+}
+
+const branchFormat = "--format=%(refname)%00%(upstream:short)%00%(upstream:track,nobracket)"
+
+// Branches lists local branches followed by remote branches that no local
+// branch tracks, each with how far it has diverged from its upstream
+func (m *WorktreeManager) Branches() ([]Branch, error) {
+	output, err := run(m.repoPath, "for-each-ref", branchFormat, "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %s", output)
+	}
+	return parseBranches(output), nil
+}
+
+func parseBranches(output string) []Branch {
+	var local, remote []Branch
+	tracked := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 3 {
+			continue
+		}
+		ref := fields[0]
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			b := Branch{Name: strings.TrimPrefix(ref, "refs/heads/"), Upstream: fields[1]}
+			b.Ahead, b.Behind = parseTrack(fields[2])
+			local = append(local, b)
+			if b.Upstream != "" {
+				tracked[b.Upstream] = true
+			}
+		case strings.HasPrefix(ref, "refs/remotes/") && !strings.HasSuffix(ref, "/HEAD"):
+			remote = append(remote, Branch{Name: strings.TrimPrefix(ref, "refs/remotes/"), Remote: true})
+		}
+	}
+
+	for _, b := range remote {
+		if !tracked[b.Name] {
+			local = append(local, b)
+		}
+	}
+	return local
+}
+
+// parseTrack parses git's upstream tracking summary, e.g. "ahead 1, behind 2"
+func parseTrack(track string) (ahead, behind int) {
+	for _, part := range strings.Split(track, ",") {
+		word, count, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(count)
+		switch word {
+		case "ahead":
+			ahead = n
+		case "behind":
+			behind = n
+		}
+	}
+	return ahead, behind
+}
+
+// BranchStatus returns the named local or remote branch
+func (m *WorktreeManager) BranchStatus(name string) (Branch, error) {
+	branches, err := m.Branches()
+	if err != nil {
+		return Branch{}, err
+	}
+	for _, b := range branches {
+		if b.Name == name {
+			return b, nil
+		}
+	}
+	return Branch{}, fmt.Errorf("unknown branch %s", name)
+}
+
+// LocalBase returns a local branch to base a ticket on. A remote branch that
+// no local branch tracks gets a local tracking branch of the same short name;
+// local branches are returned unchanged.
+func (m *WorktreeManager) LocalBase(name string) (string, error) {
+	if m.hasLocalBranch(name) {
+		return name, nil
+	}
+	if _, err := run(m.repoPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+name); err != nil {
+		return "", fmt.Errorf("unknown branch %s", name)
+	}
+	_, short, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", fmt.Errorf("unknown branch %s", name)
+	}
+	if m.hasLocalBranch(short) {
+		return short, nil
+	}
+	if output, err := run(m.repoPath, "branch", "--track", short, "refs/remotes/"+name); err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %s", short, output)
+	}
+	return short, nil
+}
+
+// IsRemoteBranch reports whether name is a remote branch, such as
+// "origin/release", rather than a local one
+func (m *WorktreeManager) IsRemoteBranch(name string) bool {
+	if m.hasLocalBranch(name) {
+		return false
+	}
+	_, err := run(m.repoPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+name)
+	return err == nil
+}
+
+func (m *WorktreeManager) hasLocalBranch(name string) bool {
+	_, err := run(m.repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
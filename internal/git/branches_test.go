@@ -0,0 +1,78 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		track         string
+		ahead, behind int
+	}{
+		{"", 0, 0},
+		{"gone", 0, 0},
+		{"ahead 2", 2, 0},
+		{"behind 3", 0, 3},
+		{"ahead 1, behind 12", 1, 12},
+	}
+	for _, tt := range tests {
+		if ahead, behind := parseTrack(tt.track); ahead != tt.ahead || behind != tt.behind {
+			t.Errorf("parseTrack(%q) = %d, %d; want %d, %d", tt.track, ahead, behind, tt.ahead, tt.behind)
+		}
+	}
+}
+
+func TestBranchesAndLocalBase(t *testing.T) {
+	upstream, _, gitIn := testRepo(t)
+	gitIn(upstream, "branch", "feature")
+	clone := filepath.Join(filepath.Dir(upstream), "clone")
+	gitIn(filepath.Dir(upstream), "clone", "-q", upstream, clone)
+
+	writeFile(t, upstream, "c.txt", "new\n")
+	gitIn(upstream, "add", ".")
+	gitIn(upstream, "commit", "-qm", "upstream moved")
+
+	mgr := NewWorktreeManagerFromPaths(clone, clone+"-worktrees")
+	if err := mgr.FetchAll(); err != nil {
+		t.Fatalf("FetchAll() error = %v", err)
+	}
+	branches, err := mgr.Branches()
+	if err != nil {
+		t.Fatalf("Branches() error = %v", err)
+	}
+	want := []Branch{
+		{Name: "main", Upstream: "origin/main", Behind: 1},
+		{Name: "origin/feature", Remote: true},
+		{Name: "origin/task", Remote: true},
+	}
+	if !reflect.DeepEqual(branches, want) {
+		t.Fatalf("Branches() = %+v; want %+v", branches, want)
+	}
+	if !branches[0].Stale() {
+		t.Error("main should be stale")
+	}
+
+	if b, err := mgr.BranchStatus("main"); err != nil || b.Behind != 1 {
+		t.Errorf("BranchStatus(main) = %+v, %v", b, err)
+	}
+
+	if !mgr.IsRemoteBranch("origin/feature") || mgr.IsRemoteBranch("main") || mgr.IsRemoteBranch("origin/nope") {
+		t.Error("IsRemoteBranch() should hold only for origin/feature")
+	}
+
+	local, err := mgr.LocalBase("origin/feature")
+	if err != nil || local != "feature" {
+		t.Fatalf("LocalBase(origin/feature) = %q, %v", local, err)
+	}
+	if b, _ := mgr.BranchStatus("feature"); b.Upstream != "origin/feature" {
+		t.Errorf("feature should track origin/feature, got %+v", b)
+	}
+	if local, err := mgr.LocalBase("main"); err != nil || local != "main" {
+		t.Errorf("LocalBase(main) = %q, %v", local, err)
+	}
+	if _, err := mgr.LocalBase("origin/nope"); err == nil {
+		t.Error("LocalBase() of an unknown branch should fail")
+	}
+}
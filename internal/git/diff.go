@@ -0,0 +1,196 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DiffLineKind classifies a line of a file diff
+type DiffLineKind int
+
+const (
+	DiffContext DiffLineKind = iota
+	DiffAdded
+	DiffRemoved
+	DiffHunk // @@ -a,b +c,d @@ header
+)
+
+// DiffLine is one line of a file diff. Text excludes the leading +, - or
+// space. OldLine and NewLine are 0 where the line does not exist on that side.
+type DiffLine struct {
+	Kind    DiffLineKind
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// FileDiff is the change to a single file
+type FileDiff struct {
+	Path    string
+	OldPath string // previous path for renames
+	Status  string // "added", "deleted", "renamed" or "modified"
+	Added   int
+	Deleted int
+	Binary  bool
+	Lines   []DiffLine
+}
+
+// DiffStat totals a set of file diffs
+type DiffStat struct {
+	Files   int
+	Added   int
+	Deleted int
+}
+
+// Stat sums the changes in files
+func Stat(files []FileDiff) DiffStat {
+	stat := DiffStat{Files: len(files)}
+	for _, f := range files {
+		stat.Added += f.Added
+		stat.Deleted += f.Deleted
+	}
+	return stat
+}
+
+func (s DiffStat) String() string {
+	plural := func(n int, word string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, word)
+		}
+		return fmt.Sprintf("%d %ss", n, word)
+	}
+	return fmt.Sprintf("%s changed, %s(+), %s(-)",
+		plural(s.Files, "file"), plural(s.Added, "insertion"), plural(s.Deleted, "deletion"))
+}
+
+// Diff returns the changes committed on HEAD in the worktree at path since it
+// diverged from base, like `git diff base...HEAD`.
+func Diff(path, base string) ([]FileDiff, error) {
+	return diff(path, base+"...HEAD")
+}
+
+// WorktreeDiff is Diff with the uncommitted changes to tracked files
+// included: the worktree against where HEAD diverged from base.
+func WorktreeDiff(path, base string) ([]FileDiff, error) {
+	fork, err := run(path, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git merge-base failed: %s", fork)
+	}
+	return diff(path, fork)
+}
+
+func diff(path, rev string) ([]FileDiff, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=off", "diff", "--no-color", "--no-ext-diff", "-M", rev)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git diff failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+	return ParseDiff(string(output)), nil
+}
+
+// Summarize lists the changed files with their line counts, then the
+// totals, e.g. "M src/login.go +12 -3". Past limit files the rest are
+// counted.
+func Summarize(files []FileDiff, limit int) string {
+	var b strings.Builder
+	for i, f := range files {
+		if i == limit {
+			fmt.Fprintf(&b, "... and %d more\n", len(files)-limit)
+			break
+		}
+		status := strings.ToUpper(f.Status[:1])
+		if f.Binary {
+			fmt.Fprintf(&b, "%s %s (binary)\n", status, f.Path)
+		} else {
+			fmt.Fprintf(&b, "%s %s +%d -%d\n", status, f.Path, f.Added, f.Deleted)
+		}
+	}
+	b.WriteString(Stat(files).String())
+	return b.String()
+}
+
+// ParseDiff parses unified diff output from git into per-file diffs
+func ParseDiff(patch string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var oldLine, newLine int
+
+	for _, line := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileDiff{Status: "modified"})
+			cur = &files[len(files)-1]
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				cur.Path = b
+			}
+			continue
+		case cur == nil:
+			continue
+		}
+
+		if len(cur.Lines) == 0 {
+			// File header, before the first hunk
+			switch {
+			case strings.HasPrefix(line, "new file mode"):
+				cur.Status = "added"
+			case strings.HasPrefix(line, "deleted file mode"):
+				cur.Status = "deleted"
+			case strings.HasPrefix(line, "rename from "):
+				cur.Status = "renamed"
+				cur.OldPath = strings.TrimPrefix(line, "rename from ")
+			case strings.HasPrefix(line, "rename to "):
+				cur.Path = strings.TrimPrefix(line, "rename to ")
+			case strings.HasPrefix(line, "Binary files "):
+				cur.Binary = true
+			case strings.HasPrefix(line, "+++ b/"):
+				cur.Path = strings.TrimPrefix(line, "+++ b/")
+			case strings.HasPrefix(line, "--- a/") && cur.Status == "deleted":
+				cur.Path = strings.TrimPrefix(line, "--- a/")
+			}
+			if !strings.HasPrefix(line, "@@") {
+				continue
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			oldLine, newLine = parseHunkHeader(line)
+			cur.Lines = append(cur.Lines, DiffLine{Kind: DiffHunk, Text: line})
+		case strings.HasPrefix(line, "+"):
+			cur.Lines = append(cur.Lines, DiffLine{Kind: DiffAdded, Text: line[1:], NewLine: newLine})
+			cur.Added++
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			cur.Lines = append(cur.Lines, DiffLine{Kind: DiffRemoved, Text: line[1:], OldLine: oldLine})
+			cur.Deleted++
+			oldLine++
+		case strings.HasPrefix(line, " "):
+			cur.Lines = append(cur.Lines, DiffLine{Kind: DiffContext, Text: line[1:], OldLine: oldLine, NewLine: newLine})
+			oldLine++
+			newLine++
+		}
+		// "\ No newline at end of file" and anything unexpected is skipped
+	}
+	return files
+}
+
+// parseHunkHeader returns the starting old and new line numbers of a hunk
+// header such as "@@ -12,7 +12,9 @@ func main() {".
+func parseHunkHeader(header string) (int, int) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return 0, 0
+	}
+	start := func(r string) int {
+		r, _, _ = strings.Cut(r[1:], ",")
+		n, _ := strconv.Atoi(r)
+		return n
+	}
+	return start(fields[1]), start(fields[2])
+}
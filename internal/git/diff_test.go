@@ -0,0 +1,137 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const samplePatch = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@ package main
+ package main
+-import "fmt"
++import (
++	"fmt"
++)
+
+\ No newline at end of file
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1 @@
++# New
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+--- a heading
+diff --git a/a.go b/b.go
+similarity index 100%
+rename from a.go
+rename to b.go
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files := ParseDiff(samplePatch)
+	if len(files) != 5 {
+		t.Fatalf("ParseDiff() returned %d files; want 5", len(files))
+	}
+
+	main := files[0]
+	if main.Path != "main.go" || main.Status != "modified" || main.Added != 3 || main.Deleted != 1 {
+		t.Errorf("main.go = %+v", main)
+	}
+	if main.Lines[0].Kind != DiffHunk || main.Lines[2].Kind != DiffRemoved || main.Lines[2].OldLine != 2 {
+		t.Errorf("main.go lines = %+v", main.Lines)
+	}
+	if added := main.Lines[3]; added.Kind != DiffAdded || added.Text != "import (" || added.NewLine != 2 {
+		t.Errorf("first added line = %+v", added)
+	}
+
+	if files[1].Path != "docs/new.md" || files[1].Status != "added" || files[1].Added != 1 {
+		t.Errorf("new file = %+v", files[1])
+	}
+	if files[2].Path != "old.txt" || files[2].Status != "deleted" || files[2].Deleted != 1 || files[2].Lines[1].Text != "-- a heading" {
+		t.Errorf("deleted file = %+v", files[2])
+	}
+	if files[3].Path != "b.go" || files[3].OldPath != "a.go" || files[3].Status != "renamed" {
+		t.Errorf("renamed file = %+v", files[3])
+	}
+	if !files[4].Binary || files[4].Path != "logo.png" {
+		t.Errorf("binary file = %+v", files[4])
+	}
+
+	if got := Stat(files).String(); got != "5 files changed, 4 insertions(+), 2 deletions(-)" {
+		t.Errorf("Stat() = %q", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("a.txt", "one\n")
+	run("add", ".")
+	run("commit", "-qm", "base")
+	run("checkout", "-qb", "task")
+	write("a.txt", "one\ntwo\n")
+	run("commit", "-qam", "change")
+
+	files, err := Diff(repo, "main")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "a.txt" || files[0].Added != 1 {
+		t.Errorf("Diff() = %+v", files)
+	}
+
+	if _, err := Diff(repo, "no-such-branch"); err == nil {
+		t.Error("Diff() with an unknown base should fail")
+	}
+
+	write("b.txt", "new\n")
+	run("add", "b.txt")
+	write("a.txt", "one\ntwo\nthree\n")
+	files, err = WorktreeDiff(repo, "main")
+	if err != nil {
+		t.Fatalf("WorktreeDiff() error = %v", err)
+	}
+	want := "M a.txt +2 -0\nA b.txt +1 -0\n2 files changed, 3 insertions(+), 0 deletions(-)"
+	if got := Summarize(files, 10); got != want {
+		t.Errorf("Summarize(WorktreeDiff()) = %q; want %q", got, want)
+	}
+	if got := Summarize(files, 1); !strings.Contains(got, "... and 1 more") {
+		t.Errorf("Summarize() past its limit = %q", got)
+	}
+}
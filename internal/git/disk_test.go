@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DiskUsage(dir)
+	if err != nil {
+		t.Fatalf("DiskUsage() error = %v", err)
+	}
+	if got != 150 {
+		t.Errorf("DiskUsage() = %d; want 150", got)
+	}
+
+	if _, err := DiskUsage(filepath.Join(dir, "missing")); err == nil {
+		t.Error("DiskUsage() on missing path should return an error")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.input); got != tt.expected {
+			t.Errorf("FormatBytes(%d) = %q; want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Exclude adds pattern to the repository's info/exclude, so files
+// openkanban writes into a worktree never show up as changes. The file is
+// shared by every worktree of the repository.
+func Exclude(path, pattern string) error {
+	excludePath, err := run(path, "rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return fmt.Errorf("failed to find info/exclude: %s", excludePath)
+	}
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(path, excludePath)
+	}
+
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if slices.Contains(strings.Split(string(data), "\n"), pattern) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
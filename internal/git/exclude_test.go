@@ -0,0 +1,26 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExclude(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	writeFile(t, wt, ".openkanban-task.md", "Task: fix login\n")
+
+	for range 2 {
+		if err := Exclude(wt, "/.openkanban-task.md"); err != nil {
+			t.Fatalf("Exclude() error = %v", err)
+		}
+	}
+	if status := gitIn(wt, "status", "--porcelain"); status != "" {
+		t.Errorf("excluded file still shows as a change:\n%s", status)
+	}
+	data, _ := os.ReadFile(filepath.Join(repo, ".git", "info", "exclude"))
+	if n := strings.Count(string(data), "openkanban-task"); n != 1 {
+		t.Errorf("pattern written %d times; want once", n)
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit in a ticket branch's log
+type Commit struct {
+	Hash    string
+	Author  string
+	Time    time.Time
+	Subject string
+	Body    string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Fields and records are separated by the ASCII unit and record separators,
+// which do not appear in commit messages
+const logFormat = "--format=%H%x1f%an%x1f%at%x1f%s%x1f%b%x1e"
+
+// Log returns the commits on rev since it diverged from base, newest first
+func Log(path, base, rev string) ([]Commit, error) {
+	output, err := run(path, "log", logFormat, base+".."+rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %s", output)
+	}
+	return parseLog(output), nil
+}
+
+func parseLog(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) < 5 {
+			continue
+		}
+		c := Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Subject: fields[3],
+			Body:    strings.TrimSpace(fields[4]),
+		}
+		if secs, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			c.Time = time.Unix(secs, 0)
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// Head returns the commit checked out at path and the branch it is on, or
+// an empty branch when HEAD is detached
+func Head(path string) (hash, branch string, err error) {
+	hash, err = run(path, "rev-parse", "HEAD")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve HEAD: %s", hash)
+	}
+	branch, err = run(path, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		branch = ""
+	}
+	return hash, branch, nil
+}
+
+// Checkout checks out rev in the worktree at path. A commit hash detaches
+// HEAD; a branch name reattaches it.
+func Checkout(path, rev string) error {
+	if output, err := run(path, "checkout", "--quiet", rev); err != nil {
+		return fmt.Errorf("failed to check out %s: %s", rev, output)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package git
+
+import "testing"
+
+func TestLogAndCheckout(t *testing.T) {
+	_, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "b.txt", "one\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "first change")
+	writeFile(t, wt, "b.txt", "two\n")
+	gitIn(wt, "commit", "-qam", "second change\n\nWith a body.\nOver two lines.")
+
+	commits, err := Log(wt, "main", "task")
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Log() = %+v; want 2 commits", commits)
+	}
+	if commits[0].Subject != "second change" || commits[0].Body != "With a body.\nOver two lines." {
+		t.Errorf("newest commit = %+v", commits[0])
+	}
+	if commits[1].Subject != "first change" || commits[1].Body != "" || commits[1].Author != "t" || commits[1].Time.IsZero() {
+		t.Errorf("oldest commit = %+v", commits[1])
+	}
+	if len(commits[0].ShortHash()) != 7 {
+		t.Errorf("ShortHash() = %q", commits[0].ShortHash())
+	}
+
+	if err := Checkout(wt, commits[1].Hash); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	hash, branch, err := Head(wt)
+	if err != nil || hash != commits[1].Hash || branch != "" {
+		t.Errorf("Head() after checking out a commit = %q, %q, %v", hash, branch, err)
+	}
+
+	if err := Checkout(wt, "task"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if hash, branch, _ := Head(wt); hash != commits[0].Hash || branch != "task" {
+		t.Errorf("Head() after checking out the branch = %q, %q", hash, branch)
+	}
+}
@@ -0,0 +1,270 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Strategy is how a ticket branch is brought up to date with its base
+type Strategy string
+
+const (
+	StrategyMerge  Strategy = "merge"
+	StrategyRebase Strategy = "rebase"
+)
+
+// run runs git in dir and returns its trimmed combined output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := combinedOutput(cmd)
+	return strings.TrimSpace(string(output)), err
+}
+
+// InProgress returns the merge or rebase that is stopped on conflicts in the
+// worktree at path, or "" when there is none.
+func InProgress(path string) Strategy {
+	for _, op := range []struct {
+		gitPath  string
+		strategy Strategy
+	}{
+		{"rebase-merge", StrategyRebase},
+		{"rebase-apply", StrategyRebase},
+		{"MERGE_HEAD", StrategyMerge},
+	} {
+		p, err := run(path, "rev-parse", "--path-format=absolute", "--git-path", op.gitPath)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			return op.strategy
+		}
+	}
+	return ""
+}
+
+// ConflictedFiles returns the paths with unresolved conflicts in the worktree
+func ConflictedFiles(path string) ([]string, error) {
+	output, err := run(path, "-c", "core.quotepath=off", "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %s", output)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// TrialMerge merges branch into base in memory, without touching any
+// worktree, and returns the files that would conflict. It needs git 2.38 or
+// later for merge-tree --write-tree.
+func TrialMerge(path, base, branch string) ([]string, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=off", "merge-tree", "--write-tree", "--name-only", "--no-messages", base, branch)
+	cmd.Dir = path
+	output, err := cmd.Output()
+
+	// The first line is the merged tree; conflicted paths follow. Exit status
+	// 1 means conflicts, but only when a tree was written: git also uses it
+	// for unknown revisions.
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 || !isObjectID(lines[0]) {
+			msg := err.Error()
+			if exitErr != nil && len(exitErr.Stderr) > 0 {
+				msg = strings.TrimSpace(string(exitErr.Stderr))
+			}
+			return nil, fmt.Errorf("failed to check %s against %s: %s", branch, base, msg)
+		}
+	}
+
+	var conflicts []string
+	seen := make(map[string]bool)
+	for i, line := range lines {
+		if i == 0 || line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		conflicts = append(conflicts, line)
+	}
+	return conflicts, nil
+}
+
+func isObjectID(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Update brings the branch checked out at path up to date with base by
+// merging or rebasing. When git stops on conflicts the operation is left in
+// progress and the conflicted files are returned with a nil error.
+func Update(path, base string, strategy Strategy) ([]string, error) {
+	var args []string
+	switch strategy {
+	case StrategyMerge:
+		args = []string{"merge", "--no-edit", base}
+	case StrategyRebase:
+		args = []string{"rebase", base}
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", strategy)
+	}
+
+	output, err := run(path, args...)
+	if err == nil {
+		return nil, nil
+	}
+	if InProgress(path) != "" {
+		if conflicts, cerr := ConflictedFiles(path); cerr == nil && len(conflicts) > 0 {
+			return conflicts, nil
+		}
+	}
+	return nil, fmt.Errorf("git %s failed: %s", strategy, output)
+}
+
+// Continue resumes the merge or rebase in progress at path once its
+// conflicts are resolved. Conflicted files that no longer contain conflict
+// markers are staged first, so they need not be added by hand. A rebase may
+// stop again on a later commit, in which case the new conflicts are returned.
+func Continue(path string) ([]string, error) {
+	conflicts, err := ConflictedFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var unresolved []string
+	for _, file := range conflicts {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err == nil && hasConflictMarkers(string(data)) {
+			unresolved = append(unresolved, file)
+			continue
+		}
+		// A file deleted to resolve the conflict is staged as a removal
+		if output, err := run(path, "add", "-A", "--", file); err != nil {
+			return nil, fmt.Errorf("failed to stage %s: %s", file, output)
+		}
+	}
+	if len(unresolved) > 0 {
+		return unresolved, fmt.Errorf("%d file(s) still have conflict markers", len(unresolved))
+	}
+
+	switch InProgress(path) {
+	case StrategyRebase:
+		output, err := run(path, "-c", "core.editor=true", "rebase", "--continue")
+		if err == nil {
+			return nil, nil
+		}
+		if conflicts, _ := ConflictedFiles(path); len(conflicts) > 0 {
+			return conflicts, nil
+		}
+		return nil, fmt.Errorf("git rebase --continue failed: %s", output)
+	case StrategyMerge:
+		if output, err := run(path, "commit", "--no-edit"); err != nil {
+			return nil, fmt.Errorf("git commit failed: %s", output)
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("no merge or rebase in progress")
+}
+
+func hasConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Abort abandons the merge or rebase in progress at path
+func Abort(path string) error {
+	strategy := InProgress(path)
+	if strategy == "" {
+		return nil
+	}
+	if output, err := run(path, string(strategy), "--abort"); err != nil {
+		return fmt.Errorf("git %s --abort failed: %s", strategy, output)
+	}
+	return nil
+}
+
+// FetchBase updates base from its upstream, when it has one, by fetching and
+// fast-forwarding. A local base that has diverged from its upstream is left
+// alone.
+func (m *WorktreeManager) FetchBase(base string) error {
+	upstream, err := run(m.repoPath, "rev-parse", "--abbrev-ref", base+"@{upstream}")
+	if err != nil {
+		return nil
+	}
+	remote, _, ok := strings.Cut(upstream, "/")
+	if !ok {
+		return nil
+	}
+	if output, err := run(m.repoPath, "fetch", remote); err != nil {
+		return fmt.Errorf("failed to fetch %s: %s", remote, output)
+	}
+	if _, err := run(m.repoPath, "merge-base", "--is-ancestor", base, upstream); err != nil {
+		return nil
+	}
+	return m.advance(base, upstream)
+}
+
+// Land moves base forward to include branch, which must already contain
+// base (see Update). With StrategyMerge a merge commit records the branch;
+// with StrategyRebase base is fast-forwarded onto it.
+func (m *WorktreeManager) Land(base, branch string, strategy Strategy) error {
+	if _, err := run(m.repoPath, "merge-base", "--is-ancestor", base, branch); err != nil {
+		return fmt.Errorf("%s is not up to date with %s", branch, base)
+	}
+	if _, err := run(m.repoPath, "merge-base", "--is-ancestor", branch, base); err == nil {
+		return fmt.Errorf("%s has no changes that are not already in %s", branch, base)
+	}
+
+	target := branch
+	if strategy == StrategyMerge {
+		// The branch already contains base, so the merge result is the
+		// branch's tree; build the commit without needing a checkout.
+		msg := fmt.Sprintf("Merge branch '%s' into %s", branch, base)
+		commit, err := run(m.repoPath, "commit-tree", branch+"^{tree}", "-p", base, "-p", branch, "-m", msg)
+		if err != nil {
+			return fmt.Errorf("failed to create merge commit: %s", commit)
+		}
+		target = commit
+	}
+	return m.advance(base, target)
+}
+
+// advance fast-forwards branch to target, updating the checkout too when the
+// branch is checked out in one of the repository's worktrees.
+func (m *WorktreeManager) advance(branch, target string) error {
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			if output, err := run(wt.Path, "merge", "--ff-only", target); err != nil {
+				return fmt.Errorf("failed to fast-forward %s in %s: %s", branch, wt.Path, output)
+			}
+			return nil
+		}
+	}
+
+	old, err := run(m.repoPath, "rev-parse", "refs/heads/"+branch)
+	if err != nil {
+		return fmt.Errorf("unknown branch %s", branch)
+	}
+	if output, err := run(m.repoPath, "update-ref", "refs/heads/"+branch, target, old); err != nil {
+		return fmt.Errorf("failed to update %s: %s", branch, output)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRepo creates a repository on main with a ticket branch checked out in
+// a worktree, and returns a git runner for either directory.
+func testRepo(t *testing.T) (repo, worktree string, gitIn func(dir string, args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	repo = filepath.Join(root, "repo")
+	worktree = filepath.Join(root, "wt")
+	gitIn = func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(repo, "init", "-q", "-b", "main")
+	gitIn(repo, "config", "user.name", "t")
+	gitIn(repo, "config", "user.email", "t@t")
+	writeFile(t, repo, "a.txt", "one\n")
+	gitIn(repo, "add", ".")
+	gitIn(repo, "commit", "-qm", "base")
+	gitIn(repo, "worktree", "add", "-q", "-b", "task", worktree)
+	return repo, worktree, gitIn
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateAndLand_Rebase(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "b.txt", "ticket\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "ticket work")
+	writeFile(t, repo, "c.txt", "base moved\n")
+	gitIn(repo, "add", ".")
+	gitIn(repo, "commit", "-qm", "base work")
+
+	conflicts, err := Update(wt, "main", StrategyRebase)
+	if err != nil || len(conflicts) > 0 {
+		t.Fatalf("Update() = %v, %v", conflicts, err)
+	}
+
+	mgr := NewWorktreeManagerFromPaths(repo, "")
+	if err := mgr.Land("main", "task", StrategyRebase); err != nil {
+		t.Fatalf("Land() error = %v", err)
+	}
+	if main, task := gitIn(repo, "rev-parse", "main"), gitIn(repo, "rev-parse", "task"); main != task {
+		t.Errorf("main = %s; want task %s", main, task)
+	}
+	// main is checked out in repo, so its files follow the branch
+	if _, err := os.Stat(filepath.Join(repo, "b.txt")); err != nil {
+		t.Errorf("b.txt missing from the main checkout: %v", err)
+	}
+}
+
+func TestUpdateAndLand_MergeConflict(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "a.txt", "ticket\n")
+	gitIn(wt, "commit", "-qam", "ticket work")
+	writeFile(t, repo, "a.txt", "base\n")
+	gitIn(repo, "commit", "-qam", "base work")
+
+	conflicts, err := Update(wt, "main", StrategyMerge)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "a.txt" {
+		t.Fatalf("Update() conflicts = %v; want [a.txt]", conflicts)
+	}
+	if got := InProgress(wt); got != StrategyMerge {
+		t.Errorf("InProgress() = %q; want merge", got)
+	}
+
+	if unresolved, err := Continue(wt); err == nil || len(unresolved) != 1 {
+		t.Errorf("Continue() with conflict markers = %v, %v; want an error", unresolved, err)
+	}
+	if err := Abort(wt); err != nil || InProgress(wt) != "" {
+		t.Fatalf("Abort() = %v, in progress %q", err, InProgress(wt))
+	}
+
+	if _, err := Update(wt, "main", StrategyMerge); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, wt, "a.txt", "both\n")
+	if conflicts, err := Continue(wt); err != nil || len(conflicts) > 0 {
+		t.Fatalf("Continue() = %v, %v", conflicts, err)
+	}
+
+	mgr := NewWorktreeManagerFromPaths(repo, "")
+	if err := mgr.Land("main", "task", StrategyMerge); err != nil {
+		t.Fatalf("Land() error = %v", err)
+	}
+	if parents := strings.Fields(gitIn(repo, "log", "-1", "--format=%P", "main")); len(parents) != 2 {
+		t.Errorf("main tip has parents %v; want a merge commit", parents)
+	}
+	if got, _ := os.ReadFile(filepath.Join(repo, "a.txt")); string(got) != "both\n" {
+		t.Errorf("a.txt on main = %q", got)
+	}
+}
+
+func TestLand_RequiresUpdatedBranch(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "b.txt", "ticket\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "ticket work")
+	writeFile(t, repo, "c.txt", "base\n")
+	gitIn(repo, "add", ".")
+	gitIn(repo, "commit", "-qm", "base work")
+
+	mgr := NewWorktreeManagerFromPaths(repo, "")
+	if err := mgr.Land("main", "task", StrategyRebase); err == nil {
+		t.Error("Land() should refuse a branch that does not contain base")
+	}
+}
+
+func TestTrialMerge(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "b.txt", "ticket\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "ticket work")
+
+	if conflicts, err := TrialMerge(wt, "main", "task"); err != nil || len(conflicts) != 0 {
+		t.Fatalf("TrialMerge() of a clean branch = %v, %v", conflicts, err)
+	}
+
+	writeFile(t, wt, "a.txt", "ticket\n")
+	gitIn(wt, "commit", "-qam", "conflicting work")
+	writeFile(t, repo, "a.txt", "base\n")
+	gitIn(repo, "commit", "-qam", "base work")
+	head := gitIn(repo, "rev-parse", "HEAD")
+
+	conflicts, err := TrialMerge(wt, "main", "task")
+	if err != nil {
+		t.Fatalf("TrialMerge() error = %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "a.txt" {
+		t.Errorf("TrialMerge() conflicts = %v; want [a.txt]", conflicts)
+	}
+	if InProgress(wt) != "" || gitIn(repo, "rev-parse", "HEAD") != head {
+		t.Error("TrialMerge() should not touch the worktree or base")
+	}
+
+	if _, err := TrialMerge(wt, "main", "no-such-branch"); err == nil {
+		t.Error("TrialMerge() with an unknown branch should fail")
+	}
+}
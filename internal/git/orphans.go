@@ -0,0 +1,66 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// TicketWorktree is a linked worktree of a repository together with the
+// ticket that owns it, if any.
+type TicketWorktree struct {
+	Path    string
+	Branch  string
+	Ticket  *board.Ticket // nil when no ticket references the worktree
+	Managed bool          // inside the project's worktree directory
+}
+
+// Orphaned reports whether no ticket references the worktree
+func (w TicketWorktree) Orphaned() bool {
+	return w.Ticket == nil
+}
+
+// Prunable reports whether the worktree belongs to a finished or deleted
+// ticket. Orphans outside the worktree directory were created by hand and
+// are never prunable.
+func (w TicketWorktree) Prunable() bool {
+	if w.Ticket == nil {
+		return w.Managed
+	}
+	return w.Ticket.Status == board.StatusDone || w.Ticket.Status == board.StatusArchived
+}
+
+// TicketWorktrees lists the repository's worktrees other than the main
+// checkout, as reported by git worktree list, and matches them to tickets
+// by path.
+func (m *WorktreeManager) TicketWorktrees(tickets []*board.Ticket) ([]TicketWorktree, error) {
+	worktrees, err := m.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	byPath := make(map[string]*board.Ticket)
+	for _, t := range tickets {
+		if t.WorktreePath != "" {
+			byPath[filepath.Clean(t.WorktreePath)] = t
+		}
+	}
+
+	repoPath := filepath.Clean(m.repoPath)
+	baseDir := filepath.Clean(m.baseDir) + string(filepath.Separator)
+	var result []TicketWorktree
+	for _, wt := range worktrees {
+		path := filepath.Clean(wt.Path)
+		if path == repoPath {
+			continue
+		}
+		result = append(result, TicketWorktree{
+			Path:    path,
+			Branch:  wt.Branch,
+			Ticket:  byPath[path],
+			Managed: strings.HasPrefix(path, baseDir),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestTicketWorktrees(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	root := filepath.Dir(repo)
+	orphan := filepath.Join(root, "orphan")
+	gitIn(repo, "worktree", "add", "-q", "-b", "stray", orphan)
+
+	// Resolve symlinks (e.g. /tmp on macOS) the way git reports paths
+	repo = gitIn(repo, "rev-parse", "--show-toplevel")
+	wt = gitIn(wt, "rev-parse", "--show-toplevel")
+	mgr := NewWorktreeManagerFromPaths(repo, filepath.Dir(wt))
+
+	ticket := &board.Ticket{WorktreePath: wt, Status: board.StatusInProgress}
+	worktrees, err := mgr.TicketWorktrees([]*board.Ticket{ticket, {WorktreePath: repo}})
+	if err != nil {
+		t.Fatalf("TicketWorktrees() error = %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("TicketWorktrees() = %+v; want 2 worktrees", worktrees)
+	}
+
+	byBranch := map[string]TicketWorktree{}
+	for _, w := range worktrees {
+		byBranch[w.Branch] = w
+	}
+	if w := byBranch["task"]; w.Ticket != ticket || w.Orphaned() || w.Prunable() {
+		t.Errorf("task worktree = %+v; want owned by the in-progress ticket", w)
+	}
+	if w := byBranch["stray"]; !w.Orphaned() || !w.Managed || !w.Prunable() {
+		t.Errorf("stray worktree = %+v; want a managed orphan", w)
+	}
+
+	ticket.Status = board.StatusArchived
+	if !byBranch["task"].Prunable() {
+		t.Error("worktree of an archived ticket should be prunable")
+	}
+}
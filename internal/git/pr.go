@@ -0,0 +1,318 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Provider is the code host a remote points at
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+	ProviderGitea  Provider = "gitea" // Gitea and Forgejo, e.g. Codeberg
+)
+
+// PullRequest describes a pull (or merge) request to open
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string // branch with the changes
+	Base  string // branch to merge into
+	Draft bool
+}
+
+// Remote is a parsed git remote URL
+type Remote struct {
+	Host string // e.g. "github.com"
+	Path string // e.g. "owner/repo", or "group/subgroup/repo" on GitLab
+}
+
+// ParseRemote parses the scp-like (git@host:owner/repo.git), ssh:// and
+// http(s):// forms of a remote URL.
+func ParseRemote(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if h, p, ok := strings.Cut(raw, ":"); ok {
+		if _, after, ok := strings.Cut(h, "@"); ok {
+			h = after
+		}
+		host, path = h, p
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("unrecognized remote URL %q", raw)
+	}
+	return Remote{Host: host, Path: path}, nil
+}
+
+// Provider guesses the code host from the remote's hostname. Self-hosted
+// instances are recognized when their hostname contains "github", "gitlab",
+// "gitea" or "forgejo".
+func (r Remote) Provider() (Provider, bool) {
+	host := strings.ToLower(r.Host)
+	switch {
+	case strings.Contains(host, "github"):
+		return ProviderGitHub, true
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab, true
+	case strings.Contains(host, "gitea"), strings.Contains(host, "forgejo"), host == "codeberg.org":
+		return ProviderGitea, true
+	}
+	return "", false
+}
+
+// RemoteURL returns the URL of remote for the repository at path
+func RemoteURL(path, remote string) (string, error) {
+	cmd := exec.Command("git", "remote", "get-url", remote)
+	cmd.Dir = path
+	output, err := combinedOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote %s: %s", remote, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Push pushes branch to remote and sets it as the branch's upstream
+func Push(path, remote, branch string) error {
+	cmd := exec.Command("git", "push", "--set-upstream", remote, branch)
+	cmd.Dir = path
+	if output, err := combinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to push %s: %s", branch, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CommitSubjects returns the subject lines of the commits on HEAD since it
+// diverged from base, oldest first.
+func CommitSubjects(path, base string) ([]string, error) {
+	cmd := exec.Command("git", "log", "--reverse", "--format=%s", base+"..HEAD")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// CreatePullRequest opens pr on the host behind remote and returns its URL.
+// The gh or glab CLI is used when installed, so their existing login is
+// reused; otherwise the REST API is called with GITHUB_TOKEN (or GH_TOKEN)
+// or GITLAB_TOKEN. Gitea is always reached through its API, with
+// GITEA_TOKEN (or FORGEJO_TOKEN).
+func CreatePullRequest(path string, remote Remote, provider Provider, pr PullRequest) (string, error) {
+	switch provider {
+	case ProviderGitHub:
+		if _, err := exec.LookPath("gh"); err == nil {
+			args := []string{"pr", "create", "--title", pr.Title, "--body", pr.Body, "--base", pr.Base, "--head", pr.Head}
+			if pr.Draft {
+				args = append(args, "--draft")
+			}
+			return runPRCommand(path, "gh", args...)
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		if token == "" {
+			return "", fmt.Errorf("install the gh CLI or set GITHUB_TOKEN to create pull requests")
+		}
+		return createGitHubPR(githubAPIBase(remote.Host), token, remote.Path, pr)
+
+	case ProviderGitLab:
+		if _, err := exec.LookPath("glab"); err == nil {
+			args := []string{"mr", "create", "--title", pr.Title, "--description", pr.Body,
+				"--target-branch", pr.Base, "--source-branch", pr.Head, "--yes"}
+			if pr.Draft {
+				args = append(args, "--draft")
+			}
+			return runPRCommand(path, "glab", args...)
+		}
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return "", fmt.Errorf("install the glab CLI or set GITLAB_TOKEN to create merge requests")
+		}
+		return createGitLabMR("https://"+remote.Host+"/api/v4", token, remote.Path, pr)
+
+	case ProviderGitea:
+		token := giteaToken()
+		if token == "" {
+			return "", fmt.Errorf("set GITEA_TOKEN to create pull requests")
+		}
+		return createGiteaPR("https://"+remote.Host+"/api/v1", token, remote.Path, pr)
+	}
+	return "", fmt.Errorf("unsupported provider %q", provider)
+}
+
+// runPRCommand runs a gh or glab create command and returns the URL it prints
+func runPRCommand(path, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = path
+	output, err := combinedOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %s", name, strings.TrimSpace(string(output)))
+	}
+	if link := lastURL(string(output)); link != "" {
+		return link, nil
+	}
+	return "", fmt.Errorf("%s did not print a URL: %s", name, strings.TrimSpace(string(output)))
+}
+
+func lastURL(output string) string {
+	fields := strings.Fields(output)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if strings.HasPrefix(fields[i], "https://") || strings.HasPrefix(fields[i], "http://") {
+			return fields[i]
+		}
+	}
+	return ""
+}
+
+func githubAPIBase(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	// GitHub Enterprise Server
+	return "https://" + host + "/api/v3"
+}
+
+var apiClient = &http.Client{Timeout: 30 * time.Second}
+
+func createGitHubPR(apiBase, token, repo string, pr PullRequest) (string, error) {
+	payload := map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/vnd.github+json",
+	}
+	if err := postJSON(apiBase+"/repos/"+repo+"/pulls", headers, payload, &resp); err != nil {
+		return "", err
+	}
+	return resp.HTMLURL, nil
+}
+
+func createGitLabMR(apiBase, token, repo string, pr PullRequest) (string, error) {
+	title := pr.Title
+	if pr.Draft {
+		title = "Draft: " + title
+	}
+	payload := map[string]any{
+		"title":         title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	var resp struct {
+		WebURL string `json:"web_url"`
+	}
+	headers := map[string]string{"PRIVATE-TOKEN": token}
+	if err := postJSON(apiBase+"/projects/"+url.PathEscape(repo)+"/merge_requests", headers, payload, &resp); err != nil {
+		return "", err
+	}
+	return resp.WebURL, nil
+}
+
+func createGiteaPR(apiBase, token, repo string, pr PullRequest) (string, error) {
+	title := pr.Title
+	if pr.Draft {
+		// Gitea treats a WIP: prefix as a draft
+		title = "WIP: " + title
+	}
+	payload := map[string]any{
+		"title": title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	headers := map[string]string{"Authorization": "token " + token}
+	if err := postJSON(apiBase+"/repos/"+repo+"/pulls", headers, payload, &resp); err != nil {
+		return "", err
+	}
+	return resp.HTMLURL, nil
+}
+
+func giteaToken() string {
+	if token := os.Getenv("GITEA_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("FORGEJO_TOKEN")
+}
+
+func postJSON(endpoint string, headers map[string]string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doJSON(req, headers, out)
+}
+
+func getJSON(endpoint string, headers map[string]string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(req, headers, out)
+}
+
+// doJSON sends req and decodes the JSON response into out, turning error
+// statuses into errors carrying the API's message
+func doJSON(req *http.Request, headers map[string]string, out any) error {
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message any `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != nil {
+			return fmt.Errorf("%s: %v", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     Remote
+		provider Provider
+	}{
+		{"git@github.com:owner/repo.git", Remote{"github.com", "owner/repo"}, ProviderGitHub},
+		{"https://github.com/owner/repo", Remote{"github.com", "owner/repo"}, ProviderGitHub},
+		{"ssh://git@gitlab.example.com:2222/group/sub/repo.git", Remote{"gitlab.example.com", "group/sub/repo"}, ProviderGitLab},
+		{"https://user@gitlab.com/group/repo.git/", Remote{"gitlab.com", "group/repo"}, ProviderGitLab},
+		{"https://codeberg.org/owner/repo.git", Remote{"codeberg.org", "owner/repo"}, ProviderGitea},
+		{"git@forgejo.example.com:team/repo.git", Remote{"forgejo.example.com", "team/repo"}, ProviderGitea},
+		{"git@git.example.com:team/repo.git", Remote{"git.example.com", "team/repo"}, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemote(tt.raw)
+		if err != nil {
+			t.Errorf("ParseRemote(%q) error = %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRemote(%q) = %+v; want %+v", tt.raw, got, tt.want)
+		}
+		if provider, _ := got.Provider(); provider != tt.provider {
+			t.Errorf("Provider(%q) = %q; want %q", tt.raw, provider, tt.provider)
+		}
+	}
+
+	for _, raw := range []string{"", "/local/path/repo", "https://github.com/"} {
+		if _, err := ParseRemote(raw); err == nil {
+			t.Errorf("ParseRemote(%q) should fail", raw)
+		}
+	}
+}
+
+func TestLastURL(t *testing.T) {
+	output := "Creating pull request for task/x into main\n\nhttps://github.com/o/r/pull/7\n"
+	if got := lastURL(output); got != "https://github.com/o/r/pull/7" {
+		t.Errorf("lastURL() = %q", got)
+	}
+	if got := lastURL("nothing here"); got != "" {
+		t.Errorf("lastURL() = %q; want empty", got)
+	}
+}
+
+func TestCreateGitHubPR(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls" || r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, `{"message":"bad request"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"html_url":"https://github.com/owner/repo/pull/1"}`))
+	}))
+	defer srv.Close()
+
+	pr := PullRequest{Title: "Fix it", Body: "body", Head: "task/fix", Base: "main", Draft: true}
+	url, err := createGitHubPR(srv.URL, "tok", "owner/repo", pr)
+	if err != nil {
+		t.Fatalf("createGitHubPR() error = %v", err)
+	}
+	if url != "https://github.com/owner/repo/pull/1" {
+		t.Errorf("createGitHubPR() = %q", url)
+	}
+	if got["head"] != "task/fix" || got["base"] != "main" || got["draft"] != true {
+		t.Errorf("request body = %v", got)
+	}
+
+	if _, err := createGitHubPR(srv.URL, "wrong", "owner/repo", pr); err == nil || err.Error() != "404 Not Found: bad request" {
+		t.Errorf("createGitHubPR() with a bad token error = %v", err)
+	}
+}
+
+func TestCreateGitLabMR(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/group%2Frepo/merge_requests" || r.Header.Get("PRIVATE-TOKEN") != "tok" {
+			http.Error(w, "", http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"web_url":"https://gitlab.com/group/repo/-/merge_requests/3"}`))
+	}))
+	defer srv.Close()
+
+	pr := PullRequest{Title: "Fix it", Head: "task/fix", Base: "main", Draft: true}
+	url, err := createGitLabMR(srv.URL, "tok", "group/repo", pr)
+	if err != nil {
+		t.Fatalf("createGitLabMR() error = %v", err)
+	}
+	if url != "https://gitlab.com/group/repo/-/merge_requests/3" {
+		t.Errorf("createGitLabMR() = %q", url)
+	}
+	if got["title"] != "Draft: Fix it" || got["source_branch"] != "task/fix" {
+		t.Errorf("request body = %v", got)
+	}
+}
+
+func TestCreateGiteaPR(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls" || r.Header.Get("Authorization") != "token tok" {
+			http.Error(w, `{"message":"user does not exist"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"html_url":"https://codeberg.org/owner/repo/pulls/5"}`))
+	}))
+	defer srv.Close()
+
+	pr := PullRequest{Title: "Fix it", Head: "task/fix", Base: "main", Draft: true}
+	url, err := createGiteaPR(srv.URL, "tok", "owner/repo", pr)
+	if err != nil {
+		t.Fatalf("createGiteaPR() error = %v", err)
+	}
+	if url != "https://codeberg.org/owner/repo/pulls/5" {
+		t.Errorf("createGiteaPR() = %q", url)
+	}
+	if got["title"] != "WIP: Fix it" || got["head"] != "task/fix" || got["base"] != "main" {
+		t.Errorf("request body = %v", got)
+	}
+}
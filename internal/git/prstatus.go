@@ -0,0 +1,424 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// CheckState summarizes a pull request's CI checks
+type CheckState string
+
+const (
+	ChecksNone    CheckState = ""
+	ChecksPending CheckState = "pending"
+	ChecksPassed  CheckState = "passed"
+	ChecksFailed  CheckState = "failed"
+)
+
+// Review decisions, as GitHub reports them
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// PRStatus is the state of a pull request and its checks
+type PRStatus struct {
+	State  string // "open", "merged" or "closed"
+	Draft  bool
+	Review string // one of the Review constants, or "" when none is needed
+	Checks CheckState
+
+	Passed, Failed, Pending int // check counts
+}
+
+// PullRequestRef identifies a pull or merge request by its web URL
+type PullRequestRef struct {
+	Provider Provider
+	Host     string
+	Repo     string // "owner/repo", or "group/subgroup/repo" on GitLab
+	Number   int
+}
+
+// ParsePullRequestURL parses the URL of a GitHub pull request
+// (/<owner>/<repo>/pull/<n>), a GitLab merge request
+// (/<group>/<repo>/-/merge_requests/<n>) or a Gitea pull request
+// (/<owner>/<repo>/pulls/<n>).
+func ParsePullRequestURL(raw string) (PullRequestRef, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return PullRequestRef{}, fmt.Errorf("invalid pull request URL %q", raw)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	n := len(parts)
+	if n < 4 {
+		return PullRequestRef{}, fmt.Errorf("not a pull request URL: %q", raw)
+	}
+	number, err := strconv.Atoi(parts[n-1])
+	if err != nil {
+		return PullRequestRef{}, fmt.Errorf("not a pull request URL: %q", raw)
+	}
+
+	ref := PullRequestRef{Host: u.Hostname(), Number: number}
+	switch {
+	case n >= 5 && parts[n-3] == "-" && parts[n-2] == "merge_requests":
+		ref.Provider, ref.Repo = ProviderGitLab, strings.Join(parts[:n-3], "/")
+	case n == 4 && parts[2] == "pull":
+		ref.Provider, ref.Repo = ProviderGitHub, parts[0]+"/"+parts[1]
+	case n == 4 && parts[2] == "pulls":
+		ref.Provider, ref.Repo = ProviderGitea, parts[0]+"/"+parts[1]
+	default:
+		return PullRequestRef{}, fmt.Errorf("not a pull request URL: %q", raw)
+	}
+	return ref, nil
+}
+
+// PullRequestStatus fetches the review state and CI checks of the pull or
+// merge request at prURL, telling the provider from the URL. Like
+// CreatePullRequest it uses the gh or glab CLI when installed and the REST
+// API with a token otherwise.
+func PullRequestStatus(path, prURL string) (PRStatus, error) {
+	ref, err := ParsePullRequestURL(prURL)
+	if err != nil {
+		return PRStatus{}, err
+	}
+
+	switch ref.Provider {
+	case ProviderGitLab:
+		if _, err := exec.LookPath("glab"); err == nil {
+			return fetchGitLabStatus(glabGetter(path, ref.Host), ref)
+		}
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return PRStatus{}, fmt.Errorf("install the glab CLI or set GITLAB_TOKEN to check merge requests")
+		}
+		return fetchGitLabStatus(apiGetter("https://"+ref.Host+"/api/v4", map[string]string{"PRIVATE-TOKEN": token}), ref)
+
+	case ProviderGitea:
+		token := giteaToken()
+		if token == "" {
+			return PRStatus{}, fmt.Errorf("set GITEA_TOKEN to check pull requests")
+		}
+		return fetchGiteaStatus(apiGetter("https://"+ref.Host+"/api/v1", map[string]string{"Authorization": "token " + token}), ref)
+	}
+
+	if _, err := exec.LookPath("gh"); err == nil {
+		cmd := exec.Command("gh", "pr", "view", prURL, "--json", "state,isDraft,reviewDecision,statusCheckRollup")
+		cmd.Dir = path
+		output, err := cmd.Output()
+		if err != nil {
+			return PRStatus{}, commandError("gh", err)
+		}
+		return parseGHStatus(output)
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token == "" {
+		return PRStatus{}, fmt.Errorf("install the gh CLI or set GITHUB_TOKEN to check pull requests")
+	}
+	return fetchGitHubStatus(apiGetter(githubAPIBase(ref.Host), map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/vnd.github+json",
+	}), ref)
+}
+
+// getter fetches an endpoint relative to a provider's API root as JSON
+type getter func(endpoint string, out any) error
+
+func apiGetter(apiBase string, headers map[string]string) getter {
+	return func(endpoint string, out any) error {
+		return getJSON(apiBase+"/"+endpoint, headers, out)
+	}
+}
+
+// glabGetter calls the GitLab API through glab, reusing its login
+func glabGetter(path, host string) getter {
+	return func(endpoint string, out any) error {
+		cmd := exec.Command("glab", "api", "--hostname", host, endpoint)
+		cmd.Dir = path
+		output, err := cmd.Output()
+		if err != nil {
+			return commandError("glab", err)
+		}
+		return json.Unmarshal(output, out)
+	}
+}
+
+func commandError(name string, err error) error {
+	msg := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		msg = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return fmt.Errorf("%s failed: %s", name, msg)
+}
+
+// parseGHStatus reads the output of gh pr view --json
+// state,isDraft,reviewDecision,statusCheckRollup
+func parseGHStatus(data []byte) (PRStatus, error) {
+	var resp struct {
+		State          string `json:"state"`
+		IsDraft        bool   `json:"isDraft"`
+		ReviewDecision string `json:"reviewDecision"`
+		Rollup         []struct {
+			Status     string `json:"status"`     // check runs
+			Conclusion string `json:"conclusion"` // check runs
+			State      string `json:"state"`      // commit statuses
+		} `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return PRStatus{}, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+
+	st := PRStatus{
+		State:  strings.ToLower(resp.State),
+		Draft:  resp.IsDraft,
+		Review: strings.ToLower(resp.ReviewDecision),
+	}
+	for _, c := range resp.Rollup {
+		if c.State != "" {
+			st.addCheck(commitStatusResult(c.State))
+		} else {
+			st.addCheck(checkRunResult(c.Status, c.Conclusion))
+		}
+	}
+	st.summarize()
+	return st, nil
+}
+
+func fetchGitHubStatus(get getter, ref PullRequestRef) (PRStatus, error) {
+	repo := "repos/" + ref.Repo
+
+	var pr struct {
+		State  string `json:"state"`
+		Merged bool   `json:"merged"`
+		Draft  bool   `json:"draft"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := get(fmt.Sprintf("%s/pulls/%d", repo, ref.Number), &pr); err != nil {
+		return PRStatus{}, err
+	}
+	st := PRStatus{State: pr.State, Draft: pr.Draft}
+	if pr.Merged {
+		st.State = "merged"
+	}
+
+	var reviews []review
+	if err := get(fmt.Sprintf("%s/pulls/%d/reviews?per_page=100", repo, ref.Number), &reviews); err != nil {
+		return PRStatus{}, err
+	}
+	st.Review = reviewDecision(reviews)
+
+	var runs struct {
+		CheckRuns []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := get(repo+"/commits/"+pr.Head.SHA+"/check-runs?per_page=100", &runs); err != nil {
+		return PRStatus{}, err
+	}
+	for _, r := range runs.CheckRuns {
+		st.addCheck(checkRunResult(r.Status, r.Conclusion))
+	}
+
+	var combined struct {
+		Statuses []struct {
+			State string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := get(repo+"/commits/"+pr.Head.SHA+"/status", &combined); err != nil {
+		return PRStatus{}, err
+	}
+	for _, s := range combined.Statuses {
+		st.addCheck(commitStatusResult(s.State))
+	}
+
+	st.summarize()
+	return st, nil
+}
+
+func fetchGitLabStatus(get getter, ref PullRequestRef) (PRStatus, error) {
+	mr := fmt.Sprintf("projects/%s/merge_requests/%d", url.PathEscape(ref.Repo), ref.Number)
+
+	var resp struct {
+		State        string `json:"state"` // opened, merged, closed or locked
+		Draft        bool   `json:"draft"`
+		HeadPipeline *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := get(mr, &resp); err != nil {
+		return PRStatus{}, err
+	}
+	st := PRStatus{State: resp.State, Draft: resp.Draft}
+	if st.State == "opened" || st.State == "locked" {
+		st.State = "open"
+	}
+
+	var approvals struct {
+		ApprovalsLeft int               `json:"approvals_left"`
+		ApprovedBy    []json.RawMessage `json:"approved_by"`
+	}
+	if err := get(mr+"/approvals", &approvals); err != nil {
+		return PRStatus{}, err
+	}
+	switch {
+	case approvals.ApprovalsLeft > 0:
+		st.Review = ReviewRequired
+	case len(approvals.ApprovedBy) > 0:
+		st.Review = ReviewApproved
+	}
+
+	// The head pipeline stands in for the checks, as GitLab's MR widget does
+	if resp.HeadPipeline != nil {
+		st.addCheck(pipelineResult(resp.HeadPipeline.Status))
+	}
+	st.summarize()
+	return st, nil
+}
+
+func fetchGiteaStatus(get getter, ref PullRequestRef) (PRStatus, error) {
+	repo := "repos/" + ref.Repo
+
+	var pr struct {
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Merged bool   `json:"merged"`
+		Draft  bool   `json:"draft"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := get(fmt.Sprintf("%s/pulls/%d", repo, ref.Number), &pr); err != nil {
+		return PRStatus{}, err
+	}
+	st := PRStatus{State: pr.State, Draft: pr.Draft || strings.HasPrefix(pr.Title, "WIP:")}
+	if pr.Merged {
+		st.State = "merged"
+	}
+
+	var reviews []review
+	if err := get(fmt.Sprintf("%s/pulls/%d/reviews", repo, ref.Number), &reviews); err != nil {
+		return PRStatus{}, err
+	}
+	st.Review = reviewDecision(reviews)
+
+	var combined struct {
+		Statuses []struct {
+			Status string `json:"status"`
+		} `json:"statuses"`
+	}
+	if err := get(repo+"/commits/"+pr.Head.SHA+"/status", &combined); err != nil {
+		return PRStatus{}, err
+	}
+	for _, s := range combined.Statuses {
+		st.addCheck(commitStatusResult(s.Status))
+	}
+
+	st.summarize()
+	return st, nil
+}
+
+// review is a pull request review as GitHub and Gitea report it
+type review struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State     string `json:"state"`
+	Dismissed bool   `json:"dismissed"` // Gitea; GitHub uses the DISMISSED state
+}
+
+// reviewDecision derives the review state from each reviewer's latest
+// approval or change request: any change request wins over approvals
+func reviewDecision(reviews []review) string {
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		switch {
+		case r.Dismissed || r.State == "DISMISSED":
+			delete(latest, r.User.Login)
+		case r.State == "APPROVED":
+			latest[r.User.Login] = ReviewApproved
+		case r.State == "CHANGES_REQUESTED" || r.State == "REQUEST_CHANGES":
+			latest[r.User.Login] = ReviewChangesRequested
+		}
+	}
+
+	decision := ""
+	for _, state := range latest {
+		if state == ReviewChangesRequested {
+			return state
+		}
+		decision = state
+	}
+	return decision
+}
+
+func checkRunResult(status, conclusion string) CheckState {
+	if !strings.EqualFold(status, "completed") {
+		return ChecksPending
+	}
+	switch strings.ToLower(conclusion) {
+	case "success", "neutral", "skipped":
+		return ChecksPassed
+	}
+	return ChecksFailed
+}
+
+func pipelineResult(status string) CheckState {
+	switch status {
+	case "success":
+		return ChecksPassed
+	case "failed", "canceled":
+		return ChecksFailed
+	case "skipped", "manual":
+		return ChecksNone
+	}
+	return ChecksPending
+}
+
+func commitStatusResult(state string) CheckState {
+	switch strings.ToLower(state) {
+	case "success":
+		return ChecksPassed
+	case "pending", "expected":
+		return ChecksPending
+	}
+	return ChecksFailed
+}
+
+func (st *PRStatus) addCheck(result CheckState) {
+	switch result {
+	case ChecksPassed:
+		st.Passed++
+	case ChecksFailed:
+		st.Failed++
+	case ChecksPending:
+		st.Pending++
+	}
+}
+
+// summarize sets Checks: any failure fails, otherwise anything still running
+// is pending
+func (st *PRStatus) summarize() {
+	switch {
+	case st.Failed > 0:
+		st.Checks = ChecksFailed
+	case st.Pending > 0:
+		st.Checks = ChecksPending
+	case st.Passed > 0:
+		st.Checks = ChecksPassed
+	default:
+		st.Checks = ChecksNone
+	}
+}
@@ -0,0 +1,138 @@
+package git
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePullRequestURL(t *testing.T) {
+	tests := map[string]PullRequestRef{
+		"https://github.com/owner/repo/pull/42":                {ProviderGitHub, "github.com", "owner/repo", 42},
+		"https://gitlab.com/group/sub/repo/-/merge_requests/3": {ProviderGitLab, "gitlab.com", "group/sub/repo", 3},
+		"https://codeberg.org/owner/repo/pulls/5":              {ProviderGitea, "codeberg.org", "owner/repo", 5},
+	}
+	for raw, want := range tests {
+		ref, err := ParsePullRequestURL(raw)
+		if err != nil {
+			t.Errorf("ParsePullRequestURL(%q) error = %v", raw, err)
+			continue
+		}
+		if ref != want {
+			t.Errorf("ParsePullRequestURL(%q) = %+v; want %+v", raw, ref, want)
+		}
+	}
+
+	for _, raw := range []string{"", "https://github.com/owner/repo", "https://github.com/owner/repo/pull/x", "https://github.com/owner/repo/issues/1"} {
+		if _, err := ParsePullRequestURL(raw); err == nil {
+			t.Errorf("ParsePullRequestURL(%q) succeeded", raw)
+		}
+	}
+}
+
+func TestParseGHStatus(t *testing.T) {
+	st, err := parseGHStatus([]byte(`{
+		"state": "OPEN",
+		"isDraft": false,
+		"reviewDecision": "CHANGES_REQUESTED",
+		"statusCheckRollup": [
+			{"__typename": "CheckRun", "status": "COMPLETED", "conclusion": "SUCCESS"},
+			{"__typename": "CheckRun", "status": "IN_PROGRESS", "conclusion": ""},
+			{"__typename": "StatusContext", "state": "SUCCESS"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parseGHStatus() error = %v", err)
+	}
+	want := PRStatus{State: "open", Review: ReviewChangesRequested, Checks: ChecksPending, Passed: 2, Pending: 1}
+	if st != want {
+		t.Errorf("parseGHStatus() = %+v; want %+v", st, want)
+	}
+}
+
+func TestFetchGitHubStatus(t *testing.T) {
+	responses := map[string]string{
+		"/repos/owner/repo/pulls/7":                `{"state":"open","draft":true,"head":{"sha":"abc"}}`,
+		"/repos/owner/repo/pulls/7/reviews":        `[{"user":{"login":"a"},"state":"CHANGES_REQUESTED"},{"user":{"login":"a"},"state":"APPROVED"},{"user":{"login":"b"},"state":"COMMENTED"}]`,
+		"/repos/owner/repo/commits/abc/check-runs": `{"check_runs":[{"status":"completed","conclusion":"success"},{"status":"completed","conclusion":"failure"}]}`,
+		"/repos/owner/repo/commits/abc/status":     `{"statuses":[{"state":"pending"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok || r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	get := apiGetter(srv.URL, map[string]string{"Authorization": "Bearer tok"})
+	ref := PullRequestRef{Provider: ProviderGitHub, Host: "github.com", Repo: "owner/repo", Number: 7}
+	st, err := fetchGitHubStatus(get, ref)
+	if err != nil {
+		t.Fatalf("fetchGitHubStatus() error = %v", err)
+	}
+	want := PRStatus{State: "open", Draft: true, Review: ReviewApproved, Checks: ChecksFailed, Passed: 1, Failed: 1, Pending: 1}
+	if st != want {
+		t.Errorf("fetchGitHubStatus() = %+v; want %+v", st, want)
+	}
+
+	ref.Number = 8
+	if _, err := fetchGitHubStatus(get, ref); err == nil || err.Error() != "404 Not Found: Not Found" {
+		t.Errorf("fetchGitHubStatus() for a missing PR error = %v", err)
+	}
+}
+
+func TestFetchGitLabStatus(t *testing.T) {
+	responses := map[string]string{
+		"/projects/group%2Frepo/merge_requests/3":           `{"state":"opened","draft":false,"head_pipeline":{"status":"running"}}`,
+		"/projects/group%2Frepo/merge_requests/3/approvals": `{"approvals_left":0,"approved_by":[{"user":{"username":"a"}}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok || r.Header.Get("PRIVATE-TOKEN") != "tok" {
+			http.Error(w, `{"message":"404 Not found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	get := apiGetter(srv.URL, map[string]string{"PRIVATE-TOKEN": "tok"})
+	st, err := fetchGitLabStatus(get, PullRequestRef{Provider: ProviderGitLab, Repo: "group/repo", Number: 3})
+	if err != nil {
+		t.Fatalf("fetchGitLabStatus() error = %v", err)
+	}
+	want := PRStatus{State: "open", Review: ReviewApproved, Checks: ChecksPending, Pending: 1}
+	if st != want {
+		t.Errorf("fetchGitLabStatus() = %+v; want %+v", st, want)
+	}
+}
+
+func TestFetchGiteaStatus(t *testing.T) {
+	responses := map[string]string{
+		"/repos/owner/repo/pulls/5":            `{"title":"WIP: Fix it","state":"open","merged":false,"head":{"sha":"abc"}}`,
+		"/repos/owner/repo/pulls/5/reviews":    `[{"user":{"login":"a"},"state":"REQUEST_CHANGES"},{"user":{"login":"b"},"state":"REQUEST_CHANGES","dismissed":true}]`,
+		"/repos/owner/repo/commits/abc/status": `{"state":"success","statuses":[{"status":"success"},{"status":"success"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok || r.Header.Get("Authorization") != "token tok" {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	get := apiGetter(srv.URL, map[string]string{"Authorization": "token tok"})
+	st, err := fetchGiteaStatus(get, PullRequestRef{Provider: ProviderGitea, Repo: "owner/repo", Number: 5})
+	if err != nil {
+		t.Fatalf("fetchGiteaStatus() error = %v", err)
+	}
+	want := PRStatus{State: "open", Draft: true, Review: ReviewChangesRequested, Checks: ChecksPassed, Passed: 2}
+	if st != want {
+		t.Errorf("fetchGiteaStatus() = %+v; want %+v", st, want)
+	}
+}
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TicketRef is a ticket referenced from a commit message, as
+// "<prefix>:<ticket>", optionally closing it with "<prefix>:<ticket> done"
+// or "Fixes <prefix>:<ticket>"
+type TicketRef struct {
+	Ticket string // ticket key, ID prefix, branch or title slug
+	Done   bool
+}
+
+// ticketRefPattern matches references for a prefix. The ticket part may not
+// end in punctuation, so "okb:fix-login." refers to fix-login.
+func ticketRefPattern(prefix string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:\b(fix|fixe[sd]|close[sd]?|resolve[sd]?)\s+)?\b` +
+		regexp.QuoteMeta(prefix) + `:([a-z0-9](?:[\w./-]*[a-z0-9])?)(?:\s+(done)\b)?`)
+}
+
+// ParseTicketRefs returns the tickets a commit message references, in order
+// of first mention
+func ParseTicketRefs(message, prefix string) []TicketRef {
+	if prefix == "" {
+		return nil
+	}
+	var refs []TicketRef
+	seen := make(map[string]int)
+	for _, m := range ticketRefPattern(prefix).FindAllStringSubmatch(message, -1) {
+		done := m[1] != "" || m[3] != ""
+		if i, ok := seen[m[2]]; ok {
+			refs[i].Done = refs[i].Done || done
+			continue
+		}
+		seen[m[2]] = len(refs)
+		refs = append(refs, TicketRef{Ticket: m[2], Done: done})
+	}
+	return refs
+}
+
+// ParseKeyRefs returns the tickets a commit message references by key, such
+// as "OKB-142" for key prefix OKB, in order of first mention. Keys close
+// tickets the same way as ParseTicketRefs references.
+func ParseKeyRefs(message, keyPrefix string) []TicketRef {
+	if keyPrefix == "" {
+		return nil
+	}
+	pattern := regexp.MustCompile(`(?i)(?:\b(fix|fixe[sd]|close[sd]?|resolve[sd]?)\s+)?\b(` +
+		regexp.QuoteMeta(keyPrefix) + `-[0-9]+)\b(?:\s+(done)\b)?`)
+	var refs []TicketRef
+	seen := make(map[string]int)
+	for _, m := range pattern.FindAllStringSubmatch(message, -1) {
+		key := strings.ToUpper(m[2])
+		done := m[1] != "" || m[3] != ""
+		if i, ok := seen[key]; ok {
+			refs[i].Done = refs[i].Done || done
+			continue
+		}
+		seen[key] = len(refs)
+		refs = append(refs, TicketRef{Ticket: key, Done: done})
+	}
+	return refs
+}
+
+// RecentLog returns the last n commits on rev, newest first
+func RecentLog(path, rev string, n int) ([]Commit, error) {
+	output, err := run(path, "log", logFormat, "-n", strconv.Itoa(n), rev, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %s", strings.TrimSpace(output))
+	}
+	return parseLog(output), nil
+}
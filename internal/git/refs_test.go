@@ -0,0 +1,64 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTicketRefs(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []TicketRef
+	}{
+		{"Add login form", nil},
+		{"Add login form\n\nokb:fix-login", []TicketRef{{Ticket: "fix-login"}}},
+		{"okb:3f2a9c1e done", []TicketRef{{Ticket: "3f2a9c1e", Done: true}}},
+		{"Fixes okb:fix-login.", []TicketRef{{Ticket: "fix-login", Done: true}}},
+		{"closed OKB:task/fix-login", []TicketRef{{Ticket: "task/fix-login", Done: true}}},
+		{"okb:a and okb:b done, okb:a", []TicketRef{{Ticket: "a"}, {Ticket: "b", Done: true}}},
+		{"See okb:a. Resolves okb:a", []TicketRef{{Ticket: "a", Done: true}}},
+		{"okb:fix-login is done", []TicketRef{{Ticket: "fix-login"}}},
+		{"notokb:x", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseTicketRefs(tt.message, "okb"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTicketRefs(%q) = %+v; want %+v", tt.message, got, tt.want)
+		}
+	}
+	if got := ParseTicketRefs("okb:x", ""); got != nil {
+		t.Errorf("ParseTicketRefs() without a prefix = %+v", got)
+	}
+}
+
+func TestParseKeyRefs(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []TicketRef
+	}{
+		{"Add login form", nil},
+		{"OKB-142: add login form", []TicketRef{{Ticket: "OKB-142"}}},
+		{"Fixes okb-142", []TicketRef{{Ticket: "OKB-142", Done: true}}},
+		{"OKB-7 done, see OKB-8 and OKB-7", []TicketRef{{Ticket: "OKB-7", Done: true}, {Ticket: "OKB-8"}}},
+		{"XOKB-1, OKB-12a, OKB-", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseKeyRefs(tt.message, "OKB"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKeyRefs(%q) = %+v; want %+v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestRecentLog(t *testing.T) {
+	_, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "b.txt", "one\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "okb:fix-login done")
+
+	commits, err := RecentLog(wt, "task", 1)
+	if err != nil {
+		t.Fatalf("RecentLog() error = %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "okb:fix-login done" {
+		t.Errorf("RecentLog() = %+v", commits)
+	}
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stash saves the uncommitted changes in the worktree at path, untracked
+// files included, and returns the stash commit. It returns "" when there is
+// nothing to save.
+func Stash(path, message string) (string, error) {
+	before, _ := run(path, "rev-parse", "-q", "--verify", "refs/stash")
+	if output, err := run(path, "stash", "push", "--include-untracked", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to stash changes: %s", output)
+	}
+	after, _ := run(path, "rev-parse", "-q", "--verify", "refs/stash")
+	if after == before {
+		return "", nil
+	}
+	return after, nil
+}
+
+// RestoreStash applies the stash commit to the worktree at path and drops
+// it. When applying fails, for example on conflicts, the stash is kept.
+func RestoreStash(path, commit string) error {
+	if output, err := run(path, "stash", "apply", commit); err != nil {
+		return fmt.Errorf("failed to apply stash %s: %s", shortID(commit), output)
+	}
+
+	// The stash list is shared by every worktree, so find the entry by commit
+	// rather than assuming it is still on top
+	output, err := run(path, "stash", "list", "--format=%H")
+	if err != nil {
+		return nil
+	}
+	for i, id := range strings.Split(output, "\n") {
+		if id == commit {
+			if output, err := run(path, "stash", "drop", fmt.Sprintf("stash@{%d}", i)); err != nil {
+				return fmt.Errorf("failed to drop stash %s: %s", shortID(commit), output)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func shortID(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStashAndRestore(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+
+	if commit, err := Stash(wt, "nothing"); err != nil || commit != "" {
+		t.Fatalf("Stash() on a clean worktree = %q, %v; want no stash", commit, err)
+	}
+
+	// An unrelated stash on top must survive restoring ours
+	writeFile(t, wt, "a.txt", "half done\n")
+	writeFile(t, wt, "new.txt", "untracked\n")
+	commit, err := Stash(wt, "openkanban: task")
+	if err != nil || commit == "" {
+		t.Fatalf("Stash() = %q, %v", commit, err)
+	}
+	if status := gitIn(wt, "status", "--porcelain"); status != "" {
+		t.Fatalf("worktree not clean after stash:\n%s", status)
+	}
+	writeFile(t, repo, "a.txt", "other\n")
+	if other, err := Stash(repo, "other"); err != nil || other == "" {
+		t.Fatalf("Stash(repo) = %q, %v", other, err)
+	}
+
+	if err := RestoreStash(wt, commit); err != nil {
+		t.Fatalf("RestoreStash() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wt, "a.txt")); string(data) != "half done\n" {
+		t.Errorf("a.txt = %q after restore", data)
+	}
+	if _, err := os.Stat(filepath.Join(wt, "new.txt")); err != nil {
+		t.Errorf("untracked file not restored: %v", err)
+	}
+	if list := gitIn(wt, "stash", "list", "--format=%s"); list != "On main: other" {
+		t.Errorf("stash list = %q; want only the other stash", list)
+	}
+}
+
+func TestRestoreStash_KeepsStashOnFailure(t *testing.T) {
+	_, wt, gitIn := testRepo(t)
+	writeFile(t, wt, "a.txt", "stashed\n")
+	commit, err := Stash(wt, "openkanban: task")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The next agent edits the same file, so applying would overwrite it
+	writeFile(t, wt, "a.txt", "agent\n")
+	if err := RestoreStash(wt, commit); err == nil {
+		t.Fatal("RestoreStash() over conflicting changes succeeded")
+	}
+	if list := gitIn(wt, "stash", "list", "--format=%H"); list != commit {
+		t.Errorf("stash list = %q; want %s kept", list, commit)
+	}
+}
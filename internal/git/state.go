@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Branches of shared state, such as the board kept on openkanban-state,
+// are written with plumbing commands so the user's checkout, index and
+// working tree are never touched.
+
+// runInput runs git in dir with input on stdin and returns its trimmed
+// stdout; stderr is used for the error
+func runInput(dir, input string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(input)
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	span := traceCommand(cmd)
+	err := cmd.Run()
+	span.End(err)
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// FetchBranch fetches branch from remote into its remote-tracking ref and
+// returns the fetched commit, or "" when the remote has no such branch yet
+func FetchBranch(path, remote, branch string) (string, error) {
+	tracking := "refs/remotes/" + remote + "/" + branch
+	output, err := run(path, "fetch", "-q", remote, "+refs/heads/"+branch+":"+tracking)
+	if err != nil {
+		if strings.Contains(output, "couldn't find remote ref") {
+			// Forget a branch deleted on the remote
+			run(path, "update-ref", "-d", tracking)
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch %s from %s: %s", branch, remote, output)
+	}
+	return ResolveRef(path, tracking), nil
+}
+
+// ResolveRef returns the commit ref points at, or "" when it does not exist
+func ResolveRef(path, ref string) string {
+	commit, err := run(path, "rev-parse", "-q", "--verify", ref+"^{commit}")
+	if err != nil {
+		return ""
+	}
+	return commit
+}
+
+// ReadFile returns file as committed in commit, or nil when the commit has
+// no such file
+func ReadFile(path, commit, file string) ([]byte, error) {
+	if commit == "" {
+		return nil, nil
+	}
+	if _, err := run(path, "cat-file", "-e", commit+":"+file); err != nil {
+		return nil, nil
+	}
+	output, err := runInput(path, "", nil, "cat-file", "blob", commit+":"+file)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
+
+// CommitFile commits data as file on top of parents, keeping the other files
+// in the first parent's tree, and returns the new commit. No ref is moved.
+// Only files at the top of the tree are kept.
+func CommitFile(path, file string, data []byte, message string, parents ...string) (string, error) {
+	blob, err := runInput(path, string(data), nil, "hash-object", "-w", "--stdin")
+	if err != nil {
+		return "", err
+	}
+
+	entries := map[string]string{file: "100644 blob " + blob + "\t" + file}
+	if len(parents) > 0 {
+		listing, err := runInput(path, "", nil, "ls-tree", parents[0])
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(listing, "\n") {
+			if _, name, ok := strings.Cut(line, "\t"); ok && name != file {
+				entries[name] = line
+			}
+		}
+	}
+	lines := make([]string, 0, len(entries))
+	for _, line := range entries {
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	tree, err := runInput(path, strings.Join(lines, "\n")+"\n", nil, "mktree")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	for _, p := range parents {
+		args = append(args, "-p", p)
+	}
+	return runInput(path, "", identityEnv(path), args...)
+}
+
+// identityEnv names openkanban as the committer when the repository has no
+// user configured, so background commits never stop on a missing identity
+func identityEnv(path string) []string {
+	if name, _ := run(path, "config", "user.name"); name != "" {
+		if email, _ := run(path, "config", "user.email"); email != "" {
+			return nil
+		}
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=openkanban", "GIT_AUTHOR_EMAIL=openkanban@localhost",
+		"GIT_COMMITTER_NAME=openkanban", "GIT_COMMITTER_EMAIL=openkanban@localhost",
+	}
+}
+
+// UpdateRef points ref at commit
+func UpdateRef(path, ref, commit string) error {
+	if output, err := run(path, "update-ref", ref, commit); err != nil {
+		return fmt.Errorf("failed to update %s: %s", ref, output)
+	}
+	return nil
+}
+
+// PushCommit pushes commit to branch on remote. The push is refused unless
+// it fast-forwards the remote branch.
+func PushCommit(path, remote, commit, branch string) error {
+	if output, err := run(path, "push", "-q", remote, commit+":refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s", branch, remote, output)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WorktreeStatus summarizes a worktree's uncommitted changes and how its
+// branch compares with the base branch and with its upstream
+type WorktreeStatus struct {
+	Changed int // files with uncommitted changes, untracked files included
+	Ahead   int // commits on HEAD that are not on base
+	Behind  int // commits on base that are not on HEAD
+
+	Upstream string // remote branch HEAD tracks; empty when not pushed
+	Unpushed int    // commits on HEAD that are not on its upstream
+	Unpulled int    // commits on the upstream that are not on HEAD
+}
+
+// Pushed reports whether the branch has an upstream that is level with it
+func (st WorktreeStatus) Pushed() bool {
+	return st.Upstream != "" && st.Unpushed == 0 && st.Unpulled == 0
+}
+
+// Status reports the state of the worktree at path. Ahead and Behind are
+// left at zero when base is empty.
+func Status(path, base string) (WorktreeStatus, error) {
+	var st WorktreeStatus
+
+	output, err := run(path, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return st, fmt.Errorf("failed to check git status: %s", output)
+	}
+	var upstream string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.upstream "):
+			upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// Only reported while the upstream exists on the remote
+			st.Upstream = upstream
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &st.Unpushed, &st.Unpulled)
+		case strings.HasPrefix(line, "# "):
+		default:
+			st.Changed++
+		}
+	}
+
+	if base == "" {
+		return st, nil
+	}
+	output, err = run(path, "rev-list", "--left-right", "--count", base+"...HEAD")
+	if err != nil {
+		return st, fmt.Errorf("failed to compare with %s: %s", base, output)
+	}
+	if behind, ahead, ok := strings.Cut(output, "\t"); ok {
+		st.Behind, _ = strconv.Atoi(behind)
+		st.Ahead, _ = strconv.Atoi(ahead)
+	}
+	return st, nil
+}
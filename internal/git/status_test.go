@@ -0,0 +1,64 @@
+package git
+
+import "testing"
+
+func TestStatus(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+
+	st, err := Status(wt, "main")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if st != (WorktreeStatus{}) {
+		t.Errorf("fresh worktree Status() = %+v; want zero", st)
+	}
+
+	writeFile(t, wt, "b.txt", "committed\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "ticket work")
+	writeFile(t, wt, "a.txt", "edited\n")
+	writeFile(t, wt, "c.txt", "untracked\n")
+	writeFile(t, repo, "d.txt", "base\n")
+	gitIn(repo, "add", ".")
+	gitIn(repo, "commit", "-qm", "base work")
+
+	st, err = Status(wt, "main")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if want := (WorktreeStatus{Changed: 2, Ahead: 1, Behind: 1}); st != want {
+		t.Errorf("Status() = %+v; want %+v", st, want)
+	}
+
+	if st, err := Status(wt, ""); err != nil || st.Ahead != 0 || st.Changed != 2 {
+		t.Errorf("Status() without base = %+v, %v", st, err)
+	}
+	if _, err := Status(wt, "no-such-branch"); err == nil {
+		t.Error("Status() with an unknown base should fail")
+	}
+}
+
+func TestStatusUpstream(t *testing.T) {
+	repo, wt, gitIn := testRepo(t)
+	gitIn(wt, "config", "push.default", "current")
+	gitIn(wt, "remote", "add", "origin", repo)
+
+	if st, err := Status(wt, "main"); err != nil || st.Upstream != "" || st.Pushed() {
+		t.Errorf("unpushed branch Status() = %+v, %v", st, err)
+	}
+
+	if err := Push(wt, "origin", "task"); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	st, err := Status(wt, "main")
+	if err != nil || st.Upstream != "origin/task" || !st.Pushed() {
+		t.Errorf("pushed branch Status() = %+v, %v", st, err)
+	}
+
+	writeFile(t, wt, "b.txt", "more\n")
+	gitIn(wt, "add", ".")
+	gitIn(wt, "commit", "-qm", "more work")
+	if st, _ := Status(wt, "main"); st.Unpushed != 1 || st.Unpulled != 0 || st.Pushed() {
+		t.Errorf("Status() after committing = %+v; want 1 unpushed", st)
+	}
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/telemetry"
+)
+
+// tracedGitCommands are the git subcommands exported as spans. The frequent
+// read-only commands behind the board's polling are left out.
+var tracedGitCommands = map[string]bool{
+	"worktree":    true,
+	"push":        true,
+	"fetch":       true,
+	"merge":       true,
+	"rebase":      true,
+	"checkout":    true,
+	"stash":       true,
+	"branch":      true,
+	"commit-tree": true,
+	"reset":       true,
+}
+
+// combinedOutput runs cmd like cmd.CombinedOutput, tracing git commands
+// that change the repository and every code host CLI call
+func combinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	span := traceCommand(cmd)
+	output, err := cmd.CombinedOutput()
+	span.End(err)
+	return output, err
+}
+
+// traceCommand starts a span named after the program and subcommand, such
+// as "git push", or returns nil for commands that are not traced
+func traceCommand(cmd *exec.Cmd) *telemetry.Span {
+	program := filepath.Base(cmd.Args[0])
+	args := cmd.Args[1:]
+	// Skip global options like "-c core.quotepath=off"
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if args[0] == "-c" && len(args) > 1 {
+			args = args[1:]
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	name := program + " " + args[0]
+	if program == "git" {
+		if !tracedGitCommands[args[0]] || args[0] == "worktree" && len(args) > 1 && args[1] == "list" {
+			return nil
+		}
+		if args[0] == "worktree" && len(args) > 1 {
+			name += " " + args[1]
+		}
+	}
+	return telemetry.Start(name,
+		telemetry.String("process.command_line", strings.Join(cmd.Args, " ")),
+		telemetry.String("process.working_directory", cmd.Dir))
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/telemetry"
+)
+
+func TestTraceCommand(t *testing.T) {
+	tracer := telemetry.New("http://127.0.0.1:1", "test", nil)
+	telemetry.SetDefault(tracer)
+	defer telemetry.SetDefault(nil)
+	defer tracer.Shutdown()
+
+	tests := []struct {
+		args   []string
+		traced bool
+	}{
+		{[]string{"git", "push", "--set-upstream", "origin", "task/x"}, true},
+		{[]string{"git", "-c", "core.quotepath=off", "worktree", "add", "/tmp/x"}, true},
+		{[]string{"git", "worktree", "list", "--porcelain"}, false},
+		{[]string{"git", "status", "--porcelain"}, false},
+		{[]string{"git", "-c", "core.quotepath=off", "diff"}, false},
+		{[]string{"gh", "pr", "create"}, true},
+	}
+	for _, tt := range tests {
+		if got := traceCommand(exec.Command(tt.args[0], tt.args[1:]...)) != nil; got != tt.traced {
+			t.Errorf("traceCommand(%v) traced = %v; want %v", tt.args, got, tt.traced)
+		}
+	}
+}
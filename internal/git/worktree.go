@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -29,7 +31,19 @@ func NewWorktreeManagerFromPaths(repoPath, baseDir string) *WorktreeManager {
 	}
 }
 
+// BaseDir returns the directory new worktrees are created in.
+func (m *WorktreeManager) BaseDir() string {
+	return m.baseDir
+}
+
 func (m *WorktreeManager) CreateWorktree(branchName, baseBranch string) (string, error) {
+	return m.CreateWorktreeContext(context.Background(), branchName, baseBranch)
+}
+
+// CreateWorktreeContext is CreateWorktree that stops git when ctx is
+// cancelled. A cancelled checkout is cleaned up, along with the branch if
+// this call created it.
+func (m *WorktreeManager) CreateWorktreeContext(ctx context.Context, branchName, baseBranch string) (string, error) {
 	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create worktree base directory: %w", err)
 	}
@@ -43,24 +57,37 @@ func (m *WorktreeManager) CreateWorktree(branchName, baseBranch string) (string,
 		os.RemoveAll(worktreePath)
 	}
 
-	cmd := exec.Command("git", "worktree", "add", "-b", branchName, worktreePath, baseBranch)
+	newBranch := !m.BranchExists(branchName)
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", "-b", branchName, worktreePath, baseBranch)
 	cmd.Dir = m.repoPath
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		if strings.Contains(string(output), "already exists") {
-			cmd = exec.Command("git", "worktree", "add", worktreePath, branchName)
-			cmd.Dir = m.repoPath
-			if output2, err2 := cmd.CombinedOutput(); err2 != nil {
-				return "", fmt.Errorf("failed to create worktree: %s: %w", string(output2), err2)
-			}
-			return worktreePath, nil
-		}
+	output, err := combinedOutput(cmd)
+	if err != nil && ctx.Err() == nil && strings.Contains(string(output), "already exists") {
+		newBranch = false
+		cmd = exec.CommandContext(ctx, "git", "worktree", "add", worktreePath, branchName)
+		cmd.Dir = m.repoPath
+		output, err = combinedOutput(cmd)
+	}
+	if ctx.Err() != nil {
+		m.abandonWorktree(worktreePath, branchName, newBranch)
+		return "", ctx.Err()
+	}
+	if err != nil {
 		return "", fmt.Errorf("failed to create worktree: %s: %w", string(output), err)
 	}
 
 	return worktreePath, nil
 }
 
+// abandonWorktree removes what an interrupted "git worktree add" left behind
+func (m *WorktreeManager) abandonWorktree(worktreePath, branchName string, deleteBranch bool) {
+	os.RemoveAll(worktreePath)
+	m.PruneMetadata()
+	if deleteBranch && m.BranchExists(branchName) {
+		m.DeleteBranch(branchName)
+	}
+}
+
 func (m *WorktreeManager) isValidWorktree(path string) bool {
 	gitPath := filepath.Join(path, ".git")
 	info, err := os.Stat(gitPath)
@@ -75,7 +102,7 @@ func (m *WorktreeManager) RemoveWorktree(worktreePath string) error {
 	cmd := exec.Command("git", "worktree", "remove", worktreePath, "--force")
 	cmd.Dir = m.repoPath
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(cmd); err != nil {
 		if !strings.Contains(string(output), "not a working tree") {
 			return fmt.Errorf("failed to remove worktree: %s: %w", string(output), err)
 		}
@@ -102,6 +129,19 @@ func (m *WorktreeManager) ListWorktrees() ([]Worktree, error) {
 	return parseWorktreeList(string(output)), nil
 }
 
+// PruneMetadata removes git's administrative records for worktrees whose
+// directories no longer exist.
+func (m *WorktreeManager) PruneMetadata() error {
+	cmd := exec.Command("git", "worktree", "prune")
+	cmd.Dir = m.repoPath
+
+	if output, err := combinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %s: %w", string(output), err)
+	}
+
+	return nil
+}
+
 type Worktree struct {
 	Path   string
 	HEAD   string
@@ -158,7 +198,7 @@ func (m *WorktreeManager) DeleteBranch(branchName string) error {
 	cmd := exec.Command("git", "branch", "-D", branchName)
 	cmd.Dir = m.repoPath
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(cmd); err != nil {
 		return fmt.Errorf("failed to delete branch: %s: %w", string(output), err)
 	}
 
@@ -175,7 +215,7 @@ func (m *WorktreeManager) CreateBranch(branchName, baseBranch string) error {
 	cmd := exec.Command("git", "branch", branchName, baseBranch)
 	cmd.Dir = m.repoPath
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(cmd); err != nil {
 		return fmt.Errorf("failed to create branch: %s: %w", string(output), err)
 	}
 
@@ -186,7 +226,7 @@ func (m *WorktreeManager) CheckoutBranch(branchName string) error {
 	cmd := exec.Command("git", "checkout", branchName)
 	cmd.Dir = m.repoPath
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(cmd); err != nil {
 		return fmt.Errorf("failed to checkout branch: %s: %w", string(output), err)
 	}
 
@@ -194,6 +234,9 @@ func (m *WorktreeManager) CheckoutBranch(branchName string) error {
 }
 
 func (m *WorktreeManager) SetupBranch(branchName, baseBranch string) error {
+	if m.IsBare() {
+		return ErrBareRepo
+	}
 	if !m.BranchExists(branchName) {
 		if err := m.CreateBranch(branchName, baseBranch); err != nil {
 			return err
@@ -202,6 +245,9 @@ func (m *WorktreeManager) SetupBranch(branchName, baseBranch string) error {
 	return m.CheckoutBranch(branchName)
 }
 
+// ErrBareRepo is returned for operations that need the main checkout
+var ErrBareRepo = errors.New("bare repository has no working tree; use a worktree for this ticket")
+
 func (m *WorktreeManager) HasUncommittedChanges(worktreePath string) (bool, error) {
 	cmd := exec.Command("git", "status", "--porcelain")
 	cmd.Dir = worktreePath
@@ -222,7 +268,61 @@ func sanitizeBranchName(name string) string {
 	return name
 }
 
+// FindRepoRoot returns the top of the working tree containing path, or the
+// git directory itself for a bare repository. Git does the lookup, so
+// $GIT_DIR, $GIT_WORK_TREE and .git files pointing elsewhere are honored.
+// It falls back to walking up to the nearest directory containing .git and
+// returns "" when path is not inside a git checkout.
+func FindRepoRoot(path string) string {
+	if top, err := run(path, "rev-parse", "--show-toplevel"); err == nil && top != "" {
+		return filepath.Clean(top)
+	}
+	if bare, err := run(path, "rev-parse", "--is-bare-repository"); err == nil && bare == "true" {
+		if dir, err := run(path, "rev-parse", "--absolute-git-dir"); err == nil {
+			return filepath.Clean(dir)
+		}
+	}
+
+	dir := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// CommonDir returns the git directory shared by all of path's worktrees
+func CommonDir(path string) (string, error) {
+	dir, err := run(path, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(path, dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// ResolveMainRepo returns the path identifying the repository that path
+// belongs to: the main working tree, even when path is a linked worktree.
+// Bare repositories and repositories whose git directory lives outside the
+// working tree ($GIT_DIR setups, jj's git backend) are identified by the git
+// directory. Paths git cannot resolve are read from their .git file, or
+// returned unchanged.
 func ResolveMainRepo(path string) string {
+	if common, err := run(path, "rev-parse", "--path-format=absolute", "--git-common-dir"); err == nil && common != "" {
+		common = filepath.Clean(common)
+		if filepath.Base(common) == ".git" {
+			return filepath.Dir(common)
+		}
+		return common
+	}
+
 	gitPath := filepath.Join(path, ".git")
 	info, err := os.Stat(gitPath)
 	if err != nil {
@@ -247,3 +347,28 @@ func ResolveMainRepo(path string) string {
 
 	return path
 }
+
+// IsRepository reports whether path is inside a git repository, bare
+// repositories included
+func IsRepository(path string) bool {
+	if _, err := run(path, "rev-parse", "--git-dir"); err == nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// IsBare reports whether the repository has no working tree of its own, so
+// tickets can only work in linked worktrees
+func (m *WorktreeManager) IsBare() bool {
+	out, err := run(m.repoPath, "rev-parse", "--is-bare-repository")
+	return err == nil && out == "true"
+}
+
+// IsolateEnv clears GIT_DIR and GIT_WORK_TREE from the environment. Git
+// commands inherit them, which would point every project's commands at the
+// same repository; call it once the repository they name has been resolved.
+func IsolateEnv() {
+	os.Unsetenv("GIT_DIR")
+	os.Unsetenv("GIT_WORK_TREE")
+}
@@ -1,8 +1,11 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -227,6 +230,33 @@ func TestResolveMainRepo(t *testing.T) {
 	})
 }
 
+func TestFindRepoRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	repoPath := filepath.Join(tmpDir, "repo")
+	nested := filepath.Join(repoPath, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FindRepoRoot(nested); got != repoPath {
+		t.Errorf("FindRepoRoot(%q) = %q; want %q", nested, got, repoPath)
+	}
+	if got := FindRepoRoot(repoPath); got != repoPath {
+		t.Errorf("FindRepoRoot(%q) = %q; want %q", repoPath, got, repoPath)
+	}
+
+	outside := filepath.Join(tmpDir, "outside")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRepoRoot(outside); got != "" && !strings.HasPrefix(outside, got) {
+		t.Errorf("FindRepoRoot(%q) = %q; want empty or an ancestor", outside, got)
+	}
+}
+
 func TestNewWorktreeManagerFromPaths(t *testing.T) {
 	mgr := NewWorktreeManagerFromPaths("/repo/path", "/worktrees/path")
 
@@ -237,3 +267,85 @@ func TestNewWorktreeManagerFromPaths(t *testing.T) {
 		t.Errorf("baseDir = %q; want %q", mgr.baseDir, "/worktrees/path")
 	}
 }
+
+func TestBareRepository(t *testing.T) {
+	repo, _, gitIn := testRepo(t)
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bare := filepath.Join(root, "repo.git")
+	gitIn(root, "clone", "-q", "--bare", repo, bare)
+
+	mgr := NewWorktreeManagerFromPaths(bare, filepath.Join(root, "repo-worktrees"))
+	if !mgr.IsBare() {
+		t.Fatal("IsBare() = false for a bare clone")
+	}
+	if err := mgr.SetupBranch("main-repo", "main"); err != ErrBareRepo {
+		t.Errorf("SetupBranch() in a bare repo = %v; want ErrBareRepo", err)
+	}
+
+	wt, err := mgr.CreateWorktree("feature-x", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+	if got := ResolveMainRepo(wt); got != bare {
+		t.Errorf("ResolveMainRepo(worktree) = %q; want %q", got, bare)
+	}
+	if got := ResolveMainRepo(bare); got != bare {
+		t.Errorf("ResolveMainRepo(bare) = %q; want %q", got, bare)
+	}
+	if got := FindRepoRoot(filepath.Join(bare, "refs")); got != bare {
+		t.Errorf("FindRepoRoot(inside bare) = %q; want %q", got, bare)
+	}
+	if got := FindRepoRoot(wt); got != wt {
+		t.Errorf("FindRepoRoot(worktree) = %q; want %q", got, wt)
+	}
+	if !IsRepository(bare) || !IsRepository(wt) {
+		t.Error("IsRepository() = false for a bare repo or its worktree")
+	}
+}
+
+func TestResolveMainRepo_GitDirEnv(t *testing.T) {
+	repo, _, _ := testRepo(t)
+	elsewhere := t.TempDir()
+
+	t.Setenv("GIT_DIR", filepath.Join(repo, ".git"))
+	if got := ResolveMainRepo(elsewhere); got != repo {
+		t.Errorf("ResolveMainRepo() with GIT_DIR = %q; want %q", got, repo)
+	}
+	if !IsRepository(elsewhere) {
+		t.Error("IsRepository() with GIT_DIR = false")
+	}
+
+	IsolateEnv()
+	if got := ResolveMainRepo(elsewhere); got != elsewhere {
+		t.Errorf("ResolveMainRepo() after IsolateEnv = %q; want %q", got, elsewhere)
+	}
+}
+
+func TestCreateWorktreeContext_Cancelled(t *testing.T) {
+	repo, _, _ := testRepo(t)
+	base := filepath.Join(t.TempDir(), "worktrees")
+	mgr := NewWorktreeManagerFromPaths(repo, base)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mgr.CreateWorktreeContext(ctx, "feature-x", "main"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateWorktreeContext() error = %v; want context.Canceled", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "feature-x")); !os.IsNotExist(err) {
+		t.Error("cancelled worktree directory was left behind")
+	}
+	if mgr.BranchExists("feature-x") {
+		t.Error("cancelled worktree's branch was left behind")
+	}
+
+	path, err := mgr.CreateWorktreeContext(context.Background(), "feature-x", "main")
+	if err != nil {
+		t.Fatalf("CreateWorktreeContext() after cancel error = %v", err)
+	}
+	if !mgr.isValidWorktree(path) {
+		t.Errorf("%s is not a worktree", path)
+	}
+}
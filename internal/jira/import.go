@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Import maps issues onto the tickets of project projectID. An issue without
+// a ticket becomes a new ticket in the column matching its status category.
+// Linked tickets pick up the issue's summary and description but keep their
+// column, which the board drives. It returns the new and the changed
+// tickets; neither is saved.
+func Import(tickets []*board.Ticket, issues []Issue, projectID string) (created, updated []*board.Ticket) {
+	linked := make(map[string]*board.Ticket)
+	for _, t := range tickets {
+		if key := t.Meta[MetaKey]; key != "" && t.ProjectID == projectID {
+			linked[key] = t
+		}
+	}
+
+	for _, issue := range issues {
+		title := issue.Key + ": " + strings.TrimSpace(issue.Summary)
+		description := strings.TrimSpace(issue.Description)
+
+		if t, ok := linked[issue.Key]; ok {
+			if t.Title != title || t.Description != description {
+				t.Title = title
+				t.Description = description
+				t.Touch()
+				updated = append(updated, t)
+			}
+			continue
+		}
+
+		t := board.NewTicket(title, projectID)
+		t.Description = description
+		t.Labels = slices.Clone(issue.Labels)
+		t.Priority = priority(issue.Priority)
+		t.Meta[MetaKey] = issue.Key
+		switch issue.Category {
+		case "indeterminate":
+			t.SetStatus(board.StatusInProgress)
+		case "done":
+			t.SetStatus(board.StatusDone)
+		}
+		created = append(created, t)
+	}
+	return created, updated
+}
+
+// priority maps Jira's default priority schemes onto ticket priorities,
+// 1 (highest) to 5
+func priority(name string) int {
+	switch strings.ToLower(name) {
+	case "highest", "blocker":
+		return 1
+	case "high", "critical":
+		return 2
+	case "low", "minor":
+		return 4
+	case "lowest", "trivial":
+		return 5
+	}
+	return 3
+}
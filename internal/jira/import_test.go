@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestImport(t *testing.T) {
+	existing := board.NewTicket("KAN-1: Old title", "p1")
+	existing.Meta[MetaKey] = "KAN-1"
+	existing.Status = board.StatusDone
+	unchanged := board.NewTicket("KAN-3: Same", "p1")
+	unchanged.Meta[MetaKey] = "KAN-3"
+	other := board.NewTicket("KAN-2: Elsewhere", "p2")
+	other.Meta[MetaKey] = "KAN-2"
+
+	issues := []Issue{
+		{Key: "KAN-1", Summary: "New title", Category: "new"},
+		{Key: "KAN-2", Summary: "Second", Category: "indeterminate", Priority: "Highest", Labels: []string{"api"}},
+		{Key: "KAN-3", Summary: "Same"},
+	}
+	created, updated := Import([]*board.Ticket{existing, unchanged, other}, issues, "p1")
+
+	if len(updated) != 1 || updated[0] != existing || existing.Title != "KAN-1: New title" || existing.Status != board.StatusDone {
+		t.Errorf("updated = %v; want KAN-1 retitled and left in its column", updated)
+	}
+	if len(created) != 1 {
+		t.Fatalf("created %d tickets; want 1 (KAN-2 belongs to another project)", len(created))
+	}
+	c := created[0]
+	if c.Meta[MetaKey] != "KAN-2" || c.ProjectID != "p1" || c.Status != board.StatusInProgress || c.Priority != 1 || c.Labels[0] != "api" {
+		t.Errorf("created ticket = %+v", c)
+	}
+}
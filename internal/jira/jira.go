@@ -0,0 +1,214 @@
+// Package jira imports Jira issues as tickets and moves them through their
+// Jira workflow as the tickets change columns.
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TokenEnv is the environment variable holding the Jira API token. It is
+// never read from the config file.
+const TokenEnv = "JIRA_API_TOKEN"
+
+// MetaKey is the ticket meta field holding the linked issue key
+const MetaKey = "jira"
+
+// Issue is the part of a Jira issue that maps onto a ticket
+type Issue struct {
+	Key         string
+	Summary     string
+	Description string
+	Status      string
+	Category    string // status category: "new", "indeterminate" or "done"
+	Labels      []string
+	Priority    string
+}
+
+// Client talks to the Jira REST API
+type Client struct {
+	baseURL string
+	email   string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the Jira instance at baseURL. With an email
+// the token is an Atlassian API token (Jira Cloud); without one it is a
+// personal access token (Jira Data Center).
+func NewClient(baseURL, email, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		email:   email,
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewClientFromEnv returns a client using the token in JIRA_API_TOKEN
+func NewClientFromEnv(baseURL, email string) (*Client, error) {
+	token := os.Getenv(TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("set %s to connect to Jira", TokenEnv)
+	}
+	return NewClient(baseURL, email, token), nil
+}
+
+var errNotFound = errors.New("not found")
+
+// Search returns every issue matching jql
+func (c *Client) Search(jql string) ([]Issue, error) {
+	// Jira Cloud replaced /search with /search/jql, which pages by token;
+	// Data Center only has /search, which pages by offset
+	endpoint := "/rest/api/2/search/jql"
+	var issues []Issue
+	var pageToken string
+	for {
+		q := url.Values{
+			"jql":        {jql},
+			"fields":     {"summary,description,status,labels,priority"},
+			"maxResults": {"100"},
+			"startAt":    {fmt.Sprint(len(issues))},
+		}
+		if pageToken != "" {
+			q.Set("nextPageToken", pageToken)
+		}
+
+		var resp struct {
+			Issues []struct {
+				Key    string `json:"key"`
+				Fields struct {
+					Summary     string   `json:"summary"`
+					Description string   `json:"description"`
+					Labels      []string `json:"labels"`
+					Status      struct {
+						Name     string `json:"name"`
+						Category struct {
+							Key string `json:"key"`
+						} `json:"statusCategory"`
+					} `json:"status"`
+					Priority *struct {
+						Name string `json:"name"`
+					} `json:"priority"`
+				} `json:"fields"`
+			} `json:"issues"`
+			Total         int    `json:"total"`
+			NextPageToken string `json:"nextPageToken"`
+			IsLast        bool   `json:"isLast"`
+		}
+		err := c.do(http.MethodGet, endpoint+"?"+q.Encode(), nil, &resp)
+		if errors.Is(err, errNotFound) && endpoint != "/rest/api/2/search" && len(issues) == 0 {
+			endpoint = "/rest/api/2/search"
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, raw := range resp.Issues {
+			issue := Issue{
+				Key:         raw.Key,
+				Summary:     raw.Fields.Summary,
+				Description: raw.Fields.Description,
+				Status:      raw.Fields.Status.Name,
+				Category:    raw.Fields.Status.Category.Key,
+				Labels:      raw.Fields.Labels,
+			}
+			if raw.Fields.Priority != nil {
+				issue.Priority = raw.Fields.Priority.Name
+			}
+			issues = append(issues, issue)
+		}
+
+		pageToken = resp.NextPageToken
+		if len(resp.Issues) == 0 || resp.IsLast || (pageToken == "" && len(issues) >= resp.Total) {
+			return issues, nil
+		}
+	}
+}
+
+// Transition moves the issue to the named status through whichever
+// workflow transition leads there. Transitions are matched by their target
+// status or their own name, ignoring case.
+func (c *Client) Transition(key, status string) error {
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", nil, &resp); err != nil {
+		return fmt.Errorf("failed to list transitions for %s: %w", key, err)
+	}
+
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			body := map[string]any{"transition": map[string]string{"id": t.ID}}
+			if err := c.do(http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", body, nil); err != nil {
+				return fmt.Errorf("failed to move %s to %s: %w", key, status, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%s has no transition to %q from its current status", key, status)
+}
+
+func (c *Client) do(method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			ErrorMessages []string `json:"errorMessages"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if len(apiErr.ErrorMessages) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, strings.Join(apiErr.ErrorMessages, "; "))
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
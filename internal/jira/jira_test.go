@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearch_PagesAndFallsBack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "me@example.com" || pass != "tok" {
+			http.Error(w, "", http.StatusUnauthorized)
+			return
+		}
+		// A Data Center instance without /search/jql
+		if r.URL.Path != "/rest/api/2/search" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("jql") != "project = KAN" {
+			http.Error(w, `{"errorMessages":["bad jql"]}`, http.StatusBadRequest)
+			return
+		}
+		page := `{"total":2,"issues":[{"key":"KAN-1","fields":{"summary":"First","status":{"name":"To Do","statusCategory":{"key":"new"}},"priority":{"name":"High"}}}]}`
+		if r.URL.Query().Get("startAt") == "1" {
+			page = `{"total":2,"issues":[{"key":"KAN-2","fields":{"summary":"Second","labels":["ui"],"status":{"name":"Doing","statusCategory":{"key":"indeterminate"}}}}]}`
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/", "me@example.com", "tok")
+	issues, err := c.Search("project = KAN")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(issues) != 2 || issues[0].Key != "KAN-1" || issues[0].Priority != "High" || issues[1].Category != "indeterminate" || issues[1].Labels[0] != "ui" {
+		t.Errorf("Search() = %+v", issues)
+	}
+
+	if _, err := c.Search("nonsense"); err == nil || err.Error() != "400 Bad Request: bad jql" {
+		t.Errorf("Search() with bad JQL error = %v", err)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	var moved string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" || r.URL.Path != "/rest/api/2/issue/KAN-1/transitions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPost {
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			moved = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"Start work","to":{"name":"In Progress"}},{"id":"31","name":"Finish","to":{"name":"Done"}}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "pat")
+	if err := c.Transition("KAN-1", "in progress"); err != nil || moved != "11" {
+		t.Errorf("Transition() = %v, moved %q; want transition 11", err, moved)
+	}
+	if err := c.Transition("KAN-1", "Finish"); err != nil || moved != "31" {
+		t.Errorf("Transition() by name = %v, moved %q; want transition 31", err, moved)
+	}
+	if err := c.Transition("KAN-1", "Review"); err == nil {
+		t.Error("Transition() to a status with no transition succeeded")
+	}
+}
@@ -0,0 +1,550 @@
+// Package keymap maps board actions to keys. Bindings start from a preset
+// and can be overridden per action in the config.
+package keymap
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Action names a bindable board command.
+type Action string
+
+const (
+	MoveLeft      Action = "move_left"
+	MoveRight     Action = "move_right"
+	MoveUp        Action = "move_up"
+	MoveDown      Action = "move_down"
+	FirstTicket   Action = "first_ticket"
+	LastTicket    Action = "last_ticket"
+	NewTicket     Action = "new_ticket"
+	EditTicket    Action = "edit_ticket"
+	EditNotes     Action = "edit_notes"
+	DeleteTicket  Action = "delete_ticket"
+	ArchiveTicket Action = "archive_ticket"
+	ColorTag      Action = "color_tag"
+	MoveForward   Action = "move_forward"
+	MoveBackward  Action = "move_backward"
+	ApproveTicket Action = "approve_ticket"
+	RejectTicket  Action = "reject_ticket"
+	RaiseTicket   Action = "raise_ticket"
+	LowerTicket   Action = "lower_ticket"
+	SpawnAgent    Action = "spawn_agent"
+	StopAgent     Action = "stop_agent"
+	AttachAgent   Action = "attach_agent"
+	Transcript    Action = "transcript"
+	ResendPrompt  Action = "resend_prompt"
+	FollowUps     Action = "follow_ups"
+	DetachAgent   Action = "detach_agent"
+	NewFromText   Action = "new_from_selection"
+	Sessions      Action = "sessions"
+	FocusPane     Action = "focus_pane"
+	ViewDiff      Action = "view_diff"
+	ViewLog       Action = "view_log"
+	RunTests      Action = "run_tests"
+	PushBranch    Action = "push_branch"
+	CreatePR      Action = "create_pr"
+	MergeTicket   Action = "merge_ticket"
+	RebaseTicket  Action = "rebase_ticket"
+	SyncBase      Action = "sync_base"
+	Worktrees     Action = "worktrees"
+	OpenEditor    Action = "open_editor"
+	ToggleSidebar Action = "toggle_sidebar"
+	ToggleSplit   Action = "toggle_split"
+	CardDensity   Action = "card_density"
+	TogglePreview Action = "toggle_preview"
+	FileFeed      Action = "file_feed"
+	FocusMode     Action = "focus_mode"
+	Collapse      Action = "collapse_column"
+	NextTab       Action = "next_tab"
+	PrevTab       Action = "prev_tab"
+	FocusSidebar  Action = "focus_sidebar"
+	Filter        Action = "filter"
+	SavedFilters  Action = "saved_filters"
+	Notifications Action = "notifications"
+	ToggleMute    Action = "toggle_mute"
+	ErrorConsole  Action = "error_console"
+	Clipboard     Action = "clipboard_history"
+	QuickSwitch   Action = "quick_switch"
+	Command       Action = "command"
+	DebugOverlay  Action = "debug_overlay"
+	RecordMacro   Action = "record_macro"
+	Macros        Action = "macros"
+	Settings      Action = "settings"
+	Help          Action = "help"
+	Quit          Action = "quit"
+)
+
+// Group is a heading in the help overlay.
+type Group string
+
+const (
+	GroupNavigation Group = "Navigation"
+	GroupTickets    Group = "Tickets"
+	GroupAgents     Group = "Agents"
+	GroupGit        Group = "Git"
+	GroupView       Group = "View"
+)
+
+// Context is where a binding is active. Keys only conflict within a context.
+type Context string
+
+const (
+	ContextBoard Context = "board"
+	ContextAgent Context = "agent"
+)
+
+// Info describes an action for help and validation.
+type Info struct {
+	Action      Action
+	Description string
+	Group       Group
+	Context     Context
+}
+
+// Actions lists every bindable action in help order.
+var Actions = []Info{
+	{MoveLeft, "Previous column", GroupNavigation, ContextBoard},
+	{MoveRight, "Next column", GroupNavigation, ContextBoard},
+	{MoveUp, "Previous ticket", GroupNavigation, ContextBoard},
+	{MoveDown, "Next ticket", GroupNavigation, ContextBoard},
+	{FirstTicket, "First ticket", GroupNavigation, ContextBoard},
+	{LastTicket, "Last ticket", GroupNavigation, ContextBoard},
+	{NewTicket, "New ticket", GroupTickets, ContextBoard},
+	{EditTicket, "Edit ticket", GroupTickets, ContextBoard},
+	{EditNotes, "Edit working notes", GroupTickets, ContextBoard},
+	{DeleteTicket, "Delete ticket", GroupTickets, ContextBoard},
+	{ArchiveTicket, "Archive ticket", GroupTickets, ContextBoard},
+	{ColorTag, "Cycle ticket color tag", GroupTickets, ContextBoard},
+	{MoveForward, "Move forward", GroupTickets, ContextBoard},
+	{MoveBackward, "Move backward", GroupTickets, ContextBoard},
+	{ApproveTicket, "Approve reviewed ticket", GroupTickets, ContextBoard},
+	{RejectTicket, "Reject reviewed ticket with feedback", GroupTickets, ContextBoard},
+	{RaiseTicket, "Move ticket up its column", GroupTickets, ContextBoard},
+	{LowerTicket, "Move ticket down its column", GroupTickets, ContextBoard},
+	{SpawnAgent, "Spawn agent", GroupAgents, ContextBoard},
+	{StopAgent, "Stop agent", GroupAgents, ContextBoard},
+	{AttachAgent, "Attach to agent", GroupAgents, ContextBoard},
+	{Transcript, "Last agent run's transcript", GroupAgents, ContextBoard},
+	{ResendPrompt, "Re-send context prompt", GroupAgents, ContextBoard},
+	{FollowUps, "Backlog the agent's follow-ups", GroupAgents, ContextBoard},
+	{DetachAgent, "Exit agent view", GroupAgents, ContextAgent},
+	{NewFromText, "New ticket from the selected text", GroupAgents, ContextAgent},
+	{FocusPane, "Focus agent pane (split view)", GroupAgents, ContextBoard},
+	{Sessions, "Agent sessions", GroupAgents, ContextBoard},
+	{ViewDiff, "Review changes", GroupGit, ContextBoard},
+	{ViewLog, "Commit log", GroupGit, ContextBoard},
+	{RunTests, "Run the project's tests", GroupGit, ContextBoard},
+	{PushBranch, "Push branch", GroupGit, ContextBoard},
+	{CreatePR, "Create pull request", GroupGit, ContextBoard},
+	{MergeTicket, "Merge into base", GroupGit, ContextBoard},
+	{RebaseTicket, "Rebase onto base", GroupGit, ContextBoard},
+	{SyncBase, "Sync with base", GroupGit, ContextBoard},
+	{Worktrees, "Worktrees and disk usage", GroupGit, ContextBoard},
+	{OpenEditor, "Open worktree in editor", GroupGit, ContextBoard},
+	{ToggleSidebar, "Toggle sidebar", GroupView, ContextBoard},
+	{ToggleSplit, "Split board and agent pane", GroupView, ContextBoard},
+	{CardDensity, "Cycle card density", GroupView, ContextBoard},
+	{TogglePreview, "Toggle agent output preview", GroupView, ContextBoard},
+	{FileFeed, "Toggle the feed of files agents change", GroupView, ContextBoard},
+	{FocusMode, "Focus on in-progress tickets", GroupView, ContextBoard},
+	{Collapse, "Collapse/expand column", GroupView, ContextBoard},
+	{NextTab, "Next project tab", GroupView, ContextBoard},
+	{PrevTab, "Previous project tab", GroupView, ContextBoard},
+	{FocusSidebar, "Focus sidebar", GroupView, ContextBoard},
+	{Filter, "Search/filter", GroupView, ContextBoard},
+	{SavedFilters, "Saved filters", GroupView, ContextBoard},
+	{QuickSwitch, "Go to ticket or project", GroupView, ContextBoard},
+	{Notifications, "Notification history", GroupView, ContextBoard},
+	{ToggleMute, "Mute desktop and webhook notifications", GroupView, ContextBoard},
+	{ErrorConsole, "Error console", GroupView, ContextBoard},
+	{Clipboard, "Text copied from agent panes", GroupView, ContextBoard},
+	{Command, "Command", GroupView, ContextBoard},
+	{RecordMacro, "Start/stop recording a macro", GroupView, ContextBoard},
+	{Macros, "Macros", GroupView, ContextBoard},
+	{DebugOverlay, "Debug overlay", GroupView, ContextBoard},
+	{Settings, "Settings", GroupView, ContextBoard},
+	{Help, "Toggle help", GroupView, ContextBoard},
+	{Quit, "Quit", GroupView, ContextBoard},
+}
+
+// Lookup returns the description of action.
+func Lookup(action Action) (Info, bool) {
+	for _, info := range Actions {
+		if info.Action == action {
+			return info, true
+		}
+	}
+	return Info{}, false
+}
+
+// Presets returns the names of the bundled presets.
+func Presets() []string {
+	return []string{"default", "vim", "emacs"}
+}
+
+// Bindings maps each action to the keys that trigger it.
+type Bindings map[Action][]string
+
+var defaultBindings = Bindings{
+	MoveLeft:      {"h", "left"},
+	MoveRight:     {"l", "right"},
+	MoveUp:        {"k", "up"},
+	MoveDown:      {"j", "down"},
+	FirstTicket:   {"g"},
+	LastTicket:    {"G"},
+	NewTicket:     {"n"},
+	EditTicket:    {"e"},
+	EditNotes:     {"m"},
+	DeleteTicket:  {"d"},
+	ArchiveTicket: {"A"},
+	ColorTag:      {"c"},
+	MoveForward:   {" "},
+	MoveBackward:  {"-", "backspace"},
+	ApproveTicket: {"a"},
+	RejectTicket:  {"r"},
+	RaiseTicket:   {"K"},
+	LowerTicket:   {"J"},
+	SpawnAgent:    {"s"},
+	StopAgent:     {"S"},
+	AttachAgent:   {"enter"},
+	Transcript:    {"t"},
+	ResendPrompt:  {"I"},
+	FollowUps:     {"B"},
+	ViewDiff:      {"D"},
+	ViewLog:       {"L"},
+	RunTests:      {"X"},
+	PushBranch:    {"p"},
+	CreatePR:      {"P"},
+	MergeTicket:   {"M"},
+	RebaseTicket:  {"R"},
+	SyncBase:      {"U"},
+	Worktrees:     {"W"},
+	OpenEditor:    {"E"},
+	DetachAgent:   {"ctrl+g"},
+	NewFromText:   {"ctrl+n"},
+	FocusPane:     {"ctrl+g"},
+	Sessions:      {"T"},
+	ToggleSidebar: {"["},
+	ToggleSplit:   {"|"},
+	CardDensity:   {"z"},
+	TogglePreview: {"v"},
+	FileFeed:      {"w"},
+	FocusMode:     {"f"},
+	Collapse:      {"C"},
+	NextTab:       {"}"},
+	PrevTab:       {"{"},
+	FocusSidebar:  {"tab"},
+	Filter:        {"/"},
+	SavedFilters:  {"F"},
+	QuickSwitch:   {"ctrl+k"},
+	Notifications: {"!"},
+	ToggleMute:    {"Z"},
+	ErrorConsole:  {"ctrl+e"},
+	Clipboard:     {"y"},
+	Command:       {":"},
+	RecordMacro:   {"Q"},
+	Macros:        {"@"},
+	DebugOverlay:  {"f12"},
+	Settings:      {"O"},
+	Help:          {"?"},
+	Quit:          {"q"},
+}
+
+// reservedKeys are handled before bindings are consulted and cannot be bound.
+var reservedKeys = map[string]bool{"esc": true, "ctrl+c": true}
+
+// presetOverrides holds each preset's differences from the default bindings.
+var presetOverrides = map[string]Bindings{
+	"default": {},
+	"vim": {
+		NewTicket:     {"o"},
+		EditTicket:    {"i"},
+		DeleteTicket:  {"x"},
+		MoveForward:   {">", " "},
+		MoveBackward:  {"<", "-"},
+		ToggleSidebar: {"ctrl+w"},
+	},
+	"emacs": {
+		MoveLeft:     {"ctrl+b", "left"},
+		MoveRight:    {"ctrl+f", "right"},
+		MoveUp:       {"ctrl+p", "up"},
+		MoveDown:     {"ctrl+n", "down"},
+		FirstTicket:  {"alt+<", "home"},
+		LastTicket:   {"alt+>", "end"},
+		NewTicket:    {"ctrl+o"},
+		DeleteTicket: {"ctrl+d"},
+		Filter:       {"ctrl+s"},
+		Command:      {"alt+x"},
+		Quit:         {"ctrl+x"},
+	},
+}
+
+// Preset returns a copy of the named preset's bindings. An empty name
+// selects the default preset.
+func Preset(name string) (Bindings, error) {
+	if name == "" {
+		name = "default"
+	}
+	overrides, ok := presetOverrides[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown keybinding preset %q (available: %s)", name, strings.Join(Presets(), ", "))
+	}
+
+	b := make(Bindings, len(defaultBindings))
+	for action, keys := range defaultBindings {
+		b[action] = append([]string(nil), keys...)
+	}
+	for action, keys := range overrides {
+		b[action] = append([]string(nil), keys...)
+	}
+	return b, nil
+}
+
+// NormalizeKey converts a key as written in the config to the form produced
+// by bubbletea's KeyMsg.String(), e.g. "space" to " " and "Ctrl+G" to "ctrl+g".
+func NormalizeKey(key string) string {
+	key = strings.TrimSpace(key)
+	if strings.EqualFold(key, "space") {
+		return " "
+	}
+	if key == " " || len([]rune(key)) == 1 {
+		return key
+	}
+
+	// Modifiers, named keys and ctrl combinations are lowercase in
+	// bubbletea; only a plain alt combination ("alt+G") keeps its case.
+	parts := strings.Split(key, "+")
+	last := len(parts) - 1
+	for i := range parts[:last] {
+		parts[i] = strings.ToLower(parts[i])
+	}
+	if len(parts) != 2 || parts[0] != "alt" || len([]rune(parts[last])) > 1 {
+		parts[last] = strings.ToLower(parts[last])
+	}
+	return strings.Join(parts, "+")
+}
+
+// DisplayKey formats a key for help text.
+func DisplayKey(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "enter":
+		return "Enter"
+	case "tab":
+		return "Tab"
+	case "backspace":
+		return "Bksp"
+	}
+	if strings.HasPrefix(key, "ctrl+") {
+		return "Ctrl+" + strings.TrimPrefix(key, "ctrl+")
+	}
+	if strings.HasPrefix(key, "alt+") {
+		return "Alt+" + strings.TrimPrefix(key, "alt+")
+	}
+	return key
+}
+
+// Conflict is a key bound to more than one action in the same context.
+type Conflict struct {
+	Key     string
+	Actions []Action
+}
+
+func (c Conflict) String() string {
+	names := make([]string, len(c.Actions))
+	for i, a := range c.Actions {
+		names[i] = string(a)
+	}
+	return fmt.Sprintf("key %q is bound to %s", DisplayKey(c.Key), strings.Join(names, " and "))
+}
+
+// Keymap resolves keys to actions.
+type Keymap struct {
+	bindings Bindings
+	byKey    map[Context]map[string]Action
+}
+
+// New builds a keymap from preset with per-action overrides applied. Override
+// keys are action names; an empty key list unbinds the action. Unknown
+// actions and reserved keys are errors. Keys bound to several actions are
+// returned as conflicts; the first action in Actions order wins.
+func New(preset string, overrides map[string][]string) (*Keymap, []Conflict, error) {
+	bindings, err := Preset(preset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, keys := range overrides {
+		action := Action(name)
+		if _, ok := Lookup(action); !ok {
+			return nil, nil, fmt.Errorf("unknown keybinding action %q", name)
+		}
+		normalized := make([]string, 0, len(keys))
+		for _, key := range keys {
+			key = NormalizeKey(key)
+			if key == "" {
+				continue
+			}
+			if reservedKeys[key] {
+				return nil, nil, fmt.Errorf("key %q for %s is reserved", key, name)
+			}
+			normalized = append(normalized, key)
+		}
+		bindings[action] = normalized
+	}
+
+	k := &Keymap{bindings: bindings, byKey: make(map[Context]map[string]Action)}
+	owners := make(map[Context]map[string][]Action)
+	for _, info := range Actions {
+		if owners[info.Context] == nil {
+			owners[info.Context] = make(map[string][]Action)
+			k.byKey[info.Context] = make(map[string]Action)
+		}
+		for _, key := range bindings[info.Action] {
+			owners[info.Context][key] = append(owners[info.Context][key], info.Action)
+			if _, taken := k.byKey[info.Context][key]; !taken {
+				k.byKey[info.Context][key] = info.Action
+			}
+		}
+	}
+
+	var conflicts []Conflict
+	for _, byKey := range owners {
+		for key, actions := range byKey {
+			if len(actions) > 1 {
+				conflicts = append(conflicts, Conflict{Key: key, Actions: actions})
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Key < conflicts[j].Key })
+
+	return k, conflicts, nil
+}
+
+// Default returns the default keymap.
+func Default() *Keymap {
+	k, _, _ := New("", nil)
+	return k
+}
+
+// Action returns the action bound to key in ctx.
+func (k *Keymap) Action(ctx Context, key string) (Action, bool) {
+	action, ok := k.byKey[ctx][key]
+	return action, ok
+}
+
+// Matches reports whether key triggers action.
+func (k *Keymap) Matches(key string, action Action) bool {
+	for _, bound := range k.bindings[action] {
+		if bound == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys bound to action.
+func (k *Keymap) Keys(action Action) []string {
+	return k.bindings[action]
+}
+
+// Label formats the keys bound to action for help text, e.g. "h/←".
+func (k *Keymap) Label(action Action) string {
+	keys := k.bindings[action]
+	if len(keys) == 0 {
+		return "unbound"
+	}
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = DisplayKey(key)
+	}
+	return strings.Join(labels, "/")
+}
+
+// HelpEntry is one line of the help overlay.
+type HelpEntry struct {
+	Keys        string
+	Description string
+}
+
+// HelpSection is a titled group of help entries.
+type HelpSection struct {
+	Group   Group
+	Entries []HelpEntry
+}
+
+// Help returns the bound actions grouped for the help overlay.
+func (k *Keymap) Help() []HelpSection {
+	var sections []HelpSection
+	index := make(map[Group]int)
+	for _, info := range Actions {
+		if len(k.bindings[info.Action]) == 0 {
+			continue
+		}
+		i, ok := index[info.Group]
+		if !ok {
+			i = len(sections)
+			index[info.Group] = i
+			sections = append(sections, HelpSection{Group: info.Group})
+		}
+		sections[i].Entries = append(sections[i].Entries, HelpEntry{
+			Keys:        k.Label(info.Action),
+			Description: info.Description,
+		})
+	}
+	return sections
+}
+
+// WriteCheatSheet writes the bound actions as a printable Markdown page:
+// a table per help group for the board, then one for the agent view.
+func (k *Keymap) WriteCheatSheet(w io.Writer, title string) error {
+	var groups []Group
+	rows := make(map[Group][]string)
+	var agentRows []string
+	for _, info := range Actions {
+		if len(k.bindings[info.Action]) == 0 {
+			continue
+		}
+		row := "| " + k.markdownKeys(info.Action) + " | " + info.Description + " |\n"
+		if info.Context == ContextAgent {
+			agentRows = append(agentRows, row)
+			continue
+		}
+		if _, ok := rows[info.Group]; !ok {
+			groups = append(groups, info.Group)
+		}
+		rows[info.Group] = append(rows[info.Group], row)
+	}
+
+	var b strings.Builder
+	b.WriteString("# " + title + "\n")
+	table := func(heading string, rows []string) {
+		b.WriteString("\n## " + heading + "\n\n| Key | Action |\n|-----|--------|\n")
+		for _, row := range rows {
+			b.WriteString(row)
+		}
+	}
+	for _, group := range groups {
+		table(string(group), rows[group])
+	}
+	if len(agentRows) > 0 {
+		table("Agent View", agentRows)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownKeys formats the keys bound to action as code spans for a
+// Markdown table, e.g. "`h` / `left`"
+func (k *Keymap) markdownKeys(action Action) string {
+	keys := k.bindings[action]
+	spans := make([]string, len(keys))
+	for i, key := range keys {
+		spans[i] = "`" + strings.ReplaceAll(DisplayKey(key), "|", `\|`) + "`"
+	}
+	return strings.Join(spans, " / ")
+}
@@ -0,0 +1,157 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for _, preset := range Presets() {
+		t.Run(preset, func(t *testing.T) {
+			k, conflicts, err := New(preset, nil)
+			if err != nil {
+				t.Fatalf("New(%q) error = %v", preset, err)
+			}
+			if len(conflicts) > 0 {
+				t.Errorf("preset %q has conflicts: %v", preset, conflicts)
+			}
+			for _, info := range Actions {
+				if len(k.Keys(info.Action)) == 0 {
+					t.Errorf("preset %q leaves %s unbound", preset, info.Action)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultBindingsCoverActions(t *testing.T) {
+	for _, info := range Actions {
+		if _, ok := defaultBindings[info.Action]; !ok {
+			t.Errorf("no default binding for %s", info.Action)
+		}
+	}
+}
+
+func TestNew_Overrides(t *testing.T) {
+	k, conflicts, err := New("vim", map[string][]string{
+		"new_ticket": {"N"},
+		"quit":       {},
+		"filter":     {"Ctrl+F", "space"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if action, ok := k.Action(ContextBoard, "N"); !ok || action != NewTicket {
+		t.Errorf("Action(N) = %q, %v; want new_ticket", action, ok)
+	}
+	if _, ok := k.Action(ContextBoard, "o"); ok {
+		t.Error("vim key o should be replaced by override")
+	}
+	if _, ok := k.Action(ContextBoard, "q"); ok {
+		t.Error("quit should be unbound")
+	}
+	if !k.Matches("ctrl+f", Filter) {
+		t.Error("Ctrl+F should normalize to ctrl+f")
+	}
+
+	// space is bound to move_forward in the vim preset too
+	if len(conflicts) != 1 || conflicts[0].Key != " " {
+		t.Fatalf("conflicts = %v; want one on space", conflicts)
+	}
+	if got := conflicts[0].String(); !strings.Contains(got, "move_forward and filter") {
+		t.Errorf("Conflict.String() = %q", got)
+	}
+	if action, _ := k.Action(ContextBoard, " "); action != MoveForward {
+		t.Errorf("conflicting key resolved to %s; want move_forward (first in Actions order)", action)
+	}
+}
+
+func TestNew_ContextsDoNotConflict(t *testing.T) {
+	_, conflicts, err := New("", map[string][]string{"detach_agent": {"q"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("agent view and board keys should not conflict: %v", conflicts)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[string][]string
+		want      string
+	}{
+		{"unknown preset", "helix", nil, "unknown keybinding preset"},
+		{"unknown action", "", map[string][]string{"fly": {"f"}}, "unknown keybinding action"},
+		{"reserved key", "", map[string][]string{"quit": {"ctrl+c"}}, "reserved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := New(tt.preset, tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New() error = %v; want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := map[string]string{
+		"space":  " ",
+		"G":      "G",
+		"Ctrl+G": "ctrl+g",
+		"alt+G":  "alt+G",
+		"Enter":  "enter",
+		" tab ":  "tab",
+	}
+	for in, want := range tests {
+		if got := NormalizeKey(in); got != want {
+			t.Errorf("NormalizeKey(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	k, _, _ := New("", map[string][]string{"settings": {}})
+	sections := k.Help()
+
+	if len(sections) != 5 || sections[0].Group != GroupNavigation {
+		t.Fatalf("Help() sections = %+v", sections)
+	}
+	if got := sections[0].Entries[0]; got.Keys != "h/left" || got.Description != "Previous column" {
+		t.Errorf("first entry = %+v", got)
+	}
+	for _, s := range sections {
+		for _, e := range s.Entries {
+			if e.Description == "Settings" {
+				t.Error("unbound actions should be omitted from help")
+			}
+		}
+	}
+}
+
+func TestWriteCheatSheet(t *testing.T) {
+	k, _, _ := New("", map[string][]string{"settings": {}, "toggle_split": {"|", "ctrl+x"}})
+	var b strings.Builder
+	if err := k.WriteCheatSheet(&b, "Keys"); err != nil {
+		t.Fatalf("WriteCheatSheet() error = %v", err)
+	}
+	sheet := b.String()
+
+	for _, want := range []string{
+		"# Keys\n",
+		"\n## Navigation\n\n| Key | Action |\n|-----|--------|\n| `h` / `left` | Previous column |\n",
+		"| `\\|` / `Ctrl+x` | ",
+		"\n## Agent View\n\n| Key | Action |\n|-----|--------|\n| `Ctrl+g` | Exit agent view |\n",
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("cheat sheet is missing %q:\n%s", want, sheet)
+		}
+	}
+	if strings.Contains(sheet, "| Settings |") {
+		t.Error("unbound actions should be omitted from the cheat sheet")
+	}
+}
@@ -0,0 +1,57 @@
+package linear
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Import maps issues onto the tickets of project projectID. An issue without
+// a ticket becomes a new ticket, in progress when its state has started.
+// Linked tickets pick up the issue's title and description but keep their
+// column, which the board drives. It returns the new and the changed
+// tickets; neither is saved.
+func Import(tickets []*board.Ticket, issues []Issue, projectID string) (created, updated []*board.Ticket) {
+	linked := make(map[string]*board.Ticket)
+	for _, t := range tickets {
+		if id := t.Meta[MetaKey]; id != "" && t.ProjectID == projectID {
+			linked[id] = t
+		}
+	}
+
+	for _, issue := range issues {
+		title := issue.Identifier + ": " + strings.TrimSpace(issue.Title)
+		description := strings.TrimSpace(issue.Description)
+
+		if t, ok := linked[issue.Identifier]; ok {
+			if t.Title != title || t.Description != description {
+				t.Title = title
+				t.Description = description
+				t.Touch()
+				updated = append(updated, t)
+			}
+			continue
+		}
+
+		t := board.NewTicket(title, projectID)
+		t.Description = description
+		t.Labels = slices.Clone(issue.Labels)
+		t.Priority = priority(issue.Priority)
+		t.Meta[MetaKey] = issue.Identifier
+		if issue.StateType == "started" {
+			t.SetStatus(board.StatusInProgress)
+		}
+		created = append(created, t)
+	}
+	return created, updated
+}
+
+// priority maps Linear's 1 (urgent) to 4 (low) onto ticket priorities, with
+// no priority in the middle
+func priority(p int) int {
+	if p < 1 || p > 4 {
+		return 3
+	}
+	return p
+}
@@ -0,0 +1,33 @@
+package linear
+
+import (
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestImport(t *testing.T) {
+	existing := board.NewTicket("ENG-1: Old title", "p1")
+	existing.Meta[MetaKey] = "ENG-1"
+	existing.Status = board.StatusDone
+
+	issues := []Issue{
+		{Identifier: "ENG-1", Title: "New title", StateType: "unstarted"},
+		{Identifier: "ENG-2", Title: "Second", StateType: "started", Priority: 1, Labels: []string{"api"}},
+		{Identifier: "ENG-3", Title: "Third", StateType: "backlog"},
+	}
+	created, updated := Import([]*board.Ticket{existing}, issues, "p1")
+
+	if len(updated) != 1 || existing.Title != "ENG-1: New title" || existing.Status != board.StatusDone {
+		t.Errorf("updated = %v; want ENG-1 retitled and left in its column", updated)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d tickets; want 2", len(created))
+	}
+	if c := created[0]; c.Meta[MetaKey] != "ENG-2" || c.Status != board.StatusInProgress || c.Priority != 1 || c.Labels[0] != "api" {
+		t.Errorf("created ticket = %+v", c)
+	}
+	if c := created[1]; c.Status != board.StatusBacklog || c.Priority != 3 {
+		t.Errorf("created ticket = %+v; want a backlog ticket with normal priority", c)
+	}
+}
@@ -0,0 +1,241 @@
+// Package linear imports Linear issues as tickets, moves them through their
+// team's workflow as the tickets change columns and links the agent's branch
+// and pull request back to the issue.
+package linear
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Endpoint is the Linear GraphQL API
+const Endpoint = "https://api.linear.app/graphql"
+
+// KeyEnv is the environment variable holding the API key for projects that
+// don't set linear_api_key
+const KeyEnv = "LINEAR_API_KEY"
+
+// MetaKey is the ticket meta field holding the linked issue identifier
+const MetaKey = "linear"
+
+// BranchMetaKey is the ticket meta field recording the branch last reported
+// on the linked issue, so it is only commented once
+const BranchMetaKey = "linear_branch"
+
+// Issue is the part of a Linear issue that maps onto a ticket
+type Issue struct {
+	Identifier  string // e.g. "ENG-42"
+	Title       string
+	Description string
+	State       string
+	StateType   string // "triage", "backlog", "unstarted", "started", "completed" or "canceled"
+	Labels      []string
+	Priority    int // 0 (none), 1 (urgent) to 4 (low)
+}
+
+// Client talks to the Linear GraphQL API
+type Client struct {
+	endpoint string
+	key      string
+	http     *http.Client
+}
+
+// NewClient returns a client authenticating with a personal API key
+func NewClient(key string) *Client {
+	return &Client{
+		endpoint: Endpoint,
+		key:      key,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewClientFromEnv returns a client using key, or the key in LINEAR_API_KEY
+// when key is empty
+func NewClientFromEnv(key string) (*Client, error) {
+	if key == "" {
+		key = os.Getenv(KeyEnv)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("set linear_api_key or %s to connect to Linear", KeyEnv)
+	}
+	return NewClient(key), nil
+}
+
+const issuesQuery = `query($team: String!, $after: String) {
+  team(id: $team) {
+    issues(first: 100, after: $after, filter: {state: {type: {nin: ["completed", "canceled"]}}}) {
+      nodes {
+        identifier title description priority
+        state { name type }
+        labels { nodes { name } }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+// Issues returns the team's open issues, leaving out completed and canceled
+// ones
+func (c *Client) Issues(teamID string) ([]Issue, error) {
+	var issues []Issue
+	var after *string
+	for {
+		var data struct {
+			Team struct {
+				Issues struct {
+					Nodes []struct {
+						Identifier  string `json:"identifier"`
+						Title       string `json:"title"`
+						Description string `json:"description"`
+						Priority    int    `json:"priority"`
+						State       struct {
+							Name string `json:"name"`
+							Type string `json:"type"`
+						} `json:"state"`
+						Labels struct {
+							Nodes []struct {
+								Name string `json:"name"`
+							} `json:"nodes"`
+						} `json:"labels"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"team"`
+		}
+		if err := c.do(issuesQuery, map[string]any{"team": teamID, "after": after}, &data); err != nil {
+			return nil, fmt.Errorf("failed to list issues of team %s: %w", teamID, err)
+		}
+
+		page := data.Team.Issues
+		for _, raw := range page.Nodes {
+			issue := Issue{
+				Identifier:  raw.Identifier,
+				Title:       raw.Title,
+				Description: raw.Description,
+				State:       raw.State.Name,
+				StateType:   raw.State.Type,
+				Priority:    raw.Priority,
+			}
+			for _, l := range raw.Labels.Nodes {
+				issue.Labels = append(issue.Labels, l.Name)
+			}
+			issues = append(issues, issue)
+		}
+		if !page.PageInfo.HasNextPage {
+			return issues, nil
+		}
+		cursor := page.PageInfo.EndCursor
+		after = &cursor
+	}
+}
+
+// SetState moves the issue to the team's workflow state with the given name,
+// ignoring case
+func (c *Client) SetState(identifier, teamID, state string) error {
+	var data struct {
+		Team struct {
+			States struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"states"`
+		} `json:"team"`
+	}
+	q := `query($team: String!) { team(id: $team) { states { nodes { id name } } } }`
+	if err := c.do(q, map[string]any{"team": teamID}, &data); err != nil {
+		return fmt.Errorf("failed to list workflow states of team %s: %w", teamID, err)
+	}
+
+	for _, s := range data.Team.States.Nodes {
+		if strings.EqualFold(s.Name, state) {
+			m := `mutation($id: String!, $state: String!) { issueUpdate(id: $id, input: {stateId: $state}) { success } }`
+			if err := c.do(m, map[string]any{"id": identifier, "state": s.ID}, nil); err != nil {
+				return fmt.Errorf("failed to move %s to %s: %w", identifier, state, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("team %s has no workflow state %q", teamID, state)
+}
+
+// AttachURL links url, such as a pull request, to the issue. Linear shows
+// pull requests from GitHub and GitLab with their status.
+func (c *Client) AttachURL(identifier, url, title string) error {
+	m := `mutation($id: String!, $url: String!, $title: String) { attachmentLinkURL(issueId: $id, url: $url, title: $title) { success } }`
+	if err := c.do(m, map[string]any{"id": identifier, "url": url, "title": title}, nil); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", url, identifier, err)
+	}
+	return nil
+}
+
+// Comment adds a markdown comment to the issue
+func (c *Client) Comment(identifier, body string) error {
+	var data struct {
+		Issue struct {
+			ID string `json:"id"`
+		} `json:"issue"`
+	}
+	// commentCreate takes the issue's UUID rather than its identifier
+	if err := c.do(`query($id: String!) { issue(id: $id) { id } }`, map[string]any{"id": identifier}, &data); err != nil {
+		return fmt.Errorf("failed to find %s: %w", identifier, err)
+	}
+	m := `mutation($id: String!, $body: String!) { commentCreate(input: {issueId: $id, body: $body}) { success } }`
+	if err := c.do(m, map[string]any{"id": data.Issue.ID, "body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", identifier, err)
+	}
+	return nil
+}
+
+func (c *Client) do(query string, variables map[string]any, out any) error {
+	payload, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.key)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Linear: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// GraphQL errors usually come with 200, but Linear also answers 400 for
+	// bad input and 401 for bad keys, with the same body
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode < 300 {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(body.Errors) > 0 {
+		var msgs []string
+		for _, e := range body.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return fmt.Errorf("%s", strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body.Data, out)
+}
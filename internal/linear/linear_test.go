@@ -0,0 +1,75 @@
+package linear
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type request struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+func testClient(t *testing.T, handler func(req request) string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Authentication required"}]}`))
+			return
+		}
+		var req request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(handler(req)))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("lin_key")
+	c.endpoint = srv.URL
+	return c
+}
+
+func TestIssues_Pages(t *testing.T) {
+	c := testClient(t, func(req request) string {
+		if req.Variables["after"] == nil {
+			return `{"data":{"team":{"issues":{"nodes":[{"identifier":"ENG-1","title":"First","priority":2,"state":{"name":"Todo","type":"unstarted"},"labels":{"nodes":[{"name":"bug"}]}}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}`
+		}
+		if req.Variables["after"] != "c1" {
+			return `{"errors":[{"message":"bad cursor"}]}`
+		}
+		return `{"data":{"team":{"issues":{"nodes":[{"identifier":"ENG-2","title":"Second","state":{"name":"In Progress","type":"started"},"labels":{"nodes":[]}}],"pageInfo":{"hasNextPage":false}}}}}`
+	})
+
+	issues, err := c.Issues("team-1")
+	if err != nil {
+		t.Fatalf("Issues() error = %v", err)
+	}
+	if len(issues) != 2 || issues[0].Labels[0] != "bug" || issues[0].Priority != 2 || issues[1].StateType != "started" {
+		t.Errorf("Issues() = %+v", issues)
+	}
+
+	c.key = "wrong"
+	if _, err := c.Issues("team-1"); err == nil || err.Error() != "failed to list issues of team team-1: Authentication required" {
+		t.Errorf("Issues() with a bad key error = %v", err)
+	}
+}
+
+func TestSetState(t *testing.T) {
+	var moved any
+	c := testClient(t, func(req request) string {
+		if req.Variables["state"] != nil {
+			moved = req.Variables["state"]
+			return `{"data":{"issueUpdate":{"success":true}}}`
+		}
+		return `{"data":{"team":{"states":{"nodes":[{"id":"s1","name":"Todo"},{"id":"s2","name":"In Progress"}]}}}}`
+	})
+
+	if err := c.SetState("ENG-1", "team-1", "in progress"); err != nil || moved != "s2" {
+		t.Errorf("SetState() = %v, moved to %v; want s2", err, moved)
+	}
+	if err := c.SetState("ENG-1", "team-1", "Review"); err == nil {
+		t.Error("SetState() to a missing state succeeded")
+	}
+}
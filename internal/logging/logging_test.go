@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{"debug": slog.LevelDebug, "": slog.LevelInfo, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) succeeded; want an error")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", FileName)
+	r, err := OpenRotating(path, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	line := strings.Repeat("x", 39) + "\n"
+	for i := 0; i < 10; i++ {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(name), err)
+		}
+		if info.Size() > 100 {
+			t.Errorf("%s is %d bytes; want at most 100", filepath.Base(name), info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("kept more rotated logs than MaxFiles")
+	}
+}
+
+func TestRotatingFile_AppendsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	for i := 0; i < 2; i++ {
+		r, err := OpenRotating(path, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(r, "run %d\n", i)
+		r.Close()
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "run 0\nrun 1\n" {
+		t.Errorf("log = %q; want both runs", data)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer func(l *slog.Logger) { slog.SetDefault(l) }(slog.Default())
+
+	path := Path(t.TempDir())
+	closer, err := Setup(path, Options{Level: slog.LevelWarn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.Info("dropped")
+	slog.Warn("kept", "ticket", "abc")
+	closer.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "dropped") {
+		t.Error("info record written below the warn level")
+	}
+	if !strings.Contains(string(data), `level=WARN msg=kept ticket=abc`) {
+		t.Errorf("log = %q; want the warning", data)
+	}
+}
+
+func TestTail(t *testing.T) {
+	lines, err := Tail(strings.NewReader("a\nb\nc\n"), 2)
+	if err != nil || strings.Join(lines, ",") != "b,c" {
+		t.Errorf("Tail(2) = %v, %v; want [b c]", lines, err)
+	}
+	lines, _ = Tail(strings.NewReader("a\nb\nc\n"), 0)
+	if len(lines) != 3 {
+		t.Errorf("Tail(0) returned %d lines; want all 3", len(lines))
+	}
+}
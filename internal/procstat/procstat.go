@@ -0,0 +1,157 @@
+// Package procstat samples the CPU and memory used by an agent and every
+// process it started, such as a build it kicked off, from the process table.
+package procstat
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned where there is no ps to read processes from
+var ErrUnsupported = errors.New("process usage is not supported on " + runtime.GOOS)
+
+// Usage is what a process tree uses
+type Usage struct {
+	CPU   float64 // percent of one core since the previous sample; 0 on the first
+	RSS   int64   // resident memory, in bytes
+	Procs int     // processes in the tree
+}
+
+type proc struct {
+	ppid int
+	rss  int64
+	cpu  time.Duration
+}
+
+// Table is a snapshot of the machine's processes
+type Table struct {
+	at       time.Time
+	procs    map[int]proc
+	children map[int][]int
+}
+
+// Read lists the running processes with ps, which reads /proc on Linux
+func Read() (*Table, error) {
+	if runtime.GOOS == "windows" {
+		return nil, ErrUnsupported
+	}
+	out, err := exec.Command("ps", "-A", "-o", "pid=", "-o", "ppid=", "-o", "rss=", "-o", "time=").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parse(string(out), time.Now()), nil
+}
+
+// parse reads ps output of pid, ppid, rss in KiB and cumulative CPU time
+func parse(out string, at time.Time) *Table {
+	t := &Table{at: at, procs: make(map[int]proc), children: make(map[int][]int)}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		cpu, ok := parseCPUTime(fields[3])
+		if err1 != nil || err2 != nil || err3 != nil || !ok {
+			continue
+		}
+		t.procs[pid] = proc{ppid: ppid, rss: rss * 1024, cpu: cpu}
+		if pid != ppid {
+			t.children[ppid] = append(t.children[ppid], pid)
+		}
+	}
+	return t
+}
+
+// parseCPUTime reads ps's time column: [[dd-]hh:]mm:ss on Linux, with
+// fractional seconds on macOS
+func parseCPUTime(s string) (time.Duration, bool) {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, false
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+	total := time.Duration(secs*float64(time.Second)) + time.Duration(days)*24*time.Hour
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total, true
+}
+
+// tree sums the CPU time and memory of pid and its descendants
+func (t *Table) tree(pid int) (cpu time.Duration, rss int64, n int) {
+	if _, ok := t.procs[pid]; !ok {
+		return 0, 0, 0
+	}
+	stack := []int{pid}
+	seen := make(map[int]bool)
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		cpu += t.procs[p].cpu
+		rss += t.procs[p].rss
+		n++
+		stack = append(stack, t.children[p]...)
+	}
+	return cpu, rss, n
+}
+
+// Sampler turns successive tables into each session's usage
+type Sampler struct {
+	last map[string]sample
+}
+
+type sample struct {
+	at  time.Time
+	cpu time.Duration
+}
+
+// Sample returns the usage of each session's process tree, keyed like pids.
+// Sessions whose process has gone are left out.
+func (s *Sampler) Sample(t *Table, pids map[string]int) map[string]Usage {
+	last := s.last
+	s.last = make(map[string]sample, len(pids))
+	usage := make(map[string]Usage, len(pids))
+	for key, pid := range pids {
+		cpu, rss, n := t.tree(pid)
+		if n == 0 {
+			continue
+		}
+		u := Usage{RSS: rss, Procs: n}
+		// Children that exited take their CPU time with them, so the
+		// total can drop
+		if prev, ok := last[key]; ok && t.at.After(prev.at) && cpu > prev.cpu {
+			u.CPU = 100 * float64(cpu-prev.cpu) / float64(t.at.Sub(prev.at))
+		}
+		usage[key] = u
+		s.last[key] = sample{at: t.at, cpu: cpu}
+	}
+	return usage
+}
@@ -0,0 +1,60 @@
+package procstat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCPUTime(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:05":    5 * time.Second,
+		"01:02:03":    time.Hour + 2*time.Minute + 3*time.Second,
+		"2-00:00:01":  48*time.Hour + time.Second,
+		"0:01.50":     1500 * time.Millisecond,
+		"12:34.00":    12*time.Minute + 34*time.Second,
+		"1:00:00.25":  time.Hour + 250*time.Millisecond,
+		"3-1:00:00.0": 73 * time.Hour,
+	}
+	for in, want := range tests {
+		if got, ok := parseCPUTime(in); !ok || got != want {
+			t.Errorf("parseCPUTime(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	if _, ok := parseCPUTime("soon"); ok {
+		t.Error("parseCPUTime accepted garbage")
+	}
+}
+
+func TestSampler(t *testing.T) {
+	start := time.Now()
+	// 100 runs the agent, which started a build (101) that runs the
+	// compiler (102); 200 is unrelated
+	first := parse(`
+  1     0   1000 00:00:10
+100     1  20000 00:00:01
+101   100  30000 00:00:02
+102   101  50000 00:00:03
+200     1   4000 00:01:00
+`, start)
+
+	var s Sampler
+	usage := s.Sample(first, map[string]int{"ticket": 100, "gone": 999})
+	if _, ok := usage["gone"]; ok {
+		t.Error("a session without a process has usage")
+	}
+	u := usage["ticket"]
+	if u.Procs != 3 || u.RSS != 100000*1024 || u.CPU != 0 {
+		t.Errorf("first sample = %+v; want 3 processes, 100000 KiB and no CPU yet", u)
+	}
+
+	// Ten seconds later the compiler has used 15 more seconds of CPU
+	second := parse(`
+100     1  20000 00:00:01
+101   100  30000 00:00:02
+102   101  60000 00:00:18
+`, start.Add(10*time.Second))
+	u = s.Sample(second, map[string]int{"ticket": 100})["ticket"]
+	if u.CPU != 150 || u.RSS != 110000*1024 {
+		t.Errorf("second sample = %+v; want 150%% CPU and 110000 KiB", u)
+	}
+}
@@ -0,0 +1,120 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// HasWorktreeSetup reports whether new worktrees of the project need files
+// brought over or setup commands run
+func (p *Project) HasWorktreeSetup() bool {
+	s := p.Settings
+	return len(s.CopyFiles) > 0 || len(s.LinkFiles) > 0 || len(s.Setup) > 0
+}
+
+// CopyWorktreeFiles brings the files listed in copy_files and link_files from
+// the repo into a new worktree, copying or symlinking them respectively.
+// Patterns matching nothing are skipped, as are files the worktree already
+// has, so a checkout without a .env is not an error.
+func (p *Project) CopyWorktreeFiles(worktreePath string) error {
+	for _, entry := range []struct {
+		patterns []string
+		link     bool
+	}{
+		{p.Settings.CopyFiles, false},
+		{p.Settings.LinkFiles, true},
+	} {
+		for _, pattern := range entry.patterns {
+			if err := p.bringFiles(pattern, worktreePath, entry.link); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Project) bringFiles(pattern, worktreePath string, link bool) error {
+	if !filepath.IsLocal(pattern) {
+		return fmt.Errorf("%s: path must be inside the repo", pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(p.RepoPath, pattern))
+	if err != nil {
+		return fmt.Errorf("%s: %w", pattern, err)
+	}
+
+	for _, src := range matches {
+		rel, err := filepath.Rel(p.RepoPath, src)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(worktreePath, rel)
+		if _, err := os.Lstat(dst); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		}
+		if link {
+			err = os.Symlink(src, dst)
+		} else {
+			err = copyTree(src, dst)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to bring %s into worktree: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// copyTree copies a file, symlink or directory, keeping file modes
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyWorktreeFiles(t *testing.T) {
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	for name, content := range map[string]string{
+		".env":                  "SECRET=1\n",
+		"config/local.json":     "{}",
+		"config/local.dev.json": "{\"dev\":true}",
+		"certs/dev.pem":         "pem",
+		"already-there.txt":     "repo",
+	} {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "already-there.txt"), []byte("worktree"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProject("test", repo)
+	p.Settings.CopyFiles = []string{".env", "config/local*.json", "already-there.txt", "missing.env"}
+	p.Settings.LinkFiles = []string{"certs"}
+
+	if err := p.CopyWorktreeFiles(worktree); err != nil {
+		t.Fatalf("CopyWorktreeFiles() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		".env":                  "SECRET=1\n",
+		"config/local.json":     "{}",
+		"config/local.dev.json": "{\"dev\":true}",
+		"already-there.txt":     "worktree",
+		"certs/dev.pem":         "pem",
+	} {
+		got, err := os.ReadFile(filepath.Join(worktree, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(worktree, ".env")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf(".env mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+	if target, err := os.Readlink(filepath.Join(worktree, "certs")); err != nil || target != filepath.Join(repo, "certs") {
+		t.Errorf("certs link = %q, %v", target, err)
+	}
+}
+
+func TestCopyWorktreeFilesRejectsPathsOutsideRepo(t *testing.T) {
+	p := NewProject("test", t.TempDir())
+	for _, pattern := range []string{"../secrets", "/etc/passwd"} {
+		p.Settings.CopyFiles = []string{pattern}
+		if err := p.CopyWorktreeFiles(t.TempDir()); err == nil {
+			t.Errorf("CopyWorktreeFiles(%q) should fail", pattern)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/techdufus/openkanban/internal/board"
@@ -16,6 +18,7 @@ type SavedFilter struct {
 	ProjectIDs []string `json:"project_ids,omitempty"`
 	Statuses   []string `json:"statuses,omitempty"`
 	Labels     []string `json:"labels,omitempty"`
+	Query      string   `json:"query,omitempty"` // filter expression, see ParseQuery
 	IsDefault  bool     `json:"is_default"`
 }
 
@@ -26,6 +29,25 @@ func NewFilter(name string) *SavedFilter {
 	}
 }
 
+// Expression returns the filter's statuses, labels and query as a single
+// filter expression. Projects are kept apart since they are matched by ID.
+func (f *SavedFilter) Expression() string {
+	var parts []string
+	for _, s := range f.Statuses {
+		parts = append(parts, "status:"+s)
+	}
+	for _, l := range f.Labels {
+		if strings.ContainsAny(l, " \t") {
+			l = `"` + l + `"`
+		}
+		parts = append(parts, "label:"+l)
+	}
+	if f.Query != "" {
+		parts = append(parts, f.Query)
+	}
+	return strings.Join(parts, " ")
+}
+
 func (f *SavedFilter) Matches(ticket *board.Ticket) bool {
 	if len(f.ProjectIDs) > 0 {
 		found := false
@@ -164,10 +186,33 @@ func (r *FilterRegistry) Delete(id string) error {
 	return r.Save()
 }
 
+// List returns the saved filters ordered by name
 func (r *FilterRegistry) List() []*SavedFilter {
 	result := make([]*SavedFilter, 0, len(r.Filters))
 	for _, f := range r.Filters {
 		result = append(result, f)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
 	return result
 }
+
+// FindByName returns the saved filter with the given name, ignoring case
+func (r *FilterRegistry) FindByName(name string) *SavedFilter {
+	for _, f := range r.Filters {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// SetDefault makes f the filter applied on startup, or clears the default
+// when f is nil
+func (r *FilterRegistry) SetDefault(f *SavedFilter) error {
+	for _, other := range r.Filters {
+		other.IsDefault = other == f
+	}
+	return r.Save()
+}
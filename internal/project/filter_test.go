@@ -0,0 +1,48 @@
+package project
+
+import "testing"
+
+func TestSavedFilter_Expression(t *testing.T) {
+	f := NewFilter("review")
+	f.Statuses = []string{"in_progress"}
+	f.Labels = []string{"bug", "needs review"}
+	f.Query = "-agent:codex login"
+
+	want := `status:in_progress label:bug label:"needs review" -agent:codex login`
+	if got := f.Expression(); got != want {
+		t.Errorf("Expression() = %s; want %s", got, want)
+	}
+	if _, err := ParseQuery(f.Expression()); err != nil {
+		t.Errorf("Expression() does not parse: %v", err)
+	}
+}
+
+func TestFilterRegistry_SetDefault(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+
+	reg, err := LoadFilterRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bugs, mine := NewFilter("Bugs"), NewFilter("mine")
+	_ = reg.Add(bugs)
+	_ = reg.Add(mine)
+
+	if err := reg.SetDefault(mine); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetDefault(bugs); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _ := LoadFilterRegistry()
+	if d := loaded.GetDefault(); d == nil || d.Name != "Bugs" {
+		t.Errorf("GetDefault() = %v; want Bugs", d)
+	}
+	if f := loaded.FindByName("BUGS"); f == nil || f.ID != bugs.ID {
+		t.Errorf("FindByName(BUGS) = %v", f)
+	}
+	if list := loaded.List(); len(list) != 2 || list[0].Name != "Bugs" {
+		t.Errorf("List() not ordered by name: %v", list)
+	}
+}
@@ -0,0 +1,74 @@
+package project
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Order is how tickets or projects are listed. Every order ends in a
+// tie-break on ID, so a list never changes between calls just because it
+// came out of a map.
+type Order string
+
+const (
+	OrderCreated Order = "created" // oldest first
+	OrderUpdated Order = "updated" // most recently changed first
+	OrderName    Order = "name"    // by title or name, ignoring case
+	OrderManual  Order = "manual"  // as arranged by hand; see board.Ticket.Position
+)
+
+// TicketOrders and ProjectOrders are the orders each list accepts
+var (
+	TicketOrders  = []Order{OrderCreated, OrderUpdated, OrderName, OrderManual}
+	ProjectOrders = []Order{OrderName, OrderCreated}
+)
+
+// SortTickets sorts tickets in place. An unknown order sorts by creation.
+func SortTickets(tickets []*board.Ticket, order Order) {
+	slices.SortFunc(tickets, func(a, b *board.Ticket) int {
+		var c int
+		switch order {
+		case OrderUpdated:
+			c = b.UpdatedAt.Compare(a.UpdatedAt)
+		case OrderName:
+			c = cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case OrderManual:
+			// Tickets never arranged go after those that were
+			switch {
+			case a.Position != 0 && b.Position == 0:
+				c = -1
+			case a.Position == 0 && b.Position != 0:
+				c = 1
+			default:
+				c = cmp.Compare(a.Position, b.Position)
+			}
+		}
+		if c == 0 {
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		return c
+	})
+}
+
+// SortProjects sorts projects in place. An unknown order sorts by name.
+func SortProjects(projects []*Project, order Order) {
+	slices.SortFunc(projects, func(a, b *Project) int {
+		var c int
+		if order == OrderCreated {
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		return c
+	})
+}
@@ -0,0 +1,94 @@
+package project
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestSortTickets(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticket := func(id, title string, created, updated int, position int) *board.Ticket {
+		return &board.Ticket{
+			ID:        board.TicketID(id),
+			Title:     title,
+			CreatedAt: base.Add(time.Duration(created) * time.Hour),
+			UpdatedAt: base.Add(time.Duration(updated) * time.Hour),
+			Position:  position,
+		}
+	}
+	tickets := []*board.Ticket{
+		ticket("d", "delta", 3, 3, 0),
+		ticket("b", "Bravo", 1, 5, 2),
+		ticket("c", "charlie", 1, 1, 0),
+		ticket("a", "alpha", 2, 4, 1),
+	}
+
+	tests := []struct {
+		order Order
+		want  []board.TicketID
+	}{
+		{OrderCreated, []board.TicketID{"b", "c", "a", "d"}},
+		{OrderUpdated, []board.TicketID{"b", "a", "d", "c"}},
+		{OrderName, []board.TicketID{"a", "b", "c", "d"}},
+		{OrderManual, []board.TicketID{"a", "b", "c", "d"}},
+		{"unknown", []board.TicketID{"b", "c", "a", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			sorted := slices.Clone(tickets)
+			SortTickets(sorted, tt.order)
+			var got []board.TicketID
+			for _, t := range sorted {
+				got = append(got, t.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortTickets(%s) = %v; want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortProjects(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	projects := []*Project{
+		{ID: "2", Name: "api", CreatedAt: base},
+		{ID: "1", Name: "Web", CreatedAt: base.Add(time.Hour)},
+		{ID: "3", Name: "api", CreatedAt: base.Add(2 * time.Hour)},
+	}
+
+	SortProjects(projects, OrderName)
+	if got := []string{projects[0].ID, projects[1].ID, projects[2].ID}; !slices.Equal(got, []string{"2", "3", "1"}) {
+		t.Errorf("by name = %v; want [2 3 1]", got)
+	}
+	SortProjects(projects, OrderCreated)
+	if got := []string{projects[0].ID, projects[1].ID, projects[2].ID}; !slices.Equal(got, []string{"2", "1", "3"}) {
+		t.Errorf("by created = %v; want [2 1 3]", got)
+	}
+}
+
+func TestGlobalTicketStore_OrderIsStable(t *testing.T) {
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: "/path"}
+	globalStore := NewGlobalTicketStore(newRegistry())
+	globalStore.AddProject(p)
+	created := time.Now()
+	for i := 0; i < 20; i++ {
+		ticket := board.NewTicket("Same time", p.ID)
+		ticket.CreatedAt = created
+		globalStore.Add(ticket)
+	}
+
+	first := globalStore.GetByStatus(board.StatusBacklog)
+	for i := 0; i < 10; i++ {
+		if again := globalStore.GetByStatus(board.StatusBacklog); !slices.Equal(again, first) {
+			t.Fatal("GetByStatus order changed between calls")
+		}
+	}
+
+	globalStore.SetOrder(OrderName, "")
+	if globalStore.TicketOrder() != OrderName {
+		t.Errorf("TicketOrder = %s; want name", globalStore.TicketOrder())
+	}
+}
@@ -1,9 +1,14 @@
 package project
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/sandbox"
 )
 
 // Project represents a git repository registered with OpenKanban.
@@ -18,6 +23,9 @@ type Project struct {
 
 	// Project-specific settings (overrides global defaults)
 	Settings ProjectSettings `json:"settings"`
+
+	// Columns shown collapsed to a bar on the project's board
+	CollapsedColumns []board.TicketStatus `json:"collapsed_columns,omitempty"`
 }
 
 // ProjectSettings contains project-specific configuration.
@@ -29,6 +37,41 @@ type ProjectSettings struct {
 	BranchNaming     string `json:"branch_naming,omitempty"`   // "template" | "ai" | "prompt"
 	BranchTemplate   string `json:"branch_template,omitempty"` // e.g., "{prefix}{slug}"
 	SlugMaxLength    int    `json:"slug_max_length,omitempty"` // default: 40
+	KeyPrefix        string `json:"key_prefix,omitempty"`      // ticket keys, e.g. "OKB" for OKB-142; default from the name
+	DefaultAgent     string `json:"default_agent,omitempty"`   // agent for the project's new tickets; overrides defaults.default_agent
+
+	// Columns that spawn a ticket's agent when it is moved into them;
+	// overrides defaults.spawn_on
+	SpawnOn []board.TicketStatus `json:"spawn_on,omitempty"`
+
+	// Files git does not track, such as ".env", brought from the repo into
+	// each new worktree; paths are relative to the repo and may be globs
+	CopyFiles []string `json:"copy_files,omitempty"`
+	LinkFiles []string `json:"link_files,omitempty"`
+
+	// Setup commands run in order in each new worktree, e.g. "npm ci"
+	Setup []string `json:"setup,omitempty"`
+
+	// TestCommand runs the project's tests in a ticket's worktree, e.g.
+	// "go test ./..."
+	TestCommand string `json:"test_command,omitempty"`
+
+	// Code host for pull requests ("github" | "gitlab" | "gitea"), for
+	// remotes whose hostname doesn't tell; overrides pull_request.provider
+	Provider string `json:"provider,omitempty"`
+
+	// JQL selecting the Jira issues imported as tickets, e.g.
+	// "project = KAN AND sprint in openSprints()"
+	JiraJQL string `json:"jira_jql,omitempty"`
+
+	// Linear team whose issues are imported as tickets, and the API key to
+	// reach it; without a key LINEAR_API_KEY is used
+	LinearTeamID string `json:"linear_team_id,omitempty"`
+	LinearAPIKey string `json:"linear_api_key,omitempty"`
+
+	// Sandbox runs the project's agents in a container or under firejail,
+	// with only the worktree writable
+	Sandbox *sandbox.Config `json:"sandbox,omitempty"`
 }
 
 // NewProject creates a new project for a repository
@@ -36,7 +79,7 @@ func NewProject(name, repoPath string) *Project {
 	now := time.Now()
 
 	// Default worktree dir is sibling to repo: /path/to/repo -> /path/to/repo-worktrees
-	worktreeDir := repoPath + "-worktrees"
+	worktreeDir := defaultWorktreeDir(repoPath)
 
 	return &Project{
 		ID:          uuid.New().String(),
@@ -59,7 +102,13 @@ func (p *Project) GetWorktreeDir() string {
 	if p.WorktreeDir != "" {
 		return p.WorktreeDir
 	}
-	return p.RepoPath + "-worktrees"
+	return defaultWorktreeDir(p.RepoPath)
+}
+
+// defaultWorktreeDir places worktrees next to the repo. A bare clone such
+// as /path/to/repo.git gets /path/to/repo-worktrees.
+func defaultWorktreeDir(repoPath string) string {
+	return strings.TrimSuffix(repoPath, ".git") + "-worktrees"
 }
 
 // GetBranchPrefix returns the branch prefix, using default if not set
@@ -86,6 +135,39 @@ func (p *Project) GetSlugMaxLength() int {
 	return 40
 }
 
+// GetKeyPrefix returns the prefix of the project's ticket keys: the
+// key_prefix setting, or one made from the project's name, such as "OK"
+// for open-kanban or "APP" for app
+func (p *Project) GetKeyPrefix() string {
+	if prefix := strings.ToUpper(p.Settings.KeyPrefix); keyPrefixPattern.MatchString(prefix) {
+		return prefix
+	}
+
+	words := strings.FieldsFunc(strings.ToUpper(p.Name), func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+	var prefix string
+	if len(words) > 1 {
+		for _, w := range words[:min(len(words), 4)] {
+			prefix += w[:1]
+		}
+	} else if len(words) == 1 {
+		prefix = words[0][:min(len(words[0]), 3)]
+	}
+	if !keyPrefixPattern.MatchString(prefix) {
+		return "TKT"
+	}
+	return prefix
+}
+
+var keyPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+
+// TicketKey returns the ticket's short key, e.g. "OKB-142", or "" when it
+// has no number yet
+func (p *Project) TicketKey(t *board.Ticket) string {
+	return board.FormatKey(p.GetKeyPrefix(), t.Number)
+}
+
 // Touch updates the UpdatedAt timestamp
 func (p *Project) Touch() {
 	p.UpdatedAt = time.Now()
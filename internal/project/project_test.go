@@ -0,0 +1,37 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestProject_GetKeyPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		want    string
+	}{
+		{name: "openkanban", want: "OPE"},
+		{name: "open-kanban board", want: "OKB"},
+		{name: "my_app", want: "MA"},
+		{name: "a-b-c-d-e", want: "ABCD"},
+		{name: "日本", want: "TKT"},
+		{name: "app", setting: "web", want: "WEB"},
+		{name: "app", setting: "not valid", want: "APP"},
+	}
+	for _, tt := range tests {
+		p := &Project{Name: tt.name, Settings: ProjectSettings{KeyPrefix: tt.setting}}
+		if got := p.GetKeyPrefix(); got != tt.want {
+			t.Errorf("GetKeyPrefix() for %q (key_prefix %q) = %q; want %q", tt.name, tt.setting, got, tt.want)
+		}
+	}
+
+	p := &Project{Name: "open-kanban board"}
+	if got := p.TicketKey(&board.Ticket{Number: 142}); got != "OKB-142" {
+		t.Errorf("TicketKey() = %q; want OKB-142", got)
+	}
+	if got := p.TicketKey(&board.Ticket{}); got != "" {
+		t.Errorf("TicketKey() of an unnumbered ticket = %q; want none", got)
+	}
+}
@@ -0,0 +1,215 @@
+package project
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// QueryKeys are the keys a filter expression accepts, in the order they are
+// documented
+var QueryKeys = []string{"status", "agent", "state", "label", "color", "project", "priority", "running", "blocked"}
+
+// Query is a parsed board filter such as
+//
+//	status:in_progress agent:claude label:bug -label:wontfix login
+//
+// Terms with the same key match any of their values; different keys and
+// plain words must all match. A leading '-' negates a term, and values with
+// spaces are quoted: label:"needs review". Plain words are searched for in
+// the title and description, and @name is shorthand for project:name.
+type Query struct {
+	terms map[string][]string
+	not   map[string][]string
+	words []string
+}
+
+// QueryContext is what a ticket's match depends on beyond the ticket itself
+type QueryContext struct {
+	ProjectName string
+	Running     bool // an agent is running for the ticket
+}
+
+// ParseQuery parses a filter expression. An empty expression matches every
+// ticket.
+func ParseQuery(expr string) (*Query, error) {
+	q := &Query{terms: map[string][]string{}, not: map[string][]string{}}
+	tokens, err := splitQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		negate := false
+		if len(tok) > 1 && tok[0] == '-' {
+			negate, tok = true, tok[1:]
+		}
+		if strings.HasPrefix(tok, "@") && len(tok) > 1 {
+			tok = "project:" + tok[1:]
+		}
+		key, value, ok := strings.Cut(tok, ":")
+		if !ok {
+			if negate {
+				return nil, fmt.Errorf("-%s: only key:value terms can be negated", tok)
+			}
+			q.words = append(q.words, strings.ToLower(tok))
+			continue
+		}
+		key = strings.ToLower(key)
+		value, err := normalizeQueryValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			q.not[key] = append(q.not[key], value)
+		} else {
+			q.terms[key] = append(q.terms[key], value)
+		}
+	}
+	return q, nil
+}
+
+// splitQuery splits an expression on spaces, keeping quoted values whole
+func splitQuery(expr string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case (r == ' ' || r == '\t') && !quoted:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens, nil
+}
+
+func normalizeQueryValue(key, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("%s: missing value", key)
+	}
+	value = strings.ToLower(value)
+	switch key {
+	case "status":
+		value = strings.NewReplacer("-", "_", " ", "_").Replace(value)
+		if value == "inprogress" {
+			value = string(board.StatusInProgress)
+		}
+		switch board.TicketStatus(value) {
+		case board.StatusBacklog, board.StatusInProgress, board.StatusReview, board.StatusDone, board.StatusArchived:
+			return value, nil
+		}
+		return "", fmt.Errorf("status:%s: must be backlog, in_progress, review, done or archived", value)
+	case "state":
+		switch board.AgentStatus(value) {
+		case board.AgentNone, board.AgentIdle, board.AgentWorking, board.AgentWaiting, board.AgentCompleted, board.AgentError:
+			return value, nil
+		}
+		return "", fmt.Errorf("state:%s: must be none, idle, working, waiting, completed or error", value)
+	case "priority":
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 5 {
+			return "", fmt.Errorf("priority:%s: must be 1 to 5", value)
+		}
+		return value, nil
+	case "running", "blocked":
+		if value != "true" && value != "false" {
+			return "", fmt.Errorf("%s:%s: must be true or false", key, value)
+		}
+		return value, nil
+	case "color":
+		if _, ok := (config.ThemeColors{}).Lookup(value); !ok && value != "none" {
+			return "", fmt.Errorf("color:%s: must be none or a theme color (%s)", value, strings.Join(config.AccentColorNames, ", "))
+		}
+		return value, nil
+	case "agent", "label", "project":
+		return value, nil
+	case "overdue", "due":
+		return "", fmt.Errorf("%s: tickets have no due dates", key)
+	}
+	return "", fmt.Errorf("unknown filter key %q (use %s)", key, strings.Join(QueryKeys, ", "))
+}
+
+// Empty reports whether the query matches every ticket
+func (q *Query) Empty() bool {
+	return len(q.terms) == 0 && len(q.not) == 0 && len(q.words) == 0
+}
+
+// Matches reports whether the ticket passes every term of the query
+func (q *Query) Matches(t *board.Ticket, ctx QueryContext) bool {
+	for key, values := range q.terms {
+		if !matchesAny(key, values, t, ctx) {
+			return false
+		}
+	}
+	for key, values := range q.not {
+		for _, v := range values {
+			if matchesTerm(key, v, t, ctx) {
+				return false
+			}
+		}
+	}
+	if len(q.words) > 0 {
+		text := strings.ToLower(t.Title + "\n" + t.Description)
+		for _, w := range q.words {
+			if !strings.Contains(text, w) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchesAny(key string, values []string, t *board.Ticket, ctx QueryContext) bool {
+	for _, v := range values {
+		if matchesTerm(key, v, t, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesTerm(key, value string, t *board.Ticket, ctx QueryContext) bool {
+	switch key {
+	case "status":
+		return string(t.Status) == value
+	case "agent":
+		return strings.EqualFold(t.AgentType, value)
+	case "state":
+		return string(t.AgentStatus) == value
+	case "label":
+		for _, l := range t.Labels {
+			if strings.EqualFold(l, value) {
+				return true
+			}
+		}
+		return false
+	case "color":
+		if value == "none" {
+			return t.Color == ""
+		}
+		return t.Color == value
+	case "project":
+		return strings.Contains(strings.ToLower(ctx.ProjectName), value)
+	case "priority":
+		return strconv.Itoa(t.Priority) == value
+	case "running":
+		return ctx.Running == (value == "true")
+	case "blocked":
+		return (len(t.BlockedBy) > 0) == (value == "true")
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package project
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func TestQuery_Matches(t *testing.T) {
+	bug := board.NewTicket("Fix login redirect", "p1")
+	bug.Status = board.StatusInProgress
+	bug.AgentType = "claude"
+	bug.AgentStatus = board.AgentWorking
+	bug.Labels = []string{"bug", "needs review"}
+	bug.Priority = 1
+	bug.Color = "error"
+
+	docs := board.NewTicket("Write release notes", "p1")
+	docs.Description = "Cover the login changes"
+	docs.Labels = []string{"docs"}
+	docs.BlockedBy = []board.TicketID{bug.ID}
+
+	ctx := map[*board.Ticket]QueryContext{
+		bug:  {ProjectName: "Web App", Running: true},
+		docs: {ProjectName: "Handbook"},
+	}
+
+	tests := []struct {
+		expr string
+		want []*board.Ticket
+	}{
+		{"", []*board.Ticket{bug, docs}},
+		{"status:in_progress agent:claude label:bug", []*board.Ticket{bug}},
+		{"status:in-progress", []*board.Ticket{bug}},
+		{"status:backlog status:in_progress", []*board.Ticket{bug, docs}},
+		{"-label:bug", []*board.Ticket{docs}},
+		{`label:"needs review"`, []*board.Ticket{bug}},
+		{"login", []*board.Ticket{bug, docs}},
+		{"login notes", []*board.Ticket{docs}},
+		{"@web", []*board.Ticket{bug}},
+		{"project:hand login", []*board.Ticket{docs}},
+		{"running:true", []*board.Ticket{bug}},
+		{"blocked:true", []*board.Ticket{docs}},
+		{"priority:1 state:working", []*board.Ticket{bug}},
+		{"LABEL:BUG", []*board.Ticket{bug}},
+		{"color:error", []*board.Ticket{bug}},
+		{"color:none", []*board.Ticket{docs}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.expr)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) error = %v", tt.expr, err)
+			continue
+		}
+		var got []*board.Ticket
+		for _, ticket := range []*board.Ticket{bug, docs} {
+			if q.Matches(ticket, ctx[ticket]) {
+				got = append(got, ticket)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q matched %d tickets; want %d", tt.expr, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q matched %q; want %q", tt.expr, got[i].Title, tt.want[i].Title)
+			}
+		}
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"status:doing", "must be backlog"},
+		{"priority:9", "must be 1 to 5"},
+		{"running:yes", "must be true or false"},
+		{"colour:red", "unknown filter key"},
+		{"color:red", "must be none or a theme color"},
+		{"overdue:true", "no due dates"},
+		{"label:", "missing value"},
+		{`label:"needs review`, "unterminated quote"},
+		{"-login", "only key:value terms"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuery(%q) error = %v; want it to mention %q", tt.expr, err, tt.want)
+		}
+	}
+}
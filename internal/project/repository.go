@@ -0,0 +1,74 @@
+package project
+
+import (
+	"context"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/crypt"
+)
+
+// TicketRepository is the storage behind one project's tickets.
+// GlobalTicketStore keeps every project's tickets in one index and goes
+// through the repository for anything that touches storage, so backends
+// other than the JSON files can sit behind the same board.
+type TicketRepository interface {
+	// Get returns a ticket, or board.ErrTicketNotFound
+	Get(id board.TicketID) (*board.Ticket, error)
+
+	// Add stores a new ticket in the repository's project, numbering it
+	// when it has no number yet
+	Add(ticket *board.Ticket) error
+
+	// Update persists changes made to a ticket
+	Update(ticket *board.Ticket) error
+
+	// Delete removes a ticket, or returns board.ErrTicketNotFound
+	Delete(id board.TicketID) error
+
+	// Query returns the tickets match accepts, or all of them when match
+	// is nil
+	Query(match func(*board.Ticket) bool) ([]*board.Ticket, error)
+
+	// Watch signals on the returned channel when the tickets change
+	// outside this repository, for example in another openkanban, until
+	// ctx is done
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// RepositoryOpener opens a project's ticket repository, creating it if
+// the project has none yet
+type RepositoryOpener func(p *Project) (TicketRepository, error)
+
+// OpenJSONRepository opens the default backend, a JSON file per project
+// in the config directory
+func OpenJSONRepository(p *Project) (TicketRepository, error) {
+	store, err := LoadTicketStore(p)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// EncryptedJSONRepository opens the JSON backend with its files encrypted
+// by c, or decrypted where c seals nothing
+func EncryptedJSONRepository(c *crypt.Cipher) RepositoryOpener {
+	return func(p *Project) (TicketRepository, error) {
+		store, err := LoadEncryptedTicketStore(p, c)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+}
+
+// saver is a repository that holds changes in memory until saved
+type saver interface {
+	Save() error
+}
+
+// numberer is a repository that knows the number its next ticket gets
+type numberer interface {
+	NextNumber() int
+}
+
+var _ TicketRepository = (*TicketStore)(nil)
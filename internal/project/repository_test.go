@@ -0,0 +1,376 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/crypt"
+)
+
+// testTicketRepository is the behavior every backend shares
+func testTicketRepository(t *testing.T, open func(t *testing.T, p *Project) TicketRepository) {
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+
+	t.Run("add and get", func(t *testing.T) {
+		repo := open(t, p)
+		ticket := board.NewTicket("Add me", "")
+		if err := repo.Add(ticket); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		got, err := repo.Get(ticket.ID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Title != "Add me" || got.ProjectID != p.ID {
+			t.Errorf("Get = %q in %q; want %q in %q", got.Title, got.ProjectID, "Add me", p.ID)
+		}
+	})
+
+	t.Run("missing tickets", func(t *testing.T) {
+		repo := open(t, p)
+		if _, err := repo.Get("missing"); !errors.Is(err, board.ErrTicketNotFound) {
+			t.Errorf("Get(missing) error = %v; want ErrTicketNotFound", err)
+		}
+		if err := repo.Delete("missing"); !errors.Is(err, board.ErrTicketNotFound) {
+			t.Errorf("Delete(missing) error = %v; want ErrTicketNotFound", err)
+		}
+		if err := repo.Update(board.NewTicket("Never added", p.ID)); !errors.Is(err, board.ErrTicketNotFound) {
+			t.Errorf("Update(never added) error = %v; want ErrTicketNotFound", err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := open(t, p)
+		ticket := board.NewTicket("Before", p.ID)
+		repo.Add(ticket)
+		ticket.Title = "After"
+		if err := repo.Update(ticket); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if got, _ := repo.Get(ticket.ID); got.Title != "After" {
+			t.Errorf("title after Update = %q; want After", got.Title)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := open(t, p)
+		ticket := board.NewTicket("Delete me", p.ID)
+		repo.Add(ticket)
+		if err := repo.Delete(ticket.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.Get(ticket.ID); !errors.Is(err, board.ErrTicketNotFound) {
+			t.Errorf("Get after Delete error = %v; want ErrTicketNotFound", err)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		repo := open(t, p)
+		todo := board.NewTicket("Todo", p.ID)
+		doing := board.NewTicket("Doing", p.ID)
+		doing.Status = board.StatusInProgress
+		repo.Add(todo)
+		repo.Add(doing)
+
+		all, err := repo.Query(nil)
+		if err != nil {
+			t.Fatalf("Query(nil): %v", err)
+		}
+		if len(all) != 2 {
+			t.Errorf("Query(nil) returned %d tickets; want 2", len(all))
+		}
+		inProgress, _ := repo.Query(func(t *board.Ticket) bool { return t.Status == board.StatusInProgress })
+		if len(inProgress) != 1 || inProgress[0].ID != doing.ID {
+			t.Errorf("Query(in progress) returned %d tickets; want only %q", len(inProgress), doing.Title)
+		}
+	})
+
+	t.Run("watch stops with context", func(t *testing.T) {
+		repo := open(t, p)
+		ctx, cancel := context.WithCancel(context.Background())
+		changes, err := repo.Watch(ctx)
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+		cancel()
+		select {
+		case _, ok := <-changes:
+			for ok {
+				_, ok = <-changes
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Watch channel not closed after cancel")
+		}
+	})
+}
+
+func TestTicketStore_Repository(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	testTicketRepository(t, func(t *testing.T, p *Project) TicketRepository {
+		// Each case starts from an empty file
+		os.Remove(NewTicketStore(p.ID, p.RepoPath).filePath())
+		repo, err := OpenJSONRepository(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestTicketStore_UpdatePersists(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	store, _ := LoadTicketStore(p)
+	ticket := board.NewTicket("Persist me", p.ID)
+	store.Add(ticket)
+	if err := store.Update(ticket); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	reloaded, err := LoadTicketStore(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.Get(ticket.ID); err != nil {
+		t.Errorf("ticket not saved by Update: %v", err)
+	}
+}
+
+func TestTicketStore_Watch(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	defer func(d time.Duration) { watchInterval = d }(watchInterval)
+	watchInterval = 10 * time.Millisecond
+
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	store, _ := LoadTicketStore(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, _ := store.Watch(ctx)
+
+	// Our own saves are not changes
+	store.Add(board.NewTicket("Ours", p.ID))
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Fatal("Watch signalled for the store's own save")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Another process writing the file is
+	other, _ := LoadTicketStore(p)
+	other.Add(board.NewTicket("Theirs", p.ID))
+	time.Sleep(20 * time.Millisecond) // let the mod time move on coarse clocks
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not signal an outside change")
+	}
+}
+
+func testCipher(seal crypt.Kind) *crypt.Cipher {
+	return crypt.New(seal, func() ([]byte, error) { return []byte("passphrase"), nil }, nil)
+}
+
+func TestEncryptedJSONRepository(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	defer func(n int) { crypt.Iterations = n }(crypt.Iterations)
+	crypt.Iterations = 1000
+
+	testTicketRepository(t, func(t *testing.T, p *Project) TicketRepository {
+		os.Remove(NewTicketStore(p.ID, p.RepoPath).filePath())
+		repo, err := EncryptedJSONRepository(testCipher(crypt.KindPassphrase))(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestEncryptedTicketStore_SwitchesOnLoad(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	defer func(n int) { crypt.Iterations = n }(crypt.Iterations)
+	crypt.Iterations = 1000
+
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	plain, _ := LoadTicketStore(p)
+	ticket := board.NewTicket("Client: Acme", p.ID)
+	plain.Add(ticket)
+	if err := plain.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path := plain.filePath()
+
+	// Turning encryption on seals the existing file straight away
+	sealed, err := LoadEncryptedTicketStore(p, testCipher(crypt.KindPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealed.Get(ticket.ID); err != nil {
+		t.Fatalf("ticket lost sealing the file: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !crypt.IsSealed(data) || strings.Contains(string(data), "Acme") {
+		t.Fatalf("file not encrypted on load: %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("encrypted file mode = %v; want 0600", info.Mode().Perm())
+	}
+
+	// Without the passphrase the file can't be read
+	if _, err := LoadTicketStore(p); !errors.Is(err, crypt.ErrNoSecret) {
+		t.Errorf("plain load of an encrypted file: error = %v; want ErrNoSecret", err)
+	}
+
+	// Turning it off again decrypts, given the passphrase
+	if _, err := LoadEncryptedTicketStore(p, testCipher(crypt.KindNone)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if crypt.IsSealed(data) || !strings.Contains(string(data), "Acme") {
+		t.Errorf("file still encrypted after turning encryption off")
+	}
+}
+
+// memRepository keeps tickets in memory, standing in for a backend other
+// than the JSON files
+type memRepository struct {
+	projectID string
+	tickets   map[board.TicketID]*board.Ticket
+	updates   int
+}
+
+func (r *memRepository) Get(id board.TicketID) (*board.Ticket, error) {
+	if t, ok := r.tickets[id]; ok {
+		return t, nil
+	}
+	return nil, board.ErrTicketNotFound
+}
+
+func (r *memRepository) Add(t *board.Ticket) error {
+	t.ProjectID = r.projectID
+	r.tickets[t.ID] = t
+	return nil
+}
+
+func (r *memRepository) Update(t *board.Ticket) error {
+	if _, ok := r.tickets[t.ID]; !ok {
+		return board.ErrTicketNotFound
+	}
+	r.updates++
+	return nil
+}
+
+func (r *memRepository) Delete(id board.TicketID) error {
+	if _, ok := r.tickets[id]; !ok {
+		return board.ErrTicketNotFound
+	}
+	delete(r.tickets, id)
+	return nil
+}
+
+func (r *memRepository) Query(match func(*board.Ticket) bool) ([]*board.Ticket, error) {
+	var result []*board.Ticket
+	for _, t := range r.tickets {
+		if match == nil || match(t) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (r *memRepository) Watch(ctx context.Context) (<-chan struct{}, error) {
+	changes := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(changes)
+	}()
+	return changes, nil
+}
+
+func TestMemRepository(t *testing.T) {
+	testTicketRepository(t, func(t *testing.T, p *Project) TicketRepository {
+		return &memRepository{projectID: p.ID, tickets: map[board.TicketID]*board.Ticket{}}
+	})
+}
+
+func TestLoadGlobalTicketStoreWith(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	registry := newRegistry()
+	existing := &Project{ID: "project-1", Name: "Existing", RepoPath: t.TempDir()}
+	registry.Projects[existing.ID] = existing
+
+	repos := map[string]*memRepository{}
+	open := func(p *Project) (TicketRepository, error) {
+		repo := &memRepository{projectID: p.ID, tickets: map[board.TicketID]*board.Ticket{}}
+		if p.ID == existing.ID {
+			repo.Add(board.NewTicket("Loaded", p.ID))
+		}
+		repos[p.ID] = repo
+		return repo, nil
+	}
+
+	g, err := LoadGlobalTicketStoreWith(registry, open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Count() != 1 {
+		t.Fatalf("loaded %d tickets; want 1", g.Count())
+	}
+
+	added := &Project{ID: "project-2", Name: "Added", RepoPath: t.TempDir()}
+	if err := g.AddProject(added); err != nil {
+		t.Fatal(err)
+	}
+	ticket := board.NewTicket("New", added.ID)
+	if err := g.Add(ticket); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repos[added.ID].tickets[ticket.ID]; !ok {
+		t.Error("Add did not reach the added project's repository")
+	}
+	if err := g.Save(ticket); err != nil || repos[added.ID].updates != 1 {
+		t.Errorf("Save = %v with %d updates; want one update", err, repos[added.ID].updates)
+	}
+	if err := g.Delete(ticket.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(repos[added.ID].tickets) != 0 {
+		t.Error("Delete did not reach the repository")
+	}
+	if _, err := os.Stat(ticketsDir()); err == nil {
+		t.Error("JSON tickets directory created with another backend")
+	}
+}
+
+func TestSealedTicketFiles(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	defer func(n int) { crypt.Iterations = n }(crypt.Iterations)
+	crypt.Iterations = 1000
+
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	store, err := LoadEncryptedTicketStore(p, testCipher(crypt.KindPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(board.NewTicket("Sealed", p.ID))
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if files, err := SealedTicketFiles(crypt.KindPassphrase); err != nil || len(files) != 1 || files[0] != store.filePath() {
+		t.Errorf("SealedTicketFiles(passphrase) = %v, %v; want the project's file", files, err)
+	}
+	if files, err := SealedTicketFiles(crypt.KindKeychain); err != nil || len(files) != 0 {
+		t.Errorf("SealedTicketFiles(keychain) = %v, %v; want none", files, err)
+	}
+}
@@ -0,0 +1,149 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/secretenv"
+	"github.com/techdufus/openkanban/internal/telemetry"
+)
+
+// SetupTimeout bounds how long a single setup command may run
+const SetupTimeout = 10 * time.Minute
+
+// setupOutputTail is how much of a failed command's output is reported
+const setupOutputTail = 400
+
+// SetupProgress describes the setup command currently running
+type SetupProgress struct {
+	Step    int    // 1-based index of the command
+	Total   int    // number of setup commands
+	Command string // command being run
+	Line    string // last line of output, if any
+}
+
+// RunSetup runs the project's setup commands in a new worktree, stopping at
+// the first failure. Commands run through sh with the worktree as working
+// directory and OPENKANBAN_REPO_PATH, OPENKANBAN_WORKTREE, OPENKANBAN_BRANCH
+// and OPENKANBAN_TICKET_ID set, so a command like
+// cp "$OPENKANBAN_REPO_PATH/.env" . can copy files from the main checkout.
+// progress, if not nil, is called as each command starts and for each line of
+// output.
+func (p *Project) RunSetup(worktreePath, branch, ticketID string, progress func(SetupProgress)) (err error) {
+	if len(p.Settings.Setup) == 0 {
+		return nil
+	}
+	span := telemetry.Start("worktree.setup",
+		telemetry.String("openkanban.project", p.Name),
+		telemetry.String("openkanban.ticket_id", ticketID),
+		telemetry.Int("openkanban.setup_commands", len(p.Settings.Setup)))
+	defer func() { span.End(err) }()
+
+	env := p.worktreeEnv(worktreePath, branch, ticketID)
+
+	for i, command := range p.Settings.Setup {
+		state := SetupProgress{Step: i + 1, Total: len(p.Settings.Setup), Command: command}
+		if progress != nil {
+			progress(state)
+		}
+		step := span.Child("worktree.setup.command", telemetry.String("process.command_line", command))
+		err := runSetupCommand(command, worktreePath, env, func(line string) {
+			if progress != nil {
+				state.Line = line
+				progress(state)
+			}
+		})
+		step.End(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// worktreeEnv is the environment commands run with in a ticket's worktree
+func (p *Project) worktreeEnv(worktreePath, branch, ticketID string) []string {
+	return append(secretenv.Environ(),
+		"OPENKANBAN_REPO_PATH="+p.RepoPath,
+		"OPENKANBAN_WORKTREE="+worktreePath,
+		"OPENKANBAN_BRANCH="+branch,
+		"OPENKANBAN_TICKET_ID="+ticketID,
+	)
+}
+
+func runSetupCommand(command, dir string, env []string, onLine func(string)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), SetupTimeout)
+	defer cancel()
+
+	out := &lineWriter{onLine: onLine}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("setup %q timed out after %s", command, SetupTimeout)
+	}
+	if err != nil {
+		msg := out.tail()
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("setup %q failed: %s", command, msg)
+	}
+	return nil
+}
+
+// lineWriter collects command output and reports each complete line
+type lineWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	partial []byte
+	onLine  func(string)
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(b)
+	w.partial = append(w.partial, b...)
+	for {
+		i := bytes.IndexAny(w.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+		if line != "" && w.onLine != nil {
+			w.onLine(line)
+		}
+	}
+	return len(b), nil
+}
+
+// String returns all the output
+func (w *lineWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// tail returns the end of the output, trimmed to setupOutputTail bytes
+func (w *lineWriter) tail() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := strings.TrimSpace(w.buf.String())
+	if len(out) > setupOutputTail {
+		out = "…" + out[len(out)-setupOutputTail:]
+	}
+	return out
+}
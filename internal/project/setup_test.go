@@ -0,0 +1,61 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSetup(t *testing.T) {
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, ".env"), []byte("KEY=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProject("test", repo)
+	p.Settings.Setup = []string{
+		`cp "$OPENKANBAN_REPO_PATH/.env" .`,
+		`echo "installing $OPENKANBAN_BRANCH"; echo done`,
+	}
+
+	var steps []SetupProgress
+	if err := p.RunSetup(worktree, "task/x", "t-1", func(s SetupProgress) {
+		steps = append(steps, s)
+	}); err != nil {
+		t.Fatalf("RunSetup() error = %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(worktree, ".env")); err != nil || string(data) != "KEY=1\n" {
+		t.Errorf(".env in worktree = %q, %v", data, err)
+	}
+	want := []SetupProgress{
+		{Step: 1, Total: 2, Command: p.Settings.Setup[0]},
+		{Step: 2, Total: 2, Command: p.Settings.Setup[1]},
+		{Step: 2, Total: 2, Command: p.Settings.Setup[1], Line: "installing task/x"},
+		{Step: 2, Total: 2, Command: p.Settings.Setup[1], Line: "done"},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("progress = %+v; want %+v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("progress[%d] = %+v; want %+v", i, steps[i], want[i])
+		}
+	}
+}
+
+func TestRunSetupStopsAtFailure(t *testing.T) {
+	worktree := t.TempDir()
+	p := NewProject("test", t.TempDir())
+	p.Settings.Setup = []string{"echo broken >&2; exit 1", "touch ran"}
+
+	err := p.RunSetup(worktree, "task/x", "t-1", nil)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("RunSetup() error = %v; want the failing command's output", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "ran")); err == nil {
+		t.Error("commands after a failure should not run")
+	}
+}
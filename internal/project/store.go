@@ -5,7 +5,6 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
-	"sort"
 
 	"github.com/techdufus/openkanban/internal/config"
 )
@@ -131,8 +130,6 @@ func (r *ProjectRegistry) List() []*Project {
 	for _, p := range r.Projects {
 		result = append(result, p)
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
-	})
+	SortProjects(result, OrderName)
 	return result
 }
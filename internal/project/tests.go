@@ -0,0 +1,117 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/telemetry"
+)
+
+// TestTimeout bounds how long the test command may run
+const TestTimeout = 30 * time.Minute
+
+// ErrNoTestCommand is returned by RunTests for a project without a
+// test_command
+var ErrNoTestCommand = errors.New("no test_command set for this project")
+
+// TestResult is the outcome of a run of the project's test command
+type TestResult struct {
+	Passed   bool   // the command exited 0
+	Pass     int    // passing tests, when the output tells; see ParseTestOutput
+	Fail     int    // failing tests, likewise
+	Output   string // end of the output
+	Duration time.Duration
+}
+
+var (
+	goTestLine    = regexp.MustCompile(`^--- (PASS|FAIL): `)
+	goPackageLine = regexp.MustCompile(`^(ok|FAIL)\s+\S+\s`)
+	jestSummary   = regexp.MustCompile(`^Tests:\s+(.*)$`)
+	jestCount     = regexp.MustCompile(`(\d+) (passed|failed)`)
+)
+
+// RunTests runs the project's test command through sh in a ticket's
+// worktree, with the same environment as setup commands. A failing command
+// is a result rather than an error; errors are for a command that could not
+// run or timed out.
+func (p *Project) RunTests(worktreePath, branch, ticketID string) (res TestResult, err error) {
+	if p.Settings.TestCommand == "" {
+		return TestResult{}, ErrNoTestCommand
+	}
+	span := telemetry.Start("worktree.test",
+		telemetry.String("openkanban.project", p.Name),
+		telemetry.String("openkanban.ticket_id", ticketID),
+		telemetry.String("process.command_line", p.Settings.TestCommand))
+	defer func() { span.End(err) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TestTimeout)
+	defer cancel()
+
+	out := &lineWriter{}
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Settings.TestCommand)
+	cmd.Dir = worktreePath
+	cmd.Env = p.worktreeEnv(worktreePath, branch, ticketID)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	start := time.Now()
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return TestResult{}, fmt.Errorf("tests timed out after %s", TestTimeout)
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return TestResult{}, fmt.Errorf("tests could not run: %w", runErr)
+	}
+
+	res = TestResult{Passed: runErr == nil, Output: out.tail(), Duration: time.Since(start)}
+	res.Pass, res.Fail = ParseTestOutput(out.String())
+	return res, nil
+}
+
+// ParseTestOutput counts passing and failing tests from a test run's output:
+// jest's "Tests:" summary, go test -v's top-level "--- PASS" and "--- FAIL"
+// lines, or else go test's "ok" and "FAIL" lines, one per package. Both are
+// 0 for output it doesn't recognise.
+func ParseTestOutput(out string) (pass, fail int) {
+	var pkgPass, pkgFail int
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := jestSummary.FindStringSubmatch(line); m != nil {
+			pass, fail = 0, 0
+			for _, c := range jestCount.FindAllStringSubmatch(m[1], -1) {
+				n, _ := strconv.Atoi(c[1])
+				if c[2] == "passed" {
+					pass = n
+				} else {
+					fail = n
+				}
+			}
+			return pass, fail
+		}
+		switch {
+		case goTestLine.MatchString(line):
+			if strings.HasPrefix(line, "--- PASS") {
+				pass++
+			} else {
+				fail++
+			}
+		case goPackageLine.MatchString(line):
+			if strings.HasPrefix(line, "ok") {
+				pkgPass++
+			} else {
+				pkgFail++
+			}
+		}
+	}
+	if pass+fail > 0 {
+		return pass, fail
+	}
+	return pkgPass, pkgFail
+}
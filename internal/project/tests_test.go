@@ -0,0 +1,47 @@
+package project
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTestOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		out        string
+		pass, fail int
+	}{
+		{"go test packages", "ok  \texample.com/a\t0.1s\n?   \texample.com/b\t[no test files]\nFAIL\texample.com/c\t0.2s\nFAIL\n", 1, 1},
+		{"go test -v", "=== RUN   TestA\n--- PASS: TestA (0.00s)\n=== RUN   TestB\n    --- FAIL: TestB/sub (0.00s)\n--- FAIL: TestB (0.00s)\n--- PASS: TestC (0.00s)\nFAIL\texample.com/a\t0.1s\n", 2, 1},
+		{"jest", "PASS src/a.test.js\nFAIL src/b.test.js\nTest Suites: 1 failed, 1 passed, 2 total\nTests:       2 failed, 1 skipped, 7 passed, 10 total\n", 7, 2},
+		{"unknown", "all good\n", 0, 0},
+	}
+	for _, tt := range tests {
+		pass, fail := ParseTestOutput(tt.out)
+		if pass != tt.pass || fail != tt.fail {
+			t.Errorf("%s: ParseTestOutput() = %d, %d; want %d, %d", tt.name, pass, fail, tt.pass, tt.fail)
+		}
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	p := NewProject("test", t.TempDir())
+	if _, err := p.RunTests(t.TempDir(), "task/x", "t-1"); !errors.Is(err, ErrNoTestCommand) {
+		t.Fatalf("RunTests() without a command error = %v; want ErrNoTestCommand", err)
+	}
+
+	p.Settings.TestCommand = `printf -- '--- PASS: TestA\n--- FAIL: TestB on %s\n' "$OPENKANBAN_BRANCH"; exit 1`
+	res, err := p.RunTests(t.TempDir(), "task/x", "t-1")
+	if err != nil {
+		t.Fatalf("RunTests() error = %v", err)
+	}
+	if res.Passed || res.Pass != 1 || res.Fail != 1 || !strings.Contains(res.Output, "TestB on task/x") {
+		t.Errorf("RunTests() = %+v; want a failure with 1 passing and 1 failing test", res)
+	}
+
+	p.Settings.TestCommand = "true"
+	if res, err := p.RunTests(t.TempDir(), "task/x", "t-1"); err != nil || !res.Passed {
+		t.Errorf("RunTests() = %+v, %v; want a pass", res, err)
+	}
+}
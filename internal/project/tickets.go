@@ -1,16 +1,20 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
-	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
 	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/crypt"
 )
 
 // ticketsDir returns the directory for ticket storage.
@@ -24,12 +28,29 @@ func ticketsDir() string {
 }
 const ticketsFile = "tickets.json"
 
+// ErrAmbiguousTicket is returned by Find when a query matches more than one ticket.
+var ErrAmbiguousTicket = errors.New("ticket reference is ambiguous")
+
+// minIDPrefix is the shortest ticket ID prefix accepted by Find.
+const minIDPrefix = 4
+
+// watchInterval is how often Watch checks the tickets file for changes.
+var watchInterval = 2 * time.Second
+
 type TicketStore struct {
 	ProjectID string                           `json:"project_id"`
 	Tickets   map[board.TicketID]*board.Ticket `json:"tickets"`
 	UpdatedAt time.Time                        `json:"updated_at"`
 
+	// LastNumber is the highest ticket number handed out, so numbers of
+	// deleted tickets are never reused
+	LastNumber int `json:"last_number,omitempty"`
+
 	repoPath string
+	cipher   *crypt.Cipher // nil reads and writes plain JSON only
+
+	fileMu  sync.Mutex
+	written time.Time // mod time of our last save, to tell it from others'
 }
 
 func NewTicketStore(projectID, repoPath string) *TicketStore {
@@ -42,7 +63,15 @@ func NewTicketStore(projectID, repoPath string) *TicketStore {
 }
 
 func LoadTicketStore(project *Project) (*TicketStore, error) {
+	return LoadEncryptedTicketStore(project, nil)
+}
+
+// LoadEncryptedTicketStore loads a project's tickets, decrypting them with
+// c. A file sealed differently from how c seals, including a plain file
+// when encryption has just been turned on, is rewritten straight away.
+func LoadEncryptedTicketStore(project *Project, c *crypt.Cipher) (*TicketStore, error) {
 	store := NewTicketStore(project.ID, project.RepoPath)
+	store.cipher = c
 
 	// Check for migration from old location
 	oldPath := filepath.Join(project.RepoPath, ".openkanban", "tickets.json")
@@ -60,7 +89,7 @@ func LoadTicketStore(project *Project) (*TicketStore, error) {
 			data, readErr := os.ReadFile(oldPath)
 			if readErr == nil {
 				if writeErr := os.WriteFile(newPath, data, 0644); writeErr == nil {
-					log.Printf("Migrated tickets from %s to %s. You can safely delete the old .openkanban directory.", oldPath, newPath)
+					slog.Info("migrated tickets; the old .openkanban directory can be deleted", "from", oldPath, "to", newPath)
 				}
 			}
 		}
@@ -75,6 +104,10 @@ func LoadTicketStore(project *Project) (*TicketStore, error) {
 		return nil, err
 	}
 
+	sealed := crypt.IsSealed(data)
+	if data, err = c.Open(data); err != nil {
+		return nil, fmt.Errorf("failed to decrypt tickets for %s: %w", project.Name, err)
+	}
 	if err := json.Unmarshal(data, store); err != nil {
 		return nil, err
 	}
@@ -84,9 +117,38 @@ func LoadTicketStore(project *Project) (*TicketStore, error) {
 	}
 	store.repoPath = project.RepoPath
 
+	if store.numberTickets() {
+		if err := store.Save(); err != nil {
+			return nil, err
+		}
+	} else if sealed != c.Seals() {
+		if err := store.Save(); err != nil {
+			return nil, err
+		}
+		slog.Info("rewrote tickets for the encryption setting", "project", project.Name, "encrypted", c.Seals())
+	}
 	return store, nil
 }
 
+// SealedTicketFiles returns the paths of the ticket files sealed with kind
+func SealedTicketFiles(kind crypt.Kind) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(ticketsDir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var sealed []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if crypt.SealedWith(data) == kind {
+			sealed = append(sealed, path)
+		}
+	}
+	return sealed, nil
+}
+
 func (s *TicketStore) filePath() string {
 	return filepath.Join(ticketsDir(), s.ProjectID+".json")
 }
@@ -103,18 +165,127 @@ func (s *TicketStore) Save() error {
 	if err != nil {
 		return err
 	}
+	perm := os.FileMode(0644)
+	if s.cipher.Seals() {
+		if data, err = s.cipher.Seal(data); err != nil {
+			return fmt.Errorf("failed to encrypt tickets: %w", err)
+		}
+		perm = 0600
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
 
 	path := s.filePath()
 	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+	if err := os.WriteFile(tmpPath, data, perm); err != nil {
 		return err
 	}
-	return os.Rename(tmpPath, path)
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		s.written = info.ModTime()
+	}
+	return nil
 }
 
-func (s *TicketStore) Add(ticket *board.Ticket) {
+// Add stores a ticket, giving it the next number unless it has one
+func (s *TicketStore) Add(ticket *board.Ticket) error {
 	ticket.ProjectID = s.ProjectID
+	if ticket.Number <= 0 {
+		ticket.Number = s.NextNumber()
+	}
+	s.LastNumber = max(s.LastNumber, ticket.Number)
 	s.Tickets[ticket.ID] = ticket
+	return nil
+}
+
+// NextNumber returns the number the next ticket added will get
+func (s *TicketStore) NextNumber() int {
+	return s.LastNumber + 1
+}
+
+// numberTickets numbers tickets saved before tickets had numbers, oldest
+// first, and reports whether any needed one
+func (s *TicketStore) numberTickets() bool {
+	for _, t := range s.Tickets {
+		s.LastNumber = max(s.LastNumber, t.Number)
+	}
+	changed := false
+	for _, t := range s.All() {
+		if t.Number <= 0 {
+			s.LastNumber++
+			t.Number = s.LastNumber
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Update saves the store; the file holds every ticket of the project
+func (s *TicketStore) Update(ticket *board.Ticket) error {
+	if _, ok := s.Tickets[ticket.ID]; !ok {
+		return board.ErrTicketNotFound
+	}
+	return s.Save()
+}
+
+func (s *TicketStore) Query(match func(*board.Ticket) bool) ([]*board.Ticket, error) {
+	var result []*board.Ticket
+	for _, t := range s.Tickets {
+		if match == nil || match(t) {
+			result = append(result, t)
+		}
+	}
+	SortTickets(result, OrderCreated)
+	return result, nil
+}
+
+// Watch polls the tickets file and signals when it was written by anyone
+// but this store
+func (s *TicketStore) Watch(ctx context.Context) (<-chan struct{}, error) {
+	path := s.filePath()
+	modTime := func() time.Time {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	s.fileMu.Lock()
+	seen := modTime()
+	s.fileMu.Unlock()
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.fileMu.Lock()
+			current, written := modTime(), s.written
+			s.fileMu.Unlock()
+			if current.Equal(seen) {
+				continue
+			}
+			seen = current
+			if current.Equal(written) {
+				continue
+			}
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes, nil
 }
 
 func (s *TicketStore) Get(id board.TicketID) (*board.Ticket, error) {
@@ -142,6 +313,7 @@ func (s *TicketStore) Move(id board.TicketID, newStatus board.TicketStatus) erro
 	return nil
 }
 
+// GetByStatus returns the tickets with a status, oldest first
 func (s *TicketStore) GetByStatus(status board.TicketStatus) []*board.Ticket {
 	var result []*board.Ticket
 	for _, t := range s.Tickets {
@@ -149,14 +321,17 @@ func (s *TicketStore) GetByStatus(status board.TicketStatus) []*board.Ticket {
 			result = append(result, t)
 		}
 	}
+	SortTickets(result, OrderCreated)
 	return result
 }
 
+// All returns every ticket, oldest first
 func (s *TicketStore) All() []*board.Ticket {
 	result := make([]*board.Ticket, 0, len(s.Tickets))
 	for _, t := range s.Tickets {
 		result = append(result, t)
 	}
+	SortTickets(result, OrderCreated)
 	return result
 }
 
@@ -176,35 +351,79 @@ func (s *TicketStore) CountByStatus(status board.TicketStatus) int {
 
 // GlobalTicketStore aggregates tickets from all projects
 type GlobalTicketStore struct {
-	registry     *ProjectRegistry
-	projects     map[string]*Project
-	ticketStores map[string]*TicketStore
-	allTickets   map[board.TicketID]*board.Ticket
+	registry   *ProjectRegistry
+	projects   map[string]*Project
+	repos      map[string]TicketRepository
+	allTickets map[board.TicketID]*board.Ticket
+	open       RepositoryOpener // nil for the JSON files
+
+	ticketOrder  Order
+	projectOrder Order
+
+	// dirty holds, per project, the tickets changed since the last save;
+	// a project with an empty set had a ticket added or removed
+	mu      sync.Mutex
+	dirty   map[string]map[board.TicketID]bool
+	savedAt time.Time
 }
 
 func NewGlobalTicketStore(registry *ProjectRegistry) *GlobalTicketStore {
 	return &GlobalTicketStore{
-		registry:     registry,
-		projects:     make(map[string]*Project),
-		ticketStores: make(map[string]*TicketStore),
-		allTickets:   make(map[board.TicketID]*board.Ticket),
+		registry:   registry,
+		projects:   make(map[string]*Project),
+		repos:      make(map[string]TicketRepository),
+		allTickets: make(map[board.TicketID]*board.Ticket),
+		dirty:      make(map[string]map[board.TicketID]bool),
+
+		ticketOrder:  OrderCreated,
+		projectOrder: OrderName,
+	}
+}
+
+// SetOrder chooses how GetByStatus and All list tickets and Projects lists
+// projects. Empty orders keep the current ones.
+func (g *GlobalTicketStore) SetOrder(tickets, projects Order) {
+	if tickets != "" {
+		g.ticketOrder = tickets
+	}
+	if projects != "" {
+		g.projectOrder = projects
 	}
 }
 
+// TicketOrder is how GetByStatus and All list tickets
+func (g *GlobalTicketStore) TicketOrder() Order {
+	return g.ticketOrder
+}
+
 func LoadGlobalTicketStore(registry *ProjectRegistry) (*GlobalTicketStore, error) {
+	return LoadGlobalTicketStoreWith(registry, nil)
+}
+
+// LoadGlobalTicketStoreWith loads every project's tickets through open,
+// or from the JSON files when open is nil
+func LoadGlobalTicketStoreWith(registry *ProjectRegistry, open RepositoryOpener) (*GlobalTicketStore, error) {
 	g := NewGlobalTicketStore(registry)
+	g.open = open
+	if open == nil {
+		open = OpenJSONRepository
+	}
 
 	for _, p := range registry.Projects {
-		store, err := LoadTicketStore(p)
+		repo, err := open(p)
+		if err != nil {
+			continue
+		}
+		tickets, err := repo.Query(nil)
 		if err != nil {
 			continue
 		}
 
 		g.projects[p.ID] = p
-		g.ticketStores[p.ID] = store
+		g.repos[p.ID] = repo
 
-		for id, ticket := range store.Tickets {
-			g.allTickets[id] = ticket
+		for _, ticket := range tickets {
+			g.allTickets[ticket.ID] = ticket
 		}
 	}
 
@@ -219,8 +438,9 @@ func (g *GlobalTicketStore) GetProjectForTicket(ticket *board.Ticket) *Project {
 	return g.projects[ticket.ProjectID]
 }
 
-func (g *GlobalTicketStore) GetStoreForTicket(ticket *board.Ticket) *TicketStore {
-	return g.ticketStores[ticket.ProjectID]
+// Repository returns the repository holding a project's tickets
+func (g *GlobalTicketStore) Repository(projectID string) TicketRepository {
+	return g.repos[projectID]
 }
 
 func (g *GlobalTicketStore) Get(id board.TicketID) (*board.Ticket, error) {
@@ -231,13 +451,102 @@ func (g *GlobalTicketStore) Get(id board.TicketID) (*board.Ticket, error) {
 	return t, nil
 }
 
+// TicketKey returns the ticket's short key, e.g. "OKB-142", or "" when its
+// project is unknown or it has no number
+func (g *GlobalTicketStore) TicketKey(t *board.Ticket) string {
+	p := g.projects[t.ProjectID]
+	if p == nil {
+		return ""
+	}
+	return p.TicketKey(t)
+}
+
+// NextKey returns the key the next ticket added to a project will get
+func (g *GlobalTicketStore) NextKey(projectID string) string {
+	p := g.projects[projectID]
+	if p == nil {
+		return ""
+	}
+	next := 1
+	if n, ok := g.repos[projectID].(numberer); ok {
+		next = n.NextNumber()
+	} else {
+		for _, t := range g.allTickets {
+			if t.ProjectID == projectID {
+				next = max(next, t.Number+1)
+			}
+		}
+	}
+	return board.FormatKey(p.GetKeyPrefix(), next)
+}
+
+// Find resolves a user-supplied ticket reference. The query may be a full
+// ticket ID, a key such as OKB-142, an ID prefix of at least four
+// characters, a branch name, the slugified title, or the exact title
+// (case-insensitive).
+func (g *GlobalTicketStore) Find(query string) (*board.Ticket, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, board.ErrTicketNotFound
+	}
+
+	if t, ok := g.allTickets[board.TicketID(query)]; ok {
+		return t, nil
+	}
+
+	var matches []*board.Ticket
+	lower := strings.ToLower(query)
+	for _, t := range g.allTickets {
+		switch {
+		case t.Number > 0 && strings.EqualFold(g.TicketKey(t), query):
+		case len(query) >= minIDPrefix && strings.HasPrefix(string(t.ID), lower):
+		case t.BranchName != "" && t.BranchName == query:
+		case board.Slugify(t.Title, len(t.Title)) == lower:
+		case strings.EqualFold(t.Title, query):
+		default:
+			continue
+		}
+		matches = append(matches, t)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, board.ErrTicketNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %q matches %d tickets", ErrAmbiguousTicket, query, len(matches))
+	}
+}
+
+// FindByLabel returns all non-archived tickets carrying the given label.
+func (g *GlobalTicketStore) FindByLabel(label string) []*board.Ticket {
+	var result []*board.Ticket
+	for _, t := range g.allTickets {
+		if t.Status == board.StatusArchived {
+			continue
+		}
+		for _, l := range t.Labels {
+			if strings.EqualFold(l, label) {
+				result = append(result, t)
+				break
+			}
+		}
+	}
+	SortTickets(result, OrderCreated)
+	return result
+}
+
 func (g *GlobalTicketStore) Add(ticket *board.Ticket) error {
-	store := g.ticketStores[ticket.ProjectID]
-	if store == nil {
+	repo := g.repos[ticket.ProjectID]
+	if repo == nil {
 		return board.ErrTicketNotFound
 	}
-	store.Add(ticket)
+	if err := repo.Add(ticket); err != nil {
+		return err
+	}
 	g.allTickets[ticket.ID] = ticket
+	g.markDirty(ticket.ProjectID, "")
 	return nil
 }
 
@@ -247,44 +556,150 @@ func (g *GlobalTicketStore) Delete(id board.TicketID) error {
 		return board.ErrTicketNotFound
 	}
 
-	store := g.ticketStores[ticket.ProjectID]
-	if store != nil {
-		store.Delete(id)
+	if repo := g.repos[ticket.ProjectID]; repo != nil {
+		if err := repo.Delete(id); err != nil && !errors.Is(err, board.ErrTicketNotFound) {
+			return err
+		}
 	}
 	delete(g.allTickets, id)
+	g.markDirty(ticket.ProjectID, "")
 	return nil
 }
 
+// Move changes a ticket's status; like any other change, Save persists it
 func (g *GlobalTicketStore) Move(id board.TicketID, newStatus board.TicketStatus) error {
 	ticket, ok := g.allTickets[id]
 	if !ok {
 		return board.ErrTicketNotFound
 	}
-
-	store := g.ticketStores[ticket.ProjectID]
-	if store != nil {
-		store.Move(id, newStatus)
-	}
+	ticket.SetStatus(newStatus)
+	g.markDirty(ticket.ProjectID, id)
 	return nil
 }
 
 func (g *GlobalTicketStore) Save(ticket *board.Ticket) error {
-	store := g.ticketStores[ticket.ProjectID]
-	if store == nil {
+	repo := g.repos[ticket.ProjectID]
+	if repo == nil {
 		return board.ErrTicketNotFound
 	}
-	return store.Save()
+	if err := repo.Update(ticket); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := repo.(saver); ok {
+		delete(g.dirty, ticket.ProjectID)
+	} else if changed := g.dirty[ticket.ProjectID]; changed != nil {
+		delete(changed, ticket.ID)
+		if len(changed) == 0 {
+			delete(g.dirty, ticket.ProjectID)
+		}
+	}
+	g.savedAt = time.Now()
+	return nil
 }
 
+// SaveAll saves the repositories that hold changes in memory, and any
+// changed tickets the others have not been given yet
 func (g *GlobalTicketStore) SaveAll() error {
-	for _, store := range g.ticketStores {
-		if err := store.Save(); err != nil {
-			return err
+	for id, repo := range g.repos {
+		if _, ok := repo.(saver); !ok {
+			continue
+		}
+		g.mu.Lock()
+		g.dirty[id] = map[board.TicketID]bool{}
+		g.mu.Unlock()
+	}
+	return g.SaveDirty()
+}
+
+// MarkDirty records a change to ticket for SaveDirty to persist
+func (g *GlobalTicketStore) MarkDirty(ticket *board.Ticket) {
+	g.markDirty(ticket.ProjectID, ticket.ID)
+}
+
+func (g *GlobalTicketStore) markDirty(projectID string, id board.TicketID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.markDirtyLocked(projectID, id)
+}
+
+func (g *GlobalTicketStore) markDirtyLocked(projectID string, id board.TicketID) {
+	changed := g.dirty[projectID]
+	if changed == nil {
+		changed = make(map[board.TicketID]bool)
+		g.dirty[projectID] = changed
+	}
+	if id != "" {
+		changed[id] = true
+	}
+}
+
+// Dirty reports whether there are changes SaveDirty has yet to persist
+func (g *GlobalTicketStore) Dirty() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.dirty) > 0
+}
+
+// SavedAt is when tickets were last persisted, zero if never
+func (g *GlobalTicketStore) SavedAt() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.savedAt
+}
+
+// SaveDirty persists the changes recorded since the last save: a whole
+// save for repositories that hold changes in memory, an Update per
+// changed ticket for the others. Projects that fail stay dirty.
+func (g *GlobalTicketStore) SaveDirty() error {
+	g.mu.Lock()
+	dirty := g.dirty
+	g.dirty = make(map[string]map[board.TicketID]bool)
+	g.mu.Unlock()
+
+	var errs []error
+	for projectID, changed := range dirty {
+		if err := g.saveProject(projectID, changed); err != nil {
+			errs = append(errs, err)
+			g.mu.Lock()
+			for id := range changed {
+				g.markDirtyLocked(projectID, id)
+			}
+			g.markDirtyLocked(projectID, "")
+			g.mu.Unlock()
 		}
 	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if len(dirty) > 0 {
+		g.mu.Lock()
+		g.savedAt = time.Now()
+		g.mu.Unlock()
+	}
 	return nil
 }
 
+func (g *GlobalTicketStore) saveProject(projectID string, changed map[board.TicketID]bool) error {
+	repo := g.repos[projectID]
+	if repo == nil {
+		return nil
+	}
+	if s, ok := repo.(saver); ok {
+		return s.Save()
+	}
+	for id := range changed {
+		if ticket, ok := g.allTickets[id]; ok {
+			if err := repo.Update(ticket); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetByStatus returns the tickets with a status in the store's order
 func (g *GlobalTicketStore) GetByStatus(status board.TicketStatus) []*board.Ticket {
 	var result []*board.Ticket
 	for _, t := range g.allTickets {
@@ -292,14 +707,17 @@ func (g *GlobalTicketStore) GetByStatus(status board.TicketStatus) []*board.Tick
 			result = append(result, t)
 		}
 	}
+	SortTickets(result, g.ticketOrder)
 	return result
 }
 
+// All returns every ticket in the store's order
 func (g *GlobalTicketStore) All() []*board.Ticket {
 	result := make([]*board.Ticket, 0, len(g.allTickets))
 	for _, t := range g.allTickets {
 		result = append(result, t)
 	}
+	SortTickets(result, g.ticketOrder)
 	return result
 }
 
@@ -312,9 +730,7 @@ func (g *GlobalTicketStore) Projects() []*Project {
 	for _, p := range g.projects {
 		result = append(result, p)
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
-	})
+	SortProjects(result, g.projectOrder)
 	return result
 }
 
@@ -322,9 +738,18 @@ func (g *GlobalTicketStore) HasProjects() bool {
 	return len(g.projects) > 0
 }
 
-func (g *GlobalTicketStore) AddProject(p *Project) {
+// AddProject starts an empty repository for a new project
+func (g *GlobalTicketStore) AddProject(p *Project) error {
+	var repo TicketRepository = NewTicketStore(p.ID, p.RepoPath)
+	if g.open != nil {
+		var err error
+		if repo, err = g.open(p); err != nil {
+			return err
+		}
+	}
 	g.projects[p.ID] = p
-	g.ticketStores[p.ID] = NewTicketStore(p.ID, p.RepoPath)
+	g.repos[p.ID] = repo
+	return nil
 }
 
 func (g *GlobalTicketStore) RemoveProject(id string) error {
@@ -349,11 +774,11 @@ func (g *GlobalTicketStore) RemoveProject(id string) error {
 		if err := os.Rename(srcPath, dstPath); err != nil {
 			return err
 		}
-		log.Printf("Archived tickets to %s", dstPath)
+		slog.Info("archived tickets", "project", id, "path", dstPath)
 	}
 
 	delete(g.projects, id)
-	delete(g.ticketStores, id)
+	delete(g.repos, id)
 
 	return g.registry.Delete(id)
 }
@@ -383,6 +808,7 @@ func (g *GlobalTicketStore) GetBlocks(ticketID board.TicketID) []*board.Ticket {
 			}
 		}
 	}
+	SortTickets(blocks, OrderCreated)
 	return blocks
 }
 
@@ -399,6 +825,7 @@ func (g *GlobalTicketStore) RemoveBlockerReferences(ticketID board.TicketID) {
 		}
 		if len(filtered) != len(ticket.BlockedBy) {
 			ticket.BlockedBy = filtered
+			g.markDirty(ticket.ProjectID, ticket.ID)
 		}
 	}
 }
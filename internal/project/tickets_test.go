@@ -1,9 +1,12 @@
 package project
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/techdufus/openkanban/internal/board"
 )
@@ -226,6 +229,44 @@ func TestTicketStore_SaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestTicketStore_Numbers(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+
+	store := NewTicketStore("project-1", "/path")
+	first, second := board.NewTicket("First", ""), board.NewTicket("Second", "")
+	store.Add(first)
+	store.Add(second)
+	if first.Number != 1 || second.Number != 2 {
+		t.Fatalf("numbers = %d, %d; want 1, 2", first.Number, second.Number)
+	}
+
+	// A deleted ticket's number is not handed out again
+	store.Delete(second.ID)
+	third := board.NewTicket("Third", "")
+	store.Add(third)
+	if third.Number != 3 {
+		t.Errorf("number after a delete = %d; want 3", third.Number)
+	}
+
+	// Tickets saved before numbering get numbers on load, oldest first
+	old := board.NewTicket("Old", "")
+	old.CreatedAt = first.CreatedAt.Add(-time.Hour)
+	store.Tickets[old.ID] = old
+	older := board.NewTicket("Older", "")
+	older.CreatedAt = old.CreatedAt.Add(-time.Hour)
+	store.Tickets[older.ID] = older
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTicketStore(&Project{ID: "project-1", RepoPath: "/path"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := []int{loaded.Tickets[older.ID].Number, loaded.Tickets[old.ID].Number, loaded.NextNumber()}; !slices.Equal(got, []int{4, 5, 6}) {
+		t.Errorf("backfilled numbers and next = %v; want [4 5 6]", got)
+	}
+}
+
 func TestLoadTicketStore_NonexistentFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	project := &Project{ID: "project-1", RepoPath: tmpDir}
@@ -347,3 +388,135 @@ func TestGlobalTicketStore_RemoveProjectArchive(t *testing.T) {
 		t.Error("original ticket file should not exist after archiving")
 	}
 }
+
+func TestGlobalTicketStore_Find(t *testing.T) {
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: "/path"}
+	globalStore := NewGlobalTicketStore(newRegistry())
+	globalStore.AddProject(p)
+
+	login := board.NewTicket("Fix login bug", p.ID)
+	login.ID = "abcd1234-0000"
+	login.BranchName = "task/fix-login"
+	docs := board.NewTicket("Write docs", p.ID)
+	docs.ID = "abcd5678-0000"
+	globalStore.Add(login)
+	globalStore.Add(docs)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    board.TicketID
+		wantErr error
+	}{
+		{name: "full ID", query: "abcd1234-0000", want: login.ID},
+		{name: "key", query: "tes-2", want: docs.ID},
+		{name: "unique ID prefix", query: "abcd12", want: login.ID},
+		{name: "ambiguous ID prefix", query: "abcd", wantErr: ErrAmbiguousTicket},
+		{name: "short prefix ignored", query: "abc", wantErr: board.ErrTicketNotFound},
+		{name: "branch name", query: "task/fix-login", want: login.ID},
+		{name: "title slug", query: "write-docs", want: docs.ID},
+		{name: "title case-insensitive", query: "FIX LOGIN BUG", want: login.ID},
+		{name: "no match", query: "nothing", wantErr: board.ErrTicketNotFound},
+		{name: "empty", query: "  ", wantErr: board.ErrTicketNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := globalStore.Find(tt.query)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Find(%q) error = %v; want %v", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Find(%q) error = %v", tt.query, err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("Find(%q) = %s; want %s", tt.query, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobalTicketStore_FindByLabel(t *testing.T) {
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: "/path"}
+	globalStore := NewGlobalTicketStore(newRegistry())
+	globalStore.AddProject(p)
+
+	auto := board.NewTicket("Auto", p.ID)
+	auto.Labels = []string{"Auto", "backend"}
+	archived := board.NewTicket("Archived auto", p.ID)
+	archived.Labels = []string{"auto"}
+	archived.Status = board.StatusArchived
+	other := board.NewTicket("Other", p.ID)
+	other.Labels = []string{"frontend"}
+
+	globalStore.Add(auto)
+	globalStore.Add(archived)
+	globalStore.Add(other)
+
+	got := globalStore.FindByLabel("auto")
+	if len(got) != 1 || got[0].ID != auto.ID {
+		t.Errorf("FindByLabel(auto) returned %d tickets; want only %q", len(got), auto.Title)
+	}
+}
+
+func TestGlobalTicketStore_SaveDirty(t *testing.T) {
+	t.Setenv("OPENKANBAN_CONFIG_DIR", t.TempDir())
+	p := &Project{ID: "project-1", Name: "Test", RepoPath: t.TempDir()}
+	globalStore := NewGlobalTicketStore(newRegistry())
+	globalStore.AddProject(p)
+
+	if globalStore.Dirty() || !globalStore.SavedAt().IsZero() {
+		t.Fatal("new store should be clean and never saved")
+	}
+
+	ticket := board.NewTicket("Autosaved", p.ID)
+	globalStore.Add(ticket)
+	if !globalStore.Dirty() {
+		t.Fatal("Add should leave the store dirty")
+	}
+	if err := globalStore.SaveDirty(); err != nil {
+		t.Fatalf("SaveDirty: %v", err)
+	}
+	if globalStore.Dirty() || globalStore.SavedAt().IsZero() {
+		t.Error("SaveDirty should leave the store clean with a save time")
+	}
+	loaded, _ := LoadTicketStore(p)
+	if _, err := loaded.Get(ticket.ID); err != nil {
+		t.Errorf("ticket not written by SaveDirty: %v", err)
+	}
+
+	ticket.Title = "Edited"
+	globalStore.MarkDirty(ticket)
+	if !globalStore.Dirty() {
+		t.Fatal("MarkDirty should leave the store dirty")
+	}
+	if err := globalStore.Save(ticket); err != nil {
+		t.Fatal(err)
+	}
+	if globalStore.Dirty() {
+		t.Error("Save should clear the project's pending changes")
+	}
+}
+
+func TestGlobalTicketStore_SaveDirtyUpdatesChangedTickets(t *testing.T) {
+	repo := &memRepository{projectID: "project-1", tickets: map[board.TicketID]*board.Ticket{}}
+	open := func(p *Project) (TicketRepository, error) { return repo, nil }
+	globalStore, _ := LoadGlobalTicketStoreWith(newRegistry(), open)
+	globalStore.AddProject(&Project{ID: "project-1", Name: "Test"})
+
+	changed := board.NewTicket("Changed", "project-1")
+	untouched := board.NewTicket("Untouched", "project-1")
+	globalStore.Add(changed)
+	globalStore.Add(untouched)
+	globalStore.Move(changed.ID, board.StatusInProgress)
+
+	if err := globalStore.SaveDirty(); err != nil {
+		t.Fatal(err)
+	}
+	if repo.updates != 1 {
+		t.Errorf("SaveDirty made %d updates; want 1 for the moved ticket", repo.updates)
+	}
+}
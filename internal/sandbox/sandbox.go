@@ -0,0 +1,158 @@
+// Package sandbox runs agents isolated from the rest of the filesystem, in a
+// container or under firejail, so an autonomous agent can only write to the
+// ticket's worktree and the repository's git directory.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Sandbox modes
+const (
+	ModeDocker   = "docker"
+	ModePodman   = "podman"
+	ModeFirejail = "firejail"
+)
+
+// Config is a project's sandbox
+type Config struct {
+	Mode    string   `json:"mode"`              // "docker", "podman" or "firejail"
+	Image   string   `json:"image,omitempty"`   // container image with the agent installed
+	Profile string   `json:"profile,omitempty"` // firejail profile; firejail's default without one
+	Mounts  []string `json:"mounts,omitempty"`  // other paths the agent may read, or write with ":rw", e.g. "~/.claude:rw"
+	Env     []string `json:"env,omitempty"`     // host variables passed into a container, e.g. "ANTHROPIC_API_KEY"
+	Args    []string `json:"args,omitempty"`    // extra arguments to `docker run` or firejail
+}
+
+// Spec is an agent command to run in the sandbox
+type Spec struct {
+	Command string
+	Args    []string
+	Workdir string   // the worktree the agent works in
+	GitDir  string   // the repository's common git directory, so the agent can commit
+	Env     []string // KEY=value variables the agent runs with
+}
+
+// Validate reports a config that can't run an agent
+func (c *Config) Validate() error {
+	switch c.Mode {
+	case ModeDocker, ModePodman:
+		if c.Image == "" {
+			return fmt.Errorf("sandbox mode %q needs an image", c.Mode)
+		}
+	case ModeFirejail:
+		if runtime.GOOS != "linux" {
+			return errors.New("firejail only runs on Linux")
+		}
+	default:
+		return fmt.Errorf("unknown sandbox mode %q; use docker, podman or firejail", c.Mode)
+	}
+	for _, m := range c.Mounts {
+		if path, _ := mount(m); !filepath.IsAbs(path) {
+			return fmt.Errorf("sandbox mount %q must be an absolute path or start with ~/", m)
+		}
+	}
+	return nil
+}
+
+// Wrap returns the command and arguments that run spec in the sandbox
+func (c *Config) Wrap(spec Spec) (string, []string, error) {
+	if err := c.Validate(); err != nil {
+		return "", nil, err
+	}
+	if _, err := exec.LookPath(c.Mode); err != nil {
+		return "", nil, fmt.Errorf("%s not found in PATH", c.Mode)
+	}
+	if c.Mode == ModeFirejail {
+		return c.Mode, c.firejailArgs(spec), nil
+	}
+	return c.Mode, c.containerArgs(spec), nil
+}
+
+// containerArgs mount the worktree and git directory at the paths they have
+// on the host, so paths in the prompt and in the worktree's .git file hold
+func (c *Config) containerArgs(spec Spec) []string {
+	args := []string{"run", "--rm", "-it", "--init", "-w", spec.Workdir}
+	switch {
+	case c.Mode == ModePodman:
+		args = append(args, "--userns=keep-id")
+	case runtime.GOOS != "windows":
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+
+	args = append(args, "-v", spec.Workdir+":"+spec.Workdir)
+	if spec.GitDir != "" && !within(spec.GitDir, spec.Workdir) {
+		args = append(args, "-v", spec.GitDir+":"+spec.GitDir)
+	}
+	for _, m := range c.Mounts {
+		path, writable := mount(m)
+		if writable {
+			args = append(args, "-v", path+":"+path)
+		} else {
+			args = append(args, "-v", path+":"+path+":ro")
+		}
+	}
+
+	// Values are taken from the environment the container is run with, so
+	// they don't show up in the process list
+	names := []string{"TERM", "HOME", "OPENKANBAN_SESSION"}
+	for _, e := range spec.Env {
+		name, _, _ := strings.Cut(e, "=")
+		names = append(names, name)
+	}
+	names = append(names, c.Env...)
+	for _, name := range names {
+		args = append(args, "-e", name)
+	}
+
+	args = append(args, c.Args...)
+	args = append(args, c.Image, spec.Command)
+	return append(args, spec.Args...)
+}
+
+// firejailArgs whitelist the worktree and git directory, hiding the rest of
+// the home directory; the profile governs the rest of the filesystem
+func (c *Config) firejailArgs(spec Spec) []string {
+	args := []string{"--quiet"}
+	if c.Profile != "" {
+		args = append(args, "--profile="+c.Profile)
+	}
+	args = append(args, "--whitelist="+spec.Workdir)
+	if spec.GitDir != "" && !within(spec.GitDir, spec.Workdir) {
+		args = append(args, "--whitelist="+spec.GitDir)
+	}
+	for _, m := range c.Mounts {
+		path, writable := mount(m)
+		args = append(args, "--whitelist="+path)
+		if !writable {
+			args = append(args, "--read-only="+path)
+		}
+	}
+	args = append(args, c.Args...)
+	args = append(args, spec.Command)
+	return append(args, spec.Args...)
+}
+
+// mount splits a mount into its path, with ~ expanded, and whether the agent
+// may write to it
+func mount(m string) (string, bool) {
+	path, writable := strings.CutSuffix(m, ":rw")
+	path = strings.TrimSuffix(path, ":ro")
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return filepath.Clean(path), writable
+}
+
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
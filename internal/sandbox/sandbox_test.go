@@ -0,0 +1,74 @@
+package sandbox
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		wantErr string
+	}{
+		{Config{Mode: ModeDocker, Image: "agents:latest"}, ""},
+		{Config{Mode: ModePodman}, "needs an image"},
+		{Config{Mode: "chroot"}, "unknown sandbox mode"},
+		{Config{Mode: ModeDocker, Image: "agents", Mounts: []string{"relative/path"}}, "absolute path"},
+		{Config{Mode: ModeDocker, Image: "agents", Mounts: []string{"~/.claude:rw", "/etc/ssl:ro"}}, ""},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Validate(%+v) = %v; want nil", tt.cfg, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Validate(%+v) = %v; want an error containing %q", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestContainerArgs(t *testing.T) {
+	cfg := Config{Mode: ModePodman, Image: "agents", Mounts: []string{"/opt/cache:rw", "/etc/ssl"}, Env: []string{"API_KEY"}}
+	spec := Spec{
+		Command: "claude",
+		Args:    []string{"Fix the login"},
+		Workdir: "/src/app-worktrees/fix-login",
+		GitDir:  "/src/app/.git",
+		Env:     []string{"AGENT_MODEL=opus"},
+	}
+	args := strings.Join(cfg.containerArgs(spec), " ")
+
+	for _, want := range []string{
+		"run --rm -it --init -w /src/app-worktrees/fix-login --userns=keep-id",
+		"-v /src/app-worktrees/fix-login:/src/app-worktrees/fix-login",
+		"-v /src/app/.git:/src/app/.git",
+		"-v /opt/cache:/opt/cache -v /etc/ssl:/etc/ssl:ro",
+		"-e AGENT_MODEL -e API_KEY",
+		"agents claude Fix the login",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("container args %q; want them to contain %q", args, want)
+		}
+	}
+	if strings.Contains(args, "opus") {
+		t.Errorf("container args %q carry an env value", args)
+	}
+}
+
+func TestFirejailArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("firejail paths are Unix paths")
+	}
+	cfg := Config{Mode: ModeFirejail, Profile: "agent", Mounts: []string{"/opt/tools"}}
+	// The main repo's git directory is inside the workdir, so it isn't
+	// listed again
+	spec := Spec{Command: "aider", Args: []string{"--yes"}, Workdir: "/src/app", GitDir: "/src/app/.git"}
+
+	got := cfg.firejailArgs(spec)
+	want := []string{"--quiet", "--profile=agent", "--whitelist=/src/app", "--whitelist=/opt/tools", "--read-only=/opt/tools", "aider", "--yes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("firejailArgs() = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+// Package secretenv keeps openkanban's own secrets out of the processes it
+// starts for others: agents, hooks, setup and test commands. An agent that
+// could read the ticket passphrase would make encrypting tickets pointless.
+package secretenv
+
+import (
+	"os"
+	"strings"
+)
+
+// Names are the variables holding openkanban's secrets: app.PassphraseEnv,
+// config.ControlTokenEnv, digest.PasswordEnv, jira.TokenEnv and
+// linear.KeyEnv. Agents are given the control token on purpose; see
+// ui's controlEnv.
+var Names = []string{
+	"OPENKANBAN_PASSPHRASE",
+	"OPENKANBAN_CONTROL_TOKEN",
+	"OPENKANBAN_SMTP_PASSWORD",
+	"JIRA_API_TOKEN",
+	"LINEAR_API_KEY",
+}
+
+// Strip returns env without the secrets
+func Strip(env []string) []string {
+	kept := make([]string, 0, len(env))
+	for _, e := range env {
+		key, _, _ := strings.Cut(e, "=")
+		if !isSecret(key) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// Environ is os.Environ without the secrets
+func Environ() []string {
+	return Strip(os.Environ())
+}
+
+func isSecret(key string) bool {
+	for _, name := range Names {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package secretenv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	env := []string{"HOME=/home/me", "OPENKANBAN_PASSPHRASE=hunter2", "LINEAR_API_KEY=lin_1", "OPENKANBAN_SESSION=fix"}
+	want := []string{"HOME=/home/me", "OPENKANBAN_SESSION=fix"}
+	if got := Strip(env); !slices.Equal(got, want) {
+		t.Errorf("Strip() = %v; want %v", got, want)
+	}
+}
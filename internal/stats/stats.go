@@ -0,0 +1,314 @@
+// Package stats computes board metrics from persisted ticket history.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// ProjectStats summarises a single project over a date range.
+type ProjectStats struct {
+	Project string `json:"project"`
+
+	Created   int `json:"created"`
+	Completed int `json:"completed"`
+
+	// ThroughputPerWeek is completed tickets per 7 days in the range
+	ThroughputPerWeek float64 `json:"throughput_per_week"`
+
+	// Cycle time is measured from StartedAt to CompletedAt
+	CycleTimeAvgHours    float64 `json:"cycle_time_avg_hours"`
+	CycleTimeMedianHours float64 `json:"cycle_time_median_hours"`
+
+	AgentRuns      int     `json:"agent_runs"`
+	AgentCompleted int     `json:"agent_completed"`
+	AgentFailed    int     `json:"agent_failed"`
+	AgentStopped   int     `json:"agent_stopped"`
+	SuccessRate    float64 `json:"success_rate"`
+	AgentHours     float64 `json:"agent_hours"`
+	Cost           float64 `json:"cost"`
+
+	cycleTimes []time.Duration
+}
+
+// AgentStats compares how reliably one agent type finishes tickets.
+type AgentStats struct {
+	Agent string `json:"agent"`
+
+	// Tickets it ran on in the range, and how many of them took it more
+	// than one attempt
+	Tickets int `json:"tickets"`
+	Retried int `json:"retried"`
+	Runs    int `json:"runs"`
+
+	AttemptsPerTicket float64 `json:"attempts_per_ticket"`
+	RetryRate         float64 `json:"retry_rate"`
+
+	AgentCompleted int     `json:"agent_completed"`
+	AgentFailed    int     `json:"agent_failed"`
+	SuccessRate    float64 `json:"success_rate"`
+}
+
+// Report is the result of Compute.
+type Report struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Projects []*ProjectStats `json:"projects"`
+	Total    *ProjectStats   `json:"total"`
+	Agents   []*AgentStats   `json:"agents"`
+}
+
+// Options controls what Compute includes.
+type Options struct {
+	From time.Time
+	To   time.Time
+
+	// ProjectNames maps project IDs to display names
+	ProjectNames map[string]string
+
+	// CostPerHour maps agent names to their hourly cost
+	CostPerHour map[string]float64
+}
+
+// Compute aggregates tickets into per-project statistics for the range
+// [opts.From, opts.To).
+func Compute(tickets []*board.Ticket, opts Options) *Report {
+	byProject := make(map[string]*ProjectStats)
+	total := &ProjectStats{Project: "Total"}
+
+	get := func(projectID string) *ProjectStats {
+		ps, ok := byProject[projectID]
+		if !ok {
+			name := opts.ProjectNames[projectID]
+			if name == "" {
+				name = projectID
+			}
+			ps = &ProjectStats{Project: name}
+			byProject[projectID] = ps
+		}
+		return ps
+	}
+
+	inRange := func(t time.Time) bool {
+		return !t.Before(opts.From) && t.Before(opts.To)
+	}
+
+	byAgent := make(map[string]*AgentStats)
+
+	for _, t := range tickets {
+		ps := get(t.ProjectID)
+		attempts := make(map[string]int)
+
+		if inRange(t.CreatedAt) {
+			ps.Created++
+			total.Created++
+		}
+
+		if t.CompletedAt != nil && inRange(*t.CompletedAt) {
+			ps.Completed++
+			total.Completed++
+			if t.StartedAt != nil && t.CompletedAt.After(*t.StartedAt) {
+				cycle := t.CompletedAt.Sub(*t.StartedAt)
+				ps.cycleTimes = append(ps.cycleTimes, cycle)
+				total.cycleTimes = append(total.cycleTimes, cycle)
+			}
+		}
+
+		for _, run := range t.AgentRuns {
+			if !inRange(run.StartedAt) {
+				continue
+			}
+			as, ok := byAgent[run.Agent]
+			if !ok {
+				as = &AgentStats{Agent: run.Agent}
+				byAgent[run.Agent] = as
+			}
+			as.Runs++
+			attempts[run.Agent]++
+			switch attempts[run.Agent] {
+			case 1:
+				as.Tickets++
+			case 2:
+				as.Retried++
+			}
+			switch run.Outcome {
+			case board.OutcomeCompleted:
+				as.AgentCompleted++
+			case board.OutcomeFailed:
+				as.AgentFailed++
+			}
+
+			hours := run.Duration().Hours()
+			cost := hours * opts.CostPerHour[run.Agent]
+			for _, s := range []*ProjectStats{ps, total} {
+				s.AgentRuns++
+				s.AgentHours += hours
+				s.Cost += cost
+				switch run.Outcome {
+				case board.OutcomeCompleted:
+					s.AgentCompleted++
+				case board.OutcomeFailed:
+					s.AgentFailed++
+				case board.OutcomeStopped:
+					s.AgentStopped++
+				}
+			}
+		}
+	}
+
+	weeks := opts.To.Sub(opts.From).Hours() / (24 * 7)
+
+	report := &Report{From: opts.From, To: opts.To, Total: total}
+	for _, ps := range byProject {
+		if ps.Created == 0 && ps.Completed == 0 && ps.AgentRuns == 0 {
+			continue
+		}
+		ps.finish(weeks)
+		report.Projects = append(report.Projects, ps)
+	}
+	total.finish(weeks)
+
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return report.Projects[i].Project < report.Projects[j].Project
+	})
+
+	for _, as := range byAgent {
+		as.AttemptsPerTicket = float64(as.Runs) / float64(as.Tickets)
+		as.RetryRate = float64(as.Retried) / float64(as.Tickets)
+		if ended := as.AgentCompleted + as.AgentFailed; ended > 0 {
+			as.SuccessRate = float64(as.AgentCompleted) / float64(ended)
+		}
+		report.Agents = append(report.Agents, as)
+	}
+	sort.Slice(report.Agents, func(i, j int) bool {
+		return report.Agents[i].Agent < report.Agents[j].Agent
+	})
+	return report
+}
+
+func (ps *ProjectStats) finish(weeks float64) {
+	if weeks > 0 {
+		ps.ThroughputPerWeek = float64(ps.Completed) / weeks
+	}
+
+	if ended := ps.AgentCompleted + ps.AgentFailed; ended > 0 {
+		ps.SuccessRate = float64(ps.AgentCompleted) / float64(ended)
+	}
+
+	if n := len(ps.cycleTimes); n > 0 {
+		sort.Slice(ps.cycleTimes, func(i, j int) bool { return ps.cycleTimes[i] < ps.cycleTimes[j] })
+		var sum time.Duration
+		for _, d := range ps.cycleTimes {
+			sum += d
+		}
+		ps.CycleTimeAvgHours = (sum / time.Duration(n)).Hours()
+		median := ps.cycleTimes[n/2]
+		if n%2 == 0 {
+			median = (ps.cycleTimes[n/2-1] + ps.cycleTimes[n/2]) / 2
+		}
+		ps.CycleTimeMedianHours = median.Hours()
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable writes the report as an aligned text table.
+func (r *Report) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "Stats from %s to %s\n\n", r.From.Format("2006-01-02"), r.To.AddDate(0, 0, -1).Format("2006-01-02"))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tCREATED\tDONE\tPER WEEK\tCYCLE AVG\tCYCLE MEDIAN\tRUNS\tSUCCESS\tAGENT TIME\tCOST")
+	rows := r.Projects
+	if len(rows) > 1 {
+		rows = append(rows[:len(rows):len(rows)], r.Total)
+	}
+	for _, ps := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			ps.Project,
+			ps.Created,
+			ps.Completed,
+			ps.ThroughputPerWeek,
+			formatHours(ps.CycleTimeAvgHours),
+			formatHours(ps.CycleTimeMedianHours),
+			ps.AgentRuns,
+			formatRate(ps.SuccessRate, ps.AgentCompleted+ps.AgentFailed),
+			formatHours(ps.AgentHours),
+			formatCost(ps.Cost),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(r.Agents) == 0 {
+		return nil
+	}
+
+	// Retry rates side by side, to compare how reliable each agent is
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "AGENT\tTICKETS\tRUNS\tATTEMPTS\tRETRIED\tSUCCESS\tRETRY RATE")
+	for _, as := range r.Agents {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%d\t%s\t%s %.0f%%\n",
+			as.Agent,
+			as.Tickets,
+			as.Runs,
+			as.AttemptsPerTicket,
+			as.Retried,
+			formatRate(as.SuccessRate, as.AgentCompleted+as.AgentFailed),
+			bar(as.RetryRate, retryBarWidth),
+			as.RetryRate*100,
+		)
+	}
+	return tw.Flush()
+}
+
+// retryBarWidth is the length of a full bar in the retry rate chart
+const retryBarWidth = 20
+
+// bar draws a fraction between 0 and 1 as a bar of width cells
+func bar(fraction float64, width int) string {
+	filled := int(math.Round(fraction * float64(width)))
+	filled = max(min(filled, width), 0)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func formatHours(h float64) string {
+	if h == 0 {
+		return "-"
+	}
+	d := time.Duration(h * float64(time.Hour))
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%.1fh", d.Hours())
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+func formatRate(rate float64, samples int) string {
+	if samples == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+func formatCost(c float64) string {
+	if c == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", c)
+}
@@ -0,0 +1,166 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+func TestCompute(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+
+	done1 := board.NewTicket("Done fast", "p1")
+	done1.CreatedAt = from.Add(time.Hour)
+	done1.StartedAt = ptr(from.Add(2 * time.Hour))
+	done1.CompletedAt = ptr(from.Add(4 * time.Hour))
+	done1.AgentRuns = []board.AgentRun{
+		{Agent: "claude", StartedAt: from.Add(2 * time.Hour), EndedAt: ptr(from.Add(3 * time.Hour)), Outcome: board.OutcomeCompleted},
+		{Agent: "claude", StartedAt: from.Add(3 * time.Hour), EndedAt: ptr(from.Add(4 * time.Hour)), Outcome: board.OutcomeFailed},
+	}
+
+	done2 := board.NewTicket("Done slow", "p1")
+	done2.CreatedAt = from.AddDate(0, 0, -10)
+	done2.StartedAt = ptr(from)
+	done2.CompletedAt = ptr(from.Add(8 * time.Hour))
+
+	outOfRange := board.NewTicket("Old", "p2")
+	outOfRange.CreatedAt = from.AddDate(0, 0, -30)
+	outOfRange.CompletedAt = ptr(from.AddDate(0, 0, -20))
+
+	report := Compute([]*board.Ticket{done1, done2, outOfRange}, Options{
+		From:         from,
+		To:           to,
+		ProjectNames: map[string]string{"p1": "Alpha"},
+		CostPerHour:  map[string]float64{"claude": 3},
+	})
+
+	if len(report.Projects) != 1 {
+		t.Fatalf("len(Projects) = %d; want 1 (inactive projects omitted)", len(report.Projects))
+	}
+
+	ps := report.Projects[0]
+	checks := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Created", float64(ps.Created), 1},
+		{"Completed", float64(ps.Completed), 2},
+		{"ThroughputPerWeek", ps.ThroughputPerWeek, 1},
+		{"CycleTimeAvgHours", ps.CycleTimeAvgHours, 5},
+		{"CycleTimeMedianHours", ps.CycleTimeMedianHours, 5},
+		{"AgentRuns", float64(ps.AgentRuns), 2},
+		{"SuccessRate", ps.SuccessRate, 0.5},
+		{"AgentHours", ps.AgentHours, 2},
+		{"Cost", ps.Cost, 6},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v; want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if ps.Project != "Alpha" {
+		t.Errorf("Project = %q; want %q", ps.Project, "Alpha")
+	}
+	if report.Total.Completed != 2 {
+		t.Errorf("Total.Completed = %d; want 2", report.Total.Completed)
+	}
+}
+
+func TestCompute_Agents(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	run := func(agent string, hour int, outcome board.AgentOutcome) board.AgentRun {
+		start := from.Add(time.Duration(hour) * time.Hour)
+		return board.AgentRun{Agent: agent, StartedAt: start, EndedAt: ptr(start.Add(time.Hour)), Outcome: outcome}
+	}
+
+	retried := board.NewTicket("Took three tries", "p1")
+	retried.AgentRuns = []board.AgentRun{
+		run("claude", 1, board.OutcomeFailed),
+		run("claude", 2, board.OutcomeStopped),
+		run("claude", 3, board.OutcomeCompleted),
+	}
+	once := board.NewTicket("First try", "p1")
+	once.AgentRuns = []board.AgentRun{run("claude", 4, board.OutcomeCompleted)}
+	switched := board.NewTicket("Handed to another agent", "p1")
+	switched.AgentRuns = []board.AgentRun{
+		run("claude", 5, board.OutcomeFailed),
+		run("codex", 6, board.OutcomeCompleted),
+	}
+
+	report := Compute([]*board.Ticket{retried, once, switched}, Options{From: from, To: to})
+
+	if len(report.Agents) != 2 {
+		t.Fatalf("len(Agents) = %d; want 2", len(report.Agents))
+	}
+	claude, codex := report.Agents[0], report.Agents[1]
+	if claude.Agent != "claude" || codex.Agent != "codex" {
+		t.Fatalf("Agents = %q, %q; want claude, codex", claude.Agent, codex.Agent)
+	}
+	checks := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"claude Tickets", float64(claude.Tickets), 3},
+		{"claude Runs", float64(claude.Runs), 5},
+		{"claude Retried", float64(claude.Retried), 1},
+		{"claude AttemptsPerTicket", claude.AttemptsPerTicket, 5.0 / 3},
+		{"claude RetryRate", claude.RetryRate, 1.0 / 3},
+		{"claude SuccessRate", claude.SuccessRate, 0.5},
+		{"codex Tickets", float64(codex.Tickets), 1},
+		{"codex RetryRate", codex.RetryRate, 0},
+		{"codex SuccessRate", codex.SuccessRate, 1},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v; want %v", c.name, c.got, c.want)
+		}
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	if !strings.Contains(table.String(), "RETRY RATE") || !strings.Contains(table.String(), "███████░░░░░░░░░░░░░ 33%") {
+		t.Errorf("table missing the retry rate chart:\n%s", table.String())
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticket := board.NewTicket("Task", "p1")
+	ticket.CreatedAt = from
+
+	report := Compute([]*board.Ticket{ticket}, Options{From: from, To: from.AddDate(0, 0, 7)})
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	if !strings.Contains(table.String(), "Stats from 2024-01-01 to 2024-01-07") {
+		t.Errorf("table header missing date range:\n%s", table.String())
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON: %v", err)
+	}
+	if len(decoded.Projects) != 1 || decoded.Projects[0].Created != 1 {
+		t.Errorf("decoded projects = %+v; want one project with 1 created", decoded.Projects)
+	}
+}
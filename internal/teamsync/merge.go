@@ -0,0 +1,204 @@
+package teamsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Ticket is a ticket's shared fields as JSON values by key
+type Ticket map[string]json.RawMessage
+
+// localFields describe one machine's checkout and agents, so they are
+// never shared and a merge keeps each side's own
+var localFields = []string{
+	"project_id",
+	"worktree_path",
+	"base_behind",
+	"stash",
+	"agent_status",
+	"agent_spawned_at",
+	"agent_port",
+	"agent_session_id",
+}
+
+// Share converts a ticket to its shared fields
+func Share(t *board.Ticket) (Ticket, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var shared Ticket
+	if err := json.Unmarshal(data, &shared); err != nil {
+		return nil, err
+	}
+	for _, f := range localFields {
+		delete(shared, f)
+	}
+	for k, v := range shared {
+		shared[k] = canonical(v)
+	}
+	return shared, nil
+}
+
+// ShareAll converts tickets to their shared fields by ID. Tickets are read
+// here, so call it where they are safe to read and sync the copies.
+func ShareAll(tickets []*board.Ticket) (map[board.TicketID]Ticket, error) {
+	shared := make(map[board.TicketID]Ticket, len(tickets))
+	for _, t := range tickets {
+		s, err := Share(t)
+		if err != nil {
+			return nil, err
+		}
+		shared[t.ID] = s
+	}
+	return shared, nil
+}
+
+// Equal reports whether a ticket's shared fields are s
+func Equal(t *board.Ticket, s Ticket) bool {
+	mine, err := Share(t)
+	return err == nil && equalTicket(mine, s)
+}
+
+// Apply copies a shared ticket's fields onto dst, leaving dst's local
+// fields alone
+func Apply(dst *board.Ticket, shared Ticket) error {
+	data, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	var fields Ticket
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	local := make(Ticket, len(localFields))
+	for _, f := range localFields {
+		if v, ok := fields[f]; ok {
+			local[f] = v
+		}
+	}
+	for k, v := range shared {
+		local[k] = v
+	}
+	data, err = json.Marshal(local)
+	if err != nil {
+		return err
+	}
+	*dst = board.Ticket{}
+	return json.Unmarshal(data, dst)
+}
+
+// Merge three-way merges the tickets of two boards that share base. Each
+// field changed on only one side takes that side's value; a field both
+// sides changed differently takes the value of the more recently updated
+// ticket. A ticket deleted on one side stays deleted unless the other side
+// changed it since base.
+func Merge(base, ours, theirs map[board.TicketID]Ticket) map[board.TicketID]Ticket {
+	merged := make(map[board.TicketID]Ticket)
+	ids := make(map[board.TicketID]bool)
+	for id := range ours {
+		ids[id] = true
+	}
+	for id := range theirs {
+		ids[id] = true
+	}
+
+	for id := range ids {
+		b, o, t := base[id], ours[id], theirs[id]
+		switch {
+		case o != nil && t != nil:
+			merged[id] = mergeTicket(b, o, t)
+		case o != nil:
+			if b == nil || !equalTicket(b, o) {
+				merged[id] = o
+			}
+		case t != nil:
+			if b == nil || !equalTicket(b, t) {
+				merged[id] = t
+			}
+		}
+	}
+	return merged
+}
+
+func mergeTicket(base, ours, theirs Ticket) Ticket {
+	newer, older := ours, theirs
+	if updatedAt(theirs).After(updatedAt(ours)) {
+		newer, older = theirs, ours
+	}
+
+	keys := make(map[string]bool)
+	for _, side := range []Ticket{base, ours, theirs} {
+		for k := range side {
+			keys[k] = true
+		}
+	}
+
+	merged := make(Ticket)
+	for k := range keys {
+		b, bOK := base[k]
+		o, oOK := ours[k]
+		t, tOK := theirs[k]
+		var v json.RawMessage
+		var ok bool
+		switch {
+		case oOK == tOK && bytes.Equal(o, t):
+			v, ok = o, oOK
+		case base != nil && oOK == bOK && bytes.Equal(o, b):
+			v, ok = t, tOK
+		case base != nil && tOK == bOK && bytes.Equal(t, b):
+			v, ok = o, oOK
+		default:
+			v, ok = newer[k]
+		}
+		if ok {
+			merged[k] = v
+		}
+	}
+
+	// The merge is at least as new as either side
+	if updatedAt(older).After(updatedAt(merged)) {
+		merged["updated_at"] = older["updated_at"]
+	}
+	if updatedAt(newer).After(updatedAt(merged)) {
+		merged["updated_at"] = newer["updated_at"]
+	}
+	return merged
+}
+
+func equalTicket(a, b Ticket) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !bytes.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func updatedAt(t Ticket) time.Time {
+	var at time.Time
+	if v, ok := t["updated_at"]; ok {
+		json.Unmarshal(v, &at)
+	}
+	return at
+}
+
+// canonical re-encodes a JSON value so equal values compare equal
+// byte for byte
+func canonical(v json.RawMessage) json.RawMessage {
+	var x any
+	if err := json.Unmarshal(v, &x); err != nil {
+		return v
+	}
+	out, err := json.Marshal(x)
+	if err != nil {
+		return v
+	}
+	return out
+}
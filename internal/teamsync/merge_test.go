@@ -0,0 +1,122 @@
+package teamsync
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+func share(t *testing.T, ticket *board.Ticket) Ticket {
+	t.Helper()
+	shared, err := Share(ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shared
+}
+
+func title(t *testing.T, shared Ticket) string {
+	t.Helper()
+	ticket := &board.Ticket{}
+	if err := Apply(ticket, shared); err != nil {
+		t.Fatal(err)
+	}
+	return ticket.Title
+}
+
+func TestShare_DropsLocalFields(t *testing.T) {
+	ticket := board.NewTicket("Fix login", "project-1")
+	ticket.WorktreePath = "/home/me/wt/fix-login"
+	ticket.AgentStatus = board.AgentWorking
+	shared := share(t, ticket)
+	for _, f := range []string{"project_id", "worktree_path", "agent_status"} {
+		if _, ok := shared[f]; ok {
+			t.Errorf("shared ticket has local field %s", f)
+		}
+	}
+
+	// Applying keeps the receiving ticket's own local fields
+	mine := board.NewTicket("Old title", "project-2")
+	mine.ID = ticket.ID
+	mine.WorktreePath = "/home/you/wt"
+	if err := Apply(mine, shared); err != nil {
+		t.Fatal(err)
+	}
+	if mine.Title != "Fix login" || mine.ProjectID != "project-2" || mine.WorktreePath != "/home/you/wt" {
+		t.Errorf("Apply = %q in %q at %q; want the shared title with local project and worktree", mine.Title, mine.ProjectID, mine.WorktreePath)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticket := func(id, title, desc string, updated time.Duration) Ticket {
+		tk := board.NewTicket(title, "p")
+		tk.ID = board.TicketID(id)
+		tk.Description = desc
+		tk.CreatedAt = at
+		tk.UpdatedAt = at.Add(updated)
+		return share(t, tk)
+	}
+	set := func(tickets ...Ticket) map[board.TicketID]Ticket {
+		m := make(map[board.TicketID]Ticket)
+		for _, tk := range tickets {
+			var id board.TicketID
+			if err := json.Unmarshal(tk["id"], &id); err != nil {
+				t.Fatal(err)
+			}
+			m[id] = tk
+		}
+		return m
+	}
+
+	base := set(ticket("a", "A", "", 0), ticket("b", "B", "", 0), ticket("c", "C", "", 0))
+	ours := set(
+		ticket("a", "A renamed", "", time.Hour), // we renamed a
+		ticket("b", "B", "", 0),                 // b untouched here, deleted there
+		ticket("c", "C", "ours", 2*time.Hour),   // both edited c's description
+		ticket("d", "D", "", time.Hour),         // we added d
+	)
+	theirs := set(
+		ticket("a", "A", "their notes", time.Minute), // they described a
+		ticket("c", "C", "theirs", time.Hour),
+		ticket("e", "E", "", time.Hour), // they added e
+	)
+
+	merged := Merge(base, ours, theirs)
+	if _, ok := merged["b"]; ok {
+		t.Error("b deleted on their side but kept")
+	}
+	for _, id := range []board.TicketID{"a", "c", "d", "e"} {
+		if _, ok := merged[id]; !ok {
+			t.Errorf("ticket %s missing from the merge", id)
+		}
+	}
+
+	a := &board.Ticket{}
+	Apply(a, merged["a"])
+	if a.Title != "A renamed" || a.Description != "their notes" {
+		t.Errorf("a = %q / %q; want both sides' edits", a.Title, a.Description)
+	}
+	if !a.UpdatedAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("a updated_at = %v; want the newer side's", a.UpdatedAt)
+	}
+	c := &board.Ticket{}
+	Apply(c, merged["c"])
+	if c.Description != "ours" {
+		t.Errorf("conflicting c description = %q; want the more recently updated side's", c.Description)
+	}
+}
+
+func TestMerge_DeleteLosesToEdit(t *testing.T) {
+	tk := board.NewTicket("Keep me", "p")
+	base := map[board.TicketID]Ticket{tk.ID: share(t, tk)}
+	tk.Title = "Kept and edited"
+	edited := map[board.TicketID]Ticket{tk.ID: share(t, tk)}
+
+	merged := Merge(base, nil, edited)
+	if got, ok := merged[tk.ID]; !ok || title(t, got) != "Kept and edited" {
+		t.Errorf("ticket deleted here but edited there: merged = %v", merged)
+	}
+}
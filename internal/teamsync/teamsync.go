@@ -0,0 +1,216 @@
+// Package teamsync shares a board between several people through a git
+// branch. Each sync fetches the branch, three-way merges its tickets with
+// the local ones and pushes the result, so a small team can work from one
+// board without a server.
+package teamsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/crypt"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// DefaultFile holds a project's tickets on a branch in its own repository
+const DefaultFile = "tickets.json"
+
+// Options say where a project's board is shared
+type Options struct {
+	Repo   string // repository the branch lives in
+	Remote string // remote the branch is pushed to
+	Branch string // branch holding the board, e.g. openkanban-state
+	File   string // file on the branch holding this project's tickets
+
+	// Cipher encrypts the shared file like the local ticket files; nil
+	// shares plain JSON
+	Cipher *crypt.Cipher
+}
+
+// Result is the outcome of a sync
+type Result struct {
+	// Tickets are the merged shared tickets, by ID
+	Tickets map[board.TicketID]Ticket
+	// Pulled is true when the remote had changes not yet on this board
+	Pulled bool
+	// Pushed is true when local changes were pushed
+	Pushed bool
+}
+
+// file is the layout of the shared file
+type file struct {
+	Version int                       `json:"version"`
+	Tickets map[board.TicketID]Ticket `json:"tickets"`
+}
+
+// ErrNoRemote is returned when the repository has no remote to sync through
+var ErrNoRemote = errors.New("no remote to sync through")
+
+// Sync merges ours, from ShareAll, with the board on the branch and pushes
+// the result. A ref per file records the commit it was last synced at,
+// which is the merge base the next time; projects sharing a repository
+// each keep their own, since syncing one moves the branch past changes to
+// the others' files they have not merged yet. The local branch follows the
+// latest sync.
+func Sync(opts Options, ours map[board.TicketID]Ticket) (*Result, error) {
+	if opts.Remote == "" {
+		return nil, ErrNoRemote
+	}
+	localRef := "refs/heads/" + opts.Branch
+	baseRef := fileBaseRef(opts)
+
+	remote, err := git.FetchBranch(opts.Repo, opts.Remote, opts.Branch)
+	if err != nil {
+		return nil, err
+	}
+	last := git.ResolveRef(opts.Repo, baseRef)
+	if last == "" && opts.File == DefaultFile {
+		// A project alone in its repository used the branch as its base
+		// before there were refs per file
+		last = git.ResolveRef(opts.Repo, localRef)
+	}
+
+	base, err := readTickets(opts, last)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := readTickets(opts, remote)
+	if err != nil {
+		return nil, err
+	}
+	merged := Merge(base, ours, theirs)
+	result := &Result{
+		Tickets: merged,
+		Pulled:  remote != "" && remote != last && !sameBoard(base, theirs),
+	}
+
+	if remote != "" && sameBoard(merged, theirs) {
+		// Nothing of ours to add; just remember what we have seen
+		if remote != last {
+			if err := remember(opts, remote); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	data, err := json.MarshalIndent(file{Version: 1, Tickets: merged}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if data, err = opts.Cipher.Seal(data); err != nil {
+		return nil, fmt.Errorf("failed to encrypt shared tickets: %w", err)
+	}
+
+	var parents []string
+	if remote != "" {
+		parents = append(parents, remote)
+	}
+	if last != "" && last != remote {
+		parents = append(parents, last)
+	}
+	commit, err := git.CommitFile(opts.Repo, opts.File, data, "openkanban: sync "+opts.File, parents...)
+	if err != nil {
+		return nil, err
+	}
+	if err := git.PushCommit(opts.Repo, opts.Remote, commit, opts.Branch); err != nil {
+		return nil, err
+	}
+	if err := remember(opts, commit); err != nil {
+		return nil, err
+	}
+	git.UpdateRef(opts.Repo, "refs/remotes/"+opts.Remote+"/"+opts.Branch, commit)
+	result.Pushed = true
+	return result, nil
+}
+
+// fileBaseRef is the ref recording the commit opts.File was last synced at
+func fileBaseRef(opts Options) string {
+	return "refs/openkanban/" + opts.Branch + "/" + opts.File
+}
+
+// remember records commit as the file's merge base and moves the local
+// branch to it
+func remember(opts Options, commit string) error {
+	if err := git.UpdateRef(opts.Repo, fileBaseRef(opts), commit); err != nil {
+		return err
+	}
+	return git.UpdateRef(opts.Repo, "refs/heads/"+opts.Branch, commit)
+}
+
+func readTickets(opts Options, commit string) (map[board.TicketID]Ticket, error) {
+	data, err := git.ReadFile(opts.Repo, commit, opts.File)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	if data, err = opts.Cipher.Open(data); err != nil {
+		return nil, fmt.Errorf("failed to decrypt shared tickets: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid shared tickets in %s: %w", opts.File, err)
+	}
+	for _, t := range f.Tickets {
+		for k, v := range t {
+			t[k] = canonical(v)
+		}
+	}
+	return f.Tickets, nil
+}
+
+func sameBoard(a, b map[board.TicketID]Ticket) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, t := range a {
+		other, ok := b[id]
+		if !ok || !equalTicket(t, other) {
+			return false
+		}
+	}
+	return true
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ProjectFile names a project's file in a repository shared by several
+// projects. Team members register projects under their own IDs, so the
+// file goes by the project's name.
+func ProjectFile(name string) string {
+	slug := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if slug == "" {
+		slug = "project"
+	}
+	return slug + ".json"
+}
+
+// ProjectOptions are the options for syncing project p. With settings.Repo
+// set, every project shares that repository under its own file; otherwise
+// each project keeps tickets.json on the branch in its own repository.
+func ProjectOptions(settings config.SyncSettings, p *project.Project, c *crypt.Cipher) Options {
+	opts := Options{
+		Repo:   p.RepoPath,
+		Remote: settings.Remote,
+		Branch: settings.Branch,
+		File:   DefaultFile,
+		Cipher: c,
+	}
+	if settings.Repo != "" {
+		opts.Repo = settings.Repo
+		if strings.HasPrefix(opts.Repo, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				opts.Repo = filepath.Join(home, opts.Repo[2:])
+			}
+		}
+		opts.File = ProjectFile(p.Name)
+	}
+	return opts
+}
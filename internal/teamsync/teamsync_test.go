@@ -0,0 +1,179 @@
+package teamsync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/crypt"
+)
+
+// testClones is a bare remote with two clones of it, one per team member
+func testClones(t *testing.T) (alice, bob string, gitIn func(dir string, args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	gitIn = func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	gitIn(root, "init", "-q", "--bare", "-b", "main", remote)
+	alice, bob = filepath.Join(root, "alice"), filepath.Join(root, "bob")
+	gitIn(root, "clone", "-q", remote, alice)
+	gitIn(root, "clone", "-q", remote, bob)
+	return alice, bob, gitIn
+}
+
+func shareAll(t *testing.T, tickets []*board.Ticket) map[board.TicketID]Ticket {
+	t.Helper()
+	shared, err := ShareAll(tickets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shared
+}
+
+func opts(repo string) Options {
+	return Options{Repo: repo, Remote: "origin", Branch: "openkanban-state", File: DefaultFile}
+}
+
+func TestSync_TwoMembers(t *testing.T) {
+	alice, bob, gitIn := testClones(t)
+
+	aliceTicket := board.NewTicket("Alice's task", "alice-project")
+	if res, err := Sync(opts(alice), shareAll(t, []*board.Ticket{aliceTicket})); err != nil || !res.Pushed {
+		t.Fatalf("first sync = %+v, %v; want a push", res, err)
+	}
+
+	// Bob starts with his own ticket and receives Alice's
+	bobTicket := board.NewTicket("Bob's task", "bob-project")
+	res, err := Sync(opts(bob), shareAll(t, []*board.Ticket{bobTicket}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Pulled || !res.Pushed || len(res.Tickets) != 2 {
+		t.Fatalf("bob's sync = pulled %v, pushed %v, %d tickets; want both and 2 tickets", res.Pulled, res.Pushed, len(res.Tickets))
+	}
+
+	// Alice edits her ticket; Bob deletes his. Both come through.
+	aliceTicket.Title = "Alice's task, renamed"
+	bobSide := &board.Ticket{}
+	if err := Apply(bobSide, res.Tickets[aliceTicket.ID]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sync(opts(bob), shareAll(t, []*board.Ticket{bobSide})); err != nil {
+		t.Fatal(err)
+	}
+	res, err = Sync(opts(alice), shareAll(t, []*board.Ticket{aliceTicket}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Tickets) != 1 || title(t, res.Tickets[aliceTicket.ID]) != "Alice's task, renamed" {
+		t.Errorf("alice's board after sync = %v; want only her renamed ticket", res.Tickets)
+	}
+
+	// The user's branch and working tree are untouched
+	if head := gitIn(alice, "symbolic-ref", "--short", "HEAD"); head != "main" {
+		t.Errorf("HEAD moved to %s", head)
+	}
+	if status := gitIn(alice, "status", "--porcelain"); status != "" {
+		t.Errorf("working tree changed:\n%s", status)
+	}
+}
+
+func TestSync_NothingToPush(t *testing.T) {
+	alice, bob, _ := testClones(t)
+	ticket := board.NewTicket("Shared", "p")
+	if _, err := Sync(opts(alice), shareAll(t, []*board.Ticket{ticket})); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Sync(opts(bob), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pushed || !res.Pulled {
+		t.Errorf("pulling an unchanged board: pushed %v, pulled %v; want only a pull", res.Pushed, res.Pulled)
+	}
+	res, err = Sync(opts(alice), shareAll(t, []*board.Ticket{ticket}))
+	if err != nil || res.Pushed || res.Pulled {
+		t.Errorf("sync with nothing new = %+v, %v; want neither push nor pull", res, err)
+	}
+}
+
+func TestSync_Encrypted(t *testing.T) {
+	defer func(n int) { crypt.Iterations = n }(crypt.Iterations)
+	crypt.Iterations = 1000
+	alice, bob, gitIn := testClones(t)
+	pass := func() ([]byte, error) { return []byte("team secret"), nil }
+
+	o := opts(alice)
+	o.Cipher = crypt.New(crypt.KindPassphrase, pass, nil)
+	if _, err := Sync(o, shareAll(t, []*board.Ticket{board.NewTicket("Client: Acme", "p")})); err != nil {
+		t.Fatal(err)
+	}
+	if shared := gitIn(alice, "show", "openkanban-state:"+DefaultFile); strings.Contains(shared, "Acme") {
+		t.Errorf("shared file is not encrypted:\n%s", shared)
+	}
+
+	o = opts(bob)
+	o.Cipher = crypt.New(crypt.KindPassphrase, pass, nil)
+	res, err := Sync(o, nil)
+	if err != nil || len(res.Tickets) != 1 {
+		t.Errorf("bob's encrypted sync = %v, %v; want the ticket", res, err)
+	}
+}
+
+func TestProjectFile(t *testing.T) {
+	for name, want := range map[string]string{"My App": "my-app.json", "../etc": "etc.json", "": "project.json"} {
+		if got := ProjectFile(name); got != want {
+			t.Errorf("ProjectFile(%q) = %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestSync_ProjectsShareRepo(t *testing.T) {
+	alice, bob, _ := testClones(t)
+	optsFor := func(repo, name string) Options {
+		o := opts(repo)
+		o.File = ProjectFile(name)
+		return o
+	}
+
+	api := board.NewTicket("API task", "api")
+	if _, err := Sync(optsFor(alice, "API"), shareAll(t, []*board.Ticket{api})); err != nil {
+		t.Fatal(err)
+	}
+	// Bob adds to the API board, which Alice has not pulled yet
+	bobAPI := board.NewTicket("Bob's API task", "api")
+	if _, err := Sync(optsFor(bob, "API"), shareAll(t, []*board.Ticket{bobAPI})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Syncing another project moves Alice's branch past Bob's change, which
+	// must not become the API board's merge base
+	web := board.NewTicket("Web task", "web")
+	if _, err := Sync(optsFor(alice, "Web"), shareAll(t, []*board.Ticket{web})); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Sync(optsFor(alice, "API"), shareAll(t, []*board.Ticket{api}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Tickets[bobAPI.ID]; !ok || len(res.Tickets) != 2 {
+		t.Errorf("alice's API board = %v; want her ticket and Bob's", res.Tickets)
+	}
+}
@@ -0,0 +1,389 @@
+// Package telemetry exports trace spans over OTLP/HTTP (JSON encoding), so
+// agent sessions, git operations and control requests can be followed in
+// an observability stack. Until a tracer is installed with SetDefault,
+// every span is a no-op.
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultEndpoint is the OTLP/HTTP collector address used when neither
+	// the config nor OTEL_EXPORTER_OTLP_ENDPOINT sets one
+	DefaultEndpoint = "http://localhost:4318"
+
+	flushInterval = 5 * time.Second
+	maxBatch      = 512
+	maxQueue      = 4096 // spans beyond this are dropped while the collector is unreachable
+)
+
+// Attr is a span attribute
+type Attr struct {
+	Key   string
+	Value any // string, int, int64, bool or float64
+}
+
+// String returns a string attribute
+func String(key, value string) Attr { return Attr{key, value} }
+
+// Int returns an integer attribute
+func Int(key string, value int) Attr { return Attr{key, value} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// Span is a timed operation. A nil Span ignores every call, so callers never
+// check whether tracing is on.
+type Span struct {
+	tracer   *Tracer
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	err      string
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// SetAttr adds an attribute to the span
+func (s *Span) SetAttr(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// Child starts a span nested under s
+func (s *Span) Child(name string, attrs ...Attr) *Span {
+	if s == nil {
+		return nil
+	}
+	c := s.tracer.newSpan(name, time.Now(), attrs)
+	c.traceID = s.traceID
+	c.parentID = s.spanID
+	return c
+}
+
+// End finishes the span, marking it failed when err is not nil. Only the
+// first call counts.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	if err != nil {
+		s.err = err.Error()
+	}
+	s.mu.Unlock()
+	s.tracer.enqueue(s)
+}
+
+// Tracer batches finished spans and posts them to an OTLP/HTTP collector in
+// the background. Export failures are dropped silently: openkanban owns the
+// terminal, so there is nowhere to print them.
+type Tracer struct {
+	url     string
+	service string
+	headers map[string]string
+	client  *http.Client
+
+	mu    sync.Mutex
+	queue []*Span
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New returns a tracer exporting to endpoint, the collector's base address
+// as in OTEL_EXPORTER_OTLP_ENDPOINT. An empty endpoint falls back to the
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT and OTEL_EXPORTER_OTLP_ENDPOINT
+// environment variables, then DefaultEndpoint. OTEL_EXPORTER_OTLP_HEADERS
+// adds to headers.
+func New(endpoint, service string, headers map[string]string) *Tracer {
+	url := TracesURL(endpoint)
+	all := parseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for k, v := range headers {
+		all[k] = v
+	}
+	t := &Tracer{
+		url:     url,
+		service: service,
+		headers: all,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// TracesURL returns the URL spans are posted to for a configured endpoint
+func TracesURL(endpoint string) string {
+	if endpoint == "" {
+		if url := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); url != "" {
+			return url
+		}
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return strings.TrimRight(endpoint, "/") + "/v1/traces"
+}
+
+// parseHeaders reads "key=value,key2=value2"
+func parseHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if k, v, ok := strings.Cut(pair, "="); ok && strings.TrimSpace(k) != "" {
+			headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return headers
+}
+
+// Start begins a root span
+func (t *Tracer) Start(name string, attrs ...Attr) *Span {
+	if t == nil {
+		return nil
+	}
+	s := t.newSpan(name, time.Now(), attrs)
+	_, _ = rand.Read(s.traceID[:])
+	return s
+}
+
+// Record exports an operation that already finished as a root span
+func (t *Tracer) Record(name string, start, end time.Time, err error, attrs ...Attr) {
+	if t == nil {
+		return
+	}
+	s := t.Start(name, attrs...)
+	s.start = start
+	s.ended = true
+	s.end = end
+	if err != nil {
+		s.err = err.Error()
+	}
+	t.enqueue(s)
+}
+
+func (t *Tracer) newSpan(name string, start time.Time, attrs []Attr) *Span {
+	s := &Span{tracer: t, name: name, start: start, attrs: attrs}
+	_, _ = rand.Read(s.spanID[:])
+	return s
+}
+
+func (t *Tracer) enqueue(s *Span) {
+	t.mu.Lock()
+	if len(t.queue) < maxQueue {
+		t.queue = append(t.queue, s)
+	}
+	t.mu.Unlock()
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.Flush(); err != nil {
+				slog.Debug("telemetry export failed", "err", err)
+			}
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Flush exports every finished span now
+func (t *Tracer) Flush() error {
+	if t == nil {
+		return nil
+	}
+	for {
+		t.mu.Lock()
+		n := min(len(t.queue), maxBatch)
+		batch := t.queue[:n:n]
+		t.queue = t.queue[n:]
+		t.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		if err := t.export(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// Shutdown stops the background export and flushes what is left
+func (t *Tracer) Shutdown() error {
+	if t == nil {
+		return nil
+	}
+	close(t.stop)
+	<-t.done
+	return t.Flush()
+}
+
+func (t *Tracer) export(spans []*Span) error {
+	body, err := json.Marshal(t.payload(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to export spans: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP/JSON encoding of ExportTraceServiceRequest
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttr `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID      string     `json:"traceId"`
+		SpanID       string     `json:"spanId"`
+		ParentSpanID string     `json:"parentSpanId,omitempty"`
+		Name         string     `json:"name"`
+		Kind         int        `json:"kind"`
+		Start        string     `json:"startTimeUnixNano"`
+		End          string     `json:"endTimeUnixNano"`
+		Attributes   []otlpAttr `json:"attributes,omitempty"`
+		Status       otlpStatus `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"` // 1 ok, 2 error
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttr struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+)
+
+const spanKindInternal = 1
+
+func (t *Tracer) payload(spans []*Span) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:    hex.EncodeToString(s.traceID[:]),
+			SpanID:     hex.EncodeToString(s.spanID[:]),
+			Name:       s.name,
+			Kind:       spanKindInternal,
+			Start:      strconv.FormatInt(s.start.UnixNano(), 10),
+			End:        strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes: encodeAttrs(s.attrs),
+			Status:     otlpStatus{Code: 1},
+		}
+		if s.parentID != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.err != "" {
+			span.Status = otlpStatus{Code: 2, Message: s.err}
+		}
+		s.mu.Unlock()
+		out = append(out, span)
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttrs([]Attr{String("service.name", t.service)})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "openkanban"}, Spans: out}},
+	}}}
+}
+
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	out := make([]otlpAttr, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]any
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(val)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(val, 10)}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		out = append(out, otlpAttr{Key: a.Key, Value: v})
+	}
+	return out
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault installs the tracer used by Start and Record; nil turns
+// tracing off
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Start begins a root span on the default tracer
+func Start(name string, attrs ...Attr) *Span {
+	return defaultTracer.Load().Start(name, attrs...)
+}
+
+// Record exports a finished operation on the default tracer
+func Record(name string, start, end time.Time, err error, attrs ...Attr) {
+	defaultTracer.Load().Record(name, start, end, err, attrs...)
+}
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	var got otlpRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	tr := New(srv.URL, "openkanban", map[string]string{"Authorization": "Bearer x"})
+	root := tr.Start("control.spawn", String("method", "spawn"))
+	child := root.Child("git worktree", Int("git.args", 3))
+	child.End(errors.New("exit status 128"))
+	root.End(nil)
+	root.End(errors.New("ignored"))
+	start := time.Unix(100, 0)
+	tr.Record("agent.session", start, start.Add(time.Minute), nil, Bool("tmux", true))
+	if err := tr.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if auth != "Bearer x" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if len(got.ResourceSpans) != 1 || got.ResourceSpans[0].Resource.Attributes[0].Value["stringValue"] != "openkanban" {
+		t.Fatalf("resource = %+v", got.ResourceSpans)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans; want 3", len(spans))
+	}
+	c, r, a := spans[0], spans[1], spans[2]
+	if c.TraceID != r.TraceID || c.ParentSpanID != r.SpanID || r.ParentSpanID != "" || len(r.TraceID) != 32 {
+		t.Errorf("child %+v not nested under root %+v", c, r)
+	}
+	if c.Status.Code != 2 || c.Status.Message != "exit status 128" || r.Status.Code != 1 {
+		t.Errorf("statuses = %+v, %+v", c.Status, r.Status)
+	}
+	if c.Attributes[0].Value["intValue"] != "3" || a.Attributes[0].Value["boolValue"] != true {
+		t.Errorf("attributes = %+v, %+v", c.Attributes, a.Attributes)
+	}
+	if a.Start != "100000000000" || a.End != "160000000000" || a.TraceID == r.TraceID {
+		t.Errorf("recorded span = %+v", a)
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	SetDefault(nil)
+	s := Start("noop")
+	s.SetAttr(String("k", "v"))
+	s.Child("child").End(nil)
+	s.End(nil)
+	Record("noop", time.Now(), time.Now(), nil)
+}
+
+func TestTracesURL(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if got := TracesURL(""); got != "http://localhost:4318/v1/traces" {
+		t.Errorf("TracesURL(\"\") = %q", got)
+	}
+	if got := TracesURL("https://otel.example.com/"); got != "https://otel.example.com/v1/traces" {
+		t.Errorf("TracesURL() = %q", got)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	if got := TracesURL(""); got != "http://collector:4318/v1/traces" {
+		t.Errorf("TracesURL() from env = %q", got)
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://collector/traces")
+	if got := TracesURL(""); got != "http://collector/traces" {
+		t.Errorf("TracesURL() from traces env = %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hinshun/vt10x"
+)
+
+// benchScreen is agent-like output: prompts, colored diff lines and plain
+// text, so style runs are short enough to exercise the batching
+func benchScreen(rows int) string {
+	var sb strings.Builder
+	for i := 0; i < rows; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&sb, "\x1b[1;34m❯\x1b[0m running step %d of the plan\r\n", i)
+		case 1:
+			fmt.Fprintf(&sb, "\x1b[32m+ added line %d\x1b[0m \x1b[2m(internal/ui/model.go)\x1b[0m\r\n", i)
+		case 2:
+			fmt.Fprintf(&sb, "\x1b[31m- removed line %d\x1b[0m \x1b[38;5;208mwarning\x1b[0m \x1b[48;5;236m highlighted \x1b[0m\r\n", i)
+		default:
+			fmt.Fprintf(&sb, "plain output line %d with some ordinary text to fill the row out\r\n", i)
+		}
+	}
+	return sb.String()
+}
+
+// newBenchPane is a pane with a live screen and full scrollback, without a
+// process behind it
+func newBenchPane(b *testing.B, cols, rows int) *Pane {
+	b.Helper()
+	p := New("bench", cols, rows, 10000)
+	p.vt = vt10x.New(vt10x.WithSize(cols, rows))
+	if _, err := p.vt.Write([]byte(benchScreen(rows))); err != nil {
+		b.Fatal(err)
+	}
+	p.scrollback = NewScrollbackBuffer(p.scrollbackSize)
+	for i := 0; i < p.scrollbackSize; i++ {
+		p.scrollback.Push(benchLine(cols, i))
+	}
+	return p
+}
+
+// benchLine is a full-width scrollback line with a style change every
+// eight cells
+func benchLine(cols, n int) []vt10x.Glyph {
+	line := make([]vt10x.Glyph, cols)
+	for i := range line {
+		line[i] = vt10x.Glyph{
+			Char: rune('a' + (n+i)%26),
+			FG:   vt10x.Color(i / 8 % 8),
+			BG:   vt10x.DefaultBG,
+		}
+	}
+	return line
+}
+
+func BenchmarkRenderLiveScreen(b *testing.B) {
+	b.Run("80x24", benchRenderLive(80, 24))
+	b.Run("200x60", benchRenderLive(200, 60))
+}
+
+func benchRenderLive(cols, rows int) func(*testing.B) {
+	return func(b *testing.B) {
+		p := newBenchPane(b, cols, rows)
+		p.vt.Lock()
+		defer p.vt.Unlock()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.renderLiveScreenUnlocked(cols, rows)
+		}
+	}
+}
+
+// BenchmarkRenderScrolledView renders a pane scrolled half a screen back,
+// mixing scrollback and live rows
+func BenchmarkRenderScrolledView(b *testing.B) {
+	p := newBenchPane(b, 200, 60)
+	p.viewportOffset = 30
+	p.vt.Lock()
+	defer p.vt.Unlock()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.renderScrolledViewUnlocked(200, 60)
+	}
+}
+
+func BenchmarkScrollbackBuffer_Push(b *testing.B) {
+	sb := NewScrollbackBuffer(10000)
+	line := benchLine(200, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sb.Push(line)
+	}
+}
+
+func BenchmarkScrollbackBuffer_Get(b *testing.B) {
+	sb := NewScrollbackBuffer(10000)
+	for i := 0; i < 10000; i++ {
+		sb.Push(benchLine(200, i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sb.Get(i % 10000)
+	}
+}
+
+func BenchmarkScrollbackBuffer_GetRange(b *testing.B) {
+	sb := NewScrollbackBuffer(10000)
+	for i := 0; i < 10000; i++ {
+		sb.Push(benchLine(200, i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := i % (10000 - 60)
+		sb.GetRange(start, start+60)
+	}
+}
+
+// BenchmarkHandleOutput feeds a screenful of agent output through the
+// emulator, including the scrollback capture around each write
+func BenchmarkHandleOutput(b *testing.B) {
+	p := newBenchPane(b, 200, 60)
+	data := []byte(benchScreen(60))
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.handleOutput(data)
+	}
+}
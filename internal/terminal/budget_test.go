@@ -0,0 +1,26 @@
+//go:build perf
+
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/testutil"
+)
+
+// Render budgets for a 200x60 pane, about a maximized terminal. A frame at
+// 60fps has 16ms for everything, and a pane is redrawn on every frame while
+// its agent prints.
+
+func TestRenderLiveScreen_Budget(t *testing.T) {
+	testutil.CheckBudget(t, benchRenderLive(200, 60), testutil.Budget{PerOp: 2 * time.Millisecond, Allocs: 2000})
+}
+
+func TestRenderScrolledView_Budget(t *testing.T) {
+	testutil.CheckBudget(t, BenchmarkRenderScrolledView, testutil.Budget{PerOp: 3 * time.Millisecond, Allocs: 8000})
+}
+
+func TestScrollbackBuffer_Push_Budget(t *testing.T) {
+	testutil.CheckBudget(t, BenchmarkScrollbackBuffer_Push, testutil.Budget{PerOp: 20 * time.Microsecond, Allocs: 1})
+}
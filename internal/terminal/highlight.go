@@ -0,0 +1,114 @@
+package terminal
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hinshun/vt10x"
+)
+
+// Highlight styles the text Pattern matches in a pane's output, on top of
+// the colors the program chose
+type Highlight struct {
+	Pattern   *regexp.Regexp
+	Color     string // "#rrggbb" or "#rgb"; "" keeps the program's color
+	Bold      bool
+	Underline bool
+}
+
+// highlightStyle is a Highlight ready to apply to glyphs
+type highlightStyle struct {
+	re    *regexp.Regexp
+	fg    vt10x.Color
+	hasFG bool
+	mode  int16
+}
+
+// SetHighlights sets the rules applied as the pane renders. Later rules win
+// where matches overlap. Panes running in tmux are drawn by tmux and keep
+// their own colors.
+func (p *Pane) SetHighlights(rules []Highlight) {
+	styles := make([]highlightStyle, 0, len(rules))
+	for _, r := range rules {
+		s := highlightStyle{re: r.Pattern}
+		s.fg, s.hasFG = hexColor(r.Color)
+		if r.Bold {
+			s.mode |= 0x04
+		}
+		if r.Underline {
+			s.mode |= 0x02
+		}
+		styles = append(styles, s)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.highlights = styles
+	p.dirty = true
+}
+
+// hexColor reads "#rrggbb" or "#rgb" as a true color
+func hexColor(s string) (vt10x.Color, bool) {
+	hex, ok := strings.CutPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if !ok || len(hex) != 6 {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	// Values below 256 are read as palette colors, so a color that dark
+	// is nudged by one step of green
+	if n < 256 {
+		n |= 0x100
+	}
+	return vt10x.Color(n), true
+}
+
+// rowStyles matches the rules against a row, one rune per column, and
+// returns the style of each column, or nil when nothing matched
+func rowStyles(rules []highlightStyle, cols int, glyphAt func(col int) vt10x.Glyph) []*highlightStyle {
+	var text strings.Builder
+	starts := make([]int, cols+1) // byte offset of each column's rune
+	for col := 0; col < cols; col++ {
+		starts[col] = text.Len()
+		ch := glyphAt(col).Char
+		if ch == 0 {
+			ch = ' '
+		}
+		text.WriteRune(ch)
+	}
+	starts[cols] = text.Len()
+
+	row := text.String()
+	var styles []*highlightStyle
+	for i := range rules {
+		for _, loc := range rules[i].re.FindAllStringIndex(row, -1) {
+			if styles == nil {
+				styles = make([]*highlightStyle, cols)
+			}
+			for col := sort.SearchInts(starts, loc[0]); col < cols && starts[col] < loc[1]; col++ {
+				styles[col] = &rules[i]
+			}
+		}
+	}
+	return styles
+}
+
+// styleGlyph applies the column's highlight, if any, to its glyph
+func styleGlyph(styles []*highlightStyle, col int, g vt10x.Glyph) vt10x.Glyph {
+	if styles == nil || styles[col] == nil {
+		return g
+	}
+	s := styles[col]
+	if s.hasFG {
+		g.FG = s.fg
+	}
+	g.Mode |= s.mode
+	return g
+}
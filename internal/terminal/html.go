@@ -0,0 +1,236 @@
+package terminal
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	htmlDefaultFG = "#d0d0d0"
+	htmlDefaultBG = "#1e1e1e"
+)
+
+// htmlPalette is xterm's default for the 16 basic colors
+var htmlPalette = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// htmlStyle is the SGR state of the text being converted
+type htmlStyle struct {
+	fg, bg    string // "" is the default color
+	bold      bool
+	faint     bool
+	italic    bool
+	underline bool
+	reverse   bool
+}
+
+func (s htmlStyle) css() string {
+	fg, bg := s.fg, s.bg
+	if s.reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = htmlDefaultBG
+		}
+		if bg == "" {
+			bg = htmlDefaultFG
+		}
+	}
+	var parts []string
+	if fg != "" {
+		parts = append(parts, "color:"+fg)
+	}
+	if bg != "" {
+		parts = append(parts, "background:"+bg)
+	}
+	if s.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.faint {
+		parts = append(parts, "opacity:0.6")
+	}
+	if s.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if s.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
+
+// HTML renders text with ANSI color escapes, such as a pane's
+// StyledTranscript, as a standalone HTML page that keeps the colors.
+// Escapes other than colors and text attributes are dropped.
+func HTML(title, text string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	b.WriteString("<style>\n")
+	b.WriteString("body { margin: 0; background: " + htmlDefaultBG + "; }\n")
+	b.WriteString("pre { margin: 0; padding: 1em; color: " + htmlDefaultFG + "; background: " + htmlDefaultBG + "; ")
+	b.WriteString("font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 13px; line-height: 1.3; }\n")
+	b.WriteString("</style>\n</head>\n<body><pre>")
+	b.WriteString(ansiToHTML(text))
+	b.WriteString("</pre></body>\n</html>\n")
+	return b.String()
+}
+
+// ansiToHTML escapes text and turns its SGR sequences into styled spans
+func ansiToHTML(text string) string {
+	var b strings.Builder
+	var style htmlStyle
+	open := "" // css of the span being written, "" when none is open
+
+	for i := 0; i < len(text); {
+		if text[i] != 0x1b {
+			end := strings.IndexByte(text[i:], 0x1b)
+			if end < 0 {
+				end = len(text) - i
+			}
+			if css := style.css(); css != open {
+				if open != "" {
+					b.WriteString("</span>")
+				}
+				if css != "" {
+					b.WriteString(`<span style="` + css + `">`)
+				}
+				open = css
+			}
+			b.WriteString(html.EscapeString(text[i : i+end]))
+			i += end
+			continue
+		}
+
+		n, params, final := parseEscape(text[i:])
+		if final == 'm' {
+			style = applySGR(style, params)
+		}
+		i += n
+	}
+	if open != "" {
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+// parseEscape reads the escape sequence at the start of s, returning its
+// length and, for a CSI sequence, its parameters and final byte
+func parseEscape(s string) (n int, params string, final byte) {
+	if len(s) < 2 {
+		return len(s), "", 0
+	}
+	switch s[1] {
+	case '[':
+		for j := 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j + 1, s[2:j], s[j]
+			}
+		}
+		return len(s), "", 0
+	case ']':
+		// OSC, ended by BEL or ST
+		for j := 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1, "", 0
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2, "", 0
+			}
+		}
+		return len(s), "", 0
+	}
+	return 2, "", 0
+}
+
+// applySGR returns style with the SGR parameters applied
+func applySGR(style htmlStyle, params string) htmlStyle {
+	if params == "" {
+		return htmlStyle{}
+	}
+	codes := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	num := func(i int) int {
+		if i >= len(codes) {
+			return -1
+		}
+		v, err := strconv.Atoi(codes[i])
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch c := num(i); {
+		case c == 0:
+			style = htmlStyle{}
+		case c == 1:
+			style.bold = true
+		case c == 2:
+			style.faint = true
+		case c == 3:
+			style.italic = true
+		case c == 4:
+			style.underline = true
+		case c == 7:
+			style.reverse = true
+		case c == 22:
+			style.bold, style.faint = false, false
+		case c == 23:
+			style.italic = false
+		case c == 24:
+			style.underline = false
+		case c == 27:
+			style.reverse = false
+		case c >= 30 && c <= 37:
+			style.fg = htmlPalette[c-30]
+		case c >= 90 && c <= 97:
+			style.fg = htmlPalette[c-90+8]
+		case c == 39:
+			style.fg = ""
+		case c >= 40 && c <= 47:
+			style.bg = htmlPalette[c-40]
+		case c >= 100 && c <= 107:
+			style.bg = htmlPalette[c-100+8]
+		case c == 49:
+			style.bg = ""
+		case c == 38 || c == 48:
+			var color string
+			switch num(i + 1) {
+			case 5:
+				color = color256(num(i + 2))
+				i += 2
+			case 2:
+				r, g, b := num(i+2), num(i+3), num(i+4)
+				if r >= 0 && g >= 0 && b >= 0 {
+					color = fmt.Sprintf("#%02x%02x%02x", r&0xff, g&0xff, b&0xff)
+				}
+				i += 4
+			}
+			if c == 38 {
+				style.fg = color
+			} else {
+				style.bg = color
+			}
+		}
+	}
+	return style
+}
+
+// color256 returns the CSS color of an xterm 256-color palette entry
+func color256(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return htmlPalette[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	}
+	gray := 8 + 10*(n-232)
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+}
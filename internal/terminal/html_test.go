@@ -0,0 +1,77 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestANSIToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "plain text is escaped",
+			text: "a < b && c",
+			want: "a &lt; b &amp;&amp; c",
+		},
+		{
+			name: "basic and bright colors",
+			text: "\x1b[31mred\x1b[0m \x1b[1;92mok\x1b[m",
+			want: `<span style="color:#cd0000">red</span> <span style="color:#00ff00;font-weight:bold">ok</span>`,
+		},
+		{
+			name: "256 colors and true color",
+			text: "\x1b[38;5;196;48;2;0;0;128mhot\x1b[39;49m",
+			want: `<span style="color:#ff0000;background:#000080">hot</span>`,
+		},
+		{
+			name: "reverse video swaps default colors",
+			text: "\x1b[7msel\x1b[27m",
+			want: `<span style="color:#1e1e1e;background:#d0d0d0">sel</span>`,
+		},
+		{
+			name: "other escapes are dropped",
+			text: "\x1b]0;title\x07\x1b[2Kdone",
+			want: "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansiToHTML(tt.text); got != tt.want {
+				t.Errorf("ansiToHTML(%q) = %q; want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	page := HTML("Fix <login>", "\x1b[32mPASS\x1b[0m")
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Fix &lt;login&gt;</title>",
+		`<pre><span style="color:#00cd00">PASS</span></pre>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML() is missing %q:\n%s", want, page)
+		}
+	}
+}
+
+func TestColor256(t *testing.T) {
+	tests := map[int]string{
+		1:   "#cd0000",
+		16:  "#000000",
+		231: "#ffffff",
+		232: "#080808",
+		255: "#eeeeee",
+		256: "",
+	}
+	for n, want := range tests {
+		if got := color256(n); got != want {
+			t.Errorf("color256(%d) = %q; want %q", n, got, want)
+		}
+	}
+}
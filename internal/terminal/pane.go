@@ -13,6 +13,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/creack/pty"
 	"github.com/hinshun/vt10x"
+
+	"github.com/techdufus/openkanban/internal/secretenv"
 )
 
 const (
@@ -20,16 +22,27 @@ const (
 	readBufferSize = 65536
 )
 
+// readBuffers recycles PTY read buffers. A busy agent fills one per read,
+// and allocating 64KB each time kept the GC busy.
+var readBuffers = sync.Pool{
+	New: func() any {
+		buf := make([]byte, readBufferSize)
+		return &buf
+	},
+}
+
 type Pane struct {
 	id      string
 	vt      vt10x.Terminal
 	pty     *os.File
 	cmd     *exec.Cmd
+	exit    *processExit // reaps cmd; nil until it starts
 	mu      sync.Mutex
 	running bool
 	exitErr     error
 	workdir     string
 	sessionName string
+	env         []string // added to the agent's environment
 	width       int
 	height      int
 
@@ -45,8 +58,29 @@ type Pane struct {
 	altScreenActive bool     // tracks if child process is in alternate screen mode
 	viewportOffset  int      // lines scrolled back (0 = live view)
 	lastTopRow      []vt10x.Glyph // snapshot of row 0 before write for scroll detection
+	topRowBuf       []vt10x.Glyph // reused backing array for lastTopRow
 	scrollbackSize  int      // configured scrollback buffer size
 	selection       *SelectionState // mouse text selection state
+	highlights      []highlightStyle // styles matched text over the program's colors
+
+	tmux *TmuxTarget // runs the agent in a tmux window instead of the pty
+}
+
+// processExit is how a pane's process ended, known once done is closed
+type processExit struct {
+	done chan struct{}
+	err  error
+}
+
+// waitProcess reaps cmd in the background. Its exit status, not the pty
+// read error that comes with it, says whether the agent failed.
+func waitProcess(cmd *exec.Cmd) *processExit {
+	e := &processExit{done: make(chan struct{})}
+	go func() {
+		e.err = cmd.Wait()
+		close(e.done)
+	}()
+	return e
 }
 
 func New(id string, width, height int, scrollbackSize int) *Pane {
@@ -80,6 +114,11 @@ func (p *Pane) SetSessionName(name string) {
 	p.sessionName = name
 }
 
+// SetEnv adds KEY=value variables to the environment commands run with
+func (p *Pane) SetEnv(env []string) {
+	p.env = env
+}
+
 // Running returns whether the pane has a running process
 func (p *Pane) Running() bool {
 	p.mu.Lock()
@@ -87,6 +126,21 @@ func (p *Pane) Running() bool {
 	return p.running
 }
 
+// PID returns the pid of the pane's process, which leads the process group
+// of everything it starts
+func (p *Pane) PID() (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running && p.tmux != nil {
+		return p.tmuxPID()
+	}
+	if !p.running || p.cmd == nil || p.cmd.Process == nil {
+		return 0, ErrPaneNotRunning
+	}
+	return p.cmd.Process.Pid, nil
+}
+
 // ExitErr returns any error from the process exit
 func (p *Pane) ExitErr() error {
 	p.mu.Lock()
@@ -156,10 +210,20 @@ func (p *Pane) IsAltScreenActive() bool {
 
 // --- Bubbletea Messages ---
 
-// OutputMsg carries data read from the PTY
+// OutputMsg carries data read from the PTY. Data is a pooled buffer that
+// the pane reuses once it has handled the message; copy it to keep it.
 type OutputMsg struct {
 	PaneID string
 	Data   []byte
+
+	buf *[]byte
+}
+
+// release returns the message's buffer to the pool
+func (msg OutputMsg) release() {
+	if msg.buf != nil {
+		readBuffers.Put(msg.buf)
+	}
 }
 
 // ExitMsg indicates the process has exited
@@ -176,6 +240,12 @@ type RenderTickMsg struct {
 // ExitFocusMsg signals to return to board view
 type ExitFocusMsg struct{}
 
+// CopiedMsg reports text copied from the pane's selection to the clipboard
+type CopiedMsg struct {
+	PaneID string
+	Text   string
+}
+
 // --- PTY Lifecycle (Issue #13) ---
 
 // Start launches a command in a PTY and returns a Cmd to begin reading
@@ -184,9 +254,13 @@ func (p *Pane) Start(command string, args ...string) tea.Cmd {
 		p.mu.Lock()
 		defer p.mu.Unlock()
 
+		if p.tmux != nil {
+			return p.startTmux(command, args)
+		}
+
 		// Build command
 		p.cmd = exec.Command(command, args...)
-		p.cmd.Env = buildCleanEnv(p.sessionName)
+		p.cmd.Env = append(buildCleanEnv(p.sessionName), p.env...)
 
 		// Set working directory if specified
 		if p.workdir != "" {
@@ -200,6 +274,7 @@ func (p *Pane) Start(command string, args ...string) tea.Cmd {
 			return ExitMsg{PaneID: p.id, Err: err}
 		}
 		p.pty = ptmx
+		p.exit = waitProcess(p.cmd)
 		p.running = true
 		p.exitErr = nil
 
@@ -224,6 +299,11 @@ func (p *Pane) Stop() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.tmux != nil {
+		p.stopTmux()
+		return nil
+	}
+
 	if p.cmd != nil && p.cmd.Process != nil {
 		p.cmd.Process.Kill()
 	}
@@ -237,26 +317,27 @@ func (p *Pane) Stop() error {
 // StopGraceful sends SIGTERM, waits for timeout, then SIGKILL if needed.
 func (p *Pane) StopGraceful(timeout time.Duration) error {
 	p.mu.Lock()
+	if p.tmux != nil {
+		// tmux sends SIGHUP when the window closes
+		p.stopTmux()
+		p.mu.Unlock()
+		return nil
+	}
 	if !p.running || p.cmd == nil || p.cmd.Process == nil {
 		p.mu.Unlock()
 		return nil
 	}
 
 	proc := p.cmd.Process
+	exit := p.exit
 	p.mu.Unlock()
 
 	if err := proc.Signal(os.Interrupt); err != nil {
 		return p.Stop()
 	}
 
-	done := make(chan error, 1)
-	go func() {
-		_, err := proc.Wait()
-		done <- err
-	}()
-
 	select {
-	case <-done:
+	case <-exit.done:
 	case <-time.After(timeout):
 		proc.Kill()
 	}
@@ -277,12 +358,33 @@ func (p *Pane) WriteInput(data []byte) (int, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.tmux != nil && p.running {
+		if _, err := tmux("send-keys", "-l", "-t", p.tmux.pane, string(data)); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
 	if !p.running || p.pty == nil {
 		return 0, ErrPaneNotRunning
 	}
 	return p.pty.Write(data)
 }
 
+// Paste types text into the pane as a bracketed paste and presses Enter, so
+// an agent takes a multi-line prompt as one message instead of submitting
+// it line by line.
+func (p *Pane) Paste(text string) error {
+	_, err := p.WriteInput([]byte("\x1b[200~" + text + "\x1b[201~\r"))
+	return err
+}
+
+// Insert types text into the pane as a bracketed paste without pressing
+// Enter, leaving it at the agent's prompt to edit or send.
+func (p *Pane) Insert(text string) error {
+	_, err := p.WriteInput([]byte("\x1b[200~" + text + "\x1b[201~"))
+	return err
+}
+
 // readOutput returns a Cmd that reads from the PTY
 func (p *Pane) readOutput() tea.Cmd {
 	p.mu.Lock()
@@ -298,14 +400,23 @@ func (p *Pane) readOutputUnlocked() tea.Cmd {
 
 	ptyFile := p.pty
 	paneID := p.id
+	exit := p.exit
 
 	return func() tea.Msg {
-		buf := make([]byte, readBufferSize)
-		n, err := ptyFile.Read(buf)
+		buf := readBuffers.Get().(*[]byte)
+		n, err := ptyFile.Read(*buf)
 		if err != nil {
+			readBuffers.Put(buf)
+			// The read fails (EIO on Linux) once the process has gone and
+			// its output is drained; that is the end of output, not a
+			// failure
+			if exit != nil {
+				<-exit.done
+				return ExitMsg{PaneID: paneID, Err: exit.err}
+			}
 			return ExitMsg{PaneID: paneID, Err: err}
 		}
-		return OutputMsg{PaneID: paneID, Data: buf[:n]}
+		return OutputMsg{PaneID: paneID, Data: (*buf)[:n], buf: buf}
 	}
 }
 
@@ -319,8 +430,15 @@ func (p *Pane) Update(msg tea.Msg) tea.Cmd {
 			return nil
 		}
 		p.handleOutput(msg.Data)
+		msg.release()
 		return tea.Batch(p.readOutput(), p.scheduleRenderTick())
 
+	case StartedMsg:
+		if msg.PaneID != p.id || p.tmux == nil {
+			return nil
+		}
+		return p.waitTmux()
+
 	case RenderTickMsg:
 		if msg.PaneID != p.id {
 			return nil
@@ -451,8 +569,12 @@ func (p *Pane) captureScrollbackBeforeWrite() {
 		return
 	}
 
-	// Snapshot row 0
-	p.lastTopRow = make([]vt10x.Glyph, cols)
+	// Snapshot row 0. Push copies the line, so the snapshot's array can
+	// be reused for the next write.
+	if cap(p.topRowBuf) < cols {
+		p.topRowBuf = make([]vt10x.Glyph, cols)
+	}
+	p.lastTopRow = p.topRowBuf[:cols]
 	for col := 0; col < cols; col++ {
 		p.lastTopRow[col] = p.vt.Cell(col, 0)
 	}
@@ -665,7 +787,9 @@ func (p *Pane) HandleKey(msg tea.KeyMsg) tea.Msg {
 	// Check for selection copy FIRST (before forwarding Ctrl+C to PTY)
 	if p.selection != nil && p.selection.IsActive() {
 		if key == "ctrl+c" || key == "cmd+c" {
-			p.copySelectionUnlocked()
+			if text := p.copySelectionUnlocked(); text != "" {
+				return CopiedMsg{PaneID: p.id, Text: text}
+			}
 			return nil
 		}
 	}
@@ -728,11 +852,20 @@ func (p *Pane) HandleKey(msg tea.KeyMsg) tea.Msg {
 	return nil
 }
 
-// copySelectionUnlocked copies selected text to clipboard
+// Selection returns the text selected with the mouse, or "" when nothing
+// is selected. The selection is left in place.
+func (p *Pane) Selection() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.selectedTextUnlocked()
+}
+
+// selectedTextUnlocked extracts the selected text from the scrollback and
+// the live screen.
 // Called with mutex held.
-func (p *Pane) copySelectionUnlocked() {
-	if p.selection == nil || !p.selection.IsActive() {
-		return
+func (p *Pane) selectedTextUnlocked() string {
+	if p.selection == nil || !p.selection.IsActive() || p.vt == nil {
+		return ""
 	}
 
 	// Get scrollback lines for text extraction
@@ -746,14 +879,23 @@ func (p *Pane) copySelectionUnlocked() {
 	// Get live screen accessor
 	var liveRows int
 	p.vt.Lock()
+	defer p.vt.Unlock()
 	_, liveRows = p.vt.Size()
 	liveScreen := func(col, row int) vt10x.Glyph {
 		return p.vt.Cell(col, row)
 	}
 
-	text := p.selection.ExtractText(scrollbackLines, liveScreen, liveRows, scrollbackLen)
-	p.vt.Unlock()
+	return p.selection.ExtractText(scrollbackLines, liveScreen, liveRows, scrollbackLen)
+}
+
+// copySelectionUnlocked copies selected text to clipboard and returns it
+// Called with mutex held.
+func (p *Pane) copySelectionUnlocked() string {
+	if p.selection == nil || !p.selection.IsActive() {
+		return ""
+	}
 
+	text := p.selectedTextUnlocked()
 	if text != "" {
 		clipboard.WriteAll(text)
 	}
@@ -761,6 +903,7 @@ func (p *Pane) copySelectionUnlocked() {
 	// Clear selection after copy
 	p.selection.Clear()
 	p.dirty = true
+	return text
 }
 
 // scrollUp scrolls the viewport up (into scrollback history)
@@ -847,6 +990,9 @@ func (p *Pane) GetContent() string {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.tmux != nil {
+		return p.captureTmux(false)
+	}
 	if p.vt == nil {
 		return ""
 	}
@@ -876,6 +1022,121 @@ func (p *Pane) GetContent() string {
 	return result.String()
 }
 
+// Transcript returns the scrollback and the screen as plain text, with
+// trailing spaces and blank lines dropped
+func (p *Pane) Transcript() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tmux != nil {
+		return strings.TrimRight(p.tmuxHistory(false), "\n")
+	}
+	if p.vt == nil {
+		return ""
+	}
+
+	var lines []string
+	if p.scrollback != nil {
+		for _, line := range p.scrollback.GetRange(0, p.scrollback.Len()) {
+			lines = append(lines, glyphText(line))
+		}
+	}
+
+	p.vt.Lock()
+	cols, rows := p.vt.Size()
+	for row := 0; row < rows; row++ {
+		line := make([]vt10x.Glyph, cols)
+		for col := range line {
+			line[col] = p.vt.Cell(col, row)
+		}
+		lines = append(lines, glyphText(line))
+	}
+	p.vt.Unlock()
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// StyledTranscript is Transcript with the program's colors and text
+// attributes kept as ANSI escapes, for HTML
+func (p *Pane) StyledTranscript() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tmux != nil {
+		return strings.TrimRight(p.tmuxHistory(true), "\n")
+	}
+	if p.vt == nil {
+		return ""
+	}
+
+	var lines []string
+	if p.scrollback != nil {
+		for _, line := range p.scrollback.GetRange(0, p.scrollback.Len()) {
+			lines = append(lines, glyphANSI(line))
+		}
+	}
+
+	p.vt.Lock()
+	cols, rows := p.vt.Size()
+	for row := 0; row < rows; row++ {
+		line := make([]vt10x.Glyph, cols)
+		for col := range line {
+			line[col] = p.vt.Cell(col, row)
+		}
+		lines = append(lines, glyphANSI(line))
+	}
+	p.vt.Unlock()
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// glyphANSI writes a line with ANSI styling, without its trailing blank
+// cells
+func glyphANSI(line []vt10x.Glyph) string {
+	end := len(line)
+	for end > 0 {
+		g := line[end-1]
+		if (g.Char != 0 && g.Char != ' ') || g.BG < 0x01000000 || g.Mode&0x01 != 0 {
+			break
+		}
+		end--
+	}
+
+	var b strings.Builder
+	styled := false
+	for i, g := range line[:end] {
+		if i == 0 || g.FG != line[i-1].FG || g.BG != line[i-1].BG || g.Mode != line[i-1].Mode {
+			if styled {
+				b.WriteString("\x1b[0m")
+			}
+			seq := buildANSI(g.FG, g.BG, g.Mode)
+			b.WriteString(seq)
+			styled = seq != ""
+		}
+		if g.Char == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteRune(g.Char)
+		}
+	}
+	if styled {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+func glyphText(line []vt10x.Glyph) string {
+	var b strings.Builder
+	for _, g := range line {
+		if g.Char == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteRune(g.Char)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
 // --- Rendering (Issue #14) ---
 
 // View returns the rendered terminal content
@@ -883,6 +1144,10 @@ func (p *Pane) View() string {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.tmux != nil {
+		return p.captureTmux(true)
+	}
+
 	// Return cached view if not dirty
 	if !p.dirty && p.cachedView != "" {
 		return p.cachedView
@@ -989,11 +1254,19 @@ func (p *Pane) renderGlyphLine(line []vt10x.Glyph, cols int, logicalRow int) str
 		batch.Reset()
 	}
 
-	for col := 0; col < cols; col++ {
-		var glyph vt10x.Glyph
+	glyphAt := func(col int) vt10x.Glyph {
 		if col < len(line) {
-			glyph = line[col]
+			return line[col]
 		}
+		return vt10x.Glyph{}
+	}
+	var styles []*highlightStyle
+	if len(p.highlights) > 0 {
+		styles = rowStyles(p.highlights, cols, glyphAt)
+	}
+
+	for col := 0; col < cols; col++ {
+		glyph := styleGlyph(styles, col, glyphAt(col))
 		ch := glyph.Char
 		if ch == 0 {
 			ch = ' '
@@ -1048,8 +1321,13 @@ func (p *Pane) renderLiveRow(cols, row int, logicalRow int) string {
 	cursor := p.vt.Cursor()
 	cursorVisible := p.vt.CursorVisible()
 
+	var styles []*highlightStyle
+	if len(p.highlights) > 0 {
+		styles = rowStyles(p.highlights, cols, func(col int) vt10x.Glyph { return p.vt.Cell(col, row) })
+	}
+
 	for col := 0; col < cols; col++ {
-		glyph := p.vt.Cell(col, row)
+		glyph := styleGlyph(styles, col, p.vt.Cell(col, row))
 		ch := glyph.Char
 		if ch == 0 {
 			ch = ' '
@@ -1121,8 +1399,13 @@ func (p *Pane) renderLiveScreenUnlocked(cols, rows int) string {
 			batch.Reset()
 		}
 
+		var styles []*highlightStyle
+		if len(p.highlights) > 0 {
+			styles = rowStyles(p.highlights, cols, func(col int) vt10x.Glyph { return p.vt.Cell(col, row) })
+		}
+
 		for col := 0; col < cols; col++ {
-			glyph := p.vt.Cell(col, row)
+			glyph := styleGlyph(styles, col, p.vt.Cell(col, row))
 			ch := glyph.Char
 			if ch == 0 {
 				ch = ' '
@@ -1223,7 +1506,7 @@ func colorToANSI(c vt10x.Color, isFG bool) string {
 
 func buildCleanEnv(sessionName string) []string {
 	var env []string
-	for _, e := range os.Environ() {
+	for _, e := range secretenv.Environ() {
 		key := strings.Split(e, "=")[0]
 		if key == "OPENCODE" || strings.HasPrefix(key, "OPENCODE_") {
 			continue
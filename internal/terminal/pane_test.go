@@ -2,7 +2,13 @@ package terminal
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/hinshun/vt10x"
 )
 
 func TestDetectMouseModeChanges(t *testing.T) {
@@ -187,3 +193,107 @@ func TestViewportScrolling(t *testing.T) {
 		t.Errorf("scrollDown beyond 0 should cap at 0, got %d", pane.viewportOffset)
 	}
 }
+
+func TestReadOutput_PooledBuffer(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	pane := New("test", 20, 3, 100)
+	pane.vt = vt10x.New(vt10x.WithSize(20, 3))
+	pane.scrollback = NewScrollbackBuffer(100)
+	pane.pty = r
+
+	for i := 0; i < 6; i++ {
+		if _, err := fmt.Fprintf(w, "line%d\r\n", i); err != nil {
+			t.Fatal(err)
+		}
+		msg, ok := pane.readOutput()().(OutputMsg)
+		if !ok {
+			t.Fatal("expected OutputMsg")
+		}
+		if got, want := string(msg.Data), fmt.Sprintf("line%d\r\n", i); got != want {
+			t.Fatalf("read %q, want %q", got, want)
+		}
+		pane.Update(msg)
+	}
+
+	// Each scrolled-off line must be its own copy, not the reused snapshot
+	// or read buffer
+	if pane.scrollback.Len() != 4 {
+		t.Fatalf("expected 4 scrollback lines, got %d", pane.scrollback.Len())
+	}
+	for i := 0; i < 4; i++ {
+		got := strings.TrimSpace(lineToString(pane.scrollback.Get(i)))
+		if want := fmt.Sprintf("line%d", i); got != want {
+			t.Errorf("scrollback line %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+// runToExit starts command in a pane and reads its output until it exits
+func runToExit(t *testing.T, command string, args ...string) ExitMsg {
+	t.Helper()
+	pane := New("test", 80, 24, 100)
+	msg := pane.Start(command, args...)()
+	for i := 0; i < 100; i++ {
+		switch m := msg.(type) {
+		case ExitMsg:
+			return m
+		case OutputMsg:
+			pane.handleOutput(m.Data)
+			m.release()
+			msg = pane.readOutput()()
+		default:
+			t.Fatalf("unexpected message %#v", msg)
+		}
+	}
+	t.Fatal("pane did not exit")
+	return ExitMsg{}
+}
+
+func TestStart_ExitStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	if exit := runToExit(t, "sh", "-c", "echo done"); exit.Err != nil {
+		t.Errorf("clean exit: Err = %v; want nil", exit.Err)
+	}
+	if exit := runToExit(t, "sh", "-c", "echo failing; exit 3"); exit.Err == nil || exit.Err.Error() != "exit status 3" {
+		t.Errorf("failed exit: Err = %v; want exit status 3", exit.Err)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	pane := New("test", 20, 3, 100)
+	pane.vt = vt10x.New(vt10x.WithSize(20, 3))
+	pane.scrollback = NewScrollbackBuffer(100)
+	pane.scrollback.Push(makeTestLine("earlier"))
+	pane.vt.Write([]byte("done  \r\n$ "))
+
+	if got, want := pane.Transcript(), "earlier\ndone\n$"; got != want {
+		t.Errorf("Transcript() = %q, want %q", got, want)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	pane := New("test", 30, 2, 100)
+	pane.vt = vt10x.New(vt10x.WithSize(30, 2))
+	pane.vt.Write([]byte("ok\r\n\x1b[32mERROR: disk\x1b[0m"))
+	pane.SetHighlights([]Highlight{
+		{Pattern: regexp.MustCompile(`ERROR`), Color: "#ff0000"},
+		{Pattern: regexp.MustCompile(`disk`), Underline: true},
+	})
+
+	view := pane.View()
+	if !strings.Contains(view, "\x1b[38;2;255;0;0mERROR") {
+		t.Errorf("View() = %q; want ERROR in red", view)
+	}
+	// The program's green stays under the underline
+	if !strings.Contains(view, "\x1b[38;5;2;4mdisk") {
+		t.Errorf("View() = %q; want disk underlined in the program's color", view)
+	}
+}
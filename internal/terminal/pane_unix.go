@@ -0,0 +1,34 @@
+//go:build !windows
+
+package terminal
+
+import "syscall"
+
+// Pause stops the pane's process group with SIGSTOP so the agent and
+// anything it spawned stop touching the worktree until Resume.
+func (p *Pane) Pause() error {
+	return p.signalGroup(syscall.SIGSTOP)
+}
+
+// Resume continues a process group stopped by Pause.
+func (p *Pane) Resume() error {
+	return p.signalGroup(syscall.SIGCONT)
+}
+
+func (p *Pane) signalGroup(sig syscall.Signal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running && p.tmux != nil {
+		pid, err := p.tmuxPID()
+		if err != nil {
+			return err
+		}
+		return syscall.Kill(-pid, sig)
+	}
+	if !p.running || p.cmd == nil || p.cmd.Process == nil {
+		return ErrPaneNotRunning
+	}
+	// The pty makes the process a session leader, so its pid is the group id
+	return syscall.Kill(-p.cmd.Process.Pid, sig)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package terminal
+
+import "errors"
+
+var errPauseUnsupported = errors.New("pausing agents is not supported on Windows")
+
+// Pause is not supported on Windows.
+func (p *Pane) Pause() error {
+	return errPauseUnsupported
+}
+
+// Resume is not supported on Windows.
+func (p *Pane) Resume() error {
+	return errPauseUnsupported
+}
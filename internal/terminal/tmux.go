@@ -0,0 +1,353 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tmuxPollInterval is how often a tmux pane is checked for its agent exiting
+const tmuxPollInterval = time.Second
+
+// tmuxTicketOption is the window option tagging openkanban's windows with
+// their pane ID, so they can be picked up again after a restart
+const tmuxTicketOption = "@openkanban_ticket"
+
+// tmuxExitOption is the window option the agent's command records its exit
+// status in. Some tmux versions, such as 3.3a, leave #{pane_dead_status}
+// empty.
+const tmuxExitOption = "@openkanban_exit"
+
+// StartedMsg reports that an agent started in a tmux window
+type StartedMsg struct {
+	PaneID string
+}
+
+// TmuxTarget places a pane's agent in a window of a tmux session
+type TmuxTarget struct {
+	Session string
+	Window  string
+	Dead    bool   // the agent exited, as found by FindTmuxPanes
+	pane    string // tmux pane ID such as "%12", set once started
+}
+
+// TmuxAvailable reports whether the tmux binary can be found
+func TmuxAvailable() bool {
+	_, err := exec.LookPath("tmux")
+	return err == nil
+}
+
+// InsideTmux reports whether openkanban itself runs inside a tmux client
+func InsideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+func tmux(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("tmux %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SetTmux makes Start run the agent in a window of the tmux session instead
+// of the embedded terminal. The session is created when missing.
+func (p *Pane) SetTmux(session, window string) {
+	p.tmux = &TmuxTarget{Session: session, Window: window}
+}
+
+// Tmux returns the pane's tmux target, or nil for embedded panes
+func (p *Pane) Tmux() *TmuxTarget {
+	return p.tmux
+}
+
+// AdoptTmux returns a running pane for an agent window left behind by an
+// earlier openkanban, and the Cmd that waits for it to exit
+func AdoptTmux(id string, target TmuxTarget, width, height int) (*Pane, tea.Cmd) {
+	p := New(id, width, height, 0)
+	p.tmux = &target
+	p.running = true
+	return p, p.waitTmux()
+}
+
+// FindTmuxPanes lists the agent windows openkanban started, by pane ID
+func FindTmuxPanes() (map[string]TmuxTarget, error) {
+	out, err := tmux("list-panes", "-a", "-F",
+		"#{pane_id}\t#{"+tmuxTicketOption+"}\t#{session_name}\t#{window_name}\t#{pane_dead}")
+	if err != nil {
+		// No server running means no windows
+		if strings.Contains(err.Error(), "no server running") || strings.Contains(err.Error(), "error connecting") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	targets := make(map[string]TmuxTarget)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 || fields[1] == "" {
+			continue
+		}
+		targets[fields[1]] = TmuxTarget{Session: fields[2], Window: fields[3], Dead: fields[4] == "1", pane: fields[0]}
+	}
+	return targets, nil
+}
+
+// startTmux opens the agent's window. It is called with mu held.
+func (p *Pane) startTmux(command string, args []string) tea.Msg {
+	// tmux runs a single command string through the shell in every version,
+	// and env -i gives the agent the same clean environment as a pty. The
+	// variables go through a private file the command reads and removes,
+	// since ps and #{pane_start_command} show the command line to anyone.
+	var env []string
+	for _, e := range append(buildCleanEnv(p.sessionName), p.env...) {
+		if key, _, _ := strings.Cut(e, "="); key != "TERM" && key != "TMUX" && key != "TMUX_PANE" {
+			env = append(env, e)
+		}
+	}
+	env = append(env, "TERM="+tmuxTerm())
+	envFile, err := writeEnvFile(env)
+	if err != nil {
+		p.exitErr = err
+		return ExitMsg{PaneID: p.id, Err: err}
+	}
+	argv := []string{"env", "-i", "sh", "-c", `set -a; . "$0"; rm -f -- "$0"; exec "$@"`, envFile, command}
+	argv = append(argv, args...)
+	// sh records the exit status, whatever the user's default shell
+	shellCmd := shellJoin([]string{"sh", "-c",
+		shellJoin(argv) + `; tmux set-option -w -t "$TMUX_PANE" ` + tmuxExitOption + ` $?`})
+
+	t := p.tmux
+	var out string
+	if _, hasErr := tmux("has-session", "-t", "="+t.Session); hasErr == nil {
+		out, err = tmux("new-window", "-d", "-P", "-F", "#{pane_id}",
+			"-t", t.Session+":", "-n", t.Window, "-c", p.workdir, shellCmd)
+	} else {
+		out, err = tmux("new-session", "-d", "-P", "-F", "#{pane_id}",
+			"-s", t.Session, "-n", t.Window, "-c", p.workdir, shellCmd)
+	}
+	if err != nil {
+		_ = os.Remove(envFile)
+		p.exitErr = err
+		return ExitMsg{PaneID: p.id, Err: err}
+	}
+	t.pane = out
+
+	// Keep the window after the agent exits so its exit status can be read,
+	// and tag it so a restarted board finds it again
+	for _, option := range [][2]string{{"remain-on-exit", "on"}, {tmuxTicketOption, p.id}} {
+		if _, err := tmux("set-option", "-w", "-t", t.pane, option[0], option[1]); err != nil {
+			slog.Warn("failed to set tmux window option", "option", option[0], "pane", t.pane, "err", err)
+		}
+	}
+
+	p.running = true
+	p.exitErr = nil
+	return StartedMsg{PaneID: p.id}
+}
+
+// tmuxTerm is the TERM tmux gives its panes
+func tmuxTerm() string {
+	if term, err := tmux("show-options", "-gv", "default-terminal"); err == nil && term != "" {
+		return term
+	}
+	return "screen-256color"
+}
+
+// waitTmux polls the tmux pane until its agent exits, then closes the window
+// and reports the exit like a pty would
+func (p *Pane) waitTmux() tea.Cmd {
+	paneID := p.id
+	target := p.tmux.pane
+	return func() tea.Msg {
+		for {
+			out, err := tmux("display-message", "-p", "-t", target,
+				"#{pane_dead}|#{pane_dead_status}|#{"+tmuxExitOption+"}")
+			if err != nil {
+				// Closing the window is how the board stops an agent
+				if !p.Running() {
+					return ExitMsg{PaneID: paneID}
+				}
+				// Closed from tmux, or the server went away
+				return ExitMsg{PaneID: paneID, Err: fmt.Errorf("lost the agent's tmux window: %w", err)}
+			}
+			fields := strings.Split(out, "|")
+			if fields[0] == "1" && len(fields) == 3 {
+				_, _ = tmux("kill-pane", "-t", target)
+				return ExitMsg{PaneID: paneID, Err: tmuxExitErr(fields[1], fields[2])}
+			}
+			time.Sleep(tmuxPollInterval)
+		}
+	}
+}
+
+// tmuxExitErr turns the exit status tmux reports, or failing that the one
+// the agent's command recorded, into an ExitMsg error. An unknown status
+// counts as a failure.
+func tmuxExitErr(status, recorded string) error {
+	if status == "" {
+		status = recorded
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return errors.New("exit status unknown")
+	}
+	if code != 0 {
+		return fmt.Errorf("exit status %d", code)
+	}
+	return nil
+}
+
+// stopTmux closes the agent's window. It is called with mu held.
+func (p *Pane) stopTmux() {
+	if p.tmux.pane != "" {
+		_, _ = tmux("kill-pane", "-t", p.tmux.pane)
+	}
+	p.running = false
+}
+
+// tmuxPID returns the pid of the process running in the tmux pane. tmux
+// makes it a session leader, so it is also the process group id.
+func (p *Pane) tmuxPID() (int, error) {
+	out, err := tmux("display-message", "-p", "-t", p.tmux.pane, "#{pane_pid}")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
+
+// tmuxHistory returns the tmux pane's history and screen, with wrapped
+// lines joined and with colors when escapes is set
+func (p *Pane) tmuxHistory(escapes bool) string {
+	if p.tmux.pane == "" {
+		return ""
+	}
+	args := []string{"capture-pane", "-p", "-J", "-S", "-", "-t", p.tmux.pane}
+	if escapes {
+		args = append(args, "-e")
+	}
+	out, err := tmux(args...)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// captureTmux returns the visible contents of the tmux pane, with colors
+// when escapes is set
+func (p *Pane) captureTmux(escapes bool) string {
+	if p.tmux.pane == "" {
+		return ""
+	}
+	args := []string{"capture-pane", "-p", "-t", p.tmux.pane}
+	if escapes {
+		args = append(args, "-e")
+	}
+	out, err := tmux(args...)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// AttachCmd returns the command that shows the agent's window: switching
+// the current client when openkanban runs inside tmux, attaching otherwise
+func (t *TmuxTarget) AttachCmd() (*exec.Cmd, error) {
+	if t.pane == "" {
+		return nil, ErrPaneNotRunning
+	}
+	if _, err := tmux("select-window", "-t", t.pane); err != nil {
+		return nil, err
+	}
+	if InsideTmux() {
+		return exec.Command("tmux", "switch-client", "-t", t.pane), nil
+	}
+	return exec.Command("tmux", "attach-session", "-t", t.pane), nil
+}
+
+// Kill closes the window, stopping its agent
+func (t *TmuxTarget) Kill() error {
+	if t.pane == "" {
+		return ErrPaneNotRunning
+	}
+	_, err := tmux("kill-pane", "-t", t.pane)
+	return err
+}
+
+// Retag hands the window to another pane ID, so AdoptTmux and later
+// restarts pick it up for that ticket
+func (t *TmuxTarget) Retag(id string) error {
+	if t.pane == "" {
+		return ErrPaneNotRunning
+	}
+	_, err := tmux("set-option", "-w", "-t", t.pane, tmuxTicketOption, id)
+	return err
+}
+
+// String returns the session and window, as shown to users
+func (t *TmuxTarget) String() string {
+	return t.Session + ":" + t.Window
+}
+
+// writeEnvFile writes env as shell assignments to a file only the user can
+// read, skipping variables sh cannot assign, and returns its path
+func writeEnvFile(env []string) (string, error) {
+	var b strings.Builder
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		if !isShellName(key) {
+			continue
+		}
+		b.WriteString(key + "=" + shellJoin([]string{value}) + "\n")
+	}
+	f, err := os.CreateTemp("", "openkanban-env-")
+	if err != nil {
+		return "", fmt.Errorf("failed to write agent environment: %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write agent environment: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write agent environment: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// isShellName reports whether name is a valid shell variable name
+func isShellName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// shellJoin quotes args into a single POSIX shell command line
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,165 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolatedTmux points tmux at a private server for the test
+func isolatedTmux(t *testing.T) {
+	t.Helper()
+	if !TmuxAvailable() {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _, _ = tmux("kill-server") })
+}
+
+func TestTmuxPane_Lifecycle(t *testing.T) {
+	isolatedTmux(t)
+
+	p := New("ticket-1", 80, 24, 0)
+	p.SetWorkdir(t.TempDir())
+	p.SetSessionName("fix-login")
+	p.SetTmux("openkanban-test", "fix-login")
+	msg := p.Start("sh", "-c", "echo hello from $OPENKANBAN_SESSION; sleep 1; exit 3")()
+	if _, ok := msg.(StartedMsg); !ok {
+		t.Fatalf("Start() = %#v; want StartedMsg", msg)
+	}
+
+	found, err := FindTmuxPanes()
+	if err != nil || found["ticket-1"].Session != "openkanban-test" || found["ticket-1"].Window != "fix-login" {
+		t.Fatalf("FindTmuxPanes() = %v, %v", found, err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(p.GetContent(), "hello from") && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !strings.Contains(p.GetContent(), "hello from") {
+		t.Errorf("GetContent() = %q; want the agent's output", p.GetContent())
+	}
+
+	exit, ok := p.Update(msg)().(ExitMsg)
+	if !ok || exit.Err == nil || exit.Err.Error() != "exit status 3" {
+		t.Errorf("wait = %#v; want exit status 3", exit)
+	}
+	if found, _ := FindTmuxPanes(); len(found) != 0 {
+		t.Errorf("window left behind after exit: %v", found)
+	}
+}
+
+func TestTmuxPane_EnvOffCommandLine(t *testing.T) {
+	isolatedTmux(t)
+
+	p := New("ticket-env", 80, 24, 0)
+	p.SetEnv([]string{"OPENKANBAN_CONTROL_TOKEN=s3cr'et", "BAD-NAME=x"})
+	p.SetTmux("openkanban-test", "env")
+	if _, ok := p.Start("sh", "-c", `echo "token=$OPENKANBAN_CONTROL_TOKEN"; sleep 5`)().(StartedMsg); !ok {
+		t.Fatal("Start() did not start the window")
+	}
+	defer func() { _ = p.Stop() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(p.GetContent(), "token=") && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !strings.Contains(p.GetContent(), "token=s3cr'et") {
+		t.Errorf("GetContent() = %q; want the agent to see its environment", p.GetContent())
+	}
+	start, err := tmux("display-message", "-p", "-t", p.tmux.pane, "#{pane_start_command}")
+	if err != nil || strings.Contains(start, "s3cr") {
+		t.Errorf("pane_start_command = %q, %v; want no secrets on the command line", start, err)
+	}
+}
+
+func TestTmuxPane_StopAndAdopt(t *testing.T) {
+	isolatedTmux(t)
+
+	p := New("ticket-2", 80, 24, 0)
+	p.SetTmux("openkanban-test", "long")
+	if _, ok := p.Start("sleep", "60")().(StartedMsg); !ok {
+		t.Fatal("Start() did not start the window")
+	}
+
+	// A restarted board picks the window up again
+	found, _ := FindTmuxPanes()
+	adopted, wait := AdoptTmux("ticket-2", found["ticket-2"], 80, 24)
+	if !adopted.Running() {
+		t.Fatal("adopted pane is not running")
+	}
+
+	if err := adopted.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if exit := wait().(ExitMsg); exit.Err != nil {
+		t.Errorf("wait after Stop = %v; want a clean exit", exit.Err)
+	}
+}
+
+func TestTmuxTarget_RetagAndKill(t *testing.T) {
+	isolatedTmux(t)
+
+	p := New("deleted-ticket", 80, 24, 0)
+	p.SetTmux("openkanban-test", "orphan")
+	if _, ok := p.Start("sleep", "60")().(StartedMsg); !ok {
+		t.Fatal("Start() did not start the window")
+	}
+
+	found, _ := FindTmuxPanes()
+	orphan := found["deleted-ticket"]
+	if orphan.Dead {
+		t.Fatal("running window reported dead")
+	}
+	if err := orphan.Retag("ticket-3"); err != nil {
+		t.Fatalf("Retag() error = %v", err)
+	}
+	found, _ = FindTmuxPanes()
+	if _, ok := found["deleted-ticket"]; ok {
+		t.Error("window still tagged with the old ticket")
+	}
+	adopted, ok := found["ticket-3"]
+	if !ok {
+		t.Fatalf("FindTmuxPanes() = %v; want the window under ticket-3", found)
+	}
+
+	if err := adopted.Kill(); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if found, _ := FindTmuxPanes(); len(found) != 0 {
+		t.Errorf("window left behind after Kill: %v", found)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := shellJoin([]string{"claude", "it's a \"prompt\"", "$HOME"})
+	if got != `'claude' 'it'\''s a "prompt"' '$HOME'` {
+		t.Errorf("shellJoin() = %s", got)
+	}
+}
+
+func TestTmuxExitErr(t *testing.T) {
+	tests := []struct {
+		status, recorded string
+		want             string
+	}{
+		{"0", "", ""},
+		{"3", "", "exit status 3"},
+		{"", "0", ""},
+		{"", "3", "exit status 3"},
+		{"", "", "exit status unknown"},
+		{"x", "", "exit status unknown"},
+	}
+	for _, tt := range tests {
+		err := tmuxExitErr(tt.status, tt.recorded)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("tmuxExitErr(%q, %q) = %q; want %q", tt.status, tt.recorded, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+)
+
+// Budget is the most a benchmark may spend per operation before it counts
+// as a regression. Zero fields are not checked.
+type Budget struct {
+	PerOp  time.Duration
+	Allocs int64
+}
+
+// CheckBudget runs bench and fails t if it goes over budget. Budgets are
+// generous so they catch regressions, not slow machines; run them without
+// -race, which inflates timings several times over.
+func CheckBudget(t *testing.T, bench func(*testing.B), budget Budget) {
+	t.Helper()
+
+	result := testing.Benchmark(bench)
+	if result.N == 0 {
+		t.Fatal("benchmark did not run")
+	}
+	perOp := time.Duration(result.NsPerOp())
+	t.Logf("%s/op, %d allocs/op", perOp, result.AllocsPerOp())
+
+	if budget.PerOp > 0 && perOp > budget.PerOp {
+		t.Errorf("%s/op is over the %s budget", perOp, budget.PerOp)
+	}
+	if budget.Allocs > 0 && result.AllocsPerOp() > budget.Allocs {
+		t.Errorf("%d allocs/op is over the budget of %d", result.AllocsPerOp(), budget.Allocs)
+	}
+}
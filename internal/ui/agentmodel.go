@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// modelChoices lists the models the ticket form offers for the selected
+// agent. The first, "", runs the agent's own model; a model set on the
+// ticket that the agent no longer lists stays selectable.
+func (m *Model) modelChoices() []string {
+	choices := []string{""}
+	for _, opt := range m.config.Agents[m.ticketAgent].Models {
+		choices = append(choices, opt.Name)
+	}
+	if !slices.Contains(choices, m.ticketModel) {
+		choices = append(choices, m.ticketModel)
+	}
+	return choices
+}
+
+func (m *Model) handleModelNav(msg tea.KeyMsg) tea.Cmd {
+	choices := m.modelChoices()
+	i := slices.Index(choices, m.ticketModel)
+	switch msg.String() {
+	case "j", "down", "l", "right":
+		i = (i + 1) % len(choices)
+	case "k", "up", "h", "left":
+		i = (i + len(choices) - 1) % len(choices)
+	}
+	m.ticketModel = choices[i]
+	return nil
+}
+
+// resetTicketModel drops the selected model when the agent changes to one
+// that doesn't list it
+func (m *Model) resetTicketModel() {
+	models := m.config.Agents[m.ticketAgent].Models
+	if !slices.ContainsFunc(models, func(o config.ModelOption) bool { return o.Name == m.ticketModel }) {
+		m.ticketModel = ""
+	}
+}
+
+func (m *Model) renderModelSelector() string {
+	agentCfg := m.config.Agents[m.ticketAgent]
+	tierStyle := lipgloss.NewStyle().Foreground(m.colors.warning)
+
+	var parts []string
+	for _, name := range m.modelChoices() {
+		label, tier := name, agentCfg.ModelTier(name)
+		if name == "" {
+			label, tier = "default", agentCfg.ModelTier(agentCfg.Model)
+			if agentCfg.Model != "" {
+				label += " (" + agentCfg.Model + ")"
+			}
+		}
+		style := lipgloss.NewStyle().Foreground(m.colors.primary)
+		option := "○ " + label
+		if m.ticketModel == name {
+			style = style.Bold(true).Background(m.colors.surface).Padding(0, 1)
+			option = "● " + label
+		}
+		part := style.Render(option)
+		if tier != "" {
+			part += " " + tierStyle.Render(tier)
+		}
+		parts = append(parts, part)
+	}
+
+	hint := ""
+	if m.ticketFormField == formFieldModel {
+		hint = "  " + m.dimStyle().Render("← → to select")
+	}
+	return strings.Join(parts, "  ") + hint
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type autosaveTickMsg time.Time
+
+func tickAutosave(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return autosaveTickMsg(t)
+	})
+}
+
+func (m *Model) autosaveEnabled() bool {
+	return m.config.Behavior.AutosaveInterval > 0
+}
+
+// autosaveInterval follows behavior.autosave_interval; with autosave off
+// the tick still runs, slowly, so turning it on in settings works
+func (m *Model) autosaveInterval() time.Duration {
+	if !m.autosaveEnabled() {
+		return 10 * time.Second
+	}
+	return time.Duration(m.config.Behavior.AutosaveInterval) * time.Second
+}
+
+func (m *Model) handleAutosaveTick() (tea.Model, tea.Cmd) {
+	m.flushTickets()
+	return m, tickAutosave(m.autosaveInterval())
+}
+
+// flushTickets writes the ticket changes waiting for autosave. A failure
+// is reported once, not on every tick it keeps failing.
+func (m *Model) flushTickets() {
+	if !m.globalStore.Dirty() {
+		return
+	}
+	err := m.globalStore.SaveDirty()
+	if err == nil {
+		m.autosaveErr = ""
+		return
+	}
+	if err.Error() != m.autosaveErr {
+		m.autosaveErr = err.Error()
+		m.notify("Failed to save: " + err.Error())
+	}
+}
+
+// renderSaveState is the status bar's "unsaved changes" or "saved 5s ago"
+func (m *Model) renderSaveState() string {
+	if m.globalStore.Dirty() {
+		return lipgloss.NewStyle().Foreground(m.colors.warning).Render("● unsaved changes")
+	}
+	savedAt := m.globalStore.SavedAt()
+	if savedAt.IsZero() {
+		return ""
+	}
+	return m.dimStyle().Render("saved " + savedAgo(time.Since(savedAt)))
+}
+
+func savedAgo(d time.Duration) string {
+	switch {
+	case d < 5*time.Second:
+		return "just now"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh ago", int(d.Hours()))
+}
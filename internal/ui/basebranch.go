@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// baseBranchesMsg carries the branches of a project for the ticket form's
+// base branch field. The first listing shows the local state; a second one
+// follows once the remotes have been fetched.
+type baseBranchesMsg struct {
+	projectID     string
+	branches      []git.Branch
+	defaultBranch string
+	fetched       bool
+	err           error
+}
+
+// resetBaseBranches clears the base branch field when the form opens
+func (m *Model) resetBaseBranches(base string) {
+	m.ticketBaseBranch = base
+	m.baseBranches = nil
+	m.baseBranchProject = ""
+	m.baseBranchIndex = 0
+	m.baseBranchErr = ""
+	m.baseFilterInput.Reset()
+}
+
+// loadBaseBranches lists the selected project's branches unless they are
+// already listed. Switching projects clears the chosen base.
+func (m *Model) loadBaseBranches() tea.Cmd {
+	if m.selectedProject == nil || m.baseBranchProject == m.selectedProject.ID {
+		return nil
+	}
+	if m.baseBranchProject != "" {
+		m.ticketBaseBranch = ""
+	}
+	m.baseBranchProject = m.selectedProject.ID
+	m.baseBranches = nil
+	m.baseBranchIndex = 0
+	m.baseBranchErr = ""
+	m.baseBranchLoading = true
+	return listBaseBranches(m.selectedProject.ID, m.worktreeMgrs[m.selectedProject.ID], false)
+}
+
+func listBaseBranches(projectID string, mgr *git.WorktreeManager, fetch bool) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return baseBranchesMsg{projectID: projectID, fetched: true, err: fmt.Errorf("worktree manager not found")}
+		}
+		var fetchErr error
+		if fetch {
+			fetchErr = mgr.FetchAll()
+		}
+		branches, err := mgr.Branches()
+		if err == nil {
+			err = fetchErr
+		}
+		defaultBranch, _ := mgr.GetDefaultBranch()
+		return baseBranchesMsg{projectID: projectID, branches: branches, defaultBranch: defaultBranch, fetched: fetch, err: err}
+	}
+}
+
+func (m *Model) handleBaseBranches(msg baseBranchesMsg) (tea.Model, tea.Cmd) {
+	if msg.projectID != m.baseBranchProject {
+		return m, nil
+	}
+	m.baseBranches = msg.branches
+	m.baseBranchErr = ""
+	if msg.err != nil {
+		m.baseBranchErr = msg.err.Error()
+	}
+	if m.ticketBaseBranch == "" {
+		m.ticketBaseBranch = msg.defaultBranch
+	}
+	m.syncBaseBranchIndex()
+
+	if !msg.fetched {
+		return m, listBaseBranches(msg.projectID, m.worktreeMgrs[msg.projectID], true)
+	}
+	m.baseBranchLoading = false
+	return m, nil
+}
+
+// filteredBaseBranches returns the branches matching the filter input
+func (m *Model) filteredBaseBranches() []git.Branch {
+	query := strings.ToLower(strings.TrimSpace(m.baseFilterInput.Value()))
+	if query == "" {
+		return m.baseBranches
+	}
+	var matches []git.Branch
+	for _, b := range m.baseBranches {
+		if strings.Contains(strings.ToLower(b.Name), query) {
+			matches = append(matches, b)
+		}
+	}
+	return matches
+}
+
+// syncBaseBranchIndex points the cursor at the chosen base
+func (m *Model) syncBaseBranchIndex() {
+	for i, b := range m.filteredBaseBranches() {
+		if b.Name == m.ticketBaseBranch {
+			m.baseBranchIndex = i
+			return
+		}
+	}
+	m.baseBranchIndex = 0
+}
+
+// selectedBaseBranch returns the chosen base from the listing, if listed
+func (m *Model) selectedBaseBranch() (git.Branch, bool) {
+	for _, b := range m.baseBranches {
+		if b.Name == m.ticketBaseBranch {
+			return b, true
+		}
+	}
+	return git.Branch{}, false
+}
+
+func (m *Model) handleBaseBranchNav(msg tea.KeyMsg) tea.Cmd {
+	visible := m.filteredBaseBranches()
+
+	switch msg.Type {
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyUp, tea.KeyCtrlP:
+		if len(visible) == 0 {
+			return nil
+		}
+		if msg.Type == tea.KeyDown || msg.Type == tea.KeyCtrlN {
+			m.baseBranchIndex = (m.baseBranchIndex + 1) % len(visible)
+		} else {
+			m.baseBranchIndex = (m.baseBranchIndex - 1 + len(visible)) % len(visible)
+		}
+		m.ticketBaseBranch = visible[m.baseBranchIndex].Name
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.baseFilterInput, cmd = m.baseFilterInput.Update(msg)
+	visible = m.filteredBaseBranches()
+	m.baseBranchIndex = 0
+	if len(visible) > 0 {
+		m.ticketBaseBranch = visible[0].Name
+	}
+	return cmd
+}
+
+// resolveBaseBranch turns the chosen base into a local branch, creating a
+// tracking branch for a remote-only choice, and records how far it was
+// behind its upstream. With branch creation deferred the remote choice is
+// kept as is, for localBase to resolve on the first spawn.
+func (m *Model) resolveBaseBranch(ticket *board.Ticket) {
+	if m.ticketBaseBranch == "" || m.selectedProject == nil {
+		return
+	}
+	branch, listed := m.selectedBaseBranch()
+	base := m.ticketBaseBranch
+	// A deferred ticket keeps the remote name until its first spawn
+	if listed && branch.Remote && m.createsBranchOnStart(m.selectedProject.ID) {
+		mgr := m.worktreeMgrs[m.selectedProject.ID]
+		if mgr == nil {
+			return
+		}
+		local, err := mgr.LocalBase(base)
+		if err != nil {
+			m.notify("Failed to use base " + base + ": " + err.Error())
+			return
+		}
+		base = local
+	}
+	ticket.BaseBranch = base
+	ticket.BaseBehind = branch.Behind
+}
+
+// localBase returns the local branch a new branch starts from: the ticket's
+// chosen base, given a local tracking branch if it is still a remote one,
+// or base when the ticket has none
+func localBase(mgr *git.WorktreeManager, base, chosen string) (string, error) {
+	if chosen == "" {
+		return base, nil
+	}
+	if !mgr.IsRemoteBranch(chosen) {
+		return chosen, nil
+	}
+	local, err := mgr.LocalBase(chosen)
+	if err != nil {
+		return "", fmt.Errorf("base branch: %w", err)
+	}
+	return local, nil
+}
+
+// staleBaseWarning checks the ticket's base branch against its upstream as
+// of the last fetch, records the result and describes a stale base
+func (m *Model) staleBaseWarning(ticket *board.Ticket) string {
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		return ""
+	}
+	base := ticket.BaseBranch
+	if base == "" {
+		base, _ = mgr.GetDefaultBranch()
+	}
+	branch, err := mgr.BranchStatus(base)
+	if err != nil {
+		return ""
+	}
+	if branch.Behind != ticket.BaseBehind {
+		ticket.BaseBehind = branch.Behind
+		m.saveTicket(ticket)
+	}
+	if !branch.Stale() {
+		return ""
+	}
+	return fmt.Sprintf("Base %s is %d commit(s) behind %s.", base, branch.Behind, branch.Upstream)
+}
+
+func (m *Model) renderBaseBranchSelector() string {
+	warnStyle := lipgloss.NewStyle().Foreground(m.colors.warning)
+
+	if m.ticketFormField != formFieldBase {
+		if m.ticketBaseBranch == "" {
+			return m.dimStyle().Render("Project default branch")
+		}
+		line := lipgloss.NewStyle().Foreground(m.colors.info).Render(m.ticketBaseBranch)
+		if branch, ok := m.selectedBaseBranch(); ok {
+			line += m.branchTrack(branch)
+		}
+		return line
+	}
+
+	lines := []string{m.baseFilterInput.View(), ""}
+
+	visible := m.filteredBaseBranches()
+	if len(visible) == 0 {
+		switch {
+		case m.baseBranchLoading:
+			lines = append(lines, m.spinner.View()+m.dimStyle().Render(" Listing branches..."))
+		case len(m.baseBranches) > 0:
+			lines = append(lines, m.dimStyle().Render("No matching branches"))
+		}
+	}
+
+	const maxVisible = 5
+	start := max(min(m.baseBranchIndex-maxVisible/2, len(visible)-maxVisible), 0)
+	end := min(start+maxVisible, len(visible))
+	for i := start; i < end; i++ {
+		b := visible[i]
+		cursor := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == m.baseBranchIndex {
+			cursor = lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
+			nameStyle = nameStyle.Bold(true).Foreground(m.colors.info)
+		}
+		line := cursor + nameStyle.Render(b.Name)
+		if b.Remote {
+			line += m.dimStyle().Render("  remote")
+		}
+		lines = append(lines, line+m.branchTrack(b))
+	}
+	if len(visible) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(visible)-end)))
+	}
+
+	switch {
+	case m.baseBranchErr != "":
+		lines = append(lines, warnStyle.Render(truncate("⚠ "+m.baseBranchErr, 50)))
+	case m.baseBranchLoading && len(visible) > 0:
+		lines = append(lines, m.spinner.View()+m.dimStyle().Render(" Fetching remotes..."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// branchTrack renders how a branch compares with its upstream
+func (m *Model) branchTrack(b git.Branch) string {
+	var parts []string
+	if b.Ahead > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.success).Render(fmt.Sprintf("↑%d", b.Ahead)))
+	}
+	if b.Behind > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("↓%d stale", b.Behind)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(parts, " ") + m.dimStyle().Render(" vs "+b.Upstream)
+}
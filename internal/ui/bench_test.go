@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/agent"
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+var benchStatuses = []board.TicketStatus{board.StatusBacklog, board.StatusInProgress, board.StatusDone}
+
+// newBenchModel is a board of n tickets spread over the default columns and
+// three projects, sized like a large terminal
+func newBenchModel(tb testing.TB, n int) *Model {
+	tb.Helper()
+	tb.Setenv("OPENKANBAN_CONFIG_DIR", tb.TempDir())
+
+	registry, err := project.LoadRegistry()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	store := project.NewGlobalTicketStore(registry)
+	var projects []*project.Project
+	for i := 0; i < 3; i++ {
+		p := project.NewProject(fmt.Sprintf("project-%d", i), tb.TempDir())
+		store.AddProject(p)
+		projects = append(projects, p)
+	}
+	for i := 0; i < n; i++ {
+		ticket := board.NewTicket(fmt.Sprintf("Ticket %d: tidy up the render path", i), projects[i%len(projects)].ID)
+		ticket.Status = benchStatuses[i%len(benchStatuses)]
+		ticket.Labels = []string{"perf"}
+		if err := store.Add(ticket); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	m := NewModel(cfg, store, registry, agent.NewManager(cfg), nil, "", nil)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 60})
+	return m
+}
+
+func BenchmarkView_1000Tickets(b *testing.B) {
+	m := newBenchModel(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.View()
+	}
+}
+
+// BenchmarkRefreshColumns_1000Tickets re-sorts and re-filters the board, which
+// every ticket change does
+func BenchmarkRefreshColumns_1000Tickets(b *testing.B) {
+	m := newBenchModel(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.refreshColumnTickets()
+	}
+}
@@ -0,0 +1,20 @@
+//go:build perf
+
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/testutil"
+)
+
+// A 1000 ticket board has to draw inside a 60fps frame, with room left for
+// the panes and everything else a frame does.
+func TestView_1000Tickets_Budget(t *testing.T) {
+	testutil.CheckBudget(t, BenchmarkView_1000Tickets, testutil.Budget{PerOp: 16 * time.Millisecond, Allocs: 40000})
+}
+
+func TestRefreshColumns_1000Tickets_Budget(t *testing.T) {
+	testutil.CheckBudget(t, BenchmarkRefreshColumns_1000Tickets, testutil.Budget{PerOp: 2 * time.Millisecond, Allocs: 1000})
+}
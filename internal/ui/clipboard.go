@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands are tried in order; the first one installed whose
+// display server is available wins
+var clipboardCommands = []struct {
+	display string // environment variable that must be set, if any
+	args    []string
+}{
+	{"", []string{"pbcopy"}},
+	{"WAYLAND_DISPLAY", []string{"wl-copy"}},
+	{"DISPLAY", []string{"xclip", "-selection", "clipboard"}},
+	{"DISPLAY", []string{"xsel", "--clipboard", "--input"}},
+	{"", []string{"clip.exe"}},
+}
+
+// copyToClipboard copies text with the platform's clipboard tool. Without
+// one, the text is sent to the terminal as an OSC 52 sequence, which most
+// terminals (and tmux with set-clipboard on) honor, also over SSH.
+func copyToClipboard(text string) error {
+	for _, c := range clipboardCommands {
+		if c.display != "" && os.Getenv(c.display) == "" {
+			continue
+		}
+		args := c.args
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		// Output is not captured: wl-copy and xclip stay running in the
+		// background to serve the selection and would hold the pipes open
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", args[0], err)
+		}
+		return nil
+	}
+
+	_, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
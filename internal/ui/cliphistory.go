@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// clipHistoryLimit caps how many selections copied from panes are kept
+const clipHistoryLimit = 30
+
+// clip is text copied from an agent pane
+type clip struct {
+	text     string
+	ticketID board.TicketID
+	at       time.Time
+}
+
+// clipsView is the clipboard history panel
+type clipsView struct {
+	index  int
+	offset int
+}
+
+// recordClip adds text copied from a ticket's pane to the history, moving
+// it to the top if it was copied before
+func (m *Model) recordClip(ticketID board.TicketID, text string) {
+	for i, c := range m.clips {
+		if c.text == text {
+			m.clips = append(m.clips[:i], m.clips[i+1:]...)
+			break
+		}
+	}
+	m.clips = append(m.clips, clip{text: text, ticketID: ticketID, at: time.Now()})
+	if len(m.clips) > clipHistoryLimit {
+		m.clips = m.clips[len(m.clips)-clipHistoryLimit:]
+	}
+}
+
+func (m *Model) openClips() (tea.Model, tea.Cmd) {
+	if len(m.clips) == 0 {
+		m.notify("Nothing copied yet; select text in an agent pane and press ctrl+c")
+		return m, nil
+	}
+	m.clipsPanel = &clipsView{}
+	m.mode = ModeClipboard
+	return m, nil
+}
+
+// selectedClip returns the highlighted entry; the panel lists newest first
+func (m *Model) selectedClip() (clip, bool) {
+	i := len(m.clips) - 1 - m.clipsPanel.index
+	if i < 0 || i >= len(m.clips) {
+		return clip{}, false
+	}
+	return m.clips[i], true
+}
+
+func (m *Model) handleClipsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.clipsPanel
+	height := m.clipsListHeight()
+	count := len(m.clips)
+	switch msg.String() {
+	case "esc", "q":
+		m.closeClips()
+	case "j", "down":
+		v.selectItem(v.index+1, count, height)
+	case "k", "up":
+		v.selectItem(v.index-1, count, height)
+	case "g":
+		v.selectItem(0, count, height)
+	case "G":
+		v.selectItem(count-1, count, height)
+	case "y":
+		if c, ok := m.selectedClip(); ok {
+			if err := copyToClipboard(c.text); err != nil {
+				m.notify("Failed to copy: " + err.Error())
+				return m, nil
+			}
+			m.recordClip(c.ticketID, c.text)
+			m.closeClips()
+			m.notify("Copied to the clipboard")
+		}
+	case "enter", "p":
+		if c, ok := m.selectedClip(); ok {
+			return m.pasteClip(c)
+		}
+	case "d", "x":
+		if i := len(m.clips) - 1 - v.index; i >= 0 && i < count {
+			m.clips = append(m.clips[:i], m.clips[i+1:]...)
+			if len(m.clips) == 0 {
+				m.closeClips()
+				return m, nil
+			}
+			v.selectItem(v.index, len(m.clips), height)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) closeClips() {
+	m.clipsPanel = nil
+	m.mode = ModeNormal
+}
+
+// pasteClip types the clip into the selected ticket's agent without
+// pressing Enter and attaches to it, to edit or send the text
+func (m *Model) pasteClip(c clip) (tea.Model, tea.Cmd) {
+	m.closeClips()
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		m.notify("No ticket selected")
+		return m, nil
+	}
+	pane, ok := m.panes[ticket.ID]
+	if !ok || !pane.Running() {
+		m.notify("No agent running — press 's' to spawn")
+		return m, nil
+	}
+	if err := pane.Insert(c.text); err != nil {
+		m.notify("Failed to paste: " + err.Error())
+		return m, nil
+	}
+	return m.attachToAgent()
+}
+
+func (v *clipsView) selectItem(i, count, height int) {
+	v.index = max(min(i, count-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+// clipPreviewLines is how much of the selected clip is shown in full
+const clipPreviewLines = 6
+
+func (m *Model) clipsListHeight() int {
+	return max(m.height-16-clipPreviewLines, 3)
+}
+
+func (m *Model) renderClipsView() string {
+	v := m.clipsPanel
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(110, m.width-4)
+	inner := width - 6
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("Clipboard History (%d)", len(m.clips))), ""}
+
+	height := m.clipsListHeight()
+	end := min(v.offset+height, len(m.clips))
+	for i := v.offset; i < end; i++ {
+		c := m.clips[len(m.clips)-1-i]
+		cursor := "  "
+		textStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == v.index {
+			cursor = "▸ "
+			textStyle = textStyle.Bold(true)
+		}
+		row := cursor + m.dimStyle().Render(c.at.Format("15:04:05")+"  ")
+		if ticket, _ := m.globalStore.Get(c.ticketID); ticket != nil {
+			row += m.dimStyle().Render(truncate(ticket.Title, 20) + "  ")
+		}
+		text := strings.Join(strings.Fields(c.text), " ")
+		lines = append(lines, row+textStyle.Render(truncate(text, max(inner-lipgloss.Width(row), 10))))
+	}
+	if len(m.clips) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(m.clips)-end)))
+	}
+
+	if c, ok := m.selectedClip(); ok {
+		preview := strings.Split(strings.TrimRight(c.text, "\n"), "\n")
+		if len(preview) > clipPreviewLines {
+			preview = append(preview[:clipPreviewLines-1], fmt.Sprintf("… %d more lines", len(preview)-clipPreviewLines+1))
+		}
+		lines = append(lines, "")
+		for _, l := range preview {
+			lines = append(lines, "  "+ansi.Truncate(l, inner-2, "…"))
+		}
+	}
+
+	lines = append(lines, "",
+		keyStyle.Render("[y]")+m.dimStyle().Render(" Copy  ")+
+			keyStyle.Render("[enter]")+m.dimStyle().Render(" Paste into agent  ")+
+			keyStyle.Render("[d]")+m.dimStyle().Render(" Delete  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
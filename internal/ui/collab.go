@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/control"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// CollabMsg carries a message from another instance sharing the board.
+type CollabMsg control.Message
+
+// CollabClosedMsg reports that the instance this one joined has gone.
+type CollabClosedMsg struct {
+	Err error
+}
+
+// SetCollab shares the board with other instances on this machine, as name.
+// joined is true when this instance joined a running one rather than
+// relaying for the others; send delivers a message to them.
+func (m *Model) SetCollab(name string, joined bool, send func(control.Message)) {
+	m.collabName = name
+	m.collabJoined = joined
+	m.collabSend = send
+	m.peers = make(map[string]control.Presence)
+}
+
+// sharePresence tells the other instances which ticket is selected or being
+// edited here, whenever that changes
+func (m *Model) sharePresence() {
+	if m.collabSend == nil {
+		return
+	}
+	p := control.Presence{Client: m.collabName}
+	if m.mode == ModeEditTicket && m.editingTicketID != "" {
+		p.Ticket, p.Editing = string(m.editingTicketID), true
+	} else if t := m.selectedTicket(); t != nil {
+		p.Ticket = string(t.ID)
+	}
+	if p == m.sentPresence {
+		return
+	}
+	m.sentPresence = p
+	m.collabSend(control.Message{Presence: &p})
+}
+
+func (m *Model) handleCollab(msg CollabMsg) tea.Cmd {
+	if p := msg.Presence; p != nil {
+		_, known := m.peers[p.Client]
+		switch {
+		case p.Left:
+			delete(m.peers, p.Client)
+			m.notify(p.Client + " left the board")
+		default:
+			m.peers[p.Client] = *p
+			if !known {
+				m.notify(p.Client + " joined the board")
+			}
+		}
+	}
+	if msg.Event != nil {
+		m.applyRemoteEvent(msg.Event)
+	}
+	return nil
+}
+
+// applyRemoteEvent takes on a change made by another instance. Nothing is
+// published, so the change isn't sent back and hooks only run where it was
+// made.
+func (m *Model) applyRemoteEvent(e *events.Event) {
+	if e.Ticket == nil {
+		return
+	}
+	ticket, _ := m.globalStore.Get(e.Ticket.ID)
+	switch {
+	case e.Type == events.TicketDeleted:
+		if ticket == nil {
+			return
+		}
+		m.globalStore.RemoveBlockerReferences(ticket.ID)
+		m.globalStore.Delete(ticket.ID)
+		if err := m.globalStore.SaveAll(); err != nil {
+			slog.Error("failed to save shared deletion", "ticket", ticket.ID, "err", err)
+		}
+	case ticket == nil:
+		if err := m.globalStore.Add(e.Ticket); err != nil {
+			slog.Warn("failed to add shared ticket", "ticket", e.Ticket.ID, "err", err)
+			return
+		}
+		m.storeTicket(e.Ticket)
+	default:
+		*ticket = *e.Ticket
+		m.storeTicket(ticket)
+	}
+	m.refreshColumnTickets()
+}
+
+// storeTicket persists a ticket changed elsewhere, without publishing it
+func (m *Model) storeTicket(ticket *board.Ticket) {
+	if m.autosaveEnabled() {
+		m.globalStore.MarkDirty(ticket)
+	} else if err := m.globalStore.Save(ticket); err != nil {
+		slog.Error("failed to save shared ticket", "ticket", ticket.ID, "err", err)
+	}
+}
+
+func (m *Model) handleCollabClosed(msg CollabClosedMsg) {
+	if msg.Err != nil {
+		slog.Warn("lost the shared board", "err", msg.Err)
+	}
+	m.collabSend = nil
+	m.peers = nil
+	m.notify("The instance sharing this board exited; changes are no longer shared")
+}
+
+// ticketEditor returns who else has the ticket open in the edit form
+func (m *Model) ticketEditor(id board.TicketID) string {
+	for _, p := range m.peers {
+		if p.Editing && p.Ticket == string(id) {
+			return p.Client
+		}
+	}
+	return ""
+}
+
+// renderPeerBadge shows who else has the ticket selected, or locked while
+// they edit it
+func (m *Model) renderPeerBadge(id board.TicketID) string {
+	if editor := m.ticketEditor(id); editor != "" {
+		return lipgloss.NewStyle().Foreground(m.colors.warning).Render("✎ " + editor)
+	}
+	var viewers []string
+	for _, p := range m.peers {
+		if p.Ticket == string(id) {
+			viewers = append(viewers, p.Client)
+		}
+	}
+	if len(viewers) == 0 {
+		return ""
+	}
+	sort.Strings(viewers)
+	return lipgloss.NewStyle().Foreground(m.colors.secondary).Render("◇ " + strings.Join(viewers, ", "))
+}
+
+// renderCollabState counts the instances sharing the board, this one included
+func (m *Model) renderCollabState() string {
+	if m.collabSend == nil || (len(m.peers) == 0 && !m.collabJoined) {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.secondary).Render(fmt.Sprintf("⚇ %d on board", len(m.peers)+1))
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// collapsedWidth is the width of a collapsed column's bar inside its
+// border, padding included
+const collapsedWidth = 5
+
+// boardProject is the one project the board shows, through a filter or
+// because only one is registered; nil while it shows several
+func (m *Model) boardProject() *project.Project {
+	switch len(m.filterProjectIDs) {
+	case 0:
+		if projects := m.globalStore.Projects(); len(projects) == 1 {
+			return projects[0]
+		}
+	case 1:
+		for id := range m.filterProjectIDs {
+			return m.globalStore.GetProject(id)
+		}
+	}
+	return nil
+}
+
+// isCollapsed reports whether column i is shown as a bar. A project's
+// board remembers its collapsed columns; a board of several projects
+// keeps them for the session.
+func (m *Model) isCollapsed(i int) bool {
+	if m.focusMode || i < 0 || i >= len(m.columns) {
+		return false
+	}
+	status := m.columns[i].Status
+	if p := m.boardProject(); p != nil {
+		return slices.Contains(p.CollapsedColumns, status)
+	}
+	return m.collapsed[status]
+}
+
+// toggleCollapse collapses the active column to a bar showing its name and
+// count, or expands it again
+func (m *Model) toggleCollapse() {
+	if m.focusMode {
+		m.notify("Focus mode shows a single column")
+		return
+	}
+	if len(m.columns) == 0 {
+		return
+	}
+	col := m.columns[m.activeColumn]
+	collapse := !m.isCollapsed(m.activeColumn)
+
+	if p := m.boardProject(); p != nil {
+		if collapse {
+			p.CollapsedColumns = append(p.CollapsedColumns, col.Status)
+		} else {
+			p.CollapsedColumns = slices.DeleteFunc(p.CollapsedColumns, func(s board.TicketStatus) bool {
+				return s == col.Status
+			})
+		}
+		if err := m.projectRegistry.Update(p); err != nil {
+			m.notifyError("Failed to save the collapsed column: " + err.Error())
+		}
+	} else {
+		if m.collapsed == nil {
+			m.collapsed = make(map[board.TicketStatus]bool)
+		}
+		m.collapsed[col.Status] = collapse
+	}
+	m.ensureColumnVisible()
+}
+
+// columnFootprint is the width a column takes on the board, margin
+// included, when expanded columns are colWidth wide
+func (m *Model) columnFootprint(i, colWidth int) int {
+	if m.isCollapsed(i) {
+		return collapsedWidth + 3
+	}
+	return colWidth + columnOverhead
+}
+
+// renderCollapsedColumn draws a collapsed column as a bar with its icon,
+// ticket count and name running down it
+func (m *Model) renderCollapsedColumn(col board.Column, count int, isActive, isDragTarget, isHovered, isLast bool) string {
+	headerColor := m.columnColor(col.Status)
+	center := lipgloss.NewStyle().Width(collapsedWidth - 2).Align(lipgloss.Center)
+
+	countStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+	if col.Limit > 0 && count >= col.Limit {
+		countStyle = countStyle.Foreground(m.colors.err).Bold(true)
+	}
+	nameStyle := lipgloss.NewStyle().Foreground(headerColor).Bold(true)
+
+	lines := []string{
+		center.Render(columnIcon(col.Status)),
+		center.Render(countStyle.Render(fmt.Sprint(count))),
+		"",
+	}
+	for _, r := range strings.ToUpper(col.Name) {
+		lines = append(lines, center.Render(nameStyle.Render(string(r))))
+	}
+
+	border := columnBorder
+	borderColor := m.colors.surface
+	if isDragTarget {
+		border = dragTargetBorder
+		borderColor = m.colors.success
+	} else if isActive {
+		border = columnBorderActive
+		borderColor = headerColor
+	} else if isHovered {
+		borderColor = m.colors.overlay
+	}
+
+	style := lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(borderColor).
+		Width(collapsedWidth).
+		Padding(0, 1)
+	if !isLast {
+		style = style.MarginRight(1)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
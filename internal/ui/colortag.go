@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/config"
+)
+
+// colorRef resolves a theme color name or a hex color against the current
+// theme
+func (m *Model) colorRef(ref string) (lipgloss.Color, bool) {
+	if ref == "" {
+		return "", false
+	}
+	if strings.HasPrefix(ref, "#") {
+		return lipgloss.Color(ref), true
+	}
+	hex, ok := m.theme.Colors.Lookup(ref)
+	if !ok || hex == "" {
+		return "", false
+	}
+	return lipgloss.Color(hex), true
+}
+
+// cycleColorTag steps the selected ticket's color tag through the theme's
+// accent colors and back to none
+func (m *Model) cycleColorTag() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("tag tickets") {
+		return m, nil
+	}
+	next := config.AccentColorNames[0]
+	if i := slices.Index(config.AccentColorNames, ticket.Color); i >= 0 {
+		next = ""
+		if i+1 < len(config.AccentColorNames) {
+			next = config.AccentColorNames[i+1]
+		}
+	}
+	ticket.Color = next
+	ticket.Touch()
+	m.saveTicket(ticket)
+	if next == "" {
+		m.notify("Color tag cleared")
+	} else {
+		m.notify("Color tag: " + next)
+	}
+	return m, nil
+}
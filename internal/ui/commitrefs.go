@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// commitRefInterval is how often ticket branches are read for references
+const commitRefInterval = 30 * time.Second
+
+// commitRefBaseDepth is how many commits of a default branch are read when
+// commit_refs.scan_base is set
+const commitRefBaseDepth = 50
+
+// commitRefsMetaKey holds the short hashes of the commits already applied to
+// a ticket, so each reference acts once
+const commitRefsMetaKey = "commits"
+
+// maxCommitRefs caps the hashes remembered per ticket
+const maxCommitRefs = 20
+
+type commitRefTickMsg time.Time
+type commitRefResultMsg struct {
+	refs []commitRef
+}
+
+// commitRef is a ticket reference found in a project's commit
+type commitRef struct {
+	projectID string
+	commit    git.Commit
+	ref       git.TicketRef
+}
+
+func tickCommitRefs(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return commitRefTickMsg(t)
+	})
+}
+
+// handleCommitRefTick reads the commits of every ticket branch, and of the
+// default branches when commit_refs.scan_base is set, for ticket references
+func (m *Model) handleCommitRefTick() (tea.Model, tea.Cmd) {
+	next := tickCommitRefs(commitRefInterval)
+	if !m.config.CommitRefs.Enabled || m.commitRefsBusy || m.config.Behavior.ReadOnly {
+		return m, next
+	}
+
+	type target struct {
+		project string
+		path    string
+		base    string
+		rev     string
+	}
+	var targets []target
+	mgrs := make(map[string]*git.WorktreeManager)
+	for _, t := range m.globalStore.All() {
+		if t.WorktreePath == "" || t.BranchName == "" || t.Status == board.StatusArchived {
+			continue
+		}
+		targets = append(targets, target{t.ProjectID, t.WorktreePath, t.BaseBranch, t.BranchName})
+		mgrs[t.ProjectID] = m.worktreeMgrs[t.ProjectID]
+	}
+	if m.config.CommitRefs.ScanBase {
+		for _, p := range m.globalStore.Projects() {
+			if mgr := m.worktreeMgrs[p.ID]; mgr != nil {
+				targets = append(targets, target{project: p.ID, path: p.RepoPath})
+				mgrs[p.ID] = mgr
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return m, next
+	}
+
+	prefix := m.config.CommitRefs.Prefix
+	keyPrefixes := make(map[string]string)
+	for _, p := range m.globalStore.Projects() {
+		keyPrefixes[p.ID] = p.GetKeyPrefix()
+	}
+	m.commitRefsBusy = true
+	scan := func() tea.Msg {
+		var result commitRefResultMsg
+		defaultBranches := make(map[string]string)
+		defaultBranch := func(project string) string {
+			if b, ok := defaultBranches[project]; ok {
+				return b
+			}
+			var b string
+			if mgr := mgrs[project]; mgr != nil {
+				b, _ = mgr.GetDefaultBranch()
+			}
+			defaultBranches[project] = b
+			return b
+		}
+
+		for _, t := range targets {
+			if _, err := os.Stat(t.path); err != nil {
+				continue
+			}
+			var commits []git.Commit
+			var err error
+			if t.rev == "" {
+				base := defaultBranch(t.project)
+				if base == "" {
+					continue
+				}
+				commits, err = git.RecentLog(t.path, base, commitRefBaseDepth)
+			} else {
+				base := t.base
+				if base == "" {
+					base = defaultBranch(t.project)
+				}
+				if base == "" {
+					continue
+				}
+				commits, err = git.Log(t.path, base, t.rev)
+			}
+			if err != nil {
+				continue
+			}
+			// Oldest first, so later commits win
+			for _, c := range slices.Backward(commits) {
+				message := c.Subject + "\n" + c.Body
+				refs := append(git.ParseTicketRefs(message, prefix), git.ParseKeyRefs(message, keyPrefixes[t.project])...)
+				for _, ref := range refs {
+					result.refs = append(result.refs, commitRef{t.project, c, ref})
+				}
+			}
+		}
+		return result
+	}
+	return m, tea.Batch(scan, next)
+}
+
+// applyCommitRefs notes each new reference on its ticket and moves tickets
+// closed by a commit to Done
+func (m *Model) applyCommitRefs(msg commitRefResultMsg) {
+	m.commitRefsBusy = false
+
+	var notes []string
+	moved := false
+	for _, r := range msg.refs {
+		ticket, err := m.globalStore.Find(r.ref.Ticket)
+		if err != nil || ticket.ProjectID != r.projectID {
+			continue
+		}
+		hash := r.commit.ShortHash()
+		seen := strings.Fields(ticket.Meta[commitRefsMetaKey])
+		if slices.Contains(seen, hash) {
+			continue
+		}
+		seen = append(seen, hash)
+		if len(seen) > maxCommitRefs {
+			seen = seen[len(seen)-maxCommitRefs:]
+		}
+		if ticket.Meta == nil {
+			ticket.Meta = map[string]string{}
+		}
+		ticket.Meta[commitRefsMetaKey] = strings.Join(seen, " ")
+
+		fromStatus := ticket.Status
+		if r.ref.Done && fromStatus != board.StatusDone && fromStatus != board.StatusArchived {
+			m.globalStore.Move(ticket.ID, board.StatusDone)
+			moved = true
+			notes = append(notes, fmt.Sprintf("%s moved to done by %s", ticket.Title, hash))
+		} else {
+			notes = append(notes, fmt.Sprintf("%s referenced in %s", ticket.Title, hash))
+		}
+		ticket.Touch()
+		m.saveTicket(ticket)
+		m.publishTicketMoved(ticket, fromStatus)
+	}
+
+	if moved {
+		selected := m.selectedTicket()
+		m.refreshColumnTickets()
+		if selected != nil {
+			m.selectTicketByID(selected.ID)
+		}
+	}
+	switch len(notes) {
+	case 0:
+	case 1:
+		m.notify(notes[0])
+	default:
+		m.notify(fmt.Sprintf("%d ticket updates from commits", len(notes)))
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+)
+
+// ControlRequestMsg carries a request from the control socket into the
+// update loop. The reply channel must be buffered.
+type ControlRequestMsg struct {
+	Request control.Request
+	Reply   chan<- control.Response
+}
+
+func (m *Model) handleControlRequest(msg ControlRequestMsg) tea.Cmd {
+	if m.mode == ModeShuttingDown {
+		msg.Reply <- control.Response{Error: "openkanban is shutting down"}
+		return nil
+	}
+
+	if msg.Request.Method == control.MethodReload {
+		if err := m.reloadConfig(); err != nil {
+			m.replyControlError(msg, err.Error())
+			return nil
+		}
+		msg.Reply <- control.Response{OK: true}
+		return nil
+	}
+
+	if m.config.Behavior.ReadOnly && (msg.Request.Method == control.MethodSpawn || msg.Request.Method == control.MethodStop) {
+		m.replyControlError(msg, "openkanban is in read-only mode")
+		return nil
+	}
+
+	tickets, err := m.resolveControlTickets(msg.Request)
+	if err != nil {
+		m.replyControlError(msg, err.Error())
+		return nil
+	}
+
+	switch msg.Request.Method {
+	case control.MethodStatus:
+		if len(msg.Request.Tickets) == 0 && msg.Request.Label == "" {
+			tickets = m.agentTickets()
+		}
+		resp := control.Response{OK: true}
+		for _, t := range tickets {
+			resp.Tickets = append(resp.Tickets, m.ticketState(t))
+		}
+		msg.Reply <- resp
+		return nil
+
+	case control.MethodSpawn:
+		if len(tickets) == 0 {
+			m.replyControlError(msg, "no tickets to spawn")
+			return nil
+		}
+		var cmds []tea.Cmd
+		spawned := 0
+		resp := control.Response{OK: true}
+		for _, t := range tickets {
+			cmd, err := m.spawnInBackground(t)
+			cmds = append(cmds, cmd)
+			state := m.ticketState(t)
+			if err != nil {
+				state.Error = err.Error()
+				m.logError(fmt.Sprintf("CLI spawn of %s failed: %v", t.Title, err))
+			} else {
+				spawned++
+			}
+			resp.Tickets = append(resp.Tickets, state)
+		}
+		m.refreshColumnTickets()
+		if spawned > 0 {
+			m.notify(fmt.Sprintf("Spawning %d agent(s) from CLI", spawned))
+		}
+		msg.Reply <- resp
+		return tea.Batch(cmds...)
+
+	case control.MethodStop:
+		if len(tickets) == 0 {
+			m.replyControlError(msg, "no tickets to stop")
+			return nil
+		}
+		resp := control.Response{OK: true}
+		for _, t := range tickets {
+			if pane, ok := m.panes[t.ID]; ok {
+				pane.Stop()
+				delete(m.panes, t.ID)
+				if m.focusedPane == t.ID {
+					m.mode = ModeNormal
+					m.focusedPane = ""
+				}
+			}
+			m.setAgentStatus(t, board.AgentNone)
+			m.endAgentRun(t, board.OutcomeStopped)
+			m.saveTicket(t)
+			resp.Tickets = append(resp.Tickets, m.ticketState(t))
+		}
+		msg.Reply <- resp
+		return nil
+	}
+
+	m.replyControlError(msg, "unknown method: "+msg.Request.Method)
+	return nil
+}
+
+// controlEnv is all an agent is given to drive the board with `openkanban
+// agent`: the control token, when the socket requires one. The rest of
+// openkanban's secrets are kept from it.
+func controlEnv() []string {
+	if token := config.ControlToken(); token != "" {
+		return []string{config.ControlTokenEnv + "=" + token}
+	}
+	return nil
+}
+
+// replyControlError fails the request, keeping the error in the error
+// console since the CLI that sent it may be long gone
+func (m *Model) replyControlError(msg ControlRequestMsg, err string) {
+	m.logError(fmt.Sprintf("CLI %s failed: %s", msg.Request.Method, err))
+	msg.Reply <- control.Response{Error: err}
+}
+
+// spawnInBackground starts an agent for t without focusing it. Backlog
+// tickets are moved to In Progress first.
+func (m *Model) spawnInBackground(t *board.Ticket) (tea.Cmd, error) {
+	switch t.Status {
+	case board.StatusBacklog:
+		m.globalStore.Move(t.ID, board.StatusInProgress)
+		m.saveTicket(t)
+		m.publishTicketMoved(t, board.StatusBacklog)
+	case board.StatusInProgress:
+	default:
+		return nil, fmt.Errorf("ticket is %s", t.Status)
+	}
+
+	_, cmd, err := m.startSpawn(t, true)
+	return cmd, err
+}
+
+func (m *Model) resolveControlTickets(req control.Request) ([]*board.Ticket, error) {
+	var tickets []*board.Ticket
+	seen := make(map[board.TicketID]bool)
+
+	for _, ref := range req.Tickets {
+		t, err := m.globalStore.Find(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			tickets = append(tickets, t)
+		}
+	}
+
+	if req.Label != "" {
+		for _, t := range m.globalStore.FindByLabel(req.Label) {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				tickets = append(tickets, t)
+			}
+		}
+	}
+
+	return tickets, nil
+}
+
+// agentTickets returns tickets that have a running pane or are in progress.
+func (m *Model) agentTickets() []*board.Ticket {
+	var tickets []*board.Ticket
+	for _, t := range m.globalStore.All() {
+		if _, running := m.panes[t.ID]; running || t.Status == board.StatusInProgress {
+			tickets = append(tickets, t)
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+	return tickets
+}
+
+func (m *Model) ticketState(t *board.Ticket) control.TicketState {
+	var projectName string
+	if proj := m.globalStore.GetProjectForTicket(t); proj != nil {
+		projectName = proj.Name
+	}
+	return control.NewTicketState(t, projectName, m.panes[t.ID] != nil)
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// debugStats measures the update loop for the debug overlay and, with
+// --debug, the debug log. Nothing is measured while neither is on.
+type debugStats struct {
+	log     *log.Logger
+	overlay bool
+
+	lastUpdate    time.Duration
+	slowestUpdate time.Duration
+	slowestMsg    string
+	lastView      time.Duration
+	frames        []time.Time // View calls within the last second
+	panes         map[string]time.Duration
+	inFlight      atomic.Int64 // commands started and not yet returned
+
+	mem     runtime.MemStats
+	memRead time.Time
+}
+
+func (d *debugStats) enabled() bool {
+	return d.log != nil || d.overlay
+}
+
+// EnableDebugLog logs every message's update time and every pane render
+// to w.
+func (m *Model) EnableDebugLog(w io.Writer) {
+	m.debug.log = log.New(w, "", log.Ltime|log.Lmicroseconds)
+	m.debug.log.Printf("debug log started")
+}
+
+// recordUpdate notes how long msg took to handle, and counts the commands
+// it started until they return
+func (m *Model) recordUpdate(msg tea.Msg, took time.Duration, cmd tea.Cmd) tea.Cmd {
+	d := &m.debug
+	if !d.enabled() {
+		return cmd
+	}
+	name := fmt.Sprintf("%T", msg)
+	d.lastUpdate = took
+	if took > d.slowestUpdate {
+		d.slowestUpdate, d.slowestMsg = took, name
+	}
+	if d.log != nil {
+		d.log.Printf("update %s %s", name, took)
+	}
+	return d.track(cmd)
+}
+
+// track counts cmd, and the commands of a batch it returns, as in flight
+// until each returns its message
+func (d *debugStats) track(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	d.inFlight.Add(1)
+	return func() tea.Msg {
+		msg := cmd()
+		d.inFlight.Add(-1)
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i, c := range batch {
+				batch[i] = d.track(c)
+			}
+		}
+		return msg
+	}
+}
+
+// recordView notes a frame and how long it took to render
+func (m *Model) recordView(took time.Duration) {
+	d := &m.debug
+	if !d.enabled() {
+		return
+	}
+	now := time.Now()
+	d.lastView = took
+	d.frames = append(d.frames, now)
+	for len(d.frames) > 0 && now.Sub(d.frames[0]) > time.Second {
+		d.frames = d.frames[1:]
+	}
+	if d.log != nil {
+		d.log.Printf("view %s", took)
+	}
+}
+
+// paneView renders pane, timing it for the debug overlay and log
+func (m *Model) paneView(pane *terminal.Pane) string {
+	d := &m.debug
+	if !d.enabled() {
+		return pane.View()
+	}
+	start := time.Now()
+	view := pane.View()
+	took := time.Since(start)
+	if d.panes == nil {
+		d.panes = make(map[string]time.Duration)
+	}
+	d.panes[pane.ID()] = took
+	if d.log != nil {
+		d.log.Printf("pane %s render %s", pane.ID(), took)
+	}
+	return view
+}
+
+func (m *Model) toggleDebugOverlay() {
+	m.debug.overlay = !m.debug.overlay
+}
+
+// renderDebugOverlay is the top-right panel of update loop and memory
+// figures
+func (m *Model) renderDebugOverlay() string {
+	d := &m.debug
+	if time.Since(d.memRead) > time.Second {
+		runtime.ReadMemStats(&d.mem)
+		d.memRead = time.Now()
+	}
+
+	lines := []string{
+		fmt.Sprintf("FPS        %d", len(d.frames)),
+		fmt.Sprintf("update     %s (slowest %s %s)", round(d.lastUpdate), round(d.slowestUpdate), strings.TrimPrefix(d.slowestMsg, "ui.")),
+		fmt.Sprintf("view       %s", round(d.lastView)),
+		fmt.Sprintf("queue      %d commands, %d events", d.inFlight.Load(), len(m.busEvents)),
+		fmt.Sprintf("memory     %s heap, %s sys, %d GCs", formatBytes(d.mem.HeapAlloc), formatBytes(d.mem.Sys), d.mem.NumGC),
+		fmt.Sprintf("goroutines %d", runtime.NumGoroutine()),
+	}
+	for _, id := range slices.Sorted(maps.Keys(d.panes)) {
+		if ticket, _ := m.globalStore.Get(board.TicketID(id)); ticket != nil {
+			lines = append(lines, fmt.Sprintf("pane       %s %s", truncate(ticket.Title, 20), round(d.panes[id])))
+		}
+	}
+	if d.log != nil {
+		lines = append(lines, m.dimStyle().Render("logging to the debug log"))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.warning).
+		Foreground(m.colors.text).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// withDebugOverlay draws the debug overlay over the top-right corner
+func (m *Model) withDebugOverlay(view string) string {
+	if !m.debug.overlay {
+		return view
+	}
+	return overlayRight(view, m.renderDebugOverlay(), m.width, 1)
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
+
+func formatBytes(n uint64) string {
+	return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+}
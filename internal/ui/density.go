@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+const (
+	densityCompact  = "compact"
+	densityNormal   = "normal"
+	densityDetailed = "detailed"
+)
+
+// cardDensities is the order the density toggle cycles through
+var cardDensities = []string{densityCompact, densityNormal, densityDetailed}
+
+// ticketHeight is the lines a card takes up in a column, used to work out
+// how many fit and which one is under the mouse. Normal and detailed cards
+// vary with their content, so theirs is typical rather than exact.
+func (m *Model) ticketHeight() int {
+	switch m.cardDensity {
+	case densityCompact:
+		return 1
+	case densityDetailed:
+		return 12
+	}
+	return 6
+}
+
+// cycleCardDensity switches to the next card density for this session
+func (m *Model) cycleCardDensity() {
+	next := densityNormal
+	for i, d := range cardDensities {
+		if d == m.cardDensity {
+			next = cardDensities[(i+1)%len(cardDensities)]
+		}
+	}
+	m.setCardDensity(next)
+	m.notify("Cards: " + next)
+}
+
+func (m *Model) setCardDensity(density string) {
+	if density == "" {
+		density = densityNormal
+	}
+	m.cardDensity = density
+	m.ensureTicketVisible()
+}
+
+// renderCompactTicket draws a ticket as a single line: an accent bar, the
+// priority, the title, and the agent's state at the end. It is as wide as a
+// bordered card so columns line up.
+func (m *Model) renderCompactTicket(ticket *board.Ticket, isSelected, isHovered bool, width int, columnColor lipgloss.Color) string {
+	pane, hasPane := m.panes[ticket.ID]
+	isRunning := hasPane && pane.Running()
+
+	accent := m.colors.surface
+	if tag, ok := m.colorRef(ticket.Color); ok {
+		accent = tag
+	}
+	var state string
+	switch ticket.AgentStatus {
+	case board.AgentWorking, board.AgentWaiting, board.AgentCompleted, board.AgentError:
+		state, accent = m.agentIndicator(ticket.AgentStatus)
+	case board.AgentIdle:
+		if hasPane {
+			state, accent = m.agentIndicator(ticket.AgentStatus)
+		}
+	}
+	if isRunning {
+		accent = m.colors.success
+	}
+	if _, busy := m.gitOps[ticket.ID]; busy {
+		accent, state = m.colors.info, m.spinner.View()
+	}
+	if isSelected {
+		accent = columnColor
+	}
+
+	base := lipgloss.NewStyle().Foreground(m.colors.text)
+	switch {
+	case isSelected:
+		base = base.Background(m.colors.surface).Bold(true)
+	case isHovered:
+		base = base.Background(m.colors.overlay)
+	}
+	bar := lipgloss.NewStyle().Foreground(accent)
+	if isSelected || isHovered {
+		bar = bar.Background(base.GetBackground())
+	}
+	return bar.Render("▌") + m.compactTicketBody(ticket, state, accent, base, width+1)
+}
+
+// compactTicketBody lays out the priority, title and state in width columns
+func (m *Model) compactTicketBody(ticket *board.Ticket, state string, stateColor lipgloss.Color, base lipgloss.Style, width int) string {
+	var prefix string
+	var prefixColor lipgloss.Color
+	switch ticket.Priority {
+	case 1:
+		prefix, prefixColor = "!! ", m.colors.err
+	case 2:
+		prefix, prefixColor = "! ", lipgloss.Color("#fab387")
+	}
+	suffix := ""
+	if state != "" {
+		suffix = " " + state
+	}
+	titleWidth := max(width-1-lipgloss.Width(prefix)-lipgloss.Width(suffix), 1)
+	title := truncate(strings.ReplaceAll(ticket.Title, "\n", " "), titleWidth)
+	pad := strings.Repeat(" ", max(titleWidth-lipgloss.Width(title), 0))
+
+	parts := []string{base.Render(" ")}
+	if prefix != "" {
+		parts = append(parts, base.Foreground(prefixColor).Bold(true).Render(prefix))
+	}
+	parts = append(parts, base.Render(title+pad))
+	if suffix != "" {
+		parts = append(parts, base.Foreground(stateColor).Render(suffix))
+	}
+	return strings.Join(parts, "")
+}
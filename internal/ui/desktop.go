@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/desktop"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+type desktopErrorMsg struct {
+	err error
+}
+
+// desktopEnabled reports whether desktop notifications may be shown now
+func (m *Model) desktopEnabled() bool {
+	n := m.config.Notifications
+	return n.Desktop.Enabled && !n.Quiet(time.Now())
+}
+
+// desktopTitle returns the title of e's desktop notification, or false when
+// its event is turned off in notifications.desktop
+func (m *Model) desktopTitle(e events.Event) (string, bool) {
+	d := m.config.Notifications.Desktop
+	switch {
+	case !d.Enabled:
+		return "", false
+	case e.Type == events.AgentWaiting && d.AgentWaiting:
+		return "Agent waiting", true
+	case e.Type == events.AgentCompleted && d.AgentCompleted:
+		return "Agent finished", true
+	case e.Type == events.AgentFailed && d.AgentFailed:
+		return "Agent failed", true
+	}
+	return "", false
+}
+
+// notifyDesktop shows e as a desktop notification when its event is turned
+// on in notifications.desktop
+func (m *Model) notifyDesktop(e events.Event) tea.Cmd {
+	title, ok := m.desktopTitle(e)
+	if !ok || !m.desktopEnabled() {
+		return nil
+	}
+	body := events.Summary(e)
+	return func() tea.Msg {
+		if err := desktop.Notify(title, body); err != nil {
+			return desktopErrorMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// toggleMute turns notifications.muted on or off, saving it so the board
+// stays muted across restarts
+func (m *Model) toggleMute() {
+	muted := !m.config.Notifications.Muted
+	err := m.updateSetting("notifications.muted", func(c *config.Config) error {
+		c.Notifications.Muted = muted
+		return nil
+	})
+	switch {
+	case err != nil:
+		m.notify("Failed to mute notifications: " + err.Error())
+	case muted:
+		m.notify("Notifications muted; they are kept in the history (!)")
+	default:
+		m.notify("Notifications unmuted")
+	}
+}
+
+// notifyDesktopError repeats a status bar error on the desktop. It runs on
+// its own since notify has no command to return, and drops failures so a
+// broken notifier can't feed itself.
+func (m *Model) notifyDesktopError(msg string) {
+	if !m.config.Notifications.Desktop.Errors || !m.desktopEnabled() {
+		return
+	}
+	go func() {
+		if err := desktop.Notify("openkanban", msg); err != nil {
+			slog.Debug("desktop notification failed", "err", err)
+		}
+	}()
+}
+
+// handleDesktopError reports a failing notifier once until the error changes
+func (m *Model) handleDesktopError(msg desktopErrorMsg) {
+	if msg.err.Error() != m.desktopErr {
+		m.desktopErr = msg.err.Error()
+		m.notify("Desktop notification failed: " + m.desktopErr)
+	}
+}
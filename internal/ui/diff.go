@@ -0,0 +1,448 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// Diff view layout. The header takes three rows and the status bar one.
+const (
+	diffBodyTop   = 3
+	diffGutter    = 10 // two right-aligned 4-digit line numbers plus spacing
+	diffTreeMax   = 40
+	diffSeparator = " │ "
+)
+
+// diffView is the state of the worktree diff view
+type diffView struct {
+	ticketID board.TicketID
+	title    string
+	branch   string
+	base     string
+	path     string
+	project  string
+
+	files        []git.FileDiff
+	file         int
+	scroll       int
+	treeOffset   int
+	focusContent bool
+
+	loading bool
+	err     error
+}
+
+type diffLoadedMsg struct {
+	ticketID board.TicketID
+	base     string
+	files    []git.FileDiff
+	err      error
+}
+
+// diffTreeRow is a row of the file tree: a directory heading (file == -1)
+// or an entry for files[file].
+type diffTreeRow struct {
+	dir  string
+	file int
+}
+
+func (m *Model) openDiff() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" {
+		m.notify("No worktree for this ticket yet")
+		return m, nil
+	}
+
+	m.diff = &diffView{
+		ticketID: ticket.ID,
+		title:    ticket.Title,
+		branch:   ticket.BranchName,
+		base:     ticket.BaseBranch,
+		path:     ticket.WorktreePath,
+		project:  ticket.ProjectID,
+	}
+	m.mode = ModeDiff
+	return m, m.loadDiff()
+}
+
+func (m *Model) loadDiff() tea.Cmd {
+	d := m.diff
+	d.loading = true
+	ticketID, path, base := d.ticketID, d.path, d.base
+	mgr := m.worktreeMgrs[d.project]
+	return func() tea.Msg {
+		if base == "" && mgr != nil {
+			base, _ = mgr.GetDefaultBranch()
+		}
+		if base == "" {
+			return diffLoadedMsg{ticketID: ticketID, err: fmt.Errorf("no base branch to compare against")}
+		}
+		files, err := git.Diff(path, base)
+		return diffLoadedMsg{ticketID: ticketID, base: base, files: files, err: err}
+	}
+}
+
+func (m *Model) handleDiffLoaded(msg diffLoadedMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	if d == nil || d.ticketID != msg.ticketID {
+		return m, nil
+	}
+	d.loading = false
+	d.err = msg.err
+	if msg.base != "" {
+		d.base = msg.base
+	}
+	d.files = msg.files
+	d.selectFile(min(d.file, max(len(d.files)-1, 0)), m.diffBodyHeight())
+	return m, nil
+}
+
+func (m *Model) closeDiff() {
+	m.diff = nil
+	m.mode = ModeNormal
+}
+
+func (m *Model) handleDiffMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	height := m.diffBodyHeight()
+
+	switch msg.String() {
+	case "esc", "q":
+		m.closeDiff()
+	case "r":
+		return m, m.loadDiff()
+	case "tab":
+		d.focusContent = !d.focusContent
+	case "enter", "l", "right":
+		d.focusContent = true
+	case "h", "left":
+		d.focusContent = false
+	case "n", "]":
+		d.selectFile(d.file+1, height)
+	case "p", "[":
+		d.selectFile(d.file-1, height)
+	case "j", "down":
+		if d.focusContent {
+			d.scrollBy(1, height)
+		} else {
+			d.selectFile(d.file+1, height)
+		}
+	case "k", "up":
+		if d.focusContent {
+			d.scrollBy(-1, height)
+		} else {
+			d.selectFile(d.file-1, height)
+		}
+	case "ctrl+d":
+		d.scrollBy(height/2, height)
+	case "ctrl+u":
+		d.scrollBy(-height/2, height)
+	case "pgdown", " ", "ctrl+f":
+		d.scrollBy(height, height)
+	case "pgup", "ctrl+b":
+		d.scrollBy(-height, height)
+	case "g", "home":
+		if d.focusContent {
+			d.scroll = 0
+		} else {
+			d.selectFile(0, height)
+		}
+	case "G", "end":
+		if d.focusContent {
+			d.scrollBy(len(d.lines()), height)
+		} else {
+			d.selectFile(len(d.files)-1, height)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handleDiffMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	height := m.diffBodyHeight()
+	inTree := msg.X < m.diffTreeWidth()
+
+	switch msg.Button {
+	case tea.MouseButtonWheelDown:
+		if inTree {
+			d.selectFile(d.file+1, height)
+		} else {
+			d.scrollBy(3, height)
+		}
+	case tea.MouseButtonWheelUp:
+		if inTree {
+			d.selectFile(d.file-1, height)
+		} else {
+			d.scrollBy(-3, height)
+		}
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			break
+		}
+		d.focusContent = !inTree
+		rows := d.treeRows()
+		idx := d.treeOffset + msg.Y - diffBodyTop
+		if inTree && msg.Y >= diffBodyTop && idx < len(rows) && rows[idx].file >= 0 {
+			d.selectFile(rows[idx].file, height)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) diffBodyHeight() int {
+	return max(m.height-diffBodyTop-1, 1)
+}
+
+func (m *Model) diffTreeWidth() int {
+	return min(diffTreeMax, m.width/3)
+}
+
+// selectFile selects files[i], resets the content scroll, and scrolls the
+// file tree so the selection stays visible in height rows.
+func (d *diffView) selectFile(i, height int) {
+	if len(d.files) == 0 {
+		d.file, d.scroll, d.treeOffset = 0, 0, 0
+		return
+	}
+	i = max(0, min(i, len(d.files)-1))
+	if i != d.file {
+		d.scroll = 0
+	}
+	d.file = i
+
+	for row, r := range d.treeRows() {
+		if r.file != i {
+			continue
+		}
+		// Keep the file's directory heading in view when scrolling up
+		if row > 0 && d.treeRows()[row-1].file == -1 {
+			row--
+		}
+		if row < d.treeOffset {
+			d.treeOffset = row
+		}
+		if r := row + 1; r >= d.treeOffset+height {
+			d.treeOffset = r - height + 1
+		}
+		break
+	}
+}
+
+func (d *diffView) scrollBy(n, height int) {
+	d.scroll = max(0, min(d.scroll+n, len(d.lines())-height))
+}
+
+func (d *diffView) lines() []git.DiffLine {
+	if d.file >= len(d.files) {
+		return nil
+	}
+	return d.files[d.file].Lines
+}
+
+// treeRows lays the files out grouped by directory. git already sorts diff
+// output by path, so files in the same directory are adjacent.
+func (d *diffView) treeRows() []diffTreeRow {
+	var rows []diffTreeRow
+	lastDir := "."
+	for i, f := range d.files {
+		if dir := filepath.Dir(f.Path); dir != lastDir {
+			if dir != "." {
+				rows = append(rows, diffTreeRow{dir: dir, file: -1})
+			}
+			lastDir = dir
+		}
+		rows = append(rows, diffTreeRow{dir: lastDir, file: i})
+	}
+	return rows
+}
+
+func (m *Model) renderDiffView() string {
+	d := m.diff
+	width := m.width
+
+	breadcrumb := lipgloss.NewStyle().Foreground(m.colors.muted).Render("Board → ")
+	title := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true).Render(d.title)
+	header := breadcrumb + title
+	if d.branch != "" && d.base != "" {
+		header += "  " + m.dimStyle().Render(d.base+"..."+d.branch)
+	}
+
+	var summary string
+	switch {
+	case d.loading && d.files == nil:
+		summary = m.spinner.View() + m.dimStyle().Render(" Loading diff...")
+	case d.err != nil:
+		summary = lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ " + d.err.Error())
+	case len(d.files) == 0:
+		summary = m.dimStyle().Render("No changes since " + d.base)
+	default:
+		stat := git.Stat(d.files)
+		summary = lipgloss.NewStyle().Foreground(m.colors.success).Render(fmt.Sprintf("+%d", stat.Added)) + " " +
+			lipgloss.NewStyle().Foreground(m.colors.err).Render(fmt.Sprintf("-%d", stat.Deleted)) + "  " +
+			m.dimStyle().Render(stat.String())
+	}
+
+	rule := lipgloss.NewStyle().Foreground(m.colors.surface).Render(strings.Repeat("─", width))
+
+	height := m.diffBodyHeight()
+	treeWidth := m.diffTreeWidth()
+	contentWidth := max(width-treeWidth-lipgloss.Width(diffSeparator), 1)
+
+	tree := m.renderDiffTree(treeWidth, height)
+	content := m.renderDiffContent(contentWidth, height)
+	sep := lipgloss.NewStyle().Foreground(m.colors.surface).Render(strings.TrimRight(diffSeparator, " "))
+
+	var body strings.Builder
+	for i := range height {
+		body.WriteString(padRight(tree[i], treeWidth))
+		body.WriteString(" " + sep + " ")
+		body.WriteString(content[i])
+		body.WriteString("\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, summary, rule) + "\n" +
+		body.String() + m.renderStatusBar()
+}
+
+// renderDiffTree returns exactly height rows, each at most width wide
+func (m *Model) renderDiffTree(width, height int) []string {
+	d := m.diff
+	out := make([]string, height)
+	rows := d.treeRows()
+
+	statusColors := map[string]lipgloss.Color{
+		"added":    m.colors.success,
+		"deleted":  m.colors.err,
+		"renamed":  m.colors.info,
+		"modified": m.colors.warning,
+	}
+
+	for i := range height {
+		idx := d.treeOffset + i
+		if idx >= len(rows) {
+			break
+		}
+		r := rows[idx]
+		if r.file == -1 {
+			out[i] = lipgloss.NewStyle().Foreground(m.colors.subtext).Render(truncate("▾ "+r.dir+"/", width))
+			continue
+		}
+
+		f := d.files[r.file]
+		indent := ""
+		if r.dir != "." {
+			indent = "  "
+		}
+		counts := fmt.Sprintf(" +%d -%d", f.Added, f.Deleted)
+		if f.Binary {
+			counts = " bin"
+		}
+		letter := strings.ToUpper(f.Status[:1])
+		name := truncate(filepath.Base(f.Path), max(width-len(indent)-2-len(counts), 1))
+
+		nameStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if r.file == d.file {
+			nameStyle = nameStyle.Bold(true).Foreground(m.colors.primary)
+			if !d.focusContent {
+				nameStyle = nameStyle.Background(m.colors.surface)
+			}
+		}
+		out[i] = indent +
+			lipgloss.NewStyle().Foreground(statusColors[f.Status]).Render(letter) + " " +
+			nameStyle.Render(name) +
+			m.dimStyle().Render(counts)
+	}
+	return out
+}
+
+// renderDiffContent returns exactly height rows for the selected file, each
+// at most width wide
+func (m *Model) renderDiffContent(width, height int) []string {
+	d := m.diff
+	out := make([]string, height)
+	if d.file >= len(d.files) {
+		return out
+	}
+
+	f := d.files[d.file]
+	switch {
+	case f.Binary:
+		out[0] = m.dimStyle().Render("Binary file not shown")
+		return out
+	case len(f.Lines) == 0 && f.Status == "renamed":
+		out[0] = m.dimStyle().Render(truncate("Renamed from "+f.OldPath+" without changes", width))
+		return out
+	}
+
+	lang, known := syntaxFor(f.Path)
+	textWidth := max(width-diffGutter-2, 1)
+	gutter := m.dimStyle()
+	lineNo := func(n int) string {
+		if n == 0 {
+			return "    "
+		}
+		return fmt.Sprintf("%4d", n)
+	}
+
+	for i := range height {
+		idx := d.scroll + i
+		if idx >= len(f.Lines) {
+			break
+		}
+		line := f.Lines[idx]
+		text := truncate(strings.ReplaceAll(line.Text, "\t", "    "), textWidth)
+
+		if line.Kind == git.DiffHunk {
+			out[i] = lipgloss.NewStyle().Foreground(m.colors.info).Render(truncate(line.Text, width))
+			continue
+		}
+
+		marker := " "
+		base := lipgloss.NewStyle().Foreground(m.colors.text)
+		markerStyle := gutter
+		switch line.Kind {
+		case git.DiffAdded:
+			marker = "+"
+			markerStyle = lipgloss.NewStyle().Foreground(m.colors.success).Bold(true)
+		case git.DiffRemoved:
+			marker = "-"
+			markerStyle = lipgloss.NewStyle().Foreground(m.colors.err).Bold(true)
+			base = base.Foreground(m.colors.subtext)
+		}
+
+		code := base.Render(text)
+		if known {
+			code = m.highlight(text, lang, base)
+		}
+		out[i] = gutter.Render(lineNo(line.OldLine)+" "+lineNo(line.NewLine)+" ") +
+			markerStyle.Render(marker) + " " + code
+	}
+	return out
+}
+
+// padRight pads s with spaces to width display columns
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+}
+
+// truncate shortens s to at most width runes, marking the cut with "…"
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:max(width, 0)])
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/editor"
+)
+
+type editorClosedMsg struct {
+	err error
+}
+
+// openInEditor opens the selected ticket's worktree in the configured
+// editor. Terminal editors take over the screen until they exit; GUI
+// editors are started in the background.
+func (m *Model) openInEditor() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" {
+		m.notify("No worktree for this ticket yet")
+		return m, nil
+	}
+	if _, err := os.Stat(ticket.WorktreePath); err != nil {
+		m.notify("Worktree is missing: " + ticket.WorktreePath)
+		return m, nil
+	}
+
+	ed, err := editor.Resolve(m.config.Editor.Command)
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+		return m, nil
+	}
+
+	target := ticket.WorktreePath
+	if m.config.Editor.Workspace && ed.SupportsWorkspace() {
+		if target, err = editor.WriteWorkspace(ticket.WorktreePath, ticket.Title); err != nil {
+			m.notify("Failed to write workspace file: " + err.Error())
+			return m, nil
+		}
+	}
+
+	cmd := ed.Command(target)
+	cmd.Dir = ticket.WorktreePath
+	name := filepath.Base(ed.Args[0])
+	if ed.Terminal {
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return editorClosedMsg{err: err}
+		})
+	}
+
+	if err := cmd.Start(); err != nil {
+		m.notify(fmt.Sprintf("Failed to start %s: %v", name, err))
+		return m, nil
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			slog.Warn("editor exited with an error", "editor", name, "err", err)
+		}
+	}()
+	m.notify(fmt.Sprintf("Opened %s in %s", ticket.Title, name))
+	return m, nil
+}
+
+func (m *Model) handleEditorClosed(msg editorClosedMsg) {
+	if msg.err != nil {
+		m.notify("Editor failed: " + msg.err.Error())
+	}
+}
+
+type descriptionEditedMsg struct {
+	path string
+	err  error
+}
+
+type notesEditedMsg struct {
+	ticketID board.TicketID
+	path     string
+	err      error
+}
+
+// editText writes text to a temp file and opens it in $VISUAL or $EDITOR,
+// vi without either. done gets the file once the editor exits. The board
+// steps aside meanwhile, so GUI editors need their wait flag, e.g.
+// "code --wait".
+func editText(text string, done func(path string, err error) tea.Msg) (tea.Cmd, error) {
+	ed, err := editor.Resolve("")
+	if errors.Is(err, editor.ErrNoEditor) {
+		ed = editor.Editor{Args: []string{"vi"}, Terminal: true}
+	}
+
+	f, err := os.CreateTemp("", "openkanban-*.md")
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	path := f.Name()
+	return tea.ExecProcess(ed.Command(path), func(err error) tea.Msg {
+		return done(path, err)
+	}), nil
+}
+
+// readEditedText reads back and removes a file from editText
+func readEditedText(path string, err error) (string, error) {
+	defer os.Remove(path)
+	if err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// editDescription opens the ticket form's description in the editor
+func (m *Model) editDescription() (tea.Model, tea.Cmd) {
+	cmd, err := editText(m.descInput.Value(), func(path string, err error) tea.Msg {
+		return descriptionEditedMsg{path: path, err: err}
+	})
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+	}
+	return m, cmd
+}
+
+func (m *Model) handleDescriptionEdited(msg descriptionEditedMsg) {
+	text, err := readEditedText(msg.path, msg.err)
+	if err != nil {
+		m.notify("Failed to edit description: " + err.Error())
+		return
+	}
+	if m.mode == ModeCreateTicket || m.mode == ModeEditTicket {
+		m.descInput.SetValue(text)
+	}
+}
+
+// editNotes opens the selected ticket's working notes in the editor. Unlike
+// the description, which the agent was started with, notes can change
+// while the agent runs.
+func (m *Model) editNotes() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("edit notes") {
+		return m, nil
+	}
+	ticketID := ticket.ID
+	cmd, err := editText(ticket.Notes, func(path string, err error) tea.Msg {
+		return notesEditedMsg{ticketID: ticketID, path: path, err: err}
+	})
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+	}
+	return m, cmd
+}
+
+func (m *Model) handleNotesEdited(msg notesEditedMsg) {
+	text, err := readEditedText(msg.path, msg.err)
+	if err != nil {
+		m.notify("Failed to edit notes: " + err.Error())
+		return
+	}
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil || ticket.Notes == text {
+		return
+	}
+	ticket.Notes = text
+	ticket.Touch()
+	m.saveTicket(ticket)
+	m.notify("Notes saved")
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// busBuffer is how many events the board may fall behind by. It is far
+// more than one update publishes, since the board must not miss any.
+const busBuffer = 1024
+
+// busEventMsg carries an event from the bus back into the update loop
+type busEventMsg events.Event
+
+// listenEvents waits for the next event published on the bus
+func (m *Model) listenEvents() tea.Cmd {
+	ch := m.busEvents
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		e, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return busEventMsg(e)
+	}
+}
+
+// handleBusEvent is the board's subscriber: whatever follows from an event,
+// beyond the change that published it, happens here.
+func (m *Model) handleBusEvent(e events.Event) tea.Cmd {
+	cmds := []tea.Cmd{m.listenEvents(), m.runHooks(e)}
+
+	var ticket *board.Ticket
+	if e.Ticket != nil {
+		// Events carry a copy; act on the ticket as it is now
+		ticket, _ = m.globalStore.Get(e.Ticket.ID)
+	}
+
+	switch e.Type {
+	case events.TicketMoved:
+		if ticket != nil {
+			cmds = append(cmds, m.transitionJira(ticket), m.moveLinearIssue(ticket))
+		}
+	case events.AgentCompleted:
+		if ticket != nil {
+			cmds = append(cmds, m.autoPush(ticket))
+			m.submitForReview(ticket)
+		}
+	case events.AgentStateChanged:
+		if ticket != nil {
+			m.agentActivity[ticket.ID] = e.Time
+		}
+		m.refreshFocus()
+	case events.TicketChanged:
+		m.refreshFocus()
+	}
+	return tea.Batch(cmds...)
+}
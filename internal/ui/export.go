@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+type htmlExportedMsg struct {
+	path string
+	err  error
+}
+
+// exportTranscript writes the agent output in the transcript view to an
+// HTML page under exports/ in the config directory. A running agent's
+// output keeps its colors; a finished one's is plain text.
+func (m *Model) exportTranscript() (tea.Model, tea.Cmd) {
+	tv := m.transcript
+	name := string(tv.ticketID)
+	if ticket, _ := m.globalStore.Get(tv.ticketID); ticket != nil {
+		if key := m.globalStore.TicketKey(ticket); key != "" {
+			name = key
+		}
+	}
+	pane := m.panes[tv.ticketID]
+	title := tv.title
+	lines := tv.lines
+
+	return m, func() tea.Msg {
+		text := ""
+		if pane != nil {
+			text = pane.StyledTranscript()
+		}
+		if text == "" {
+			// Escape characters would be taken for colors
+			text = strings.ReplaceAll(strings.Join(lines, "\n"), "\x1b", "")
+		}
+		path, err := writeExport(name, terminal.HTML(title, text))
+		return htmlExportedMsg{path: path, err: err}
+	}
+}
+
+// writeExport saves page as exports/<name>-<time>.html in the config
+// directory and returns its path
+func writeExport(name, page string) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "exports")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+"-"+time.Now().Format("20060102-150405")+".html")
+	if err := os.WriteFile(path, []byte(page), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (m *Model) handleHTMLExported(msg htmlExportedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notify("Failed to export output: " + msg.err.Error())
+		return m, nil
+	}
+	m.notify("Exported output to " + msg.path)
+	return m, nil
+}
@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/filewatch"
+)
+
+// fileFeedLimit caps the changes kept per ticket for the feed
+const fileFeedLimit = 100
+
+// fileFeed is what a ticket's agent has changed in its working directory
+// since it started
+type fileFeed struct {
+	changes []filewatch.Change // newest last; repeated writes to a file are one entry
+	files   map[string]bool    // every path touched
+}
+
+func (f *fileFeed) add(c filewatch.Change) {
+	f.files[c.Path] = true
+	if n := len(f.changes); n > 0 && f.changes[n-1].Path == c.Path && f.changes[n-1].Op == c.Op {
+		f.changes[n-1].At = c.At
+		return
+	}
+	f.changes = append(f.changes, c)
+	if len(f.changes) > fileFeedLimit {
+		f.changes = f.changes[len(f.changes)-fileFeedLimit:]
+	}
+}
+
+type fileWatchStartedMsg struct {
+	ticketID board.TicketID
+	dir      string
+	watcher  *filewatch.Watcher
+	err      error
+}
+
+type fileChangesMsg struct {
+	ticketID board.TicketID
+	watcher  *filewatch.Watcher
+	changes  []filewatch.Change
+	ok       bool
+}
+
+func (m *Model) toggleFileFeed() tea.Cmd {
+	m.fileFeed = !m.fileFeed
+	m.ensureColumnVisible()
+	if m.fileFeed {
+		m.notify("File feed on")
+	} else {
+		m.notify("File feed off")
+	}
+	return m.syncFileWatchers()
+}
+
+// syncFileWatchers watches the working directory of every running agent
+// while the file feed is on, and stops watching the rest
+func (m *Model) syncFileWatchers() tea.Cmd {
+	var cmds []tea.Cmd
+	for ticketID, w := range m.fileWatchers {
+		if pane, ok := m.panes[ticketID]; m.fileFeed && ok && pane.Running() {
+			continue
+		}
+		// A watcher still starting is closed when it arrives
+		if w != nil {
+			w.Close()
+		}
+		delete(m.fileWatchers, ticketID)
+	}
+	if !m.fileFeed {
+		return nil
+	}
+
+	for ticketID, pane := range m.panes {
+		if _, watched := m.fileWatchers[ticketID]; watched || !pane.Running() {
+			continue
+		}
+		dir := pane.GetWorkdir()
+		if dir == "" {
+			if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+				dir = ticket.WorktreePath
+			}
+		}
+		if dir == "" {
+			continue
+		}
+		if m.fileWatchers == nil {
+			m.fileWatchers = make(map[board.TicketID]*filewatch.Watcher)
+		}
+		m.fileWatchers[ticketID] = nil
+		cmds = append(cmds, startFileWatch(ticketID, dir))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startFileWatch starts a watcher in the background; adding a watch for
+// each directory of a large worktree takes a while
+func startFileWatch(ticketID board.TicketID, dir string) tea.Cmd {
+	return func() tea.Msg {
+		w, err := filewatch.New(dir)
+		return fileWatchStartedMsg{ticketID: ticketID, dir: dir, watcher: w, err: err}
+	}
+}
+
+func waitFileChanges(ticketID board.TicketID, w *filewatch.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		changes, ok := w.Next()
+		return fileChangesMsg{ticketID: ticketID, watcher: w, changes: changes, ok: ok}
+	}
+}
+
+func (m *Model) handleFileWatchStarted(msg fileWatchStartedMsg) (tea.Model, tea.Cmd) {
+	w, pending := m.fileWatchers[msg.ticketID]
+	if msg.err != nil {
+		if pending && w == nil {
+			// Left in place so the next sync doesn't retry every few seconds
+			m.logError("Failed to watch " + msg.dir + " for the file feed: " + msg.err.Error())
+		}
+		return m, nil
+	}
+	if !pending || w != nil {
+		msg.watcher.Close()
+		return m, nil
+	}
+	m.fileWatchers[msg.ticketID] = msg.watcher
+	if m.fileFeeds == nil {
+		m.fileFeeds = make(map[board.TicketID]*fileFeed)
+	}
+	m.fileFeeds[msg.ticketID] = &fileFeed{files: make(map[string]bool)}
+	return m, waitFileChanges(msg.ticketID, msg.watcher)
+}
+
+func (m *Model) handleFileChanges(msg fileChangesMsg) (tea.Model, tea.Cmd) {
+	if m.fileWatchers[msg.ticketID] != msg.watcher {
+		return m, nil
+	}
+	if feed := m.fileFeeds[msg.ticketID]; feed != nil {
+		for _, c := range msg.changes {
+			feed.add(c)
+		}
+	}
+	if !msg.ok {
+		delete(m.fileWatchers, msg.ticketID)
+		return m, nil
+	}
+	return m, waitFileChanges(msg.ticketID, msg.watcher)
+}
+
+// closeFileWatchers stops every watcher, on the way out
+func (m *Model) closeFileWatchers() {
+	for _, w := range m.fileWatchers {
+		if w != nil {
+			w.Close()
+		}
+	}
+	m.fileWatchers = nil
+}
+
+// renderFileBadge shows how many files a ticket's agent has touched
+func (m *Model) renderFileBadge(id board.TicketID) string {
+	if !m.fileFeed {
+		return ""
+	}
+	feed := m.fileFeeds[id]
+	if feed == nil || len(feed.files) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.subtext).Render(fmt.Sprintf("✎ %d", len(feed.files)))
+}
+
+// renderFileFeed lists the files the selected card's agent is changing,
+// newest first
+func (m *Model) renderFileFeed(width, height int) string {
+	inner := max(width-3, 1)
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+
+	title := "File feed"
+	var count string
+	var body []string
+	ticket := m.selectedTicket()
+	var feed *fileFeed
+	if ticket != nil {
+		feed = m.fileFeeds[ticket.ID]
+	}
+	switch {
+	case feed != nil:
+		title = "✎ " + ticket.Title
+		count = m.dimStyle().Render(fmt.Sprintf("%d files", len(feed.files)))
+		opStyles := map[filewatch.Op]struct {
+			glyph string
+			color lipgloss.Color
+		}{
+			filewatch.Created:  {"+", m.colors.success},
+			filewatch.Modified: {"~", m.colors.warning},
+			filewatch.Removed:  {"-", m.colors.err},
+		}
+		rows := max(height-3, 1)
+		for i := len(feed.changes) - 1; i >= 0 && len(body) < rows; i-- {
+			c := feed.changes[i]
+			op := opStyles[c.Op]
+			prefix := m.dimStyle().Render(c.At.Format("15:04:05")+" ") +
+				lipgloss.NewStyle().Foreground(op.color).Render(op.glyph) + " "
+			body = append(body, prefix+truncatePath(c.Path, max(inner-lipgloss.Width(prefix), 1)))
+		}
+		if len(body) == 0 {
+			body = []string{m.dimStyle().Render("No changes yet")}
+		}
+	default:
+		body = []string{m.dimStyle().Render("Select a card with a running agent")}
+	}
+
+	title = titleStyle.Render(truncate(title, max(inner-lipgloss.Width(count)-1, 1)))
+	spacing := max(inner-lipgloss.Width(title)-lipgloss.Width(count), 1)
+	header := title + strings.Repeat(" ", spacing) + count
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(m.colors.surface).
+		PaddingLeft(1).
+		Width(width - 1).
+		Height(height).
+		MaxHeight(height).
+		Render(header + "\n\n" + strings.Join(body, "\n"))
+}
+
+// truncatePath shortens a path from the left, keeping the file name
+func truncatePath(path string, width int) string {
+	r := []rune(path)
+	if len(r) <= width {
+		return path
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return "…" + string(r[len(r)-width+1:])
+}
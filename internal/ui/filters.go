@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// savedFiltersView is the menu of named filters. While naming is set it
+// asks for the name to save the current filter under.
+type savedFiltersView struct {
+	index  int
+	offset int
+	naming bool
+	input  textinput.Model
+	err    string
+}
+
+func (m *Model) openSavedFilters() (tea.Model, tea.Cmd) {
+	if m.filters == nil {
+		reg, err := project.LoadFilterRegistry()
+		if err != nil {
+			m.notify("Failed to load saved filters: " + err.Error())
+			return m, nil
+		}
+		m.filters = reg
+	}
+	input := textinput.New()
+	input.Placeholder = "Name"
+	input.Prompt = "Save as › "
+	input.CharLimit = 40
+	m.savedFilters = &savedFiltersView{input: input}
+	m.mode = ModeSavedFilters
+	return m, nil
+}
+
+// applySavedFilter replaces the board's filter with f
+func (m *Model) applySavedFilter(f *project.SavedFilter) {
+	m.filterProjectIDs = make(map[string]bool)
+	for _, id := range f.ProjectIDs {
+		if m.globalStore.GetProject(id) != nil {
+			m.filterProjectIDs[id] = true
+		}
+	}
+	m.filterQuery = f.Expression()
+	m.filterInput.SetValue(m.filterQuery)
+	m.refreshColumnTickets()
+	m.activeTicket = 0
+	m.ensureTicketVisible()
+}
+
+// ApplyDefaultFilter filters the board with the saved filter marked as
+// default, if any. Launching into a project or ticket skips it.
+func (m *Model) ApplyDefaultFilter() {
+	reg, err := project.LoadFilterRegistry()
+	if err != nil {
+		m.notify("Failed to load saved filters: " + err.Error())
+		return
+	}
+	m.filters = reg
+	if f := reg.GetDefault(); f != nil {
+		m.applySavedFilter(f)
+	}
+}
+
+func (m *Model) handleSavedFiltersMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.savedFilters
+	if v.naming {
+		return m.handleFilterNameKey(msg)
+	}
+	list := m.filters.List()
+	height := m.savedFiltersListHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.savedFilters = nil
+		m.mode = ModeNormal
+	case "j", "down":
+		v.selectItem(v.index+1, len(list), height)
+	case "k", "up":
+		v.selectItem(v.index-1, len(list), height)
+	case "enter":
+		if v.index < len(list) {
+			m.applySavedFilter(list[v.index])
+			m.savedFilters = nil
+			m.mode = ModeNormal
+			m.notify("Filter: " + list[v.index].Name)
+		}
+	case "s":
+		if m.filterQuery == "" && len(m.filterProjectIDs) == 0 {
+			v.err = "nothing to save; filter the board with / first"
+			return m, nil
+		}
+		if _, err := m.boardQuery(); m.filterQuery != "" && err != nil {
+			v.err = err.Error()
+			return m, nil
+		}
+		v.naming, v.err = true, ""
+		v.input.SetValue("")
+		v.input.Focus()
+		return m, textinput.Blink
+	case "d":
+		if v.index < len(list) {
+			f := list[v.index]
+			if err := m.filters.Delete(f.ID); err != nil {
+				v.err = err.Error()
+				return m, nil
+			}
+			v.selectItem(v.index, len(list)-1, height)
+			m.notify("Deleted filter " + f.Name)
+		}
+	case "*":
+		if v.index < len(list) {
+			f := list[v.index]
+			if f.IsDefault {
+				f = nil
+			}
+			if err := m.filters.SetDefault(f); err != nil {
+				v.err = err.Error()
+			}
+		}
+	}
+	return m, nil
+}
+
+// handleFilterNameKey saves the board's current filter under the name
+// typed, replacing a filter already saved under it
+func (m *Model) handleFilterNameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.savedFilters
+	switch msg.String() {
+	case "esc":
+		v.naming = false
+		v.input.Blur()
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(v.input.Value())
+		if name == "" {
+			return m, nil
+		}
+		f := m.filters.FindByName(name)
+		if f == nil {
+			f = project.NewFilter(name)
+		}
+		f.Statuses, f.Labels = nil, nil
+		f.Query = m.filterQuery
+		f.ProjectIDs = f.ProjectIDs[:0]
+		for id := range m.filterProjectIDs {
+			f.ProjectIDs = append(f.ProjectIDs, id)
+		}
+		sort.Strings(f.ProjectIDs)
+		if err := m.filters.Add(f); err != nil {
+			v.err = err.Error()
+			return m, nil
+		}
+		v.naming = false
+		v.input.Blur()
+		for i, saved := range m.filters.List() {
+			if saved == f {
+				v.selectItem(i, len(m.filters.Filters), m.savedFiltersListHeight())
+			}
+		}
+		m.notify("Saved filter " + name)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return m, cmd
+}
+
+func (v *savedFiltersView) selectItem(i, count, height int) {
+	v.index = max(min(i, count-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+func (m *Model) savedFiltersListHeight() int {
+	return max(m.height-18, 3)
+}
+
+// describeSavedFilter writes a filter's projects and expression for the menu
+func (m *Model) describeSavedFilter(f *project.SavedFilter) string {
+	var parts []string
+	for _, id := range f.ProjectIDs {
+		if p := m.globalStore.GetProject(id); p != nil {
+			parts = append(parts, "@"+p.Name)
+		}
+	}
+	if expr := f.Expression(); expr != "" {
+		parts = append(parts, expr)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (m *Model) renderSavedFiltersView() string {
+	v := m.savedFilters
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(90, m.width-4)
+	inner := width - 6
+	list := m.filters.List()
+
+	lines := []string{titleStyle.Render("Saved Filters"), ""}
+	if len(list) == 0 {
+		lines = append(lines, m.dimStyle().Render("  None yet. Filter the board with / and press s here to save it."))
+	}
+
+	height := m.savedFiltersListHeight()
+	end := min(v.offset+height, len(list))
+	for i := v.offset; i < end; i++ {
+		f := list[i]
+		style := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == v.index {
+			style = style.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		marker := "  "
+		if f.IsDefault {
+			marker = lipgloss.NewStyle().Foreground(m.colors.warning).Render("★ ")
+		}
+		row := cursor + marker + style.Render(truncate(f.Name, 24))
+		desc := truncate(m.describeSavedFilter(f), max(inner-lipgloss.Width(row)-2, 10))
+		lines = append(lines, row+m.dimStyle().Render("  "+desc))
+	}
+	if len(list) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(list)-end)))
+	}
+
+	if v.naming {
+		lines = append(lines, "", v.input.View())
+	}
+	if v.err != "" {
+		lines = append(lines, "", "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+v.err))
+	}
+
+	current := m.filterQuery
+	if n := len(m.filterProjectIDs); n > 0 {
+		current = strings.TrimSpace(fmt.Sprintf("%d project(s) %s", n, current))
+	}
+	if current != "" {
+		lines = append(lines, "", m.dimStyle().Render("Current: "+truncate(current, inner-9)))
+	}
+
+	help := keyStyle.Render("[enter]") + m.dimStyle().Render(" Apply  ") +
+		keyStyle.Render("[s]") + m.dimStyle().Render(" Save current  ") +
+		keyStyle.Render("[d]") + m.dimStyle().Render(" Delete  ") +
+		keyStyle.Render("[*]") + m.dimStyle().Render(" Default  ") +
+		keyStyle.Render("[esc]") + m.dimStyle().Render(" Close")
+	if v.naming {
+		help = keyStyle.Render("[enter]") + m.dimStyle().Render(" Save  ") +
+			keyStyle.Render("[esc]") + m.dimStyle().Render(" Cancel")
+	}
+	lines = append(lines, "", help)
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// toggleFocusMode swaps the board for a single column of in-progress
+// tickets from every project, most recently active first
+func (m *Model) toggleFocusMode() {
+	m.focusMode = !m.focusMode
+	if m.focusMode {
+		m.boardColumns = m.columns
+		m.columns = focusColumns(m.columns)
+		m.notify("Focus mode: in-progress tickets from all projects")
+	} else {
+		m.columns = m.boardColumns
+		m.boardColumns = nil
+		m.notify("Focus mode off")
+	}
+	m.columnOffsets = nil
+	m.scrollOffset = 0
+	m.activeColumn = 0
+	m.activeTicket = 0
+	m.refreshColumnTickets()
+	if !m.focusMode {
+		// Land back on the in-progress column rather than the backlog
+		for i, col := range m.columns {
+			if col.Status == board.StatusInProgress {
+				m.activeColumn = i
+				break
+			}
+		}
+	}
+	m.ensureColumnVisible()
+	m.ensureTicketVisible()
+}
+
+// refreshFocus re-sorts focus mode's column as tickets see activity,
+// keeping the selection
+func (m *Model) refreshFocus() {
+	if !m.focusMode {
+		return
+	}
+	selected := m.selectedTicket()
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+}
+
+// focusColumns keeps the in-progress columns, without their WIP limits,
+// which are meant for one project at a time
+func focusColumns(columns []board.Column) []board.Column {
+	var focus []board.Column
+	for _, col := range columns {
+		if col.Status == board.StatusInProgress {
+			col.Limit = 0
+			focus = append(focus, col)
+		}
+	}
+	return focus
+}
+
+// sortByActivity orders tickets by their last activity, newest first
+func (m *Model) sortByActivity(tickets []*board.Ticket) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return m.lastActivity(tickets[i]).After(m.lastActivity(tickets[j]))
+	})
+}
+
+// lastActivity is the latest edit to a ticket, agent run or change in its
+// agent's state seen this session
+func (m *Model) lastActivity(t *board.Ticket) time.Time {
+	last := t.UpdatedAt
+	if at := m.agentActivity[t.ID]; at.After(last) {
+		last = at
+	}
+	for _, run := range t.AgentRuns {
+		if run.StartedAt.After(last) {
+			last = run.StartedAt
+		}
+		if run.EndedAt != nil && run.EndedAt.After(last) {
+			last = *run.EndedAt
+		}
+	}
+	return last
+}
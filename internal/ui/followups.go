@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/agent"
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+type followUpsEditedMsg struct {
+	ticketID board.TicketID
+	path     string
+	err      error
+}
+
+// backlogFollowUps turns the work the selected ticket's agent left undone
+// into backlog tickets: the lines selected in its pane, or else the last
+// list of remaining work in its output. The items open in the editor first,
+// to drop or reword them.
+func (m *Model) backlogFollowUps() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("create tickets") {
+		return m, nil
+	}
+
+	var items []string
+	if pane, ok := m.panes[ticket.ID]; ok {
+		if selected := pane.Selection(); selected != "" {
+			items = agent.ListItems(selected)
+		} else {
+			items = agent.FollowUps(pane.Transcript())
+		}
+	} else {
+		items = agent.FollowUps(m.transcripts[ticket.ID])
+	}
+	if len(items) == 0 {
+		m.notify("No follow-ups in the agent's output; select them in its pane")
+		return m, nil
+	}
+
+	ticketID := ticket.ID
+	cmd, err := editText(strings.Join(items, "\n")+"\n", func(path string, err error) tea.Msg {
+		return followUpsEditedMsg{ticketID: ticketID, path: path, err: err}
+	})
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+	}
+	return m, cmd
+}
+
+// handleFollowUpsEdited adds a backlog ticket for each line left in the
+// editor
+func (m *Model) handleFollowUpsEdited(msg followUpsEditedMsg) (tea.Model, tea.Cmd) {
+	text, err := readEditedText(msg.path, msg.err)
+	if err != nil {
+		m.notify("Failed to edit follow-ups: " + err.Error())
+		return m, nil
+	}
+	source, _ := m.globalStore.Get(msg.ticketID)
+	if source == nil {
+		return m, nil
+	}
+
+	created := 0
+	for _, title := range agent.ListItems(text) {
+		if _, err := m.addLinkedTicket(source, title, ""); err != nil {
+			m.notify("Failed to create follow-up: " + err.Error())
+			break
+		}
+		created++
+	}
+	if created == 0 {
+		return m, nil
+	}
+
+	selected := m.selectedTicket()
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.notify(fmt.Sprintf("Added %d follow-up ticket(s) to the backlog", created))
+	return m, nil
+}
+
+// ticketFromSelection opens the new ticket form for a Backlog ticket in the
+// project of the ticket whose pane text is selected, with the text as its
+// description and a link back to that ticket
+func (m *Model) ticketFromSelection(sourceID board.TicketID, selected string) (tea.Model, tea.Cmd) {
+	source, _ := m.globalStore.Get(sourceID)
+	if source == nil {
+		return m, nil
+	}
+	m.selectedProject = m.globalStore.GetProjectForTicket(source)
+	m.focusedPane = ""
+	model, cmd := m.createNewTicket()
+	if m.mode == ModeCreateTicket {
+		m.ticketSource = source.ID
+		m.descInput.SetValue(m.linkedDescription(source, selected))
+	}
+	return model, cmd
+}
+
+// linkedDescription adds a line naming source to the description of a
+// ticket split off from it
+func (m *Model) linkedDescription(source *board.Ticket, description string) string {
+	origin := source.Title
+	if key := m.globalStore.TicketKey(source); key != "" {
+		origin = key + " " + origin
+	}
+	return strings.TrimSpace(strings.TrimSpace(description) + "\n\nFrom " + origin)
+}
+
+// addLinkedTicket adds a backlog ticket to source's project, linked back to
+// source. The description says where it came from.
+func (m *Model) addLinkedTicket(source *board.Ticket, title, description string) (*board.Ticket, error) {
+	ticket := board.NewTicket(title, source.ProjectID)
+	ticket.Description = m.linkedDescription(source, description)
+	ticket.Source = source.ID
+	if err := m.globalStore.Add(ticket); err != nil {
+		return nil, err
+	}
+	m.saveTicket(ticket)
+	m.publish(m.newEvent(events.TicketCreated, ticket))
+	return ticket, nil
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// gitOp is a slow git operation running in the background for a ticket,
+// such as creating or removing its worktree. The card shows its label with
+// a spinner until it finishes.
+type gitOp struct {
+	label  string
+	cancel context.CancelFunc // nil when the operation can't be cancelled
+}
+
+type worktreeCreatedMsg struct {
+	ticketID board.TicketID
+	from     board.TicketStatus // where the ticket was moved from
+	path     string
+	base     string
+	err      error
+}
+
+type worktreeRemovedMsg struct {
+	ticketID  board.TicketID
+	title     string
+	branch    string // deleted along with the worktree, if set
+	deleted   bool   // the ticket itself is gone, so there is nothing to update
+	err       error
+	branchErr error
+}
+
+// startGitOp registers an operation for ticketID and returns the context it
+// runs under
+func (m *Model) startGitOp(ticketID board.TicketID, label string, cancellable bool) context.Context {
+	if m.gitOps == nil {
+		m.gitOps = make(map[board.TicketID]*gitOp)
+	}
+	op := &gitOp{label: label}
+	ctx := context.Background()
+	if cancellable {
+		ctx, op.cancel = context.WithCancel(ctx)
+	}
+	m.gitOps[ticketID] = op
+	return ctx
+}
+
+func (m *Model) endGitOp(ticketID board.TicketID) {
+	if op, ok := m.gitOps[ticketID]; ok {
+		if op.cancel != nil {
+			op.cancel()
+		}
+		delete(m.gitOps, ticketID)
+	}
+}
+
+// cancelGitOp stops the ticket's operation, reporting whether there was one
+// that could be stopped
+func (m *Model) cancelGitOp(ticketID board.TicketID) bool {
+	op, ok := m.gitOps[ticketID]
+	if !ok || op.cancel == nil {
+		return false
+	}
+	op.cancel()
+	return true
+}
+
+// createWorktreeCmd creates the ticket's worktree off the UI goroutine. The
+// ticket has already moved, and moves back to from if creation fails.
+func (m *Model) createWorktreeCmd(ticket *board.Ticket, mgr *git.WorktreeManager, branchName, baseBranch string, from board.TicketStatus) tea.Cmd {
+	ticketID := ticket.ID
+	ctx := m.startGitOp(ticketID, "creating worktree", true)
+	return func() tea.Msg {
+		base := baseBranch
+		if base == "" {
+			base, _ = mgr.GetDefaultBranch()
+		}
+		path, err := mgr.CreateWorktreeContext(ctx, branchName, base)
+		return worktreeCreatedMsg{ticketID: ticketID, from: from, path: path, base: base, err: err}
+	}
+}
+
+func (m *Model) handleWorktreeCreated(msg worktreeCreatedMsg) (tea.Model, tea.Cmd) {
+	m.endGitOp(msg.ticketID)
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			m.notify("Cancelled worktree for " + truncate(ticket.Title, 30))
+		} else {
+			m.notify("Worktree failed: " + msg.err.Error())
+		}
+		// Without a worktree the ticket can't be worked on, so undo the move
+		if ticket.Status == board.StatusInProgress && ticket.WorktreePath == "" {
+			m.globalStore.Move(ticket.ID, msg.from)
+			m.refreshColumnTickets()
+			m.saveTicket(ticket)
+			m.publishTicketMoved(ticket, board.StatusInProgress)
+		}
+		return m, nil
+	}
+
+	ticket.WorktreePath = msg.path
+	ticket.BaseBranch = msg.base
+	m.saveTicket(ticket)
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		return m, m.runWorktreeSetup(ticket, proj)
+	}
+	return m, nil
+}
+
+// removeWorktreeCmd removes a worktree, and the branch when set, off the UI
+// goroutine. deleted is set when the ticket itself is being deleted.
+func (m *Model) removeWorktreeCmd(ticket *board.Ticket, mgr *git.WorktreeManager, path, branch string, deleted bool) tea.Cmd {
+	ticketID, title := ticket.ID, ticket.Title
+	m.startGitOp(ticketID, "removing worktree", false)
+	return func() tea.Msg {
+		msg := worktreeRemovedMsg{ticketID: ticketID, title: title, branch: branch, deleted: deleted}
+		if path != "" {
+			if msg.err = mgr.RemoveWorktree(path); msg.err != nil {
+				return msg
+			}
+		}
+		if branch != "" {
+			msg.branchErr = mgr.DeleteBranch(branch)
+		}
+		return msg
+	}
+}
+
+func (m *Model) handleWorktreeRemoved(msg worktreeRemovedMsg) (tea.Model, tea.Cmd) {
+	m.endGitOp(msg.ticketID)
+	if msg.err != nil {
+		m.notify("Failed to remove worktree: " + msg.err.Error())
+		return m, nil
+	}
+	if !msg.deleted {
+		if ticket, _ := m.globalStore.Get(msg.ticketID); ticket != nil {
+			ticket.WorktreePath = ""
+			m.saveTicket(ticket)
+		}
+	}
+	switch {
+	case msg.branchErr != nil:
+		m.notify("Failed to delete branch: " + msg.branchErr.Error())
+	case msg.deleted:
+		// The ticket's deletion was already reported
+	case msg.branch != "":
+		m.notify("Removed worktree and branch " + msg.branch)
+	default:
+		m.notify("Removed worktree of " + msg.title)
+	}
+	return m, nil
+}
+
+// renderGitOpBadge shows a running git operation on a ticket card
+func (m *Model) renderGitOpBadge(ticketID board.TicketID) string {
+	op, ok := m.gitOps[ticketID]
+	if !ok {
+		return ""
+	}
+	label := op.label
+	if op.cancel != nil {
+		label += " (" + m.keyHint(keymap.StopAgent) + " cancel)"
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.info).Render(m.spinner.View() + label)
+}
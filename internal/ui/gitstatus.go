@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+type worktreeStatusTickMsg time.Time
+type worktreeStatusResultMsg struct {
+	statuses  map[board.TicketID]git.WorktreeStatus
+	conflicts map[board.TicketID][]string
+}
+
+func tickWorktreeStatus(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return worktreeStatusTickMsg(t)
+	})
+}
+
+// worktreeStatusInterval is how often worktrees are checked, following
+// ui.refresh_interval
+func (m *Model) worktreeStatusInterval() time.Duration {
+	return time.Duration(max(m.config.UI.RefreshInterval, 1)) * time.Second
+}
+
+// handleWorktreeStatusTick starts a background check of every ticket
+// worktree, trial-merging the branches of Done tickets into their base, and
+// schedules the next tick. A tick that arrives while the
+// previous check is still running is skipped, so slow repositories never
+// pile up git processes.
+func (m *Model) handleWorktreeStatusTick() (tea.Model, tea.Cmd) {
+	next := tickWorktreeStatus(m.worktreeStatusInterval())
+	if !m.config.UI.ShowGitStatus || m.worktreeStatusBusy {
+		return m, next
+	}
+
+	type target struct {
+		ticketID board.TicketID
+		path     string
+		base     string
+		branch   string
+		project  string
+		done     bool
+	}
+	var targets []target
+	mgrs := make(map[string]*git.WorktreeManager)
+	for _, t := range m.globalStore.All() {
+		if t.WorktreePath == "" {
+			continue
+		}
+		targets = append(targets, target{t.ID, t.WorktreePath, t.BaseBranch, t.BranchName, t.ProjectID, t.Status == board.StatusDone})
+		mgrs[t.ProjectID] = m.worktreeMgrs[t.ProjectID]
+	}
+	if len(targets) == 0 {
+		m.worktreeStatus = nil
+		m.mergeConflicts = nil
+		return m, next
+	}
+
+	defaultBranches := make(map[string]string)
+	m.worktreeStatusBusy = true
+	check := func() tea.Msg {
+		result := worktreeStatusResultMsg{
+			statuses:  make(map[board.TicketID]git.WorktreeStatus, len(targets)),
+			conflicts: make(map[board.TicketID][]string),
+		}
+		for _, t := range targets {
+			if _, err := os.Stat(t.path); err != nil {
+				continue
+			}
+			base := t.base
+			if base == "" {
+				if b, ok := defaultBranches[t.project]; ok {
+					base = b
+				} else if mgr := mgrs[t.project]; mgr != nil {
+					base, _ = mgr.GetDefaultBranch()
+					defaultBranches[t.project] = base
+				}
+			}
+			if st, err := git.Status(t.path, base); err == nil {
+				result.statuses[t.ticketID] = st
+			}
+			if t.done && t.branch != "" && base != "" {
+				if conflicts, err := git.TrialMerge(t.path, base, t.branch); err == nil {
+					result.conflicts[t.ticketID] = conflicts
+				}
+			}
+		}
+		return result
+	}
+	return m, tea.Batch(check, next)
+}
+
+func (m *Model) applyWorktreeStatuses(msg worktreeStatusResultMsg) {
+	m.worktreeStatusBusy = false
+	m.worktreeStatus = msg.statuses
+	m.mergeConflicts = msg.conflicts
+}
+
+// renderGitBadge shows uncommitted changes, commits ahead of or behind the
+// base branch, whether the branch is pushed and, for Done tickets, how many
+// files would conflict when merging, or "" when there is nothing to report
+func (m *Model) renderGitBadge(ticketID board.TicketID) string {
+	if !m.config.UI.ShowGitStatus {
+		return ""
+	}
+	st, ok := m.worktreeStatus[ticketID]
+	if !ok {
+		return ""
+	}
+
+	var parts []string
+	if st.Changed > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("✎%d", st.Changed)))
+	}
+	if st.Ahead > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.success).Render(fmt.Sprintf("↑%d", st.Ahead)))
+	}
+	if st.Behind > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.muted).Render(fmt.Sprintf("↓%d", st.Behind)))
+	}
+	if remote := m.renderRemoteBadge(st); remote != "" {
+		parts = append(parts, remote)
+	}
+	if conflicts := len(m.mergeConflicts[ticketID]); conflicts > 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.colors.err).Render(fmt.Sprintf("⚔%d", conflicts)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// syntax describes just enough of a language to color a line of it
+type syntax struct {
+	comment  string
+	keywords map[string]bool
+}
+
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	goSyntax = syntax{"//", words(`break case chan const continue default defer else fallthrough for func go goto if
+		import interface map package range return select struct switch type var nil true false`)}
+	jsSyntax = syntax{"//", words(`async await break case catch class const continue default delete do else export
+		extends false finally for from function if import in instanceof interface let new null return switch this
+		throw true try type typeof undefined var void while yield`)}
+	pySyntax = syntax{"#", words(`and as assert async await break class continue def del elif else except False
+		finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield`)}
+	rustSyntax = syntax{"//", words(`as async await break const continue crate else enum false fn for if impl in let
+		loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while`)}
+	shSyntax = syntax{"#", words(`case do done elif else esac export fi for function if in local return then until while`)}
+	// Config formats get comments, strings and numbers but no keywords
+	hashSyntax = syntax{comment: "#"}
+)
+
+var syntaxByExt = map[string]syntax{
+	".go":   goSyntax,
+	".js":   jsSyntax,
+	".jsx":  jsSyntax,
+	".ts":   jsSyntax,
+	".tsx":  jsSyntax,
+	".mjs":  jsSyntax,
+	".py":   pySyntax,
+	".rs":   rustSyntax,
+	".sh":   shSyntax,
+	".bash": shSyntax,
+	".zsh":  shSyntax,
+	".yaml": hashSyntax,
+	".yml":  hashSyntax,
+	".toml": hashSyntax,
+}
+
+// syntaxFor returns the syntax for path and whether it is known
+func syntaxFor(path string) (syntax, bool) {
+	s, ok := syntaxByExt[strings.ToLower(filepath.Ext(path))]
+	return s, ok
+}
+
+// highlight colors keywords, strings, comments and numbers in a single line
+// of code. Tokens that span lines, such as block comments, are not tracked.
+func (m *Model) highlight(line string, lang syntax, base lipgloss.Style) string {
+	keyword := base.Foreground(m.colors.secondary)
+	str := base.Foreground(m.colors.success)
+	comment := base.Foreground(m.colors.muted)
+	number := base.Foreground(m.colors.warning)
+
+	var b strings.Builder
+	runes := []rune(line)
+	plain := 0 // start of the pending run of unstyled text
+	flush := func(end int) {
+		if end > plain {
+			b.WriteString(base.Render(string(runes[plain:end])))
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case lang.comment != "" && strings.HasPrefix(string(runes[i:]), lang.comment):
+			flush(i)
+			b.WriteString(comment.Render(string(runes[i:])))
+			return b.String()
+
+		case r == '"' || r == '\'' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			flush(i)
+			b.WriteString(str.Render(string(runes[i:end])))
+			i, plain = end, end
+
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(runes[i-1])):
+			end := i
+			for end < len(runes) && (isIdentRune(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			flush(i)
+			b.WriteString(number.Render(string(runes[i:end])))
+			i, plain = end, end
+
+		case isIdentRune(r):
+			end := i
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			if lang.keywords[string(runes[i:end])] {
+				flush(i)
+				b.WriteString(keyword.Render(string(runes[i:end])))
+				plain = end
+			}
+			i = end
+
+		default:
+			i++
+		}
+	}
+	flush(len(runes))
+	return b.String()
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// applyHighlights gives the agent panes ui.highlights, with theme colors
+// resolved. The slice is replaced rather than reused, as spawns in flight
+// hold the old one.
+func (m *Model) applyHighlights() {
+	m.highlights = nil
+	for _, rule := range m.config.UI.Highlights {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil || rule.Pattern == "" {
+			continue
+		}
+		h := terminal.Highlight{Pattern: re, Bold: rule.Bold, Underline: rule.Underline}
+		if color, ok := m.colorRef(rule.Color); ok {
+			h.Color = string(color)
+		}
+		m.highlights = append(m.highlights, h)
+	}
+	for _, pane := range m.panes {
+		pane.SetHighlights(m.highlights)
+	}
+}
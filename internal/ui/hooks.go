@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+type hookErrorMsg struct {
+	err error
+}
+
+type webhookErrorMsg struct {
+	err error
+}
+
+// newEvent builds an event for ticket. The ticket is cloned so hooks can
+// marshal it off the update goroutine.
+func (m *Model) newEvent(t events.Type, ticket *board.Ticket) events.Event {
+	var projectName string
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		projectName = proj.Name
+	}
+	return events.New(t, ticket.Clone(), projectName)
+}
+
+// Events returns the bus on which the board publishes its events.
+func (m *Model) Events() *events.Bus {
+	return m.bus
+}
+
+// publish puts e on the bus. The board's own subscriber runs its hooks,
+// notifications and tracker updates; see handleBusEvent.
+func (m *Model) publish(e events.Event) {
+	m.bus.Publish(e)
+}
+
+func (m *Model) publishTicketMoved(ticket *board.Ticket, from board.TicketStatus) {
+	if from == ticket.Status {
+		return
+	}
+	e := m.newEvent(events.TicketMoved, ticket)
+	e.From = from
+	e.To = ticket.Status
+	m.publish(e)
+}
+
+func (m *Model) publishAgentFailed(ticket *board.Ticket, reason string) {
+	e := m.newEvent(events.AgentFailed, ticket)
+	e.Error = reason
+	m.publish(e)
+}
+
+// runHooks runs the hooks and sends the webhook and desktop notifications
+// configured for e in the background. While notifications are quiet, those
+// that would have gone out are only kept in the notification history.
+func (m *Model) runHooks(e events.Event) tea.Cmd {
+	var cmds []tea.Cmd
+	if m.hooks.Has(e.Type) {
+		runner := m.hooks
+		cmds = append(cmds, func() tea.Msg {
+			if err := runner.Run(e); err != nil {
+				return hookErrorMsg{err: err}
+			}
+			return nil
+		})
+	}
+	_, desktop := m.desktopTitle(e)
+	if m.config.Notifications.Quiet(time.Now()) {
+		if desktop || m.notifier.Wants(e) {
+			m.addNotice(notice{text: "Held back: " + events.Summary(e), at: time.Now(), quiet: true})
+		}
+		return tea.Batch(cmds...)
+	}
+	if m.notifier.Wants(e) {
+		notifier := m.notifier
+		cmds = append(cmds, func() tea.Msg {
+			if err := notifier.Send(e); err != nil {
+				return webhookErrorMsg{err: err}
+			}
+			return nil
+		})
+	}
+	cmds = append(cmds, m.notifyDesktop(e))
+	return tea.Batch(cmds...)
+}
+
+// setAgentStatus records a ticket's agent state, publishing the change
+func (m *Model) setAgentStatus(ticket *board.Ticket, status board.AgentStatus) {
+	prev := ticket.AgentStatus
+	ticket.AgentStatus = status
+	if prev == status {
+		return
+	}
+	e := m.newEvent(events.AgentStateChanged, ticket)
+	e.AgentFrom, e.AgentTo = prev, status
+	m.publish(e)
+}
+
+// applyAgentStatuses stores polled agent statuses and publishes every
+// transition, along with the completed, error and waiting events.
+func (m *Model) applyAgentStatuses(statuses agentStatusResultMsg) {
+	for ticketID, status := range statuses {
+		ticket, _ := m.globalStore.Get(ticketID)
+		if ticket == nil {
+			continue
+		}
+		prev := ticket.AgentStatus
+		m.setAgentStatus(ticket, status)
+		if prev == status {
+			continue
+		}
+		switch status {
+		case board.AgentCompleted:
+			m.publish(m.newEvent(events.AgentCompleted, ticket))
+		case board.AgentError:
+			m.publishAgentFailed(ticket, "agent reported an error")
+		case board.AgentWaiting:
+			m.publish(m.newEvent(events.AgentWaiting, ticket))
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// runPane starts command in an embedded pane and reads its output until it
+// exits, returning the exit message the board would get
+func runPane(t *testing.T, pane *terminal.Pane, command string, args ...string) terminal.ExitMsg {
+	t.Helper()
+	pending := []tea.Cmd{pane.Start(command, args...)}
+	for len(pending) > 0 {
+		cmd := pending[0]
+		pending = pending[1:]
+		if cmd == nil {
+			continue
+		}
+		switch msg := cmd().(type) {
+		case terminal.ExitMsg:
+			return msg
+		case tea.BatchMsg:
+			pending = append(pending, msg...)
+		case terminal.OutputMsg:
+			pending = append(pending, pane.Update(msg))
+		}
+	}
+	t.Fatal("pane did not exit")
+	return terminal.ExitMsg{}
+}
+
+func TestHandleAgentExit_Outcome(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	tests := []struct {
+		name    string
+		script  string
+		want    events.Type
+		outcome board.AgentOutcome
+	}{
+		{name: "zero exit completes", script: "echo done", want: events.AgentCompleted, outcome: board.OutcomeCompleted},
+		{name: "non-zero exit fails", script: "exit 3", want: events.AgentFailed, outcome: board.OutcomeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newBenchModel(t, 1)
+			ticket := m.globalStore.All()[0]
+			ticket.StartAgentRun("claude")
+
+			pane := terminal.New(string(ticket.ID), 80, 24, 100)
+			m.panes[ticket.ID] = pane
+			exit := runPane(t, pane, "sh", "-c", tt.script)
+
+			published, unsubscribe := m.Events().Subscribe()
+			defer unsubscribe()
+			m.Update(exit)
+
+			var got []events.Type
+			for len(published) > 0 {
+				e := <-published
+				if e.Type == events.AgentCompleted || e.Type == events.AgentFailed {
+					got = append(got, e.Type)
+				}
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("published %v; want only %s", got, tt.want)
+			}
+			if run := ticket.AgentRuns[len(ticket.AgentRuns)-1]; run.Outcome != tt.outcome {
+				t.Errorf("run outcome = %q; want %q", run.Outcome, tt.outcome)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// Agent state glyphs. Each state has its own shape so cards never rely on
+// color alone.
+const (
+	glyphNone      = "○"
+	glyphIdle      = "◆"
+	glyphWorking   = "●"
+	glyphWaiting   = "◐"
+	glyphCompleted = "✔"
+	glyphError     = "✖"
+)
+
+// agentIndicator returns the glyph and color marking an agent state. Working
+// agents show the spinner unless ui.symbolic_indicators asks for a glyph
+// that holds still.
+func (m *Model) agentIndicator(status board.AgentStatus) (string, lipgloss.Color) {
+	switch status {
+	case board.AgentIdle:
+		return glyphIdle, m.colors.primary
+	case board.AgentWorking:
+		if m.config.UI.SymbolicIndicators {
+			return glyphWorking, m.colors.warning
+		}
+		return m.spinner.View(), m.colors.warning
+	case board.AgentWaiting:
+		return glyphWaiting, m.colors.secondary
+	case board.AgentCompleted:
+		return glyphCompleted, m.colors.success
+	case board.AgentError:
+		return glyphError, m.colors.err
+	}
+	return glyphNone, m.colors.muted
+}
+
+// renderAttemptBadge counts the agent runs a ticket has taken once it is
+// past its first, turning to a warning from the third
+func (m *Model) renderAttemptBadge(ticket *board.Ticket) string {
+	n := len(ticket.AgentRuns)
+	if n < 2 {
+		return ""
+	}
+	color := m.colors.subtext
+	if n >= 3 {
+		color = m.colors.warning
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("↻ %d", n))
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/jira"
+)
+
+type jiraTickMsg time.Time
+type jiraImportMsg struct {
+	issues map[string][]jira.Issue // by project ID
+	err    error                   // the first failure, if any
+}
+type jiraTransitionMsg struct {
+	key    string
+	status string
+	err    error
+}
+
+func tickJira(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return jiraTickMsg(t)
+	})
+}
+
+// jiraInterval follows jira.sync_interval; with imports disabled the tick
+// still runs so enabling them in the config takes effect on reload
+func (m *Model) jiraInterval() time.Duration {
+	return time.Duration(max(m.config.Jira.SyncInterval, 1)) * time.Minute
+}
+
+// handleJiraTick searches Jira for the issues of every project with a
+// jira_jql in the background
+func (m *Model) handleJiraTick() (tea.Model, tea.Cmd) {
+	next := tickJira(m.jiraInterval())
+	if m.config.Jira.URL == "" || m.config.Jira.SyncInterval <= 0 || m.jiraBusy || m.config.Behavior.ReadOnly {
+		return m, next
+	}
+
+	queries := make(map[string]string)
+	for _, p := range m.globalStore.Projects() {
+		if p.Settings.JiraJQL != "" {
+			queries[p.ID] = p.Settings.JiraJQL
+		}
+	}
+	if len(queries) == 0 {
+		return m, next
+	}
+	client, err := jira.NewClientFromEnv(m.config.Jira.URL, m.config.Jira.Email)
+	if err != nil {
+		m.reportJiraError(err)
+		return m, next
+	}
+
+	m.jiraBusy = true
+	search := func() tea.Msg {
+		result := jiraImportMsg{issues: make(map[string][]jira.Issue, len(queries))}
+		for projectID, jql := range queries {
+			issues, err := client.Search(jql)
+			if err != nil {
+				if result.err == nil {
+					result.err = err
+				}
+				continue
+			}
+			result.issues[projectID] = issues
+		}
+		return result
+	}
+	return m, tea.Batch(search, next)
+}
+
+// handleJiraImport adds tickets for new issues and refreshes linked ones
+func (m *Model) handleJiraImport(msg jiraImportMsg) (tea.Model, tea.Cmd) {
+	m.jiraBusy = false
+	if msg.err != nil {
+		m.reportJiraError(msg.err)
+	} else {
+		m.jiraErr = ""
+	}
+
+	imported := 0
+	for projectID, issues := range msg.issues {
+		created, updated := jira.Import(m.globalStore.All(), issues, projectID)
+		for _, ticket := range created {
+			if err := m.globalStore.Add(ticket); err != nil {
+				m.notify("Failed to import " + ticket.Meta[jira.MetaKey] + ": " + err.Error())
+				continue
+			}
+			m.saveTicket(ticket)
+			m.publish(m.newEvent(events.TicketCreated, ticket))
+			imported++
+		}
+		for _, ticket := range updated {
+			m.saveTicket(ticket)
+		}
+	}
+
+	if imported > 0 {
+		m.refreshColumnTickets()
+		m.notify(fmt.Sprintf("Imported %d Jira issue(s)", imported))
+	}
+	return m, nil
+}
+
+// transitionJira moves the Jira issue linked to ticket to the status mapped
+// to the ticket's new column in jira.transitions
+func (m *Model) transitionJira(ticket *board.Ticket) tea.Cmd {
+	key := ticket.Meta[jira.MetaKey]
+	status := m.config.Jira.Transitions[string(ticket.Status)]
+	if key == "" || status == "" || m.config.Jira.URL == "" {
+		return nil
+	}
+	client, err := jira.NewClientFromEnv(m.config.Jira.URL, m.config.Jira.Email)
+	if err != nil {
+		return func() tea.Msg {
+			return jiraTransitionMsg{key: key, status: status, err: err}
+		}
+	}
+	return func() tea.Msg {
+		return jiraTransitionMsg{key: key, status: status, err: client.Transition(key, status)}
+	}
+}
+
+func (m *Model) handleJiraTransition(msg jiraTransitionMsg) {
+	if msg.err != nil {
+		m.notify("Failed to update Jira: " + msg.err.Error())
+	}
+}
+
+// reportJiraError notifies about an import failure once until it changes
+func (m *Model) reportJiraError(err error) {
+	if err.Error() != m.jiraErr {
+		m.jiraErr = err.Error()
+		m.notify("Failed to import from Jira: " + m.jiraErr)
+	}
+}
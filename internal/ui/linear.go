@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/linear"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+type linearTickMsg time.Time
+type linearImportMsg struct {
+	issues map[string][]linear.Issue // by project ID
+	err    error                     // the first failure, if any
+}
+type linearUpdateMsg struct {
+	err error
+}
+
+func tickLinear(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return linearTickMsg(t)
+	})
+}
+
+// linearInterval follows linear.sync_interval; with imports disabled the
+// tick still runs so enabling them in the config takes effect on reload
+func (m *Model) linearInterval() time.Duration {
+	return time.Duration(max(m.config.Linear.SyncInterval, 1)) * time.Minute
+}
+
+// linearClient returns a client for the Linear team of the ticket's project,
+// or nil when the project isn't connected to Linear
+func (m *Model) linearClient(proj *project.Project) (*linear.Client, error) {
+	if proj == nil || proj.Settings.LinearTeamID == "" {
+		return nil, nil
+	}
+	return linear.NewClientFromEnv(proj.Settings.LinearAPIKey)
+}
+
+// handleLinearTick fetches the open issues of every project's Linear team in
+// the background
+func (m *Model) handleLinearTick() (tea.Model, tea.Cmd) {
+	next := tickLinear(m.linearInterval())
+	if m.config.Linear.SyncInterval <= 0 || m.linearBusy || m.config.Behavior.ReadOnly {
+		return m, next
+	}
+
+	type team struct {
+		id     string
+		client *linear.Client
+	}
+	teams := make(map[string]team)
+	for _, p := range m.globalStore.Projects() {
+		client, err := m.linearClient(p)
+		if err != nil {
+			m.reportLinearError(err)
+			continue
+		}
+		if client != nil {
+			teams[p.ID] = team{p.Settings.LinearTeamID, client}
+		}
+	}
+	if len(teams) == 0 {
+		return m, next
+	}
+
+	m.linearBusy = true
+	fetch := func() tea.Msg {
+		result := linearImportMsg{issues: make(map[string][]linear.Issue, len(teams))}
+		for projectID, t := range teams {
+			issues, err := t.client.Issues(t.id)
+			if err != nil {
+				if result.err == nil {
+					result.err = err
+				}
+				continue
+			}
+			result.issues[projectID] = issues
+		}
+		return result
+	}
+	return m, tea.Batch(fetch, next)
+}
+
+// handleLinearImport adds tickets for new issues and refreshes linked ones
+func (m *Model) handleLinearImport(msg linearImportMsg) (tea.Model, tea.Cmd) {
+	m.linearBusy = false
+	if msg.err != nil {
+		m.reportLinearError(msg.err)
+	} else {
+		m.linearErr = ""
+	}
+
+	imported := 0
+	for projectID, issues := range msg.issues {
+		created, updated := linear.Import(m.globalStore.All(), issues, projectID)
+		for _, ticket := range created {
+			if err := m.globalStore.Add(ticket); err != nil {
+				m.notify("Failed to import " + ticket.Meta[linear.MetaKey] + ": " + err.Error())
+				continue
+			}
+			m.saveTicket(ticket)
+			m.publish(m.newEvent(events.TicketCreated, ticket))
+			imported++
+		}
+		for _, ticket := range updated {
+			m.saveTicket(ticket)
+		}
+	}
+
+	if imported > 0 {
+		m.refreshColumnTickets()
+		m.notify(fmt.Sprintf("Imported %d Linear issue(s)", imported))
+	}
+	return m, nil
+}
+
+// updateLinear runs fn against the Linear issue linked to ticket in the
+// background, or does nothing for unlinked tickets
+func (m *Model) updateLinear(ticket *board.Ticket, fn func(c *linear.Client, issue, team string) error) tea.Cmd {
+	issue := ticket.Meta[linear.MetaKey]
+	if issue == "" {
+		return nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	client, err := m.linearClient(proj)
+	if client == nil && err == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err != nil {
+			return linearUpdateMsg{err: err}
+		}
+		return linearUpdateMsg{err: fn(client, issue, proj.Settings.LinearTeamID)}
+	}
+}
+
+// moveLinearIssue moves the linked issue to the state mapped to the ticket's
+// new column in linear.states
+func (m *Model) moveLinearIssue(ticket *board.Ticket) tea.Cmd {
+	state := m.config.Linear.States[string(ticket.Status)]
+	if state == "" {
+		return nil
+	}
+	return m.updateLinear(ticket, func(c *linear.Client, issue, team string) error {
+		return c.SetState(issue, team, state)
+	})
+}
+
+// linkLinearBranch comments the ticket's branch on the linked issue, once
+// per branch
+func (m *Model) linkLinearBranch(ticket *board.Ticket) tea.Cmd {
+	branch := ticket.BranchName
+	if !m.config.Linear.LinkBranch || branch == "" || ticket.Meta[linear.BranchMetaKey] == branch {
+		return nil
+	}
+	cmd := m.updateLinear(ticket, func(c *linear.Client, issue, _ string) error {
+		return c.Comment(issue, fmt.Sprintf("An agent is working on this in branch `%s`.", branch))
+	})
+	if cmd != nil {
+		ticket.Meta[linear.BranchMetaKey] = branch
+		m.saveTicket(ticket)
+	}
+	return cmd
+}
+
+// linkLinearPR attaches the ticket's pull request to the linked issue
+func (m *Model) linkLinearPR(ticket *board.Ticket) tea.Cmd {
+	url := ticket.PRURL
+	if !m.config.Linear.LinkPR || url == "" {
+		return nil
+	}
+	return m.updateLinear(ticket, func(c *linear.Client, issue, _ string) error {
+		return c.AttachURL(issue, url, ticket.Title)
+	})
+}
+
+func (m *Model) handleLinearUpdate(msg linearUpdateMsg) {
+	if msg.err != nil {
+		m.notify("Failed to update Linear: " + msg.err.Error())
+	}
+}
+
+// reportLinearError notifies about an import failure once until it changes
+func (m *Model) reportLinearError(err error) {
+	if err.Error() != m.linearErr {
+		m.linearErr = err.Error()
+		m.notify("Failed to import from Linear: " + m.linearErr)
+	}
+}
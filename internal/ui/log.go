@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// logView lists the commits on a ticket branch since its base
+type logView struct {
+	ticketID board.TicketID
+	title    string
+	branch   string
+	base     string
+	path     string
+	project  string
+
+	commits []git.Commit
+	head    string // commit checked out in the worktree
+	onHead  string // branch checked out in the worktree; empty when detached
+	index   int
+	offset  int
+
+	loading bool
+	err     string
+}
+
+type logLoadedMsg struct {
+	ticketID board.TicketID
+	base     string
+	commits  []git.Commit
+	head     string
+	onHead   string
+	err      error
+}
+
+func (m *Model) openLog() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" || ticket.BranchName == "" {
+		m.notify("No branch for this ticket yet")
+		return m, nil
+	}
+
+	m.log = &logView{
+		ticketID: ticket.ID,
+		title:    ticket.Title,
+		branch:   ticket.BranchName,
+		base:     ticket.BaseBranch,
+		path:     ticket.WorktreePath,
+		project:  ticket.ProjectID,
+	}
+	m.mode = ModeLog
+	return m, m.loadLog()
+}
+
+// loadLog reads the branch rather than HEAD, so the whole log stays listed
+// while an older commit is checked out
+func (m *Model) loadLog() tea.Cmd {
+	l := m.log
+	l.loading = true
+	ticketID, path, base, branch := l.ticketID, l.path, l.base, l.branch
+	mgr := m.worktreeMgrs[l.project]
+	return func() tea.Msg {
+		if base == "" && mgr != nil {
+			base, _ = mgr.GetDefaultBranch()
+		}
+		if base == "" {
+			return logLoadedMsg{ticketID: ticketID, err: fmt.Errorf("no base branch to compare against")}
+		}
+		commits, err := git.Log(path, base, branch)
+		if err != nil {
+			return logLoadedMsg{ticketID: ticketID, err: err}
+		}
+		head, onHead, err := git.Head(path)
+		return logLoadedMsg{ticketID: ticketID, base: base, commits: commits, head: head, onHead: onHead, err: err}
+	}
+}
+
+// offBranch reports whether the worktree has something other than the
+// ticket branch checked out
+func (l *logView) offBranch() bool {
+	return l.head != "" && l.onHead != l.branch
+}
+
+func (m *Model) handleLogLoaded(msg logLoadedMsg) (tea.Model, tea.Cmd) {
+	l := m.log
+	if l == nil || l.ticketID != msg.ticketID {
+		return m, nil
+	}
+	l.loading = false
+	l.err = ""
+	if msg.err != nil {
+		l.err = msg.err.Error()
+	}
+	if msg.base != "" {
+		l.base = msg.base
+	}
+	l.commits, l.head, l.onHead = msg.commits, msg.head, msg.onHead
+	l.selectCommit(l.index, m.logListHeight())
+	return m, nil
+}
+
+func (m *Model) handleLogMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.log
+	height := m.logListHeight()
+
+	switch msg.String() {
+	case "esc", "q":
+		m.log = nil
+		m.mode = ModeNormal
+	case "r":
+		return m, m.loadLog()
+	case "j", "down":
+		l.selectCommit(l.index+1, height)
+	case "k", "up":
+		l.selectCommit(l.index-1, height)
+	case "g", "home":
+		l.selectCommit(0, height)
+	case "G", "end":
+		l.selectCommit(len(l.commits)-1, height)
+	case "y":
+		if l.index < len(l.commits) {
+			hash := l.commits[l.index].Hash
+			if err := copyToClipboard(hash); err != nil {
+				m.notify("Failed to copy: " + err.Error())
+			} else {
+				m.notify("Copied " + hash[:min(len(hash), 7)])
+			}
+		}
+	case "c":
+		if !l.loading && l.index < len(l.commits) {
+			return m, m.confirmCheckout(l.commits[l.index])
+		}
+	case "b":
+		if !l.loading && l.offBranch() && !m.readOnlyBlocked("check out branches") {
+			return m, m.checkoutLogRev(l.branch)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) confirmCheckout(c git.Commit) tea.Cmd {
+	l := m.log
+	if c.Hash == l.head || m.readOnlyBlocked("check out commits") {
+		return nil
+	}
+	msg := fmt.Sprintf("Check out %s in the worktree? HEAD will be detached from %s until you press b.", c.ShortHash(), l.branch)
+	if mgr := m.worktreeMgrs[l.project]; mgr != nil {
+		if dirty, _ := mgr.HasUncommittedChanges(l.path); dirty {
+			msg += " Uncommitted changes may block it."
+		}
+	}
+	m.showConfirm = true
+	m.confirmMsg = msg
+	m.confirmFn = func() tea.Cmd {
+		return m.checkoutLogRev(c.Hash)
+	}
+	return nil
+}
+
+func (m *Model) checkoutLogRev(rev string) tea.Cmd {
+	l := m.log
+	if err := git.Checkout(l.path, rev); err != nil {
+		l.err = err.Error()
+		return nil
+	}
+	if rev == l.branch {
+		m.notify("Back on " + l.branch)
+	} else {
+		m.notify("Checked out " + rev[:min(len(rev), 7)])
+	}
+	return m.loadLog()
+}
+
+// logListHeight is the number of commit rows that fit in the log overlay
+// next to the header, message and key hints
+func (m *Model) logListHeight() int {
+	return max(m.height-20, 3)
+}
+
+func (l *logView) selectCommit(i, height int) {
+	l.index = max(min(i, len(l.commits)-1), 0)
+	if l.index < l.offset {
+		l.offset = l.index
+	} else if l.index >= l.offset+height {
+		l.offset = l.index - height + 1
+	}
+}
+
+// commitAge formats when a commit was made: relative for the last day,
+// a date otherwise
+func commitAge(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if d := time.Since(t); d < 24*time.Hour {
+		return formatDuration(max(d, 0)) + " ago"
+	}
+	if t.Year() == time.Now().Year() {
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2 2006")
+}
+
+func (m *Model) renderLogView() string {
+	l := m.log
+	width := min(100, m.width-4)
+	inner := width - 6
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	hashStyle := lipgloss.NewStyle().Foreground(m.colors.warning)
+
+	lines := []string{
+		titleStyle.Render("Commits: " + truncate(l.title, inner-9)),
+		"",
+		m.dimStyle().Render(truncate(fmt.Sprintf("%s..%s in %s", l.base, l.branch, l.path), inner)),
+	}
+	if l.offBranch() {
+		note := "HEAD is detached at " + l.head[:min(len(l.head), 7)]
+		if l.onHead != "" {
+			note = "The worktree is on " + l.onHead
+		}
+		note += "; press b to return to " + l.branch
+		lines = append(lines, lipgloss.NewStyle().Foreground(m.colors.warning).Render(truncate(note, inner)))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case l.loading && len(l.commits) == 0:
+		lines = append(lines, "  "+m.spinner.View()+m.dimStyle().Render(" Loading commits..."))
+	case len(l.commits) == 0 && l.err == "":
+		lines = append(lines, m.dimStyle().Render("  No commits since "+l.base))
+	}
+
+	height := m.logListHeight()
+	end := min(l.offset+height, len(l.commits))
+	for i := l.offset; i < end; i++ {
+		c := l.commits[i]
+		cursor := "  "
+		subjectStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == l.index {
+			cursor = "▸ "
+			subjectStyle = subjectStyle.Foreground(m.colors.primary).Bold(true)
+		}
+		marker := "  "
+		if c.Hash == l.head {
+			marker = lipgloss.NewStyle().Foreground(m.colors.success).Render("● ")
+		}
+		meta := c.Author + " · " + commitAge(c.Time)
+		subject := truncate(c.Subject, max(inner-lipgloss.Width(meta)-15, 10))
+		row := cursor + marker + hashStyle.Render(c.ShortHash()) + " " + subjectStyle.Render(subject)
+		gap := max(inner-lipgloss.Width(row)-lipgloss.Width(meta), 1)
+		lines = append(lines, row+strings.Repeat(" ", gap)+m.dimStyle().Render(meta))
+	}
+
+	if l.index < len(l.commits) {
+		c := l.commits[l.index]
+		lines = append(lines, "", m.dimStyle().Render(strings.Repeat("─", inner)))
+		lines = append(lines, hashStyle.Render(c.Hash))
+		lines = append(lines, m.dimStyle().Render(c.Author+"  "+c.Time.Format("2006-01-02 15:04")))
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(m.colors.text).Bold(true).Render(truncate(c.Subject, inner)))
+		if c.Body != "" {
+			body := strings.Split(c.Body, "\n")
+			for i, line := range body {
+				if i == 6 {
+					lines = append(lines, m.dimStyle().Render("…"))
+					break
+				}
+				lines = append(lines, lipgloss.NewStyle().Foreground(m.colors.text).Render(truncate(line, inner)))
+			}
+		}
+	}
+
+	if l.err != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(m.colors.err).Render(truncate("✗ "+l.err, inner)))
+	}
+
+	hints := keyStyle.Render("[y]") + m.dimStyle().Render(" Copy hash  ") +
+		keyStyle.Render("[c]") + m.dimStyle().Render(" Check out  ")
+	if l.offBranch() {
+		hints += keyStyle.Render("[b]") + m.dimStyle().Render(" Back to branch  ")
+	}
+	hints += keyStyle.Render("[r]") + m.dimStyle().Render(" Refresh  ") +
+		keyStyle.Render("[esc]") + m.dimStyle().Render(" Close")
+	lines = append(lines, "", hints)
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// macrosView is the macro menu. When pending holds a fresh recording it
+// asks for a name and then an optional key before saving it.
+type macrosView struct {
+	index   int
+	offset  int
+	pending []string // recorded keys waiting to be saved
+	naming  bool
+	keying  bool
+	name    string
+	input   textinput.Model
+	err     string
+}
+
+// recordKey adds a key to the macro being recorded. Keys replayed by a
+// macro are not recorded again; the key that started the replay already was.
+func (m *Model) recordKey(msg tea.KeyMsg) {
+	if !m.macroRecording || m.macroReplaying {
+		return
+	}
+	m.macroKeys = append(m.macroKeys, macroKeyName(msg))
+}
+
+// toggleMacroRecording starts recording keys, or stops and asks where to
+// save what was recorded
+func (m *Model) toggleMacroRecording() (tea.Model, tea.Cmd) {
+	if m.macroReplaying {
+		return m, nil
+	}
+	if !m.macroRecording {
+		m.macroRecording = true
+		m.macroKeys = nil
+		m.notify("Recording macro; press " + m.keys.Label(keymap.RecordMacro) + " again to stop")
+		return m, nil
+	}
+
+	// The key that stopped recording was recorded with the rest
+	keys := m.macroKeys[:max(len(m.macroKeys)-1, 0)]
+	m.macroRecording = false
+	m.macroKeys = nil
+	if len(keys) == 0 {
+		m.notify("Nothing recorded")
+		return m, nil
+	}
+	m.macros = &macrosView{pending: keys, naming: true, input: newMacroInput("Name › ", "e.g. triage")}
+	m.mode = ModeMacros
+	return m, textinput.Blink
+}
+
+func newMacroInput(prompt, placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.Placeholder = placeholder
+	input.CharLimit = 40
+	input.Focus()
+	return input
+}
+
+func (m *Model) openMacros() (tea.Model, tea.Cmd) {
+	m.macros = &macrosView{}
+	m.mode = ModeMacros
+	return m, nil
+}
+
+func (m *Model) closeMacros() {
+	m.macros = nil
+	m.mode = ModeNormal
+}
+
+func (m *Model) macroNames() []string {
+	return slices.Sorted(maps.Keys(m.config.Keybindings.Macros))
+}
+
+// macroForKey returns the macro replayed by key, if any
+func (m *Model) macroForKey(key string) (string, bool) {
+	for name, macro := range m.config.Keybindings.Macros {
+		if macro.Key != "" && keymap.NormalizeKey(macro.Key) == key {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// runMacro feeds a macro's keys through the board as if typed. Work the
+// keys start in the background, such as loading a diff, is not waited for.
+func (m *Model) runMacro(name string) (tea.Model, tea.Cmd) {
+	macro, ok := m.config.Keybindings.Macros[name]
+	if !ok || m.macroReplaying {
+		return m, nil
+	}
+	m.macroReplaying = true
+	defer func() { m.macroReplaying = false }()
+
+	var cmds []tea.Cmd
+	for _, key := range macro.Keys {
+		_, cmd := m.handleKey(parseMacroKey(key))
+		cmds = append(cmds, cmd)
+		if m.mode == ModeShuttingDown {
+			break
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) handleMacrosMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.macros
+	if v.naming || v.keying {
+		return m.handleMacroSaveKey(msg)
+	}
+	names := m.macroNames()
+	height := m.macrosListHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.closeMacros()
+	case "j", "down":
+		v.selectItem(v.index+1, len(names), height)
+	case "k", "up":
+		v.selectItem(v.index-1, len(names), height)
+	case "enter":
+		if v.index < len(names) {
+			m.closeMacros()
+			return m.runMacro(names[v.index])
+		}
+	case "d":
+		if v.index < len(names) {
+			name := names[v.index]
+			err := m.updateSetting("keybindings.macros."+name, func(c *config.Config) error {
+				delete(c.Keybindings.Macros, name)
+				return nil
+			})
+			if err != nil {
+				v.err = err.Error()
+				return m, nil
+			}
+			v.selectItem(v.index, len(names)-1, height)
+			m.notify("Deleted macro " + name)
+		}
+	}
+	return m, nil
+}
+
+// handleMacroSaveKey asks for the new macro's name, then its key, and
+// saves it to the config file, replacing a macro of the same name
+func (m *Model) handleMacroSaveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.macros
+	switch msg.String() {
+	case "esc":
+		m.closeMacros()
+		m.notify("Macro discarded")
+		return m, nil
+	case "enter":
+		if v.naming {
+			name := strings.TrimSpace(v.input.Value())
+			if !validMacroName(name) {
+				v.err = "use letters, digits, - and _"
+				return m, nil
+			}
+			v.name, v.naming, v.keying, v.err = name, false, true, ""
+			v.input = newMacroInput("Key › ", "optional, e.g. ctrl+t")
+			if macro, ok := m.config.Keybindings.Macros[name]; ok {
+				v.input.SetValue(macro.Key)
+			}
+			return m, textinput.Blink
+		}
+		key := strings.TrimSpace(v.input.Value())
+		macro := config.Macro{Key: key, Keys: v.pending}
+		err := m.updateSetting("keybindings.macros."+v.name, func(c *config.Config) error {
+			if c.Keybindings.Macros == nil {
+				c.Keybindings.Macros = make(map[string]config.Macro)
+			}
+			c.Keybindings.Macros[v.name] = macro
+			return nil
+		})
+		if err != nil {
+			v.err = err.Error()
+			return m, nil
+		}
+		m.notify(fmt.Sprintf("Saved macro %s (%d keys)", v.name, len(v.pending)))
+		m.closeMacros()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return m, cmd
+}
+
+func validMacroName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *macrosView) selectItem(i, count, height int) {
+	v.index = max(min(i, count-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+func (m *Model) macrosListHeight() int {
+	return max(m.height-18, 3)
+}
+
+// macroKeyName writes a key the way bindings are written, so a recorded
+// macro reads like the rest of the keybindings section
+func macroKeyName(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		if msg.Alt {
+			return "alt+space"
+		}
+		return "space"
+	}
+	return msg.String()
+}
+
+// namedKeys maps bubbletea's key names back to their key types
+var namedKeys = func() map[string]tea.KeyType {
+	keys := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-200); k < 200; k++ {
+		if k == tea.KeyRunes {
+			continue
+		}
+		if name := k.String(); name != "" {
+			keys[name] = k
+		}
+	}
+	return keys
+}()
+
+// parseMacroKey turns a recorded key back into the message typing it sends
+func parseMacroKey(key string) tea.KeyMsg {
+	if key == "space" || key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	if t, ok := namedKeys[key]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok && rest != "" {
+		msg := parseMacroKey(rest)
+		msg.Alt = true
+		return msg
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// renderMacroBadge marks the status bar while keys are being recorded
+func (m *Model) renderMacroBadge() string {
+	if !m.macroRecording {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(m.colors.base).
+		Background(m.colors.err).
+		Bold(true).
+		Padding(0, 1).
+		Render(fmt.Sprintf("● REC %d", len(m.macroKeys)))
+}
+
+func (m *Model) renderMacrosView() string {
+	v := m.macros
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(90, m.width-4)
+	inner := width - 6
+
+	lines := []string{titleStyle.Render("Macros"), ""}
+	if v.pending != nil {
+		lines = append(lines,
+			m.dimStyle().Render("Recorded: "+truncate(strings.Join(v.pending, " "), inner-10)),
+			"", v.input.View())
+	} else {
+		names := m.macroNames()
+		if len(names) == 0 {
+			hint := m.keys.Label(keymap.RecordMacro)
+			lines = append(lines, m.dimStyle().Render("  None yet. Press "+hint+" on the board to start recording and again to stop."))
+		}
+		height := m.macrosListHeight()
+		end := min(v.offset+height, len(names))
+		for i := v.offset; i < end; i++ {
+			macro := m.config.Keybindings.Macros[names[i]]
+			style := lipgloss.NewStyle().Foreground(m.colors.text)
+			cursor := "  "
+			if i == v.index {
+				style = style.Foreground(m.colors.primary).Bold(true)
+				cursor = "▸ "
+			}
+			row := cursor + style.Render(fmt.Sprintf("%-20s", truncate(names[i], 20)))
+			row += " " + keyStyle.Render(fmt.Sprintf("%-8s", truncate(macro.Key, 8)))
+			desc := truncate(strings.Join(macro.Keys, " "), max(inner-lipgloss.Width(row)-2, 10))
+			lines = append(lines, row+m.dimStyle().Render("  "+desc))
+		}
+		if len(names) > end {
+			lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(names)-end)))
+		}
+	}
+	if v.err != "" {
+		lines = append(lines, "", "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+v.err))
+	}
+
+	help := keyStyle.Render("[enter]") + m.dimStyle().Render(" Run  ") +
+		keyStyle.Render("[d]") + m.dimStyle().Render(" Delete  ") +
+		keyStyle.Render("[esc]") + m.dimStyle().Render(" Close")
+	switch {
+	case v.naming:
+		help = keyStyle.Render("[enter]") + m.dimStyle().Render(" Next  ") +
+			keyStyle.Render("[esc]") + m.dimStyle().Render(" Discard")
+	case v.keying:
+		help = keyStyle.Render("[enter]") + m.dimStyle().Render(" Save  ") +
+			keyStyle.Render("[esc]") + m.dimStyle().Render(" Discard")
+	}
+	lines = append(lines, "", help)
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
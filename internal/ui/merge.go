@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// mergeState tracks a merge or rebase of a ticket branch into its base, or
+// of base into the branch when syncing. It drives the conflicts view while
+// git is stopped on conflicts.
+type mergeState struct {
+	ticketID board.TicketID
+	project  string
+	title    string
+	path     string
+	branch   string
+	base     string
+	strategy git.Strategy
+	sync     bool // only bring the branch up to date; don't land it
+	paused   bool // the ticket's agent was paused for the sync
+
+	conflicts []string
+	index     int
+	running   bool
+	err       string
+}
+
+type mergeResultMsg struct {
+	ticketID  board.TicketID
+	conflicts []string
+	err       error
+}
+
+// describe names the operation for messages, e.g. "rebase task/x onto main"
+func (s *mergeState) describe() string {
+	if s.strategy == git.StrategyRebase {
+		return fmt.Sprintf("rebase %s onto %s", s.branch, s.base)
+	}
+	if s.sync {
+		return fmt.Sprintf("merge %s into %s", s.base, s.branch)
+	}
+	return fmt.Sprintf("merge %s into %s", s.branch, s.base)
+}
+
+func (m *Model) confirmMerge(strategy git.Strategy) (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("merge branches") {
+		return m, nil
+	}
+	if ticket.Status != board.StatusDone {
+		m.notify("Move the ticket to Done before merging it")
+		return m, nil
+	}
+	if ticket.BranchName == "" || ticket.WorktreePath == "" {
+		m.notify("Ticket has no branch to merge")
+		return m, nil
+	}
+	if m.merge != nil && m.merge.running {
+		m.notify("Wait for " + m.merge.describe() + " to finish")
+		return m, nil
+	}
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		m.notify("Failed to merge: worktree manager not found")
+		return m, nil
+	}
+
+	state := &mergeState{
+		ticketID: ticket.ID,
+		project:  ticket.ProjectID,
+		title:    ticket.Title,
+		path:     ticket.WorktreePath,
+		branch:   ticket.BranchName,
+		base:     ticket.BaseBranch,
+		strategy: strategy,
+	}
+	if state.base == "" {
+		state.base, _ = mgr.GetDefaultBranch()
+	}
+
+	// Pick up a merge or rebase that stopped on conflicts earlier
+	if inProgress := git.InProgress(ticket.WorktreePath); inProgress != "" {
+		state.strategy = inProgress
+		conflicts, err := git.ConflictedFiles(ticket.WorktreePath)
+		if err != nil {
+			state.err = err.Error()
+		}
+		m.openConflicts(state, conflicts)
+		return m, nil
+	}
+
+	msg := strings.ToUpper(state.describe()[:1]) + state.describe()[1:] + "? " + m.mergePrecheck(state)
+	if dirty, _ := mgr.HasUncommittedChanges(ticket.WorktreePath); dirty {
+		msg += " Uncommitted changes will block it."
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = msg
+	m.confirmFn = func() tea.Cmd {
+		m.merge = state
+		state.running = true
+		m.notify("Starting " + state.describe() + "...")
+		return runMerge(mgr, state, false)
+	}
+	return m, nil
+}
+
+// mergePrecheck trial-merges the branch into base and describes the
+// conflicts to expect. Base is checked as it is locally; the merge itself
+// fetches it first.
+func (m *Model) mergePrecheck(s *mergeState) string {
+	conflicts, err := git.TrialMerge(s.path, s.base, s.branch)
+	if err != nil {
+		return "Could not check for conflicts."
+	}
+	if m.mergeConflicts == nil {
+		m.mergeConflicts = make(map[board.TicketID][]string)
+	}
+	m.mergeConflicts[s.ticketID] = conflicts
+	if len(conflicts) == 0 {
+		return "No conflicts expected."
+	}
+	files := conflicts
+	if len(files) > 3 {
+		files = append(files[:3:3], fmt.Sprintf("%d more", len(conflicts)-3))
+	}
+	return fmt.Sprintf("Expect conflicts in %d file(s): %s.", len(conflicts), strings.Join(files, ", "))
+}
+
+// runMerge fetches base, brings the branch up to date and, unless syncing,
+// lands it. With resume set it continues a merge or rebase stopped on
+// conflicts instead.
+func runMerge(mgr *git.WorktreeManager, s *mergeState, resume bool) tea.Cmd {
+	ticketID, path, branch, base, strategy, sync := s.ticketID, s.path, s.branch, s.base, s.strategy, s.sync
+	return func() tea.Msg {
+		var conflicts []string
+		var err error
+		if resume {
+			conflicts, err = git.Continue(path)
+		} else {
+			if err := mgr.FetchBase(base); err != nil {
+				return mergeResultMsg{ticketID: ticketID, err: err}
+			}
+			conflicts, err = git.Update(path, base, strategy)
+		}
+		if err != nil || len(conflicts) > 0 {
+			return mergeResultMsg{ticketID: ticketID, conflicts: conflicts, err: err}
+		}
+		if sync {
+			return mergeResultMsg{ticketID: ticketID}
+		}
+		return mergeResultMsg{ticketID: ticketID, err: mgr.Land(base, branch, strategy)}
+	}
+}
+
+func (m *Model) handleMergeResult(msg mergeResultMsg) (tea.Model, tea.Cmd) {
+	s := m.merge
+	if s == nil || s.ticketID != msg.ticketID {
+		return m, nil
+	}
+	s.running = false
+	// Git is done with the worktree, whether or not it stopped on conflicts;
+	// the agent may be needed to resolve them
+	m.resumeSyncedAgent(s)
+
+	if len(msg.conflicts) > 0 {
+		if msg.err != nil {
+			s.err = msg.err.Error()
+		}
+		m.openConflicts(s, msg.conflicts)
+		return m, nil
+	}
+	if msg.err != nil {
+		if m.mode == ModeConflicts {
+			s.err = msg.err.Error()
+		} else {
+			m.merge = nil
+			m.notify("Failed to " + s.describe() + ": " + msg.err.Error())
+		}
+		return m, nil
+	}
+
+	m.merge = nil
+	if m.mode == ModeConflicts {
+		m.mode = ModeNormal
+	}
+	if s.sync {
+		delete(m.mergeConflicts, s.ticketID)
+		m.notify(fmt.Sprintf("Synced %s with %s", s.branch, s.base))
+		return m, nil
+	}
+	m.notify(fmt.Sprintf("Merged %s into %s", s.branch, s.base))
+
+	ticket, _ := m.globalStore.Get(s.ticketID)
+	if ticket == nil {
+		return m, nil
+	}
+	m.publish(m.newEvent(events.TicketMerged, ticket))
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || ticket.WorktreePath == proj.RepoPath {
+		// Tickets on the main checkout have no worktree to remove
+		return m, nil
+	}
+	var cmd tea.Cmd
+	switch m.config.Cleanup.AfterMerge {
+	case "always":
+		cmd = m.removeTicketWorktree(ticket, true)
+	case "never":
+	default:
+		m.showConfirm = true
+		m.confirmMsg = fmt.Sprintf("Merged %s. Delete its worktree and branch?", s.branch)
+		m.confirmFn = func() tea.Cmd {
+			return m.removeTicketWorktree(ticket, true)
+		}
+	}
+	return m, cmd
+}
+
+func (m *Model) openConflicts(s *mergeState, conflicts []string) {
+	m.merge = s
+	s.conflicts = conflicts
+	s.index = min(s.index, max(len(conflicts)-1, 0))
+	m.mode = ModeConflicts
+}
+
+func (m *Model) handleConflictsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.merge
+	if s.running {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		// The merge or rebase stays in progress; merging again reopens it
+		m.mode = ModeNormal
+		m.merge = nil
+	case "j", "down":
+		s.index = min(s.index+1, max(len(s.conflicts)-1, 0))
+	case "k", "up":
+		s.index = max(s.index-1, 0)
+	case "r":
+		conflicts, err := git.ConflictedFiles(s.path)
+		s.err = ""
+		if err != nil {
+			s.err = err.Error()
+		}
+		s.conflicts = conflicts
+		s.index = min(s.index, max(len(conflicts)-1, 0))
+	case "c":
+		mgr := m.worktreeMgrs[s.project]
+		if mgr == nil {
+			s.err = "worktree manager not found"
+			return m, nil
+		}
+		s.err = ""
+		s.running = true
+		return m, runMerge(mgr, s, true)
+	case "a":
+		if err := git.Abort(s.path); err != nil {
+			s.err = err.Error()
+			return m, nil
+		}
+		m.mode = ModeNormal
+		m.merge = nil
+		m.notify("Aborted " + s.describe())
+	case "enter":
+		m.mode = ModeNormal
+		m.selectTicketByID(s.ticketID)
+		m.merge = nil
+		return m.attachToAgent()
+	}
+	return m, nil
+}
+
+func (m *Model) renderConflictsView() string {
+	s := m.merge
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.warning).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+
+	verb := "Merging " + s.base + " into " + s.branch
+	if s.strategy == git.StrategyRebase {
+		verb = "Rebasing " + s.branch + " onto " + s.base
+	}
+
+	lines := []string{
+		titleStyle.Render("⚠ Conflicts: " + s.title),
+		"",
+		m.dimStyle().Render(verb + " stopped on conflicts in " + s.path),
+		"",
+	}
+
+	if len(s.conflicts) == 0 {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ All conflicts resolved"))
+	}
+	for i, file := range s.conflicts {
+		style := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == s.index {
+			style = style.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		lines = append(lines, cursor+lipgloss.NewStyle().Foreground(m.colors.err).Render("U ")+style.Render(file))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case s.running:
+		lines = append(lines, "  "+m.spinner.View()+m.dimStyle().Render(" Continuing..."))
+	case s.err != "":
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+s.err))
+	default:
+		lines = append(lines, m.dimStyle().Render("Edit the files to resolve them (or attach the agent), then continue."))
+	}
+
+	lines = append(lines, "",
+		keyStyle.Render("[c]")+m.dimStyle().Render(" Continue  ")+
+			keyStyle.Render("[a]")+m.dimStyle().Render(" Abort  ")+
+			keyStyle.Render("[enter]")+m.dimStyle().Render(" Attach agent  ")+
+			keyStyle.Render("[r]")+m.dimStyle().Render(" Refresh  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.warning).
+		Padding(1, 2).
+		Width(min(80, m.width-4)).
+		Render(strings.Join(lines, "\n"))
+}
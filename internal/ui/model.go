@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -16,8 +19,15 @@ import (
 	"github.com/techdufus/openkanban/internal/agent"
 	"github.com/techdufus/openkanban/internal/board"
 	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/control"
+	"github.com/techdufus/openkanban/internal/crypt"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/filewatch"
 	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/keymap"
+	"github.com/techdufus/openkanban/internal/procstat"
 	"github.com/techdufus/openkanban/internal/project"
+	"github.com/techdufus/openkanban/internal/sandbox"
 	"github.com/techdufus/openkanban/internal/terminal"
 	"github.com/techdufus/openkanban/internal/update"
 )
@@ -40,24 +50,36 @@ const (
 	ModeSpawning      Mode = "SPAWNING"
 	ModeFilter        Mode = "FILTER"
 	ModeCreateProject Mode = "NEW_PROJECT"
+	ModeDiff          Mode = "DIFF"
+	ModeConflicts     Mode = "CONFLICTS"
+	ModeWorktrees     Mode = "WORKTREES"
+	ModeLog           Mode = "LOG"
+	ModeSwitcher      Mode = "SWITCH"
+	ModeNotices       Mode = "NOTIFICATIONS"
+	ModeClipboard     Mode = "CLIPBOARD"
+	ModeSessions      Mode = "SESSIONS"
+	ModeSavedFilters  Mode = "FILTERS"
+	ModeMacros        Mode = "MACROS"
+	ModeTranscript    Mode = "TRANSCRIPT"
 )
 
 const (
 	minColumnWidth = 20
 	columnOverhead = 5
 
-	ticketHeight       = 6
 	columnHeaderHeight = 3
 
 	formFieldTitle       = 0
 	formFieldDescription = 1
 	formFieldBranch      = 2
-	formFieldLabels      = 3
-	formFieldPriority    = 4
-	formFieldWorktree    = 5
-	formFieldAgent       = 6
-	formFieldBlockedBy   = 7
-	formFieldProject     = 8
+	formFieldBase        = 3
+	formFieldLabels      = 4
+	formFieldPriority    = 5
+	formFieldWorktree    = 6
+	formFieldAgent       = 7
+	formFieldModel       = 8
+	formFieldBlockedBy   = 9
+	formFieldProject     = 10
 )
 
 type Model struct {
@@ -68,10 +90,20 @@ type Model struct {
 	globalStore      *project.GlobalTicketStore
 	projectRegistry  *project.ProjectRegistry
 	columns          []board.Column
+	boardColumns     []board.Column // the full board while focus mode shows a subset
+	focusMode        bool
+	collapsed        map[board.TicketStatus]bool  // collapsed columns while the board shows several projects
+	agentActivity    map[board.TicketID]time.Time // last agent state change seen
 	filterProjectIDs map[string]bool
 
 	worktreeMgrs   map[string]*git.WorktreeManager
 	agentMgr       *agent.Manager
+	hooks          *events.HookRunner
+	notifier       *events.Notifier
+	desktopErr     string // last desktop notification failure, reported once
+	bus            *events.Bus
+	busEvents      <-chan events.Event // the board's own subscription
+	keys           *keymap.Keymap
 	opencodeServer *agent.OpencodeServer
 
 	mode          Mode
@@ -97,10 +129,13 @@ type Model struct {
 
 	columnTickets [][]*board.Ticket
 
-	showHelp    bool
-	showConfirm bool
-	confirmMsg  string
-	confirmFn   func() tea.Cmd
+	showHelp     bool
+	showConfirm  bool
+	confirmMsg   string
+	confirmFn    func() tea.Cmd
+	declineFn    func() tea.Cmd // run on [n]; nil just closes the dialog
+	confirmWant  string         // text to type instead of [y]; see askTypedConfirm
+	confirmInput textinput.Model
 
 	titleInput         textinput.Model
 	descInput          textarea.Model
@@ -110,9 +145,11 @@ type Model struct {
 	ticketUseWorktree  bool
 	ticketAgent        string
 	agentListIndex     int
+	ticketModel        string
 	projectInput       textinput.Model
 	ticketFormField    int
 	editingTicketID    board.TicketID
+	ticketSource       board.TicketID // ticket a new ticket is split off from
 	branchLocked       bool
 	agentLocked        bool
 	selectedProject    *project.Project
@@ -125,6 +162,14 @@ type Model struct {
 	blockerListIndex   int
 	blockerFilterInput textinput.Model
 
+	ticketBaseBranch  string // chosen base branch; empty until listed
+	baseBranches      []git.Branch
+	baseBranchProject string // project baseBranches belong to
+	baseBranchIndex   int
+	baseBranchLoading bool
+	baseBranchErr     string
+	baseFilterInput   textinput.Model
+
 	formScrollOffset int
 	formFieldLines   map[int]int
 
@@ -137,20 +182,108 @@ type Model struct {
 
 	spawningTicketID board.TicketID
 	spawningAgent    string
+	spawnPrompts     map[board.TicketID]string         // init prompts reviewed in the editor, waiting for their spawn
+	pendingPrompts   map[board.TicketID]*pendingPrompt // init prompts waiting for their agent to be ready
+
+	setups       map[board.TicketID]*worktreeSetup // running worktree setup commands
+	gitOps       map[board.TicketID]*gitOp         // worktrees being created or removed
+	spawnPending map[board.TicketID]bool           // spawns waiting for their ready or error message
+	testing      map[board.TicketID]bool           // test commands running
 
+	highlights []terminal.Highlight // ui.highlights, ready for the agent panes
+
+	settingsPage    int
 	settingsIndex   int
+	settingsOffset  int
 	settingsEditing bool
+	settingsError   string
 	settingsInput   textinput.Model
 	themeListIndex  int
 
-	filterInput textinput.Model
-	filterQuery string
+	diff      *diffView
+	merge     *mergeState
+	worktrees *worktreesView
+	sessions  *sessionsView
+	log       *logView
+	switcher  *switcherView
+
+	transcript  *transcriptView
+	transcripts map[board.TicketID]string // output of agents that exited this session
+
+	filters      *project.FilterRegistry // loaded on first use
+	savedFilters *savedFiltersView
+
+	macros         *macrosView
+	macroRecording bool
+	macroReplaying bool
+	macroKeys      []string // keys recorded so far
+
+	debug debugStats
+
+	notices      []notice // history, oldest first
+	noticesPanel *noticesView
+	unreadErrors int
+
+	clips      []clip // copied from panes, oldest first
+	clipsPanel *clipsView
+
+	controlSocket string // where the CLI reaches this instance; empty when disabled
+	configPath    string // config file given with --config; empty for the default
+
+	worktreeStatus map[board.TicketID]git.WorktreeStatus
+	mergeConflicts map[board.TicketID][]string // files a Done ticket's merge would conflict on
+
+	worktreeSizes      map[string]int64 // bytes by worktree path, as last measured
+	diskUsageBusy      bool
+	diskLimitWarned    bool
+	worktreeStatusBusy bool
+	commitRefsBusy     bool
+
+	usage        map[board.TicketID]procstat.Usage // agents' CPU and memory, as last sampled
+	usageSampler *procstat.Sampler
+	usageBusy    bool
+
+	prStatus     map[board.TicketID]git.PRStatus // linked pull requests, as last checked
+	prStatusBusy bool
+	prStatusErr  string // last check failure, reported once
+
+	jiraBusy bool
+	jiraErr  string // last import failure, reported once
+
+	linearBusy bool
+	linearErr  string // last import failure, reported once
+
+	autosaveErr string // last autosave failure, reported once
+
+	teamSyncing map[string]bool // projects with a sync running
+	teamSyncErr string          // last sync failure, reported once
+	syncCipher  *crypt.Cipher
+
+	collabName   string
+	collabJoined bool                        // joined another instance rather than relaying
+	collabSend   func(control.Message)       // nil when the board isn't shared
+	peers        map[string]control.Presence // other instances sharing the board, by name
+	sentPresence control.Presence
+
+	filterInput  textinput.Model
+	filterQuery  string
+	filterExpr   *project.Query // filterQuery parsed, as of parsedFilter
+	filterErr    error
+	parsedFilter string
 
 	sidebarVisible bool
 	sidebarFocused bool
 	sidebarIndex   int
 	sidebarWidth   int
 
+	splitView     bool   // board stays visible beside the agent pane
+	cardDensity   string // compact, normal or detailed ticket cards
+	outputPreview bool   // selected card's agent output shown beside the board
+	fileFeed      bool   // files agents change are watched and shown beside the board
+
+	fileWatchers map[board.TicketID]*filewatch.Watcher // nil while starting
+	fileFeeds    map[board.TicketID]*fileFeed
+
 	updateChecker *update.Checker
 }
 
@@ -201,9 +334,16 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 	bf.CharLimit = 100
 	bf.Width = 30
 
+	bb := textinput.New()
+	bb.Placeholder = "Filter branches..."
+	bb.CharLimit = 100
+	bb.Width = 30
+
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 
+	globalStore.SetOrder(project.Order(cfg.UI.TicketOrder), project.Order(cfg.UI.ProjectOrder))
+
 	worktreeMgrs := make(map[string]*git.WorktreeManager)
 	for _, p := range globalStore.Projects() {
 		worktreeMgrs[p.ID] = git.NewWorktreeManager(p)
@@ -227,10 +367,15 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		colors:             newUIColors(theme),
 		globalStore:        globalStore,
 		projectRegistry:    projectRegistry,
-		columns:            board.DefaultColumns(),
+		columns:            columnLayout(cfg.Behavior.Review, globalStore.All()),
+		agentActivity:      make(map[board.TicketID]time.Time),
 		filterProjectIDs:   make(map[string]bool),
 		worktreeMgrs:       worktreeMgrs,
 		agentMgr:           agentMgr,
+		hooks:              events.NewHookRunner(cfg.Hooks),
+		notifier:           events.NewNotifier(cfg.Notifications.Webhooks),
+		bus:                events.NewBus(),
+		keys:               cfg.Keymap(),
 		opencodeServer:     opencodeServer,
 		mode:               ModeNormal,
 		titleInput:         ti,
@@ -243,6 +388,7 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		filterInput:        fi,
 		addProjectPath:     ap,
 		blockerFilterInput: bf,
+		baseFilterInput:    bb,
 		selectedBlockers:   make(map[board.TicketID]bool),
 		formFieldLines:     make(map[int]int),
 		spinner:            sp,
@@ -251,6 +397,10 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 		selectedProject:    selectedProject,
 		sidebarVisible:     cfg.UI.SidebarVisible,
 		sidebarWidth:       24,
+		splitView:          cfg.UI.Split,
+		cardDensity:        cfg.UI.CardDensity,
+		outputPreview:      cfg.UI.OutputPreview,
+		fileFeed:           cfg.UI.FileFeed,
 		hoverColumn:        -1,
 		hoverTicket:        -1,
 		updateChecker:      updateChecker,
@@ -258,12 +408,16 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 	if filterProjectID != "" {
 		m.filterProjectIDs[filterProjectID] = true
 	}
+	m.busEvents, _ = m.bus.SubscribeBuffered(busBuffer)
+	m.applyHighlights()
 
 	// Reset all agent statuses on startup since there are no active sessions yet.
 	// This prevents stale "working" statuses from persisting after app restart.
 	for _, ticket := range globalStore.All() {
-		if ticket.AgentStatus != board.AgentNone {
+		openRun := len(ticket.AgentRuns) > 0 && ticket.AgentRuns[len(ticket.AgentRuns)-1].EndedAt == nil
+		if ticket.AgentStatus != board.AgentNone || openRun {
 			ticket.AgentStatus = board.AgentNone
+			ticket.EndAgentRun(board.OutcomeStopped)
 			globalStore.Save(ticket)
 		}
 	}
@@ -275,8 +429,20 @@ func NewModel(cfg *config.Config, globalStore *project.GlobalTicketStore, projec
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		tickAgentStatus(m.agentMgr.StatusPollInterval()),
+		tickWorktreeStatus(time.Second),
+		tickCommitRefs(5*time.Second),
+		tickDiskUsage(30*time.Second),
+		tickUsage(usageInterval),
+		tickAutosave(m.autosaveInterval()),
+		tickPRStatus(5*time.Second),
+		tickJira(10*time.Second),
+		tickLinear(10*time.Second),
+		tickTeamSync(time.Second),
+		tickNotifications(toastTickInterval),
 		m.spinner.Tick,
 		m.checkForUpdates(),
+		m.findTmuxPanes(),
+		m.listenEvents(),
 	)
 }
 
@@ -289,7 +455,11 @@ func (m *Model) checkForUpdates() tea.Cmd {
 	}
 }
 
-func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m *Model) Update(msg tea.Msg) (_ tea.Model, cmd tea.Cmd) {
+	if m.debug.enabled() {
+		start := time.Now()
+		defer func() { cmd = m.recordUpdate(msg, time.Since(start), cmd) }()
+	}
 	if m.mode == ModeShuttingDown {
 		switch msg := msg.(type) {
 		case shutdownCompleteMsg:
@@ -302,47 +472,76 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	defer m.sharePresence()
+
+	if req, ok := msg.(ControlRequestMsg); ok {
+		return m, m.handleControlRequest(req)
+	}
+
+	switch msg := msg.(type) {
+	case CollabMsg:
+		return m, m.handleCollab(msg)
+	case CollabClosedMsg:
+		m.handleCollabClosed(msg)
+		return m, nil
+	}
+
+	if e, ok := msg.(busEventMsg); ok {
+		return m, m.handleBusEvent(events.Event(e))
+	}
+
+	// While an agent spawns, its own messages and keys are handled here;
+	// everything else, background ticks and results included, falls
+	// through to the main switch
 	if m.mode == ModeSpawning {
 		switch msg := msg.(type) {
-		case agentStatusMsg:
-			return m, tea.Batch(
-				m.pollAgentStatusesAsync(),
-				tickAgentStatus(m.agentMgr.StatusPollInterval()),
-			)
 		case spawnReadyMsg:
+			delete(m.setups, msg.ticketID)
+			delete(m.spawnPending, msg.ticketID)
+			m.endGitOp(msg.ticketID)
+			if msg.background {
+				return m, m.startSpawnedPane(msg)
+			}
 			if msg.ticketID != m.spawningTicketID {
 				return m, nil
 			}
 
-			ticket, _ := m.globalStore.Get(msg.ticketID)
-			if ticket != nil {
-				ticket.AgentType = m.spawningAgent
-				ticket.AgentStatus = board.AgentNone
-				if ticket.AgentSpawnedAt == nil {
-					now := time.Now()
-					ticket.AgentSpawnedAt = &now
-				}
-				if msg.worktreePath != "" && ticket.WorktreePath == "" {
-					ticket.WorktreePath = msg.worktreePath
-					ticket.BranchName = msg.branchName
-					ticket.BaseBranch = msg.baseBranch
-				}
-				m.saveTicket(ticket)
-			}
-
-			m.panes[msg.ticketID] = msg.pane
 			m.focusedPane = msg.ticketID
-			return m, msg.pane.Start(msg.command, msg.args...)
+			return m, m.startSpawnedPane(msg)
 
 		case spawnErrorMsg:
-			if msg.ticketID == m.spawningTicketID {
+			delete(m.setups, msg.ticketID)
+			delete(m.spawnPending, msg.ticketID)
+			m.endGitOp(msg.ticketID)
+			if msg.background {
+				m.notify("Background spawn failed: " + msg.err)
+			} else if msg.ticketID == m.spawningTicketID {
 				m.mode = ModeNormal
 				m.spawningTicketID = ""
 				m.spawningAgent = ""
-				m.notify(msg.err)
+				m.notifyError(msg.err)
+				// Nothing ran, so hand back the changes stashed for it
+				if ticket, _ := m.globalStore.Get(msg.ticketID); ticket != nil {
+					m.restoreStash(ticket)
+				}
 			}
 			return m, nil
 
+		case terminal.StartedMsg:
+			_, cmd := m.handleTerminalMsg(msg)
+			if board.TicketID(msg.PaneID) != m.spawningTicketID {
+				return m, cmd
+			}
+			m.mode = ModeNormal
+			m.focusedPane = ""
+			m.spawningTicketID = ""
+			m.spawningAgent = ""
+			if pane, ok := m.panes[board.TicketID(msg.PaneID)]; ok {
+				m.notify("Agent running in tmux window " + pane.Tmux().String())
+				return m, tea.Batch(cmd, m.attachTmux(pane.Tmux()))
+			}
+			return m, cmd
+
 		case terminal.OutputMsg:
 			if board.TicketID(msg.PaneID) == m.spawningTicketID {
 				m.mode = ModeAgentView
@@ -352,13 +551,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleTerminalMsg(msg)
 
 		case terminal.ExitMsg:
-			if board.TicketID(msg.PaneID) == m.spawningTicketID {
-				m.resetSpawnState(board.TicketID(msg.PaneID))
-				if msg.Err != nil {
-					m.notify("Agent failed: " + msg.Err.Error())
-				} else {
-					m.notify("Agent exited unexpectedly")
-				}
+			if board.TicketID(msg.PaneID) != m.spawningTicketID {
+				return m.handleAgentExit(msg)
+			}
+			m.resetSpawnState(board.TicketID(msg.PaneID))
+			if msg.Err != nil {
+				m.notify("Agent failed: " + msg.Err.Error())
+			} else {
+				m.notify("Agent exited unexpectedly")
 			}
 			return m, nil
 
@@ -369,18 +569,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case tea.KeyMsg:
 			if msg.String() == "esc" {
+				m.cancelGitOp(m.spawningTicketID)
 				if pane, ok := m.panes[m.spawningTicketID]; ok {
 					pane.Stop()
 					delete(m.panes, m.spawningTicketID)
+					if ticket, _ := m.globalStore.Get(m.spawningTicketID); ticket != nil {
+						m.endAgentRun(ticket, board.OutcomeStopped)
+						m.saveTicket(ticket)
+					}
 				}
 				m.mode = ModeNormal
 				m.spawningTicketID = ""
 				m.spawningAgent = ""
 				m.notify("Spawn cancelled")
-				return m, nil
 			}
+			return m, nil
 		}
-		return m, nil
 	}
 
 	switch msg := msg.(type) {
@@ -390,9 +594,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		if m.focusedPane != "" {
+		m.ensureColumnVisible()
+		m.ensureTicketVisible()
+		if m.splitView {
+			m.resizePanes()
+		} else if m.focusedPane != "" {
 			if pane, ok := m.panes[m.focusedPane]; ok {
-				pane.SetSize(m.width, m.height-2)
+				pane.SetSize(m.paneSize())
 			}
 		}
 		return m, nil
@@ -413,6 +621,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.mode == ModeSettings {
 			return m.handleSettingsMouse(msg)
 		}
+		if m.mode == ModeDiff {
+			return m.handleDiffMouse(msg)
+		}
 		if m.showHelp {
 			if msg.Action == tea.MouseActionPress {
 				m.showHelp = false
@@ -424,23 +635,112 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case terminal.OutputMsg, terminal.RenderTickMsg:
+	case terminal.OutputMsg, terminal.RenderTickMsg, terminal.StartedMsg:
 		return m.handleTerminalMsg(msg)
 
+	case tmuxPanesMsg:
+		return m.handleTmuxPanes(msg)
+
+	case tmuxAttachedMsg:
+		m.handleTmuxAttached(msg)
+		return m, nil
+
+	case editorClosedMsg:
+		m.handleEditorClosed(msg)
+		return m, nil
+
+	case descriptionEditedMsg:
+		m.handleDescriptionEdited(msg)
+		return m, nil
+
+	case notesEditedMsg:
+		m.handleNotesEdited(msg)
+		return m, nil
+
+	case htmlExportedMsg:
+		return m.handleHTMLExported(msg)
+
+	case feedbackEditedMsg:
+		return m.handleFeedbackEdited(msg)
+
+	case followUpsEditedMsg:
+		return m.handleFollowUpsEdited(msg)
+
+	case testsDoneMsg:
+		return m.handleTestsDone(msg)
+
+	case promptReviewedMsg:
+		return m.handlePromptReviewed(msg)
+
 	case terminal.ExitMsg:
-		ticketID := board.TicketID(msg.PaneID)
-		delete(m.panes, ticketID)
-		if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
-			ticket.AgentStatus = board.AgentNone
-			m.saveTicket(ticket)
+		return m.handleAgentExit(msg)
+
+	case spawnReadyMsg:
+		delete(m.setups, msg.ticketID)
+		delete(m.spawnPending, msg.ticketID)
+		m.endGitOp(msg.ticketID)
+		if msg.background {
+			return m, m.startSpawnedPane(msg)
 		}
-		if m.focusedPane == ticketID {
-			m.mode = ModeNormal
-			m.focusedPane = ""
-			m.notify("Agent exited")
+		return m, nil
+
+	case spawnErrorMsg:
+		delete(m.setups, msg.ticketID)
+		delete(m.spawnPending, msg.ticketID)
+		m.endGitOp(msg.ticketID)
+		if msg.background {
+			m.notify("Background spawn failed: " + msg.err)
 		}
 		return m, nil
 
+	case setupDoneMsg:
+		return m.handleSetupDone(msg)
+
+	case worktreeCreatedMsg:
+		return m.handleWorktreeCreated(msg)
+
+	case worktreeRemovedMsg:
+		return m.handleWorktreeRemoved(msg)
+
+	case pushResultMsg:
+		return m.handlePushResult(msg)
+
+	case diffLoadedMsg:
+		return m.handleDiffLoaded(msg)
+
+	case prCreatedMsg:
+		return m.handlePRCreated(msg)
+
+	case mergeResultMsg:
+		return m.handleMergeResult(msg)
+
+	case worktreesLoadedMsg:
+		return m.handleWorktreesLoaded(msg)
+
+	case sessionsLoadedMsg:
+		return m.handleSessionsLoaded(msg)
+
+	case logLoadedMsg:
+		return m.handleLogLoaded(msg)
+
+	case baseBranchesMsg:
+		return m.handleBaseBranches(msg)
+
+	case ReloadConfigMsg:
+		return m.handleReloadConfig()
+
+	case hookErrorMsg:
+		m.notify("Hook failed: " + msg.err.Error())
+		return m, nil
+
+	case webhookErrorMsg:
+		m.notify("Notification failed: " + msg.err.Error())
+		return m, nil
+
+	case desktopErrorMsg:
+		m.handleDesktopError(msg)
+		return m, nil
+
 	case terminal.ExitFocusMsg:
 		m.mode = ModeNormal
 		m.focusedPane = ""
@@ -450,14 +750,84 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(
 			m.pollAgentStatusesAsync(),
 			tickAgentStatus(m.agentMgr.StatusPollInterval()),
+			m.syncFileWatchers(),
 		)
 
+	case fileWatchStartedMsg:
+		return m.handleFileWatchStarted(msg)
+
+	case fileChangesMsg:
+		return m.handleFileChanges(msg)
+
 	case agentStatusResultMsg:
-		for ticketID, status := range msg {
-			if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
-				ticket.AgentStatus = status
-			}
-		}
+		m.applyAgentStatuses(msg)
+		return m, nil
+
+	case worktreeStatusTickMsg:
+		return m.handleWorktreeStatusTick()
+
+	case worktreeStatusResultMsg:
+		m.applyWorktreeStatuses(msg)
+		return m, nil
+
+	case commitRefTickMsg:
+		return m.handleCommitRefTick()
+
+	case commitRefResultMsg:
+		m.applyCommitRefs(msg)
+		return m, nil
+
+	case usageTickMsg:
+		return m.handleUsageTick()
+
+	case usageMsg:
+		return m.handleUsage(msg)
+
+	case diskUsageTickMsg:
+		return m.handleDiskUsageTick()
+
+	case autosaveTickMsg:
+		return m.handleAutosaveTick()
+
+	case tea.BlurMsg:
+		m.flushTickets()
+		return m, nil
+
+	case prStatusTickMsg:
+		return m.handlePRStatusTick()
+
+	case prStatusResultMsg:
+		m.applyPRStatuses(msg)
+		return m, nil
+
+	case jiraTickMsg:
+		return m.handleJiraTick()
+
+	case jiraImportMsg:
+		return m.handleJiraImport(msg)
+
+	case jiraTransitionMsg:
+		m.handleJiraTransition(msg)
+		return m, nil
+
+	case linearTickMsg:
+		return m.handleLinearTick()
+
+	case linearImportMsg:
+		return m.handleLinearImport(msg)
+
+	case linearUpdateMsg:
+		m.handleLinearUpdate(msg)
+		return m, nil
+
+	case teamSyncTickMsg:
+		return m.handleTeamSyncTick()
+
+	case readinessTickMsg:
+		return m.handleReadinessTick()
+
+	case teamSyncMsg:
+		return m.handleTeamSync(msg)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -465,10 +835,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case notificationMsg:
-		if time.Since(m.notifyTime) > 3*time.Second {
-			m.notification = ""
-		}
-		return m, nil
+		return m.handleNotificationTick()
 
 	case updateCheckMsg:
 		if msg.UpdateAvailable {
@@ -482,11 +849,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
-		if m.mode == ModeNormal {
-			return m.handleQuit()
+	m.recordKey(msg)
+	key := msg.String()
+	// A typed confirmation takes every key but esc
+	if (m.mode == ModeNormal || m.mode == ModeHelp) && m.confirmWant == "" {
+		switch action, _ := m.keys.Action(keymap.ContextBoard, key); {
+		case key == "ctrl+c" || action == keymap.Quit:
+			if m.mode == ModeNormal {
+				return m.handleQuit()
+			}
+		case action == keymap.Help:
+			m.showHelp = !m.showHelp
+			return m, nil
 		}
+	}
+
+	switch key {
 	case "esc":
 		if m.mode == ModeAgentView {
 			break
@@ -499,13 +877,10 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeNormal
 		m.showHelp = false
 		m.showConfirm = false
+		m.declineFn = nil
+		m.confirmWant = ""
 		m.titleInput.Blur()
 		return m, nil
-	case "?":
-		if m.mode == ModeNormal || m.mode == ModeHelp {
-			m.showHelp = !m.showHelp
-			return m, nil
-		}
 	}
 
 	if m.showHelp {
@@ -534,19 +909,43 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFilterMode(msg)
 	case ModeCreateProject:
 		return m.handleCreateProjectMode(msg)
+	case ModeDiff:
+		return m.handleDiffMode(msg)
+	case ModeConflicts:
+		return m.handleConflictsMode(msg)
+	case ModeWorktrees:
+		return m.handleWorktreesMode(msg)
+	case ModeLog:
+		return m.handleLogMode(msg)
+	case ModeTranscript:
+		return m.handleTranscriptMode(msg)
+	case ModeSwitcher:
+		return m.handleSwitcherMode(msg)
+	case ModeNotices:
+		return m.handleNoticesMode(msg)
+	case ModeClipboard:
+		return m.handleClipsMode(msg)
+	case ModeSessions:
+		return m.handleSessionsMode(msg)
+	case ModeSavedFilters:
+		return m.handleSavedFiltersMode(msg)
+	case ModeMacros:
+		return m.handleMacrosMode(msg)
 	}
 
 	return m, nil
 }
 
 func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab":
+	action, _ := m.keys.Action(keymap.ContextBoard, msg.String())
+
+	switch action {
+	case keymap.FocusSidebar:
 		if m.sidebarVisible {
 			m.sidebarFocused = !m.sidebarFocused
 			return m, nil
 		}
-	case "[":
+	case keymap.ToggleSidebar:
 		m.sidebarVisible = !m.sidebarVisible
 		if !m.sidebarVisible {
 			m.sidebarFocused = false
@@ -558,63 +957,160 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSidebarNav(msg)
 	}
 
-	switch msg.String() {
-	case "h", "left":
+	switch action {
+	case keymap.MoveLeft:
 		if m.activeColumn == 0 && m.sidebarVisible {
 			m.sidebarFocused = true
 			return m, nil
 		}
 		m.moveColumn(-1)
-	case "l", "right":
+	case keymap.MoveRight:
 		m.moveColumn(1)
-	case "j", "down":
+	case keymap.MoveDown:
 		m.moveTicket(1)
-	case "k", "up":
+	case keymap.MoveUp:
 		m.moveTicket(-1)
-	case "g":
+	case keymap.FirstTicket:
 		m.activeTicket = 0
 		m.ensureTicketVisible()
-	case "G":
+	case keymap.LastTicket:
 		if len(m.columnTickets) > m.activeColumn {
 			m.activeTicket = max(len(m.columnTickets[m.activeColumn])-1, 0)
 		}
 		m.ensureTicketVisible()
 
-	case "n":
+	case keymap.NewTicket:
 		return m.createNewTicket()
-	case "e":
+	case keymap.EditTicket:
 		return m.editTicket()
-	case "enter":
+	case keymap.AttachAgent:
 		return m.attachToAgent()
-	case "d":
+	case keymap.Transcript:
+		return m.openTranscript()
+	case keymap.FocusPane:
+		return m.focusPane()
+	case keymap.ViewDiff:
+		return m.openDiff()
+	case keymap.ViewLog:
+		return m.openLog()
+	case keymap.RunTests:
+		return m.runTests()
+	case keymap.PushBranch:
+		return m.pushTicketBranch()
+	case keymap.CreatePR:
+		return m.confirmCreatePR()
+	case keymap.MergeTicket:
+		return m.confirmMerge(git.StrategyMerge)
+	case keymap.RebaseTicket:
+		return m.confirmMerge(git.StrategyRebase)
+	case keymap.SyncBase:
+		return m.confirmSync()
+	case keymap.Worktrees:
+		return m.openWorktrees()
+	case keymap.Sessions:
+		return m.openSessions()
+	case keymap.OpenEditor:
+		return m.openInEditor()
+	case keymap.EditNotes:
+		return m.editNotes()
+	case keymap.ResendPrompt:
+		return m.resendPrompt()
+	case keymap.FollowUps:
+		return m.backlogFollowUps()
+	case keymap.DeleteTicket:
 		return m.confirmDeleteTicket()
-	case " ":
+	case keymap.ArchiveTicket:
+		return m.confirmArchiveTicket()
+	case keymap.ColorTag:
+		return m.cycleColorTag()
+	case keymap.MoveForward:
 		return m.quickMoveTicket()
-	case "-", "backspace":
+	case keymap.MoveBackward:
 		return m.quickMoveTicketBackward()
-	case "s":
+	case keymap.ApproveTicket:
+		return m.approveTicket()
+	case keymap.RejectTicket:
+		return m.rejectTicket()
+	case keymap.RaiseTicket:
+		return m.reorderTicket(-1)
+	case keymap.LowerTicket:
+		return m.reorderTicket(1)
+	case keymap.SpawnAgent:
 		return m.spawnAgent()
-	case "S":
+	case keymap.StopAgent:
 		return m.stopAgent()
 
-	case ":":
+	case keymap.Command:
 		m.mode = ModeCommand
 
-	case "/":
+	case keymap.QuickSwitch:
+		return m.openSwitcher()
+	case keymap.SavedFilters:
+		return m.openSavedFilters()
+	case keymap.ToggleSplit:
+		return m.toggleSplit()
+	case keymap.CardDensity:
+		m.cycleCardDensity()
+		return m, nil
+	case keymap.TogglePreview:
+		m.toggleOutputPreview()
+		return m, nil
+	case keymap.FileFeed:
+		return m, m.toggleFileFeed()
+	case keymap.FocusMode:
+		m.toggleFocusMode()
+		return m, nil
+	case keymap.Collapse:
+		m.toggleCollapse()
+		return m, nil
+	case keymap.DebugOverlay:
+		m.toggleDebugOverlay()
+		return m, nil
+	case keymap.Notifications:
+		return m.openNotices(false)
+	case keymap.ErrorConsole:
+		return m.openNotices(true)
+	case keymap.Clipboard:
+		return m.openClips()
+	case keymap.ToggleMute:
+		m.toggleMute()
+		return m, nil
+	case keymap.NextTab:
+		m.cycleTab(1)
+	case keymap.PrevTab:
+		m.cycleTab(-1)
+
+	case keymap.Filter:
 		m.filterInput.SetValue(m.filterQuery)
 		m.filterInput.Focus()
 		m.mode = ModeFilter
 
-	case "O":
-		m.mode = ModeSettings
-		m.settingsIndex = 0
-		m.settingsEditing = false
+	case keymap.Settings:
+		m.openSettings()
+
+	case keymap.RecordMacro:
+		return m.toggleMacroRecording()
+
+	case keymap.Macros:
+		return m.openMacros()
+
+	case "":
+		if name, ok := m.macroForKey(msg.String()); ok {
+			return m.runMacro(name)
+		}
+		// Unbound digits jump straight to a project tab, 1 being all projects
+		if key := msg.String(); len(key) == 1 && key >= "1" && key <= "9" {
+			m.selectTab(int(key[0] - '1'))
+		}
 	}
 
 	return m, nil
 }
 
 func (m *Model) openAddProjectForm() (tea.Model, tea.Cmd) {
+	if m.readOnlyBlocked("add projects") {
+		return m, nil
+	}
 	m.addProjectPath.SetValue("")
 	m.addProjectPath.Focus()
 	m.mode = ModeCreateProject
@@ -663,15 +1159,24 @@ func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	projects := m.globalStore.Projects()
 	addIndex := len(projects) + 1
 
-	switch msg.String() {
-	case "j", "down":
+	key := msg.String()
+	switch action, _ := m.keys.Action(keymap.ContextBoard, key); {
+	case action == keymap.MoveDown || key == "down":
 		if m.sidebarIndex < addIndex {
 			m.sidebarIndex++
 		}
-	case "k", "up":
+		return m, nil
+	case action == keymap.MoveUp || key == "up":
 		if m.sidebarIndex > 0 {
 			m.sidebarIndex--
 		}
+		return m, nil
+	case action == keymap.MoveRight || key == "right":
+		m.sidebarFocused = false
+		return m, nil
+	}
+
+	switch key {
 	case "enter", " ":
 		if m.sidebarIndex == 0 {
 			m.toggleAllProjects()
@@ -683,9 +1188,6 @@ func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.toggleProjectFilter(projects[idx].ID)
 			}
 		}
-	case "l", "right":
-		m.sidebarFocused = false
-		return m, nil
 	case "a":
 		return m.openAddProjectForm()
 	case "d":
@@ -701,6 +1203,24 @@ func (m *Model) handleSidebarNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.inSplitPane(msg.X, msg.Y) && !m.dragging {
+		return m.handleSplitPaneMouse(msg)
+	}
+
+	// Wheel events arrive as presses, so they are picked out first
+	if msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown {
+		delta := 1
+		if msg.Button == tea.MouseButtonWheelUp {
+			delta = -1
+		}
+		// Scroll whichever column is under the pointer
+		if col, _ := m.hitTest(msg.X, msg.Y); col >= 0 {
+			m.scrollColumn(col, delta)
+			m.hoverColumn, m.hoverTicket = m.hitTest(msg.X, msg.Y)
+		}
+		return m, nil
+	}
+
 	switch msg.Action {
 	case tea.MouseActionPress:
 		if msg.Button == tea.MouseButtonLeft {
@@ -769,14 +1289,6 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		col, ticket := m.hitTest(msg.X, msg.Y)
 		m.hoverColumn = col
 		m.hoverTicket = ticket
-
-	default:
-		switch msg.Button {
-		case tea.MouseButtonWheelUp:
-			m.moveTicket(-1)
-		case tea.MouseButtonWheelDown:
-			m.moveTicket(1)
-		}
 	}
 
 	return m, nil
@@ -787,6 +1299,13 @@ func (m *Model) hitTestHeader(x, y int) bool {
 		return false
 	}
 
+	if tabs := m.projectTabs(); len(tabs) > 0 {
+		if i := m.tabAt(x, tabs); i >= 0 {
+			m.selectTab(i)
+			return true
+		}
+	}
+
 	if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
 		clearStart := 20 + len(m.filterQuery) + 15
 		if x >= clearStart && x <= clearStart+10 {
@@ -814,19 +1333,16 @@ func (m *Model) hitTest(x, y int) (column, ticket int) {
 		x = x - m.sidebarWidth - 1
 	}
 
-	headerHeight := 2
+	headerHeight := m.headerHeight()
 	if y < headerHeight {
 		return -1, -1
 	}
 
 	columnWidth := m.calcColumnWidth()
 	visibleCols := m.visibleColumnCount(columnWidth)
-	numVisible := visibleCols
-	if m.scrollOffset+visibleCols > len(m.columns) {
-		numVisible = len(m.columns) - m.scrollOffset
-	}
+	endCol := min(m.scrollOffset+visibleCols, len(m.columns))
 
-	baseWidth, remainder := m.distributeWidth(numVisible)
+	baseWidth, remainder := m.distributeWidth(m.scrollOffset, endCol)
 
 	hasLeftIndicator := m.scrollOffset > 0
 	startX := 0
@@ -834,16 +1350,20 @@ func (m *Model) hitTest(x, y int) (column, ticket int) {
 		startX = 2
 	}
 
-	for i := 0; i < numVisible; i++ {
-		colWidth := baseWidth + 3
-		if i < remainder {
-			colWidth++
+	expanded := 0
+	for i := m.scrollOffset; i < endCol; i++ {
+		colWidth := collapsedWidth + 3
+		if !m.isCollapsed(i) {
+			colWidth = baseWidth + 3
+			if expanded < remainder {
+				colWidth++
+			}
+			expanded++
 		}
 
 		if x >= startX && x < startX+colWidth {
-			actualCol := m.scrollOffset + i
-			ticketIdx := m.hitTestTicket(y-headerHeight, actualCol)
-			return actualCol, ticketIdx
+			ticketIdx := m.hitTestTicket(y-headerHeight, i)
+			return i, ticketIdx
 		}
 		startX += colWidth
 	}
@@ -852,7 +1372,7 @@ func (m *Model) hitTest(x, y int) (column, ticket int) {
 }
 
 func (m *Model) hitTestTicket(relativeY, column int) int {
-	if column < 0 || column >= len(m.columnTickets) {
+	if column < 0 || column >= len(m.columnTickets) || m.isCollapsed(column) {
 		return -1
 	}
 
@@ -871,7 +1391,15 @@ func (m *Model) hitTestTicket(relativeY, column int) int {
 		offset = m.columnOffsets[column]
 	}
 
-	ticketIdx := offset + (ticketY / ticketHeight)
+	if offset > 0 {
+		// The "▲ N more" line sits above the first card
+		ticketY--
+		if ticketY < 0 {
+			return -1
+		}
+	}
+
+	ticketIdx := offset + (ticketY / m.ticketHeight())
 	if ticketIdx >= len(tickets) {
 		return -1
 	}
@@ -880,7 +1408,7 @@ func (m *Model) hitTestTicket(relativeY, column int) int {
 }
 
 func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
-	if len(m.columnTickets) <= m.dragSourceColumn {
+	if len(m.columnTickets) <= m.dragSourceColumn || m.readOnlyBlocked("move tickets") {
 		m.dragging = false
 		return m, nil
 	}
@@ -894,9 +1422,14 @@ func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
 	ticket := tickets[m.dragSourceTicket]
 	targetStatus := m.columns[m.dragTargetColumn].Status
 
-	if targetStatus == board.StatusInProgress && ticket.WorktreePath == "" {
+	// An agent spawned by the move creates the worktree itself, as does the
+	// first spawn when creating it is deferred
+	spawn := targetStatus != ticket.Status && m.spawnsOnMove(ticket, targetStatus)
+	var setupCmd tea.Cmd
+	if targetStatus == board.StatusInProgress && ticket.WorktreePath == "" && !spawn && m.createsBranchOnStart(ticket.ProjectID) {
 		if ticket.UseWorktree {
-			if err := m.setupWorktree(ticket); err != nil {
+			var err error
+			if setupCmd, err = m.setupWorktree(ticket); err != nil {
 				m.notify("Worktree failed: " + err.Error())
 				m.dragging = false
 				return m, nil
@@ -910,6 +1443,7 @@ func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
 		}
 	}
 
+	fromStatus := ticket.Status
 	m.globalStore.Move(ticket.ID, targetStatus)
 	m.refreshColumnTickets()
 	m.saveTicket(ticket)
@@ -923,7 +1457,11 @@ func (m *Model) dropTicket() (tea.Model, tea.Cmd) {
 	m.dragging = false
 	m.dragTargetColumn = 0
 
-	return m, nil
+	m.publishTicketMoved(ticket, fromStatus)
+	if spawn {
+		return m.beginSpawn(ticket)
+	}
+	return m, setupCmd
 }
 
 func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -937,26 +1475,45 @@ func (m *Model) handleCommandMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmWant != "" {
+		return m.handleTypedConfirm(msg)
+	}
 	switch msg.String() {
 	case "y", "Y":
+		return m, m.answerConfirm(true)
+	case "n", "N":
+		return m, m.answerConfirm(false)
+	case "esc":
 		m.showConfirm = false
-		if m.confirmFn != nil {
-			return m, m.confirmFn()
-		}
-	case "n", "N", "esc":
-		m.showConfirm = false
+		m.declineFn = nil
 	}
 	return m, nil
 }
 
+// answerConfirm closes the confirm dialog and runs the chosen action
+func (m *Model) answerConfirm(yes bool) tea.Cmd {
+	fn := m.declineFn
+	if yes {
+		fn = m.confirmFn
+	}
+	m.showConfirm = false
+	m.declineFn = nil
+	m.confirmWant = ""
+	if fn == nil {
+		return nil
+	}
+	return fn()
+}
+
 func (m *Model) handleQuit() (tea.Model, tea.Cmd) {
-	runningCount := m.RunningAgentCount()
+	runningCount := m.runningEmbeddedAgents()
 	if runningCount == 0 {
 		return m, tea.Quit
 	}
 
 	if !m.config.Behavior.ConfirmQuitWithAgents {
 		m.mode = ModeShuttingDown
+		m.endAgentRuns(board.OutcomeStopped)
 		return m, tea.Batch(m.spinner.Tick, m.cleanupAsync())
 	}
 
@@ -965,11 +1522,26 @@ func (m *Model) handleQuit() (tea.Model, tea.Cmd) {
 	m.confirmFn = func() tea.Cmd {
 		m.mode = ModeShuttingDown
 		m.showConfirm = false
+		m.endAgentRuns(board.OutcomeStopped)
 		return tea.Batch(m.spinner.Tick, m.cleanupAsync())
 	}
 	return m, nil
 }
 
+// endAgentRuns closes the open agent run of every ticket with an embedded
+// pane. Agents in tmux outlive the board.
+func (m *Model) endAgentRuns(outcome board.AgentOutcome) {
+	for ticketID, pane := range m.panes {
+		if pane.Tmux() != nil {
+			continue
+		}
+		if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+			m.endAgentRun(ticket, outcome)
+			m.saveTicket(ticket)
+		}
+	}
+}
+
 func (m *Model) cleanupAsync() tea.Cmd {
 	return func() tea.Msg {
 		m.Cleanup()
@@ -985,13 +1557,27 @@ func (m *Model) handleAgentViewMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if result := pane.HandleKey(msg); result != nil {
-		if _, isExit := result.(terminal.ExitFocusMsg); isExit {
-			m.mode = ModeNormal
-			m.focusedPane = ""
+	if m.keys.Matches(msg.String(), keymap.DetachAgent) {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+		return m, nil
+	}
+
+	// Only with text selected, so the agent still gets the key otherwise
+	if m.keys.Matches(msg.String(), keymap.NewFromText) {
+		if selected := pane.Selection(); selected != "" {
+			return m.ticketFromSelection(m.focusedPane, selected)
 		}
 	}
 
+	switch result := pane.HandleKey(msg).(type) {
+	case terminal.ExitFocusMsg:
+		m.mode = ModeNormal
+		m.focusedPane = ""
+	case terminal.CopiedMsg:
+		m.recordClip(m.focusedPane, result.Text)
+	}
+
 	return m, nil
 }
 
@@ -1001,6 +1587,19 @@ func (m *Model) handleAgentViewMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.splitView {
+		if m.inSplitPane(msg.X, msg.Y) {
+			return m.handleSplitPaneMouse(msg)
+		}
+		// Clicking the board takes focus back from the agent
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			m.mode = ModeNormal
+			m.focusedPane = ""
+			return m.handleMouse(msg)
+		}
+		return m, nil
+	}
+
 	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
 		if msg.Y == 0 && msg.X >= m.width-25 {
 			m.mode = ModeNormal
@@ -1045,10 +1644,12 @@ func (m *Model) handleTicketFormMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	case relY >= 11 && relY <= 13:
 		clickedField = formFieldBranch
 	case relY >= 15 && relY <= 17:
-		clickedField = formFieldLabels
+		clickedField = formFieldBase
 	case relY >= 19 && relY <= 21:
+		clickedField = formFieldLabels
+	case relY >= 23 && relY <= 25:
 		clickedField = formFieldPriority
-	case relY >= 23:
+	case relY >= 27:
 		clickedField = formFieldProject
 	}
 
@@ -1059,7 +1660,7 @@ func (m *Model) handleTicketFormMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 		if clickedField == formFieldProject && !m.showAddProjectForm {
 			projects := m.globalStore.Projects()
-			projectRelY := relY - 24
+			projectRelY := relY - 28
 			if projectRelY >= 0 && projectRelY <= len(projects) {
 				m.projectListIndex = projectRelY
 				if projectRelY == len(projects) {
@@ -1089,7 +1690,7 @@ func (m *Model) handleTicketFormMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		m.labelsInput, cmd = m.labelsInput.Update(msg)
 	}
 
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadBaseBranches())
 }
 
 func (m *Model) handleCreateTicketMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -1129,6 +1730,9 @@ func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cm
 	case "ctrl+s":
 		return m.saveTicketForm(isEdit)
 
+	case "ctrl+o":
+		return m.editDescription()
+
 	case "enter":
 		if m.ticketFormField == formFieldTitle {
 			return m.saveTicketForm(isEdit)
@@ -1160,6 +1764,10 @@ func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cm
 		if !m.branchLocked {
 			m.branchInput, cmd = m.branchInput.Update(msg)
 		}
+	case formFieldBase:
+		if !m.branchLocked {
+			cmd = m.handleBaseBranchNav(msg)
+		}
 	case formFieldLabels:
 		m.labelsInput, cmd = m.labelsInput.Update(msg)
 	case formFieldPriority:
@@ -1170,6 +1778,8 @@ func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cm
 		if !m.agentLocked {
 			cmd = m.handleAgentNav(msg)
 		}
+	case formFieldModel:
+		cmd = m.handleModelNav(msg)
 	case formFieldBlockedBy:
 		cmd = m.handleBlockerNav(msg)
 	case formFieldProject:
@@ -1179,7 +1789,7 @@ func (m *Model) handleTicketForm(msg tea.KeyMsg, isEdit bool) (tea.Model, tea.Cm
 			cmd = m.handleProjectListNav(msg)
 		}
 	}
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadBaseBranches())
 }
 
 func (m *Model) handlePriorityNav(msg tea.KeyMsg) tea.Cmd {
@@ -1231,6 +1841,7 @@ func (m *Model) handleAgentNav(msg tea.KeyMsg) tea.Cmd {
 		}
 	}
 	m.ticketAgent = agents[m.agentListIndex]
+	m.resetTicketModel()
 	return nil
 }
 
@@ -1257,6 +1868,12 @@ func (m *Model) handleProjectListNav(msg tea.KeyMsg) tea.Cmd {
 
 	// Auto-select the highlighted project (if not on "+ Add project" option)
 	if m.projectListIndex < len(projects) {
+		// A new ticket's agent follows the project until one is picked
+		if m.mode == ModeCreateTicket && m.ticketAgent == m.getDefaultAgent(m.selectedProject) {
+			m.ticketAgent = m.getDefaultAgent(projects[m.projectListIndex])
+			m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+			m.ticketModel = ""
+		}
 		m.selectedProject = projects[m.projectListIndex]
 	}
 
@@ -1351,6 +1968,9 @@ func (m *Model) collectSelectedBlockers() []board.TicketID {
 }
 
 func (m *Model) confirmDeleteProject(p *project.Project) {
+	if m.readOnlyBlocked("delete projects") {
+		return
+	}
 	ticketCount := 0
 	for _, t := range m.globalStore.All() {
 		if t.ProjectID == p.ID {
@@ -1429,11 +2049,11 @@ func (m *Model) createProjectFromPath() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	gitDir := filepath.Join(absPath, ".git")
-	if _, err := os.Stat(gitDir); err != nil {
+	if !git.IsRepository(absPath) {
 		m.notify("Not a git repository")
 		return m, nil
 	}
+	absPath = git.ResolveMainRepo(absPath)
 
 	name := filepath.Base(absPath)
 
@@ -1446,7 +2066,10 @@ func (m *Model) createProjectFromPath() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	m.globalStore.AddProject(newProject)
+	if err := m.globalStore.AddProject(newProject); err != nil {
+		m.notify("Failed to open tickets: " + err.Error())
+		return m, nil
+	}
 	m.worktreeMgrs[newProject.ID] = git.NewWorktreeManager(newProject)
 	m.selectedProject = newProject
 	m.showAddProjectForm = false
@@ -1474,7 +2097,7 @@ func (m *Model) nextFormField(isEdit bool) *Model {
 		if m.ticketFormField > maxField {
 			m.ticketFormField = formFieldTitle
 		}
-		if m.ticketFormField == formFieldBranch && m.branchLocked {
+		if (m.ticketFormField == formFieldBranch || m.ticketFormField == formFieldBase) && m.branchLocked {
 			m.ticketFormField++
 			continue
 		}
@@ -1482,6 +2105,10 @@ func (m *Model) nextFormField(isEdit bool) *Model {
 			m.ticketFormField++
 			continue
 		}
+		if m.ticketFormField == formFieldModel && len(m.modelChoices()) < 2 {
+			m.ticketFormField++
+			continue
+		}
 		break
 	}
 	m.focusCurrentField()
@@ -1501,7 +2128,7 @@ func (m *Model) prevFormField(isEdit bool) *Model {
 		if m.ticketFormField < formFieldTitle {
 			m.ticketFormField = maxField
 		}
-		if m.ticketFormField == formFieldBranch && m.branchLocked {
+		if (m.ticketFormField == formFieldBranch || m.ticketFormField == formFieldBase) && m.branchLocked {
 			m.ticketFormField--
 			continue
 		}
@@ -1509,6 +2136,10 @@ func (m *Model) prevFormField(isEdit bool) *Model {
 			m.ticketFormField--
 			continue
 		}
+		if m.ticketFormField == formFieldModel && len(m.modelChoices()) < 2 {
+			m.ticketFormField--
+			continue
+		}
 		break
 	}
 	m.focusCurrentField()
@@ -1521,6 +2152,7 @@ func (m *Model) blurAllFormFields() {
 	m.branchInput.Blur()
 	m.labelsInput.Blur()
 	m.blockerFilterInput.Blur()
+	m.baseFilterInput.Blur()
 	m.projectInput.Blur()
 }
 
@@ -1532,6 +2164,8 @@ func (m *Model) focusCurrentField() {
 		m.descInput.Focus()
 	case formFieldBranch:
 		m.branchInput.Focus()
+	case formFieldBase:
+		m.baseFilterInput.Focus()
 	case formFieldLabels:
 		m.labelsInput.Focus()
 	case formFieldPriority:
@@ -1560,7 +2194,15 @@ func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
 	desc := strings.TrimSpace(m.descInput.Value())
 	branchName := strings.TrimSpace(m.branchInput.Value())
 	if branchName == "" {
-		branchName = m.generateBranchNameFromTitle(title, m.selectedProject)
+		key := m.globalStore.NextKey(m.selectedProject.ID)
+		var self board.TicketID
+		if isEdit {
+			self = m.editingTicketID
+			if ticket, _ := m.globalStore.Get(self); ticket != nil {
+				key = m.globalStore.TicketKey(ticket)
+			}
+		}
+		branchName = m.uniqueBranchName(m.generateBranchNameFromTitle(title, key, m.selectedProject), m.selectedProject.ID, self)
 	}
 
 	labels := m.parseLabels(m.labelsInput.Value())
@@ -1574,6 +2216,7 @@ func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
 			ticket.Description = desc
 			if !m.branchLocked {
 				ticket.BranchName = branchName
+				m.resolveBaseBranch(ticket)
 			}
 			ticket.Labels = labels
 			ticket.Priority = m.ticketPriority
@@ -1581,6 +2224,7 @@ func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
 			if !m.agentLocked {
 				ticket.AgentType = m.ticketAgent
 			}
+			ticket.Model = m.ticketModel
 			ticket.BlockedBy = blockedBy
 			ticket.Touch()
 			m.saveTicket(ticket)
@@ -1591,17 +2235,24 @@ func (m *Model) saveTicketForm(isEdit bool) (tea.Model, tea.Cmd) {
 		ticket := board.NewTicket(title, m.selectedProject.ID)
 		ticket.Description = desc
 		ticket.BranchName = branchName
+		m.resolveBaseBranch(ticket)
 		ticket.Labels = labels
 		ticket.Priority = m.ticketPriority
 		ticket.UseWorktree = m.ticketUseWorktree
 		ticket.AgentType = m.ticketAgent
+		ticket.Model = m.ticketModel
 		ticket.BlockedBy = blockedBy
 		ticket.Status = m.columns[m.activeColumn].Status
+		if m.ticketSource != "" {
+			ticket.Source = m.ticketSource
+			ticket.Status = board.StatusBacklog
+		}
 		m.globalStore.Add(ticket)
 		m.refreshColumnTickets()
 		m.selectTicketByID(ticket.ID)
 		m.saveTicket(ticket)
 		m.notify("Created: " + title)
+		m.publish(m.newEvent(events.TicketCreated, ticket))
 	}
 
 	m.mode = ModeNormal
@@ -1626,126 +2277,8 @@ func (m *Model) parseLabels(input string) []string {
 	return labels
 }
 
-type settingsField struct {
-	key         string
-	label       string
-	kind        string
-	description string
-}
-
-var settingsFields = []settingsField{
-	{"theme", "Theme", "theme", "Color theme for the UI"},
-	{"default_agent", "Default Agent", "agent", "Agent to spawn for new tickets (opencode, claude, aider)"},
-	{"confirm_quit", "Confirm Quit", "toggle", "Prompt before quitting with running agents"},
-	{"branch_prefix", "Branch Prefix", "text", "Prefix for auto-generated branch names (e.g. task/, feature/)"},
-	{"delete_worktree", "Delete Worktree", "toggle", "Remove git worktree when deleting tickets"},
-	{"delete_branch", "Delete Branch", "toggle", "Delete git branch when deleting tickets"},
-	{"force_cleanup", "Force Cleanup", "toggle", "Force worktree removal even with uncommitted changes"},
-	{"sidebar_visible", "Show Sidebar", "toggle", "Toggle the project sidebar visibility"},
-	{"filter_project", "Filter Project", "project", "Show only tickets from a specific project"},
-}
-
-func (m *Model) handleSettingsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.settingsEditing {
-		return m.handleSettingsEdit(msg)
-	}
-
-	switch msg.String() {
-	case "j", "down":
-		m.settingsIndex++
-		if m.settingsIndex >= len(settingsFields) {
-			m.settingsIndex = len(settingsFields) - 1
-		}
-	case "k", "up":
-		m.settingsIndex--
-		m.settingsIndex = max(m.settingsIndex, 0)
-	case "enter", " ":
-		return m.enterSettingsEdit()
-	case "esc", "q":
-		m.mode = ModeNormal
-	}
-	return m, nil
-}
-
-func (m *Model) handleSettingsEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	field := settingsFields[m.settingsIndex]
-
-	if field.kind == "project" {
-		m.filterInput.SetValue(m.filterQuery)
-		m.filterInput.Focus()
-		m.mode = ModeFilter
-		return m, textinput.Blink
-	}
-
-	if field.kind == "theme" {
-		return m.handleThemeNav(msg)
-	}
-
-	switch msg.String() {
-	case "enter":
-		m.applySettingsValue(field.key, m.settingsInput.Value())
-		m.settingsEditing = false
-		m.settingsInput.Blur()
-		m.notify("Settings saved")
-		return m, nil
-	case "esc":
-		m.settingsEditing = false
-		m.settingsInput.Blur()
-		return m, nil
-	}
-
-	var cmd tea.Cmd
-	m.settingsInput, cmd = m.settingsInput.Update(msg)
-	return m, cmd
-}
-
-func (m *Model) handleThemeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	themes := config.ThemeNames()
-	if len(themes) == 0 {
-		return m, nil
-	}
-
-	switch msg.String() {
-	case "j", "down":
-		m.themeListIndex++
-		if m.themeListIndex >= len(themes) {
-			m.themeListIndex = 0
-		}
-		m.applySettingsValue("theme", themes[m.themeListIndex])
-	case "k", "up":
-		m.themeListIndex--
-		if m.themeListIndex < 0 {
-			m.themeListIndex = len(themes) - 1
-		}
-		m.applySettingsValue("theme", themes[m.themeListIndex])
-	case "enter":
-		m.settingsEditing = false
-		m.notify("Theme: " + themes[m.themeListIndex])
-	case "esc":
-		m.settingsEditing = false
-	}
-
-	return m, nil
-}
-
-func (m *Model) handleSettingsMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
-		return m, nil
-	}
-
-	formTop := (m.height - 10) / 2
-	relY := msg.Y - formTop - 3
-
-	if relY >= 0 && relY < len(settingsFields) {
-		m.settingsIndex = relY
-		return m.enterSettingsEdit()
-	}
-
-	return m, nil
-}
-
 func (m *Model) handleConfirmMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
-	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || m.confirmWant != "" {
 		return m, nil
 	}
 
@@ -1757,156 +2290,16 @@ func (m *Model) handleConfirmMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 	if msg.Y == formCenterY+2 {
 		if msg.X >= yesX && msg.X <= yesX+5 {
-			m.showConfirm = false
-			if m.confirmFn != nil {
-				return m, m.confirmFn()
-			}
+			return m, m.answerConfirm(true)
 		}
 		if msg.X >= noX && msg.X <= noX+4 {
-			m.showConfirm = false
+			return m, m.answerConfirm(false)
 		}
 	}
 
 	return m, nil
 }
 
-func (m *Model) enterSettingsEdit() (tea.Model, tea.Cmd) {
-	field := settingsFields[m.settingsIndex]
-
-	switch field.kind {
-	case "project":
-		m.filterInput.SetValue(m.filterQuery)
-		m.filterInput.Focus()
-		m.mode = ModeFilter
-		return m, textinput.Blink
-
-	case "toggle":
-		m.applySettingsValue(field.key, "")
-		status := m.getSettingsValue(field.key)
-		m.notify(field.label + ": " + status)
-		return m, nil
-
-	case "theme":
-		themes := config.ThemeNames()
-		current := m.config.UI.Theme
-		m.themeListIndex = 0
-		for i, t := range themes {
-			if t == current {
-				m.themeListIndex = i
-				break
-			}
-		}
-		m.settingsEditing = true
-		return m, nil
-
-	case "agent":
-		agents := m.getAgentNames()
-		current := m.config.Defaults.DefaultAgent
-		currentIndex := 0
-		for i, a := range agents {
-			if a == current {
-				currentIndex = i
-				break
-			}
-		}
-		nextIndex := (currentIndex + 1) % len(agents)
-		nextAgent := agents[nextIndex]
-		m.applySettingsValue(field.key, nextAgent)
-		m.notify("Default agent: " + nextAgent)
-		return m, nil
-
-	case "text":
-		m.settingsEditing = true
-		m.settingsInput.SetValue(m.getSettingsValue(field.key))
-		m.settingsInput.Focus()
-		return m, textinput.Blink
-
-	default:
-		m.settingsEditing = true
-		m.settingsInput.SetValue(m.getSettingsValue(field.key))
-		m.settingsInput.Focus()
-		return m, textinput.Blink
-	}
-}
-
-func (m *Model) getSettingsValue(key string) string {
-	switch key {
-	case "theme":
-		return m.config.UI.Theme
-	case "default_agent":
-		return m.config.Defaults.DefaultAgent
-	case "confirm_quit":
-		if m.config.Behavior.ConfirmQuitWithAgents {
-			return "On"
-		}
-		return "Off"
-	case "branch_prefix":
-		return m.config.Defaults.BranchPrefix
-	case "delete_worktree":
-		if m.config.Cleanup.DeleteWorktree {
-			return "On"
-		}
-		return "Off"
-	case "delete_branch":
-		if m.config.Cleanup.DeleteBranch {
-			return "On"
-		}
-		return "Off"
-	case "force_cleanup":
-		if m.config.Cleanup.ForceWorktreeRemoval {
-			return "On"
-		}
-		return "Off"
-	case "filter_project":
-		count := len(m.filterProjectIDs)
-		if count == 0 {
-			return "All Projects"
-		}
-		return fmt.Sprintf("%d selected", count)
-	case "sidebar_visible":
-		if m.sidebarVisible {
-			return "On"
-		}
-		return "Off"
-	}
-	return ""
-}
-
-func (m *Model) applySettingsValue(key, value string) {
-	switch key {
-	case "theme":
-		m.config.UI.Theme = value
-		m.theme = m.config.GetTheme()
-		m.colors = newUIColors(m.theme)
-		m.config.Save("")
-	case "default_agent":
-		m.config.Defaults.DefaultAgent = value
-		m.config.Save("")
-	case "confirm_quit":
-		m.config.Behavior.ConfirmQuitWithAgents = !m.config.Behavior.ConfirmQuitWithAgents
-		m.config.Save("")
-	case "branch_prefix":
-		m.config.Defaults.BranchPrefix = value
-		m.config.Save("")
-	case "delete_worktree":
-		m.config.Cleanup.DeleteWorktree = !m.config.Cleanup.DeleteWorktree
-		m.config.Save("")
-	case "delete_branch":
-		m.config.Cleanup.DeleteBranch = !m.config.Cleanup.DeleteBranch
-		m.config.Save("")
-	case "force_cleanup":
-		m.config.Cleanup.ForceWorktreeRemoval = !m.config.Cleanup.ForceWorktreeRemoval
-		m.config.Save("")
-	case "sidebar_visible":
-		m.sidebarVisible = !m.sidebarVisible
-		m.config.UI.SidebarVisible = m.sidebarVisible
-		if !m.sidebarVisible {
-			m.sidebarFocused = false
-		}
-		m.config.Save("")
-	}
-}
-
 func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
@@ -2007,15 +2400,17 @@ func (m *Model) moveColumn(delta int) {
 
 func (m *Model) ensureColumnVisible() {
 	colWidth := m.calcColumnWidth()
-	visibleCols := m.visibleColumnCount(colWidth)
 
 	if m.activeColumn < m.scrollOffset {
 		m.scrollOffset = m.activeColumn
-	} else if m.activeColumn >= m.scrollOffset+visibleCols {
-		m.scrollOffset = m.activeColumn - visibleCols + 1
+	}
+	// Collapsed columns are narrower, so how many fit depends on where
+	// the board starts
+	for m.scrollOffset < m.activeColumn && m.activeColumn >= m.scrollOffset+m.visibleColumnCount(colWidth) {
+		m.scrollOffset++
 	}
 
-	maxOffset := max(len(m.columns)-visibleCols, 0)
+	maxOffset := max(len(m.columns)-m.visibleColumnCount(colWidth), 0)
 	if m.scrollOffset > maxOffset {
 		m.scrollOffset = maxOffset
 	}
@@ -2036,34 +2431,66 @@ func (m *Model) calcColumnWidth() int {
 		return minColumnWidth
 	}
 
-	numCols := len(m.columns)
+	numCols := 0
+	for i := range m.columns {
+		if m.isCollapsed(i) {
+			boardW -= m.columnFootprint(i, 0)
+		} else {
+			numCols++
+		}
+	}
+	if numCols == 0 {
+		return minColumnWidth
+	}
 	totalOverhead := numCols * columnOverhead
 	colWidth := (boardW - totalOverhead) / numCols
 
 	return max(colWidth, minColumnWidth)
 }
 
+// visibleColumnCount is how many columns fit from the scroll offset on.
+// When the rest of the board fits with room to spare, columns before the
+// offset are counted too, so a wider window scrolls back.
 func (m *Model) visibleColumnCount(colWidth int) int {
 	boardW := m.boardWidth()
 	if boardW == 0 {
 		return len(m.columns)
 	}
-	visible := boardW / (colWidth + columnOverhead)
-	visible = max(visible, 1)
-	if visible > len(m.columns) {
-		visible = len(m.columns)
+	visible, used := 0, 0
+	for i := m.scrollOffset; i < len(m.columns); i++ {
+		used += m.columnFootprint(i, colWidth)
+		if used > boardW {
+			return max(visible, 1)
+		}
+		visible++
 	}
-	return visible
+	for i := m.scrollOffset - 1; i >= 0; i-- {
+		used += m.columnFootprint(i, colWidth)
+		if used > boardW {
+			break
+		}
+		visible++
+	}
+	return max(visible, 1)
 }
 
-func (m *Model) distributeWidth(numCols int) (baseWidth, remainder int) {
+// distributeWidth shares the board's width out among the expanded columns
+// from start to end, after the collapsed ones take theirs
+func (m *Model) distributeWidth(start, end int) (baseWidth, remainder int) {
 	boardW := m.boardWidth()
+	numCols := 0
+	available := boardW - max(end-start-1, 0)
+	for i := start; i < end; i++ {
+		if m.isCollapsed(i) {
+			available -= collapsedWidth + 2
+		} else {
+			numCols++
+			available -= 2
+		}
+	}
 	if numCols == 0 || boardW == 0 {
 		return minColumnWidth, 0
 	}
-	borders := numCols * 2
-	margins := numCols - 1
-	available := boardW - borders - margins
 	baseWidth = available / numCols
 	remainder = available % numCols
 	if baseWidth < minColumnWidth {
@@ -2091,12 +2518,12 @@ func (m *Model) visibleTicketCount() int {
 	if availableHeight <= 0 {
 		return 1
 	}
-	count := availableHeight / ticketHeight
+	count := availableHeight / m.ticketHeight()
 	return max(count, 1)
 }
 
 func (m *Model) columnContentHeight() int {
-	boardHeight := m.height - 4
+	boardHeight := m.boardHeight() - 4
 	contentHeight := boardHeight - columnHeaderHeight - 4
 	return contentHeight
 }
@@ -2118,10 +2545,30 @@ func (m *Model) ensureTicketVisible() {
 	m.columnOffsets[m.activeColumn] = max(m.columnOffsets[m.activeColumn], 0)
 }
 
+// scrollColumn scrolls a column by delta tickets without moving focus to it.
+// The cursor is pulled along when it would leave the active column's view.
+func (m *Model) scrollColumn(column, delta int) {
+	if column < 0 || column >= len(m.columnOffsets) || column >= len(m.columnTickets) {
+		return
+	}
+	visible := m.visibleTicketCount()
+	maxOffset := max(len(m.columnTickets[column])-visible, 0)
+	offset := max(min(m.columnOffsets[column]+delta, maxOffset), 0)
+	m.columnOffsets[column] = offset
+
+	if column == m.activeColumn && len(m.columnTickets[column]) > 0 {
+		m.activeTicket = max(min(m.activeTicket, offset+visible-1), offset)
+	}
+}
+
 func (m *Model) createNewTicket() (tea.Model, tea.Cmd) {
+	if m.readOnlyBlocked("create tickets") {
+		return m, nil
+	}
 	m.mode = ModeCreateTicket
 	m.ticketFormField = formFieldTitle
 	m.editingTicketID = ""
+	m.ticketSource = ""
 	m.branchLocked = false
 	m.agentLocked = false
 	m.showAddProjectForm = false
@@ -2148,8 +2595,9 @@ func (m *Model) createNewTicket() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	m.ticketAgent = m.getDefaultAgent()
+	m.ticketAgent = m.getDefaultAgent(m.selectedProject)
 	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+	m.ticketModel = ""
 
 	m.titleInput.Reset()
 	m.descInput.Reset()
@@ -2162,11 +2610,12 @@ func (m *Model) createNewTicket() (tea.Model, tea.Cmd) {
 	m.selectedBlockers = make(map[board.TicketID]bool)
 	m.blockerListIndex = 0
 	m.blockerFilterInput.Reset()
+	m.resetBaseBranches("")
 	m.formScrollOffset = 0
 
 	m.blurAllFormFields()
 	m.titleInput.Focus()
-	return m, m.titleInput.Cursor.BlinkCmd()
+	return m, tea.Batch(m.titleInput.Cursor.BlinkCmd(), m.loadBaseBranches())
 }
 
 func (m *Model) editTicket() (tea.Model, tea.Cmd) {
@@ -2175,6 +2624,13 @@ func (m *Model) editTicket() (tea.Model, tea.Cmd) {
 		m.notify("No ticket selected")
 		return m, nil
 	}
+	if m.readOnlyBlocked("edit tickets") {
+		return m, nil
+	}
+	if editor := m.ticketEditor(ticket.ID); editor != "" {
+		m.notify("Locked: " + editor + " is editing this ticket")
+		return m, nil
+	}
 
 	m.mode = ModeEditTicket
 	m.ticketFormField = formFieldTitle
@@ -2187,7 +2643,7 @@ func (m *Model) editTicket() (tea.Model, tea.Cmd) {
 	if ticket.BranchName != "" {
 		m.branchInput.SetValue(ticket.BranchName)
 	} else if m.selectedProject != nil {
-		m.branchInput.SetValue(m.generateBranchNameFromTitle(ticket.Title, m.selectedProject))
+		m.branchInput.SetValue(m.generateBranchName(ticket, m.selectedProject))
 	}
 	m.labelsInput.SetValue(strings.Join(ticket.Labels, ", "))
 	m.ticketPriority = ticket.Priority
@@ -2195,12 +2651,9 @@ func (m *Model) editTicket() (tea.Model, tea.Cmd) {
 		m.ticketPriority = 3
 	}
 	m.ticketUseWorktree = ticket.UseWorktree
-	if ticket.AgentType != "" {
-		m.ticketAgent = ticket.AgentType
-	} else {
-		m.ticketAgent = m.getDefaultAgent()
-	}
+	m.ticketAgent = m.agentNameFor(ticket)
 	m.agentListIndex = m.getAgentIndex(m.ticketAgent)
+	m.ticketModel = ticket.Model
 
 	m.initBlockerCandidates(ticket.ID)
 	m.selectedBlockers = make(map[board.TicketID]bool)
@@ -2209,11 +2662,15 @@ func (m *Model) editTicket() (tea.Model, tea.Cmd) {
 	}
 	m.blockerListIndex = 0
 	m.blockerFilterInput.Reset()
+	m.resetBaseBranches(ticket.BaseBranch)
 	m.formScrollOffset = 0
 
 	m.blurAllFormFields()
 	m.titleInput.Focus()
-	return m, m.titleInput.Cursor.BlinkCmd()
+	if m.branchLocked {
+		return m, m.titleInput.Cursor.BlinkCmd()
+	}
+	return m, tea.Batch(m.titleInput.Cursor.BlinkCmd(), m.loadBaseBranches())
 }
 
 func (m *Model) attachToAgent() (tea.Model, tea.Cmd) {
@@ -2228,11 +2685,13 @@ func (m *Model) attachToAgent() (tea.Model, tea.Cmd) {
 		m.notify("No agent running — press 's' to spawn")
 		return m, nil
 	}
+	if pane.Tmux() != nil {
+		return m, m.attachTmux(pane.Tmux())
+	}
 
 	m.mode = ModeAgentView
 	m.focusedPane = ticket.ID
-	paneHeight := m.height - 2
-	pane.SetSize(m.width, paneHeight)
+	pane.SetSize(m.paneSize())
 	return m, nil
 }
 
@@ -2250,16 +2709,21 @@ func (m *Model) handleDoubleClick() (tea.Model, tea.Cmd) {
 	return m.spawnAgent()
 }
 
+// confirmDeleteTicket asks before deleting the selected ticket as
+// behavior.confirm says. Uncommitted changes always ask, and with
+// type_to_delete a deletion that removes a worktree wants the title typed.
 func (m *Model) confirmDeleteTicket() (tea.Model, tea.Cmd) {
 	ticket := m.selectedTicket()
-	if ticket == nil {
+	if ticket == nil || m.readOnlyBlocked("delete tickets") {
 		return m, nil
 	}
 
 	proj := m.globalStore.GetProjectForTicket(ticket)
+	removesWorktree := false
 	hasUncommitted := false
 	if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree && proj != nil {
 		if mgr := m.worktreeMgrs[proj.ID]; mgr != nil {
+			removesWorktree = ticket.WorktreePath != proj.RepoPath
 			var err error
 			hasUncommitted, err = mgr.HasUncommittedChanges(ticket.WorktreePath)
 			if err != nil {
@@ -2268,62 +2732,149 @@ func (m *Model) confirmDeleteTicket() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if hasUncommitted && !m.config.Cleanup.ForceWorktreeRemoval {
-		m.showConfirm = true
-		m.confirmMsg = "Worktree has uncommitted changes. Force delete?"
-		m.confirmFn = func() tea.Cmd {
-			m.performTicketCleanup(ticket)
-			return nil
-		}
-	} else {
-		m.showConfirm = true
-		m.confirmMsg = "Delete ticket: " + ticket.Title + "?"
-		m.confirmFn = func() tea.Cmd {
-			m.performTicketCleanup(ticket)
-			return nil
+	deleteTicket := func() tea.Cmd {
+		return m.performTicketCleanup(ticket)
+	}
+	switch {
+	case removesWorktree && m.config.Behavior.Confirm.TypeToDelete:
+		msg := "Delete the ticket and its worktree?"
+		if hasUncommitted {
+			msg += " It has uncommitted changes."
 		}
+		return m, m.askTypedConfirm(msg, ticket.Title, deleteTicket)
+	case hasUncommitted && !m.config.Cleanup.ForceWorktreeRemoval:
+		return m, m.askConfirm(true, "Worktree has uncommitted changes. Force delete?", deleteTicket)
 	}
-	return m, nil
+	return m, m.askConfirm(m.config.Behavior.Confirm.DeleteTicket, "Delete ticket: "+ticket.Title+"?", deleteTicket)
 }
 
-func (m *Model) performTicketCleanup(ticket *board.Ticket) {
+func (m *Model) performTicketCleanup(ticket *board.Ticket) tea.Cmd {
 	ticketTitle := ticket.Title // Capture before deletion
 
 	if pane, ok := m.panes[ticket.ID]; ok {
 		pane.Stop()
 		delete(m.panes, ticket.ID)
 	}
+	m.cancelGitOp(ticket.ID)
 
+	// The worktree and branch go in the background once the ticket is gone
+	var cmd tea.Cmd
 	proj := m.globalStore.GetProjectForTicket(ticket)
 	if proj != nil {
 		mgr := m.worktreeMgrs[proj.ID]
 		if mgr != nil {
+			var path, branch string
 			if ticket.WorktreePath != "" && m.config.Cleanup.DeleteWorktree {
-				err := mgr.RemoveWorktree(ticket.WorktreePath)
-				if err != nil {
-					m.notify("Failed to remove worktree: " + err.Error())
-				}
+				path = ticket.WorktreePath
 			}
-
 			if ticket.BranchName != "" && m.config.Cleanup.DeleteBranch {
-				err := mgr.DeleteBranch(ticket.BranchName)
-				if err != nil {
-					m.notify("Failed to delete branch: " + err.Error())
-				}
+				branch = ticket.BranchName
+			}
+			if path != "" || branch != "" {
+				cmd = m.removeWorktreeCmd(ticket, mgr, path, branch, true)
 			}
 		}
 	}
 
 	m.globalStore.RemoveBlockerReferences(ticket.ID)
 	m.globalStore.Delete(ticket.ID)
+	m.publish(m.newEvent(events.TicketDeleted, ticket))
 	m.refreshColumnTickets()
-	m.globalStore.SaveAll()
+	if err := m.globalStore.SaveAll(); err != nil {
+		m.notify("Failed to save: " + err.Error())
+	}
 	m.notify("Deleted: " + ticketTitle)
+	return cmd
+}
+
+func (m *Model) confirmArchiveTicket() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("archive tickets") {
+		return m, nil
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = "Archive ticket: " + ticket.Title + "?"
+	m.confirmFn = func() tea.Cmd {
+		return m.archiveTicket(ticket)
+	}
+	return m, nil
+}
+
+// archiveTicket hides the ticket from the board, then removes its worktree
+// as cleanup.on_archive says. Worktrees with uncommitted changes are only
+// removed after asking, unless cleanup.force_worktree_removal is set.
+func (m *Model) archiveTicket(ticket *board.Ticket) tea.Cmd {
+	if pane, ok := m.panes[ticket.ID]; ok {
+		pane.Stop()
+		delete(m.panes, ticket.ID)
+		m.setAgentStatus(ticket, board.AgentNone)
+		m.endAgentRun(ticket, board.OutcomeStopped)
+	}
+
+	fromStatus := ticket.Status
+	m.globalStore.Move(ticket.ID, board.StatusArchived)
+	m.refreshColumnTickets()
+	m.moveTicket(0)
+	m.saveTicket(ticket)
+	m.notify("Archived: " + ticket.Title)
+	m.publishTicketMoved(ticket, fromStatus)
+
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if proj == nil || mgr == nil || ticket.WorktreePath == "" || ticket.WorktreePath == proj.RepoPath {
+		return nil
+	}
+
+	policy := m.config.Cleanup.OnArchive
+	dirty, _ := mgr.HasUncommittedChanges(ticket.WorktreePath)
+	if policy == "never" {
+		return nil
+	}
+	if policy == "always" && (!dirty || m.config.Cleanup.ForceWorktreeRemoval) {
+		return m.removeTicketWorktree(ticket, m.config.Cleanup.DeleteBranch)
+	}
+
+	what := "worktree"
+	if m.config.Cleanup.DeleteBranch {
+		what = "worktree and branch"
+	}
+	m.showConfirm = true
+	m.confirmMsg = "Remove the archived ticket's " + what + "?"
+	if dirty {
+		m.confirmMsg += " It has uncommitted changes."
+	}
+	m.confirmFn = func() tea.Cmd {
+		return m.removeTicketWorktree(ticket, m.config.Cleanup.DeleteBranch)
+	}
+	return nil
+}
+
+// removeTicketWorktree removes a ticket's worktree, and optionally its
+// branch, in the background but keeps the ticket itself
+func (m *Model) removeTicketWorktree(ticket *board.Ticket, deleteBranch bool) tea.Cmd {
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		return nil
+	}
+	if _, busy := m.gitOps[ticket.ID]; busy {
+		m.notify("Worktree is busy — try again when it finishes")
+		return nil
+	}
+	if pane, ok := m.panes[ticket.ID]; ok {
+		pane.Stop()
+		delete(m.panes, ticket.ID)
+	}
+	branch := ""
+	if deleteBranch {
+		branch = ticket.BranchName
+	}
+	return m.removeWorktreeCmd(ticket, mgr, ticket.WorktreePath, branch, false)
 }
 
 func (m *Model) quickMoveTicket() (tea.Model, tea.Cmd) {
 	ticket := m.selectedTicket()
-	if ticket == nil {
+	if ticket == nil || m.readOnlyBlocked("move tickets") {
 		return m, nil
 	}
 
@@ -2332,9 +2883,14 @@ func (m *Model) quickMoveTicket() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if nextStatus == board.StatusInProgress && ticket.WorktreePath == "" {
+	// An agent spawned by the move creates the worktree itself, as does the
+	// first spawn when creating it is deferred
+	spawn := m.spawnsOnMove(ticket, nextStatus)
+	var setupCmd tea.Cmd
+	if nextStatus == board.StatusInProgress && ticket.WorktreePath == "" && !spawn && m.createsBranchOnStart(ticket.ProjectID) {
 		if ticket.UseWorktree {
-			if err := m.setupWorktree(ticket); err != nil {
+			var err error
+			if setupCmd, err = m.setupWorktree(ticket); err != nil {
 				m.notify("Worktree failed: " + err.Error())
 				return m, nil
 			}
@@ -2346,18 +2902,23 @@ func (m *Model) quickMoveTicket() (tea.Model, tea.Cmd) {
 		}
 	}
 
+	fromStatus := ticket.Status
 	m.globalStore.Move(ticket.ID, nextStatus)
 	m.refreshColumnTickets()
 	m.selectTicketByID(ticket.ID)
 	m.saveTicket(ticket)
 	m.notify("Moved to " + string(nextStatus))
 
-	return m, nil
+	m.publishTicketMoved(ticket, fromStatus)
+	if spawn {
+		return m.beginSpawn(ticket)
+	}
+	return m, setupCmd
 }
 
 func (m *Model) quickMoveTicketBackward() (tea.Model, tea.Cmd) {
 	ticket := m.selectedTicket()
-	if ticket == nil {
+	if ticket == nil || m.readOnlyBlocked("move tickets") {
 		return m, nil
 	}
 
@@ -2366,38 +2927,35 @@ func (m *Model) quickMoveTicketBackward() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	fromStatus := ticket.Status
 	m.globalStore.Move(ticket.ID, prevStatus)
 	m.refreshColumnTickets()
 	m.selectTicketByID(ticket.ID)
 	m.saveTicket(ticket)
 	m.notify("Moved to " + string(prevStatus))
 
+	m.publishTicketMoved(ticket, fromStatus)
 	return m, nil
 }
 
-func (m *Model) setupWorktree(ticket *board.Ticket) error {
+// setupWorktree starts creating the ticket's worktree in the background.
+// The returned command goes on to run the project's setup commands in it.
+func (m *Model) setupWorktree(ticket *board.Ticket) (tea.Cmd, error) {
 	proj := m.globalStore.GetProjectForTicket(ticket)
 	if proj == nil {
-		return fmt.Errorf("project not found for ticket")
+		return nil, fmt.Errorf("project not found for ticket")
 	}
 
 	mgr := m.worktreeMgrs[proj.ID]
 	if mgr == nil {
-		return fmt.Errorf("worktree manager not found")
+		return nil, fmt.Errorf("worktree manager not found")
 	}
-
-	branchName := m.generateBranchName(ticket, proj)
-	baseBranch, _ := mgr.GetDefaultBranch()
-
-	path, err := mgr.CreateWorktree(branchName, baseBranch)
-	if err != nil {
-		return err
+	if _, busy := m.gitOps[ticket.ID]; busy {
+		return nil, fmt.Errorf("worktree already being created")
 	}
 
-	ticket.WorktreePath = path
-	ticket.BranchName = branchName
-	ticket.BaseBranch = baseBranch
-	return nil
+	ticket.BranchName = m.generateBranchName(ticket, proj)
+	return m.createWorktreeCmd(ticket, mgr, ticket.BranchName, ticket.BaseBranch, ticket.Status), nil
 }
 
 func (m *Model) setupMainRepoBranch(ticket *board.Ticket) error {
@@ -2410,9 +2968,15 @@ func (m *Model) setupMainRepoBranch(ticket *board.Ticket) error {
 	if mgr == nil {
 		return fmt.Errorf("worktree manager not found")
 	}
+	if mgr.IsBare() {
+		return git.ErrBareRepo
+	}
 
 	branchName := m.generateBranchName(ticket, proj)
-	baseBranch, _ := mgr.GetDefaultBranch()
+	baseBranch := ticket.BaseBranch
+	if baseBranch == "" {
+		baseBranch, _ = mgr.GetDefaultBranch()
+	}
 
 	ticket.WorktreePath = proj.RepoPath
 	ticket.BranchName = branchName
@@ -2420,7 +2984,9 @@ func (m *Model) setupMainRepoBranch(ticket *board.Ticket) error {
 	return nil
 }
 
-func (m *Model) generateBranchNameFromTitle(title string, proj *project.Project) string {
+// generateBranchNameFromTitle fills in the branch template for a ticket
+// titled title with the short key key
+func (m *Model) generateBranchNameFromTitle(title, key string, proj *project.Project) string {
 	maxLen := m.getSlugMaxLength(proj)
 	slug := board.Slugify(title, maxLen)
 
@@ -2429,6 +2995,7 @@ func (m *Model) generateBranchNameFromTitle(title string, proj *project.Project)
 
 	result := strings.ReplaceAll(template, "{prefix}", prefix)
 	result = strings.ReplaceAll(result, "{slug}", slug)
+	result = strings.ReplaceAll(result, "{key}", key)
 
 	return result
 }
@@ -2437,7 +3004,21 @@ func (m *Model) generateBranchName(ticket *board.Ticket, proj *project.Project)
 	if ticket.BranchName != "" {
 		return ticket.BranchName
 	}
-	return m.generateBranchNameFromTitle(ticket.Title, proj)
+	return m.uniqueBranchName(m.generateBranchNameFromTitle(ticket.Title, proj.TicketKey(ticket), proj), proj.ID, ticket.ID)
+}
+
+// uniqueBranchName suffixes a generated branch name with -2, -3 and so on
+// while another ticket in the project has it, so tickets with the same
+// title don't share a branch and worktree
+func (m *Model) uniqueBranchName(name, projectID string, self board.TicketID) string {
+	return board.Unique(name, func(candidate string) bool {
+		for _, t := range m.globalStore.All() {
+			if t.ID != self && t.ProjectID == projectID && t.BranchName == candidate {
+				return true
+			}
+		}
+		return false
+	})
 }
 
 func (m *Model) allocateAgentPort() int {
@@ -2457,7 +3038,7 @@ func (m *Model) allocateAgentPort() int {
 
 func (m *Model) spawnAgent() (tea.Model, tea.Cmd) {
 	ticket := m.selectedTicket()
-	if ticket == nil {
+	if ticket == nil || m.readOnlyBlocked("start agents") {
 		return m, nil
 	}
 
@@ -2466,15 +3047,103 @@ func (m *Model) spawnAgent() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if _, exists := m.panes[ticket.ID]; exists {
-		m.notify("Agent already running — press Enter to attach")
+	return m.beginSpawn(ticket)
+}
+
+// beginSpawn spawns the ticket's agent, opening its init prompt for review
+// first when review_prompt is on
+func (m *Model) beginSpawn(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	if _, running := m.panes[ticket.ID]; m.config.Defaults.ReviewPrompt && !running && ticket.AgentSpawnedAt == nil {
+		if prompt := m.contextPrompt(ticket); prompt != "" {
+			return m.reviewPrompt(ticket.ID, prompt, false)
+		}
+	}
+	return m.checkAndSpawn(ticket)
+}
+
+// createsBranchOnStart reports whether a ticket's branch and worktree are
+// created when it moves to In Progress. With auto_create_branch off,
+// globally or for the project, git is left alone until the first spawn.
+func (m *Model) createsBranchOnStart(projectID string) bool {
+	if proj := m.globalStore.GetProject(projectID); proj != nil && !proj.Settings.AutoCreateBranch {
+		return false
+	}
+	return m.config.Defaults.AutoCreateBranch
+}
+
+// spawnsOnMove reports whether moving ticket into status spawns its agent:
+// auto_spawn_agent is on, globally and for the project, and status is one
+// of the project's spawn_on columns, or the global ones when it has none
+func (m *Model) spawnsOnMove(ticket *board.Ticket, status board.TicketStatus) bool {
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil || !m.config.Defaults.AutoSpawnAgent || !proj.Settings.AutoSpawnAgent {
+		return false
+	}
+	if _, running := m.panes[ticket.ID]; running {
+		return false
+	}
+	if len(proj.Settings.SpawnOn) > 0 {
+		return slices.Contains(proj.Settings.SpawnOn, status)
+	}
+	return slices.Contains(m.config.Defaults.SpawnOn, string(status))
+}
+
+// checkAndSpawn spawns the ticket's agent once the stale base and
+// uncommitted changes have been dealt with
+func (m *Model) checkAndSpawn(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	if warning := m.staleBaseWarning(ticket); warning != "" {
+		m.showConfirm = true
+		m.confirmMsg = warning + " Spawn anyway?"
+		m.confirmFn = func() tea.Cmd {
+			_, cmd := m.offerStash(ticket)
+			return cmd
+		}
+		return m, nil
+	}
+	return m.offerStash(ticket)
+}
+
+func (m *Model) spawnTicketAgent(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	agentType, cmd, err := m.startSpawn(ticket, false)
+	if err != nil {
+		delete(m.spawnPrompts, ticket.ID)
+		if errors.Is(err, errAgentRunning) {
+			m.notify("Agent already running — press Enter to attach")
+		} else {
+			m.notify(err.Error())
+		}
 		return m, nil
 	}
 
+	m.mode = ModeSpawning
+	m.spawningTicketID = ticket.ID
+	m.spawningAgent = agentType
+
+	return m, tea.Batch(m.spinner.Tick, cmd)
+}
+
+var errAgentRunning = errors.New("agent already running")
+
+// startSpawn validates that an agent can be spawned for ticket and returns the
+// configured agent name along with the command that prepares its pane.
+// Background spawns start the agent without switching to the spawning view.
+func (m *Model) startSpawn(ticket *board.Ticket, background bool) (string, tea.Cmd, error) {
+	if _, exists := m.panes[ticket.ID]; exists {
+		return "", nil, errAgentRunning
+	}
+	if m.spawnPending[ticket.ID] {
+		return "", nil, errors.New("agent is already starting")
+	}
+	if _, busy := m.setups[ticket.ID]; busy {
+		return "", nil, errors.New("worktree setup still running")
+	}
+	if _, busy := m.gitOps[ticket.ID]; busy {
+		return "", nil, errors.New("worktree still being created or removed")
+	}
+
 	proj := m.globalStore.GetProjectForTicket(ticket)
 	if proj == nil {
-		m.notify("Project not found for this ticket")
-		return m, nil
+		return "", nil, errors.New("project not found for this ticket")
 	}
 
 	if !ticket.UseWorktree {
@@ -2486,42 +3155,44 @@ func (m *Model) spawnAgent() (tea.Model, tea.Cmd) {
 			if other != nil && !other.UseWorktree {
 				otherProj := m.globalStore.GetProjectForTicket(other)
 				if otherProj != nil && otherProj.ID == proj.ID {
-					m.notify("Another main-repo agent is running in this project")
-					return m, nil
+					return "", nil, errors.New("another main-repo agent is running in this project")
 				}
 			}
 		}
 	}
 
-	agentType := ticket.AgentType
-	if agentType == "" {
-		agentType = m.config.Defaults.DefaultAgent
-	}
+	agentType := m.agentNameFor(ticket)
 	agentCfg, ok := m.config.Agents[agentType]
 	if !ok {
-		m.notify("Agent '" + agentType + "' not configured")
-		return m, nil
+		return "", nil, errors.New("agent '" + agentType + "' not configured")
+	}
+	if m.config.Tmux.Enabled && !terminal.TmuxAvailable() {
+		return "", nil, errors.New("tmux.enabled is set but tmux is not installed")
 	}
 
 	// Start opencode server on-demand if spawning opencode agent
 	if agentType == "opencode" {
-		_ = m.opencodeServer.Start() // Best effort, ignore errors
+		if err := m.opencodeServer.Start(); err != nil {
+			// Agents still start; they just run without the shared server
+			m.notify("Failed to start opencode server: " + err.Error())
+		}
 	}
 
-	m.mode = ModeSpawning
-	m.spawningTicketID = ticket.ID
-	m.spawningAgent = agentType
-
-	return m, tea.Batch(m.spinner.Tick, m.prepareSpawn(ticket, proj, agentCfg))
+	if m.spawnPending == nil {
+		m.spawnPending = make(map[board.TicketID]bool)
+	}
+	m.spawnPending[ticket.ID] = true
+	return agentType, m.prepareSpawn(ticket, proj, agentType, agentCfg, background), nil
 }
 
-func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentCfg config.AgentConfig) tea.Cmd {
+func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentName string, agentCfg config.AgentConfig, background bool) tea.Cmd {
 	ticketID := ticket.ID
 	worktreePath := ticket.WorktreePath
 	branchName := ticket.BranchName
 	baseBranch := ticket.BaseBranch
 	useWorktree := ticket.UseWorktree
-	width, height := m.width, m.height-2
+	highlights := m.highlights
+	width, height := m.paneSize()
 
 	agentType := agentCfg.Command
 	if strings.Contains(agentType, "/") {
@@ -2529,7 +3200,7 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 	}
 
 	agentPort := ticket.AgentPort
-	if agentPort == 0 && agentType == "opencode" {
+	if agentPort == 0 && (agentType == "opencode" || agent.UsesPort(agentCfg.SpawnArgs) || agent.UsesPort(agentCfg.ResumeArgs)) {
 		agentPort = m.allocateAgentPort()
 		ticket.AgentPort = agentPort
 		m.saveTicket(ticket)
@@ -2537,20 +3208,34 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 
 	mgr := m.worktreeMgrs[proj.ID]
 	cfg := m.config
+	tmuxSession := m.tmuxSession(proj)
+	generatedBranch := m.generateBranchName(ticket, proj)
+	if ticket.Model != "" && (ticket.AgentType == "" || ticket.AgentType == agentName) {
+		agentCfg.Model = ticket.Model
+	}
+	ticketKey := proj.TicketKey(ticket)
+	if ticketKey == "" {
+		ticketKey = string(ticketID)
+	}
+	reviewedPrompt := m.spawnPrompts[ticketID]
+	delete(m.spawnPrompts, ticketID)
+	feedback := ticket.Feedback
+	box := proj.Settings.Sandbox
+	agentEnv := append(agent.Environ(agentCfg), controlEnv()...)
 
-	return func() tea.Msg {
-		if mgr == nil {
-			return spawnErrorMsg{ticketID: ticketID, err: "worktree manager not found"}
-		}
+	// The agent waits for the setup of a worktree created here
+	var setup *worktreeSetup
+	if useWorktree && proj.HasWorktreeSetup() {
+		setup = m.trackSetup(ticketID)
+	}
+	ctx := context.Background()
+	if _, err := os.Stat(worktreePath); useWorktree && (worktreePath == "" || err != nil) {
+		ctx = m.startGitOp(ticketID, "creating worktree", true)
+	}
 
-		generatedBranch := branchName
-		if generatedBranch == "" {
-			maxLen := m.getSlugMaxLength(proj)
-			slug := board.Slugify(ticket.Title, maxLen)
-			template := m.getBranchTemplate(proj)
-			prefix := m.getBranchPrefix(proj)
-			generatedBranch = strings.ReplaceAll(template, "{prefix}", prefix)
-			generatedBranch = strings.ReplaceAll(generatedBranch, "{slug}", slug)
+	spawn := func() tea.Msg {
+		if mgr == nil {
+			return spawnErrorMsg{ticketID: ticketID, err: "worktree manager not found", background: background}
 		}
 
 		base, _ := mgr.GetDefaultBranch()
@@ -2559,16 +3244,31 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 		}
 
 		if useWorktree {
-			if worktreePath == "" {
-				path, err := mgr.CreateWorktree(generatedBranch, base)
+			if _, err := os.Stat(worktreePath); worktreePath == "" || err != nil {
+				if base, err = localBase(mgr, base, baseBranch); err != nil {
+					return spawnErrorMsg{ticketID: ticketID, err: err.Error(), background: background}
+				}
+				path, err := mgr.CreateWorktreeContext(ctx, generatedBranch, base)
+				if errors.Is(err, context.Canceled) {
+					return spawnErrorMsg{ticketID: ticketID, err: "worktree cancelled", background: background}
+				}
 				if err != nil {
-					return spawnErrorMsg{ticketID: ticketID, err: "worktree failed: " + err.Error()}
+					return spawnErrorMsg{ticketID: ticketID, err: "worktree failed: " + err.Error(), background: background}
 				}
 				worktreePath = path
+				if setup != nil {
+					if err := prepareWorktree(proj, path, generatedBranch, ticketID, setup); err != nil {
+						return spawnErrorMsg{ticketID: ticketID, err: err.Error(), background: background}
+					}
+				}
 			}
 		} else {
+			var err error
+			if base, err = localBase(mgr, base, baseBranch); err != nil {
+				return spawnErrorMsg{ticketID: ticketID, err: err.Error(), background: background}
+			}
 			if err := mgr.SetupBranch(generatedBranch, base); err != nil {
-				return spawnErrorMsg{ticketID: ticketID, err: "branch setup failed: " + err.Error()}
+				return spawnErrorMsg{ticketID: ticketID, err: "branch setup failed: " + err.Error(), background: background}
 			}
 			worktreePath = proj.RepoPath
 		}
@@ -2577,6 +3277,7 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 
 		pane := terminal.New(string(ticketID), width, height, 0)
 		pane.SetWorkdir(worktreePath)
+		pane.SetHighlights(highlights)
 
 		// Set session name for terminal identification (priority: AgentSessionID > branch > ticket)
 		sessionName := string(ticketID)
@@ -2587,6 +3288,10 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 			sessionName = ticket.AgentSessionID
 		}
 		pane.SetSessionName(sessionName)
+		pane.SetEnv(agentEnv)
+		if cfg.Tmux.Enabled {
+			pane.SetTmux(tmuxSession, tmuxWindow(ticket))
+		}
 
 		// Clean up any stale status file from previous sessions that may not have
 		// been properly cleaned up (e.g., if the app was closed while an agent was running)
@@ -2596,16 +3301,77 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 		args := make([]string, len(agentCfg.Args))
 		copy(args, agentCfg.Args)
 
-		promptTemplate := cfg.GetEffectiveInitPrompt(agentType)
+		var prompt string
+		if isNewSession {
+			prompt = reviewedPrompt
+			if prompt == "" {
+				prompt = agent.BuildContextPrompt(cfg.GetEffectiveInitPrompt(agentType), ticket)
+				prompt = iteratePrompt(prompt, feedback, worktreePath, baseBranch)
+			}
+		}
+
+		// Agents that take their prompt typed in get it once they are
+		// ready, after they start
+		var typedPrompt string
+		if agentCfg.ContextInput == config.ContextInputType {
+			typedPrompt, prompt = prompt, ""
+		}
+
+		// Agents configured with a context file read the prompt from the
+		// worktree, through their own flag or as asked to by a short prompt
+		var contextArgs []string
+		if prompt != "" && agentCfg.ContextFile != "" {
+			path, err := writeContextFile(worktreePath, agentCfg.ContextFile, prompt)
+			if err != nil {
+				return spawnErrorMsg{ticketID: ticketID, err: err.Error(), background: background}
+			}
+			prompt = agent.ContextFilePrompt(agentCfg.ContextFile)
+			if len(agentCfg.ContextArgs) > 0 {
+				prompt = ""
+				contextArgs = agent.ContextFileArgs(agentCfg.ContextArgs, path)
+			}
+		}
+		args = append(args, contextArgs...)
+
+		templates := agentCfg.SpawnArgs
+		if !isNewSession {
+			templates = agentCfg.ResumeArgs
+		}
+		if len(templates) > 0 {
+			vars := map[string]string{
+				"prompt":   prompt,
+				"worktree": worktreePath,
+				"model":    agentCfg.Model,
+				"branch":   branchName,
+				"ticket":   ticketKey,
+			}
+			if agentPort > 0 {
+				vars["port"] = fmt.Sprintf("%d", agentPort)
+			}
+			return spawnReadyMsg{
+				ticketID:     ticketID,
+				pane:         pane,
+				command:      agentCfg.Command,
+				args:         append(args, agent.ExpandArgs(templates, vars)...),
+				worktreePath: worktreePath,
+				branchName:   branchName,
+				baseBranch:   baseBranch,
+				agentName:    agentName,
+				background:   background,
+				typedPrompt:  typedPrompt,
+			}
+		}
+
+		modelArgs := agent.ExpandArgs(agentCfg.ModelArgs, map[string]string{"model": agentCfg.Model})
+		args = append(args, modelArgs...)
 
 		switch agentType {
 		case "claude":
-			if isNewSession && promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
+			if isNewSession {
 				if prompt != "" {
 					args = append(args, prompt)
 				}
-			} else if !isNewSession {
+			} else {
 				hasFlag := false
 				for _, arg := range args {
 					if arg == "--continue" || arg == "-c" {
@@ -2621,13 +3387,11 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 			command := agentCfg.Command
 			sessionID := agent.FindOpencodeSession(worktreePath)
 
-			args = []string{worktreePath, "--port", fmt.Sprintf("%d", agentPort)}
+			args = append([]string{worktreePath, "--port", fmt.Sprintf("%d", agentPort)}, contextArgs...)
+			args = append(args, modelArgs...)
 			if isNewSession {
-				if promptTemplate != "" {
-					prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-					if prompt != "" {
-						args = append(args, "--prompt", prompt)
-					}
+				if prompt != "" {
+					args = append(args, "--prompt", prompt)
 				}
 			} else if sessionID != "" {
 				args = append(args, "--session", sessionID)
@@ -2642,6 +3406,9 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 				worktreePath: worktreePath,
 				branchName:   branchName,
 				baseBranch:   baseBranch,
+				agentName:    agentName,
+				background:   background,
+				typedPrompt:  typedPrompt,
 			}
 		case "gemini":
 			command := agentCfg.Command
@@ -2650,11 +3417,8 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 				if sessionID != "" {
 					args = append(args, "--resume")
 				}
-			} else if promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-				if prompt != "" {
-					args = append(args, "-i", prompt)
-				}
+			} else if prompt != "" {
+				args = append(args, "-i", prompt)
 			}
 			return spawnReadyMsg{
 				ticketID:     ticketID,
@@ -2664,6 +3428,9 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 				worktreePath: worktreePath,
 				branchName:   branchName,
 				baseBranch:   baseBranch,
+				agentName:    agentName,
+				background:   background,
+				typedPrompt:  typedPrompt,
 			}
 		case "codex":
 			command := agentCfg.Command
@@ -2676,12 +3443,10 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 						args = []string{"resume", sessionID}
 					}
 					args = append(args, agentCfg.Args...)
+					args = append(args, modelArgs...)
 				}
-			} else if promptTemplate != "" {
-				prompt := agent.BuildContextPrompt(promptTemplate, ticket)
-				if prompt != "" {
-					args = append(args, prompt)
-				}
+			} else if prompt != "" {
+				args = append(args, prompt)
 			}
 			return spawnReadyMsg{
 				ticketID:     ticketID,
@@ -2691,6 +3456,9 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 				worktreePath: worktreePath,
 				branchName:   branchName,
 				baseBranch:   baseBranch,
+				agentName:    agentName,
+				background:   background,
+				typedPrompt:  typedPrompt,
 			}
 		}
 
@@ -2702,29 +3470,74 @@ func (m *Model) prepareSpawn(ticket *board.Ticket, proj *project.Project, agentC
 			worktreePath: worktreePath,
 			branchName:   branchName,
 			baseBranch:   baseBranch,
+			agentName:    agentName,
+			background:   background,
+			typedPrompt:  typedPrompt,
+		}
+	}
+	if box == nil {
+		return spawn
+	}
+
+	return func() tea.Msg {
+		msg := spawn()
+		ready, ok := msg.(spawnReadyMsg)
+		if !ok {
+			return msg
+		}
+		gitDir, _ := git.CommonDir(ready.worktreePath)
+		command, args, err := box.Wrap(sandbox.Spec{
+			Command: ready.command,
+			Args:    ready.args,
+			Workdir: ready.worktreePath,
+			GitDir:  gitDir,
+			Env:     agentEnv,
+		})
+		if err != nil {
+			return spawnErrorMsg{ticketID: ticketID, err: "sandbox: " + err.Error(), background: background}
 		}
+		ready.command, ready.args = command, args
+		return ready
 	}
 }
 
 func (m *Model) stopAgent() (tea.Model, tea.Cmd) {
 	ticket := m.selectedTicket()
-	if ticket == nil {
+	if ticket == nil || m.readOnlyBlocked("stop agents") {
+		return m, nil
+	}
+	_, running := m.panes[ticket.ID]
+	if !running && m.cancelGitOp(ticket.ID) {
+		m.notify("Cancelling worktree for " + truncate(ticket.Title, 30))
 		return m, nil
 	}
 
+	return m, m.askConfirm(running && m.config.Behavior.Confirm.StopAgent, "Stop the agent for "+ticket.Title+"?", func() tea.Cmd {
+		m.stopTicketAgent(ticket)
+		return nil
+	})
+}
+
+// stopTicketAgent ends the ticket's agent and restores any changes stashed
+// when it was spawned
+func (m *Model) stopTicketAgent(ticket *board.Ticket) {
 	if pane, ok := m.panes[ticket.ID]; ok {
 		pane.Stop()
 		delete(m.panes, ticket.ID)
 	}
 
-	ticket.AgentStatus = board.AgentNone
+	m.setAgentStatus(ticket, board.AgentNone)
+	m.endAgentRun(ticket, board.OutcomeStopped)
 	m.saveTicket(ticket)
 	m.notify("Agent stopped")
-	return m, nil
+	if m.restoreStash(ticket) {
+		m.notify("Agent stopped; restored stashed changes")
+	}
 }
 
 func (m *Model) selectedTicket() *board.Ticket {
-	if len(m.columnTickets) <= m.activeColumn {
+	// A collapsed column's cards are hidden, so none of them is selected
+	if len(m.columnTickets) <= m.activeColumn || m.isCollapsed(m.activeColumn) {
 		return nil
 	}
 	tickets := m.columnTickets[m.activeColumn]
@@ -2747,17 +3560,42 @@ func (m *Model) selectTicketByID(ticketID board.TicketID) {
 	}
 }
 
+// FocusTicket selects the given ticket, switching the project filter to the
+// ticket's project if it would otherwise be hidden.
+func (m *Model) FocusTicket(ticketID board.TicketID) bool {
+	ticket, err := m.globalStore.Get(ticketID)
+	if err != nil {
+		return false
+	}
+
+	if !m.ticketMatchesFilter(ticket) {
+		m.filterProjectIDs = map[string]bool{ticket.ProjectID: true}
+		m.filterQuery = ""
+		m.refreshColumnTickets()
+	}
+
+	m.selectTicketByID(ticketID)
+	m.ensureColumnVisible()
+	return true
+}
+
 func (m *Model) refreshColumnTickets() {
 	m.columnTickets = make([][]*board.Ticket, len(m.columns))
 	for i, col := range m.columns {
 		allForStatus := m.globalStore.GetByStatus(col.Status)
 		var filtered []*board.Ticket
 		for _, t := range allForStatus {
-			if !m.ticketMatchesFilter(t) {
+			// Focus mode spans every project, whichever tab is open
+			if m.focusMode && !m.ticketMatchesQuery(t) || !m.focusMode && !m.ticketMatchesFilter(t) {
 				continue
 			}
 			filtered = append(filtered, t)
 		}
+		if m.focusMode {
+			m.sortByActivity(filtered)
+		} else if m.config.UI.GroupByProject && len(m.filterProjectIDs) != 1 {
+			m.groupByProject(filtered)
+		}
 		m.columnTickets[i] = filtered
 	}
 
@@ -2770,28 +3608,40 @@ func (m *Model) ticketMatchesFilter(t *board.Ticket) bool {
 	if len(m.filterProjectIDs) > 0 && !m.filterProjectIDs[t.ProjectID] {
 		return false
 	}
+	return m.ticketMatchesQuery(t)
+}
+
+// ticketMatchesQuery checks a ticket against the filter expression alone
+func (m *Model) ticketMatchesQuery(t *board.Ticket) bool {
 	if m.filterQuery == "" {
 		return true
 	}
+	q, err := m.boardQuery()
+	if err != nil {
+		// Half typed, or not meant as an expression: search the text as is
+		query := strings.ToLower(m.filterQuery)
+		return strings.Contains(strings.ToLower(t.Title), query) ||
+			strings.Contains(strings.ToLower(t.Description), query)
+	}
 
-	query := strings.ToLower(m.filterQuery)
-
-	if strings.HasPrefix(query, "@") {
-		parts := strings.SplitN(query, " ", 2)
-		projectName := strings.TrimPrefix(parts[0], "@")
-		proj := m.globalStore.GetProjectForTicket(t)
-		if proj == nil || !strings.Contains(strings.ToLower(proj.Name), projectName) {
-			return false
-		}
-		if len(parts) == 1 {
-			return true
-		}
-		query = strings.TrimSpace(parts[1])
+	ctx := project.QueryContext{}
+	if proj := m.globalStore.GetProjectForTicket(t); proj != nil {
+		ctx.ProjectName = proj.Name
 	}
+	if pane, ok := m.panes[t.ID]; ok && pane.Running() {
+		ctx.Running = true
+	}
+	return q.Matches(t, ctx)
+}
 
-	title := strings.ToLower(t.Title)
-	desc := strings.ToLower(t.Description)
-	return strings.Contains(title, query) || strings.Contains(desc, query)
+// boardQuery parses the filter expression, reusing the last parse while the
+// text is unchanged
+func (m *Model) boardQuery() (*project.Query, error) {
+	if m.parsedFilter != m.filterQuery || (m.filterExpr == nil && m.filterErr == nil) {
+		m.parsedFilter = m.filterQuery
+		m.filterExpr, m.filterErr = project.ParseQuery(m.filterQuery)
+	}
+	return m.filterExpr, m.filterErr
 }
 
 func (m *Model) nextStatus(current board.TicketStatus) board.TicketStatus {
@@ -2799,6 +3649,11 @@ func (m *Model) nextStatus(current board.TicketStatus) board.TicketStatus {
 	case board.StatusBacklog:
 		return board.StatusInProgress
 	case board.StatusInProgress:
+		if m.hasReviewColumn() {
+			return board.StatusReview
+		}
+		return board.StatusDone
+	case board.StatusReview:
 		return board.StatusDone
 	default:
 		return current
@@ -2808,6 +3663,11 @@ func (m *Model) nextStatus(current board.TicketStatus) board.TicketStatus {
 func (m *Model) previousStatus(current board.TicketStatus) board.TicketStatus {
 	switch current {
 	case board.StatusDone:
+		if m.hasReviewColumn() {
+			return board.StatusReview
+		}
+		return board.StatusInProgress
+	case board.StatusReview:
 		return board.StatusInProgress
 	case board.StatusInProgress:
 		return board.StatusBacklog
@@ -2816,21 +3676,23 @@ func (m *Model) previousStatus(current board.TicketStatus) board.TicketStatus {
 	}
 }
 
-func (m *Model) notify(msg string) {
-	m.notification = msg
-	m.notifyTime = time.Now()
-}
-
+// saveTicket persists a change to ticket, right away or with the next
+// autosave
 func (m *Model) saveTicket(ticket *board.Ticket) {
-	if err := m.globalStore.Save(ticket); err != nil {
+	if m.autosaveEnabled() {
+		m.globalStore.MarkDirty(ticket)
+	} else if err := m.globalStore.Save(ticket); err != nil {
 		m.notify("Failed to save: " + err.Error())
+		return
 	}
+	m.publish(m.newEvent(events.TicketChanged, ticket))
 }
 
 func (m *Model) resetSpawnState(ticketID board.TicketID) {
 	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
 		ticket.AgentSpawnedAt = nil
-		ticket.AgentStatus = board.AgentNone
+		m.setAgentStatus(ticket, board.AgentNone)
+		m.endAgentRun(ticket, board.OutcomeFailed)
 		m.saveTicket(ticket)
 	}
 	m.mode = ModeNormal
@@ -2861,12 +3723,28 @@ func (m *Model) getAgentNames() []string {
 	return names
 }
 
-func (m *Model) getDefaultAgent() string {
+// getDefaultAgent returns the agent new tickets in proj get: the project's
+// default_agent when it is configured, else the global default
+func (m *Model) getDefaultAgent(proj *project.Project) string {
+	if proj != nil && proj.Settings.DefaultAgent != "" && !m.config.IsEnvOverride("defaults.default_agent") {
+		if _, ok := m.config.Agents[proj.Settings.DefaultAgent]; ok {
+			return proj.Settings.DefaultAgent
+		}
+	}
 	return m.config.Defaults.DefaultAgent
 }
 
+// agentNameFor returns the agent the ticket runs: its own, or its project's
+// default
+func (m *Model) agentNameFor(ticket *board.Ticket) string {
+	if ticket.AgentType != "" {
+		return ticket.AgentType
+	}
+	return m.getDefaultAgent(m.globalStore.GetProjectForTicket(ticket))
+}
+
 func (m *Model) getBranchPrefix(proj *project.Project) string {
-	if proj != nil && proj.Settings.BranchPrefix != "" {
+	if proj != nil && proj.Settings.BranchPrefix != "" && !m.config.IsEnvOverride("defaults.branch_prefix") {
 		return proj.Settings.BranchPrefix
 	}
 	if m.config.Defaults.BranchPrefix != "" {
@@ -2876,7 +3754,7 @@ func (m *Model) getBranchPrefix(proj *project.Project) string {
 }
 
 func (m *Model) getBranchTemplate(proj *project.Project) string {
-	if proj != nil && proj.Settings.BranchTemplate != "" {
+	if proj != nil && proj.Settings.BranchTemplate != "" && !m.config.IsEnvOverride("defaults.branch_template") {
 		return proj.Settings.BranchTemplate
 	}
 	if m.config.Defaults.BranchTemplate != "" {
@@ -2886,7 +3764,7 @@ func (m *Model) getBranchTemplate(proj *project.Project) string {
 }
 
 func (m *Model) getSlugMaxLength(proj *project.Project) int {
-	if proj != nil && proj.Settings.SlugMaxLength > 0 {
+	if proj != nil && proj.Settings.SlugMaxLength > 0 && !m.config.IsEnvOverride("defaults.slug_max_length") {
 		return proj.Settings.SlugMaxLength
 	}
 	if m.config.Defaults.SlugMaxLength > 0 {
@@ -2908,8 +3786,9 @@ func (m *Model) getAgentIndex(agentName string) int {
 const gracefulShutdownTimeout = 3 * time.Second
 
 func (m *Model) Cleanup() {
+	m.closeFileWatchers()
 	for _, pane := range m.panes {
-		if pane.Running() {
+		if pane.Running() && pane.Tmux() == nil {
 			pane.StopGraceful(gracefulShutdownTimeout)
 		}
 	}
@@ -2984,6 +3863,73 @@ func (m *Model) pollAgentStatusesAsync() tea.Cmd {
 	}
 }
 
+// startSpawnedPane records the spawned agent on its ticket and starts the pane.
+func (m *Model) startSpawnedPane(msg spawnReadyMsg) tea.Cmd {
+	var link tea.Cmd
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket != nil {
+		ticket.AgentType = msg.agentName
+		m.setAgentStatus(ticket, board.AgentNone)
+		if ticket.AgentSpawnedAt == nil {
+			now := time.Now()
+			ticket.AgentSpawnedAt = &now
+		}
+		ticket.StartAgentRun(msg.agentName)
+		ticket.Feedback = ""
+		ticket.Tests = nil
+		if msg.worktreePath != "" && ticket.WorktreePath == "" {
+			ticket.WorktreePath = msg.worktreePath
+			ticket.BranchName = msg.branchName
+			ticket.BaseBranch = msg.baseBranch
+		}
+		m.saveTicket(ticket)
+		link = m.linkLinearBranch(ticket)
+	}
+
+	m.panes[msg.ticketID] = msg.pane
+	return tea.Batch(msg.pane.Start(msg.command, msg.args...), link, m.awaitReady(msg.ticketID, msg.typedPrompt))
+}
+
+func (m *Model) handleAgentExit(msg terminal.ExitMsg) (tea.Model, tea.Cmd) {
+	ticketID := board.TicketID(msg.PaneID)
+	pane, tracked := m.panes[ticketID]
+	if !tracked {
+		// Stopped from the board, which already wrapped up the run
+		return m, nil
+	}
+	m.keepTranscript(ticketID, pane)
+	delete(m.panes, ticketID)
+	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+		alreadyCompleted := ticket.AgentStatus == board.AgentCompleted
+		m.setAgentStatus(ticket, board.AgentNone)
+		if msg.Err != nil {
+			m.endAgentRun(ticket, board.OutcomeFailed)
+			m.publishAgentFailed(ticket, msg.Err.Error())
+		} else {
+			m.endAgentRun(ticket, board.OutcomeCompleted)
+			if !alreadyCompleted {
+				m.publish(m.newEvent(events.AgentCompleted, ticket))
+			}
+		}
+		m.saveTicket(ticket)
+	}
+	if m.focusedPane == ticketID {
+		m.mode = ModeNormal
+		m.focusedPane = ""
+		m.notify("Agent exited")
+	} else if ticket, _ := m.globalStore.Get(ticketID); ticket != nil {
+		if msg.Err != nil {
+			m.notify(fmt.Sprintf("Agent for %q failed: %v", ticket.Title, msg.Err))
+		} else {
+			m.notify(fmt.Sprintf("Agent for %q exited", ticket.Title))
+		}
+	}
+	if ticket, _ := m.globalStore.Get(ticketID); ticket != nil && m.restoreStash(ticket) {
+		m.notify("Agent exited; restored stashed changes")
+	}
+	return m, nil
+}
+
 func (m *Model) handleTerminalMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	for _, pane := range m.panes {
@@ -3008,11 +3954,15 @@ type spawnReadyMsg struct {
 	worktreePath string
 	branchName   string
 	baseBranch   string
+	agentName    string
+	background   bool
+	typedPrompt  string // init prompt to type in once the agent is ready
 }
 
 type spawnErrorMsg struct {
-	ticketID board.TicketID
-	err      string
+	ticketID   board.TicketID
+	err        string
+	background bool
 }
 
 func tickAgentStatus(d time.Duration) tea.Cmd {
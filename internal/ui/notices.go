@@ -0,0 +1,375 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// noticeHistoryLimit caps the notification history kept for the panel
+	noticeHistoryLimit = 200
+	// toastDuration is how long a toast stays up; errors stay longer
+	toastDuration      = 4 * time.Second
+	errorToastDuration = 10 * time.Second
+	maxToasts          = 3
+	toastTickInterval  = 500 * time.Millisecond
+)
+
+// notice is a status message, kept for the notification history
+type notice struct {
+	text    string
+	at      time.Time
+	isError bool
+	quiet   bool // only recorded in the history, never shown as a toast
+}
+
+func (n notice) expired(now time.Time) bool {
+	if n.quiet {
+		return true
+	}
+	d := toastDuration
+	if n.isError {
+		d = errorToastDuration
+	}
+	return now.Sub(n.at) > d
+}
+
+// noticesView is the notification history panel. With errorsOnly set it
+// is the error console.
+type noticesView struct {
+	index      int
+	offset     int
+	errorsOnly bool
+}
+
+func tickNotifications(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return notificationMsg(t)
+	})
+}
+
+// notify shows msg as a toast and records it in the history. Errors are
+// also sent to the desktop when configured.
+func (m *Model) notify(msg string) {
+	m.addNotice(notice{text: msg, at: time.Now(), isError: isErrorNotification(msg)})
+}
+
+// notifyError is notify for messages that are errors whatever their wording
+func (m *Model) notifyError(msg string) {
+	m.addNotice(notice{text: msg, at: time.Now(), isError: true})
+}
+
+// logError records an error in the error console without a toast, for
+// failures nobody is waiting on, such as requests from the CLI
+func (m *Model) logError(msg string) {
+	m.addNotice(notice{text: msg, at: time.Now(), isError: true, quiet: true})
+}
+
+func (m *Model) addNotice(n notice) {
+	if n.isError {
+		slog.Error(n.text)
+	} else {
+		slog.Debug(n.text)
+	}
+	if !n.quiet {
+		m.notification = n.text
+		m.notifyTime = n.at
+	}
+	m.notices = append(m.notices, n)
+	if len(m.notices) > noticeHistoryLimit {
+		m.trimNotices()
+	}
+	if n.isError {
+		m.unreadErrors++
+		if !n.quiet {
+			m.notifyDesktopError(n.text)
+		}
+	}
+}
+
+// trimNotices drops the oldest notice over the history limit, preferring
+// one that isn't an error so routine messages don't push errors out
+func (m *Model) trimNotices() {
+	drop := 0
+	for i, n := range m.notices {
+		if !n.isError {
+			drop = i
+			break
+		}
+	}
+	m.notices = append(m.notices[:drop], m.notices[drop+1:]...)
+}
+
+// isErrorNotification reports whether a status bar message is an error
+func isErrorNotification(msg string) bool {
+	return strings.HasPrefix(msg, "Failed") ||
+		strings.HasPrefix(msg, "Error") ||
+		strings.Contains(msg, "failed")
+}
+
+// handleNotificationTick clears the latest message once its toast is gone
+func (m *Model) handleNotificationTick() (tea.Model, tea.Cmd) {
+	if m.notification != "" {
+		for i := len(m.notices) - 1; i >= 0; i-- {
+			if !m.notices[i].quiet {
+				if m.notices[i].expired(time.Now()) {
+					m.notification = ""
+				}
+				break
+			}
+		}
+	}
+	return m, tickNotifications(toastTickInterval)
+}
+
+// toasts returns the notices still on screen, oldest first
+func (m *Model) toasts() []notice {
+	now := time.Now()
+	var active []notice
+	for i := len(m.notices) - 1; i >= 0 && len(active) < maxToasts; i-- {
+		n := m.notices[i]
+		if n.expired(now) {
+			// Errors outlive newer messages, so keep looking past expired ones
+			if now.Sub(n.at) > errorToastDuration {
+				break
+			}
+			continue
+		}
+		active = append([]notice{n}, active...)
+	}
+	return active
+}
+
+func (m *Model) renderToasts() string {
+	toasts := m.toasts()
+	if len(toasts) == 0 {
+		return ""
+	}
+	maxWidth := max(min(70, m.width/2), 20)
+	lines := make([]string, len(toasts))
+	for i, n := range toasts {
+		bg, icon := m.colors.success, "✓"
+		if n.isError {
+			bg, icon = m.colors.err, "✗"
+		}
+		lines[i] = lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(bg).
+			Padding(0, 1).
+			Render(truncate(icon+" "+n.text, maxWidth-2))
+	}
+	// Lines are right-aligned one by one when drawn, so none is padded
+	return strings.Join(lines, "\n")
+}
+
+// withToasts draws the toasts over the bottom-right corner of view, above
+// the status bar line
+func (m *Model) withToasts(view string) string {
+	box := m.renderToasts()
+	if box == "" {
+		return view
+	}
+	return overlayRight(view, box, m.width, len(strings.Split(view, "\n"))-1-len(strings.Split(box, "\n")))
+}
+
+// overlayRight writes box over the right end of view's lines, from line
+// start down
+func overlayRight(view, box string, width, start int) string {
+	lines := strings.Split(view, "\n")
+	boxLines := strings.Split(box, "\n")
+	for i, boxLine := range boxLines {
+		row := start + i
+		if row < 0 || row >= len(lines) {
+			continue
+		}
+		boxWidth := ansi.StringWidth(boxLine)
+		x := max(width-boxWidth, 0)
+		line := lines[row]
+		left := ansi.Truncate(line, x, "")
+		pad := strings.Repeat(" ", max(x-ansi.StringWidth(left), 0))
+		right := ansi.TruncateLeft(line, x+boxWidth, "")
+		lines[row] = left + "\x1b[0m" + pad + boxLine + right
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) openNotices(errorsOnly bool) (tea.Model, tea.Cmd) {
+	m.noticesPanel = &noticesView{errorsOnly: errorsOnly}
+	m.unreadErrors = 0
+	m.mode = ModeNotices
+	return m, nil
+}
+
+// visibleNotices returns the notices the panel lists, newest first
+func (m *Model) visibleNotices() []notice {
+	var shown []notice
+	for i := len(m.notices) - 1; i >= 0; i-- {
+		if n := m.notices[i]; n.isError || !m.noticesPanel.errorsOnly {
+			shown = append(shown, n)
+		}
+	}
+	return shown
+}
+
+func (m *Model) handleNoticesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.noticesPanel
+	height := m.noticesListHeight()
+	count := len(m.visibleNotices())
+	switch msg.String() {
+	case "esc", "q":
+		m.noticesPanel = nil
+		m.mode = ModeNormal
+	case "j", "down":
+		v.selectItem(v.index+1, count, height)
+	case "k", "up":
+		v.selectItem(v.index-1, count, height)
+	case "g":
+		v.selectItem(0, count, height)
+	case "G":
+		v.selectItem(count-1, count, height)
+	case "e":
+		*v = noticesView{errorsOnly: !v.errorsOnly}
+	case "y":
+		if n, ok := m.selectedNotice(); ok {
+			m.copyNotices("Copied message", n.text)
+		}
+	case "Y":
+		shown := m.visibleNotices()
+		lines := make([]string, len(shown))
+		// Oldest first, as in a log file
+		for i, n := range shown {
+			lines[len(shown)-1-i] = formatNotice(n)
+		}
+		if len(lines) > 0 {
+			m.copyNotices(fmt.Sprintf("Copied %d messages", len(lines)), strings.Join(lines, "\n"))
+		}
+	case "c":
+		m.notices = nil
+		m.notification = ""
+		*v = noticesView{errorsOnly: v.errorsOnly}
+	}
+	return m, nil
+}
+
+func (m *Model) copyNotices(done, text string) {
+	if err := copyToClipboard(text); err != nil {
+		m.notify("Failed to copy: " + err.Error())
+	} else {
+		m.notify(done)
+	}
+}
+
+// formatNotice writes a notice as a timestamped log line
+func formatNotice(n notice) string {
+	level := "INFO "
+	if n.isError {
+		level = "ERROR"
+	}
+	return n.at.Format("2006-01-02 15:04:05") + " " + level + " " + n.text
+}
+
+func (v *noticesView) selectItem(i, count, height int) {
+	v.index = max(min(i, count-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+func (m *Model) selectedNotice() (notice, bool) {
+	shown := m.visibleNotices()
+	if m.noticesPanel.index >= len(shown) {
+		return notice{}, false
+	}
+	return shown[m.noticesPanel.index], true
+}
+
+// noticeDetailLines is how much of the selected message is shown in full
+const noticeDetailLines = 4
+
+// noticesListHeight leaves room below the list for the selected message
+func (m *Model) noticesListHeight() int {
+	return max(m.height-16-noticeDetailLines, 3)
+}
+
+func (m *Model) renderNoticesView() string {
+	v := m.noticesPanel
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(110, m.width-4)
+	inner := width - 6
+	shown := m.visibleNotices()
+
+	title := fmt.Sprintf("Notifications (%d)", len(shown))
+	empty := "  Nothing yet"
+	if v.errorsOnly {
+		title = fmt.Sprintf("Errors (%d)", len(shown))
+		empty = "  No errors"
+	}
+	lines := []string{titleStyle.Render(title), ""}
+	if len(shown) == 0 {
+		lines = append(lines, m.dimStyle().Render(empty))
+	}
+
+	height := m.noticesListHeight()
+	end := min(v.offset+height, len(shown))
+	for i := v.offset; i < end; i++ {
+		n := shown[i]
+		cursor := "  "
+		textStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+		if i == v.index {
+			cursor = "▸ "
+			textStyle = textStyle.Bold(true)
+		}
+		icon := lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ ")
+		if n.isError {
+			icon = lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ ")
+			textStyle = textStyle.Foreground(m.colors.err)
+		}
+		stamp := m.dimStyle().Render(n.at.Format("15:04:05") + "  ")
+		row := cursor + stamp + icon
+		text := strings.ReplaceAll(n.text, "\n", " ")
+		lines = append(lines, row+textStyle.Render(truncate(text, max(inner-lipgloss.Width(row), 10))))
+	}
+	if len(shown) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(shown)-end)))
+	}
+
+	// Long messages, such as git's output, are cut off in the list
+	if n, ok := m.selectedNotice(); ok {
+		wrapped := strings.Split(ansi.Wrap(n.text, inner-2, ""), "\n")
+		if len(wrapped) > noticeDetailLines {
+			wrapped = wrapped[:noticeDetailLines]
+			wrapped[noticeDetailLines-1] = truncate(wrapped[noticeDetailLines-1]+"…", inner-2)
+		}
+		lines = append(lines, "", m.dimStyle().Render(n.at.Format("2006-01-02 15:04:05")))
+		for _, l := range wrapped {
+			lines = append(lines, "  "+l)
+		}
+	}
+
+	filter := " Errors only  "
+	if v.errorsOnly {
+		filter = " Show all  "
+	}
+	lines = append(lines, "",
+		keyStyle.Render("[y]")+m.dimStyle().Render(" Copy  ")+
+			keyStyle.Render("[Y]")+m.dimStyle().Render(" Copy all  ")+
+			keyStyle.Render("[e]")+m.dimStyle().Render(filter)+
+			keyStyle.Render("[c]")+m.dimStyle().Render(" Clear  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
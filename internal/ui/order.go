@@ -0,0 +1,56 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// applyOrder hands the configured orders to the store, which lists
+// tickets and projects in them
+func (m *Model) applyOrder() {
+	m.globalStore.SetOrder(project.Order(m.config.UI.TicketOrder), project.Order(m.config.UI.ProjectOrder))
+}
+
+// reorderTicket moves the selected ticket up (delta -1) or down its column.
+// Arranging by hand switches the board to the manual order, starting from
+// the order the column is shown in so nothing else moves.
+func (m *Model) reorderTicket(delta int) (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("reorder tickets") {
+		return m, nil
+	}
+	if m.focusMode {
+		m.notify("Focus mode orders tickets by activity")
+		return m, nil
+	}
+	tickets := m.columnTickets[m.activeColumn]
+	from, to := m.activeTicket, m.activeTicket+delta
+	if to < 0 || to >= len(tickets) {
+		return m, nil
+	}
+
+	if m.globalStore.TicketOrder() != project.OrderManual {
+		err := m.updateSetting("ui.ticket_order", func(c *config.Config) error {
+			c.UI.TicketOrder = string(project.OrderManual)
+			return nil
+		})
+		if err != nil {
+			m.notify("Failed to switch to manual order: " + err.Error())
+			return m, nil
+		}
+		m.notify("Tickets are now ordered by hand")
+	}
+
+	tickets[from], tickets[to] = tickets[to], tickets[from]
+	for i, t := range tickets {
+		if t.Position != i+1 {
+			t.Position = i + 1
+			m.saveTicket(t)
+		}
+	}
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	return m, nil
+}
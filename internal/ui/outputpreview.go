@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+const (
+	// previewLines is how much of an agent's output the preview shows
+	previewLines = 15
+	// previewMinScreenWidth keeps the preview from squeezing the board on
+	// narrow terminals
+	previewMinScreenWidth = 100
+)
+
+// previewWidth is the width of the output preview beside the board, or 0
+// when it isn't shown. The space is kept while any agent runs, so columns
+// don't reflow as the cursor moves between cards.
+func (m *Model) previewWidth() int {
+	if !(m.outputPreview || m.fileFeed) || m.splitView || m.width < previewMinScreenWidth || m.RunningAgentCount() == 0 {
+		return 0
+	}
+	return min(m.width*35/100, 70)
+}
+
+func (m *Model) toggleOutputPreview() {
+	m.outputPreview = !m.outputPreview
+	m.ensureColumnVisible()
+	if m.outputPreview {
+		m.notify("Output preview on")
+	} else {
+		m.notify("Output preview off")
+	}
+}
+
+// previewOutput returns the last lines of a terminal screen with trailing
+// spaces and blank lines dropped
+func previewOutput(content string, lines int) []string {
+	rows := strings.Split(content, "\n")
+	for i, row := range rows {
+		rows[i] = strings.TrimRight(row, " ")
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) > lines {
+		rows = rows[len(rows)-lines:]
+	}
+	return rows
+}
+
+// renderSidePanels stacks the output preview over the file feed, or shows
+// whichever is on
+func (m *Model) renderSidePanels(width, height int) string {
+	switch {
+	case m.outputPreview && m.fileFeed:
+		top := height / 2
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderOutputPreview(width, top), m.renderFileFeed(width, height-top))
+	case m.fileFeed:
+		return m.renderFileFeed(width, height)
+	}
+	return m.renderOutputPreview(width, height)
+}
+
+// renderOutputPreview shows the end of the selected card's agent output,
+// read from its terminal without focusing it
+func (m *Model) renderOutputPreview(width, height int) string {
+	inner := max(width-3, 1)
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+
+	var title, hint string
+	var body []string
+	ticket := m.selectedTicket()
+	pane, ok := m.panes[m.splitPaneID()]
+	switch {
+	case ok && ticket != nil:
+		title = "▶ " + ticket.Title
+		hint = keyStyle.Render(m.keyHint(keymap.AttachAgent)) + m.dimStyle().Render(" attach")
+		for _, line := range previewOutput(pane.GetContent(), min(previewLines, max(height-3, 1))) {
+			body = append(body, truncate(line, inner))
+		}
+		if len(body) == 0 {
+			body = []string{m.dimStyle().Render("No output yet")}
+		}
+	default:
+		title = "Output preview"
+		body = []string{m.dimStyle().Render("Select a card with a running agent")}
+	}
+
+	title = titleStyle.Render(truncate(title, max(inner-lipgloss.Width(hint)-1, 1)))
+	spacing := max(inner-lipgloss.Width(title)-lipgloss.Width(hint), 1)
+	header := title + strings.Repeat(" ", spacing) + hint
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(m.colors.surface).
+		PaddingLeft(1).
+		Width(width - 1).
+		Height(height).
+		MaxHeight(height).
+		Render(header + "\n\n" + strings.Join(body, "\n"))
+}
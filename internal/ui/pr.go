@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+type prCreatedMsg struct {
+	ticketID board.TicketID
+	url      string
+	err      error
+}
+
+func (m *Model) confirmCreatePR() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("open pull requests") {
+		return m, nil
+	}
+	if ticket.BranchName == "" || ticket.WorktreePath == "" {
+		m.notify("Ticket has no branch to open a pull request from")
+		return m, nil
+	}
+	if ticket.PRURL != "" {
+		m.notify("Pull request already open: " + ticket.PRURL)
+		return m, nil
+	}
+
+	base := ticket.BaseBranch
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if base == "" && mgr != nil {
+		base, _ = mgr.GetDefaultBranch()
+	}
+
+	msg := fmt.Sprintf("Push %s and open a pull request into %s?", ticket.BranchName, base)
+	if mgr != nil {
+		if dirty, _ := mgr.HasUncommittedChanges(ticket.WorktreePath); dirty {
+			msg += " Uncommitted changes are not included."
+		}
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = msg
+	m.confirmFn = func() tea.Cmd {
+		m.notify("Opening pull request for " + ticket.BranchName + "...")
+		return createPR(m.pullRequestSettings(ticket), ticket.Clone(), base)
+	}
+	return m, nil
+}
+
+// pullRequestSettings returns the pull_request settings with the ticket's
+// project provider override applied
+func (m *Model) pullRequestSettings(ticket *board.Ticket) config.PullRequestSettings {
+	settings := m.config.PullRequest
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil && proj.Settings.Provider != "" {
+		settings.Provider = proj.Settings.Provider
+	}
+	return settings
+}
+
+// createPR pushes the ticket's branch and opens a pull request for it
+func createPR(settings config.PullRequestSettings, ticket *board.Ticket, base string) tea.Cmd {
+	return func() tea.Msg {
+		url, err := openPullRequest(settings, ticket, base)
+		return prCreatedMsg{ticketID: ticket.ID, url: url, err: err}
+	}
+}
+
+func openPullRequest(settings config.PullRequestSettings, ticket *board.Ticket, base string) (string, error) {
+	remoteName := settings.Remote
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+	rawURL, err := git.RemoteURL(ticket.WorktreePath, remoteName)
+	if err != nil {
+		return "", err
+	}
+	remote, err := git.ParseRemote(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	provider := git.Provider(settings.Provider)
+	if provider == "" || provider == "auto" {
+		var ok bool
+		if provider, ok = remote.Provider(); !ok {
+			return "", fmt.Errorf("cannot tell whether %s is GitHub, GitLab or Gitea; set pull_request.provider or the project's provider", remote.Host)
+		}
+	}
+
+	if err := git.Push(ticket.WorktreePath, remoteName, ticket.BranchName); err != nil {
+		return "", err
+	}
+
+	pr := git.PullRequest{
+		Title: ticket.Title,
+		Body:  prBody(settings, ticket, base),
+		Head:  ticket.BranchName,
+		Base:  base,
+		Draft: settings.Draft,
+	}
+	return git.CreatePullRequest(ticket.WorktreePath, remote, provider, pr)
+}
+
+// prBody is the ticket description followed, when enabled, by the commit
+// messages the branch adds
+func prBody(settings config.PullRequestSettings, ticket *board.Ticket, base string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(ticket.Description))
+
+	if settings.IncludeCommits {
+		if subjects, err := git.CommitSubjects(ticket.WorktreePath, base); err == nil && len(subjects) > 0 {
+			if b.Len() > 0 {
+				b.WriteString("\n\n")
+			}
+			b.WriteString("## Changes\n\n")
+			for _, s := range subjects {
+				b.WriteString("- " + s + "\n")
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (m *Model) handlePRCreated(msg prCreatedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notify("Failed to create pull request: " + msg.err.Error())
+		return m, nil
+	}
+
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil {
+		return m, nil
+	}
+	ticket.PRURL = msg.url
+	m.saveTicket(ticket)
+	m.notify("Opened " + msg.url)
+	return m, m.linkLinearPR(ticket)
+}
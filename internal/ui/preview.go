@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// RenderPreview renders a sample board in the given theme at width x height.
+// The board is built in memory; nothing is read from or written to disk.
+func RenderPreview(cfg *config.Config, theme config.Theme, width, height int) string {
+	registry := &project.ProjectRegistry{Projects: map[string]*project.Project{}}
+	store := project.NewGlobalTicketStore(registry)
+	proj := project.NewProject("demo", "/tmp/demo")
+	store.AddProject(proj)
+
+	m := NewModel(cfg, store, registry, nil, nil, "", nil)
+	m.theme = theme
+	m.colors = newUIColors(theme)
+	m.width = width
+	m.height = height
+
+	// Agent statuses are set after NewModel, which resets them on startup
+	for _, t := range previewTickets(proj.ID) {
+		store.Add(t)
+	}
+	m.refreshColumnTickets()
+
+	return m.View()
+}
+
+func previewTickets(projectID string) []*board.Ticket {
+	started := time.Now().Add(-25 * time.Minute)
+
+	ticket := func(title string, status board.TicketStatus, agentStatus board.AgentStatus, priority int, labels ...string) *board.Ticket {
+		t := board.NewTicket(title, projectID)
+		t.Status = status
+		t.AgentStatus = agentStatus
+		t.Priority = priority
+		t.Labels = labels
+		if agentStatus != board.AgentNone {
+			t.AgentType = "claude"
+			t.AgentSpawnedAt = &started
+			t.BranchName = "task/" + board.Slugify(title, 40)
+		}
+		return t
+	}
+
+	return []*board.Ticket{
+		ticket("Add dark mode toggle", board.StatusBacklog, board.AgentNone, 2, "ui"),
+		ticket("Write release notes", board.StatusBacklog, board.AgentNone, 4, "docs"),
+		ticket("Fix login redirect loop", board.StatusInProgress, board.AgentWorking, 1, "bug"),
+		ticket("Refactor config loader", board.StatusInProgress, board.AgentWaiting, 3),
+		ticket("Retry flaky upload", board.StatusInProgress, board.AgentError, 2, "bug"),
+		ticket("Upgrade dependencies", board.StatusDone, board.AgentCompleted, 3, "chore"),
+	}
+}
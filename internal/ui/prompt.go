@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/agent"
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+type promptReviewedMsg struct {
+	ticketID board.TicketID
+	resend   bool // send to the running agent rather than spawn one
+	path     string
+	err      error
+}
+
+// agentConfigFor returns the config of the agent the ticket runs
+func (m *Model) agentConfigFor(ticket *board.Ticket) (config.AgentConfig, bool) {
+	agentCfg, ok := m.config.Agents[m.agentNameFor(ticket)]
+	return agentCfg, ok
+}
+
+// contextPrompt renders the init prompt the ticket's agent is started with
+func (m *Model) contextPrompt(ticket *board.Ticket) string {
+	agentCfg, ok := m.agentConfigFor(ticket)
+	if !ok {
+		return ""
+	}
+	agentType := filepath.Base(agentCfg.Command)
+	prompt := agent.BuildContextPrompt(m.config.GetEffectiveInitPrompt(agentType), ticket)
+	if ticket.Feedback == "" {
+		return prompt
+	}
+	base := ticket.BaseBranch
+	if mgr := m.worktreeMgrs[ticket.ProjectID]; base == "" && mgr != nil {
+		base, _ = mgr.GetDefaultBranch()
+	}
+	return iteratePrompt(prompt, ticket.Feedback, ticket.WorktreePath, base)
+}
+
+// reviewPrompt opens the rendered prompt in the editor, to be sent as saved.
+// Emptying the file cancels.
+func (m *Model) reviewPrompt(ticketID board.TicketID, prompt string, resend bool) (tea.Model, tea.Cmd) {
+	cmd, err := editText(prompt, func(path string, err error) tea.Msg {
+		return promptReviewedMsg{ticketID: ticketID, resend: resend, path: path, err: err}
+	})
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+	}
+	return m, cmd
+}
+
+func (m *Model) handlePromptReviewed(msg promptReviewedMsg) (tea.Model, tea.Cmd) {
+	prompt, err := readEditedText(msg.path, msg.err)
+	if err != nil {
+		m.notify("Failed to review prompt: " + err.Error())
+		return m, nil
+	}
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil {
+		return m, nil
+	}
+	if prompt == "" {
+		m.notify("Prompt was empty; nothing sent")
+		return m, nil
+	}
+	if msg.resend {
+		m.sendPrompt(ticket, prompt)
+		return m, nil
+	}
+	if m.spawnPrompts == nil {
+		m.spawnPrompts = make(map[board.TicketID]string)
+	}
+	m.spawnPrompts[ticket.ID] = prompt
+	return m.checkAndSpawn(ticket)
+}
+
+// resendPrompt sends the ticket's init prompt, rendered afresh so it picks
+// up edits and notes made since, to its running agent
+func (m *Model) resendPrompt() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("send prompts") {
+		return m, nil
+	}
+	if pane, ok := m.panes[ticket.ID]; !ok || !pane.Running() {
+		m.notify("No running agent for this ticket")
+		return m, nil
+	}
+	prompt := m.contextPrompt(ticket)
+	if prompt == "" {
+		m.notify("No init prompt configured for this agent")
+		return m, nil
+	}
+	if m.config.Defaults.ReviewPrompt {
+		return m.reviewPrompt(ticket.ID, prompt, true)
+	}
+	m.sendPrompt(ticket, prompt)
+	return m, nil
+}
+
+func (m *Model) sendPrompt(ticket *board.Ticket, prompt string) {
+	pane, ok := m.panes[ticket.ID]
+	if !ok || !pane.Running() {
+		m.notify("The agent exited before the prompt was sent")
+		return
+	}
+	if agentCfg, _ := m.agentConfigFor(ticket); agentCfg.ContextFile != "" && ticket.WorktreePath != "" {
+		if _, err := writeContextFile(ticket.WorktreePath, agentCfg.ContextFile, prompt); err != nil {
+			m.notify("Failed to send prompt: " + err.Error())
+			return
+		}
+		prompt = agent.ContextFilePrompt(agentCfg.ContextFile)
+	}
+	if err := pane.Paste(prompt); err != nil {
+		m.notify("Failed to send prompt: " + err.Error())
+		return
+	}
+	m.notify("Sent the context prompt to " + truncate(ticket.Title, 30))
+}
+
+// writeContextFile writes the prompt to the agent's context file in the
+// worktree, kept out of git status, and returns the file's path
+func writeContextFile(worktree, name, prompt string) (string, error) {
+	path, err := agent.WriteContextFile(worktree, name, prompt)
+	if err != nil {
+		return "", err
+	}
+	if err := git.Exclude(worktree, "/"+filepath.ToSlash(name)); err != nil {
+		slog.Warn("failed to exclude the context file from git", "file", path, "err", err)
+	}
+	return path, nil
+}
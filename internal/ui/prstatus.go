@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+type prStatusTickMsg time.Time
+type prStatusResultMsg struct {
+	statuses map[board.TicketID]git.PRStatus
+	err      error // the first failure, if any
+}
+
+func tickPRStatus(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return prStatusTickMsg(t)
+	})
+}
+
+// prStatusInterval follows pull_request.status_interval; with checks
+// disabled the tick still runs, slowly, so enabling them in settings works
+func (m *Model) prStatusInterval() time.Duration {
+	if m.config.PullRequest.StatusInterval <= 0 {
+		return time.Minute
+	}
+	return time.Duration(m.config.PullRequest.StatusInterval) * time.Second
+}
+
+// handlePRStatusTick checks the pull requests linked to tickets in the
+// background. Merged pull requests are not checked again.
+func (m *Model) handlePRStatusTick() (tea.Model, tea.Cmd) {
+	next := tickPRStatus(m.prStatusInterval())
+	if m.config.PullRequest.StatusInterval <= 0 || m.prStatusBusy {
+		return m, next
+	}
+
+	type target struct {
+		ticketID board.TicketID
+		path     string
+		url      string
+	}
+	var targets []target
+	for _, t := range m.globalStore.All() {
+		if t.PRURL == "" || t.Status == board.StatusArchived || m.prStatus[t.ID].State == "merged" {
+			continue
+		}
+		targets = append(targets, target{t.ID, t.WorktreePath, t.PRURL})
+	}
+	if len(targets) == 0 {
+		return m, next
+	}
+
+	m.prStatusBusy = true
+	check := func() tea.Msg {
+		result := prStatusResultMsg{statuses: make(map[board.TicketID]git.PRStatus, len(targets))}
+		for _, t := range targets {
+			st, err := git.PullRequestStatus(t.path, t.url)
+			if err != nil {
+				if result.err == nil {
+					result.err = err
+				}
+				continue
+			}
+			result.statuses[t.ticketID] = st
+		}
+		return result
+	}
+	return m, tea.Batch(check, next)
+}
+
+// applyPRStatuses records the checked pull requests, keeping the last known
+// state of those that failed. An error is reported once until it changes.
+func (m *Model) applyPRStatuses(msg prStatusResultMsg) {
+	m.prStatusBusy = false
+	if m.prStatus == nil {
+		m.prStatus = make(map[board.TicketID]git.PRStatus)
+	}
+	for id, st := range msg.statuses {
+		m.prStatus[id] = st
+	}
+
+	if msg.err == nil {
+		m.prStatusErr = ""
+		return
+	}
+	if msg.err.Error() != m.prStatusErr {
+		m.prStatusErr = msg.err.Error()
+		m.notify("Failed to check pull request status: " + m.prStatusErr)
+	}
+}
+
+// renderPRBadge shows a linked pull request with its checks and review
+// state once known, e.g. "PR ✓ approved"
+func (m *Model) renderPRBadge(ticket *board.Ticket) string {
+	if ticket.PRURL == "" {
+		return ""
+	}
+	st, ok := m.prStatus[ticket.ID]
+	if !ok {
+		return lipgloss.NewStyle().Foreground(m.colors.info).Render("⇡ PR")
+	}
+
+	switch st.State {
+	case "merged":
+		return lipgloss.NewStyle().Foreground(m.colors.secondary).Render("PR merged")
+	case "closed":
+		return lipgloss.NewStyle().Foreground(m.colors.muted).Render("PR closed")
+	}
+
+	label := "PR"
+	if st.Draft {
+		label = "PR draft"
+	}
+	badge := lipgloss.NewStyle().Foreground(m.colors.info).Render(label)
+	switch st.Checks {
+	case git.ChecksPassed:
+		badge += " " + lipgloss.NewStyle().Foreground(m.colors.success).Render("✓")
+	case git.ChecksFailed:
+		badge += " " + lipgloss.NewStyle().Foreground(m.colors.err).Render(fmt.Sprintf("✗%d", st.Failed))
+	case git.ChecksPending:
+		badge += " " + lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("◌%d", st.Pending))
+	}
+	switch st.Review {
+	case git.ReviewApproved:
+		badge += " " + lipgloss.NewStyle().Foreground(m.colors.success).Render("approved")
+	case git.ReviewChangesRequested:
+		badge += " " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("changes")
+	}
+	return badge
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+type pushResultMsg struct {
+	ticketID board.TicketID
+	branch   string
+	remote   string
+	auto     bool
+	status   git.WorktreeStatus
+	err      error
+}
+
+func (m *Model) pushTicketBranch() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("push branches") {
+		return m, nil
+	}
+	if ticket.BranchName == "" || ticket.WorktreePath == "" {
+		m.notify("No branch to push for this ticket yet")
+		return m, nil
+	}
+	m.notify("Pushing " + ticket.BranchName + "...")
+	return m, m.pushBranch(ticket, false)
+}
+
+// autoPush pushes the branch of a ticket whose agent completed, when
+// pull_request.push_on_complete is set
+func (m *Model) autoPush(ticket *board.Ticket) tea.Cmd {
+	if !m.config.PullRequest.PushOnComplete || ticket.BranchName == "" || ticket.WorktreePath == "" {
+		return nil
+	}
+	return m.pushBranch(ticket, true)
+}
+
+// pushBranch pushes the ticket branch with upstream tracking and reports the
+// worktree's status afterwards, so the card's remote badge updates at once
+func (m *Model) pushBranch(ticket *board.Ticket, auto bool) tea.Cmd {
+	remote := m.config.PullRequest.Remote
+	if remote == "" {
+		remote = "origin"
+	}
+	ticketID, path, branch, base := ticket.ID, ticket.WorktreePath, ticket.BranchName, ticket.BaseBranch
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	return func() tea.Msg {
+		msg := pushResultMsg{ticketID: ticketID, branch: branch, remote: remote, auto: auto}
+		if _, err := os.Stat(path); err != nil {
+			msg.err = fmt.Errorf("worktree %s is missing", path)
+			return msg
+		}
+		if msg.err = git.Push(path, remote, branch); msg.err != nil {
+			return msg
+		}
+		if base == "" && mgr != nil {
+			base, _ = mgr.GetDefaultBranch()
+		}
+		msg.status, _ = git.Status(path, base)
+		return msg
+	}
+}
+
+func (m *Model) handlePushResult(msg pushResultMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notify("Failed to push " + msg.branch + ": " + msg.err.Error())
+		return m, nil
+	}
+	if m.worktreeStatus == nil {
+		m.worktreeStatus = make(map[board.TicketID]git.WorktreeStatus)
+	}
+	m.worktreeStatus[msg.ticketID] = msg.status
+	if msg.auto {
+		m.notify("Agent finished; pushed " + msg.branch + " to " + msg.remote)
+	} else {
+		m.notify("Pushed " + msg.branch + " to " + msg.remote)
+	}
+	return m, nil
+}
+
+// renderRemoteBadge shows whether the branch is pushed: ☁ when level with
+// its upstream, with ⇡/⇣ counts of commits not yet pushed or pulled
+func (m *Model) renderRemoteBadge(st git.WorktreeStatus) string {
+	if st.Upstream == "" {
+		return ""
+	}
+	if st.Pushed() {
+		return lipgloss.NewStyle().Foreground(m.colors.success).Render("☁")
+	}
+	badge := "☁"
+	if st.Unpushed > 0 {
+		badge += fmt.Sprintf("⇡%d", st.Unpushed)
+	}
+	if st.Unpulled > 0 {
+		badge += fmt.Sprintf("⇣%d", st.Unpulled)
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.warning).Render(badge)
+}
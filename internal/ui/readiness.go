@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+const (
+	// readinessInterval is how often a starting agent is checked
+	readinessInterval = 250 * time.Millisecond
+	// settleTime is how long an agent's screen must stay unchanged to count
+	// as ready when it has no ready_pattern
+	settleTime = 1500 * time.Millisecond
+)
+
+type readinessTickMsg time.Time
+
+func tickReadiness() tea.Cmd {
+	return tea.Tick(readinessInterval, func(t time.Time) tea.Msg {
+		return readinessTickMsg(t)
+	})
+}
+
+// pendingPrompt is an init prompt waiting for its agent to take input
+type pendingPrompt struct {
+	text     string
+	pattern  *regexp.Regexp
+	deadline time.Time
+
+	screen    string    // agent's screen at the last check
+	changedAt time.Time // when the screen last changed
+}
+
+// awaitReady holds prompt until the ticket's agent is ready for it: when its
+// screen matches ready_pattern, or without one when the screen has settled.
+// Checks carry on until ready_timeout.
+func (m *Model) awaitReady(ticketID board.TicketID, prompt string) tea.Cmd {
+	if prompt == "" {
+		return nil
+	}
+	ticket, _ := m.globalStore.Get(ticketID)
+	if ticket == nil {
+		return nil
+	}
+	agentCfg, _ := m.agentConfigFor(ticket)
+	timeout := agentCfg.ReadyTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultReadyTimeout
+	}
+	p := &pendingPrompt{text: prompt, deadline: time.Now().Add(time.Duration(timeout) * time.Second)}
+	if agentCfg.ReadyPattern != "" {
+		p.pattern, _ = regexp.Compile(agentCfg.ReadyPattern)
+	}
+
+	start := len(m.pendingPrompts) == 0
+	if m.pendingPrompts == nil {
+		m.pendingPrompts = make(map[board.TicketID]*pendingPrompt)
+	}
+	m.pendingPrompts[ticketID] = p
+	if start {
+		return tickReadiness()
+	}
+	return nil
+}
+
+func (m *Model) handleReadinessTick() (tea.Model, tea.Cmd) {
+	now := time.Now()
+	for id, p := range m.pendingPrompts {
+		ticket, _ := m.globalStore.Get(id)
+		pane, ok := m.panes[id]
+		if ticket == nil || !ok || !pane.Running() {
+			delete(m.pendingPrompts, id)
+			continue
+		}
+
+		if agentReady(p, pane.GetContent(), now) {
+			delete(m.pendingPrompts, id)
+			m.sendPrompt(ticket, p.text)
+			continue
+		}
+		if now.After(p.deadline) {
+			delete(m.pendingPrompts, id)
+			m.notify(fmt.Sprintf("The agent for %q wasn't ready for its prompt; press %s to send it",
+				truncate(ticket.Title, 30), m.keyHint(keymap.ResendPrompt)))
+		}
+	}
+	if len(m.pendingPrompts) == 0 {
+		return m, nil
+	}
+	return m, tickReadiness()
+}
+
+// agentReady checks the agent's screen. The status detector isn't asked: it
+// takes a trust or login question for the agent waiting on its prompt.
+func agentReady(p *pendingPrompt, screen string, now time.Time) bool {
+	if p.pattern != nil {
+		return p.pattern.MatchString(screen)
+	}
+	if screen != p.screen {
+		p.screen, p.changedAt = screen, now
+		return false
+	}
+	return strings.TrimSpace(screen) != "" && now.Sub(p.changedAt) >= settleTime
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/events"
+)
+
+// ReloadConfigMsg asks the model to re-read the config file, e.g. on SIGHUP.
+type ReloadConfigMsg struct{}
+
+// SetConfigPath records the config file the board was started with, which
+// reloads and settings changes use instead of the default one.
+func (m *Model) SetConfigPath(path string) {
+	m.configPath = path
+}
+
+// configFile returns the path of the config file the board runs with
+func (m *Model) configFile() (string, error) {
+	if m.configPath != "" {
+		return m.configPath, nil
+	}
+	return config.ConfigPath()
+}
+
+// reloadConfig re-reads the config file and applies it in place. The current
+// config is kept when the file is invalid.
+func (m *Model) reloadConfig() error {
+	cfg, result, err := config.LoadWithValidation(m.configPath)
+	if result != nil && result.HasErrors() {
+		return errors.New("invalid config: " + strings.TrimSpace(result.FormatErrors()))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Update in place so components holding the pointer see the new values
+	*m.config = *cfg
+	m.applyConfig()
+	m.sidebarVisible = m.config.UI.SidebarVisible
+	if !m.sidebarVisible {
+		m.sidebarFocused = false
+	}
+	m.setSplitView(m.config.UI.Split)
+	m.setCardDensity(m.config.UI.CardDensity)
+	m.outputPreview = m.config.UI.OutputPreview
+	m.fileFeed = m.config.UI.FileFeed
+	m.refreshColumnTickets()
+	m.notify("Config reloaded")
+	return nil
+}
+
+// applyConfig refreshes the state derived from m.config
+func (m *Model) applyConfig() {
+	m.theme = m.config.GetTheme()
+	m.colors = newUIColors(m.theme)
+	m.hooks = events.NewHookRunner(m.config.Hooks)
+	m.notifier = events.NewNotifier(m.config.Notifications.Webhooks)
+	m.keys = m.config.Keymap()
+	m.applyOrder()
+	m.applyColumns()
+	m.applyHighlights()
+}
+
+func (m *Model) handleReloadConfig() (tea.Model, tea.Cmd) {
+	if err := m.reloadConfig(); err != nil {
+		m.notify("Reload failed: " + err.Error())
+	}
+	return m, nil
+}
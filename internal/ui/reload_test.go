@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReloadConfig_StartedWithConfig(t *testing.T) {
+	m := newBenchModel(t, 1)
+	path := filepath.Join(t.TempDir(), "board.json")
+	if err := os.WriteFile(path, []byte(`{"ui": {"column_width": 55}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m.SetConfigPath(path)
+
+	if err := m.reloadConfig(); err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+	if got := m.config.UI.ColumnWidth; got != 55 {
+		t.Errorf("column width after reload = %d; want 55 from %s", got, path)
+	}
+}
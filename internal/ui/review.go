@@ -0,0 +1,328 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/agent"
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// maxSummaryFiles caps the files listed in an iteration's prompt
+const maxSummaryFiles = 50
+
+type feedbackEditedMsg struct {
+	ticketID board.TicketID
+	path     string
+	err      error
+}
+
+// transcriptView shows what an agent printed, for reviewing its run
+type transcriptView struct {
+	ticketID board.TicketID
+	title    string
+	lines    []string
+	offset   int
+}
+
+// columnLayout returns the board's columns. Review is shown while review is
+// on, and while a ticket still waits in it after review was turned off.
+func columnLayout(review bool, tickets []*board.Ticket) []board.Column {
+	if !review {
+		review = slices.ContainsFunc(tickets, func(t *board.Ticket) bool {
+			return t.Status == board.StatusReview
+		})
+	}
+	return board.Columns(review)
+}
+
+// applyColumns adds or drops the Review column after behavior.review changes
+func (m *Model) applyColumns() {
+	columns := columnLayout(m.config.Behavior.Review, m.globalStore.All())
+	if m.focusMode {
+		m.boardColumns = columns
+		return
+	}
+	if len(columns) == len(m.columns) {
+		return
+	}
+	selected := m.selectedTicket()
+	m.columns = columns
+	m.columnOffsets = nil
+	m.activeColumn = min(m.activeColumn, len(columns)-1)
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.ensureColumnVisible()
+}
+
+// hasReviewColumn reports whether the board, rather than focus mode's
+// column, has a Review column
+func (m *Model) hasReviewColumn() bool {
+	columns := m.columns
+	if m.focusMode {
+		columns = m.boardColumns
+	}
+	return slices.ContainsFunc(columns, func(c board.Column) bool {
+		return c.Status == board.StatusReview
+	})
+}
+
+// submitForReview moves a ticket whose agent finished from In Progress to
+// Review
+func (m *Model) submitForReview(ticket *board.Ticket) {
+	if !m.config.Behavior.Review || m.config.Behavior.ReadOnly || ticket.Status != board.StatusInProgress {
+		return
+	}
+	m.globalStore.Move(ticket.ID, board.StatusReview)
+	m.saveTicket(ticket)
+
+	selected := m.selectedTicket()
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.notify(truncate(ticket.Title, 30) + " is ready for review")
+	m.publishTicketMoved(ticket, board.StatusInProgress)
+}
+
+// approveTicket moves a reviewed ticket to Done and starts what
+// behavior.on_approve asks for
+func (m *Model) approveTicket() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("approve tickets") {
+		return m, nil
+	}
+	if ticket.Status != board.StatusReview {
+		m.notify("Only tickets in Review can be approved")
+		return m, nil
+	}
+
+	m.globalStore.Move(ticket.ID, board.StatusDone)
+	ticket.Feedback = ""
+	m.refreshColumnTickets()
+	m.selectTicketByID(ticket.ID)
+	m.saveTicket(ticket)
+	m.publishTicketMoved(ticket, board.StatusReview)
+	delete(m.transcripts, ticket.ID)
+
+	// A filter hiding Done leaves the ticket unselected, and the merge and
+	// pull request flows act on the selection
+	if selected := m.selectedTicket(); selected == nil || selected.ID != ticket.ID || m.config.Behavior.OnApprove == "none" {
+		m.notify("Approved " + truncate(ticket.Title, 30))
+		return m, nil
+	}
+	if m.config.Behavior.OnApprove == "pr" {
+		return m.confirmCreatePR()
+	}
+	return m.confirmMerge(git.StrategyMerge)
+}
+
+// rejectTicket asks for feedback on a reviewed ticket in the editor.
+// Saving an empty file keeps the ticket in Review.
+func (m *Model) rejectTicket() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("reject tickets") {
+		return m, nil
+	}
+	if ticket.Status != board.StatusReview {
+		m.notify("Only tickets in Review can be rejected")
+		return m, nil
+	}
+	ticketID := ticket.ID
+	cmd, err := editText(ticket.Feedback, func(path string, err error) tea.Msg {
+		return feedbackEditedMsg{ticketID: ticketID, path: path, err: err}
+	})
+	if err != nil {
+		m.notify("Failed to open editor: " + err.Error())
+	}
+	return m, cmd
+}
+
+// handleFeedbackEdited sends the ticket back to In Progress and iterates:
+// the agent is started afresh in the same worktree, with the feedback and
+// a summary of the changes so far added to its prompt. A spawn that can't
+// start now leaves the feedback for the next one.
+func (m *Model) handleFeedbackEdited(msg feedbackEditedMsg) (tea.Model, tea.Cmd) {
+	text, err := readEditedText(msg.path, msg.err)
+	if err != nil {
+		m.notify("Failed to edit feedback: " + err.Error())
+		return m, nil
+	}
+	ticket, _ := m.globalStore.Get(msg.ticketID)
+	if ticket == nil || ticket.Status != board.StatusReview {
+		return m, nil
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		m.notify("No feedback given; the ticket stays in review")
+		return m, nil
+	}
+
+	if pane, ok := m.panes[ticket.ID]; ok {
+		m.keepTranscript(ticket.ID, pane)
+		m.stopTicketAgent(ticket)
+	}
+	m.globalStore.Move(ticket.ID, board.StatusInProgress)
+	ticket.Feedback = text
+	ticket.AgentSpawnedAt = nil
+	ticket.AgentSessionID = ""
+	m.saveTicket(ticket)
+
+	selected := m.selectedTicket()
+	m.refreshColumnTickets()
+	if selected != nil {
+		m.selectTicketByID(selected.ID)
+	}
+	m.publishTicketMoved(ticket, board.StatusReview)
+
+	_, cmd, err := m.startSpawn(ticket, true)
+	if err != nil {
+		m.notify("Rejected " + truncate(ticket.Title, 30) + "; the next agent run gets the feedback (" + err.Error() + ")")
+		return m, nil
+	}
+	m.notify("Rejected " + truncate(ticket.Title, 30) + "; starting a new agent run with the feedback")
+	return m, cmd
+}
+
+// iteratePrompt adds review feedback, and what the worktree changes since
+// base, to a fresh run's prompt
+func iteratePrompt(prompt, feedback, worktree, base string) string {
+	if feedback == "" {
+		return prompt
+	}
+	var changes string
+	if worktree != "" && base != "" {
+		files, err := git.WorktreeDiff(worktree, base)
+		if err != nil {
+			slog.Debug("failed to summarize changes for the feedback prompt", "worktree", worktree, "err", err)
+		} else if len(files) > 0 {
+			changes = git.Summarize(files, maxSummaryFiles)
+		}
+	}
+	return agent.IteratePrompt(prompt, feedback, changes)
+}
+
+// keepTranscript holds on to what an exiting agent printed, for review
+// once its pane is gone
+func (m *Model) keepTranscript(ticketID board.TicketID, pane *terminal.Pane) {
+	text := pane.Transcript()
+	if text == "" {
+		return
+	}
+	if m.transcripts == nil {
+		m.transcripts = make(map[board.TicketID]string)
+	}
+	m.transcripts[ticketID] = text
+}
+
+// openTranscript shows the selected ticket's agent output: the running
+// agent's, or what the last agent to exit this session left
+func (m *Model) openTranscript() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil {
+		return m, nil
+	}
+	text := m.transcripts[ticket.ID]
+	if pane, ok := m.panes[ticket.ID]; ok {
+		text = pane.Transcript()
+	}
+	if text == "" {
+		m.notify("No agent output for this ticket this session")
+		return m, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	m.transcript = &transcriptView{
+		ticketID: ticket.ID,
+		title:    ticket.Title,
+		lines:    lines,
+		offset:   max(len(lines)-m.transcriptHeight(), 0),
+	}
+	m.mode = ModeTranscript
+	return m, nil
+}
+
+func (m *Model) handleTranscriptMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tv := m.transcript
+	height := m.transcriptHeight()
+
+	switch msg.String() {
+	case "esc", "q":
+		m.transcript = nil
+		m.mode = ModeNormal
+	case "j", "down":
+		tv.scroll(1, height)
+	case "k", "up":
+		tv.scroll(-1, height)
+	case "pgdown", " ", "ctrl+d":
+		tv.scroll(height, height)
+	case "pgup", "ctrl+u":
+		tv.scroll(-height, height)
+	case "g", "home":
+		tv.offset = 0
+	case "G", "end":
+		tv.scroll(len(tv.lines), height)
+	case "y":
+		if err := copyToClipboard(strings.Join(tv.lines, "\n")); err != nil {
+			m.notify("Failed to copy: " + err.Error())
+		} else {
+			m.notify(fmt.Sprintf("Copied %d lines", len(tv.lines)))
+		}
+	case "e":
+		return m.exportTranscript()
+	}
+	return m, nil
+}
+
+func (tv *transcriptView) scroll(delta, height int) {
+	tv.offset = max(min(tv.offset+delta, len(tv.lines)-height), 0)
+}
+
+// transcriptHeight is the number of output lines that fit in the
+// transcript overlay next to its header and key hints
+func (m *Model) transcriptHeight() int {
+	return max(m.height-14, 3)
+}
+
+func (m *Model) renderTranscriptView() string {
+	tv := m.transcript
+	width := min(120, m.width-4)
+	inner := width - 6
+	height := m.transcriptHeight()
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(m.colors.text)
+
+	end := min(tv.offset+height, len(tv.lines))
+	lines := []string{
+		titleStyle.Render("Transcript: " + truncate(tv.title, inner-12)),
+		m.dimStyle().Render(fmt.Sprintf("Lines %d-%d of %d", tv.offset+1, end, len(tv.lines))),
+		"",
+	}
+	for _, line := range tv.lines[tv.offset:end] {
+		lines = append(lines, textStyle.Render(truncate(line, inner)))
+	}
+
+	hints := keyStyle.Render("[j/k]") + m.dimStyle().Render(" Scroll  ") +
+		keyStyle.Render("[g/G]") + m.dimStyle().Render(" Top/bottom  ") +
+		keyStyle.Render("[y]") + m.dimStyle().Render(" Copy  ") +
+		keyStyle.Render("[e]") + m.dimStyle().Render(" Export HTML  ") +
+		keyStyle.Render("[esc]") + m.dimStyle().Render(" Close")
+	lines = append(lines, "", hints)
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
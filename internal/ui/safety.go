@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// readOnlyBlocked reports whether behavior.read_only forbids an action,
+// telling the user so. what finishes "can't ...", e.g. "delete tickets".
+func (m *Model) readOnlyBlocked(what string) bool {
+	if !m.config.Behavior.ReadOnly {
+		return false
+	}
+	m.notify("Read-only mode: can't " + what)
+	return true
+}
+
+// askConfirm shows the confirm dialog, running fn on [y]. When ask is
+// false fn runs straight away.
+func (m *Model) askConfirm(ask bool, msg string, fn func() tea.Cmd) tea.Cmd {
+	if !ask {
+		return fn()
+	}
+	m.showConfirm = true
+	m.confirmMsg = msg
+	m.confirmFn = fn
+	return nil
+}
+
+// askTypedConfirm shows the confirm dialog but only runs fn once want has
+// been typed, for deletions that can't be undone
+func (m *Model) askTypedConfirm(msg, want string, fn func() tea.Cmd) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "› "
+	input.CharLimit = max(len(want)*2, 64)
+	input.Width = min(max(len(want), 20), 60)
+	input.Focus()
+	m.confirmInput = input
+	m.confirmWant = want
+	m.askConfirm(true, msg, fn)
+	return textinput.Blink
+}
+
+// confirmTyped reports whether the typed confirmation matches, ignoring
+// case and surrounding spaces
+func (m *Model) confirmTyped() bool {
+	return strings.EqualFold(strings.TrimSpace(m.confirmInput.Value()), strings.TrimSpace(m.confirmWant))
+}
+
+func (m *Model) handleTypedConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if m.confirmTyped() {
+			return m, m.answerConfirm(true)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.confirmInput, cmd = m.confirmInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,432 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// sessionItem is an agent session: a pane on the board, or a tmux window
+// openkanban started that no pane owns, usually because its ticket was
+// deleted
+type sessionItem struct {
+	ticketID board.TicketID
+	ticket   *board.Ticket        // nil when the ticket no longer exists
+	pane     *terminal.Pane       // nil for windows the board hasn't adopted
+	target   *terminal.TmuxTarget // nil for agents in the embedded terminal
+}
+
+func (s sessionItem) orphaned() bool {
+	return s.pane == nil
+}
+
+func (s sessionItem) running() bool {
+	if s.pane != nil {
+		return s.pane.Running()
+	}
+	return !s.target.Dead
+}
+
+// sessionsView is the screen listing every agent session
+type sessionsView struct {
+	items   []sessionItem
+	index   int
+	offset  int
+	loading bool
+	err     string
+	adopt   *adoptView
+}
+
+// adoptView picks the ticket an orphaned session is handed to
+type adoptView struct {
+	session sessionItem
+	tickets []*board.Ticket
+	index   int
+	offset  int
+}
+
+type sessionsLoadedMsg struct {
+	targets map[string]terminal.TmuxTarget // by ticket ID
+	err     error
+}
+
+func (m *Model) openSessions() (tea.Model, tea.Cmd) {
+	m.sessions = &sessionsView{loading: true}
+	m.mode = ModeSessions
+	return m, m.loadSessions()
+}
+
+// loadSessions lists openkanban's tmux windows in the background. They are
+// looked for even with tmux disabled, since windows from when it was enabled
+// may still be running.
+func (m *Model) loadSessions() tea.Cmd {
+	if !terminal.TmuxAvailable() {
+		return func() tea.Msg { return sessionsLoadedMsg{} }
+	}
+	return func() tea.Msg {
+		targets, err := terminal.FindTmuxPanes()
+		return sessionsLoadedMsg{targets: targets, err: err}
+	}
+}
+
+func (m *Model) handleSessionsLoaded(msg sessionsLoadedMsg) (tea.Model, tea.Cmd) {
+	v := m.sessions
+	if v == nil {
+		return m, nil
+	}
+	v.loading = false
+	v.err = ""
+	if msg.err != nil {
+		v.err = msg.err.Error()
+	}
+
+	var items []sessionItem
+	for id, pane := range m.panes {
+		ticket, _ := m.globalStore.Get(id)
+		items = append(items, sessionItem{ticketID: id, ticket: ticket, pane: pane, target: pane.Tmux()})
+	}
+	for id, target := range msg.targets {
+		ticketID := board.TicketID(id)
+		if _, owned := m.panes[ticketID]; owned {
+			continue
+		}
+		ticket, _ := m.globalStore.Get(ticketID)
+		items = append(items, sessionItem{ticketID: ticketID, ticket: ticket, target: &target})
+	}
+	// Sessions on the board first, then orphans, each by title
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].orphaned() != items[j].orphaned() {
+			return !items[i].orphaned()
+		}
+		if a, b := m.sessionLabel(items[i]), m.sessionLabel(items[j]); a != b {
+			return a < b
+		}
+		return items[i].ticketID < items[j].ticketID
+	})
+	v.items = items
+	v.selectItem(v.index, m.sessionsListHeight())
+	return m, nil
+}
+
+func (m *Model) sessionLabel(s sessionItem) string {
+	if s.ticket == nil {
+		return "deleted ticket " + string(s.ticketID)
+	}
+	return s.ticket.Title
+}
+
+func (m *Model) handleSessionsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.sessions
+	if v.adopt != nil {
+		return m.handleAdoptKey(msg)
+	}
+	height := m.sessionsListHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = ModeNormal
+		m.sessions = nil
+	case "j", "down":
+		v.selectItem(v.index+1, height)
+	case "k", "up":
+		v.selectItem(v.index-1, height)
+	case "r":
+		v.loading = true
+		return m, m.loadSessions()
+	case "enter":
+		if s, ok := v.selected(); ok {
+			return m.attachSession(s)
+		}
+	case "x":
+		if s, ok := v.selected(); ok {
+			m.confirmKillSession(s)
+		}
+	case "a":
+		if s, ok := v.selected(); ok {
+			return m.startAdopt(s)
+		}
+	}
+	return m, nil
+}
+
+func (v *sessionsView) selected() (sessionItem, bool) {
+	if v.loading || v.index >= len(v.items) {
+		return sessionItem{}, false
+	}
+	return v.items[v.index], true
+}
+
+func (v *sessionsView) selectItem(i, height int) {
+	v.index = max(min(i, len(v.items)-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+func (m *Model) sessionsListHeight() int {
+	return max(m.height-16, 3)
+}
+
+// attachSession shows a session's agent: its tmux window, or the embedded
+// agent view
+func (m *Model) attachSession(s sessionItem) (tea.Model, tea.Cmd) {
+	if !s.running() {
+		m.sessions.err = "agent has exited"
+		return m, nil
+	}
+	if s.target != nil {
+		return m, m.attachTmux(s.target)
+	}
+	m.sessions = nil
+	m.selectTicketByID(s.ticketID)
+	m.mode = ModeAgentView
+	m.focusedPane = s.ticketID
+	s.pane.SetSize(m.paneSize())
+	return m, nil
+}
+
+func (m *Model) confirmKillSession(s sessionItem) {
+	if m.readOnlyBlocked("kill sessions") {
+		return
+	}
+	what := "the agent of " + m.sessionLabel(s)
+	if s.target != nil {
+		what += " (tmux " + s.target.String() + ")"
+	}
+	m.showConfirm = true
+	m.confirmMsg = "Kill " + what + "?"
+	m.confirmFn = func() tea.Cmd {
+		if s.orphaned() {
+			if err := s.target.Kill(); err != nil {
+				m.notify("Failed to kill session: " + err.Error())
+				return nil
+			}
+		} else if s.pane == m.panes[s.ticketID] {
+			s.pane.Stop()
+			delete(m.panes, s.ticketID)
+			if m.focusedPane == s.ticketID {
+				m.focusedPane = ""
+			}
+			if s.ticket != nil {
+				m.setAgentStatus(s.ticket, board.AgentNone)
+				m.endAgentRun(s.ticket, board.OutcomeStopped)
+				m.saveTicket(s.ticket)
+			}
+		}
+		m.notify("Killed " + what)
+		if m.sessions == nil {
+			return nil
+		}
+		m.sessions.loading = true
+		return m.loadSessions()
+	}
+}
+
+// startAdopt gives an orphaned tmux window back to the board: to its own
+// ticket when that still exists, otherwise to one picked from the list
+func (m *Model) startAdopt(s sessionItem) (tea.Model, tea.Cmd) {
+	switch {
+	case m.readOnlyBlocked("adopt sessions"):
+		return m, nil
+	case !s.orphaned():
+		m.sessions.err = "session already belongs to " + m.sessionLabel(s)
+		return m, nil
+	case !s.running():
+		m.sessions.err = "agent has exited; kill its window instead"
+		return m, nil
+	case s.ticket != nil:
+		return m, m.adoptSession(s, s.ticket)
+	}
+
+	var tickets []*board.Ticket
+	for _, t := range m.globalStore.All() {
+		if _, busy := m.panes[t.ID]; !busy && t.Status != board.StatusArchived {
+			tickets = append(tickets, t)
+		}
+	}
+	if len(tickets) == 0 {
+		m.sessions.err = "every ticket already has an agent"
+		return m, nil
+	}
+	// In Progress tickets are the likely owners, so they come first
+	sort.SliceStable(tickets, func(i, j int) bool {
+		if ip := tickets[i].Status == board.StatusInProgress; ip != (tickets[j].Status == board.StatusInProgress) {
+			return ip
+		}
+		return tickets[i].Title < tickets[j].Title
+	})
+	m.sessions.adopt = &adoptView{session: s, tickets: tickets}
+	return m, nil
+}
+
+func (m *Model) handleAdoptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a := m.sessions.adopt
+	height := m.sessionsListHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.sessions.adopt = nil
+	case "j", "down":
+		a.selectItem(a.index+1, height)
+	case "k", "up":
+		a.selectItem(a.index-1, height)
+	case "enter":
+		m.sessions.adopt = nil
+		return m, m.adoptSession(a.session, a.tickets[a.index])
+	}
+	return m, nil
+}
+
+func (a *adoptView) selectItem(i, height int) {
+	a.index = max(min(i, len(a.tickets)-1), 0)
+	if a.index < a.offset {
+		a.offset = a.index
+	} else if a.index >= a.offset+height {
+		a.offset = a.index - height + 1
+	}
+}
+
+// adoptSession makes the window ticket's agent pane, retagging it so a
+// restarted board finds it under the new ticket too
+func (m *Model) adoptSession(s sessionItem, ticket *board.Ticket) tea.Cmd {
+	if _, busy := m.panes[ticket.ID]; busy {
+		m.sessions.err = ticket.Title + " already has an agent"
+		return nil
+	}
+	if ticket.ID != s.ticketID {
+		if err := s.target.Retag(string(ticket.ID)); err != nil {
+			m.notify("Failed to adopt session: " + err.Error())
+			return nil
+		}
+	}
+	pane, wait := terminal.AdoptTmux(string(ticket.ID), *s.target, m.width, m.height-2)
+	m.panes[ticket.ID] = pane
+	m.notify("Adopted tmux " + s.target.String() + " as the agent of " + truncate(ticket.Title, 30))
+	m.sessions.loading = true
+	return tea.Batch(wait, m.loadSessions())
+}
+
+func (m *Model) renderSessionsView() string {
+	v := m.sessions
+	if v.adopt != nil {
+		return m.renderAdoptView()
+	}
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(100, m.width-4)
+	inner := width - 6
+
+	lines := []string{titleStyle.Render("Agent Sessions"), ""}
+	switch {
+	case v.loading && len(v.items) == 0:
+		lines = append(lines, "  "+m.spinner.View()+m.dimStyle().Render(" Looking for sessions..."))
+	case len(v.items) == 0:
+		lines = append(lines, m.dimStyle().Render("  No agents running"))
+	}
+
+	height := m.sessionsListHeight()
+	end := min(v.offset+height, len(v.items))
+	for i := v.offset; i < end; i++ {
+		s := v.items[i]
+		style := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == v.index {
+			style = style.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		state := lipgloss.NewStyle().Foreground(m.colors.success).Render("● ")
+		if !s.running() {
+			state = m.dimStyle().Render("○ ")
+		}
+
+		where := "embedded"
+		if s.target != nil {
+			where = "tmux " + s.target.String()
+		}
+		var note string
+		switch {
+		case s.orphaned() && s.ticket == nil:
+			note = lipgloss.NewStyle().Foreground(m.colors.warning).Render("  orphaned")
+		case s.orphaned():
+			note = lipgloss.NewStyle().Foreground(m.colors.warning).Render("  not on board")
+		default:
+			where += " · " + string(s.ticket.Status)
+			if badge := m.renderUsageBadge(s.ticketID); badge != "" {
+				note = "  " + badge + m.dimStyle().Render(fmt.Sprintf(" %d procs", m.usage[s.ticketID].Procs))
+			}
+		}
+		row := cursor + state
+		label := truncate(m.sessionLabel(s), max(inner-lipgloss.Width(row)-len(where)-lipgloss.Width(note)-2, 10))
+		lines = append(lines, row+style.Render(label)+m.dimStyle().Render("  "+where)+note)
+	}
+	if len(v.items) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(v.items)-end)))
+	}
+
+	if v.err != "" {
+		lines = append(lines, "", "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+v.err))
+	}
+
+	lines = append(lines, "",
+		m.dimStyle().Render("Orphaned sessions are tmux windows whose ticket was deleted."),
+		"",
+		keyStyle.Render("[enter]")+m.dimStyle().Render(" Attach  ")+
+			keyStyle.Render("[x]")+m.dimStyle().Render(" Kill  ")+
+			keyStyle.Render("[a]")+m.dimStyle().Render(" Adopt to ticket  ")+
+			keyStyle.Render("[r]")+m.dimStyle().Render(" Refresh  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderAdoptView() string {
+	a := m.sessions.adopt
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(100, m.width-4)
+	inner := width - 6
+
+	lines := []string{
+		titleStyle.Render("Adopt tmux " + a.session.target.String()),
+		m.dimStyle().Render("Pick the ticket this agent works on"),
+		"",
+	}
+	height := m.sessionsListHeight()
+	end := min(a.offset+height, len(a.tickets))
+	for i := a.offset; i < end; i++ {
+		t := a.tickets[i]
+		style := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == a.index {
+			style = style.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		status := "  " + string(t.Status)
+		lines = append(lines, cursor+style.Render(truncate(t.Title, max(inner-len(status)-2, 10)))+m.dimStyle().Render(status))
+	}
+	if len(a.tickets) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(a.tickets)-end)))
+	}
+
+	lines = append(lines, "",
+		keyStyle.Render("[enter]")+m.dimStyle().Render(" Adopt  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Back"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
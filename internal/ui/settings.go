@@ -0,0 +1,607 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// settingsField is one row of the settings overlay. key is the dotted config
+// key the field edits; the project filter is board state and has no key.
+type settingsField struct {
+	key         string
+	label       string
+	kind        string // "toggle", "choice", "text", "theme" or "project"
+	description string
+	options     []string // values a "choice" field cycles through
+	placeholder string   // shown when the value is empty
+}
+
+// settingsPage is a tab of the settings overlay
+type settingsPage struct {
+	title  string
+	fields func(m *Model) []settingsField
+}
+
+var settingsPages = []settingsPage{
+	{"General", (*Model).generalSettings},
+	{"Agents", (*Model).agentSettings},
+	{"Git", (*Model).gitSettings},
+	{"Terminal", (*Model).terminalSettings},
+	{"Notify", (*Model).notifySettings},
+	{"Theme", (*Model).themeSettings},
+	{"Keys", (*Model).keySettings},
+}
+
+const bindingKeyPrefix = "keybindings.bindings."
+
+func (m *Model) generalSettings() []settingsField {
+	return []settingsField{
+		{key: "defaults.default_agent", label: "Default Agent", kind: "choice", options: m.getAgentNames(), description: "Agent to spawn for new tickets"},
+		{key: "behavior.confirm_quit_with_agents", label: "Confirm Quit", kind: "toggle", description: "Prompt before quitting with running agents"},
+		{key: "behavior.read_only", label: "Read-only", kind: "toggle", description: "Browse only: no ticket, agent or git changes"},
+		{key: "behavior.confirm.delete_ticket", label: "Confirm Delete", kind: "toggle", description: "Ask before deleting a ticket (uncommitted changes always ask)"},
+		{key: "behavior.confirm.type_to_delete", label: "Type to Delete", kind: "toggle", description: "Type the ticket's title to delete a ticket that removes its worktree"},
+		{key: "behavior.confirm.stop_agent", label: "Confirm Stop", kind: "toggle", description: "Ask before stopping an agent"},
+		{key: "behavior.confirm.prune_worktree", label: "Confirm Prune", kind: "toggle", description: "Ask before removing a worktree from the worktrees screen (uncommitted changes always ask)"},
+		{key: "behavior.autosave_interval", label: "Autosave", kind: "text", description: "Seconds ticket changes wait before being written; 0 writes each change at once", placeholder: "2"},
+		{key: "behavior.sync_strategy", label: "Sync Strategy", kind: "choice", options: []string{"rebase", "merge"}, description: "How syncing brings a ticket branch up to date with its base"},
+		{key: "behavior.review", label: "Review Stage", kind: "toggle", description: "Tickets whose agent finishes wait in a Review column to be approved or rejected"},
+		{key: "behavior.on_approve", label: "On Approve", kind: "choice", options: []string{"merge", "pr", "none"}, description: "What approving a reviewed ticket starts: a merge, a pull request or nothing"},
+		{key: "ui.sidebar_visible", label: "Show Sidebar", kind: "toggle", description: "Show the project sidebar"},
+		{key: "ui.tabs", label: "Project Tabs", kind: "toggle", description: "Show a header tab per project when there are two or more"},
+		{key: "ui.ticket_order", label: "Ticket Order", kind: "choice", options: []string{"created", "updated", "name", "manual"}, description: "Order of tickets in a column; J/K arrange them by hand"},
+		{key: "ui.project_order", label: "Project Order", kind: "choice", options: []string{"name", "created"}, description: "Order of projects in the sidebar, tabs and lists"},
+		{key: "ui.group_by_project", label: "Group by Project", kind: "toggle", description: "Order each column by project when showing several projects"},
+		{key: "ui.show_agent_status", label: "Agent Status", kind: "toggle", description: "Show agent status on tickets"},
+		{key: "ui.show_git_status", label: "Git Status", kind: "toggle", description: "Show uncommitted changes and commits ahead/behind base on tickets"},
+		{key: "ui.card_density", label: "Card Density", kind: "choice", options: []string{"compact", "normal", "detailed"}, description: "How much each ticket card shows"},
+		{key: "ui.column_width", label: "Column Width", kind: "text", description: "Preferred column width in characters"},
+		{key: "ui.ticket_height", label: "Ticket Height", kind: "text", description: "Height of a ticket card in lines"},
+		{key: "ui.refresh_interval", label: "Refresh Interval", kind: "text", description: "Seconds between board refreshes"},
+		{key: "ui.output_preview", label: "Output Preview", kind: "toggle", description: "Show the last lines of the selected card's agent beside the board"},
+		{key: "ui.file_feed", label: "File Feed", kind: "toggle", description: "Watch the files agents change and list them beside the board"},
+		{key: "ui.split", label: "Split View", kind: "toggle", description: "Keep the board visible beside the agent pane instead of switching to it full screen"},
+		{key: "ui.split_direction", label: "Split Direction", kind: "choice", options: []string{"right", "bottom"}, description: "Where the agent pane goes in split view"},
+		{key: "ui.split_ratio", label: "Split Ratio", kind: "text", description: "Percentage of the screen given to the agent pane (20-80)"},
+		{key: "filter_project", label: "Filter Project", kind: "project", description: "Show only tickets from a specific project"},
+		{key: "telemetry.enabled", label: "Tracing", kind: "toggle", description: "Export OpenTelemetry spans for agent sessions, git and control requests (applies on restart)"},
+		{key: "telemetry.endpoint", label: "OTLP Endpoint", kind: "text", description: "OTLP/HTTP collector base URL (applies on restart)", placeholder: "$OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318"},
+		{key: "logging.level", label: "Log Level", kind: "choice", options: []string{"debug", "info", "warn", "error"}, description: "How much goes to logs/app.log in the config directory (applies on restart)"},
+		{key: "storage.encryption", label: "Ticket Encryption", kind: "choice", options: []string{"none", "passphrase", "keychain"}, description: "Encrypt ticket files with a passphrase or a keychain key (applies on restart)"},
+		{key: "sync.enabled", label: "Team Sync", kind: "toggle", description: "Share boards with the team through the sync branch (applies on restart)"},
+		{key: "sync.interval", label: "Sync Interval", kind: "text", description: "Seconds between team syncs; 0 syncs only on start (applies on restart)", placeholder: "60"},
+		{key: "vault.path", label: "Vault Path", kind: "text", description: "Obsidian vault that 'openkanban vault export' writes to", placeholder: "~/notes"},
+		{key: "vault.folder", label: "Vault Folder", kind: "text", description: "Folder inside the vault for exported notes"},
+	}
+}
+
+func (m *Model) agentSettings() []settingsField {
+	fields := []settingsField{
+		{key: "defaults.auto_spawn_agent", label: "Auto Spawn", kind: "toggle", description: "Spawn the ticket's agent when it is moved into a Spawn On column"},
+		{key: "defaults.spawn_on", label: "Spawn On", kind: "text", description: "Columns that spawn the agent on a move, comma-separated: in_progress, review", placeholder: "none"},
+		{key: "defaults.review_prompt", label: "Review Prompt", kind: "toggle", description: "Open the rendered init prompt in $EDITOR before it is sent to an agent"},
+		{key: "opencode.server_enabled", label: "OpenCode Server", kind: "toggle", description: "Run an OpenCode server for status detection (applies on restart)"},
+		{key: "opencode.server_port", label: "Server Port", kind: "text", description: "Port for the OpenCode server (applies on restart)"},
+		{key: "opencode.poll_interval", label: "Poll Interval", kind: "text", description: "Seconds between agent status checks"},
+		{key: "opencode.startup_timeout", label: "Startup Timeout", kind: "text", description: "Seconds to wait for the OpenCode server to start"},
+	}
+	for _, name := range m.getAgentNames() {
+		prefix := "agents." + name + "."
+		fields = append(fields,
+			settingsField{key: prefix + "command", label: name + " command", kind: "text", description: "Executable run for the " + name + " agent"},
+			settingsField{key: prefix + "args", label: name + " args", kind: "text", description: "Arguments for " + name + ", comma-separated", placeholder: "none"},
+			settingsField{key: prefix + "status_file", label: name + " status", kind: "text", description: "Status file " + name + " writes in the worktree", placeholder: "none"},
+		)
+	}
+	return fields
+}
+
+func (m *Model) gitSettings() []settingsField {
+	return []settingsField{
+		{key: "defaults.auto_create_branch", label: "Create Branch", kind: "toggle", description: "Create the branch and worktree when a ticket moves to In Progress; off waits for the first agent spawn"},
+		{key: "defaults.branch_prefix", label: "Branch Prefix", kind: "text", description: "Prefix for generated branch names (e.g. task/, feature/)", placeholder: "none"},
+		{key: "defaults.branch_naming", label: "Branch Naming", kind: "choice", options: []string{"template", "ai", "prompt"}, description: "How branch names are chosen"},
+		{key: "defaults.branch_template", label: "Branch Template", kind: "text", description: "Template using {prefix}, {slug} and {key}, e.g. {prefix}{key}-{slug}"},
+		{key: "defaults.slug_max_length", label: "Slug Length", kind: "text", description: "Maximum length of the title slug in branch names"},
+		{key: "defaults.worktree_base", label: "Worktree Base", kind: "text", description: "Directory for worktrees; empty uses <repo>-worktrees", placeholder: "next to repository"},
+		{key: "cleanup.delete_worktree", label: "Delete Worktree", kind: "toggle", description: "Remove the worktree when deleting a ticket"},
+		{key: "cleanup.delete_branch", label: "Delete Branch", kind: "toggle", description: "Delete the branch when deleting a ticket"},
+		{key: "cleanup.force_worktree_removal", label: "Force Cleanup", kind: "toggle", description: "Remove worktrees even with uncommitted changes"},
+		{key: "cleanup.after_merge", label: "After Merge", kind: "choice", options: []string{"ask", "always", "never"}, description: "Remove the worktree and branch once a ticket is merged"},
+		{key: "cleanup.on_archive", label: "On Archive", kind: "choice", options: []string{"ask", "always", "never"}, description: "Remove the worktree when archiving a ticket"},
+		{key: "cleanup.disk_limit_gb", label: "Disk Limit", kind: "text", description: "Warn when worktrees use more GiB than this; 0 disables", placeholder: "0"},
+		{key: "pull_request.remote", label: "PR Remote", kind: "text", description: "Remote ticket branches are pushed to", placeholder: "origin"},
+		{key: "pull_request.provider", label: "PR Provider", kind: "choice", options: []string{"auto", "github", "gitlab", "gitea"}, description: "Code host for pull requests; auto detects from the remote"},
+		{key: "pull_request.draft", label: "Draft PRs", kind: "toggle", description: "Open pull requests as drafts"},
+		{key: "pull_request.include_commits", label: "PR Commits", kind: "toggle", description: "List the branch's commit messages in the PR body"},
+		{key: "pull_request.push_on_complete", label: "Auto Push", kind: "toggle", description: "Push the ticket branch when its agent completes"},
+		{key: "pull_request.status_interval", label: "PR Status", kind: "text", description: "Seconds between checks of pull request reviews and CI; 0 disables", placeholder: "60"},
+		{key: "commit_refs.enabled", label: "Commit Refs", kind: "toggle", description: "Note and close tickets referenced in commit messages (okb:<ticket> done)"},
+		{key: "commit_refs.prefix", label: "Ref Prefix", kind: "text", description: "Marker for ticket references in commit messages"},
+		{key: "commit_refs.scan_base", label: "Scan Base", kind: "toggle", description: "Also read recent commits on each project's default branch"},
+		{key: "editor.command", label: "Editor", kind: "text", description: "Command worktrees are opened in (e.g. code, zed, nvim)", placeholder: "$VISUAL or $EDITOR"},
+		{key: "editor.workspace", label: "Workspace File", kind: "toggle", description: "Open VS Code style editors on a .code-workspace file generated per worktree"},
+	}
+}
+
+func (m *Model) terminalSettings() []settingsField {
+	return []settingsField{
+		{key: "ui.scrollback_lines", label: "Scrollback", kind: "text", description: "Lines of agent output kept for scrolling back (new sessions)"},
+		{key: "tmux.enabled", label: "Use tmux", kind: "toggle", description: "Run new agents in tmux windows, one session per project, instead of the embedded terminal"},
+		{key: "tmux.session_prefix", label: "Session Prefix", kind: "text", description: "Prepended to the project name to name its tmux session", placeholder: "none"},
+	}
+}
+
+func (m *Model) notifySettings() []settingsField {
+	return []settingsField{
+		{key: "notifications.desktop.enabled", label: "Desktop", kind: "toggle", description: "Show desktop notifications (notify-send, osascript or terminal-notifier)"},
+		{key: "notifications.desktop.agent_waiting", label: "Agent Waiting", kind: "toggle", description: "Notify when an agent waits for input"},
+		{key: "notifications.desktop.agent_completed", label: "Agent Finished", kind: "toggle", description: "Notify when an agent finishes"},
+		{key: "notifications.desktop.agent_failed", label: "Agent Failed", kind: "toggle", description: "Notify when an agent fails"},
+		{key: "notifications.desktop.errors", label: "Errors", kind: "toggle", description: "Notify about any other failure shown in the status bar"},
+		{key: "notifications.quiet_hours.start", label: "Quiet From", kind: "text", description: "Hold desktop and webhook notifications back from this time (HH:MM)", placeholder: "off"},
+		{key: "notifications.quiet_hours.end", label: "Quiet Until", kind: "text", description: "End of quiet hours (HH:MM)", placeholder: "off"},
+		{key: "notifications.muted", label: "Muted", kind: "toggle", description: "Hold desktop and webhook notifications back, keeping them in the history"},
+	}
+}
+
+func (m *Model) themeSettings() []settingsField {
+	fields := []settingsField{
+		{key: "ui.theme", label: "Theme", kind: "theme", description: "Color theme; moving through the list previews it"},
+		{key: "ui.symbolic_indicators", label: "Symbolic States", kind: "toggle", description: "Mark working agents with a static ● and badge on the card instead of only the spinner"},
+	}
+	for _, name := range config.ThemeColorNames() {
+		fields = append(fields, settingsField{
+			key:         "ui.custom_colors." + name,
+			label:       strings.ToUpper(name[:1]) + name[1:],
+			kind:        "text",
+			description: "Override the theme's " + name + " color (#rrggbb); r resets",
+			placeholder: "theme",
+		})
+	}
+	return fields
+}
+
+func (m *Model) keySettings() []settingsField {
+	fields := []settingsField{
+		{key: "keybindings.preset", label: "Preset", kind: "choice", options: keymap.Presets(), description: "Base key layout; bindings below override it"},
+	}
+	for _, info := range keymap.Actions {
+		fields = append(fields, settingsField{
+			key:         bindingKeyPrefix + string(info.Action),
+			label:       string(info.Action),
+			kind:        "text",
+			description: info.Description + "; comma-separate keys, empty unbinds, r resets",
+		})
+	}
+	return fields
+}
+
+func (m *Model) settingsFields() []settingsField {
+	return settingsPages[m.settingsPage].fields(m)
+}
+
+func (m *Model) openSettings() {
+	m.mode = ModeSettings
+	m.settingsPage = 0
+	m.settingsIndex = 0
+	m.settingsOffset = 0
+	m.settingsEditing = false
+	m.settingsError = ""
+}
+
+// settingsVisibleRows is how many fields fit in the overlay at once
+func (m *Model) settingsVisibleRows() int {
+	return max(m.height-18, 3)
+}
+
+func (m *Model) scrollSettingsToCursor() {
+	rows := m.settingsVisibleRows()
+	if m.settingsIndex < m.settingsOffset {
+		m.settingsOffset = m.settingsIndex
+	}
+	if m.settingsIndex >= m.settingsOffset+rows {
+		m.settingsOffset = m.settingsIndex - rows + 1
+	}
+}
+
+func (m *Model) switchSettingsPage(page int) {
+	m.settingsPage = (page + len(settingsPages)) % len(settingsPages)
+	m.settingsIndex = 0
+	m.settingsOffset = 0
+}
+
+func (m *Model) handleSettingsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsEditing {
+		return m.handleSettingsEdit(msg)
+	}
+
+	fields := m.settingsFields()
+	m.settingsError = ""
+
+	switch msg.String() {
+	case "j", "down":
+		m.settingsIndex = min(m.settingsIndex+1, len(fields)-1)
+	case "k", "up":
+		m.settingsIndex = max(m.settingsIndex-1, 0)
+	case "g", "home":
+		m.settingsIndex = 0
+	case "G", "end":
+		m.settingsIndex = len(fields) - 1
+	case "tab", "l", "right":
+		m.switchSettingsPage(m.settingsPage + 1)
+	case "shift+tab", "h", "left":
+		m.switchSettingsPage(m.settingsPage - 1)
+	case "enter", " ":
+		return m.enterSettingsEdit()
+	case "r":
+		m.resetSetting(fields[m.settingsIndex])
+	case "esc", "q":
+		m.mode = ModeNormal
+	}
+
+	m.scrollSettingsToCursor()
+	return m, nil
+}
+
+func (m *Model) handleSettingsEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := m.settingsFields()[m.settingsIndex]
+
+	if field.kind == "theme" {
+		return m.handleThemeNav(msg)
+	}
+
+	switch msg.String() {
+	case "enter":
+		value := m.settingsInput.Value()
+		if strings.HasPrefix(field.key, bindingKeyPrefix) {
+			value = keyListJSON(value)
+		}
+		if err := m.saveSetting(field, value); err != nil {
+			m.settingsError = err.Error()
+			return m, nil
+		}
+		m.settingsEditing = false
+		m.settingsInput.Blur()
+		return m, nil
+	case "esc":
+		m.settingsEditing = false
+		m.settingsError = ""
+		m.settingsInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.settingsInput, cmd = m.settingsInput.Update(msg)
+	return m, cmd
+}
+
+// handleThemeNav previews each theme as the cursor moves through the list.
+// Enter saves the highlighted theme; esc restores the saved one.
+func (m *Model) handleThemeNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	themes := config.ThemeNames()
+	if len(themes) == 0 {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		m.themeListIndex++
+		if m.themeListIndex >= len(themes) {
+			m.themeListIndex = 0
+		}
+		m.previewTheme(themes[m.themeListIndex])
+	case "k", "up":
+		m.themeListIndex--
+		if m.themeListIndex < 0 {
+			m.themeListIndex = len(themes) - 1
+		}
+		m.previewTheme(themes[m.themeListIndex])
+	case "enter":
+		field := m.settingsFields()[m.settingsIndex]
+		if err := m.saveSetting(field, themes[m.themeListIndex]); err != nil {
+			m.settingsError = err.Error()
+			m.previewTheme(m.config.UI.Theme)
+		}
+		m.settingsEditing = false
+	case "esc":
+		m.previewTheme(m.config.UI.Theme)
+		m.settingsEditing = false
+	}
+
+	return m, nil
+}
+
+// previewTheme recolors the UI without changing the config
+func (m *Model) previewTheme(name string) {
+	m.theme = config.GetTheme(name, m.config.UI.CustomColors)
+	m.colors = newUIColors(m.theme)
+}
+
+func (m *Model) enterSettingsEdit() (tea.Model, tea.Cmd) {
+	field := m.settingsFields()[m.settingsIndex]
+
+	switch field.kind {
+	case "project":
+		m.filterInput.SetValue(m.filterQuery)
+		m.filterInput.Focus()
+		m.mode = ModeFilter
+		return m, textinput.Blink
+
+	case "toggle":
+		enabled := m.settingsValue(field) == "true"
+		if err := m.saveSetting(field, strconv.FormatBool(!enabled)); err != nil {
+			m.settingsError = err.Error()
+		}
+		return m, nil
+
+	case "choice":
+		if len(field.options) == 0 {
+			return m, nil
+		}
+		current := m.settingsValue(field)
+		next := field.options[0]
+		for i, option := range field.options {
+			if option == current {
+				next = field.options[(i+1)%len(field.options)]
+				break
+			}
+		}
+		if err := m.saveSetting(field, next); err != nil {
+			m.settingsError = err.Error()
+		}
+		return m, nil
+
+	case "theme":
+		themes := config.ThemeNames()
+		m.themeListIndex = 0
+		for i, t := range themes {
+			if t == m.config.UI.Theme {
+				m.themeListIndex = i
+				break
+			}
+		}
+		m.settingsEditing = true
+		return m, nil
+
+	default:
+		m.settingsEditing = true
+		m.settingsInput.SetValue(m.settingsValue(field))
+		m.settingsInput.Width = m.settingsValueWidth()
+		m.settingsInput.CursorEnd()
+		m.settingsInput.Focus()
+		return m, textinput.Blink
+	}
+}
+
+// settingsValue returns the current value of field as it is edited: lists
+// are comma-separated and booleans are "true" or "false".
+func (m *Model) settingsValue(field settingsField) string {
+	if field.kind == "project" {
+		if count := len(m.filterProjectIDs); count > 0 {
+			return fmt.Sprintf("%d selected", count)
+		}
+		return "All Projects"
+	}
+
+	if action, ok := strings.CutPrefix(field.key, bindingKeyPrefix); ok {
+		return strings.Join(m.keys.Keys(keymap.Action(action)), ", ")
+	}
+
+	value, err := m.config.Get(field.key)
+	if err != nil {
+		return ""
+	}
+	if strings.HasPrefix(value, "[") {
+		var list []string
+		if json.Unmarshal([]byte(value), &list) == nil {
+			return strings.Join(list, ", ")
+		}
+	}
+	return value
+}
+
+// settingsDisplay formats field's value for the settings list
+func (m *Model) settingsDisplay(field settingsField) string {
+	if action, ok := strings.CutPrefix(field.key, bindingKeyPrefix); ok {
+		return m.keys.Label(keymap.Action(action))
+	}
+
+	value := m.settingsValue(field)
+	switch {
+	case field.kind == "toggle" && value == "true":
+		return "On"
+	case field.kind == "toggle":
+		return "Off"
+	case value == "" && field.placeholder != "":
+		return "(" + field.placeholder + ")"
+	}
+	return value
+}
+
+// settingsEnvVar returns the environment variable overriding field, if any
+func (m *Model) settingsEnvVar(field settingsField) string {
+	for _, o := range m.config.EnvOverrides() {
+		if o.Key == field.key {
+			return o.Var
+		}
+	}
+	return ""
+}
+
+// keyListJSON turns "n, a" into a JSON list so a binding is stored as keys
+// rather than a single string.
+func keyListJSON(value string) string {
+	keys := []string{}
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	data, _ := json.Marshal(keys)
+	return string(data)
+}
+
+func (m *Model) saveSetting(field settingsField, value string) error {
+	err := m.updateSetting(field.key, func(c *config.Config) error {
+		return c.Set(field.key, value)
+	})
+	if err == nil {
+		m.notify(field.label + ": " + m.settingsDisplay(field))
+	}
+	return err
+}
+
+// resetSetting restores the default for field. Keybindings and custom colors
+// are removed so the preset or theme applies again.
+func (m *Model) resetSetting(field settingsField) {
+	if field.key == "" || field.kind == "project" {
+		return
+	}
+
+	var err error
+	if strings.HasPrefix(field.key, bindingKeyPrefix) || strings.HasPrefix(field.key, "ui.custom_colors.") {
+		err = m.updateSetting(field.key, func(c *config.Config) error {
+			return c.Unset(field.key)
+		})
+	} else {
+		var value string
+		value, err = config.DefaultConfig().Get(field.key)
+		if err != nil {
+			err = fmt.Errorf("%s has no default", field.key)
+		} else {
+			err = m.updateSetting(field.key, func(c *config.Config) error {
+				return c.Set(field.key, value)
+			})
+		}
+	}
+
+	if err != nil {
+		m.settingsError = err.Error()
+		return
+	}
+	m.notify(field.label + " reset")
+}
+
+// updateSetting applies change to the config file and saves it, then to the
+// running board. A change is refused when it introduces validation errors.
+// Keys overridden by environment variables are saved but keep their
+// override until restart.
+func (m *Model) updateSetting(key string, change func(*config.Config) error) error {
+	path, err := m.configFile()
+	if err != nil {
+		return err
+	}
+	fileCfg, err := config.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	before := fileCfg.Validate()
+	if err := change(fileCfg); err != nil {
+		return err
+	}
+	if err := newValidationError(before, fileCfg.Validate()); err != nil {
+		return err
+	}
+	if err := fileCfg.Save(path); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if m.config.IsEnvOverride(key) {
+		return nil
+	}
+	if err := change(m.config); err != nil {
+		return err
+	}
+	m.applyConfig()
+	switch key {
+	case "ui.group_by_project", "ui.ticket_order", "ui.project_order":
+		m.refreshColumnTickets()
+	}
+	if strings.HasPrefix(key, "ui.split") {
+		m.setSplitView(m.config.UI.Split)
+	}
+	if key == "ui.card_density" {
+		m.setCardDensity(m.config.UI.CardDensity)
+	}
+	if key == "ui.output_preview" {
+		m.outputPreview = m.config.UI.OutputPreview
+		m.ensureColumnVisible()
+	}
+	if key == "ui.file_feed" {
+		m.fileFeed = m.config.UI.FileFeed
+		m.ensureColumnVisible()
+	}
+	if key == "ui.sidebar_visible" {
+		m.sidebarVisible = m.config.UI.SidebarVisible
+		if !m.sidebarVisible {
+			m.sidebarFocused = false
+		}
+	}
+	return nil
+}
+
+// newValidationError returns the first error in after that was not already
+// in before, so unrelated problems in the file do not block every change.
+func newValidationError(before, after *config.ValidationResult) error {
+	existing := make(map[config.ValidationError]bool)
+	for _, e := range before.Errors {
+		e.Value = nil
+		existing[e] = true
+	}
+	for _, e := range after.Errors {
+		e.Value = nil
+		if existing[e] {
+			continue
+		}
+		key := e.Section
+		if e.Field != "" {
+			key += "." + e.Field
+		}
+		return fmt.Errorf("%s %s", key, e.Message)
+	}
+	return nil
+}
+
+func (m *Model) handleSettingsMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || m.settingsEditing {
+		return m, nil
+	}
+
+	view := m.renderSettingsView()
+	top := (m.height-lipgloss.Height(view))/2 + settingsContentTop
+	left := (m.width-lipgloss.Width(view))/2 + settingsContentLeft
+
+	if msg.Y == top+settingsTabsRow {
+		x := left
+		for i, tab := range m.settingsTabLabels() {
+			w := lipgloss.Width(tab)
+			if msg.X >= x && msg.X < x+w {
+				m.switchSettingsPage(i)
+				return m, nil
+			}
+			x += w + lipgloss.Width(settingsTabSeparator)
+		}
+		return m, nil
+	}
+
+	row := msg.Y - top - settingsFieldsRow
+	fields := m.settingsFields()
+	if row >= 0 && row < m.settingsVisibleRows() && m.settingsOffset+row < len(fields) {
+		m.settingsIndex = m.settingsOffset + row
+		m.settingsError = ""
+		return m.enterSettingsEdit()
+	}
+
+	return m, nil
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// worktreeSetup tracks a new worktree being prepared: the project's files
+// being brought over, then its setup commands running.
+// The commands run off the UI goroutine; rendering reads the latest progress.
+type worktreeSetup struct {
+	mu       sync.Mutex
+	progress project.SetupProgress
+}
+
+func (s *worktreeSetup) set(p project.SetupProgress) {
+	s.mu.Lock()
+	s.progress = p
+	s.mu.Unlock()
+}
+
+func (s *worktreeSetup) get() project.SetupProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.progress
+}
+
+type setupDoneMsg struct {
+	ticketID board.TicketID
+	title    string
+	err      error
+}
+
+// trackSetup registers the setup about to run for a ticket
+func (m *Model) trackSetup(ticketID board.TicketID) *worktreeSetup {
+	if m.setups == nil {
+		m.setups = make(map[board.TicketID]*worktreeSetup)
+	}
+	s := &worktreeSetup{}
+	m.setups[ticketID] = s
+	return s
+}
+
+// runWorktreeSetup prepares the ticket's freshly created worktree in the
+// background
+func (m *Model) runWorktreeSetup(ticket *board.Ticket, proj *project.Project) tea.Cmd {
+	if !proj.HasWorktreeSetup() {
+		return nil
+	}
+	s := m.trackSetup(ticket.ID)
+	ticketID, title := ticket.ID, ticket.Title
+	path, branch := ticket.WorktreePath, ticket.BranchName
+	return func() tea.Msg {
+		err := prepareWorktree(proj, path, branch, ticketID, s)
+		return setupDoneMsg{ticketID: ticketID, title: title, err: err}
+	}
+}
+
+// prepareWorktree copies and links the project's untracked files into a new
+// worktree, then runs its setup commands there. Files come first so setup
+// commands can rely on them.
+func prepareWorktree(proj *project.Project, path, branch string, ticketID board.TicketID, s *worktreeSetup) error {
+	if len(proj.Settings.CopyFiles) > 0 || len(proj.Settings.LinkFiles) > 0 {
+		s.set(project.SetupProgress{Command: "copying files"})
+		if err := proj.CopyWorktreeFiles(path); err != nil {
+			return err
+		}
+	}
+	return proj.RunSetup(path, branch, string(ticketID), s.set)
+}
+
+func (m *Model) handleSetupDone(msg setupDoneMsg) (tea.Model, tea.Cmd) {
+	delete(m.setups, msg.ticketID)
+	if msg.err != nil {
+		m.notify("Setup failed for " + truncate(msg.title, 30) + ": " + msg.err.Error())
+		return m, nil
+	}
+	m.notify("Setup finished for " + truncate(msg.title, 30))
+	return m, nil
+}
+
+// setupLabel describes the running setup step, e.g. "setup 1/2 npm ci"
+func (m *Model) setupLabel(ticketID board.TicketID) (string, bool) {
+	s, ok := m.setups[ticketID]
+	if !ok {
+		return "", false
+	}
+	p := s.get()
+	switch {
+	case p.Command == "":
+		return "", false
+	case p.Step == 0:
+		return "setup: " + p.Command, true
+	}
+	return fmt.Sprintf("setup %d/%d %s", p.Step, p.Total, p.Command), true
+}
+
+// renderSetupBadge shows the running setup step on a ticket card
+func (m *Model) renderSetupBadge(ticketID board.TicketID, width int) string {
+	label, ok := m.setupLabel(ticketID)
+	if !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.info).Render(m.spinner.View() + truncate(label, max(width, 10)))
+}
+
+// renderSetupProgress shows the running setup step and its latest output in
+// the spawning dialog
+func (m *Model) renderSetupProgress(ticketID board.TicketID) string {
+	label, ok := m.setupLabel(ticketID)
+	if !ok {
+		return ""
+	}
+	out := lipgloss.NewStyle().Foreground(m.colors.info).Render("  ⚙ " + truncate(label, 50))
+	if line := m.setups[ticketID].get().Line; line != "" {
+		out += "\n  " + m.dimStyle().Render(truncate(line, 50))
+	}
+	return out + "\n\n"
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/filewatch"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+// newSpawningModel is a one-ticket board waiting for that ticket's agent
+// to start
+func newSpawningModel(t *testing.T) (*Model, *board.Ticket) {
+	t.Helper()
+	m := newBenchModel(t, 1)
+	ticket := m.globalStore.All()[0]
+	m.mode = ModeSpawning
+	m.spawningTicketID = ticket.ID
+	return m, ticket
+}
+
+func TestSpawning_KeepsToastTick(t *testing.T) {
+	m, _ := newSpawningModel(t)
+	if _, cmd := m.Update(notificationMsg(time.Now())); cmd == nil {
+		t.Error("notification tick dropped while spawning; toasts would never expire")
+	}
+	if m.mode != ModeSpawning {
+		t.Errorf("mode = %v; want still spawning", m.mode)
+	}
+}
+
+func TestSpawning_TestsDone(t *testing.T) {
+	m, ticket := newSpawningModel(t)
+	m.testing = map[board.TicketID]bool{ticket.ID: true}
+
+	m.Update(testsDoneMsg{ticketID: ticket.ID, title: ticket.Title, result: project.TestResult{Passed: true, Pass: 3}})
+	if m.testing[ticket.ID] {
+		t.Error("tests finishing during a spawn left the ticket testing")
+	}
+	if ticket.Tests == nil || !ticket.Tests.Passed {
+		t.Errorf("ticket tests = %+v; want the passing run recorded", ticket.Tests)
+	}
+}
+
+func TestSpawning_MergeResult(t *testing.T) {
+	m, ticket := newSpawningModel(t)
+	s := &mergeState{ticketID: ticket.ID, branch: "task/x", base: "main", sync: true, paused: true, running: true}
+	m.merge = s
+
+	m.Update(mergeResultMsg{ticketID: ticket.ID})
+	if s.running || s.paused {
+		t.Errorf("merge state = %+v; want the sync finished and its agent resumed", s)
+	}
+	if m.merge != nil {
+		t.Error("a finished sync should clear the merge state")
+	}
+}
+
+func TestSpawning_FileFeed(t *testing.T) {
+	m, ticket := newSpawningModel(t)
+	w, err := filewatch.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	m.fileWatchers = map[board.TicketID]*filewatch.Watcher{ticket.ID: nil}
+
+	if _, cmd := m.Update(fileWatchStartedMsg{ticketID: ticket.ID, watcher: w}); cmd == nil || m.fileWatchers[ticket.ID] != w {
+		t.Fatal("a watcher started during a spawn was not tracked")
+	}
+	change := filewatch.Change{Path: "main.go", Op: filewatch.Modified, At: time.Now()}
+	if _, cmd := m.Update(fileChangesMsg{ticketID: ticket.ID, watcher: w, changes: []filewatch.Change{change}, ok: true}); cmd == nil {
+		t.Error("the watch loop stopped during a spawn")
+	}
+	if feed := m.fileFeeds[ticket.ID]; feed == nil || !feed.files["main.go"] {
+		t.Error("changes during a spawn were not added to the feed")
+	}
+}
+
+func TestStartSpawn_RefusesPendingSpawn(t *testing.T) {
+	m := newBenchModel(t, 1)
+	ticket := m.globalStore.All()[0]
+	m.spawnPending = map[board.TicketID]bool{ticket.ID: true}
+
+	if _, cmd, err := m.startSpawn(ticket, true); err == nil || cmd != nil {
+		t.Fatal("a second spawn started while the first was still pending")
+	}
+
+	m.Update(spawnErrorMsg{ticketID: ticket.ID, err: "boom", background: true})
+	if m.spawnPending[ticket.ID] {
+		t.Error("a failed spawn stayed pending; the ticket could never spawn again")
+	}
+}
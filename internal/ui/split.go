@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// splitBottom reports whether split view puts the agent pane below the board
+// rather than to its right
+func (m *Model) splitBottom() bool {
+	return m.config.UI.SplitDirection == "bottom"
+}
+
+// splitPaneRect returns where the agent pane's region sits on screen in
+// split view, between the header and the status bar
+func (m *Model) splitPaneRect() (x, y, width, height int) {
+	top := m.headerHeight()
+	bodyHeight := max(m.height-top-1, 0)
+	ratio := m.config.UI.SplitRatio
+	if m.splitBottom() {
+		height = bodyHeight * ratio / 100
+		return 0, top + bodyHeight - height, m.width, height
+	}
+	width = m.width * ratio / 100
+	return m.width - width, top, width, bodyHeight
+}
+
+// paneOrigin returns the screen position of the agent terminal's top-left
+// cell in split view, inside the region's border and title line
+func (m *Model) paneOrigin() (x, y int) {
+	x, y, _, _ = m.splitPaneRect()
+	if m.splitBottom() {
+		return x, y + 2
+	}
+	return x + 1, y + 1
+}
+
+// paneSize is the terminal size agents get: the split region when split
+// view is on, the whole screen below the agent view's header otherwise
+func (m *Model) paneSize() (width, height int) {
+	if !m.splitView {
+		return m.width, m.height - 2
+	}
+	_, _, w, h := m.splitPaneRect()
+	if m.splitBottom() {
+		return w, max(h-2, 1)
+	}
+	return max(w-1, 1), max(h-1, 1)
+}
+
+// resizePanes fits every agent terminal to the current layout
+func (m *Model) resizePanes() {
+	width, height := m.paneSize()
+	for _, pane := range m.panes {
+		pane.SetSize(width, height)
+	}
+}
+
+// boardHeight is the screen height left to the board, header and status bar
+func (m *Model) boardHeight() int {
+	if !m.splitView || !m.splitBottom() {
+		return m.height
+	}
+	_, _, _, h := m.splitPaneRect()
+	return m.height - h
+}
+
+func (m *Model) toggleSplit() (tea.Model, tea.Cmd) {
+	m.setSplitView(!m.splitView)
+	if m.splitView {
+		m.notify("Split view on")
+	} else {
+		m.notify("Split view off")
+	}
+	return m, nil
+}
+
+func (m *Model) setSplitView(on bool) {
+	m.splitView = on
+	m.ensureColumnVisible()
+	m.ensureTicketVisible()
+	m.resizePanes()
+}
+
+// splitPaneID returns the agent shown beside the board: the focused one, or
+// else the selected ticket's when it is running
+func (m *Model) splitPaneID() board.TicketID {
+	if m.mode == ModeAgentView && m.focusedPane != "" {
+		return m.focusedPane
+	}
+	if ticket := m.selectedTicket(); ticket != nil {
+		if pane, ok := m.panes[ticket.ID]; ok && pane.Running() {
+			return ticket.ID
+		}
+	}
+	return ""
+}
+
+// focusPane moves keyboard focus from the board to the agent pane. Outside
+// split view it is the same as attaching.
+func (m *Model) focusPane() (tea.Model, tea.Cmd) {
+	if !m.splitView {
+		return m.attachToAgent()
+	}
+	if m.splitPaneID() == "" {
+		m.notify("No agent running — press 's' to spawn")
+		return m, nil
+	}
+	return m.attachToAgent()
+}
+
+// inSplitPane reports whether a screen position falls in the agent pane's
+// region of split view
+func (m *Model) inSplitPane(x, y int) bool {
+	if !m.splitView {
+		return false
+	}
+	px, py, w, h := m.splitPaneRect()
+	return x >= px && x < px+w && y >= py && y < py+h
+}
+
+// handleSplitPaneMouse passes a mouse event in the pane region to the agent,
+// focusing the pane on click
+func (m *Model) handleSplitPaneMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	id := m.splitPaneID()
+	pane, ok := m.panes[id]
+	if !ok {
+		return m, nil
+	}
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft && m.mode != ModeAgentView {
+		if pane.Tmux() != nil {
+			return m, nil
+		}
+		m.mode = ModeAgentView
+		m.focusedPane = id
+	}
+	x, y := m.paneOrigin()
+	msg.X -= x
+	msg.Y -= y
+	pane.HandleMouse(msg)
+	return m, nil
+}
+
+// withSplitPane lays the board region out beside or above the agent pane
+func (m *Model) withSplitPane(boardView string) string {
+	_, _, w, h := m.splitPaneRect()
+	pane := m.renderSplitPane(w, h)
+	if m.splitBottom() {
+		boardRegion := lipgloss.NewStyle().
+			Height(max(m.height-m.headerHeight()-1-h, 0)).
+			MaxHeight(max(m.height-m.headerHeight()-1-h, 0)).
+			Render(boardView)
+		return lipgloss.JoinVertical(lipgloss.Left, boardRegion, pane)
+	}
+	// Cut rather than wrap anything wider than the board region, then pad
+	// it out so the pane lines up
+	boardRegion := lipgloss.NewStyle().MaxWidth(m.width - w).MaxHeight(h).Render(boardView)
+	boardRegion = lipgloss.NewStyle().Width(m.width - w).Render(boardRegion)
+	return lipgloss.JoinHorizontal(lipgloss.Top, boardRegion, pane)
+}
+
+func (m *Model) renderSplitPane(width, height int) string {
+	focused := m.mode == ModeAgentView
+	id := m.splitPaneID()
+	innerWidth, innerHeight := m.paneSize()
+
+	borderColor := m.colors.surface
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
+	if focused {
+		borderColor = m.colors.primary
+		titleStyle = lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	}
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+
+	var title, hint, content string
+	if pane, ok := m.panes[id]; ok {
+		title = "▶ Agent"
+		if ticket, _ := m.globalStore.Get(id); ticket != nil {
+			title = "▶ " + ticket.Title
+			if ticket.AgentType != "" {
+				title += " · " + ticket.AgentType
+			}
+		}
+		if focused {
+			hint = keyStyle.Render(m.keyHint(keymap.DetachAgent)) + m.dimStyle().Render(" board")
+		} else {
+			hint = keyStyle.Render(m.keyHint(keymap.FocusPane)) + m.dimStyle().Render(" focus")
+		}
+		content = m.paneView(pane)
+	} else {
+		title = "No agent"
+		content = lipgloss.Place(innerWidth, innerHeight, lipgloss.Center, lipgloss.Center,
+			m.dimStyle().Render("No agent running for this ticket\n"+
+				m.keyHint(keymap.SpawnAgent)+" to spawn one"))
+	}
+
+	title = titleStyle.Render(truncate(title, max(innerWidth-lipgloss.Width(hint)-1, 1)))
+	spacing := max(innerWidth-lipgloss.Width(title)-lipgloss.Width(hint), 1)
+	header := title + strings.Repeat(" ", spacing) + hint
+
+	style := lipgloss.NewStyle().BorderForeground(borderColor).BorderStyle(lipgloss.NormalBorder())
+	if m.splitBottom() {
+		style = style.BorderTop(true).Width(width).MaxHeight(height)
+	} else {
+		style = style.BorderLeft(true).Height(height).MaxHeight(height).Width(width - 1)
+	}
+	return style.Render(lipgloss.NewStyle().MaxWidth(innerWidth).Render(header + "\n" + content))
+}
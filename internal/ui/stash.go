@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// offerStash spawns the ticket's agent, first offering to stash uncommitted
+// changes in its worktree so the new agent doesn't trample half-finished
+// work. Stashed changes are restored when the agent exits.
+func (m *Model) offerStash(ticket *board.Ticket) (tea.Model, tea.Cmd) {
+	if ticket.Stash != "" || ticket.WorktreePath == "" {
+		return m.spawnTicketAgent(ticket)
+	}
+	if _, running := m.panes[ticket.ID]; running {
+		return m.spawnTicketAgent(ticket)
+	}
+	if _, err := os.Stat(ticket.WorktreePath); err != nil {
+		return m.spawnTicketAgent(ticket)
+	}
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		return m.spawnTicketAgent(ticket)
+	}
+	if dirty, _ := mgr.HasUncommittedChanges(ticket.WorktreePath); !dirty {
+		return m.spawnTicketAgent(ticket)
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = "The worktree has uncommitted changes. Stash them until the agent exits?"
+	m.confirmFn = func() tea.Cmd {
+		commit, err := git.Stash(ticket.WorktreePath, "openkanban: "+ticket.Title)
+		if err != nil {
+			m.notify(err.Error())
+			return nil
+		}
+		ticket.Stash = commit
+		m.saveTicket(ticket)
+		_, cmd := m.spawnTicketAgent(ticket)
+		return cmd
+	}
+	m.declineFn = func() tea.Cmd {
+		_, cmd := m.spawnTicketAgent(ticket)
+		return cmd
+	}
+	return m, nil
+}
+
+// restoreStash brings back changes offerStash set aside for an agent that
+// has exited, reporting whether there were any. If they no longer apply
+// cleanly they stay in git's stash list.
+func (m *Model) restoreStash(ticket *board.Ticket) bool {
+	if ticket.Stash == "" {
+		return false
+	}
+	commit := ticket.Stash
+	ticket.Stash = ""
+	m.saveTicket(ticket)
+
+	if err := git.RestoreStash(ticket.WorktreePath, commit); err != nil {
+		m.notify(err.Error() + " (kept in git stash list)")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/techdufus/openkanban/internal/keymap"
+)
+
+// statusSegment is a piece of system state on the right of the status bar.
+// When the bar is too narrow, segments with the highest priority value are
+// dropped first.
+type statusSegment struct {
+	text     string
+	priority int
+}
+
+// SetControlSocket records the control socket the CLI reaches this instance
+// on, shown in the status bar. An empty path means CLI control is disabled.
+func (m *Model) SetControlSocket(path string) {
+	m.controlSocket = path
+}
+
+// statusSegments returns the system state shown in the status bar, in
+// display order
+func (m *Model) statusSegments() []statusSegment {
+	dim := m.dimStyle()
+	var segments []statusSegment
+
+	if name := m.currentProjectLabel(); name != "" {
+		segments = append(segments, statusSegment{
+			text:     lipgloss.NewStyle().Foreground(m.colors.secondary).Render("◈ " + name),
+			priority: 3,
+		})
+	}
+
+	running := m.RunningAgentCount()
+	agentStyle := dim
+	if running > 0 {
+		agentStyle = lipgloss.NewStyle().Foreground(m.colors.success)
+	}
+	segments = append(segments, statusSegment{
+		text:     agentStyle.Render(fmt.Sprintf("● %d running", running)),
+		priority: 1,
+	})
+
+	if starting := m.startingAgentCount(); starting > 0 {
+		segments = append(segments, statusSegment{
+			text:     lipgloss.NewStyle().Foreground(m.colors.warning).Render(fmt.Sprintf("⟳ %d starting", starting)),
+			priority: 2,
+		})
+	}
+
+	if jobs := m.busyJobs(); len(jobs) > 0 {
+		segments = append(segments, statusSegment{
+			text:     lipgloss.NewStyle().Foreground(m.colors.info).Render("⟳ " + strings.Join(jobs, ", ")),
+			priority: 4,
+		})
+	}
+
+	if m.opencodeServer != nil {
+		text := lipgloss.NewStyle().Foreground(m.colors.success).Render("opencode ●")
+		if !m.opencodeServer.IsRunning() {
+			text = lipgloss.NewStyle().Foreground(m.colors.err).Render("opencode ○")
+		}
+		segments = append(segments, statusSegment{text: text, priority: 5})
+	}
+
+	if m.autosaveEnabled() {
+		if saved := m.renderSaveState(); saved != "" {
+			segments = append(segments, statusSegment{text: saved, priority: 5})
+		}
+	}
+
+	if collab := m.renderCollabState(); collab != "" {
+		segments = append(segments, statusSegment{text: collab, priority: 3})
+	}
+
+	cli := dim.Render("⇄ cli")
+	if m.controlSocket == "" {
+		cli = lipgloss.NewStyle().Foreground(m.colors.warning).Render("⇄ cli off")
+	}
+	segments = append(segments, statusSegment{text: cli, priority: 6})
+
+	if lastErr := m.renderLastError(); lastErr != "" {
+		segments = append(segments, statusSegment{text: lastErr, priority: 0})
+	}
+	return segments
+}
+
+// currentProjectLabel names the projects the board is showing
+func (m *Model) currentProjectLabel() string {
+	switch len(m.filterProjectIDs) {
+	case 0:
+		projects := m.globalStore.Projects()
+		if len(projects) == 1 {
+			return projects[0].Name
+		}
+		return "All projects"
+	case 1:
+		for id := range m.filterProjectIDs {
+			if p := m.globalStore.GetProject(id); p != nil {
+				return p.Name
+			}
+		}
+	}
+	return fmt.Sprintf("%d projects", len(m.filterProjectIDs))
+}
+
+// startingAgentCount counts agents still being set up or launched
+func (m *Model) startingAgentCount() int {
+	count := len(m.setups)
+	if _, ok := m.setups[m.spawningTicketID]; m.spawningTicketID != "" && !ok {
+		count++
+	}
+	return count
+}
+
+// busyJobs names the git operations and external syncs running in the
+// background
+func (m *Model) busyJobs() []string {
+	var jobs []string
+	switch n := len(m.gitOps); {
+	case n == 1:
+		jobs = append(jobs, "worktree")
+	case n > 1:
+		jobs = append(jobs, fmt.Sprintf("%d worktrees", n))
+	}
+	if m.prStatusBusy {
+		jobs = append(jobs, "PRs")
+	}
+	if m.jiraBusy {
+		jobs = append(jobs, "Jira")
+	}
+	if m.linearBusy {
+		jobs = append(jobs, "Linear")
+	}
+	if len(m.teamSyncing) > 0 {
+		jobs = append(jobs, "sync")
+	}
+	return jobs
+}
+
+// renderLastError shows the newest error not yet seen in the error console,
+// with a count of any others
+func (m *Model) renderLastError() string {
+	if m.unreadErrors == 0 {
+		return ""
+	}
+	var text string
+	for i := len(m.notices) - 1; i >= 0; i-- {
+		if m.notices[i].isError {
+			text = m.notices[i].text
+			break
+		}
+	}
+	label := "✗ " + truncate(text, 40)
+	if m.unreadErrors > 1 {
+		label += fmt.Sprintf(" (+%d)", m.unreadErrors-1)
+	}
+	return lipgloss.NewStyle().
+		Foreground(m.colors.base).
+		Background(m.colors.err).
+		Padding(0, 1).
+		Render(label + " · " + m.keyHint(keymap.ErrorConsole) + " errors")
+}
+
+// renderSystemState joins the status segments that fit in width
+func (m *Model) renderSystemState(width int) string {
+	segments := m.statusSegments()
+	sep := lipgloss.NewStyle().Foreground(m.colors.overlay).Render(" │ ")
+	join := func(segments []statusSegment) string {
+		parts := make([]string, len(segments))
+		for i, s := range segments {
+			parts[i] = s.text
+		}
+		return strings.Join(parts, sep)
+	}
+
+	for len(segments) > 0 && ansi.StringWidth(join(segments)) > width {
+		drop := 0
+		for i, s := range segments {
+			if s.priority > segments[drop].priority {
+				drop = i
+			}
+		}
+		segments = append(segments[:drop], segments[drop+1:]...)
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return join(segments)
+}
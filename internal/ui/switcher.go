@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/fuzzy"
+)
+
+// switcherItem is a ticket or project offered by the quick switcher
+type switcherItem struct {
+	ticketID  board.TicketID // empty for projects
+	projectID string
+	title     string   // ticket title or project name
+	fields    []string // labels, status and project name, also searched
+	detail    string
+}
+
+// switcherMatch is an item that matched the query, with the title runes to
+// highlight
+type switcherMatch struct {
+	item      *switcherItem
+	score     int
+	positions []int
+}
+
+// switcherView is the fuzzy finder over every ticket and project
+type switcherView struct {
+	input   textinput.Model
+	items   []switcherItem
+	matches []switcherMatch
+	index   int
+	offset  int
+}
+
+func (m *Model) openSwitcher() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "Jump to ticket or project..."
+	if m.filterQuery != "" {
+		input.Placeholder = "Jump to ticket matching " + truncate(m.filterQuery, 30) + " or project..."
+	}
+	input.Prompt = "› "
+	input.CharLimit = 100
+	input.Focus()
+
+	v := &switcherView{input: input, items: m.switcherItems()}
+	v.filter()
+	m.switcher = v
+	m.mode = ModeSwitcher
+	return m, textinput.Blink
+}
+
+// switcherItems lists tickets in column order, then projects. Archived
+// tickets have no column to jump to and are left out, as are tickets the
+// board's filter expression hides.
+func (m *Model) switcherItems() []switcherItem {
+	var items []switcherItem
+	for _, col := range m.columns {
+		for _, t := range m.globalStore.GetByStatus(col.Status) {
+			if !m.ticketMatchesQuery(t) {
+				continue
+			}
+			projectName := ""
+			if proj := m.globalStore.GetProjectForTicket(t); proj != nil {
+				projectName = proj.Name
+			}
+			fields := append([]string{col.Name, string(t.Status), projectName}, t.Labels...)
+			detail := col.Name
+			if projectName != "" {
+				detail += " · " + projectName
+			}
+			if len(t.Labels) > 0 {
+				detail += " · " + strings.Join(t.Labels, ", ")
+			}
+			items = append(items, switcherItem{
+				ticketID:  t.ID,
+				projectID: t.ProjectID,
+				title:     t.Title,
+				fields:    fields,
+				detail:    detail,
+			})
+		}
+	}
+	for _, p := range m.globalStore.Projects() {
+		items = append(items, switcherItem{
+			projectID: p.ID,
+			title:     p.Name,
+			fields:    []string{"project"},
+			detail:    "project",
+		})
+	}
+	return items
+}
+
+// filter matches every whitespace-separated term of the query against an
+// item's title or one of its other fields, and orders items by their total
+// score. An empty query keeps every item in its original order.
+func (v *switcherView) filter() {
+	terms := strings.Fields(v.input.Value())
+	v.matches = v.matches[:0]
+	for i := range v.items {
+		item := &v.items[i]
+		match := switcherMatch{item: item}
+		ok := true
+		for _, term := range terms {
+			score, positions, found := fuzzy.Match(term, item.title)
+			for _, field := range item.fields {
+				if s, _, f := fuzzy.Match(term, field); f && (!found || s > score) {
+					score, positions, found = s, nil, true
+				}
+			}
+			if !found {
+				ok = false
+				break
+			}
+			match.score += score
+			match.positions = append(match.positions, positions...)
+		}
+		if ok {
+			v.matches = append(v.matches, match)
+		}
+	}
+	slices.SortStableFunc(v.matches, func(a, b switcherMatch) int {
+		return b.score - a.score
+	})
+	v.index, v.offset = 0, 0
+}
+
+func (v *switcherView) selectItem(i, height int) {
+	v.index = max(min(i, len(v.matches)-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+// switcherListHeight is the number of items that fit in the overlay
+func (m *Model) switcherListHeight() int {
+	return max(min(m.height-14, 20), 3)
+}
+
+func (m *Model) handleSwitcherMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.switcher
+	height := m.switcherListHeight()
+	switch msg.String() {
+	case "esc":
+		m.closeSwitcher()
+		return m, nil
+	case "down", "ctrl+n", "ctrl+j":
+		v.selectItem(v.index+1, height)
+		return m, nil
+	case "up", "ctrl+p", "ctrl+k":
+		v.selectItem(v.index-1, height)
+		return m, nil
+	case "enter":
+		return m.switchTo(false)
+	case "ctrl+o":
+		return m.switchTo(true)
+	}
+
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	v.filter()
+	return m, cmd
+}
+
+func (m *Model) closeSwitcher() {
+	m.switcher = nil
+	m.mode = ModeNormal
+}
+
+// switchTo jumps to the selected item: a ticket is selected on the board,
+// and attached to when openPane is set; a project becomes the only one shown
+func (m *Model) switchTo(openPane bool) (tea.Model, tea.Cmd) {
+	v := m.switcher
+	if v.index >= len(v.matches) {
+		return m, nil
+	}
+	item := v.matches[v.index].item
+	m.closeSwitcher()
+	m.sidebarFocused = false
+
+	if item.ticketID == "" {
+		m.filterProjectIDs = map[string]bool{item.projectID: true}
+		m.filterQuery = ""
+		m.refreshColumnTickets()
+		m.activeColumn, m.activeTicket = 0, 0
+		m.ensureColumnVisible()
+		m.notify("Showing " + item.title)
+		return m, nil
+	}
+
+	if !m.FocusTicket(item.ticketID) {
+		m.notify("Ticket no longer exists")
+		return m, nil
+	}
+	if openPane {
+		return m.attachToAgent()
+	}
+	return m, nil
+}
+
+func (m *Model) renderSwitcherView() string {
+	v := m.switcher
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	width := min(90, m.width-4)
+	inner := width - 6
+
+	lines := []string{
+		titleStyle.Render("Go to"),
+		"",
+		v.input.View(),
+		"",
+	}
+
+	if len(v.matches) == 0 {
+		lines = append(lines, m.dimStyle().Render("  No matches"))
+	}
+	height := m.switcherListHeight()
+	end := min(v.offset+height, len(v.matches))
+	for i := v.offset; i < end; i++ {
+		match := v.matches[i]
+		item := match.item
+
+		base := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == v.index {
+			base = base.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		icon := "  "
+		switch {
+		case item.ticketID == "":
+			icon = lipgloss.NewStyle().Foreground(m.colors.secondary).Render("◈ ")
+		case m.panes[item.ticketID] != nil && m.panes[item.ticketID].Running():
+			icon = lipgloss.NewStyle().Foreground(m.colors.success).Render("● ")
+		}
+
+		detail := "  " + item.detail
+		titleWidth := max(inner-lipgloss.Width(cursor+icon)-min(lipgloss.Width(detail), inner/2), 10)
+		title := truncate(item.title, titleWidth)
+		row := cursor + icon + highlightRunes(title, match.positions, base, base.Foreground(m.colors.warning).Underline(true))
+		row += m.dimStyle().Render(truncate(detail, max(inner-lipgloss.Width(row), 0)))
+		lines = append(lines, row)
+	}
+	if len(v.matches) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(v.matches)-end)))
+	}
+
+	lines = append(lines, "",
+		keyStyle.Render("[enter]")+m.dimStyle().Render(" Jump  ")+
+			keyStyle.Render("[ctrl+o]")+m.dimStyle().Render(" Open pane  ")+
+			keyStyle.Render("[↑/↓]")+m.dimStyle().Render(" Move  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+// highlightRunes renders text with the runes at positions in hl and the rest
+// in base. Positions past the end of text are ignored.
+func highlightRunes(text string, positions []int, base, hl lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	var b strings.Builder
+	var run []rune
+	runHL := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runHL {
+			b.WriteString(hl.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		isHL := slices.Contains(positions, i)
+		if isHL != runHL {
+			flush()
+			runHL = isHL
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// confirmSync offers to bring the selected ticket's branch up to date with
+// its base, using behavior.sync_strategy. A running agent is paused while git
+// rewrites its worktree.
+func (m *Model) confirmSync() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("sync branches") {
+		return m, nil
+	}
+	if ticket.BranchName == "" || ticket.WorktreePath == "" {
+		m.notify("Ticket has no branch to sync")
+		return m, nil
+	}
+	if m.merge != nil && m.merge.running {
+		m.notify("Wait for " + m.merge.describe() + " to finish")
+		return m, nil
+	}
+	mgr := m.worktreeMgrs[ticket.ProjectID]
+	if mgr == nil {
+		m.notify("Failed to sync: worktree manager not found")
+		return m, nil
+	}
+
+	state := &mergeState{
+		ticketID: ticket.ID,
+		project:  ticket.ProjectID,
+		title:    ticket.Title,
+		path:     ticket.WorktreePath,
+		branch:   ticket.BranchName,
+		base:     ticket.BaseBranch,
+		strategy: syncStrategy(m.config.Behavior.SyncStrategy),
+		sync:     true,
+	}
+	if state.base == "" {
+		state.base, _ = mgr.GetDefaultBranch()
+	}
+	if state.base == "" || state.base == state.branch {
+		m.notify("Ticket branch has no separate base to sync with")
+		return m, nil
+	}
+
+	if inProgress := git.InProgress(ticket.WorktreePath); inProgress != "" {
+		state.strategy = inProgress
+		conflicts, err := git.ConflictedFiles(ticket.WorktreePath)
+		if err != nil {
+			state.err = err.Error()
+		}
+		m.openConflicts(state, conflicts)
+		return m, nil
+	}
+
+	msg := strings.ToUpper(state.describe()[:1]) + state.describe()[1:] + " to sync it?"
+	if dirty, _ := mgr.HasUncommittedChanges(ticket.WorktreePath); dirty {
+		msg += " Uncommitted changes will block it."
+	}
+	pane := m.panes[ticket.ID]
+	if pane != nil && pane.Running() {
+		msg += " The agent is paused until git is done."
+	}
+
+	m.showConfirm = true
+	m.confirmMsg = msg
+	m.confirmFn = func() tea.Cmd {
+		if pane != nil && pane.Running() {
+			if err := pane.Pause(); err != nil {
+				m.notify("Failed to pause agent: " + err.Error())
+				return nil
+			}
+			state.paused = true
+		}
+		m.merge = state
+		state.running = true
+		m.notify("Starting " + state.describe() + "...")
+		return runMerge(mgr, state, false)
+	}
+	return m, nil
+}
+
+// resumeSyncedAgent continues an agent paused by confirmSync
+func (m *Model) resumeSyncedAgent(s *mergeState) {
+	if !s.paused {
+		return
+	}
+	s.paused = false
+	if pane, ok := m.panes[s.ticketID]; ok && pane.Running() {
+		if err := pane.Resume(); err != nil {
+			m.notify("Failed to resume agent: " + err.Error())
+		}
+	}
+}
+
+// syncStrategy maps behavior.sync_strategy to a git strategy, defaulting to
+// rebase
+func syncStrategy(name string) git.Strategy {
+	if git.Strategy(name) == git.StrategyMerge {
+		return git.StrategyMerge
+	}
+	return git.StrategyRebase
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+)
+
+// projectTab is a header tab showing every project, or just one
+type projectTab struct {
+	projectID string // empty for the all-projects tab
+	label     string
+	count     int
+}
+
+// projectTabs returns the header tabs: all projects first, then each
+// project. There are none with tabs turned off or fewer than two projects.
+func (m *Model) projectTabs() []projectTab {
+	projects := m.globalStore.Projects()
+	if !m.config.UI.Tabs || len(projects) < 2 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, t := range m.globalStore.All() {
+		if t.Status != board.StatusArchived {
+			counts[t.ProjectID]++
+		}
+	}
+	tabs := []projectTab{{label: "All"}}
+	for _, p := range projects {
+		tabs = append(tabs, projectTab{projectID: p.ID, label: p.Name, count: counts[p.ID]})
+		tabs[0].count += counts[p.ID]
+	}
+	return tabs
+}
+
+// activeTab returns the index of the tab matching the project filter, or -1
+// when several projects are picked in the sidebar
+func (m *Model) activeTab(tabs []projectTab) int {
+	switch len(m.filterProjectIDs) {
+	case 0:
+		return 0
+	case 1:
+		for i, tab := range tabs {
+			if m.filterProjectIDs[tab.projectID] {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// selectTab shows the tab's projects, keeping any search
+func (m *Model) selectTab(i int) {
+	tabs := m.projectTabs()
+	if i < 0 || i >= len(tabs) {
+		return
+	}
+	m.filterProjectIDs = make(map[string]bool)
+	if id := tabs[i].projectID; id != "" {
+		m.filterProjectIDs[id] = true
+	}
+	m.refreshColumnTickets()
+	m.activeTicket = 0
+	m.ensureTicketVisible()
+}
+
+// cycleTab moves delta tabs along, wrapping around
+func (m *Model) cycleTab(delta int) {
+	tabs := m.projectTabs()
+	if len(tabs) == 0 {
+		m.notify("Tabs need two or more projects")
+		return
+	}
+	i := max(m.activeTab(tabs), 0)
+	m.selectTab((i + delta + len(tabs)) % len(tabs))
+}
+
+func (m *Model) renderTab(tab projectTab, active bool) string {
+	label := fmt.Sprintf("%s %d", tab.label, tab.count)
+	if active {
+		return lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.colors.primary).
+			Bold(true).
+			Padding(0, 1).
+			Render(label)
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.subtext).Padding(0, 1).Render(label)
+}
+
+func (m *Model) renderTabs(tabs []projectTab) string {
+	active := m.activeTab(tabs)
+	parts := make([]string, len(tabs))
+	for i, tab := range tabs {
+		parts[i] = m.renderTab(tab, i == active)
+	}
+	return strings.Join(parts, "")
+}
+
+// tabAt returns the tab under column x of the header, or -1
+func (m *Model) tabAt(x int, tabs []projectTab) int {
+	active := m.activeTab(tabs)
+	start := m.headerTabsX()
+	for i, tab := range tabs {
+		width := lipgloss.Width(m.renderTab(tab, i == active))
+		if x >= start && x < start+width {
+			return i
+		}
+		start += width
+	}
+	return -1
+}
+
+// groupByProject orders tickets by project name, keeping their order within
+// each project
+func (m *Model) groupByProject(tickets []*board.Ticket) {
+	name := func(t *board.Ticket) string {
+		if p := m.globalStore.GetProject(t.ProjectID); p != nil {
+			return p.Name
+		}
+		return ""
+	}
+	slices.SortStableFunc(tickets, func(a, b *board.Ticket) int {
+		return strings.Compare(name(a), name(b))
+	})
+}
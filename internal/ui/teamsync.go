@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/crypt"
+	"github.com/techdufus/openkanban/internal/events"
+	"github.com/techdufus/openkanban/internal/teamsync"
+)
+
+type teamSyncTickMsg time.Time
+type teamSyncMsg struct {
+	projectID string
+	started   time.Time // when the board was read for the sync
+	result    *teamsync.Result
+	err       error
+}
+
+func tickTeamSync(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return teamSyncTickMsg(t)
+	})
+}
+
+// SetSyncCipher sets the cipher shared boards are encrypted with, the same
+// one the local ticket files use
+func (m *Model) SetSyncCipher(c *crypt.Cipher) {
+	m.syncCipher = c
+}
+
+// handleTeamSyncTick syncs every project's board with its team branch in the
+// background. With sync.interval at 0 boards only sync on start. An instance
+// that joined another leaves syncing to it.
+func (m *Model) handleTeamSyncTick() (tea.Model, tea.Cmd) {
+	var next tea.Cmd
+	if m.config.Sync.Interval > 0 {
+		next = tickTeamSync(time.Duration(m.config.Sync.Interval) * time.Second)
+	}
+	if !m.config.Sync.Enabled || m.config.Behavior.ReadOnly || m.collabJoined {
+		return m, next
+	}
+	if m.teamSyncing == nil {
+		m.teamSyncing = make(map[string]bool)
+	}
+
+	cmds := []tea.Cmd{next}
+	for _, p := range m.globalStore.Projects() {
+		if m.teamSyncing[p.ID] {
+			continue
+		}
+		var tickets []*board.Ticket
+		for _, t := range m.globalStore.All() {
+			if t.ProjectID == p.ID {
+				tickets = append(tickets, t)
+			}
+		}
+		started := time.Now()
+		shared, err := teamsync.ShareAll(tickets)
+		if err != nil {
+			m.reportTeamSyncError(err)
+			continue
+		}
+		m.teamSyncing[p.ID] = true
+		opts := teamsync.ProjectOptions(m.config.Sync, p, m.syncCipher)
+		projectID := p.ID
+		cmds = append(cmds, func() tea.Msg {
+			result, err := teamsync.Sync(opts, shared)
+			return teamSyncMsg{projectID: projectID, started: started, result: result, err: err}
+		})
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleTeamSync applies a synced board to the project's tickets. Tickets
+// changed here while the sync ran keep their changes; the next sync shares
+// them.
+func (m *Model) handleTeamSync(msg teamSyncMsg) (tea.Model, tea.Cmd) {
+	delete(m.teamSyncing, msg.projectID)
+	if msg.err != nil {
+		m.reportTeamSyncError(msg.err)
+		return m, nil
+	}
+	m.teamSyncErr = ""
+	if !msg.result.Pulled {
+		return m, nil
+	}
+
+	changed := 0
+	seen := make(map[board.TicketID]bool, len(msg.result.Tickets))
+	for id, shared := range msg.result.Tickets {
+		seen[id] = true
+		ticket, _ := m.globalStore.Get(id)
+		switch {
+		case ticket == nil:
+			ticket = &board.Ticket{}
+			if err := teamsync.Apply(ticket, shared); err != nil {
+				m.reportTeamSyncError(err)
+				continue
+			}
+			ticket.ProjectID = msg.projectID
+			if err := m.globalStore.Add(ticket); err != nil {
+				m.reportTeamSyncError(err)
+				continue
+			}
+			m.saveTicket(ticket)
+			m.publish(m.newEvent(events.TicketCreated, ticket))
+		case ticket.ProjectID != msg.projectID || ticket.UpdatedAt.After(msg.started):
+			continue
+		case teamsync.Equal(ticket, shared):
+			continue
+		default:
+			if err := teamsync.Apply(ticket, shared); err != nil {
+				m.reportTeamSyncError(err)
+				continue
+			}
+			m.saveTicket(ticket)
+		}
+		changed++
+	}
+
+	deleted := false
+	for _, ticket := range m.globalStore.All() {
+		if ticket.ProjectID != msg.projectID || seen[ticket.ID] {
+			continue
+		}
+		if ticket.CreatedAt.After(msg.started) || ticket.UpdatedAt.After(msg.started) {
+			continue
+		}
+		if _, running := m.panes[ticket.ID]; running {
+			continue
+		}
+		m.globalStore.RemoveBlockerReferences(ticket.ID)
+		m.globalStore.Delete(ticket.ID)
+		m.publish(m.newEvent(events.TicketDeleted, ticket))
+		deleted = true
+		changed++
+	}
+	if deleted {
+		if err := m.globalStore.SaveAll(); err != nil {
+			m.notify("Failed to save: " + err.Error())
+		}
+	}
+
+	if changed > 0 {
+		m.refreshColumnTickets()
+		m.notify(fmt.Sprintf("Synced %d change(s) from the team", changed))
+	}
+	return m, nil
+}
+
+func (m *Model) reportTeamSyncError(err error) {
+	if err.Error() != m.teamSyncErr {
+		m.teamSyncErr = err.Error()
+		m.notify("Failed to sync the board: " + m.teamSyncErr)
+	}
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"errors"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/telemetry"
+)
+
+// endAgentRun closes the ticket's open agent run and traces it as a span
+// from spawn to exit
+func (m *Model) endAgentRun(ticket *board.Ticket, outcome board.AgentOutcome) {
+	n := len(ticket.AgentRuns)
+	if n == 0 || ticket.AgentRuns[n-1].EndedAt != nil {
+		return
+	}
+	ticket.EndAgentRun(outcome)
+
+	run := ticket.AgentRuns[n-1]
+	var err error
+	if outcome == board.OutcomeFailed {
+		err = errors.New("agent failed")
+	}
+	attrs := []telemetry.Attr{
+		telemetry.String("openkanban.agent", run.Agent),
+		telemetry.String("openkanban.outcome", string(outcome)),
+		telemetry.String("openkanban.ticket_id", string(ticket.ID)),
+		telemetry.String("openkanban.ticket", ticket.Title),
+	}
+	if proj := m.globalStore.GetProjectForTicket(ticket); proj != nil {
+		attrs = append(attrs, telemetry.String("openkanban.project", proj.Name))
+	}
+	telemetry.Record("agent.session", run.StartedAt, *run.EndedAt, err, attrs...)
+}
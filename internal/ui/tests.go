@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/project"
+)
+
+type testsDoneMsg struct {
+	ticketID board.TicketID
+	title    string
+	result   project.TestResult
+	err      error
+}
+
+// runTests runs the project's test command in the selected ticket's
+// worktree in the background, the way setup commands run
+func (m *Model) runTests() (tea.Model, tea.Cmd) {
+	ticket := m.selectedTicket()
+	if ticket == nil || m.readOnlyBlocked("run tests") {
+		return m, nil
+	}
+	if ticket.WorktreePath == "" {
+		m.notify("No worktree for this ticket yet")
+		return m, nil
+	}
+	if m.testing[ticket.ID] {
+		m.notify("Tests are already running for this ticket")
+		return m, nil
+	}
+	if _, busy := m.gitOps[ticket.ID]; busy {
+		m.notify("Worktree still being created or removed")
+		return m, nil
+	}
+	proj := m.globalStore.GetProjectForTicket(ticket)
+	if proj == nil {
+		m.notify("Project not found for this ticket")
+		return m, nil
+	}
+	if proj.Settings.TestCommand == "" {
+		m.notify("Set test_command in the project's settings to run tests")
+		return m, nil
+	}
+
+	if m.testing == nil {
+		m.testing = make(map[board.TicketID]bool)
+	}
+	m.testing[ticket.ID] = true
+	ticketID, title := ticket.ID, ticket.Title
+	path, branch := ticket.WorktreePath, ticket.BranchName
+	return m, func() tea.Msg {
+		res, err := proj.RunTests(path, branch, string(ticketID))
+		return testsDoneMsg{ticketID: ticketID, title: title, result: res, err: err}
+	}
+}
+
+// handleTestsDone records the result on the ticket for its card's badge.
+// The output of failing tests goes to the error console.
+func (m *Model) handleTestsDone(msg testsDoneMsg) (tea.Model, tea.Cmd) {
+	delete(m.testing, msg.ticketID)
+	title := truncate(msg.title, 30)
+	if msg.err != nil {
+		m.notifyError("Tests for " + title + ": " + msg.err.Error())
+		return m, nil
+	}
+
+	res := msg.result
+	if ticket, _ := m.globalStore.Get(msg.ticketID); ticket != nil {
+		ticket.Tests = &board.TestRun{Passed: res.Passed, Pass: res.Pass, Fail: res.Fail, RanAt: time.Now()}
+		m.saveTicket(ticket)
+	}
+	counts := formatTestCounts(res.Pass, res.Fail)
+	if res.Passed {
+		m.notify(fmt.Sprintf("Tests passed for %s%s in %s", title, counts, res.Duration.Round(time.Second)))
+		return m, nil
+	}
+	m.notifyError("Tests failed for " + title + counts)
+	if res.Output != "" {
+		m.logError("Test output for " + title + ":\n" + res.Output)
+	}
+	return m, nil
+}
+
+// formatTestCounts reads ", 2 failed, 7 passed", or "" when the output gave
+// no counts
+func formatTestCounts(pass, fail int) string {
+	switch {
+	case fail > 0:
+		return fmt.Sprintf(", %d failed, %d passed", fail, pass)
+	case pass > 0:
+		return fmt.Sprintf(", %d passed", pass)
+	}
+	return ""
+}
+
+// renderTestBadge shows running tests, or the last run's result, on a
+// ticket card
+func (m *Model) renderTestBadge(ticket *board.Ticket) string {
+	if m.testing[ticket.ID] {
+		return lipgloss.NewStyle().Foreground(m.colors.info).Render(m.spinner.View() + "testing")
+	}
+	run := ticket.Tests
+	if run == nil {
+		return ""
+	}
+	if run.Passed {
+		label := "✓ tests"
+		if run.Pass > 0 {
+			label = fmt.Sprintf("✓ %d passed", run.Pass)
+		}
+		return lipgloss.NewStyle().Foreground(m.colors.success).Render(label)
+	}
+	label := "✗ tests"
+	if run.Fail > 0 {
+		label = fmt.Sprintf("✗ %d failed", run.Fail)
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.err).Render(label)
+}
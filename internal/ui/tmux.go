@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/project"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+type tmuxPanesMsg struct {
+	targets map[string]terminal.TmuxTarget // by ticket ID
+	err     error
+}
+
+type tmuxAttachedMsg struct {
+	err error
+}
+
+// tmuxSession names the tmux session holding a project's agent windows
+func (m *Model) tmuxSession(proj *project.Project) string {
+	return m.config.Tmux.SessionPrefix + board.Slugify(proj.Name, 40)
+}
+
+// tmuxWindow names a ticket's agent window. tmux reads '.' and ':' in
+// targets, so the slug keeps names plain.
+func tmuxWindow(ticket *board.Ticket) string {
+	if name := board.Slugify(ticket.Title, 30); name != "" {
+		return name
+	}
+	return string(ticket.ID)
+}
+
+// findTmuxPanes looks for agent windows left running by an earlier
+// openkanban so they show up on the board again
+func (m *Model) findTmuxPanes() tea.Cmd {
+	if !m.config.Tmux.Enabled || !terminal.TmuxAvailable() {
+		return nil
+	}
+	return func() tea.Msg {
+		targets, err := terminal.FindTmuxPanes()
+		return tmuxPanesMsg{targets: targets, err: err}
+	}
+}
+
+func (m *Model) handleTmuxPanes(msg tmuxPanesMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notify("Failed to list tmux windows: " + msg.err.Error())
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	for id, target := range msg.targets {
+		ticketID := board.TicketID(id)
+		if _, exists := m.panes[ticketID]; exists {
+			continue
+		}
+		if ticket, _ := m.globalStore.Get(ticketID); ticket == nil {
+			continue
+		}
+		pane, wait := terminal.AdoptTmux(id, target, m.width, m.height-2)
+		m.panes[ticketID] = pane
+		cmds = append(cmds, wait)
+	}
+	if len(cmds) > 0 {
+		m.notify(fmt.Sprintf("%d agent(s) still running in tmux", len(cmds)))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// attachTmux shows an agent's tmux window. Inside tmux the client switches
+// to it; otherwise the board is suspended until the window is detached.
+func (m *Model) attachTmux(target *terminal.TmuxTarget) tea.Cmd {
+	cmd, err := target.AttachCmd()
+	if err != nil {
+		m.notify("Failed to attach to tmux: " + err.Error())
+		return nil
+	}
+	if terminal.InsideTmux() {
+		return func() tea.Msg {
+			if out, err := cmd.CombinedOutput(); err != nil {
+				if msg := strings.TrimSpace(string(out)); msg != "" {
+					err = errors.New(msg)
+				}
+				return tmuxAttachedMsg{err: err}
+			}
+			return nil
+		}
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return tmuxAttachedMsg{err: err}
+	})
+}
+
+func (m *Model) handleTmuxAttached(msg tmuxAttachedMsg) {
+	if msg.err != nil {
+		m.notify("Failed to attach to tmux: " + msg.err.Error())
+	}
+}
+
+// runningEmbeddedAgents counts the agents that stop when openkanban quits;
+// agents in tmux keep running
+func (m *Model) runningEmbeddedAgents() int {
+	count := 0
+	for _, pane := range m.panes {
+		if pane.Running() && pane.Tmux() == nil {
+			count++
+		}
+	}
+	return count
+}
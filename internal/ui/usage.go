@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+	"github.com/techdufus/openkanban/internal/procstat"
+	"github.com/techdufus/openkanban/internal/terminal"
+)
+
+// usageInterval is how often the agents' CPU and memory are sampled
+const usageInterval = 5 * time.Second
+
+// busyCPU is the CPU use, in percent of a core, at which an agent's usage
+// is highlighted
+const busyCPU = 100
+
+type usageTickMsg time.Time
+
+type usageMsg struct {
+	usage map[board.TicketID]procstat.Usage
+	err   error
+}
+
+func tickUsage(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return usageTickMsg(t)
+	})
+}
+
+// handleUsageTick samples the process tree of every running agent in the
+// background. The next tick is scheduled once the sample is in.
+func (m *Model) handleUsageTick() (tea.Model, tea.Cmd) {
+	if len(m.panes) == 0 || m.usageBusy {
+		m.usage = nil
+		return m, tickUsage(usageInterval)
+	}
+	if m.usageSampler == nil {
+		m.usageSampler = &procstat.Sampler{}
+	}
+	panes := make(map[board.TicketID]*terminal.Pane, len(m.panes))
+	for id, pane := range m.panes {
+		panes[id] = pane
+	}
+	sampler := m.usageSampler
+	m.usageBusy = true
+	return m, func() tea.Msg {
+		table, err := procstat.Read()
+		if err != nil {
+			return usageMsg{err: err}
+		}
+		pids := make(map[string]int, len(panes))
+		for id, pane := range panes {
+			if pid, err := pane.PID(); err == nil {
+				pids[string(id)] = pid
+			}
+		}
+		usage := make(map[board.TicketID]procstat.Usage)
+		for id, u := range sampler.Sample(table, pids) {
+			usage[board.TicketID(id)] = u
+		}
+		return usageMsg{usage: usage}
+	}
+}
+
+func (m *Model) handleUsage(msg usageMsg) (tea.Model, tea.Cmd) {
+	m.usageBusy = false
+	switch {
+	case errors.Is(msg.err, procstat.ErrUnsupported):
+		return m, nil
+	case msg.err != nil:
+		slog.Debug("failed to sample agent usage", "err", msg.err)
+	default:
+		m.usage = msg.usage
+	}
+	return m, tickUsage(usageInterval)
+}
+
+// renderUsageBadge shows the CPU and memory used by the ticket's agent and
+// everything it started, highlighted when it keeps a core busy
+func (m *Model) renderUsageBadge(id board.TicketID) string {
+	u, ok := m.usage[id]
+	if !ok {
+		return ""
+	}
+	color := m.colors.subtext
+	if u.CPU >= busyCPU {
+		color = m.colors.warning
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(formatUsage(u))
+}
+
+func formatUsage(u procstat.Usage) string {
+	return fmt.Sprintf("⚙ %.0f%% %s", u.CPU, git.FormatBytes(u.RSS))
+}
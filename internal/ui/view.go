@@ -8,12 +8,24 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/techdufus/openkanban/internal/board"
 	"github.com/techdufus/openkanban/internal/config"
+	"github.com/techdufus/openkanban/internal/keymap"
 )
 
 func (m *Model) View() string {
+	if !m.debug.enabled() {
+		return m.withToasts(m.renderScreen())
+	}
+	start := time.Now()
+	view := m.withToasts(m.renderScreen())
+	m.recordView(time.Since(start))
+	return m.withDebugOverlay(view)
+}
+
+func (m *Model) renderScreen() string {
 	if m.width == 0 || m.height == 0 {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(m.colors.primary).
@@ -33,10 +45,14 @@ func (m *Model) View() string {
 		return m.renderSpawning()
 	}
 
-	if m.mode == ModeAgentView && m.focusedPane != "" {
+	if m.mode == ModeAgentView && m.focusedPane != "" && !m.splitView {
 		return m.renderAgentView()
 	}
 
+	if m.mode == ModeDiff && m.diff != nil {
+		return m.renderDiffView()
+	}
+
 	var b strings.Builder
 
 	b.WriteString(m.renderHeader())
@@ -45,10 +61,14 @@ func (m *Model) View() string {
 	sidebar := m.renderSidebar()
 	board := m.renderBoard()
 	if sidebar != "" {
-		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, sidebar, board))
-	} else {
-		b.WriteString(board)
+		board = lipgloss.JoinHorizontal(lipgloss.Top, sidebar, board)
+	}
+	if m.splitView {
+		board = m.withSplitPane(board)
+	} else if w := m.previewWidth(); w > 0 {
+		board = lipgloss.JoinHorizontal(lipgloss.Top, board, m.renderSidePanels(w, m.height-m.headerHeight()-1))
 	}
+	b.WriteString(board)
 
 	if m.showHelp {
 		return m.renderWithOverlay(m.renderHelp())
@@ -65,6 +85,36 @@ func (m *Model) View() string {
 	if m.mode == ModeCreateProject {
 		return m.renderWithOverlay(m.renderCreateProjectForm())
 	}
+	if m.mode == ModeConflicts && m.merge != nil {
+		return m.renderWithOverlay(m.renderConflictsView())
+	}
+	if m.mode == ModeWorktrees && m.worktrees != nil {
+		return m.renderWithOverlay(m.renderWorktreesView())
+	}
+	if m.mode == ModeSavedFilters && m.savedFilters != nil {
+		return m.renderWithOverlay(m.renderSavedFiltersView())
+	}
+	if m.mode == ModeMacros && m.macros != nil {
+		return m.renderWithOverlay(m.renderMacrosView())
+	}
+	if m.mode == ModeSessions && m.sessions != nil {
+		return m.renderWithOverlay(m.renderSessionsView())
+	}
+	if m.mode == ModeLog && m.log != nil {
+		return m.renderWithOverlay(m.renderLogView())
+	}
+	if m.mode == ModeTranscript && m.transcript != nil {
+		return m.renderWithOverlay(m.renderTranscriptView())
+	}
+	if m.mode == ModeSwitcher && m.switcher != nil {
+		return m.renderWithOverlay(m.renderSwitcherView())
+	}
+	if m.mode == ModeNotices && m.noticesPanel != nil {
+		return m.renderWithOverlay(m.renderNoticesView())
+	}
+	if m.mode == ModeClipboard && m.clipsPanel != nil {
+		return m.renderWithOverlay(m.renderClipsView())
+	}
 
 	b.WriteString("\n")
 	b.WriteString(m.renderStatusBar())
@@ -73,31 +123,19 @@ func (m *Model) View() string {
 }
 
 func (m *Model) renderHeader() string {
-	logo := lipgloss.NewStyle().
-		Foreground(m.colors.primary).
-		Bold(true).
-		Render("◈ OpenKanban")
-
-	var filterSection string
-	if m.mode == ModeFilter {
-		filterSection = m.renderFilterInput()
-	} else if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
-		filterSection = m.renderActiveFilter()
-	} else {
-		filterSection = m.renderFilterHint()
-	}
-
 	projectCount := len(m.globalStore.Projects())
 	ticketCount := m.globalStore.Count()
 	visibleCount := m.countVisibleTickets()
 	var stats string
-	if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
+	if tabs := m.projectTabs(); len(tabs) > 0 {
+		stats = m.renderTabs(tabs)
+	} else if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
 		stats = m.dimStyle().Render(fmt.Sprintf("showing %d of %d", visibleCount, ticketCount))
 	} else {
 		stats = m.dimStyle().Render(fmt.Sprintf("%d projects, %d tickets", projectCount, ticketCount))
 	}
 
-	left := lipgloss.JoinHorizontal(lipgloss.Center, logo, "  ", filterSection, "  ", stats)
+	left := lipgloss.JoinHorizontal(lipgloss.Center, m.renderLogo(), "  ", m.renderHeaderFilter(), "  ", stats)
 
 	workingCount, waitingCount, idleCount := 0, 0, 0
 	for ticketID, pane := range m.panes {
@@ -125,18 +163,19 @@ func (m *Model) renderHeader() string {
 		var statusText string
 		var bgColor lipgloss.Color
 
+		var glyph string
 		if waitingCount > 0 {
-			bgColor = m.colors.secondary
-			statusText = fmt.Sprintf("◐ %d waiting", waitingCount)
+			glyph, bgColor = m.agentIndicator(board.AgentWaiting)
+			statusText = fmt.Sprintf("%s %d waiting", glyph, waitingCount)
 			if workingCount > 0 {
-				statusText = fmt.Sprintf("◐ %d waiting, %d working", waitingCount, workingCount)
+				statusText = fmt.Sprintf("%s %d waiting, %d working", glyph, waitingCount, workingCount)
 			}
 		} else if workingCount > 0 {
-			bgColor = m.colors.warning
-			statusText = fmt.Sprintf("%s %d working", m.spinner.View(), workingCount)
+			glyph, bgColor = m.agentIndicator(board.AgentWorking)
+			statusText = fmt.Sprintf("%s %d working", glyph, workingCount)
 		} else {
-			bgColor = m.colors.primary
-			statusText = fmt.Sprintf("◆ %d idle", idleCount)
+			glyph, bgColor = m.agentIndicator(board.AgentIdle)
+			statusText = fmt.Sprintf("%s %d idle", glyph, idleCount)
 		}
 
 		activityBadge := lipgloss.NewStyle().
@@ -178,8 +217,7 @@ func (m *Model) renderBoard() string {
 	startCol := m.scrollOffset
 	endCol := min(startCol+visibleCols, len(m.columns))
 
-	numVisible := endCol - startCol
-	baseWidth, remainder := m.distributeWidth(numVisible)
+	baseWidth, remainder := m.distributeWidth(startCol, endCol)
 
 	var columns []string
 
@@ -192,6 +230,7 @@ func (m *Model) renderBoard() string {
 		columns = append(columns, indicator)
 	}
 
+	expanded := 0
 	for i := startCol; i < endCol; i++ {
 		col := m.columns[i]
 		isActive := i == m.activeColumn && !m.sidebarFocused
@@ -199,10 +238,15 @@ func (m *Model) renderBoard() string {
 		isDragTarget := m.dragging && i == m.dragTargetColumn && i != m.dragSourceColumn
 		isHovered := i == m.hoverColumn && !m.dragging
 
+		if m.isCollapsed(i) {
+			columns = append(columns, m.renderCollapsedColumn(col, len(m.columnTickets[i]), isActive, isDragTarget, isHovered, isLast))
+			continue
+		}
 		colWidth := baseWidth
-		if i-startCol < remainder {
+		if expanded < remainder {
 			colWidth++
 		}
+		expanded++
 
 		ticketOffset := 0
 		if i < len(m.columnOffsets) {
@@ -228,15 +272,7 @@ func (m *Model) renderBoard() string {
 func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive, isDragTarget, isHovered bool, width int, isLast bool, ticketOffset int) string {
 	headerColor := m.columnColor(col.Status)
 
-	columnIcons := map[board.TicketStatus]string{
-		board.StatusBacklog:    "📋",
-		board.StatusInProgress: "⚡",
-		board.StatusDone:       "✅",
-	}
-	icon := columnIcons[col.Status]
-	if icon == "" {
-		icon = "○"
-	}
+	icon := columnIcon(col.Status)
 	if isActive {
 		icon = "▸ " + icon
 	}
@@ -262,11 +298,22 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 
 	count := countStyle.Render(" " + countText)
 
-	headerLine := header + count
-
 	visibleCount := m.visibleTicketCount()
 	endIdx := min(ticketOffset+visibleCount, len(tickets))
 
+	headerLine := header + count
+	if len(tickets) > visibleCount {
+		// Where the cursor is, or the first card shown, out of the column
+		pos := ticketOffset + 1
+		if isActive {
+			pos = m.activeTicket + 1
+		}
+		position := lipgloss.NewStyle().Foreground(m.colors.subtext).Render(fmt.Sprintf("%d/%d", pos, len(tickets)))
+		if gap := width - 2 - lipgloss.Width(headerLine) - lipgloss.Width(position); gap > 0 {
+			headerLine += strings.Repeat(" ", gap) + position
+		}
+	}
+
 	hasMoreAbove := ticketOffset > 0
 	hasMoreBelow := endIdx < len(tickets)
 
@@ -340,7 +387,26 @@ func (m *Model) renderColumn(col board.Column, tickets []*board.Ticket, isActive
 	return style.Render(content)
 }
 
+func columnIcon(status board.TicketStatus) string {
+	switch status {
+	case board.StatusBacklog:
+		return "📋"
+	case board.StatusInProgress:
+		return "⚡"
+	case board.StatusReview:
+		return "🔍"
+	case board.StatusDone:
+		return "✅"
+	}
+	return "○"
+}
+
 func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, width int, columnColor lipgloss.Color) string {
+	if m.cardDensity == densityCompact {
+		return m.renderCompactTicket(ticket, isSelected, isHovered, width, columnColor)
+	}
+	detailed := m.cardDensity == densityDetailed
+
 	pane, hasPane := m.panes[ticket.ID]
 	isRunning := hasPane && pane.Running()
 
@@ -357,26 +423,19 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 		projectBadge = bracketStyle.Render("❨") + textStyle.Render(shortName) + bracketStyle.Render("❩")
 	}
 
-	var sessionBadge string
+	// Working agents already spin on the status line; symbolic indicators
+	// mark them up here too
+	showBadge := effectiveStatus != board.AgentNone
 	switch effectiveStatus {
-	case board.AgentWaiting:
-		sessionBadge = lipgloss.NewStyle().
-			Foreground(m.colors.secondary).
-			Render("◐")
 	case board.AgentIdle:
-		if hasPane {
-			sessionBadge = lipgloss.NewStyle().
-				Foreground(m.colors.primary).
-				Render("◆")
-		}
-	case board.AgentCompleted:
-		sessionBadge = lipgloss.NewStyle().
-			Foreground(m.colors.success).
-			Render("✓")
-	case board.AgentError:
-		sessionBadge = lipgloss.NewStyle().
-			Foreground(m.colors.err).
-			Render("✗")
+		showBadge = hasPane
+	case board.AgentWorking:
+		showBadge = m.config.UI.SymbolicIndicators
+	}
+	var sessionBadge string
+	if showBadge {
+		glyph, color := m.agentIndicator(effectiveStatus)
+		sessionBadge = lipgloss.NewStyle().Foreground(color).Render(glyph)
 	}
 
 	var priorityBadge string
@@ -408,6 +467,9 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	}
 
 	var headerParts []string
+	if key := m.globalStore.TicketKey(ticket); key != "" {
+		headerParts = append(headerParts, lipgloss.NewStyle().Foreground(m.colors.subtext).Render(key))
+	}
 	if priorityBadge != "" {
 		headerParts = append(headerParts, priorityBadge)
 	}
@@ -431,15 +493,41 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	var descLine string
 	if ticket.Description != "" {
 		desc := ticket.Description
-		if len(desc) > 60 {
-			desc = desc[:57] + "..."
-		}
-		desc = strings.ReplaceAll(desc, "\n", " ")
-		descLine = lipgloss.NewStyle().
+		descStyle := lipgloss.NewStyle().
 			Foreground(m.colors.muted).
 			Italic(true).
-			Width(width).
-			Render(desc)
+			Width(width)
+		if detailed {
+			// Up to three wrapped lines rather than a one-line teaser
+			wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(strings.Join(strings.Fields(desc), " ")), "\n")
+			if len(wrapped) > 3 {
+				wrapped = wrapped[:3]
+				last := []rune(strings.TrimRight(wrapped[2], " "))
+				wrapped[2] = string(last[:min(len(last), max(width-1, 0))]) + "…"
+			}
+			desc = strings.Join(wrapped, "\n")
+		} else {
+			if len(desc) > 60 {
+				desc = desc[:57] + "..."
+			}
+			desc = strings.ReplaceAll(desc, "\n", " ")
+		}
+		descLine = descStyle.Render(desc)
+	}
+
+	var notesLine string
+	if detailed && ticket.Notes != "" {
+		first, _, _ := strings.Cut(strings.TrimSpace(ticket.Notes), "\n")
+		notesLine = lipgloss.NewStyle().
+			Foreground(m.colors.subtext).
+			Render(truncate("▤ "+first, width))
+	}
+
+	var branchLine string
+	if detailed && ticket.BranchName != "" {
+		branchLine = lipgloss.NewStyle().
+			Foreground(m.colors.subtext).
+			Render(truncate("⎇ "+ticket.BranchName, width))
 	}
 
 	var statusParts []string
@@ -452,35 +540,49 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 		statusParts = append(statusParts, agentBadge)
 	}
 
+	if effectiveStatus == board.AgentNone && detailed {
+		statusParts = append(statusParts, m.dimStyle().Render(glyphNone+" no agent"))
+	}
 	if effectiveStatus != board.AgentNone {
-		var statusIcon, statusText string
-		var statusColor lipgloss.Color
-		switch effectiveStatus {
-		case board.AgentIdle:
-			statusIcon = "◆"
-			statusText = "idle"
-			statusColor = m.colors.primary
-		case board.AgentWorking:
-			statusIcon = m.spinner.View()
-			statusText = "working"
-			statusColor = m.colors.warning
-		case board.AgentWaiting:
-			statusIcon = "◐"
-			statusText = "waiting"
-			statusColor = m.colors.secondary
-		case board.AgentCompleted:
-			statusIcon = "✓"
+		statusIcon, statusColor := m.agentIndicator(effectiveStatus)
+		statusText := string(effectiveStatus)
+		if effectiveStatus == board.AgentCompleted {
 			statusText = "done"
-			statusColor = m.colors.success
-		case board.AgentError:
-			statusIcon = "✗"
-			statusText = "error"
-			statusColor = m.colors.err
 		}
 		statusStyle := lipgloss.NewStyle().Foreground(statusColor)
 		statusParts = append(statusParts, statusStyle.Render(statusIcon+" "+statusText))
 	}
 
+	if badge := m.renderGitBadge(ticket.ID); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderGitOpBadge(ticket.ID); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderSetupBadge(ticket.ID, width-8); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderPRBadge(ticket); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderTestBadge(ticket); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderAttemptBadge(ticket); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if badge := m.renderFileBadge(ticket.ID); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+	if detailed {
+		if badge := m.renderUsageBadge(ticket.ID); badge != "" {
+			statusParts = append(statusParts, badge)
+		}
+	}
+	if badge := m.renderPeerBadge(ticket.ID); badge != "" {
+		statusParts = append(statusParts, badge)
+	}
+
 	statusLine := strings.Join(statusParts, " ")
 
 	var labelParts []string
@@ -498,6 +600,12 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 	if descLine != "" {
 		lines = append(lines, descLine)
 	}
+	if notesLine != "" {
+		lines = append(lines, notesLine)
+	}
+	if branchLine != "" {
+		lines = append(lines, branchLine)
+	}
 	if statusLine != "" {
 		lines = append(lines, statusLine)
 	}
@@ -528,6 +636,9 @@ func (m *Model) renderTicket(ticket *board.Ticket, isSelected, isHovered bool, w
 
 	border := ticketBorder
 	borderColor := m.colors.surface
+	if tag, ok := m.colorRef(ticket.Color); ok {
+		borderColor = tag
+	}
 
 	if isHovered && !isSelected {
 		borderColor = m.colors.overlay
@@ -570,6 +681,17 @@ func (m *Model) renderStatusBar() string {
 		ModeConfirm:       {"!", m.colors.err},
 		ModeFilter:        {"/", m.colors.info},
 		ModeCreateProject: {"📁", m.colors.success},
+		ModeDiff:          {"±", m.colors.info},
+		ModeConflicts:     {"⚠", m.colors.warning},
+		ModeWorktrees:     {"⌥", m.colors.secondary},
+		ModeLog:           {"⎇", m.colors.info},
+		ModeSwitcher:      {"»", m.colors.primary},
+		ModeNotices:       {"✉", m.colors.secondary},
+		ModeClipboard:     {"⎘", m.colors.secondary},
+		ModeSessions:      {"▣", m.colors.secondary},
+		ModeSavedFilters:  {"/", m.colors.info},
+		ModeMacros:        {"@", m.colors.secondary},
+		ModeTranscript:    {"≡", m.colors.info},
 	}
 	cfg := modeConfigs[m.mode]
 	if cfg.bg == "" {
@@ -581,36 +703,49 @@ func (m *Model) renderStatusBar() string {
 		Bold(true).
 		Padding(0, 1).
 		Render(cfg.icon + " " + string(m.mode))
+	if m.config.Behavior.ReadOnly {
+		modeStr += lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.colors.warning).
+			Bold(true).
+			Padding(0, 1).
+			Render("READ-ONLY")
+	}
+	if m.focusMode {
+		modeStr += lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.colors.info).
+			Bold(true).
+			Padding(0, 1).
+			Render("FOCUS")
+	}
+	if m.config.Notifications.Quiet(time.Now()) {
+		modeStr += lipgloss.NewStyle().
+			Foreground(m.colors.base).
+			Background(m.colors.secondary).
+			Bold(true).
+			Padding(0, 1).
+			Render("DND")
+	}
+	modeStr += m.renderMacroBadge()
 
 	sep := lipgloss.NewStyle().Foreground(m.colors.overlay).Render(" │ ")
 	hintStyle := lipgloss.NewStyle().Foreground(m.colors.subtext)
 
 	hints := m.contextualHints(hintStyle, sep)
 
-	notif := ""
-	if m.notification != "" {
-		isError := strings.HasPrefix(m.notification, "Failed") ||
-			strings.HasPrefix(m.notification, "Error") ||
-			strings.Contains(m.notification, "failed")
-		bgColor := m.colors.success
-		icon := "✓"
-		if isError {
-			bgColor = m.colors.err
-			icon = "✗"
-		}
-		notifBadge := lipgloss.NewStyle().
-			Foreground(m.colors.base).
-			Background(bgColor).
-			Padding(0, 1).
-			Render(icon + " " + m.notification)
-		notif = notifBadge
+	// System state wins over hints, but always leaves them some room
+	state := m.renderSystemState(m.width - lipgloss.Width(modeStr) - lipgloss.Width(sep) - 20)
+	if state != "" {
+		state = sep + state
 	}
+	hints = ansi.Truncate(hints, max(m.width-lipgloss.Width(modeStr)-lipgloss.Width(sep)-lipgloss.Width(state), 0), "…")
 
 	left := lipgloss.JoinHorizontal(lipgloss.Center, modeStr, sep, hints)
-	spacing := m.width - lipgloss.Width(left) - lipgloss.Width(notif)
+	spacing := m.width - lipgloss.Width(left) - lipgloss.Width(state)
 	spacing = max(spacing, 0)
 
-	return lipgloss.JoinHorizontal(lipgloss.Center, left, strings.Repeat(" ", spacing), notif)
+	return lipgloss.JoinHorizontal(lipgloss.Center, left, strings.Repeat(" ", spacing), state)
 }
 
 func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
@@ -622,9 +757,74 @@ func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
 
 	case ModeSettings:
 		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("h/l") + m.dimStyle().Render(" page") + sep +
 			hintStyle.Render("Enter") + m.dimStyle().Render(" select") + sep +
 			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
 
+	case ModeDiff:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" move") + sep +
+			hintStyle.Render("Tab") + m.dimStyle().Render(" files/diff") + sep +
+			hintStyle.Render("n/p") + m.dimStyle().Render(" file") + sep +
+			hintStyle.Render("r") + m.dimStyle().Render(" reload") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeConflicts:
+		return hintStyle.Render("c") + m.dimStyle().Render(" continue") + sep +
+			hintStyle.Render("a") + m.dimStyle().Render(" abort") + sep +
+			hintStyle.Render("Enter") + m.dimStyle().Render(" attach agent") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeWorktrees:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("d") + m.dimStyle().Render(" remove") + sep +
+			hintStyle.Render("D") + m.dimStyle().Render(" remove with branch") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeSavedFilters:
+		if m.savedFilters != nil && m.savedFilters.naming {
+			return hintStyle.Render("Enter") + m.dimStyle().Render(" save") + sep +
+				hintStyle.Render("Esc") + m.dimStyle().Render(" cancel")
+		}
+		return hintStyle.Render("Enter") + m.dimStyle().Render(" apply") + sep +
+			hintStyle.Render("s") + m.dimStyle().Render(" save current") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeMacros:
+		if m.macros != nil && m.macros.pending != nil {
+			return hintStyle.Render("Enter") + m.dimStyle().Render(" next") + sep +
+				hintStyle.Render("Esc") + m.dimStyle().Render(" discard")
+		}
+		return hintStyle.Render("Enter") + m.dimStyle().Render(" run") + sep +
+			hintStyle.Render("d") + m.dimStyle().Render(" delete") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeSessions:
+		if m.sessions != nil && m.sessions.adopt != nil {
+			return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+				hintStyle.Render("Enter") + m.dimStyle().Render(" adopt") + sep +
+				hintStyle.Render("Esc") + m.dimStyle().Render(" back")
+		}
+		return hintStyle.Render("Enter") + m.dimStyle().Render(" attach") + sep +
+			hintStyle.Render("x") + m.dimStyle().Render(" kill") + sep +
+			hintStyle.Render("a") + m.dimStyle().Render(" adopt") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeLog:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
+			hintStyle.Render("y") + m.dimStyle().Render(" copy hash") + sep +
+			hintStyle.Render("c") + m.dimStyle().Render(" check out") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeTranscript:
+		return hintStyle.Render("j/k") + m.dimStyle().Render(" scroll") + sep +
+			hintStyle.Render("y") + m.dimStyle().Render(" copy") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
+	case ModeSwitcher:
+		return hintStyle.Render("Enter") + m.dimStyle().Render(" jump") + sep +
+			hintStyle.Render("Ctrl+O") + m.dimStyle().Render(" open pane") + sep +
+			hintStyle.Render("Esc") + m.dimStyle().Render(" close")
+
 	case ModeCreateTicket, ModeEditTicket:
 		action := "create"
 		if m.mode == ModeEditTicket {
@@ -632,53 +832,64 @@ func (m *Model) contextualHints(hintStyle lipgloss.Style, sep string) string {
 		}
 		return hintStyle.Render("Tab") + m.dimStyle().Render(" next") + sep +
 			hintStyle.Render("Ctrl+S") + m.dimStyle().Render(" "+action) + sep +
+			hintStyle.Render("Ctrl+O") + m.dimStyle().Render(" description in $EDITOR") + sep +
 			hintStyle.Render("Esc") + m.dimStyle().Render(" cancel")
 
 	case ModeAgentView:
-		return hintStyle.Render("Ctrl+G") + m.dimStyle().Render(" back to board") + sep +
-			m.dimStyle().Render("Shift+click to select text")
+		return hintStyle.Render(m.keyHint(keymap.DetachAgent)) + m.dimStyle().Render(" back to board") + sep +
+			m.dimStyle().Render("Shift+click to select text") + sep +
+			hintStyle.Render(m.keyHint(keymap.NewFromText)) + m.dimStyle().Render(" ticket from selection")
 
 	case ModeNormal:
 		if m.sidebarFocused {
 			return hintStyle.Render("j/k") + m.dimStyle().Render(" navigate") + sep +
 				hintStyle.Render("Space/Enter") + m.dimStyle().Render(" toggle") + sep +
-				hintStyle.Render("l") + m.dimStyle().Render(" board")
+				hintStyle.Render(m.keyHint(keymap.MoveRight)) + m.dimStyle().Render(" board")
 		}
 
 		if m.filterQuery != "" || len(m.filterProjectIDs) > 0 {
 			return hintStyle.Render("Esc") + m.dimStyle().Render(" clear filter") + sep +
-				hintStyle.Render("/") + m.dimStyle().Render(" edit filter") + sep +
-				hintStyle.Render("?") + m.dimStyle().Render(" help")
+				hintStyle.Render(m.keyHint(keymap.Filter)) + m.dimStyle().Render(" edit filter") + sep +
+				hintStyle.Render(m.keyHint(keymap.Help)) + m.dimStyle().Render(" help")
 		}
 
 		ticket := m.selectedTicket()
 		if ticket != nil {
 			if _, hasPane := m.panes[ticket.ID]; hasPane {
-				return hintStyle.Render("Enter") + m.dimStyle().Render(" attach") + sep +
-					hintStyle.Render("S") + m.dimStyle().Render(" stop agent") + sep +
-					hintStyle.Render("Space") + m.dimStyle().Render(" move") + sep +
-					hintStyle.Render("?") + m.dimStyle().Render(" help")
+				return hintStyle.Render(m.keyHint(keymap.AttachAgent)) + m.dimStyle().Render(" attach") + sep +
+					hintStyle.Render(m.keyHint(keymap.StopAgent)) + m.dimStyle().Render(" stop agent") + sep +
+					hintStyle.Render(m.keyHint(keymap.MoveForward)) + m.dimStyle().Render(" move") + sep +
+					hintStyle.Render(m.keyHint(keymap.Help)) + m.dimStyle().Render(" help")
 			}
 			if ticket.Status == board.StatusInProgress {
-				return hintStyle.Render("s") + m.dimStyle().Render(" spawn agent") + sep +
-					hintStyle.Render("Space") + m.dimStyle().Render(" move") + sep +
-					hintStyle.Render("e") + m.dimStyle().Render(" edit") + sep +
-					hintStyle.Render("?") + m.dimStyle().Render(" help")
+				return hintStyle.Render(m.keyHint(keymap.SpawnAgent)) + m.dimStyle().Render(" spawn agent") + sep +
+					hintStyle.Render(m.keyHint(keymap.MoveForward)) + m.dimStyle().Render(" move") + sep +
+					hintStyle.Render(m.keyHint(keymap.EditTicket)) + m.dimStyle().Render(" edit") + sep +
+					hintStyle.Render(m.keyHint(keymap.Help)) + m.dimStyle().Render(" help")
 			}
 		}
 
-		return hintStyle.Render("h/l") + m.dimStyle().Render(" columns") + sep +
-			hintStyle.Render("n") + m.dimStyle().Render(" new") + sep +
-			hintStyle.Render("Space") + m.dimStyle().Render(" move") + sep +
-			hintStyle.Render("/") + m.dimStyle().Render(" search") + sep +
-			hintStyle.Render("?") + m.dimStyle().Render(" help")
+		return hintStyle.Render(m.keyHint(keymap.MoveLeft)+"/"+m.keyHint(keymap.MoveRight)) + m.dimStyle().Render(" columns") + sep +
+			hintStyle.Render(m.keyHint(keymap.NewTicket)) + m.dimStyle().Render(" new") + sep +
+			hintStyle.Render(m.keyHint(keymap.MoveForward)) + m.dimStyle().Render(" move") + sep +
+			hintStyle.Render(m.keyHint(keymap.Filter)) + m.dimStyle().Render(" search") + sep +
+			hintStyle.Render(m.keyHint(keymap.Help)) + m.dimStyle().Render(" help")
 
 	default:
 		return hintStyle.Render("Esc") + m.dimStyle().Render(" back") + sep +
-			hintStyle.Render("?") + m.dimStyle().Render(" help")
+			hintStyle.Render(m.keyHint(keymap.Help)) + m.dimStyle().Render(" help")
 	}
 }
 
+// keyHint returns the primary key bound to action, as shown in hints.
+func (m *Model) keyHint(action keymap.Action) string {
+	keys := m.keys.Keys(action)
+	if len(keys) == 0 {
+		return "-"
+	}
+	return keymap.DisplayKey(keys[0])
+}
+
 func (m *Model) renderHelp() string {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(m.colors.primary).
@@ -700,28 +911,43 @@ func (m *Model) renderHelp() string {
 
 	sep := sepStyle.Render("────────────────────────────────────────────")
 
-	help := titleStyle.Render("◈ Keyboard Shortcuts") + "\n\n" +
-		sep + "\n" +
-		sectionStyle.Render("  🧭 Navigation") + "                 " + sectionStyle.Render("📝 Actions") + "\n" +
-		sep + "\n" +
-		"  " + keyStyle.Render("h/l") + descStyle.Render("   Move between columns  ") + keyStyle.Render("n") + descStyle.Render("       New ticket") + "\n" +
-		"  " + keyStyle.Render("j/k") + descStyle.Render("   Move between tickets  ") + keyStyle.Render("e") + descStyle.Render("       Edit ticket") + "\n" +
-		"  " + keyStyle.Render("g") + descStyle.Render("     Go to first ticket    ") + keyStyle.Render("d") + descStyle.Render("       Delete ticket") + "\n" +
-		"  " + keyStyle.Render("G") + descStyle.Render("     Go to last ticket     ") + keyStyle.Render("Space") + descStyle.Render("   Move forward") + "\n" +
-		"  " + keyStyle.Render(" ") + descStyle.Render("                            ") + keyStyle.Render("-") + descStyle.Render("       Move backward") + "\n\n" +
-		sep + "\n" +
-		sectionStyle.Render("  📂 Sidebar") + "                    " + sectionStyle.Render("🤖 Agent") + "\n" +
-		sep + "\n" +
-		"  " + keyStyle.Render("[") + descStyle.Render("     Toggle sidebar        ") + keyStyle.Render("s") + descStyle.Render("       Spawn agent") + "\n" +
-		"  " + keyStyle.Render("h") + descStyle.Render("     Enter sidebar         ") + keyStyle.Render("S") + descStyle.Render("       Stop agent") + "\n" +
-		"  " + keyStyle.Render("l") + descStyle.Render("     Exit sidebar          ") + keyStyle.Render("Enter") + descStyle.Render("   Attach to agent") + "\n" +
-		"  " + keyStyle.Render("j/k") + descStyle.Render("   Navigate projects     ") + keyStyle.Render("Ctrl+g") + descStyle.Render("  Exit agent view") + "\n\n" +
-		sep + "\n" +
-		sectionStyle.Render("  👁 View") + "\n" +
-		sep + "\n" +
-		"  " + keyStyle.Render("/") + descStyle.Render("     Search/filter         ") + keyStyle.Render("O") + descStyle.Render("       Settings") + "\n" +
-		"  " + keyStyle.Render("?") + descStyle.Render("     Toggle help           ") + keyStyle.Render("q") + descStyle.Render("       Quit") + "\n\n" +
-		sep + "\n" +
+	sections := m.keys.Help()
+	sections = append(sections, keymap.HelpSection{
+		Group: "Sidebar",
+		Entries: []keymap.HelpEntry{
+			{Keys: m.keys.Label(keymap.MoveLeft), Description: "Enter sidebar"},
+			{Keys: m.keys.Label(keymap.MoveRight), Description: "Exit sidebar"},
+			{Keys: "a", Description: "Add project"},
+			{Keys: "d", Description: "Delete project"},
+		},
+	})
+
+	keyWidth := 0
+	for _, section := range sections {
+		for _, e := range section.Entries {
+			keyWidth = max(keyWidth, lipgloss.Width(e.Keys))
+		}
+	}
+
+	renderSection := func(section keymap.HelpSection) string {
+		lines := []string{sectionStyle.Render("  " + string(section.Group))}
+		for _, e := range section.Entries {
+			lines = append(lines, "  "+keyStyle.Width(keyWidth).Render(e.Keys)+descStyle.Render("  "+e.Description))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	help := titleStyle.Render("◈ Keyboard Shortcuts") + "\n\n"
+	for i := 0; i < len(sections); i += 2 {
+		left := renderSection(sections[i])
+		right := ""
+		if i+1 < len(sections) {
+			right = renderSection(sections[i+1])
+		}
+		help += sep + "\n" +
+			lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(lipgloss.Width(left)+4).Render(left), right) + "\n\n"
+	}
+	help += sep + "\n" +
 		"  " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("💡") + m.dimStyle().Render(" Tip: Hold Shift to select text in agent view") + "\n\n" +
 		"  " + m.dimStyle().Render("Press any key to close")
 
@@ -737,6 +963,24 @@ func (m *Model) renderConfirmDialog() string {
 		Foreground(m.colors.err).
 		Bold(true)
 
+	if m.confirmWant != "" {
+		enter := m.dimStyle().Render("[enter]")
+		if m.confirmTyped() {
+			enter = lipgloss.NewStyle().Foreground(m.colors.success).Render("[enter]")
+		}
+		content := titleStyle.Render("⚠ Confirm") + "\n\n" +
+			"  " + lipgloss.NewStyle().Foreground(m.colors.text).Render(m.confirmMsg) + "\n" +
+			"  " + m.dimStyle().Render("Type ") + lipgloss.NewStyle().Foreground(m.colors.warning).Render(m.confirmWant) + m.dimStyle().Render(" to confirm") + "\n\n" +
+			"  " + m.confirmInput.View() + "\n\n" +
+			"  " + enter + m.dimStyle().Render(" Confirm    ") +
+			lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]") + m.dimStyle().Render(" Cancel")
+		return lipgloss.NewStyle().
+			Border(columnBorder).
+			BorderForeground(m.colors.err).
+			Padding(1, 2).
+			Render(content)
+	}
+
 	content := titleStyle.Render("⚠ Confirm") + "\n\n" +
 		"  " + lipgloss.NewStyle().Foreground(m.colors.text).Render(m.confirmMsg) + "\n\n" +
 		"  " + lipgloss.NewStyle().Foreground(m.colors.success).Render("[y]") + m.dimStyle().Render(" Yes    ") +
@@ -786,7 +1030,14 @@ func (m *Model) renderSpawning() string {
 		Foreground(m.colors.success).
 		Bold(true)
 
+	var step string
+	if op, ok := m.gitOps[m.spawningTicketID]; ok {
+		step = lipgloss.NewStyle().Foreground(m.colors.info).Render("  ⎇ "+op.label) + "\n\n"
+	}
+
 	content := titleStyle.Render(m.spinner.View()+" Starting "+agentName) + "\n\n" +
+		step +
+		m.renderSetupProgress(m.spawningTicketID) +
 		"  " + m.dimStyle().Render("[Esc] Cancel")
 
 	dialog := lipgloss.NewStyle().
@@ -835,10 +1086,12 @@ func (m *Model) renderTicketForm() string {
 	titleLabel := labelStyle
 	descLabel := labelStyle
 	branchLabel := labelStyle
+	baseLabel := labelStyle
 	labelsLabel := labelStyle
 	priorityLabel := labelStyle
 	worktreeLabel := labelStyle
 	agentLabel := labelStyle
+	modelLabel := labelStyle
 	blockerLabel := labelStyle
 	projectLabel := labelStyle
 
@@ -852,6 +1105,8 @@ func (m *Model) renderTicketForm() string {
 		descLabel = activeLabelStyle
 	case formFieldBranch:
 		branchLabel = activeLabelStyle
+	case formFieldBase:
+		baseLabel = activeLabelStyle
 	case formFieldLabels:
 		labelsLabel = activeLabelStyle
 	case formFieldPriority:
@@ -860,26 +1115,33 @@ func (m *Model) renderTicketForm() string {
 		worktreeLabel = activeLabelStyle
 	case formFieldAgent:
 		agentLabel = activeLabelStyle
+	case formFieldModel:
+		modelLabel = activeLabelStyle
 	case formFieldBlockedBy:
 		blockerLabel = activeLabelStyle
 	case formFieldProject:
 		projectLabel = activeLabelStyle
 	}
 
-	var branchField string
-	var branchDesc string
+	var branchField, branchDesc, baseField, baseDesc string
 	if m.branchLocked {
 		branchLabel = lockedStyle
 		branchField = lockedStyle.Render(m.branchInput.Value() + " (locked)")
 		branchDesc = descriptionStyle.Render("Branch is locked after worktree creation")
+		baseLabel = lockedStyle
+		baseField = lockedStyle.Render(m.ticketBaseBranch + " (locked)")
+		baseDesc = descriptionStyle.Render("Base is locked after worktree creation")
 	} else {
 		branchField = m.branchInput.View()
 		branchDesc = descriptionStyle.Render("Auto-generated from title if left empty")
+		baseField = m.renderBaseBranchSelector()
+		baseDesc = descriptionStyle.Render("Local or remote branch to start from")
 	}
 
 	priorityField := m.renderPrioritySelector()
 	worktreeField := m.renderWorktreeSelector()
 	agentField := m.renderAgentSelector()
+	modelField := m.renderModelSelector()
 	blockerField := m.renderBlockerSelector()
 	projectField := m.renderProjectSelector()
 
@@ -895,7 +1157,7 @@ func (m *Model) renderTicketForm() string {
 	focusIndicator := lipgloss.NewStyle().Foreground(m.colors.info).Render("▸ ")
 	noFocus := "  "
 
-	titleFocus, descFocus, branchFocus, labelsFocus, priorityFocus, worktreeFocus, agentFocus, blockerFocus, projectFocus := noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus
+	titleFocus, descFocus, branchFocus, baseFocus, labelsFocus, priorityFocus, worktreeFocus, agentFocus, modelFocus, blockerFocus, projectFocus := noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus, noFocus
 	switch m.ticketFormField {
 	case formFieldTitle:
 		titleFocus = focusIndicator
@@ -903,6 +1165,8 @@ func (m *Model) renderTicketForm() string {
 		descFocus = focusIndicator
 	case formFieldBranch:
 		branchFocus = focusIndicator
+	case formFieldBase:
+		baseFocus = focusIndicator
 	case formFieldLabels:
 		labelsFocus = focusIndicator
 	case formFieldPriority:
@@ -911,6 +1175,8 @@ func (m *Model) renderTicketForm() string {
 		worktreeFocus = focusIndicator
 	case formFieldAgent:
 		agentFocus = focusIndicator
+	case formFieldModel:
+		modelFocus = focusIndicator
 	case formFieldBlockedBy:
 		blockerFocus = focusIndicator
 	case formFieldProject:
@@ -947,6 +1213,16 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldBranch] = len(lines) - 1
 	currentLine = len(lines)
 
+	fieldStartLines[formFieldBase] = currentLine
+	lines = append(lines, baseFocus+baseLabel.Render("Base Branch"))
+	lines = append(lines, "  "+baseDesc)
+	for _, bl := range strings.Split(baseField, "\n") {
+		lines = append(lines, "  "+bl)
+	}
+	lines = append(lines, "")
+	fieldEndLines[formFieldBase] = len(lines) - 1
+	currentLine = len(lines)
+
 	fieldStartLines[formFieldLabels] = currentLine
 	lines = append(lines, labelsFocus+labelsLabel.Render("Labels"))
 	lines = append(lines, "  "+descriptionStyle.Render("Comma-separated tags (e.g. bug, urgent)"))
@@ -979,6 +1255,16 @@ func (m *Model) renderTicketForm() string {
 	fieldEndLines[formFieldAgent] = len(lines) - 1
 	currentLine = len(lines)
 
+	if len(m.modelChoices()) > 1 {
+		fieldStartLines[formFieldModel] = currentLine
+		lines = append(lines, modelFocus+modelLabel.Render("Model"))
+		lines = append(lines, "  "+descriptionStyle.Render("Model the agent runs, with its cost tier"))
+		lines = append(lines, "  "+modelField)
+		lines = append(lines, "")
+		fieldEndLines[formFieldModel] = len(lines) - 1
+		currentLine = len(lines)
+	}
+
 	fieldStartLines[formFieldBlockedBy] = currentLine
 	lines = append(lines, blockerFocus+blockerLabel.Render("Blocked By"))
 	lines = append(lines, "  "+descriptionStyle.Render("Tickets that must complete before this one"))
@@ -1275,6 +1561,33 @@ func (m *Model) renderWithOverlay(overlay string) string {
 	)
 }
 
+// Layout of the settings overlay, used to map mouse clicks to rows
+const (
+	settingsContentTop   = 2 // border and top padding
+	settingsContentLeft  = 3 // border and left padding
+	settingsTabsRow      = 2
+	settingsFieldsRow    = 5
+	settingsTabSeparator = " │ "
+)
+
+func (m *Model) settingsWidth() int {
+	return min(76, m.width-4)
+}
+
+// settingsValueWidth is the room left for a value after padding, cursor,
+// label and the env marker
+func (m *Model) settingsValueWidth() int {
+	return max(m.settingsWidth()-4-2-19-7, 10)
+}
+
+func (m *Model) settingsTabLabels() []string {
+	labels := make([]string, len(settingsPages))
+	for i, page := range settingsPages {
+		labels[i] = " " + page.title + " "
+	}
+	return labels
+}
+
 func (m *Model) renderSettingsView() string {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(m.colors.secondary).
@@ -1298,46 +1611,91 @@ func (m *Model) renderSettingsView() string {
 	lines = append(lines, titleStyle.Render("◈ Settings"))
 	lines = append(lines, "")
 
-	for i, field := range settingsFields {
-		label := field.label
-		value := m.getSettingsValue(field.key)
+	var tabs []string
+	for i, label := range m.settingsTabLabels() {
+		style := lipgloss.NewStyle().Foreground(m.colors.subtext)
+		if i == m.settingsPage {
+			style = lipgloss.NewStyle().Foreground(m.colors.base).Background(m.colors.secondary).Bold(true)
+		}
+		tabs = append(tabs, style.Render(label))
+	}
+	lines = append(lines, strings.Join(tabs, m.dimStyle().Render(settingsTabSeparator)))
+	lines = append(lines, "")
+
+	fields := m.settingsFields()
+	rows := m.settingsVisibleRows()
+	end := min(m.settingsOffset+rows, len(fields))
+
+	if m.settingsOffset > 0 {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ▲ %d more", m.settingsOffset)))
+	} else {
+		lines = append(lines, "")
+	}
+
+	for i := m.settingsOffset; i < end; i++ {
+		field := fields[i]
+		selected := i == m.settingsIndex
 
 		cursor := "  "
 		lStyle := labelStyle
 		vStyle := valueStyle
-
-		if i == m.settingsIndex {
+		if selected {
 			cursor = lipgloss.NewStyle().Foreground(m.colors.secondary).Render("▸ ")
 			lStyle = selectedLabelStyle
 			vStyle = lipgloss.NewStyle().Foreground(m.colors.info)
 		}
 
-		line := cursor + lStyle.Render(fmt.Sprintf("%-18s", label)) + " " + vStyle.Render(value)
-		lines = append(lines, line)
-		lines = append(lines, "    "+descStyle.Render(field.description))
+		display := m.settingsDisplay(field)
+		if r := []rune(display); len(r) > m.settingsValueWidth() {
+			display = string(r[:m.settingsValueWidth()-3]) + "..."
+		}
+		value := vStyle.Render(display)
+		if selected && m.settingsEditing && field.kind != "theme" {
+			value = m.settingsInput.View()
+		}
+		if m.settingsEnvVar(field) != "" {
+			value += m.dimStyle().Render("  (env)")
+		}
+
+		lines = append(lines, cursor+lStyle.Render(fmt.Sprintf("%-18s", field.label))+" "+value)
 
-		if i == m.settingsIndex && m.settingsEditing && field.kind == "theme" {
+		if selected && m.settingsEditing && field.kind == "theme" {
 			lines = append(lines, m.renderThemeDropdown())
 		}
+	}
 
+	if end < len(fields) {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ▼ %d more", len(fields)-end)))
+	} else {
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, m.dimStyle().Render("  Config file: ~/.config/openkanban/config.json"))
+	field := fields[m.settingsIndex]
+	lines = append(lines, "  "+descStyle.Render(field.description))
+	if name := m.settingsEnvVar(field); name != "" {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.warning).Render("Set by "+name+"; changes are saved but apply after it is unset"))
+	}
+	if m.settingsError != "" {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+m.settingsError))
+	}
 	lines = append(lines, "")
 
-	field := settingsFields[m.settingsIndex]
 	var actionHint string
 	switch field.kind {
 	case "toggle":
 		actionHint = "Toggle"
+	case "choice":
+		actionHint = "Next"
 	case "project", "theme":
 		actionHint = "Select"
 	default:
 		actionHint = "Edit"
 	}
 
-	lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.info).Render("[Enter]")+m.dimStyle().Render(" "+actionHint+"  ")+
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
+	lines = append(lines, "  "+keyStyle.Render("[Enter]")+m.dimStyle().Render(" "+actionHint+"  ")+
+		keyStyle.Render("[r]")+m.dimStyle().Render(" Reset  ")+
+		keyStyle.Render("[Tab]")+m.dimStyle().Render(" Page  ")+
 		lipgloss.NewStyle().Foreground(m.colors.muted).Render("[Esc]")+m.dimStyle().Render(" Close"))
 
 	content := strings.Join(lines, "\n")
@@ -1346,6 +1704,7 @@ func (m *Model) renderSettingsView() string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(m.colors.secondary).
 		Padding(1, 2).
+		Width(m.settingsWidth()).
 		Render(content)
 }
 
@@ -1410,9 +1769,13 @@ func (m *Model) renderAgentView() string {
 	if ticket != nil {
 		blockedBy := m.globalStore.GetBlockedBy(ticket.ID)
 		blocks := m.globalStore.GetBlocks(ticket.ID)
-		if len(blockedBy) > 0 || len(blocks) > 0 {
+		source, _ := m.globalStore.Get(ticket.Source)
+		if len(blockedBy) > 0 || len(blocks) > 0 || source != nil {
 			depStyle := lipgloss.NewStyle().Foreground(m.colors.muted)
 			var depParts []string
+			if source != nil {
+				depParts = append(depParts, "↰ "+source.Title)
+			}
 			if len(blockedBy) > 0 {
 				var names []string
 				for _, t := range blockedBy {
@@ -1458,7 +1821,7 @@ func (m *Model) renderAgentView() string {
 
 	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info)
 	hints := scrollIndicator + paneIndicator + "  " +
-		keyStyle.Render("Ctrl+g") + m.dimStyle().Render(" Board")
+		keyStyle.Render(m.keyHint(keymap.DetachAgent)) + m.dimStyle().Render(" Board")
 
 	spacing := m.width - lipgloss.Width(header) - lipgloss.Width(hints)
 	spacing = max(spacing, 0)
@@ -1473,7 +1836,7 @@ func (m *Model) renderAgentView() string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString(pane.View())
+	b.WriteString(m.paneView(pane))
 
 	return b.String()
 }
@@ -1493,12 +1856,46 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm", hours, mins)
 }
 
+func (m *Model) renderLogo() string {
+	return lipgloss.NewStyle().
+		Foreground(m.colors.primary).
+		Bold(true).
+		Render("◈ OpenKanban")
+}
+
+// renderHeaderFilter shows the search being typed or applied. A project
+// picked with a tab is shown by the tab instead.
+func (m *Model) renderHeaderFilter() string {
+	switch {
+	case m.mode == ModeFilter:
+		return m.renderFilterInput()
+	case m.filterQuery != "":
+		return m.renderActiveFilter()
+	case len(m.filterProjectIDs) > 0:
+		if tabs := m.projectTabs(); len(tabs) > 0 && m.activeTab(tabs) >= 0 {
+			return m.renderFilterHint()
+		}
+		return m.renderActiveFilter()
+	}
+	return m.renderFilterHint()
+}
+
+// headerTabsX is the column the header's project tabs start at
+func (m *Model) headerTabsX() int {
+	return lipgloss.Width(m.renderLogo()) + 2 + lipgloss.Width(m.renderHeaderFilter()) + 2
+}
+
 func (m *Model) renderFilterInput() string {
 	inputStyle := lipgloss.NewStyle().
 		Foreground(m.colors.base).
 		Background(m.colors.info).
 		Padding(0, 1)
-	return inputStyle.Render("/ " + m.filterInput.View())
+	input := inputStyle.Render("/ " + m.filterInput.View())
+	if _, err := m.boardQuery(); m.filterQuery != "" && err != nil {
+		// Shown while typing, since the text is searched as is meanwhile
+		input += " " + lipgloss.NewStyle().Foreground(m.colors.warning).Render("text search: "+truncate(err.Error(), 40))
+	}
+	return input
 }
 
 func (m *Model) renderActiveFilter() string {
@@ -1534,7 +1931,7 @@ func (m *Model) renderActiveFilter() string {
 func (m *Model) renderFilterHint() string {
 	return lipgloss.NewStyle().
 		Foreground(m.colors.muted).
-		Render("/ search (@project to filter)")
+		Render("/ search (status:, label:, @project ...)")
 }
 
 func (m *Model) countVisibleTickets() int {
@@ -1659,7 +2056,7 @@ func (m *Model) renderSidebar() string {
 
 	projects := m.globalStore.Projects()
 	statusHeight := 1
-	availableHeight := m.height - m.headerHeight() - statusHeight
+	availableHeight := m.boardHeight() - m.headerHeight() - statusHeight
 
 	titleStyle := lipgloss.NewStyle().
 		Foreground(m.colors.primary).
@@ -1772,10 +2169,16 @@ func (m *Model) renderSidebar() string {
 }
 
 func (m *Model) boardWidth() int {
+	width := m.width
+	if m.splitView && !m.splitBottom() {
+		_, _, paneWidth, _ := m.splitPaneRect()
+		width -= paneWidth
+	}
+	width -= m.previewWidth()
 	if m.sidebarVisible {
-		return m.width - m.sidebarWidth - 1
+		return width - m.sidebarWidth - 1
 	}
-	return m.width
+	return width
 }
 
 type uiColors struct {
@@ -1872,11 +2275,16 @@ func (m *Model) dimStyle() lipgloss.Style {
 }
 
 func (m *Model) columnColor(status board.TicketStatus) lipgloss.Color {
+	if color, ok := m.colorRef(m.config.UI.ColumnColors[string(status)]); ok {
+		return color
+	}
 	switch status {
 	case board.StatusBacklog:
 		return m.colors.primary
 	case board.StatusInProgress:
 		return m.colors.warning
+	case board.StatusReview:
+		return m.colors.info
 	case board.StatusDone:
 		return m.colors.success
 	default:
@@ -1928,7 +2336,7 @@ func (m *Model) renderThemeDropdown() string {
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, m.dimStyle().Render("      ↑↓ navigate  Enter select  Esc cancel"))
+	lines = append(lines, m.dimStyle().Render("      ↑↓ preview  Enter save  Esc revert"))
 
 	return strings.Join(lines, "\n")
 }
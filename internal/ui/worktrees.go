@@ -0,0 +1,411 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// diskUsageInterval is how often worktree sizes are measured in the
+// background while cleanup.disk_limit_gb is set
+const diskUsageInterval = 10 * time.Minute
+
+// worktreeItem is a worktree found by git worktree list, with the ticket
+// that owns it, if any
+type worktreeItem struct {
+	git.TicketWorktree
+	projectID   string
+	projectName string
+	size        int64 // bytes; -1 until measured
+}
+
+// worktreesView is the maintenance screen listing every ticket and orphaned
+// worktree with its size
+type worktreesView struct {
+	items   []worktreeItem
+	index   int
+	offset  int
+	loading bool
+	sizing  bool
+	err     string
+}
+
+type worktreesLoadedMsg struct {
+	items []worktreeItem
+	sized bool
+	err   error
+}
+
+type diskUsageTickMsg time.Time
+
+func tickDiskUsage(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return diskUsageTickMsg(t)
+	})
+}
+
+func (m *Model) openWorktrees() (tea.Model, tea.Cmd) {
+	m.worktrees = &worktreesView{loading: true}
+	m.mode = ModeWorktrees
+	return m, m.loadWorktrees(false)
+}
+
+// handleDiskUsageTick measures the worktrees when a disk limit is set and
+// schedules the next tick
+func (m *Model) handleDiskUsageTick() (tea.Model, tea.Cmd) {
+	next := tickDiskUsage(diskUsageInterval)
+	if m.config.Cleanup.DiskLimitGB <= 0 || m.diskUsageBusy {
+		return m, next
+	}
+	m.diskUsageBusy = true
+	return m, tea.Batch(m.loadWorktrees(true), next)
+}
+
+// loadWorktrees lists every project's worktrees in the background, measuring
+// their sizes when sized is set. Tickets are copied so the board can keep
+// changing them meanwhile.
+func (m *Model) loadWorktrees(sized bool) tea.Cmd {
+	var tickets []*board.Ticket
+	for _, t := range m.globalStore.All() {
+		ticket := *t
+		tickets = append(tickets, &ticket)
+	}
+	type target struct {
+		id, name string
+		mgr      *git.WorktreeManager
+	}
+	var targets []target
+	for _, p := range m.globalStore.Projects() {
+		if mgr := m.worktreeMgrs[p.ID]; mgr != nil {
+			targets = append(targets, target{p.ID, p.Name, mgr})
+		}
+	}
+
+	return func() tea.Msg {
+		var items []worktreeItem
+		var errs []string
+		for _, t := range targets {
+			worktrees, err := t.mgr.TicketWorktrees(tickets)
+			if err != nil {
+				errs = append(errs, t.name+": "+err.Error())
+				continue
+			}
+			for _, wt := range worktrees {
+				item := worktreeItem{TicketWorktree: wt, projectID: t.id, projectName: t.name, size: -1}
+				if sized {
+					if n, err := git.DiskUsage(wt.Path); err == nil {
+						item.size = n
+					}
+				}
+				items = append(items, item)
+			}
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].projectName != items[j].projectName {
+				return items[i].projectName < items[j].projectName
+			}
+			return items[i].Path < items[j].Path
+		})
+
+		msg := worktreesLoadedMsg{items: items, sized: sized}
+		if len(errs) > 0 {
+			msg.err = fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+		return msg
+	}
+}
+
+func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if msg.sized {
+		m.diskUsageBusy = false
+		m.worktreeSizes = make(map[string]int64, len(msg.items))
+		for _, item := range msg.items {
+			if item.size >= 0 {
+				m.worktreeSizes[item.Path] = item.size
+			}
+		}
+		m.checkDiskLimit(msg.items)
+	}
+
+	v := m.worktrees
+	if v == nil {
+		return m, nil
+	}
+	for i := range msg.items {
+		if size, ok := m.worktreeSizes[msg.items[i].Path]; ok {
+			msg.items[i].size = size
+		}
+	}
+	v.loading = false
+	v.items = msg.items
+	v.selectItem(v.index, m.worktreesListHeight())
+	v.err = ""
+	if msg.err != nil {
+		v.err = msg.err.Error()
+	}
+
+	// List first, then measure, since sizing large worktrees takes a while
+	if !msg.sized && !v.sizing {
+		v.sizing = true
+		cmd = m.loadWorktrees(true)
+	} else if msg.sized {
+		v.sizing = false
+	}
+	return m, cmd
+}
+
+// diskUsage sums the measured worktree sizes and the share that prune
+// candidates account for
+func diskUsage(items []worktreeItem) (total, prunable int64, candidates int) {
+	for _, item := range items {
+		if item.size < 0 {
+			continue
+		}
+		total += item.size
+		if item.Prunable() {
+			prunable += item.size
+			candidates++
+		}
+	}
+	return total, prunable, candidates
+}
+
+// diskLimit returns cleanup.disk_limit_gb in bytes, or 0 without a limit
+func (m *Model) diskLimit() int64 {
+	return int64(m.config.Cleanup.DiskLimitGB) << 30
+}
+
+// checkDiskLimit warns once each time worktree usage goes over the limit
+func (m *Model) checkDiskLimit(items []worktreeItem) {
+	limit := m.diskLimit()
+	total, prunable, candidates := diskUsage(items)
+	if limit <= 0 || total <= limit {
+		m.diskLimitWarned = false
+		return
+	}
+	if m.diskLimitWarned {
+		return
+	}
+	m.diskLimitWarned = true
+	msg := fmt.Sprintf("Worktrees use %s, over the %s limit", git.FormatBytes(total), git.FormatBytes(limit))
+	if candidates > 0 {
+		msg += fmt.Sprintf("; press W to prune %d worktree(s) freeing %s", candidates, git.FormatBytes(prunable))
+	}
+	m.notify(msg)
+}
+
+func (m *Model) handleWorktreesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.worktrees
+	height := m.worktreesListHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = ModeNormal
+		m.worktrees = nil
+	case "j", "down":
+		v.selectItem(v.index+1, height)
+	case "k", "up":
+		v.selectItem(v.index-1, height)
+	case "r":
+		v.loading = true
+		return m, m.loadWorktrees(false)
+	case "d", "D":
+		if v.loading || v.index >= len(v.items) {
+			return m, nil
+		}
+		return m, m.confirmRemoveWorktree(v.items[v.index], msg.String() == "D")
+	}
+	return m, nil
+}
+
+func (v *worktreesView) selectItem(i, height int) {
+	v.index = max(min(i, len(v.items)-1), 0)
+	if v.index < v.offset {
+		v.offset = v.index
+	} else if v.index >= v.offset+height {
+		v.offset = v.index - height + 1
+	}
+}
+
+// worktreesListHeight is the number of worktrees that fit in the overlay;
+// each takes two lines
+func (m *Model) worktreesListHeight() int {
+	return max((m.height-18)/2, 3)
+}
+
+// confirmRemoveWorktree removes an orphaned worktree, or the worktree of a
+// done or archived ticket. Active tickets keep theirs.
+func (m *Model) confirmRemoveWorktree(wt worktreeItem, deleteBranch bool) tea.Cmd {
+	if m.readOnlyBlocked("remove worktrees") {
+		return nil
+	}
+	mgr := m.worktreeMgrs[wt.projectID]
+	if mgr == nil {
+		m.worktrees.err = "worktree manager not found"
+		return nil
+	}
+	var ticket *board.Ticket
+	if wt.Ticket != nil {
+		if !wt.Prunable() {
+			m.notify("Ticket is still " + string(wt.Ticket.Status) + "; finish or archive it first")
+			return nil
+		}
+		if ticket, _ = m.globalStore.Get(wt.Ticket.ID); ticket == nil {
+			return nil
+		}
+		if _, running := m.panes[ticket.ID]; running {
+			m.notify("Stop the ticket's agent before removing its worktree")
+			return nil
+		}
+	}
+
+	what := "worktree " + wt.Path
+	if deleteBranch && wt.Branch != "" {
+		what += " and branch " + wt.Branch
+	}
+	msg := "Remove " + what + "?"
+	if wt.size >= 0 {
+		msg = fmt.Sprintf("Remove %s (%s)?", what, git.FormatBytes(wt.size))
+	}
+	dirty, _ := mgr.HasUncommittedChanges(wt.Path)
+	if dirty {
+		msg += " It has uncommitted changes."
+	}
+
+	return m.askConfirm(m.config.Behavior.Confirm.PruneWorktree || dirty, msg, func() tea.Cmd {
+		if err := mgr.RemoveWorktree(wt.Path); err != nil {
+			m.worktrees.err = err.Error()
+			return nil
+		}
+		if err := mgr.PruneMetadata(); err != nil {
+			slog.Warn("failed to prune worktree metadata", "err", err)
+		}
+		if deleteBranch && wt.Branch != "" {
+			if err := mgr.DeleteBranch(wt.Branch); err != nil {
+				m.notify("Failed to delete branch: " + err.Error())
+			}
+		}
+		if ticket != nil {
+			ticket.WorktreePath = ""
+			m.saveTicket(ticket)
+		}
+		delete(m.worktreeSizes, wt.Path)
+		m.notify("Removed " + what)
+		m.worktrees.loading = true
+		return m.loadWorktrees(false)
+	})
+}
+
+func (m *Model) renderWorktreesView() string {
+	v := m.worktrees
+	titleStyle := lipgloss.NewStyle().Foreground(m.colors.primary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(m.colors.info).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(m.colors.warning)
+	width := min(100, m.width-4)
+	inner := width - 6
+
+	lines := []string{titleStyle.Render("Worktrees"), ""}
+
+	total, prunable, candidates := diskUsage(v.items)
+	usage := "Total " + git.FormatBytes(total)
+	if limit := m.diskLimit(); limit > 0 {
+		usage += " of " + git.FormatBytes(limit)
+	}
+	switch {
+	case v.sizing:
+		usage = m.spinner.View() + " Measuring worktrees..."
+	case candidates > 0:
+		usage += fmt.Sprintf(" · ✂ %d prune candidate(s) would free %s", candidates, git.FormatBytes(prunable))
+	}
+	if limit := m.diskLimit(); limit > 0 && total > limit && !v.sizing {
+		lines = append(lines, warnStyle.Render("⚠ "+usage))
+	} else {
+		lines = append(lines, m.dimStyle().Render(usage))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case v.loading && len(v.items) == 0:
+		lines = append(lines, "  "+m.spinner.View()+m.dimStyle().Render(" Scanning worktrees..."))
+	case len(v.items) == 0:
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(m.colors.success).Render("✓ No worktrees"))
+	}
+
+	height := m.worktreesListHeight()
+	end := min(v.offset+height, len(v.items))
+	project := ""
+	if v.offset > 0 {
+		project = v.items[v.offset-1].projectName
+	}
+	for i := v.offset; i < end; i++ {
+		wt := v.items[i]
+		if wt.projectName != project {
+			project = wt.projectName
+			lines = append(lines, lipgloss.NewStyle().Foreground(m.colors.secondary).Bold(true).Render(project))
+		}
+		style := lipgloss.NewStyle().Foreground(m.colors.text)
+		cursor := "  "
+		if i == v.index {
+			style = style.Foreground(m.colors.primary).Bold(true)
+			cursor = "▸ "
+		}
+		marker := "  "
+		if wt.Prunable() {
+			marker = warnStyle.Render("✂ ")
+		}
+
+		size := "…"
+		if wt.size >= 0 {
+			size = git.FormatBytes(wt.size)
+		} else if !v.sizing {
+			size = "?"
+		}
+
+		branch := wt.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		var owner string
+		switch {
+		case wt.Ticket != nil:
+			owner = fmt.Sprintf("%s [%s]", wt.Ticket.Title, wt.Ticket.Status)
+		case wt.Managed:
+			owner = "orphaned"
+		default:
+			owner = "orphaned, outside worktree directory"
+		}
+		row := cursor + marker + style.Render(fmt.Sprintf("%-10s %s", size, branch))
+		row += m.dimStyle().Render("  " + truncate(owner, max(inner-lipgloss.Width(row)-2, 10)))
+		lines = append(lines, row, "      "+m.dimStyle().Render(truncate(wt.Path, inner-6)))
+	}
+	if len(v.items) > end {
+		lines = append(lines, m.dimStyle().Render(fmt.Sprintf("  ... and %d more", len(v.items)-end)))
+	}
+
+	if v.err != "" {
+		lines = append(lines, "", "  "+lipgloss.NewStyle().Foreground(m.colors.err).Render("✗ "+v.err))
+	}
+
+	lines = append(lines, "",
+		m.dimStyle().Render("✂ marks worktrees of done, archived or deleted tickets."),
+		"",
+		keyStyle.Render("[d]")+m.dimStyle().Render(" Remove  ")+
+			keyStyle.Render("[D]")+m.dimStyle().Render(" Remove with branch  ")+
+			keyStyle.Render("[r]")+m.dimStyle().Render(" Refresh  ")+
+			keyStyle.Render("[esc]")+m.dimStyle().Render(" Close"))
+
+	return lipgloss.NewStyle().
+		Border(columnBorder).
+		BorderForeground(m.colors.primary).
+		Padding(1, 2).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,218 @@
+// Package vault renders tickets as interlinked Markdown notes for Obsidian
+// and other [[wikilink]] note apps: one note per ticket and an index note
+// per project.
+package vault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+// KeepMarker separates generated content from notes written by hand. Text
+// below it survives a re-export. %% %% is an Obsidian comment, hidden when
+// reading.
+const KeepMarker = "%% Notes below this line are kept on re-export %%"
+
+// TicketsDir is the subfolder ticket notes are written to
+const TicketsDir = "tickets"
+
+// Ticket is a ticket with what is needed to render its note
+type Ticket struct {
+	*board.Ticket
+	Project string
+	Commits []git.Commit // commits on the ticket branch, newest first
+}
+
+// forbidden are characters that file names or wikilinks cannot contain
+const forbidden = `\/:*?"<>|#^[]`
+
+// noteName turns a title into a note (file) name
+func noteName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(forbidden, r) || r < ' ' {
+			return ' '
+		}
+		return r
+	}, title)
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		name = "Untitled"
+	}
+	return name
+}
+
+// NoteNames gives every ticket a unique note name, its title where possible;
+// titles used more than once get the start of the ticket ID added
+func NoteNames(tickets []Ticket) map[board.TicketID]string {
+	count := make(map[string]int)
+	for _, t := range tickets {
+		count[strings.ToLower(noteName(t.Title))]++
+	}
+	names := make(map[board.TicketID]string, len(tickets))
+	for _, t := range tickets {
+		name := noteName(t.Title)
+		if count[strings.ToLower(name)] > 1 {
+			id := string(t.ID)
+			name += " (" + id[:min(8, len(id))] + ")"
+		}
+		names[t.ID] = name
+	}
+	return names
+}
+
+// TicketNote renders a ticket: front matter for Dataview-style queries, the
+// description, links to its project, blockers, branch and pull request, its
+// agent runs, and the commits agents made
+func TicketNote(t Ticket, names map[board.TicketID]string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "id: %s\n", t.ID)
+	fmt.Fprintf(&b, "project: %s\n", yamlString(t.Project))
+	fmt.Fprintf(&b, "status: %s\n", t.Status)
+	if t.BranchName != "" {
+		fmt.Fprintf(&b, "branch: %s\n", yamlString(t.BranchName))
+	}
+	if t.PRURL != "" {
+		fmt.Fprintf(&b, "pr: %s\n", yamlString(t.PRURL))
+	}
+	if t.AgentType != "" {
+		fmt.Fprintf(&b, "agent: %s\n", yamlString(t.AgentType))
+	}
+	fmt.Fprintf(&b, "priority: %d\n", t.Priority)
+	fmt.Fprintf(&b, "created: %s\n", t.CreatedAt.Format(time.DateOnly))
+	if t.CompletedAt != nil {
+		fmt.Fprintf(&b, "completed: %s\n", t.CompletedAt.Format(time.DateOnly))
+	}
+	tags := []string{"openkanban"}
+	for _, l := range t.Labels {
+		tags = append(tags, strings.ReplaceAll(l, " ", "-"))
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", t.Title)
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		b.WriteString(desc + "\n\n")
+	}
+
+	b.WriteString("## Links\n\n")
+	fmt.Fprintf(&b, "- Project: [[%s]]\n", noteName(t.Project))
+	if t.BranchName != "" {
+		fmt.Fprintf(&b, "- Branch: `%s`\n", t.BranchName)
+	}
+	if t.PRURL != "" {
+		fmt.Fprintf(&b, "- Pull request: %s\n", t.PRURL)
+	}
+	for _, id := range t.BlockedBy {
+		if name, ok := names[id]; ok {
+			fmt.Fprintf(&b, "- Blocked by: [[%s]]\n", name)
+		}
+	}
+
+	if len(t.AgentRuns) > 0 {
+		b.WriteString("\n## Agent runs\n\n")
+		b.WriteString("| Agent | Started | Duration | Outcome |\n|---|---|---|---|\n")
+		for _, run := range t.AgentRuns {
+			duration := "running"
+			if run.EndedAt != nil {
+				duration = run.Duration().Round(time.Second).String()
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				run.Agent, run.StartedAt.Format("2006-01-02 15:04"), duration, run.Outcome)
+		}
+	}
+
+	if len(t.Commits) > 0 {
+		b.WriteString("\n## Commits\n\n")
+		for i := len(t.Commits) - 1; i >= 0; i-- {
+			c := t.Commits[i]
+			fmt.Fprintf(&b, "- `%s` %s\n", c.ShortHash(), c.Subject)
+		}
+	}
+	return b.String()
+}
+
+// ProjectNote renders a project's index, linking its tickets by column
+func ProjectNote(project string, tickets []Ticket, names map[board.TicketID]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntags: [openkanban, project]\n---\n\n# %s\n", project)
+
+	byStatus := make(map[board.TicketStatus][]Ticket)
+	for _, t := range tickets {
+		byStatus[t.Status] = append(byStatus[t.Status], t)
+	}
+	for _, col := range []struct {
+		status board.TicketStatus
+		title  string
+	}{
+		{board.StatusInProgress, "In Progress"},
+		{board.StatusReview, "Review"},
+		{board.StatusBacklog, "Backlog"},
+		{board.StatusDone, "Done"},
+		{board.StatusArchived, "Archived"},
+	} {
+		list := byStatus[col.status]
+		if len(list) == 0 {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		fmt.Fprintf(&b, "\n## %s\n\n", col.title)
+		for _, t := range list {
+			fmt.Fprintf(&b, "- [[%s]]\n", names[t.ID])
+		}
+	}
+	return b.String()
+}
+
+func yamlString(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+// Write exports the tickets into dir: a note per project at the top and one
+// per ticket in TicketsDir. Existing notes are replaced, keeping anything
+// written below KeepMarker. It returns the number of notes written.
+func Write(dir string, tickets []Ticket) (int, error) {
+	if err := os.MkdirAll(filepath.Join(dir, TicketsDir), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create vault folder: %w", err)
+	}
+
+	names := NoteNames(tickets)
+	byProject := make(map[string][]Ticket)
+	written := 0
+	for _, t := range tickets {
+		byProject[t.Project] = append(byProject[t.Project], t)
+		path := filepath.Join(dir, TicketsDir, names[t.ID]+".md")
+		if err := writeNote(path, TicketNote(t, names)); err != nil {
+			return written, err
+		}
+		written++
+	}
+	for project, list := range byProject {
+		path := filepath.Join(dir, noteName(project)+".md")
+		if err := writeNote(path, ProjectNote(project, list, names)); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// writeNote replaces the generated part of a note
+func writeNote(path, content string) error {
+	var kept string
+	if old, err := os.ReadFile(path); err == nil {
+		_, kept, _ = strings.Cut(string(old), KeepMarker+"\n")
+	}
+	content += "\n" + KeepMarker + "\n" + kept
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
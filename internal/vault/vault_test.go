@@ -0,0 +1,104 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techdufus/openkanban/internal/board"
+	"github.com/techdufus/openkanban/internal/git"
+)
+
+func testTickets() []Ticket {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ended := created.Add(90 * time.Minute)
+	blocker := &board.Ticket{ID: "11111111-aaaa", Title: "Add API: v2", Status: board.StatusDone, CreatedAt: created, CompletedAt: &ended}
+	login := &board.Ticket{
+		ID: "22222222-bbbb", Title: "Fix login", Description: "Sessions expire too early.",
+		Status: board.StatusInProgress, BranchName: "task/fix-login", PRURL: "https://github.com/o/r/pull/7",
+		AgentType: "claude", Priority: 2, CreatedAt: created.Add(time.Hour), Labels: []string{"bug"},
+		BlockedBy: []board.TicketID{blocker.ID},
+		AgentRuns: []board.AgentRun{{Agent: "claude", StartedAt: created, EndedAt: &ended, Outcome: board.OutcomeCompleted}},
+	}
+	dup := &board.Ticket{ID: "33333333-cccc", Title: "Fix login", Status: board.StatusBacklog, CreatedAt: created}
+	return []Ticket{
+		{Ticket: blocker, Project: "app"},
+		{Ticket: login, Project: "app", Commits: []git.Commit{
+			{Hash: "bbbbbbbbbb", Subject: "Check expiry"},
+			{Hash: "aaaaaaaaaa", Subject: "Add test"},
+		}},
+		{Ticket: dup, Project: "web"},
+	}
+}
+
+func TestNoteNames(t *testing.T) {
+	names := NoteNames(testTickets())
+	want := map[board.TicketID]string{
+		"11111111-aaaa": "Add API v2",
+		"22222222-bbbb": "Fix login (22222222)",
+		"33333333-cccc": "Fix login (33333333)",
+	}
+	for id, name := range want {
+		if names[id] != name {
+			t.Errorf("name of %s = %q; want %q", id, names[id], name)
+		}
+	}
+}
+
+func TestTicketNote(t *testing.T) {
+	tickets := testTickets()
+	note := TicketNote(tickets[1], NoteNames(tickets))
+	for _, want := range []string{
+		"id: 22222222-bbbb\n",
+		`project: "app"` + "\n",
+		"status: in_progress\n",
+		"tags: [openkanban, bug]\n",
+		"# Fix login\n\nSessions expire too early.\n",
+		"- Project: [[app]]\n",
+		"- Branch: `task/fix-login`\n",
+		"- Pull request: https://github.com/o/r/pull/7\n",
+		"- Blocked by: [[Add API v2]]\n",
+		"| claude | 2026-03-01 09:00 | 1h30m0s | completed |\n",
+		"- `aaaaaaa` Add test\n- `bbbbbbb` Check expiry\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note is missing %q:\n%s", want, note)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	tickets := testTickets()
+	n, err := Write(dir, tickets)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if n != 5 {
+		t.Errorf("Write() wrote %d notes; want 5", n)
+	}
+
+	project, err := os.ReadFile(filepath.Join(dir, "app.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(project), "## In Progress\n\n- [[Fix login (22222222)]]\n\n## Done\n\n- [[Add API v2]]\n") {
+		t.Errorf("project note:\n%s", project)
+	}
+
+	path := filepath.Join(dir, TicketsDir, "Add API v2.md")
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append(data, "My own notes\n"...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tickets[0].Description = "Now described"
+	if _, err := Write(dir, tickets); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "Now described") || !strings.HasSuffix(string(data), KeepMarker+"\nMy own notes\n") {
+		t.Errorf("re-exported note:\n%s", data)
+	}
+}